@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/haridev22/ct-assignement/pkg/api"
+	"github.com/haridev22/ct-assignement/pkg/approvals"
+)
+
+// runApprovals scans the Approval events emitted for address and writes the
+// approvals report, flagging allowances that are still active
+func runApprovals(client *api.EtherscanClient, address string, startBlock, endBlock int64, outputDir string) {
+	fmt.Printf("Fetching approvals for address: %s\n", address)
+	fmt.Printf("Block range: %d to %d\n", startBlock, endBlock)
+
+	logs, err := client.GetAllApprovalLogs(address, startBlock, endBlock)
+	if err != nil {
+		log.Fatalf("Error fetching approval events: %v", err)
+	}
+
+	result, err := approvals.FromLogs(logs)
+	if err != nil {
+		log.Fatalf("Error decoding approval events: %v", err)
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		log.Fatalf("Error creating output directory: %v", err)
+	}
+
+	outputFile := filepath.Join(outputDir, fmt.Sprintf("%s_approvals.csv", address))
+	if err := approvals.WriteCSV(result, outputFile); err != nil {
+		log.Fatalf("Error exporting approvals to CSV: %v", err)
+	}
+
+	active := 0
+	for _, a := range result {
+		if a.Active {
+			active++
+		}
+	}
+	fmt.Printf("Successfully exported %d approvals (%d still active) to %s\n", len(result), active, outputFile)
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/haridev22/ct-assignement/pkg/auth"
+	"github.com/haridev22/ct-assignement/pkg/server"
+)
+
+// authFlags are the flags protecting the HTTP endpoints of serve, tail and
+// watch
+type authFlags struct {
+	keysFile    *string
+	jwtSecret   *string
+	jwtIssuer   *string
+	jwtAudience *string
+}
+
+func addAuthFlags(fs *flag.FlagSet) *authFlags {
+	return &authFlags{
+		keysFile:    fs.String("api-keys", "", "JSON file of API keys and their scopes; requests must then present a key or token"),
+		jwtSecret:   fs.String("jwt-secret", "", "Also accept HS256 JWTs signed with this secret, taking scopes from their scope claim"),
+		jwtIssuer:   fs.String("jwt-issuer", "", "Only accept JWTs with this iss claim"),
+		jwtAudience: fs.String("jwt-audience", "", "Only accept JWTs with this aud claim"),
+	}
+}
+
+// authenticator builds the authenticator described by the flags, or
+// returns nil when the endpoints are open, exiting on invalid values
+func (f *authFlags) authenticator() *auth.Authenticator {
+	if *f.keysFile == "" && *f.jwtSecret == "" {
+		if *f.jwtIssuer != "" || *f.jwtAudience != "" {
+			log.Fatal("Error: -jwt-issuer and -jwt-audience require -jwt-secret.")
+		}
+		return nil
+	}
+	a := &auth.Authenticator{Issuer: *f.jwtIssuer, Audience: *f.jwtAudience}
+	if *f.keysFile != "" {
+		keys, err := auth.LoadKeys(*f.keysFile)
+		if err != nil {
+			log.Fatalf("Error: invalid -api-keys: %v", err)
+		}
+		a.Keys = keys
+	}
+	if *f.jwtSecret != "" {
+		a.JWTSecret = []byte(resolveSecret("-jwt-secret", *f.jwtSecret))
+	}
+	return a
+}
+
+// requireScope protects next with a, or returns next unchanged when a is
+// nil
+func requireScope(a *auth.Authenticator, scope func(r *http.Request) string, next http.Handler) http.Handler {
+	if a == nil {
+		return next
+	}
+	return a.Require(scope, next)
+}
+
+// scope returns a scope function that always requires name
+func scope(name string) func(*http.Request) string {
+	return func(*http.Request) string { return name }
+}
+
+// serveScope is the scope required by the endpoints of the server: changing
+// watchlists needs the watchlists scope, everything else reading
+func serveScope(r *http.Request) string {
+	if strings.HasPrefix(r.URL.Path, "/watchlists") && r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return auth.ScopeWatchlists
+	}
+	return auth.ScopeRead
+}
+
+// identityTenant returns the tenant of the authenticated caller, so keys
+// and tokens only see the watchlists of their tenant
+func identityTenant(r *http.Request) string {
+	if id, ok := auth.FromContext(r.Context()); ok && id.Tenant != "" {
+		return id.Tenant
+	}
+	return server.DefaultTenant
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/haridev22/ct-assignement/pkg/bigquery"
+	"github.com/haridev22/ct-assignement/pkg/chains"
+	"github.com/haridev22/ct-assignement/pkg/models"
+)
+
+// bigqueryFlags select a BigQuery table that fetch, sync and export load
+// their rows into
+type bigqueryFlags struct {
+	project     *string
+	dataset     *string
+	table       *string
+	location    *string
+	credentials *string
+	batchRows   *int
+}
+
+func addBigQueryFlags(fs *flag.FlagSet) *bigqueryFlags {
+	return &bigqueryFlags{
+		project:     fs.String("bigquery-project", "", "Google Cloud project of -bigquery-dataset (default: the project of the credentials)"),
+		dataset:     fs.String("bigquery-dataset", "", "BigQuery dataset to load rows into, created when missing (requires -bigquery-table)"),
+		table:       fs.String("bigquery-table", "", "BigQuery table to load rows into, created with the export schema when missing"),
+		location:    fs.String("bigquery-location", "", "Location of a created dataset, such as US or EU (default: US)"),
+		credentials: fs.String("bigquery-credentials", "", "Service account key file (default: GOOGLE_APPLICATION_CREDENTIALS, or an access token in GOOGLE_OAUTH_ACCESS_TOKEN)"),
+		batchRows:   fs.Int("bigquery-batch-rows", bigquery.DefaultBatchRows, "Rows sent in each BigQuery load job"),
+	}
+}
+
+// bigQuerySink is a table rows are loaded into
+type bigQuerySink struct {
+	client *bigquery.Client
+	table  string
+}
+
+// sink builds the sink described by the flags, or returns nil when no
+// table is given, exiting on invalid values
+func (f *bigqueryFlags) sink() *bigQuerySink {
+	if *f.dataset == "" && *f.table == "" {
+		return nil
+	}
+	if *f.dataset == "" || *f.table == "" {
+		log.Fatal("Error: -bigquery-dataset and -bigquery-table must be given together.")
+	}
+	for _, name := range []string{*f.dataset, *f.table} {
+		if err := bigquery.ValidateName(name); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+	}
+	if *f.batchRows < 1 {
+		log.Fatal("Error: -bigquery-batch-rows must be at least 1.")
+	}
+
+	client := &bigquery.Client{Project: *f.project, Dataset: *f.dataset, Location: *f.location, BatchRows: *f.batchRows}
+	credentials := *f.credentials
+	if credentials == "" {
+		credentials = os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")
+	}
+	switch token := os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN"); {
+	case credentials != "":
+		account, err := bigquery.LoadServiceAccount(credentials)
+		if err != nil {
+			log.Fatalf("Error reading BigQuery credentials: %v", err)
+		}
+		client.Token = account
+		if client.Project == "" {
+			client.Project = account.ProjectID
+		}
+	case token != "":
+		client.Token = bigquery.StaticToken(token)
+	default:
+		log.Fatal("Error: BigQuery needs credentials. Use -bigquery-credentials, GOOGLE_APPLICATION_CREDENTIALS or GOOGLE_OAUTH_ACCESS_TOKEN.")
+	}
+	if client.Project == "" {
+		log.Fatal("Error: -bigquery-project is required when the credentials name no project.")
+	}
+	return &bigQuerySink{client: client, table: *f.table}
+}
+
+// loadBigQuery replaces the rows of address between startBlock and
+// endBlock in the BigQuery table of opts, if any. Only the rows of the
+// chain fetched are replaced; rows read from a store are of the chain they
+// name, and rows of several chains are refused.
+func loadBigQuery(opts runOptions, address string, startBlock, endBlock int64, txs []models.Transaction) error {
+	if opts.bigquery == nil {
+		return nil
+	}
+	ctx := opts.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	chain := opts.converter.Chain
+	for i := 0; chain == "" && i < len(txs); i++ {
+		chain = txs[i].Chain
+	}
+	if chain == "" {
+		chain = chains.Ethereum.Name
+	}
+	sink := opts.bigquery
+	if err := sink.client.Replace(ctx, sink.table, address, chain, startBlock, endBlock, txs); err != nil {
+		return fmt.Errorf("failed to load BigQuery table %s.%s: %w", sink.client.Dataset, sink.table, err)
+	}
+	fmt.Printf("Loaded %d transactions into BigQuery table %s.%s.%s\n", len(txs), sink.client.Project, sink.client.Dataset, sink.table)
+	return nil
+}
+
+// loadFetched loads the rows fetched for a block range. Rows of a range
+// that failed in part are not loaded, as they would replace the rows of
+// the types that failed.
+func loadFetched(opts runOptions, address string, startBlock, endBlock int64, txs []models.Transaction, complete bool) error {
+	if opts.bigquery == nil {
+		return nil
+	}
+	if !complete {
+		fmt.Printf("Warning: not loading blocks %d to %d into BigQuery, as some rows failed to fetch\n", startBlock, endBlock)
+		return nil
+	}
+	return loadBigQuery(opts, address, startBlock, endBlock, txs)
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/api"
+	"github.com/haridev22/ct-assignement/pkg/wallets"
+)
+
+// runBulk exports every wallet in turn, carrying on past failures, then
+// prints a summary. It returns the number of wallets that failed or were
+// skipped after an interruption.
+func runBulk(client *api.EtherscanClient, list []wallets.Wallet, startBlock, endBlock, batchSize int64, outputDir string, opts runOptions) int {
+	type failure struct {
+		wallet wallets.Wallet
+		err    error
+	}
+	var failures []failure
+	skipped := 0
+	started := time.Now()
+
+	fmt.Printf("Exporting %d addresses, block range %d to %d\n", len(list), startBlock, endBlock)
+	for i, wallet := range list {
+		if opts.interrupted() {
+			skipped = len(list) - i
+			fmt.Printf("Interrupted, skipping the remaining %d addresses\n", skipped)
+			break
+		}
+		fmt.Printf("\n##### [%d/%d] %s #####\n", i+1, len(list), wallet)
+		if err := exportWallet(client, wallet.Address, startBlock, endBlock, batchSize, outputDir, opts); err != nil {
+			fmt.Printf("Failed to export %s: %v%s\n", wallet, err, errorHint(err))
+			failures = append(failures, failure{wallet, err})
+			opts.summary.AddError(fmt.Errorf("%s: %w", wallet.Address, err))
+		}
+	}
+
+	fmt.Printf("\n===== Summary =====\n")
+	fmt.Printf("Addresses: %d, succeeded: %d, failed: %d, skipped: %d (took %s)\n",
+		len(list), len(list)-len(failures)-skipped, len(failures), skipped, time.Since(started).Round(time.Second))
+	for _, f := range failures {
+		fmt.Printf("  FAILED %s: %v\n", f.wallet, f.err)
+	}
+	return len(failures) + skipped
+}
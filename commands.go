@@ -0,0 +1,175 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/haridev22/ct-assignement/pkg/envflags"
+	"github.com/haridev22/ct-assignement/pkg/version"
+)
+
+// command is a subcommand of the CLI
+type command struct {
+	name    string
+	summary string
+	// define adds the flags of the command to a flag set and returns the
+	// action running the command once they are parsed
+	define func(fs *flag.FlagSet) func()
+}
+
+// build returns the flag set of the command and its action
+func (c command) build() (*flag.FlagSet, func()) {
+	fs := flag.NewFlagSet(c.name, flag.ExitOnError)
+	return fs, c.define(fs)
+}
+
+// flags returns the flag set of the command, for help and completion
+func (c command) flags() *flag.FlagSet {
+	fs, _ := c.build()
+	return fs
+}
+
+// run parses args into the flags of the command and runs it. Arguments
+// starting with the name of a subcommand run the subcommand instead.
+func (c command) run(args []string) {
+	if len(args) > 0 {
+		if sub, ok := c.subcommand(args[0]); ok {
+			sub.run(args[1:])
+			return
+		}
+	}
+	fs, action := c.build()
+	parseFlags(fs, args)
+	action()
+}
+
+// subcommands returns the subcommands of the command, which only report
+// has
+func (c command) subcommands() []command {
+	if c.name == "report" {
+		return reportCommands
+	}
+	return nil
+}
+
+// subcommand looks up a subcommand of the command by name. It is named
+// after both, as in "report summary", which its help and errors show.
+func (c command) subcommand(name string) (command, bool) {
+	for _, sub := range c.subcommands() {
+		if sub.name == name {
+			sub.name = c.name + " " + sub.name
+			return sub, true
+		}
+	}
+	return command{}, false
+}
+
+// commands lists the subcommands in the order they are shown in the usage
+var commands = []command{
+	{"fetch", "Fetch the transactions of an address and export them to CSV", defineFetch},
+	{"sync", "Fetch the transactions of an address into a -store without exporting", defineFetch},
+	{"export", "Export rows from a -store without fetching", defineExport},
+	{"import", "Record the rows of existing exports in a -store without fetching", defineImport},
+	{"report", "Regenerate an export and summary from a -store as of a date, print totals with report summary, holdings at a block with report holdings, top counterparties with report counterparties, realized gains with report gains, period statements with report statement, an HTML page with report html, or chart images with report charts", defineReport},
+	{"holdings", "Read the current balance of every ERC-20 token in the history of an address", defineHoldings},
+	{"preview", "Print the first and last rows of an export or a -store as a table", definePreview},
+	{"diff", "Compare two exports, listing added, removed and changed rows by row ID", defineDiff},
+	{"query", "Print matching rows from a -store", defineQuery},
+	{"tui", "Browse the rows of an export or a -store interactively, filtering and exporting them", defineTUI},
+	{"serve", "Serve the histories in a -store over an HTTP API", defineServe},
+	{"tail", "Print new transactions of an address as they are mined", defineTail},
+	{"watch", "Record new transactions of addresses in a -store and send notifications", defineWatch},
+	{"validate", "Check an export for unknown headers, unparsable rows, order, duplicates and impossible values", defineValidate},
+	{"merge", "Combine exports of an address into one deduplicated, chronological export", defineMerge},
+	{"convert", "Rewrite an export from an older schema version", defineConvert},
+	{"completion", "Print the completion script of a shell: bash, zsh or fish", defineCompletion},
+	{"version", "Print the version", defineVersion},
+}
+
+func lookupCommand(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+// printUsage lists the subcommands
+func printUsage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: eth-tx-exporter <command> [flags]\n\nCommands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintf(w, "  %-10s %s\n", "help", "Show this help, or with a command its flags")
+	fmt.Fprintf(w, "\nRun 'eth-tx-exporter help <command>' for the flags of a command.\n"+
+		"Without a command, flags are passed to fetch.\n")
+}
+
+func defineVersion(fs *flag.FlagSet) func() {
+	return func() {
+		fmt.Println(version.Version)
+	}
+}
+
+// runHelp implements help, which lists the commands or, given a command or
+// a report subcommand, describes it with its flags
+func runHelp(args []string) {
+	if len(args) == 0 {
+		printUsage(os.Stdout)
+		return
+	}
+	cmd, ok := lookupCommand(args[0])
+	if !ok {
+		log.Fatalf("Error: unknown command %q.", args[0])
+	}
+	subcommands := cmd.subcommands()
+	if len(args) > 1 && subcommands != nil {
+		if cmd, ok = cmd.subcommand(args[1]); !ok {
+			log.Fatalf("Error: unknown %s subcommand %q.", args[0], args[1])
+		}
+		subcommands = nil
+	}
+	fmt.Printf("%s\n\n", cmd.summary)
+	if subcommands != nil {
+		fmt.Printf("Subcommands:\n")
+		for _, sub := range subcommands {
+			fmt.Printf("  %-15s %s\n", sub.name, sub.summary)
+		}
+		fmt.Println()
+	}
+	printFlags(os.Stdout, cmd.flags())
+}
+
+// printFlags prints the usage of fs, naming the environment variable that
+// can set each flag
+func printFlags(w io.Writer, fs *flag.FlagSet) {
+	fmt.Fprintf(w, "Usage: eth-tx-exporter %s [flags]\n", fs.Name())
+	if !hasFlags(fs) {
+		return
+	}
+	fmt.Fprintf(w, "\nFlags:\n")
+	fs.VisitAll(func(f *flag.Flag) {
+		kind, usage := flag.UnquoteUsage(f)
+		name := "-" + f.Name
+		if kind != "" {
+			name += " " + kind
+		}
+		fmt.Fprintf(w, "  %s\n    \t%s", name, strings.ReplaceAll(usage, "\n", "\n    \t"))
+		if f.DefValue != "" && f.DefValue != "false" && f.DefValue != "0" && f.DefValue != "0s" {
+			fmt.Fprintf(w, " (default %s)", f.DefValue)
+		}
+		fmt.Fprintf(w, "\n    \t[$%s]\n", envflags.Name(f.Name))
+	})
+}
+
+// hasFlags reports whether fs defines any flag
+func hasFlags(fs *flag.FlagSet) bool {
+	found := false
+	fs.VisitAll(func(*flag.Flag) { found = true })
+	return found
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommandFlags(t *testing.T) {
+	for _, cmd := range commands {
+		fs := cmd.flags()
+		assert.Equal(t, cmd.name, fs.Name())
+		for _, listed := range cmd.subcommands() {
+			sub, ok := cmd.subcommand(listed.name)
+			if assert.True(t, ok) {
+				assert.Equal(t, cmd.name+" "+listed.name, sub.flags().Name())
+			}
+		}
+	}
+
+	fetch, _ := lookupCommand("fetch")
+	assert.NotNil(t, fetch.flags().Lookup("address"))
+	sync, _ := lookupCommand("sync")
+	assert.NotNil(t, sync.flags().Lookup("store"), "commands sharing a definition get their own flag sets")
+
+	report, _ := lookupCommand("report")
+	gains, ok := report.subcommand("gains")
+	if assert.True(t, ok) {
+		assert.Equal(t, "report gains", gains.name)
+		assert.NotNil(t, gains.flags().Lookup("prices"))
+		assert.Nil(t, report.flags().Lookup("prices"))
+	}
+	_, ok = report.subcommand("nosuchsubcommand")
+	assert.False(t, ok)
+}
+
+func TestCommandRun(t *testing.T) {
+	var got string
+	cmd := command{name: "greet", define: func(fs *flag.FlagSet) func() {
+		name := fs.String("name", "", "Name to greet")
+		return func() { got = "hello " + *name + " " + fs.Arg(0) }
+	}}
+	cmd.run([]string{"-name", "ada", "again"})
+	assert.Equal(t, "hello ada again", got, "the action sees the parsed flags")
+}
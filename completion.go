@@ -0,0 +1,382 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/haridev22/ct-assignement/pkg/chains"
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/haridev22/ct-assignement/pkg/report"
+	"github.com/haridev22/ct-assignement/pkg/server"
+	"github.com/haridev22/ct-assignement/pkg/store"
+)
+
+// completeCommand is the hidden command the completion scripts call back
+// to complete a command line
+const completeCommand = "__complete"
+
+// defineCompletion defines the completion subcommand, which prints the
+// completion script of a shell
+func defineCompletion(fs *flag.FlagSet) func() {
+	return func() {
+		if fs.NArg() != 1 {
+			log.Fatal("Error: completion requires a shell: bash, zsh or fish.")
+		}
+		script, ok := completionScripts[fs.Arg(0)]
+		if !ok {
+			log.Fatalf("Error: unknown shell %q (use bash, zsh or fish).", fs.Arg(0))
+		}
+		fmt.Print(script)
+	}
+}
+
+// completionScripts are the completion scripts of each shell. They pass
+// the command line to the hidden __complete command, so the candidates
+// always match the flags of the installed binary, and fall back to file
+// names when it has none.
+var completionScripts = map[string]string{
+	"bash": `# bash completion for eth-tx-exporter
+_eth_tx_exporter() {
+	local line=${COMP_LINE:0:COMP_POINT} candidate
+	local -a words candidates
+	read -ra words <<< "$line"
+	[[ $line == *[[:space:]] ]] && words+=("")
+	local IFS=$'\n'
+	candidates=($("${words[0]}" __complete "${words[@]:1}" 2>/dev/null))
+	COMPREPLY=()
+	for candidate in "${candidates[@]}"; do
+		candidate=${candidate%%$'\t'*}
+		# bash splits -flag=value at the =, leaving only the value to replace
+		[[ ${words[-1]} == -*=* && $COMP_WORDBREAKS == *=* ]] && candidate=${candidate#*=}
+		COMPREPLY+=("$candidate")
+	done
+}
+complete -o default -F _eth_tx_exporter eth-tx-exporter
+`,
+	"zsh": `#compdef eth-tx-exporter
+# zsh completion for eth-tx-exporter
+_eth_tx_exporter() {
+	local -a candidates values
+	local candidate
+	candidates=("${(@f)$("${words[1]}" __complete "${(@)words[2,CURRENT]}" 2>/dev/null)}")
+	for candidate in "${candidates[@]}"; do
+		[[ -n $candidate ]] || continue
+		if [[ $candidate == *$'\t'* ]]; then
+			values+=("${${candidate%%$'\t'*}//:/\\:}:${candidate#*$'\t'}")
+		else
+			values+=("${candidate//:/\\:}")
+		fi
+	done
+	if (( ${#values} )); then
+		_describe 'eth-tx-exporter' values
+	else
+		_files
+	fi
+}
+compdef _eth_tx_exporter eth-tx-exporter
+`,
+	"fish": `# fish completion for eth-tx-exporter
+function __eth_tx_exporter_candidates
+	set -l tokens (commandline -opc)
+	set -l current (commandline -ct)
+	$tokens[1] __complete $tokens[2..-1] "$current" 2>/dev/null
+end
+complete -c eth-tx-exporter -f -n 'count (__eth_tx_exporter_candidates) >/dev/null' -a '(__eth_tx_exporter_candidates)'
+complete -c eth-tx-exporter -F -n 'not count (__eth_tx_exporter_candidates) >/dev/null'
+`,
+}
+
+// candidate is a completion with an optional description
+type candidate struct {
+	value       string
+	description string
+}
+
+// runComplete implements __complete. args are the words after the program
+// name, the last one being the word to complete. Each candidate is printed
+// on a line, followed by a tab and its description when it has one.
+func runComplete(args []string) {
+	for _, c := range complete(args) {
+		if c.description != "" {
+			fmt.Printf("%s\t%s\n", c.value, c.description)
+		} else {
+			fmt.Println(c.value)
+		}
+	}
+}
+
+// complete returns the candidates for the last of words: commands, report
+// subcommands, flags, or the values of the flag before it
+func complete(words []string) []candidate {
+	if len(words) == 0 {
+		words = []string{""}
+	}
+	current := words[len(words)-1]
+	if len(words) == 1 && !strings.HasPrefix(current, "-") {
+		return matching(commandCandidates(commands, true), current)
+	}
+
+	// Invocations without a command mean fetch
+	name, rest := "fetch", words
+	if !strings.HasPrefix(words[0], "-") {
+		name, rest = words[0], words[1:]
+	}
+	switch name {
+	case "help":
+		if len(rest) == 1 {
+			return matching(commandCandidates(commands, false), current)
+		}
+		if cmd, ok := lookupCommand(rest[0]); ok && len(rest) == 2 {
+			return matching(commandCandidates(cmd.subcommands(), false), current)
+		}
+		return nil
+	case "completion":
+		if len(rest) == 1 {
+			return matching([]candidate{{value: "bash"}, {value: "fish"}, {value: "zsh"}}, current)
+		}
+		return nil
+	}
+	cmd, ok := lookupCommand(name)
+	if !ok {
+		return nil
+	}
+	if subcommands := cmd.subcommands(); subcommands != nil {
+		if len(rest) == 1 && !strings.HasPrefix(current, "-") {
+			return matching(commandCandidates(subcommands, false), current)
+		}
+		if sub, ok := cmd.subcommand(rest[0]); ok {
+			cmd, rest = sub, rest[1:]
+		}
+	}
+	return completeFlags(cmd.flags(), rest)
+}
+
+// commandCandidates returns the names of cmds with their summaries
+func commandCandidates(cmds []command, withHelp bool) []candidate {
+	var out []candidate
+	for _, cmd := range cmds {
+		out = append(out, candidate{cmd.name, cmd.summary})
+	}
+	if withHelp {
+		out = append(out, candidate{"help", "Show the commands, or the flags of a command"})
+	}
+	return out
+}
+
+// completeFlags completes the last of words, the arguments of the command
+// of fs, as a flag name or a flag value
+func completeFlags(fs *flag.FlagSet, words []string) []candidate {
+	if fs == nil || len(words) == 0 {
+		return nil
+	}
+	current := words[len(words)-1]
+	if len(words) > 1 {
+		if f := valueFlag(fs, words[len(words)-2]); f != nil {
+			return matching(flagValues(f, words, current), current)
+		}
+	}
+	if name, value, ok := strings.Cut(current, "="); ok && strings.HasPrefix(name, "-") {
+		f := valueFlag(fs, name)
+		if f == nil {
+			return nil
+		}
+		var out []candidate
+		for _, c := range matching(flagValues(f, words, value), value) {
+			out = append(out, candidate{name + "=" + c.value, c.description})
+		}
+		return out
+	}
+	if !strings.HasPrefix(current, "-") {
+		return nil
+	}
+	dash := "-"
+	if strings.HasPrefix(current, "--") {
+		dash = "--"
+	}
+	var out []candidate
+	fs.VisitAll(func(f *flag.Flag) {
+		out = append(out, candidate{dash + f.Name, f.Usage})
+	})
+	return matching(out, current)
+}
+
+// valueFlag returns the flag word names when it is a flag that takes a
+// separate value, or nil
+func valueFlag(fs *flag.FlagSet, word string) *flag.Flag {
+	if !strings.HasPrefix(word, "-") || strings.Contains(word, "=") {
+		return nil
+	}
+	f := fs.Lookup(strings.TrimLeft(word, "-"))
+	if f == nil {
+		return nil
+	}
+	if b, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && b.IsBoolFlag() {
+		return nil
+	}
+	return f
+}
+
+// flagValues returns the values of f to complete current with. Chains,
+// transaction types and the watchlists of a -store are listed; most other
+// flags offer the choices their usage lists.
+func flagValues(f *flag.Flag, words []string, current string) []candidate {
+	switch f.Name {
+	case "chain":
+		return chainCandidates("")
+	case "chains":
+		// Complete the last name of the comma-separated list
+		head := current[:strings.LastIndex(current, ",")+1]
+		return chainCandidates(head)
+	case "type":
+		head := current[:strings.LastIndex(current, ",")+1]
+		var out []candidate
+		for _, t := range models.TransactionTypes {
+			out = append(out, candidate{value: head + string(t)})
+		}
+		return out
+	case "sort":
+		return []candidate{
+			{string(report.ByTransactions), "transaction count"},
+			{string(report.ByIncoming), "ETH received"},
+			{string(report.ByOutgoing), "ETH sent"},
+		}
+	case "by":
+		return []candidate{{value: "month"}, {value: "quarter"}}
+	case "watchlist":
+		return watchlistCandidates(words)
+	case "address":
+		return watchedAddressCandidates(words)
+	}
+	return usageChoices(f.Usage)
+}
+
+// chainCandidates returns the registered chains, prefixed with head
+func chainCandidates(head string) []candidate {
+	var out []candidate
+	for _, name := range chains.Names() {
+		chain := chains.Registry[name]
+		out = append(out, candidate{head + name, fmt.Sprintf("%s, chain ID %d", chain.NativeSymbol, chain.ID)})
+	}
+	return out
+}
+
+// watchlistCandidates returns the watchlists of the -tenant in the -store
+// of words
+func watchlistCandidates(words []string) []candidate {
+	s, tenant := completionStore(words)
+	if s == nil {
+		return nil
+	}
+	var out []candidate
+	for _, w := range s.Watchlists(tenant) {
+		description := fmt.Sprintf("%d addresses", len(w.Addresses))
+		if len(w.Addresses) == 1 {
+			description = "1 address"
+		}
+		out = append(out, candidate{w.Name, description})
+	}
+	return out
+}
+
+// watchedAddressCandidates returns the addresses of the watchlists of the
+// -tenant in the -store of words, described by their labels
+func watchedAddressCandidates(words []string) []candidate {
+	s, tenant := completionStore(words)
+	if s == nil {
+		return nil
+	}
+	var out []candidate
+	for _, w := range s.Watchlists(tenant) {
+		for _, a := range w.Addresses {
+			description := w.Name
+			if a.Label != "" {
+				description = a.Label + " (" + w.Name + ")"
+			}
+			out = append(out, candidate{a.Address, description})
+		}
+	}
+	return out
+}
+
+// completionStore opens the -store named in words, returning it with the
+// -tenant named there or the default tenant, or nil without one
+func completionStore(words []string) (*store.Store, string) {
+	path := flagArgument(words, "store")
+	if path == "" {
+		return nil, ""
+	}
+	s, err := store.Open(path)
+	if err != nil {
+		return nil, ""
+	}
+	tenant := flagArgument(words, "tenant")
+	if tenant == "" {
+		tenant = server.DefaultTenant
+	}
+	return s, tenant
+}
+
+// flagArgument returns the value given to flag name in words, as -name
+// value or -name=value
+func flagArgument(words []string, name string) string {
+	for i, word := range words {
+		trimmed := strings.TrimLeft(word, "-")
+		if trimmed == word {
+			continue
+		}
+		if value, ok := strings.CutPrefix(trimmed, name+"="); ok {
+			return value
+		}
+		if trimmed == name && i+1 < len(words)-1 {
+			return words[i+1]
+		}
+	}
+	return ""
+}
+
+// usageChoices reads the values a flag accepts from its usage, which lists
+// them after a colon, as in "Output format: text, csv or pdf"
+func usageChoices(usage string) []candidate {
+	i := strings.LastIndex(usage, ": ")
+	if i < 0 {
+		return nil
+	}
+	var out []candidate
+	for _, word := range strings.Split(strings.ReplaceAll(usage[i+2:], " or ", ", "), ", ") {
+		if !isChoice(word) {
+			return nil
+		}
+		out = append(out, candidate{value: word})
+	}
+	if len(out) < 2 {
+		return nil
+	}
+	return out
+}
+
+// isChoice reports whether word looks like a flag value: lowercase letters,
+// digits and dashes
+func isChoice(word string) bool {
+	if word == "" {
+		return false
+	}
+	for _, r := range word {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' && r != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// matching returns the candidates starting with prefix
+func matching(candidates []candidate, prefix string) []candidate {
+	var out []candidate
+	for _, c := range candidates {
+		if strings.HasPrefix(c.value, prefix) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/haridev22/ct-assignement/pkg/server"
+	"github.com/haridev22/ct-assignement/pkg/store"
+	"github.com/stretchr/testify/assert"
+)
+
+// completions returns the values of the candidates for words
+func completions(words ...string) []string {
+	var out []string
+	for _, c := range complete(words) {
+		out = append(out, c.value)
+	}
+	return out
+}
+
+func TestComplete_Commands(t *testing.T) {
+	assert.Equal(t, []string{"validate", "version"}, completions("v"))
+	assert.Contains(t, completions(""), "help")
+	assert.Equal(t, []string{"report"}, completions("help", "rep"))
+	assert.Equal(t, []string{"gains"}, completions("help", "report", "ga"))
+	assert.Equal(t, []string{"holdings"}, completions("report", "ho"))
+	assert.Equal(t, []string{"bash"}, completions("completion", "b"))
+	assert.Empty(t, completions("nosuchcommand", "-"))
+}
+
+func TestComplete_Flags(t *testing.T) {
+	assert.Equal(t, []string{"-format"}, completions("validate", "-form"))
+	assert.Equal(t, []string{"--format"}, completions("validate", "--form"))
+	assert.Equal(t, []string{"-block"}, completions("report", "holdings", "-bl"), "report subcommands complete their own flags")
+	assert.Equal(t, []string{"-chain", "-chains"}, completions("-chai"), "invocations without a command mean fetch")
+	assert.Empty(t, completions("validate", "file.csv"), "arguments are left to the shell")
+
+	c := complete([]string{"validate", "-form"})
+	if assert.Len(t, c, 1) {
+		assert.Equal(t, "Output format: text or json", c[0].description)
+	}
+}
+
+func TestComplete_FlagValues(t *testing.T) {
+	assert.Equal(t, []string{"text", "json"}, completions("validate", "-format", ""), "choices are read from the usage")
+	assert.Equal(t, []string{"-format=json"}, completions("validate", "-format=j"))
+	assert.Equal(t, []string{"month", "quarter"}, completions("report", "statement", "-by", ""))
+	assert.Equal(t, []string{"in"}, completions("report", "counterparties", "-sort", "i"))
+	assert.Equal(t, []string{"ERC20_TRANSFER", "ERC721_TRANSFER", "ERC1155_TRANSFER"}, completions("query", "-type", "ERC"))
+	assert.Equal(t, []string{"ETH_TRANSFER,ERC20_TRANSFER"}, completions("query", "-type", "ETH_TRANSFER,ERC2"))
+	assert.Empty(t, completions("fetch", "-resume", ""), "boolean flags take no value")
+	assert.Empty(t, completions("fetch", "-output", ""), "free-form values are left to the shell")
+}
+
+func TestComplete_Chains(t *testing.T) {
+	assert.Equal(t, []string{"polygon"}, completions("fetch", "-chain", "pol"))
+	assert.Equal(t, []string{"ethereum,polygon"}, completions("fetch", "-chains", "ethereum,po"))
+	assert.Equal(t, []string{"-chain=base", "-chain=bsc"}, completions("tail", "-chain=b"))
+
+	c := complete([]string{"fetch", "-chain", "ethereum"})
+	if assert.Len(t, c, 1) {
+		assert.Equal(t, "ETH, chain ID 1", c[0].description)
+	}
+}
+
+func TestComplete_Watchlists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	err := store.Update(path, func(s *store.Store) error {
+		for _, w := range []store.Watchlist{
+			{Tenant: server.DefaultTenant, Name: "treasury", Addresses: []store.WatchedAddress{
+				{Address: "0x1111111111111111111111111111111111111111", Label: "cold wallet"},
+				{Address: "0x2222222222222222222222222222222222222222"},
+			}},
+			{Tenant: server.DefaultTenant, Name: "trading"},
+			{Tenant: "acme", Name: "ops"},
+		} {
+			if _, err := s.CreateWatchlist(w); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"trading", "treasury"}, completions("watch", "-store", path, "-watchlist", "tr"))
+	assert.Equal(t, []string{"ops"}, completions("watch", "-store="+path, "-tenant", "acme", "-watchlist", ""), "only the watchlists of -tenant")
+	assert.Empty(t, completions("watch", "-watchlist", ""), "no -store, no watchlists")
+
+	c := complete([]string{"watch", "-store", path, "-address", "0x"})
+	if assert.Len(t, c, 2) {
+		assert.Equal(t, candidate{"0x1111111111111111111111111111111111111111", "cold wallet (treasury)"}, c[0])
+		assert.Equal(t, candidate{"0x2222222222222222222222222222222222222222", "treasury"}, c[1])
+	}
+	c = complete([]string{"watch", "-store", path, "-watchlist", "trea"})
+	if assert.Len(t, c, 1) {
+		assert.Equal(t, "2 addresses", c[0].description)
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/haridev22/ct-assignement/pkg/export"
+	"github.com/haridev22/ct-assignement/pkg/models"
+)
+
+// defineConvert defines the convert subcommand, which rewrites an export
+// produced by an older schema version in the current schema
+func defineConvert(fs *flag.FlagSet) func() {
+	input := fs.String("input", "", "Export CSV to convert (required)")
+	output := fs.String("output", "", "File to write the converted CSV to (required)")
+	return func() {
+		if *input == "" || *output == "" {
+			log.Fatal("Error: convert requires -input and -output.")
+		}
+
+		txs, columns, err := export.ReadCSVFile(*input)
+		if err != nil {
+			log.Fatalf("Error reading %s: %v", *input, err)
+		}
+		version := models.DetectSchemaVersion(columns)
+
+		// Keep any optional columns the input carried, after the current defaults
+		outColumns := append([]models.Column{}, models.DefaultColumns...)
+		for _, col := range columns {
+			if !containsColumn(outColumns, col.Key) {
+				outColumns = append(outColumns, col)
+			}
+		}
+
+		if err := export.WriteCSVWithOptions(txs, *output, export.CSVOptions{Columns: outColumns}); err != nil {
+			log.Fatalf("Error exporting to CSV: %v", err)
+		}
+
+		fmt.Printf("Converted %d transactions from schema v%d to v%d\n", len(txs), version, models.SchemaVersion)
+		var added []string
+		for _, col := range outColumns {
+			if !containsColumn(columns, col.Key) {
+				added = append(added, col.Header)
+			}
+		}
+		if len(added) > 0 {
+			fmt.Printf("Columns not present in the input were left at zero values: %s\n", strings.Join(added, ", "))
+		}
+		fmt.Printf("Exported transaction history to %s\n", *output)
+	}
+}
+
+func containsColumn(cols []models.Column, key string) bool {
+	for _, col := range cols {
+		if col.Key == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/haridev22/ct-assignement/pkg/diff"
+	"github.com/haridev22/ct-assignement/pkg/export"
+	"github.com/haridev22/ct-assignement/pkg/models"
+)
+
+// defineDiff defines the diff subcommand, which compares two exports by
+// row ID and exits with status 1 when they differ, like diff(1)
+func defineDiff(fs *flag.FlagSet) func() {
+	format := fs.String("format", "text", "Output format: text or json")
+	ignore := fs.String("ignore", "", "Comma-separated column keys not to compare, such as gas_fee,status")
+	return func() {
+		if *format != "text" && *format != "json" {
+			log.Fatalf("Error: unknown -format %q (use text or json).", *format)
+		}
+		if fs.NArg() != 2 {
+			log.Fatal("Error: diff requires two exports: diff [flags] old.csv new.csv")
+		}
+		ignored, err := models.ParseColumnKeys(*ignore)
+		if err != nil {
+			log.Fatalf("Error: invalid -ignore: %v", err)
+		}
+		oldPath, newPath := fs.Arg(0), fs.Arg(1)
+		oldRows, oldColumns, err := export.ReadCSVFile(oldPath)
+		if err != nil {
+			log.Fatalf("Error reading %s: %v", oldPath, err)
+		}
+		newRows, newColumns, err := export.ReadCSVFile(newPath)
+		if err != nil {
+			log.Fatalf("Error reading %s: %v", newPath, err)
+		}
+
+		// Only columns both exports carry can be compared
+		var columns []models.Column
+		for _, col := range diff.CommonColumns(oldColumns, newColumns) {
+			if !containsColumn(ignored, col.Key) {
+				columns = append(columns, col)
+			}
+		}
+
+		result := diff.Compare(oldRows, newRows, columns)
+		if *format == "text" {
+			fmt.Printf("Comparing %s (%d rows) with %s (%d rows)\n\n", oldPath, len(oldRows), newPath, len(newRows))
+		}
+		printReport(result, *format)
+		if !result.Empty() {
+			os.Exit(1)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/api"
+)
+
+// runDryRun probes each transaction type of address with one request and
+// prints the estimated rows, pages, API calls and run time of a full
+// export, without exporting anything
+func runDryRun(client *api.EtherscanClient, address string, startBlock, endBlock, batchSize int64, callsPerSecond float64) {
+	fmt.Printf("Estimating the export of %s between blocks %d and %d...\n", address, startBlock, endBlock)
+
+	estimates, err := client.Estimate(address, startBlock, endBlock, batchSize)
+	if err != nil {
+		log.Fatalf("Error probing transaction counts: %v", err)
+	}
+
+	fmt.Printf("\n%-10s %12s %8s %10s\n", "Type", "Rows", "Pages", "API calls")
+	var rows, pages, calls int
+	var longest time.Duration
+	for _, est := range estimates {
+		count := fmt.Sprintf("%d", est.Rows)
+		if !est.Exact {
+			count = "~" + count
+		}
+		fmt.Printf("%-10s %12s %8d %10d\n", progressLabels[est.Action], count, est.Pages, est.Calls)
+		rows += est.Rows
+		pages += est.Pages
+		calls += est.Calls
+		longest = max(longest, est.Duration)
+	}
+	fmt.Printf("%-10s %12d %8d %10d\n", "total", rows, pages, calls)
+
+	// Types are fetched concurrently, so the run takes as long as the
+	// slowest type unless the rate limit of the keys is the bottleneck
+	keys := 1
+	if client.Keys != nil {
+		keys = client.Keys.Len()
+	}
+	if callsPerSecond <= 0 {
+		callsPerSecond = api.DefaultCallsPerSecond
+	}
+	limited := time.Duration(float64(calls) / (callsPerSecond * float64(keys)) * float64(time.Second))
+	fmt.Printf("\nEstimated run time: %s\n", max(longest, limited).Round(time.Second))
+	fmt.Printf("This dry run used %d API calls; nothing was exported.\n", client.Stats().Calls)
+}
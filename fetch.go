@@ -0,0 +1,1396 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/api"
+	"github.com/haridev22/ct-assignement/pkg/audit"
+	"github.com/haridev22/ct-assignement/pkg/chains"
+	"github.com/haridev22/ct-assignement/pkg/checkpoint"
+	"github.com/haridev22/ct-assignement/pkg/daterange"
+	"github.com/haridev22/ct-assignement/pkg/dedupe"
+	"github.com/haridev22/ct-assignement/pkg/enrich"
+	"github.com/haridev22/ct-assignement/pkg/export"
+	"github.com/haridev22/ct-assignement/pkg/filter"
+	"github.com/haridev22/ct-assignement/pkg/manifest"
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/haridev22/ct-assignement/pkg/progress"
+	"github.com/haridev22/ct-assignement/pkg/protect"
+	"github.com/haridev22/ct-assignement/pkg/rawarchive"
+	"github.com/haridev22/ct-assignement/pkg/report"
+	"github.com/haridev22/ct-assignement/pkg/runsummary"
+	"github.com/haridev22/ct-assignement/pkg/sample"
+	"github.com/haridev22/ct-assignement/pkg/skipped"
+	"github.com/haridev22/ct-assignement/pkg/version"
+	"github.com/haridev22/ct-assignement/pkg/wallets"
+)
+
+// fetchFlags are the flags of the fetch and sync subcommands
+type fetchFlags struct {
+	fs               *flag.FlagSet
+	address          *string
+	addressesFile    *string
+	apiKey           *string
+	rateLimit        *float64
+	httpTimeout      *time.Duration
+	deadline         *time.Duration
+	breakerFailures  *int
+	breakerCooldown  *time.Duration
+	maxAPICalls      *int64
+	outputDir        *string
+	startBlock       *int64
+	endBlock         *int64
+	fromDate         *string
+	toDate           *string
+	last             *string
+	batchBlocks      *int64
+	newestFirst      *bool
+	resume           *bool
+	summaryJSON      *string
+	noProgress       *bool
+	storePath        *string
+	asOfRun          *int64
+	feeBreakdown     *bool
+	blockRewards     *bool
+	dryRun           *bool
+	previewRows      *int
+	sampleSize       *string
+	approvalsMode    *bool
+	finalized        *bool
+	minConfirmations *int64
+	auditLog         *string
+	chainName        *string
+	chainsList       *string
+	rpcURL           *string
+	strict           *bool
+	archiveRaw       *bool
+	continueOnError  *bool
+	showVersion      *bool
+	export           *exportFlags
+	bigquery         *bigqueryFlags
+	log              *logFlags
+	transport        *transportFlags
+	profile          *profileFlags
+}
+
+func addFetchFlags(fs *flag.FlagSet) *fetchFlags {
+	return &fetchFlags{
+		fs:               fs,
+		address:          fs.String("address", "", "Ethereum wallet address to fetch transactions for (required)"),
+		addressesFile:    fs.String("addresses-file", "", "Export every address in this file (one address or address,label per line) instead of -address"),
+		apiKey:           fs.String("apikey", "", "Etherscan API key, or a comma-separated list of keys to rotate between (required)"),
+		rateLimit:        fs.Float64("rate-limit", api.DefaultCallsPerSecond, "Calls per second allowed for each key when several API keys are given"),
+		httpTimeout:      fs.Duration("http-timeout", 0, "Timeout for each HTTP request, e.g. 30s (0 keeps the default of 10s, or 30s for -rpc-url)"),
+		deadline:         fs.Duration("deadline", 0, "Stop gracefully with a checkpoint once the run has taken this long, e.g. 2h (0 = no deadline)"),
+		breakerFailures:  fs.Int("breaker-failures", 5, "Pause all requests after this many consecutive provider failures (0 disables the circuit breaker)"),
+		breakerCooldown:  fs.Duration("breaker-cooldown", 30*time.Second, "How long the circuit breaker pauses requests once tripped"),
+		maxAPICalls:      fs.Int64("max-api-calls", 0, "Stop gracefully with a checkpoint once this many API calls have been made (0 = no limit)"),
+		outputDir:        fs.String("output", defaultOutputDir, "Directory to save CSV output, or - to write the export to stdout"),
+		startBlock:       fs.Int64("start", defaultStartBlock, "Starting block number"),
+		endBlock:         fs.Int64("end", defaultEndBlock, "Ending block number (capped at the latest block)"),
+		fromDate:         fs.String("from-date", "", "Start at the first block on or after this date (YYYY-MM-DD, UTC, or RFC 3339)"),
+		toDate:           fs.String("to-date", "", "End at the last block on or before this date (YYYY-MM-DD includes the whole day)"),
+		last:             fs.String("last", "", "Export the given span up to now, e.g. 90d, 2w or 12h"),
+		batchBlocks:      fs.Int64("batch", 0, "Process in smaller block ranges (e.g., 100000 blocks at a time)"),
+		newestFirst:      fs.Bool("newest-first", false, "With -batch, process the most recent block ranges first"),
+		resume:           fs.Bool("resume", false, "Continue an interrupted export from its checkpoint in -output"),
+		summaryJSON:      fs.String("summary-json", "", "Write a JSON summary of the run to this file, or - for stdout"),
+		noProgress:       fs.Bool("no-progress", false, "Print plain progress lines instead of the live progress display (the default when output is not a terminal)"),
+		storePath:        fs.String("store", "", "Record fetched rows as a new run in this versioned store file"),
+		asOfRun:          fs.Int64("as-of-run", 0, "Export rows from -store as they were after this run instead of fetching"),
+		feeBreakdown:     fs.Bool("fee-breakdown", false, "Fetch receipts to split gas fees into EIP-1559 base and priority fees"),
+		blockRewards:     fs.Bool("block-rewards", false, "Include rewards for blocks validated by the address"),
+		dryRun:           fs.Bool("dry-run", false, "Probe transaction counts with one request per type and print the estimated pages, API calls and run time instead of exporting"),
+		previewRows:      fs.Int("preview", 0, "Print the first and last N rows as a table instead of writing the export, to check the flags"),
+		sampleSize:       fs.String("sample", "", "Estimate row counts from a deterministic sample of block windows (e.g. 10%) instead of exporting"),
+		approvalsMode:    fs.Bool("approvals", false, "Export the ERC-20 approvals granted by the address instead of its transactions"),
+		finalized:        fs.Bool("finalized", false, "Only include finalized blocks, which can no longer be reorganised"),
+		minConfirmations: fs.Int64("min-confirmations", 0, "Only include blocks at least this many blocks below the latest block"),
+		auditLog:         fs.String("audit-log", "", "Append a JSONL record of every provider call to this file"),
+		chainName:        addChainFlag(fs),
+		chainsList:       fs.String("chains", "", "Export -address from each of these comma-separated networks concurrently into one chain-tagged file, e.g. ethereum,polygon,arbitrum"),
+		rpcURL:           fs.String("rpc-url", "", "Scan blocks through this JSON-RPC node instead of Etherscan (for networks without an explorer)"),
+		strict:           fs.Bool("strict", false, "Skip records with malformed numeric fields, reporting them in the error report, instead of reading the fields as zero"),
+		archiveRaw:       fs.Bool("archive-raw", false, "Save the gzipped raw JSON response of every page to [address]_raw in -output"),
+		continueOnError:  fs.Bool("continue-on-error", false, "Export the transaction types that were fetched when others fail, and list the incomplete types in the run summary"),
+		showVersion:      fs.Bool("version", false, "Print the version and exit"),
+		export:           addExportFlags(fs),
+		bigquery:         addBigQueryFlags(fs),
+		log:              addLogFlags(fs),
+		transport:        addTransportFlags(fs),
+		profile:          addProfileFlags(fs),
+	}
+}
+
+// defineFetch defines the fetch and sync subcommands. fetch exports the
+// transactions of an address to CSV; sync records them in a store without
+// writing an export.
+func defineFetch(fs *flag.FlagSet) func() {
+	f := addFetchFlags(fs)
+	return func() { runFetch(f) }
+}
+
+// fetchConflict names the flags that cannot be combined with a flag, or
+// with the sync command, once it is in use
+type fetchConflict struct {
+	name      string
+	active    bool
+	conflicts []string
+}
+
+// given reports whether the flag name was set to a value other than zero,
+// so -batch 0 or -resume=false are treated as left out
+func (f *fetchFlags) given(name string) bool {
+	set := false
+	f.fs.Visit(func(fl *flag.Flag) {
+		if fl.Name == name {
+			switch fl.Value.String() {
+			case "", "0", "0s", "false":
+			default:
+				set = true
+			}
+		}
+	})
+	return set
+}
+
+// validateFetchFlags checks the flags of a fetch or sync run without doing
+// any I/O, returning an error that names the offending flags
+func validateFetchFlags(f *fetchFlags) error {
+	if *f.address == "" && *f.addressesFile == "" {
+		return errors.New("-address or -addresses-file is required")
+	}
+	for _, value := range []struct {
+		name     string
+		negative bool
+	}{
+		{"max-api-calls", *f.maxAPICalls < 0},
+		{"http-timeout", *f.httpTimeout < 0},
+		{"deadline", *f.deadline < 0},
+		{"breaker-failures", *f.breakerFailures < 0},
+		{"breaker-cooldown", *f.breakerCooldown < 0},
+		{"min-confirmations", *f.minConfirmations < 0},
+		{"preview", *f.previewRows < 0},
+	} {
+		if value.negative {
+			return fmt.Errorf("-%s cannot be negative", value.name)
+		}
+	}
+	if *f.newestFirst && *f.batchBlocks <= 0 {
+		return errors.New("-newest-first requires -batch")
+	}
+	if *f.asOfRun > 0 && *f.storePath == "" {
+		return errors.New("-as-of-run requires -store")
+	}
+	syncing := f.fs.Name() == "sync"
+	if syncing && *f.storePath == "" {
+		return errors.New("sync requires -store")
+	}
+	if *f.rpcURL != "" {
+		if chain, err := chains.Lookup(*f.chainName); err == nil && chain.Name != chains.Ethereum.Name {
+			return errors.New("-chain cannot be combined with -rpc-url, which reads the chain from the node")
+		}
+	}
+	if *f.outputDir == "-" && *f.summaryJSON == "-" {
+		return errors.New("-output - cannot be combined with -summary-json -")
+	}
+
+	dateFlag := ""
+	for _, name := range []string{"from-date", "to-date", "last"} {
+		if dateFlag == "" && f.given(name) {
+			dateFlag = "-" + name
+		}
+	}
+	rules := []fetchConflict{
+		{dateFlag, dateFlag != "", []string{"start", "end", "rpc-url"}},
+		{"-addresses-file", *f.addressesFile != "", []string{"address", "as-of-run", "rpc-url", "sample", "approvals"}},
+		{"-chains", *f.chainsList != "", []string{"chain", "start", "end", "batch", "addresses-file", "rpc-url", "as-of-run", "resume", "dry-run", "sample", "approvals", "store", "summary-json"}},
+		{"-preview", *f.previewRows > 0, []string{"batch", "addresses-file", "chains", "rpc-url", "as-of-run", "store", "resume", "summary-json", "manifest"}},
+		{"sync", syncing, []string{"as-of-run", "sample", "approvals", "manifest", "preview"}},
+		{"-running-balance", *f.export.balance, []string{"newest-first", "chains"}},
+		{"-archive-raw", *f.archiveRaw, []string{"rpc-url"}},
+		{"-output -", *f.outputDir == "-", []string{"batch", "addresses-file", "approvals"}},
+		{"-summary-json", *f.summaryJSON != "", []string{"as-of-run", "rpc-url", "sample", "approvals"}},
+		{"-bigquery-table", *f.bigquery.table != "" || *f.bigquery.dataset != "", []string{"chains", "rpc-url", "dry-run", "sample", "approvals"}},
+		{"-dry-run", *f.dryRun, []string{"addresses-file", "as-of-run", "rpc-url", "sample", "approvals", "resume", "summary-json"}},
+	}
+	for _, rule := range rules {
+		if !rule.active {
+			continue
+		}
+		var offending []string
+		for _, name := range rule.conflicts {
+			if f.given(name) {
+				offending = append(offending, "-"+name)
+			}
+		}
+		if len(offending) > 0 {
+			return fmt.Errorf("%s cannot be combined with %s", rule.name, joinOr(offending))
+		}
+	}
+	return nil
+}
+
+// joinOr joins names as "a, b or c"
+func joinOr(names []string) string {
+	if len(names) == 1 {
+		return names[0]
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " or " + names[len(names)-1]
+}
+
+// runFetch runs the fetch or sync subcommand with the parsed flags
+func runFetch(f *fetchFlags) {
+	f.log.apply()
+	if *f.log.quiet {
+		discardStdout()
+	}
+
+	if *f.showVersion {
+		fmt.Println(version.Version)
+		return
+	}
+	defer f.profile.start()()
+
+	if err := validateFetchFlags(f); err != nil {
+		log.Fatalf("Error: %v.", err)
+	}
+	dateRange, err := daterange.Resolve(*f.fromDate, *f.toDate, *f.last, time.Now())
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	var walletList []wallets.Wallet
+	if *f.addressesFile != "" {
+		if walletList, err = wallets.ParseFile(*f.addressesFile); err != nil {
+			log.Fatalf("Error reading %s: %v", *f.addressesFile, err)
+		}
+		if len(walletList) == 0 {
+			log.Fatalf("Error: %s lists no addresses.", *f.addressesFile)
+		}
+	}
+
+	var chainList []chains.Chain
+	if *f.chainsList != "" {
+		chainList = parseChains(*f.chainsList)
+	}
+
+	*f.apiKey = resolveAPIKey(*f.apiKey)
+	*f.rpcURL = resolveSecret("-rpc-url", *f.rpcURL)
+	if *f.apiKey == "" && *f.rpcURL == "" && chainList == nil {
+		log.Fatal("Error: Etherscan API key is required. Use -apikey flag or set ETHERSCAN_API_KEY environment variable.")
+	}
+	for _, chain := range chainList {
+		if chainAPIKey(chain, *f.apiKey) == "" {
+			log.Fatalf("Error: no API key for %s. Use -apikey or set %s_API_KEY.", chain.Name, strings.ToUpper(chain.Name))
+		}
+	}
+
+	opts := f.options(chainList)
+	if *f.auditLog != "" {
+		if opts.audit, err = audit.Open(*f.auditLog); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		defer opts.audit.Close()
+		fmt.Printf("Recording provider calls in %s\n", *f.auditLog)
+	}
+
+	if *f.asOfRun > 0 {
+		exportFromStore(*f.storePath, *f.address, *f.asOfRun, *f.outputDir, opts)
+		writeManifest(*f.outputDir, opts)
+		return
+	}
+
+	// On SIGINT or SIGTERM, cancel in-flight requests and save what was
+	// fetched so far. A second signal exits immediately.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	opts.ctx = ctx
+	if *f.deadline > 0 {
+		// Past the deadline the run stops the same way, so a scheduled job
+		// cannot hang on a slow provider
+		var cancel context.CancelFunc
+		opts.ctx, cancel = context.WithTimeout(ctx, *f.deadline)
+		defer cancel()
+	}
+
+	switch {
+	case *f.rpcURL != "":
+		runRPCScan(*f.rpcURL, *f.address, *f.startBlock, *f.endBlock, *f.outputDir, opts)
+		writeManifest(*f.outputDir, opts)
+	case chainList != nil:
+		fetchMultichain(f, chainList, dateRange, opts)
+	default:
+		fetchFromExplorer(f, walletList, dateRange, opts)
+	}
+}
+
+// options returns the run options selected by the flags, exiting on
+// invalid values
+func (f *fetchFlags) options(chainList []chains.Chain) runOptions {
+	opts := f.export.options()
+	if *f.feeBreakdown {
+		opts.csv.Columns = appendMissingColumns(opts.csv.Columns, "effective_gas_price", "base_fee", "priority_fee")
+	}
+	opts.storePath = *f.storePath
+	opts.feeBreakdown = *f.feeBreakdown
+	opts.blockRewards = *f.blockRewards
+	opts.minConfirmations = *f.minConfirmations
+	opts.newestFirst = *f.newestFirst
+	opts.finalized = *f.finalized
+	opts.resume = *f.resume
+	opts.continueOnError = *f.continueOnError
+	opts.preview = *f.previewRows
+	// The L1 data fee is often most of the cost on OP-stack chains
+	if lookupChain(*f.chainName).OPStack {
+		opts.csv.Columns = appendMissingColumns(opts.csv.Columns, "l1_fee", "total_fee")
+		opts.l1Fees = true
+	}
+	if chainList != nil {
+		opts.csv.Columns = appendMissingColumns(opts.csv.Columns, "chain")
+		for _, chain := range chainList {
+			if chain.OPStack {
+				opts.csv.Columns = appendMissingColumns(opts.csv.Columns, "l1_fee", "total_fee")
+			}
+		}
+	}
+	opts.archiveRaw = *f.archiveRaw
+	opts.httpTimeout = *f.httpTimeout
+	opts.transport = f.transport.roundTripper()
+	exportToStdout(f.outputDir, &opts)
+	opts.progress = !*f.noProgress && !*f.log.quiet && progress.IsTerminal(os.Stdout)
+	opts.storeOnly = f.fs.Name() == "sync"
+	opts.bigquery = f.bigquery.sink()
+	opts.converter.Strict = *f.strict
+	if *f.maxAPICalls > 0 {
+		opts.budget = api.NewCallBudget(*f.maxAPICalls)
+	}
+	return opts
+}
+
+// client creates the explorer client of chain. Chains of a multichain
+// export share the call budget but not their rate limits.
+func (f *fetchFlags) client(chain chains.Chain, opts runOptions) *api.EtherscanClient {
+	client := newClient(chainAPIKey(chain, *f.apiKey), *f.rateLimit, chain)
+	client.Audit = opts.audit
+	client.Context = opts.ctx
+	client.HTTPClient.Transport = opts.transport
+	if *f.httpTimeout > 0 {
+		client.HTTPClient.Timeout = *f.httpTimeout
+	}
+	if *f.breakerFailures > 0 {
+		client.Breaker = api.NewCircuitBreaker(*f.breakerFailures, *f.breakerCooldown)
+	}
+	client.Budget = opts.budget
+	if client.Keys != nil {
+		fmt.Printf("Rotating between %d API keys at up to %g calls per second each\n", client.Keys.Len(), *f.rateLimit)
+	}
+	return client
+}
+
+// fetchMultichain exports -address from every chain of -chains into one file
+func fetchMultichain(f *fetchFlags, chainList []chains.Chain, dateRange daterange.Range, opts runOptions) {
+	describeManifestRun(opts, "etherscan", 0, 0, *f.address)
+	if opts.manifest != nil {
+		for _, chain := range chainList {
+			opts.manifest.Chains = append(opts.manifest.Chains, chain.Name)
+		}
+	}
+	clientFor := func(chain chains.Chain) *api.EtherscanClient {
+		return f.client(chain, opts)
+	}
+	if err := runMultichain(chainList, clientFor, *f.address, dateRange, *f.outputDir, opts); err != nil {
+		log.Fatalf("Error: %v%s", err, errorHint(err))
+	}
+	writeManifest(*f.outputDir, opts)
+}
+
+// fetchFromExplorer fetches through the explorer of -chain, exporting
+// -address or every address of walletList unless a probing mode such as
+// -dry-run, -sample or -approvals is selected
+func fetchFromExplorer(f *fetchFlags, walletList []wallets.Wallet, dateRange daterange.Range, opts runOptions) {
+	chain := lookupChain(*f.chainName)
+	client := f.client(chain, opts)
+	opts.converter = converterFor(chain)
+	opts.converter.Strict = *f.strict
+
+	startBlock, endBlock := resolveBlockRange(client, dateRange, *f.startBlock, *f.endBlock, opts)
+	switch {
+	case *f.dryRun:
+		runDryRun(client, *f.address, startBlock, endBlock, *f.batchBlocks, *f.rateLimit)
+		return
+	case *f.sampleSize != "":
+		fraction, err := sample.ParseFraction(*f.sampleSize)
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		runSample(client, *f.address, startBlock, endBlock, fraction)
+		return
+	case *f.approvalsMode:
+		runApprovals(client, *f.address, startBlock, endBlock, *f.outputDir)
+		return
+	}
+
+	if *f.summaryJSON != "" {
+		opts.summary = runsummary.New(startBlock, endBlock)
+		opts.summary.Address = *f.address
+		for _, wallet := range walletList {
+			opts.summary.Addresses = append(opts.summary.Addresses, wallet.Address)
+		}
+	}
+	if walletList != nil {
+		fetchWallets(f, client, walletList, startBlock, endBlock, opts)
+		return
+	}
+	fetchWallet(f, client, startBlock, endBlock, opts)
+}
+
+// resolveBlockRange checks the keys with a cheap call before any real work
+// and resolves the real chain head, so the end block, batch planning and
+// progress percentages reflect the chain instead of the open-ended default.
+// The range is then narrowed to the date range, the confirmed blocks and
+// the finalized blocks as requested.
+func resolveBlockRange(client *api.EtherscanClient, dateRange daterange.Range, startBlock, endBlock int64, opts runOptions) (int64, int64) {
+	head, err := client.Preflight()
+	if err != nil {
+		log.Fatalf("Error: preflight check failed: %v%s", err, errorHint(err))
+	}
+	if !dateRange.Start.IsZero() || !dateRange.End.IsZero() {
+		startBlock, endBlock = resolveDateRange(client, dateRange, startBlock, endBlock)
+	}
+	if startBlock > head {
+		log.Fatalf("Error: start block %d is beyond the latest block %d.", startBlock, head)
+	}
+	if endBlock > head {
+		endBlock = head
+	}
+	if opts.minConfirmations > 0 {
+		endBlock = confirmedEndBlock(startBlock, endBlock, head, opts.minConfirmations)
+	}
+	if opts.finalized {
+		finalizedBlock, err := client.GetFinalizedBlockNumber()
+		if err != nil {
+			log.Fatalf("Error: could not determine the latest finalized block: %v", err)
+		}
+		endBlock = finalizedEndBlock(startBlock, endBlock, finalizedBlock)
+	}
+	return startBlock, endBlock
+}
+
+// fetchWallets exports every address of -addresses-file, exiting with a
+// failure status when any of them failed
+func fetchWallets(f *fetchFlags, client *api.EtherscanClient, walletList []wallets.Wallet, startBlock, endBlock int64, opts runOptions) {
+	for _, wallet := range walletList {
+		describeManifestRun(opts, "etherscan", startBlock, endBlock, wallet.Address)
+	}
+	failed := runBulk(client, walletList, startBlock, endBlock, *f.batchBlocks, *f.outputDir, opts)
+	printAPIUsage(client)
+	status := runsummary.StatusComplete
+	if opts.interrupted() {
+		status = runsummary.StatusInterrupted
+	} else if failed > 0 {
+		status = runsummary.StatusFailed
+	}
+	writeRunSummary(*f.summaryJSON, client, opts, status)
+	if opts.manifest.Len() > 0 {
+		writeManifest(*f.outputDir, opts)
+	}
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// fetchWallet exports -address, in batches with -batch
+func fetchWallet(f *fetchFlags, client *api.EtherscanClient, startBlock, endBlock int64, opts runOptions) {
+	fmt.Printf("Fetching transactions for address: %s\n", *f.address)
+	fmt.Printf("Block range: %d to %d\n", startBlock, endBlock)
+
+	describeManifestRun(opts, "etherscan", startBlock, endBlock, *f.address)
+	err := exportWallet(client, *f.address, startBlock, endBlock, *f.batchBlocks, *f.outputDir, opts)
+	if err == nil {
+		writeManifest(*f.outputDir, opts)
+	}
+	status := runsummary.StatusComplete
+	if errors.Is(err, errInterrupted) {
+		status = runsummary.StatusInterrupted
+	} else if err != nil {
+		status = runsummary.StatusFailed
+	}
+	opts.summary.AddError(err)
+	writeRunSummary(*f.summaryJSON, client, opts, status)
+	printAPIUsage(client)
+	if err != nil {
+		log.Fatalf("Error: %v%s", err, errorHint(err))
+	}
+}
+
+// writeRunSummary completes the run summary, if one was requested, and
+// writes it to path, where - means stdout
+func writeRunSummary(path string, client *api.EtherscanClient, opts runOptions, status runsummary.Status) {
+	if opts.summary == nil {
+		return
+	}
+	stats := client.Stats()
+	opts.summary.Finish(status, stats.Calls, stats.Retries)
+
+	var err error
+	if path == "-" {
+		err = opts.summary.Write(stdout)
+	} else {
+		err = opts.summary.WriteFile(path)
+	}
+	if err != nil {
+		logger.Error("failed to write run summary", "error", err)
+	}
+}
+
+// exportWallet exports the transactions of address, in batches when
+// batchSize is positive
+func exportWallet(client *api.EtherscanClient, address string, startBlock, endBlock, batchSize int64, outputDir string, opts runOptions) error {
+	cp, fetched, err := loadCheckpoint(address, startBlock, endBlock, outputDir, opts)
+	if err != nil {
+		return err
+	}
+	// A resumed export keeps the records skipped before its interruption
+	if opts.skipped, err = skipped.New(filepath.Join(outputDir, fmt.Sprintf("%s_errors.jsonl", address)), address, opts.resume); err != nil {
+		return err
+	}
+	defer closeErrorReport(opts)
+	if opts.archiveRaw {
+		client.Archive = rawarchive.New(filepath.Join(outputDir, address+"_raw"))
+		defer func() { client.Archive = nil }()
+		fmt.Printf("Archiving raw API responses in %s\n", client.Archive.Dir())
+	}
+	if batchSize > 0 {
+		// Resumed batches continue from the balance of the rows exported before
+		if opts.balances != nil {
+			if err := opts.balances.Resume(address, fetched); err != nil {
+				return fmt.Errorf("cannot continue the running balance: %w; export again without -resume", err)
+			}
+		}
+		err = processInBatches(client, cp, fetched, batchSize, outputDir, opts)
+	} else {
+		err = exportSinglePass(client, cp, outputDir, opts)
+	}
+	if err == nil {
+		clearCheckpoint(address, outputDir)
+		reconcileBalance(address, cp.EndBlock, opts, etherscanBalance(client, address, cp.EndBlock))
+	}
+	return err
+}
+
+// closeErrorReport closes the error report of an export, and points to it
+// when records were skipped
+func closeErrorReport(opts runOptions) {
+	if err := opts.skipped.Close(); err != nil {
+		logger.Error("failed to write error report", "error", err)
+	}
+	if count := opts.skipped.Count(); count > 0 {
+		fmt.Printf("Skipped %d records that could not be converted, see %s\n", count, opts.skipped.Path())
+		opts.summary.AddOutput(opts.skipped.Path())
+	}
+}
+
+// exportSinglePass fetches all transaction types of address concurrently
+// and writes them to a single file
+func exportSinglePass(client *api.EtherscanClient, cp *checkpoint.Checkpoint, outputDir string, opts runOptions) error {
+	address, startBlock, endBlock := cp.Address, cp.StartBlock, cp.EndBlock
+	var wg sync.WaitGroup
+	wg.Add(5) // five transaction types
+
+	// channel for transactions
+	normalTxCh := make(chan []api.NormalTransaction, 1)
+	internalTxCh := make(chan []api.InternalTransaction, 1)
+	erc20TxCh := make(chan []api.ERC20Transaction, 1)
+	erc721TxCh := make(chan []api.ERC721Transaction, 1)
+	erc1155TxCh := make(chan []api.ERC1155Transaction, 1)
+	errorCh := make(chan error, 5)
+
+	fmt.Println("Fetching normal and internal transactions and ERC-20, ERC-721 and ERC-1155 transfers...")
+	stopProgress := trackProgress(client, opts, startBlock, endBlock)
+
+	// Fetch normal ETH transactions with pagination
+	go func() {
+		defer wg.Done()
+		txs, err := client.GetAllNormalTransactions(address, startBlock, endBlock)
+		if err != nil {
+			errorCh <- &typeError{kind: "normal", err: fmt.Errorf("error fetching normal transactions: %w", err)}
+			normalTxCh <- nil
+			return
+		}
+		normalTxCh <- txs
+	}()
+
+	// Fetch internal transactions with pagination
+	go func() {
+		defer wg.Done()
+		txs, err := client.GetAllInternalTransactions(address, startBlock, endBlock)
+		if err != nil {
+			errorCh <- &typeError{kind: "internal", err: fmt.Errorf("error fetching internal transactions: %w", err)}
+			internalTxCh <- nil
+			return
+		}
+		internalTxCh <- txs
+	}()
+
+	// Fetch ERC-20 token transfers with pagination
+	go func() {
+		defer wg.Done()
+		txs, err := client.GetAllERC20Transfers(address, startBlock, endBlock)
+		if err != nil {
+			errorCh <- &typeError{kind: "erc20", err: fmt.Errorf("error fetching ERC-20 transfers: %w", err)}
+			erc20TxCh <- nil
+			return
+		}
+		erc20TxCh <- txs
+	}()
+
+	// Fetch ERC-721 NFT transfers with pagination
+	go func() {
+		defer wg.Done()
+		txs, err := client.GetAllERC721Transfers(address, startBlock, endBlock)
+		if err != nil {
+			errorCh <- &typeError{kind: "erc721", err: fmt.Errorf("error fetching ERC-721 transfers: %w", err)}
+			erc721TxCh <- nil
+			return
+		}
+		erc721TxCh <- txs
+	}()
+
+	// Fetch ERC-1155 token transfers with pagination
+	go func() {
+		defer wg.Done()
+		txs, err := client.GetAllERC1155Transfers(address, startBlock, endBlock)
+		if err != nil {
+			errorCh <- &typeError{kind: "erc1155", err: fmt.Errorf("error fetching ERC-1155 transfers: %w", err)}
+			erc1155TxCh <- nil
+			return
+		}
+		erc1155TxCh <- txs
+	}()
+
+	// Wait for all goroutines to complete
+	wg.Wait()
+	stopProgress()
+
+	// Report every type that failed, not just the first. Once interrupted,
+	// the types that finished are still saved.
+	close(errorCh)
+	var errs []error
+	for err := range errorCh {
+		errs = append(errs, err)
+	}
+	// Errors arrive in completion order; sort them so reruns read the same
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Error() < errs[j].Error() })
+	if len(errs) > 0 && !opts.interrupted() {
+		if !opts.continueOnError {
+			return errors.Join(errs...)
+		}
+		for _, err := range errs {
+			fmt.Printf("Warning: %v\n", err)
+			opts.summary.AddError(err)
+			markIncomplete(opts, err)
+		}
+	}
+
+	// Convert all transactions to a common model
+	var allTxs []models.Transaction
+
+	// normal transactions
+	normalTxs := <-normalTxCh
+	for _, tx := range normalTxs {
+		model, err := opts.converter.NormalTx(tx)
+		if err != nil {
+			skipRecord(opts, "normal", tx.Hash, tx, err)
+			continue
+		}
+		allTxs = append(allTxs, model)
+	}
+
+	// internal transactions
+	internalTxs := <-internalTxCh
+	for _, tx := range internalTxs {
+		model, err := opts.converter.InternalTx(tx)
+		if err != nil {
+			skipRecord(opts, "internal", tx.Hash, tx, err)
+			continue
+		}
+		allTxs = append(allTxs, model)
+	}
+
+	// ERC20 transactions
+	erc20Txs := <-erc20TxCh
+	allTxs = append(allTxs, convertERC20Transfers(opts, erc20Txs)...)
+
+	// ERC721 transactions
+	erc721Txs := <-erc721TxCh
+	for _, tx := range erc721Txs {
+		model, err := opts.converter.ERC721Tx(tx)
+		if err != nil {
+			skipRecord(opts, "erc721", tx.Hash, tx, err)
+			continue
+		}
+		allTxs = append(allTxs, model)
+	}
+
+	// ERC1155 transactions
+	erc1155Txs := <-erc1155TxCh
+	for _, tx := range erc1155Txs {
+		model, err := opts.converter.ERC1155Tx(tx)
+		if err != nil {
+			skipRecord(opts, "erc1155", tx.Hash, tx, err)
+			continue
+		}
+		allTxs = append(allTxs, model)
+	}
+
+	if opts.blockRewards && !opts.interrupted() {
+		rewards, err := fetchBlockRewards(client, opts, address, startBlock, endBlock)
+		if err != nil && !opts.interrupted() {
+			err = &typeError{kind: "rewards", err: fmt.Errorf("error fetching block rewards: %w", err)}
+			if !opts.continueOnError {
+				return err
+			}
+			fmt.Printf("Warning: %v\n", err)
+			opts.summary.AddError(err)
+			markIncomplete(opts, err)
+			errs = append(errs, err)
+		}
+		allTxs = append(allTxs, rewards...)
+	}
+	if opts.interrupted() {
+		// No block range is complete, so a resumed run fetches it all again
+		return saveCheckpoint(cp, prepareExport(address, allTxs, opts), outputDir, opts)
+	}
+
+	fmt.Printf("Total transactions processed: %d\n", len(allTxs))
+
+	enrichTransactions(client, opts, allTxs)
+
+	if opts.storePath != "" {
+		// Unless a fetcher failed under -continue-on-error, the result is
+		// complete for the range
+		recordRun(opts.storePath, address, startBlock, endBlock, allTxs, len(errs) == 0)
+	}
+	if opts.storeOnly {
+		opts.summary.CountRows(allTxs)
+		return loadFetched(opts, address, startBlock, endBlock, allTxs, len(errs) == 0)
+	}
+
+	allTxs = prepareExport(address, allTxs, opts)
+	opts.summary.CountRows(allTxs)
+
+	if opts.preview > 0 {
+		printPreview(address, allTxs, opts.preview, true)
+		return nil
+	}
+
+	// Export to CSV
+	fmt.Printf("Total transactions: %d\n", len(allTxs))
+
+	// Export to CSV
+	written, err := writeExport(allTxs, outputDir, address+"_tx_history", opts)
+	if err != nil {
+		return fmt.Errorf("error exporting to CSV: %w", err)
+	}
+
+	fmt.Printf("Exported transaction history to %s\n", written)
+	if err := loadFetched(opts, address, startBlock, endBlock, allTxs, len(errs) == 0); err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		var missing []string
+		for _, err := range errs {
+			missing = append(missing, err.(*typeError).kind)
+		}
+		fmt.Printf("Warning: the export is incomplete, it lacks the %s rows that failed to fetch\n", strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// processInBatches processes transactions in smaller block ranges to avoid memory issues
+func processInBatches(client *api.EtherscanClient, cp *checkpoint.Checkpoint, fetched []models.Transaction, batchSize int64, outputDir string, opts runOptions) error {
+	address, startBlock, endBlock := cp.Address, cp.StartBlock, cp.EndBlock
+	allTxs := fetched
+	var processedBlocks, fetchedBlocks int64
+	started := time.Now()
+	totalBlocks := endBlock - startBlock
+
+	// Block rewards cannot be fetched by range, so fetch them once up front
+	var rewards []models.Transaction
+	if opts.blockRewards {
+		var err error
+		rewards, err = fetchBlockRewards(client, opts, address, startBlock, endBlock)
+		if err != nil {
+			fmt.Printf("Warning: Error fetching block rewards: %v\n", err)
+			opts.summary.AddError(err)
+			opts.summary.MarkIncomplete("rewards")
+		}
+	}
+
+	// Process in batches
+	for _, batch := range batchRanges(startBlock, endBlock, batchSize, opts.newestFirst) {
+		currentStart, currentEnd := batch[0], batch[1]
+		if cp.Done(currentStart, currentEnd) {
+			fmt.Printf("Skipping blocks %d to %d, fetched before the interruption\n", currentStart, currentEnd)
+			processedBlocks += (currentEnd - currentStart)
+			continue
+		}
+		if opts.interrupted() {
+			break
+		}
+
+		eta := ""
+		if fetchedBlocks > 0 {
+			// Extrapolate from the batches fetched by this run, not those resumed
+			remaining := float64(totalBlocks-processedBlocks) / float64(fetchedBlocks)
+			eta = fmt.Sprintf(", ETA %s", time.Duration(float64(time.Since(started))*remaining).Round(time.Second))
+		}
+		fmt.Printf("\n=== Processing blocks %d to %d (%d%% complete%s) ===\n",
+			currentStart, currentEnd, int(float64(processedBlocks)/float64(totalBlocks)*100), eta)
+
+		fmt.Println("Fetching transactions for batch...")
+		stopProgress := trackProgress(client, opts, currentStart, currentEnd)
+		batchTxs, errs := fetchRange(client, opts, address, currentStart, currentEnd)
+		stopProgress()
+		if opts.interrupted() {
+			// The batch was cut short; a resumed run fetches it again
+			break
+		}
+		for _, err := range errs {
+			fmt.Printf("Warning: Error %v\n", err)
+			opts.summary.AddError(err)
+			markIncomplete(opts, err)
+		}
+
+		// Consecutive batches share their boundary block, so only the last batch includes its end
+		for _, reward := range rewards {
+			if reward.BlockNumber >= currentStart && (reward.BlockNumber < currentEnd || currentEnd == endBlock) {
+				batchTxs = append(batchTxs, reward)
+			}
+		}
+
+		enrichTransactions(client, opts, batchTxs)
+
+		if opts.storePath != "" {
+			// Fetch errors above are only warnings, so never treat a batch as complete
+			recordRun(opts.storePath, address, currentStart, currentEnd, batchTxs, false)
+		}
+		processedBlocks += (currentEnd - currentStart)
+		fetchedBlocks += (currentEnd - currentStart)
+		cp.Completed = append(cp.Completed, batch)
+		if opts.storeOnly {
+			opts.summary.CountRows(batchTxs)
+			if err := loadFetched(opts, address, currentStart, currentEnd, batchTxs, len(errs) == 0); err != nil {
+				return err
+			}
+			continue
+		}
+
+		batchTxs = prepareExport(address, batchTxs, opts)
+		if err := loadFetched(opts, address, currentStart, currentEnd, batchTxs, len(errs) == 0); err != nil {
+			return err
+		}
+
+		// Append to all transactions
+		allTxs = append(allTxs, batchTxs...)
+
+		// Write intermediate results to CSV
+		intermediateFilePath := filepath.Join(outputDir,
+			fmt.Sprintf("%s_tx_history_blocks_%d_%d%s", address, currentStart, currentEnd, opts.csv.Extension()))
+		if err := export.WriteCSVWithOptions(batchTxs, intermediateFilePath, opts.csv); err != nil {
+			fmt.Printf("Warning: Error saving intermediate results: %v\n", err)
+			opts.summary.AddError(err)
+		} else {
+			intermediateFilePath = protectOutput(intermediateFilePath, opts)
+			fmt.Printf("Saved intermediate results to %s\n", intermediateFilePath)
+		}
+	}
+	if opts.interrupted() {
+		return saveCheckpoint(cp, dedupe.Apply(allTxs, opts.duplicates), outputDir, opts)
+	}
+	if opts.storeOnly {
+		return nil
+	}
+
+	// Consecutive batches share their boundary block, so drop the rows fetched twice
+	allTxs = dedupe.Apply(allTxs, opts.duplicates)
+	if opts.newestFirst {
+		// Restore chronological order; the stable sort keeps rows of a block in API order
+		sort.SliceStable(allTxs, func(i, j int) bool {
+			return allTxs[i].BlockNumber < allTxs[j].BlockNumber
+		})
+	}
+
+	opts.summary.CountRows(allTxs)
+
+	// Export final combined CSV
+	written, err := writeExport(allTxs, outputDir, address+"_tx_history_full", opts)
+	if err != nil {
+		return fmt.Errorf("error exporting to CSV: %w", err)
+	}
+
+	fmt.Printf("\nComplete! Exported %d transactions to %s\n", len(allTxs), written)
+	return nil
+}
+
+// progressLabels names the Etherscan actions on the progress display
+var progressLabels = map[string]string{
+	"txlist":         "normal",
+	"txlistinternal": "internal",
+	"tokentx":        "erc20",
+	"tokennfttx":     "erc721",
+	"token1155tx":    "erc1155",
+	"getminedblocks": "rewards",
+}
+
+// trackProgress reports the pages the client fetches for
+// startBlock..endBlock, on a live display when opts.progress is set and as a
+// line per page otherwise. The returned function draws the final state and
+// detaches the display.
+func trackProgress(client *api.EtherscanClient, opts runOptions, startBlock, endBlock int64) func() {
+	if !opts.progress {
+		client.Progress = func(e api.PageEvent) {
+			fmt.Printf("Fetched %d %s rows (page %d)\n", e.Rows, progressLabels[e.Action], e.Page)
+		}
+		return func() { client.Progress = nil }
+	}
+	tracker := progress.New(os.Stdout, startBlock, endBlock, "normal", "internal", "erc20", "erc721", "erc1155")
+	client.Progress = func(e api.PageEvent) {
+		tracker.Page(progressLabels[e.Action], e.Rows, e.Block)
+	}
+	return func() {
+		client.Progress = nil
+		tracker.Finish()
+	}
+}
+
+// typeError is the failure to fetch one transaction type, named by its
+// progress label, so a partial export can report the types it lacks
+type typeError struct {
+	kind string
+	err  error
+}
+
+func (e *typeError) Error() string {
+	return e.err.Error()
+}
+
+func (e *typeError) Unwrap() error {
+	return e.err
+}
+
+// markIncomplete records in the run summary the transaction type that err
+// failed to fetch
+func markIncomplete(opts runOptions, err error) {
+	var failed *typeError
+	if errors.As(err, &failed) {
+		opts.summary.MarkIncomplete(failed.kind)
+	}
+}
+
+// fetchRange fetches and converts every transaction type of address in the
+// inclusive block range sequentially. A type that fails to fetch is left out
+// and its error returned, so callers decide whether partial data is usable.
+func fetchRange(client *api.EtherscanClient, opts runOptions, address string, startBlock, endBlock int64) ([]models.Transaction, []error) {
+	var txs []models.Transaction
+	var errs []error
+
+	// Normal transactions
+	normalTxs, err := client.GetAllNormalTransactions(address, startBlock, endBlock)
+	if err != nil {
+		errs = append(errs, &typeError{kind: "normal", err: fmt.Errorf("fetching normal transactions for block range %d-%d: %w", startBlock, endBlock, err)})
+	} else {
+		for _, tx := range normalTxs {
+			convertedTx, err := opts.converter.NormalTx(tx)
+			if err != nil {
+				skipRecord(opts, "normal", tx.Hash, tx, err)
+				continue
+			}
+			txs = append(txs, convertedTx)
+		}
+	}
+
+	// Internal transactions
+	internalTxs, err := client.GetAllInternalTransactions(address, startBlock, endBlock)
+	if err != nil {
+		errs = append(errs, &typeError{kind: "internal", err: fmt.Errorf("fetching internal transactions for block range %d-%d: %w", startBlock, endBlock, err)})
+	} else {
+		for _, tx := range internalTxs {
+			convertedTx, err := opts.converter.InternalTx(tx)
+			if err != nil {
+				skipRecord(opts, "internal", tx.Hash, tx, err)
+				continue
+			}
+			txs = append(txs, convertedTx)
+		}
+	}
+
+	// ERC20 transfers
+	erc20Txs, err := client.GetAllERC20Transfers(address, startBlock, endBlock)
+	if err != nil {
+		errs = append(errs, &typeError{kind: "erc20", err: fmt.Errorf("fetching ERC20 transfers for block range %d-%d: %w", startBlock, endBlock, err)})
+	} else {
+		txs = append(txs, convertERC20Transfers(opts, erc20Txs)...)
+	}
+
+	// ERC721 transfers
+	erc721Txs, err := client.GetAllERC721Transfers(address, startBlock, endBlock)
+	if err != nil {
+		errs = append(errs, &typeError{kind: "erc721", err: fmt.Errorf("fetching ERC721 transfers for block range %d-%d: %w", startBlock, endBlock, err)})
+	} else {
+		for _, tx := range erc721Txs {
+			convertedTx, err := opts.converter.ERC721Tx(tx)
+			if err != nil {
+				skipRecord(opts, "erc721", tx.Hash, tx, err)
+				continue
+			}
+			txs = append(txs, convertedTx)
+		}
+	}
+
+	// ERC1155 transfers
+	erc1155Txs, err := client.GetAllERC1155Transfers(address, startBlock, endBlock)
+	if err != nil {
+		errs = append(errs, &typeError{kind: "erc1155", err: fmt.Errorf("fetching ERC1155 transfers for block range %d-%d: %w", startBlock, endBlock, err)})
+	} else {
+		for _, tx := range erc1155Txs {
+			convertedTx, err := opts.converter.ERC1155Tx(tx)
+			if err != nil {
+				skipRecord(opts, "erc1155", tx.Hash, tx, err)
+				continue
+			}
+			txs = append(txs, convertedTx)
+		}
+	}
+
+	return txs, errs
+}
+
+// batchRanges splits startBlock..endBlock into batches of batchSize blocks.
+// Consecutive batches share their boundary block. With newestFirst the
+// batches are returned from the end of the range backwards, so recent
+// activity is exported before older history.
+func batchRanges(startBlock, endBlock, batchSize int64, newestFirst bool) [][2]int64 {
+	var batches [][2]int64
+	for currentStart := startBlock; currentStart < endBlock; currentStart += batchSize {
+		currentEnd := currentStart + batchSize
+		if currentEnd > endBlock {
+			currentEnd = endBlock
+		}
+		batches = append(batches, [2]int64{currentStart, currentEnd})
+	}
+	if newestFirst {
+		for i, j := 0, len(batches)-1; i < j; i, j = i+1, j-1 {
+			batches[i], batches[j] = batches[j], batches[i]
+		}
+	}
+	return batches
+}
+
+// fetchBlockRewards fetches rewards for blocks validated by address as rows
+func fetchBlockRewards(client *api.EtherscanClient, opts runOptions, address string, startBlock, endBlock int64) ([]models.Transaction, error) {
+	fmt.Println("Fetching block rewards...")
+	blocks, err := client.GetAllMinedBlocks(address, startBlock, endBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	var rewards []models.Transaction
+	for _, block := range blocks {
+		model, err := opts.converter.MinedBlock(block, address)
+		if err != nil {
+			skipRecord(opts, "rewards", "", block, err)
+			continue
+		}
+		rewards = append(rewards, model)
+	}
+	return rewards, nil
+}
+
+// skipRecord reports a provider record of type kind that could not be
+// converted, and adds it to the error report of the export
+func skipRecord(opts runOptions, kind, hash string, record interface{}, err error) {
+	logger.Warn("skipped a record that could not be converted", "type", kind, "hash", hash, "error", err)
+	if err := opts.skipped.Record(kind, hash, record, err); err != nil {
+		logger.Error("failed to write error report", "error", err)
+	}
+}
+
+// prepareExport applies the duplicate policy and row filters before writing,
+// and tags the rows of address that match the alert rules. With
+// -running-balance, every fetched row is replayed first, so the fees of rows
+// that are filtered out still count.
+func prepareExport(address string, txs []models.Transaction, opts runOptions) []models.Transaction {
+	if opts.balances != nil {
+		warnShortfalls(address, opts.balances.Apply(address, txs))
+	}
+	filters := opts.filters
+	if opts.direction != "" {
+		// The direction depends on the address, so bulk runs add it per address
+		filters = append(filters[:len(filters):len(filters)], filter.InDirection(address, opts.direction))
+	}
+	txs = filter.Apply(dedupe.Apply(txs, opts.duplicates), filters...)
+	if opts.alerts != nil {
+		if tagged := opts.alerts.Tag(address, txs); tagged > 0 {
+			fmt.Printf("Tagged %d rows matching alert rules\n", tagged)
+		}
+	}
+	return txs
+}
+
+// warnShortfalls points out the blocks after which the running balance of
+// address is below zero, which usually means rows are missing
+func warnShortfalls(address string, shortfalls []report.Shortfall) {
+	if len(shortfalls) == 0 {
+		return
+	}
+	first := shortfalls[0]
+	fmt.Printf("Warning: the running balance of %s is %s ETH after block %d", address, report.FormatAmount(first.Balance), first.Block)
+	if len(shortfalls) > 1 {
+		fmt.Printf(" and below zero after %d more blocks", len(shortfalls)-1)
+	}
+	fmt.Println("; rows such as internal transfers may be missing, or -opening-balance is too low")
+}
+
+// protectOutput encrypts and signs an output file as requested, records the
+// files in the run summary and returns the path of the file to report to
+// the user
+func protectOutput(path string, opts runOptions) string {
+	if opts.encrypter == nil && opts.signer == nil {
+		opts.summary.AddOutput(path)
+		return path
+	}
+	written, err := protect.File(path, opts.encrypter, opts.signer)
+	if err != nil {
+		log.Fatalf("Error protecting output: %v", err)
+	}
+	if len(written) > 1 {
+		fmt.Printf("Signed %s (signature %s)\n", written[0], written[1])
+	}
+	for _, w := range written {
+		opts.summary.AddOutput(w)
+	}
+	return written[0]
+}
+
+// writeExport writes transactions to name in outputDir, to stdout with
+// -output -, or with -split-by to one name_<period> file per month or year
+// that has rows. It returns the written file, or a description of the files
+// for a split export.
+func writeExport(transactions []models.Transaction, outputDir, name string, opts runOptions) (string, error) {
+	if opts.toStdout {
+		return "stdout", export.WriteCSVTo(stdout, transactions, opts.csv)
+	}
+	if opts.splitBy == export.PeriodNone {
+		return writeExportFile(transactions, filepath.Join(outputDir, name+opts.csv.Extension()), opts)
+	}
+
+	keys, groups := opts.splitBy.Split(transactions, opts.location)
+	if len(keys) == 0 {
+		return "no files, there are no transactions to split", nil
+	}
+	var first, last string
+	for i, key := range keys {
+		path, err := writeExportFile(groups[key], filepath.Join(outputDir, fmt.Sprintf("%s_%s%s", name, key, opts.csv.Extension())), opts)
+		if err != nil {
+			return "", err
+		}
+		if i == 0 {
+			first = path
+		}
+		last = path
+	}
+	if len(keys) == 1 {
+		return first, nil
+	}
+	return fmt.Sprintf("%d files, %s to %s", len(keys), first, last), nil
+}
+
+// writeExportFile writes a single export file, merging the rows into it
+// with -append, and returns the path to report. An existing export is only
+// overwritten with -force; otherwise the new one gets a timestamped name.
+func writeExportFile(transactions []models.Transaction, path string, opts runOptions) (string, error) {
+	rows := len(transactions)
+	if opts.appendRows {
+		added, total, err := export.AppendCSV(transactions, path, opts.csv)
+		if err != nil {
+			return "", err
+		}
+		fmt.Printf("Appended %d new rows to %s (%d already present)\n", added, path, len(transactions)-added)
+		rows = total
+	} else {
+		if !opts.force {
+			suffix := ""
+			if opts.encrypter != nil {
+				suffix = opts.encrypter.Extension()
+			}
+			if available := export.AvailablePath(path, suffix, time.Now()); available != path {
+				fmt.Printf("Warning: %s already exists, writing to %s instead (use -force to overwrite)\n", path, available)
+				path = available
+			}
+		}
+		if err := export.WriteCSVWithOptions(transactions, path, opts.csv); err != nil {
+			return "", err
+		}
+	}
+
+	path = protectOutput(path, opts)
+	opts.manifest.AddExport(path, rows)
+	if opts.signer != nil {
+		opts.manifest.AddFile(path + opts.signer.Extension())
+	}
+	return path, nil
+}
+
+// describeManifestRun records the provider, block range and addresses of
+// the run in the manifest, if one was requested
+func describeManifestRun(opts runOptions, provider string, startBlock, endBlock int64, addresses ...string) {
+	if opts.manifest == nil {
+		return
+	}
+	opts.manifest.Provider = provider
+	opts.manifest.StartBlock, opts.manifest.EndBlock = startBlock, endBlock
+	opts.manifest.Addresses = append(opts.manifest.Addresses, addresses...)
+}
+
+// writeManifest writes the manifest of the files the run exported to
+// outputDir, if one was requested
+func writeManifest(outputDir string, opts runOptions) {
+	if opts.manifest == nil {
+		return
+	}
+	path := filepath.Join(outputDir, manifest.FileName)
+	if err := opts.manifest.WriteFile(path); err != nil {
+		log.Fatalf("Error writing manifest: %v", err)
+	}
+	opts.summary.AddOutput(path)
+	fmt.Printf("Recorded the checksums of the exported files in %s\n", path)
+}
+
+// confirmedEndBlock caps endBlock at head minus minConfirmations, so blocks
+// that may still be reorganised never enter the export
+func confirmedEndBlock(startBlock, endBlock, head, minConfirmations int64) int64 {
+	safe := head - minConfirmations
+	if endBlock <= safe {
+		return endBlock
+	}
+	if safe < startBlock {
+		log.Fatalf("Error: no block between %d and %d has %d confirmations yet (latest block is %d).",
+			startBlock, endBlock, minConfirmations, head)
+	}
+	fmt.Printf("Capping end block at %d (latest block %d minus %d confirmations)\n", safe, head, minConfirmations)
+	return safe
+}
+
+// resolveDateRange translates the open or closed sides of r into block
+// numbers, keeping startBlock or endBlock for sides left open
+func resolveDateRange(client *api.EtherscanClient, r daterange.Range, startBlock, endBlock int64) (int64, int64) {
+	var err error
+	if !r.Start.IsZero() {
+		if startBlock, err = client.GetBlockNumberByTime(r.Start, api.ClosestAfter); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+	}
+	if !r.End.IsZero() {
+		if endBlock, err = client.GetBlockNumberByTime(r.End, api.ClosestBefore); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+	}
+	if endBlock < startBlock {
+		log.Fatalf("Error: no blocks were mined in the selected date range.")
+	}
+	fmt.Printf("Resolved date range to blocks %d to %d\n", startBlock, endBlock)
+	return startBlock, endBlock
+}
+
+// finalizedEndBlock caps endBlock at the latest finalized block, exiting
+// when no block of the range is finalized yet
+func finalizedEndBlock(startBlock, endBlock, finalized int64) int64 {
+	if endBlock <= finalized {
+		return endBlock
+	}
+	if finalized < startBlock {
+		log.Fatalf("Error: no block between %d and %d is finalized yet (latest finalized block is %d).",
+			startBlock, endBlock, finalized)
+	}
+	fmt.Printf("Capping end block at the latest finalized block %d\n", finalized)
+	return finalized
+}
+
+// convertERC20Transfers converts token transfers after correcting implausible
+// token decimals, noting each correction on the affected row
+func convertERC20Transfers(opts runOptions, transfers []api.ERC20Transaction) []models.Transaction {
+	notes := make(map[int]string)
+	for _, c := range enrich.GuardTokenDecimals(opts.metadata, opts.converter.Chain, transfers) {
+		logger.Warn(c.Note(), "contract", c.Contract, "hash", c.Hash)
+		notes[c.Index] = c.Note()
+	}
+
+	var result []models.Transaction
+	for i, tx := range transfers {
+		model, err := opts.converter.ERC20Tx(tx)
+		if err != nil {
+			skipRecord(opts, "erc20", tx.Hash, tx, err)
+			continue
+		}
+		model.Notes = notes[i]
+		result = append(result, model)
+	}
+	return result
+}
+
+// enrichTransactions runs the optional enrichment passes over txs in place
+func enrichTransactions(client *api.EtherscanClient, opts runOptions, txs []models.Transaction) {
+	if opts.feeBreakdown {
+		fmt.Println("Fetching receipts for EIP-1559 fee breakdown...")
+		enriched, err := enrich.FeeBreakdown(client, opts.metadata, txs)
+		if err != nil {
+			logger.Warn("fee breakdown incomplete", "error", err)
+		}
+		fmt.Printf("Added fee breakdown to %d transactions\n", enriched)
+	}
+	if opts.l1Fees {
+		fmt.Println("Fetching receipts for L1 data fees...")
+		enriched, err := enrich.L1Fees(client, opts.metadata, txs)
+		if err != nil {
+			logger.Warn("L1 data fees incomplete", "error", err)
+		}
+		fmt.Printf("Added L1 data fees to %d transactions\n", enriched)
+	}
+}
+
+// appendMissingColumns appends the named optional columns that are not already selected
+func appendMissingColumns(columns []models.Column, keys ...string) []models.Column {
+	for _, key := range keys {
+		present := false
+		for _, col := range columns {
+			if col.Key == key {
+				present = true
+				break
+			}
+		}
+		if !present {
+			col, _ := models.LookupColumn(key)
+			columns = append(columns, col)
+		}
+	}
+	return columns
+}
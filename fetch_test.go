@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// validateArgs parses args as the flags of command and validates them
+func validateArgs(t *testing.T, command string, args ...string) error {
+	fs := flag.NewFlagSet(command, flag.ContinueOnError)
+	f := addFetchFlags(fs)
+	if !assert.NoError(t, fs.Parse(args)) {
+		return nil
+	}
+	return validateFetchFlags(f)
+}
+
+func TestValidateFetchFlags(t *testing.T) {
+	const address = "0x1111111111111111111111111111111111111111"
+	tests := []struct {
+		name    string
+		command string
+		args    []string
+		err     string
+	}{
+		{"address", "fetch", []string{"-address", address}, ""},
+		{"no address", "fetch", nil, "-address or -addresses-file is required"},
+		{"address and file", "fetch", []string{"-address", address, "-addresses-file", "wallets.txt"}, "-addresses-file cannot be combined with -address"},
+		{"negative", "fetch", []string{"-address", address, "-deadline", "-1s"}, "-deadline cannot be negative"},
+		{"newest first without batch", "fetch", []string{"-address", address, "-newest-first"}, "-newest-first requires -batch"},
+		{"newest first", "fetch", []string{"-address", address, "-newest-first", "-batch", "1000"}, ""},
+		{"dates and blocks", "fetch", []string{"-address", address, "-last", "30d", "-start", "100"}, "-last cannot be combined with -start"},
+		{"chains", "fetch", []string{"-address", address, "-chains", "ethereum,polygon", "-resume", "-store", "s.json"}, "-chains cannot be combined with -resume or -store"},
+		{"chains with batch 0", "fetch", []string{"-address", address, "-chains", "ethereum,polygon", "-batch", "0"}, ""},
+		{"chain with rpc", "fetch", []string{"-address", address, "-chain", "polygon", "-rpc-url", "http://localhost:8545"}, "-chain cannot be combined with -rpc-url, which reads the chain from the node"},
+		{"preview", "fetch", []string{"-address", address, "-preview", "5", "-manifest"}, "-preview cannot be combined with -manifest"},
+		{"output to stdout", "fetch", []string{"-address", address, "-output", "-", "-batch", "1000"}, "-output - cannot be combined with -batch"},
+		{"summary to stdout", "fetch", []string{"-address", address, "-output", "-", "-summary-json", "-"}, "-output - cannot be combined with -summary-json -"},
+		{"summary to file", "fetch", []string{"-address", address, "-output", "-", "-summary-json", "run.json"}, ""},
+		{"as of run without store", "fetch", []string{"-address", address, "-as-of-run", "2"}, "-as-of-run requires -store"},
+		{"dry run", "fetch", []string{"-address", address, "-dry-run", "-resume"}, "-dry-run cannot be combined with -resume"},
+		{"bigquery", "fetch", []string{"-address", address, "-bigquery-table", "txs", "-sample", "10%"}, "-bigquery-table cannot be combined with -sample"},
+		{"running balance", "fetch", []string{"-address", address, "-running-balance", "-batch", "1000", "-newest-first"}, "-running-balance cannot be combined with -newest-first"},
+		{"encrypted resume", "fetch", []string{"-address", address, "-resume", "-encrypt", "age:age1xyz"}, ""},
+		{"sync without store", "sync", []string{"-address", address}, "sync requires -store"},
+		{"sync", "sync", []string{"-address", address, "-store", "s.json"}, ""},
+		{"sync with preview", "sync", []string{"-address", address, "-store", "s.json", "-preview", "3"}, "-preview cannot be combined with -store"},
+		{"sync with manifest", "sync", []string{"-address", address, "-store", "s.json", "-manifest"}, "sync cannot be combined with -manifest"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateArgs(t, tt.command, tt.args...)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
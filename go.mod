@@ -1,10 +1,15 @@
-module eth-tx-history
+module github.com/haridev22/ct-assignement
 
 go 1.23.2
 
+require (
+	github.com/stretchr/testify v1.11.1
+	golang.org/x/crypto v0.31.0
+	golang.org/x/sys v0.28.0
+)
+
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	github.com/stretchr/testify v1.11.1 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
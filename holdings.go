@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/haridev22/ct-assignement/pkg/api"
+	"github.com/haridev22/ct-assignement/pkg/holdings"
+	"github.com/haridev22/ct-assignement/pkg/rpc"
+)
+
+// defineHoldings defines the holdings subcommand, which reads the current
+// balance of every ERC-20 token in the history of an address, so stale and
+// dust positions show up beside the transfer log
+func defineHoldings(fs *flag.FlagSet) func() {
+	rows := addReportInputFlags(fs)
+	apiKey := fs.String("apikey", "", "Etherscan API key, or a comma-separated list of keys to rotate between (required without -rpc-url)")
+	chainName := addChainFlag(fs)
+	rateLimit := fs.Float64("rate-limit", api.DefaultCallsPerSecond, "Calls per second allowed for each key when several API keys are given")
+	rpcURL := fs.String("rpc-url", "", "JSON-RPC node to read balances from with batched multicalls, instead of one Etherscan call per token")
+	multicall := fs.String("multicall", holdings.DefaultMulticall, "Multicall3 contract used with -rpc-url")
+	format := fs.String("format", "text", "Output format: text or json")
+	logOpts := addLogFlags(fs)
+	transportOpts := addTransportFlags(fs)
+	return func() {
+		logOpts.apply()
+
+		if *format != "text" && *format != "json" {
+			log.Fatalf("Error: unknown -format %q (use text or json).", *format)
+		}
+		address, txs := rows.load("holdings")
+
+		var source holdings.Source
+		if *rpcURL = resolveSecret("-rpc-url", *rpcURL); *rpcURL != "" {
+			node := rpc.NewClient(*rpcURL)
+			node.Logger = logger
+			node.HTTPClient.Transport = transportOpts.roundTripper()
+			source = holdings.Multicall{Node: node, Address: *multicall}
+		} else {
+			*apiKey = resolveAPIKey(*apiKey)
+			if *apiKey == "" {
+				log.Fatal("Error: Etherscan API key is required. Use -apikey flag or set ETHERSCAN_API_KEY environment variable, or read balances from a node with -rpc-url.")
+			}
+			client := newClient(*apiKey, *rateLimit, lookupChain(*chainName))
+			client.HTTPClient.Transport = transportOpts.roundTripper()
+			source = holdings.Etherscan{Client: client}
+		}
+
+		positions, err := holdings.Read(source, address, txs)
+		if err != nil {
+			log.Fatalf("Error reading token balances: %v", err)
+		}
+		if *format == "json" {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(positions); err != nil {
+				log.Fatalf("Error: %v", err)
+			}
+			return
+		}
+		if err := holdings.Write(os.Stdout, address, positions); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/haridev22/ct-assignement/pkg/export"
+	"github.com/haridev22/ct-assignement/pkg/store"
+)
+
+// defineImport defines the import subcommand, which records the rows of
+// existing exports in a store, so a history exported before the store was
+// used does not have to be fetched again
+func defineImport(fs *flag.FlagSet) func() {
+	storePath := fs.String("store", "", "Versioned store file to import into (required)")
+	address := fs.String("address", "", "Address the rows belong to (default: taken from the name of each file)")
+	return func() {
+		inputs := fs.Args()
+		if *storePath == "" || len(inputs) == 0 {
+			log.Fatal("Error: import requires -store and at least one export: import -store FILE [flags] file...")
+		}
+		addresses := make([]string, len(inputs))
+		for i, path := range inputs {
+			addresses[i] = *address
+			if addresses[i] == "" {
+				var ok bool
+				if addresses[i], ok = exportAddress(path); !ok {
+					log.Fatalf("Error: -address is required when a file name does not start with the address, as %s does.", path)
+				}
+			}
+		}
+
+		// Each file is a run, so the rows of a later file supersede those of an
+		// earlier one and reports as of a run can tell the files apart. Nothing
+		// is deleted, as an export may cover only part of the history. The
+		// store is only written once every file was read, so a damaged file
+		// leaves it unchanged.
+		err := store.Update(*storePath, func(s *store.Store) error {
+			for i, path := range inputs {
+				txs, _, err := export.ReadFile(path)
+				if err != nil {
+					return fmt.Errorf("failed to read %s: %w", path, err)
+				}
+				run := s.BeginRun(addresses[i])
+				stats, err := s.Upsert(run, txs)
+				if err != nil {
+					return fmt.Errorf("failed to import %s: %w", path, err)
+				}
+				fmt.Printf("Imported %s into run %d for %s: %d new, %d superseded, %d unchanged\n",
+					path, run.ID, run.Address, stats.Inserted, stats.Superseded, stats.Unchanged)
+			}
+			return nil
+		})
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		fmt.Printf("Saved %s\n", *storePath)
+	}
+}
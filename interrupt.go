@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/checkpoint"
+	"github.com/haridev22/ct-assignement/pkg/export"
+	"github.com/haridev22/ct-assignement/pkg/models"
+)
+
+// errInterrupted is returned by an export stopped by SIGINT, SIGTERM, the
+// -deadline or an exhausted -max-api-calls budget after it saved its partial
+// results
+var errInterrupted = errors.New("export interrupted; rerun with -resume to continue")
+
+// interrupted reports whether the run was asked to stop or ran out of API
+// calls
+func (o runOptions) interrupted() bool {
+	return (o.ctx != nil && o.ctx.Err() != nil) || o.budget.Exhausted()
+}
+
+// loadCheckpoint returns the progress of an export of address, continuing
+// from its checkpoint in outputDir when resuming. The rows already fetched
+// before the interruption are returned with it.
+func loadCheckpoint(address string, startBlock, endBlock int64, outputDir string, opts runOptions) (*checkpoint.Checkpoint, []models.Transaction, error) {
+	cp := &checkpoint.Checkpoint{Address: address, StartBlock: startBlock, EndBlock: endBlock}
+	if !opts.resume {
+		return cp, nil, nil
+	}
+
+	saved, err := checkpoint.Load(checkpoint.Path(outputDir, address))
+	if errors.Is(err, os.ErrNotExist) {
+		fmt.Printf("No checkpoint found for %s, starting from the beginning\n", address)
+		return cp, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if !strings.EqualFold(saved.Address, address) {
+		return nil, nil, fmt.Errorf("checkpoint is for address %s, not %s", saved.Address, address)
+	}
+	cp.Completed = saved.Completed
+
+	var rows []models.Transaction
+	if saved.PartialFile != "" && len(saved.Completed) > 0 {
+		partial, _, err := export.ReadCSVFileWithOptions(saved.PartialFile, partialCSVOptions(opts))
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot read partial results %s: %w", saved.PartialFile, err)
+		}
+		// Rows outside the completed ranges are fetched again
+		for _, tx := range partial {
+			if cp.Covers(tx.BlockNumber) {
+				rows = append(rows, tx)
+			}
+		}
+	}
+	fmt.Printf("Resuming from checkpoint: %d block ranges and %d transactions already fetched\n",
+		len(cp.Completed), len(rows))
+	return cp, rows, nil
+}
+
+// saveCheckpoint flushes the rows fetched before an interruption to a
+// partial CSV and records cp next to it, then returns errInterrupted
+func saveCheckpoint(cp *checkpoint.Checkpoint, txs []models.Transaction, outputDir string, opts runOptions) error {
+	if opts.budget.Exhausted() {
+		fmt.Printf("\nAPI call budget of %d calls used up, saving partial results...\n", opts.budget.Max())
+	} else if opts.ctx != nil && errors.Is(opts.ctx.Err(), context.DeadlineExceeded) {
+		fmt.Println("\nDeadline reached, saving partial results...")
+	} else {
+		fmt.Println("\nInterrupted, saving partial results...")
+	}
+	if !opts.storeOnly {
+		partialPath := filepath.Join(outputDir, fmt.Sprintf("%s_tx_history_partial.csv", cp.Address))
+		if err := writePartial(txs, partialPath, opts); err != nil {
+			return fmt.Errorf("error saving partial results: %w", err)
+		}
+		// The partial results are only read back by -resume, so -encrypt and
+		// -sign apply to the export that completes the run instead
+		cp.PartialFile = partialPath
+		opts.summary.AddOutput(partialPath)
+		opts.summary.CountRows(txs)
+		fmt.Printf("Saved %d transactions fetched before the interruption to %s\n", len(txs), cp.PartialFile)
+		if opts.encrypter != nil {
+			fmt.Printf("Warning: %s is not encrypted; it is removed once a run with -resume completes the export, so delete it if the export is not resumed\n", partialPath)
+		}
+	}
+
+	cp.InterruptedAt = time.Now().UTC()
+	path := checkpoint.Path(outputDir, cp.Address)
+	if err := cp.Save(path); err != nil {
+		return err
+	}
+	fmt.Printf("Recorded checkpoint in %s\n", path)
+	return errInterrupted
+}
+
+// writePartial writes the partial results of an interrupted export to
+// path. Only the owner can read the file, as it holds the rows in plaintext
+// even when the finished export is encrypted.
+func writePartial(txs []models.Transaction, path string, opts runOptions) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	// A partial file left by an earlier interruption keeps its mode otherwise
+	if err := file.Chmod(0600); err != nil {
+		return err
+	}
+	return export.WriteCSVTo(file, txs, partialCSVOptions(opts))
+}
+
+// partialCSVOptions lays out the partial results of an interrupted export
+// as a plain CSV. Every registered column is written after the selected
+// ones, so a resumed run restores complete rows whatever -columns selected.
+func partialCSVOptions(opts runOptions) export.CSVOptions {
+	partial := export.CSVOptions{Columns: append([]models.Column{}, opts.csv.Columns...)}
+	for _, col := range models.Schema() {
+		partial.Columns = appendMissingColumns(partial.Columns, col.Key)
+	}
+	return partial
+}
+
+// clearCheckpoint removes the checkpoint of a finished export and the
+// partial results it pointed to, with any signature of them
+func clearCheckpoint(address, outputDir string) {
+	path := checkpoint.Path(outputDir, address)
+	saved, err := checkpoint.Load(path)
+	if err != nil {
+		return
+	}
+	if saved.PartialFile != "" {
+		os.Remove(saved.PartialFile)
+		// Partial results written before -sign stopped applying to them
+		// have a detached signature next to them
+		os.Remove(saved.PartialFile + ".sig")
+	}
+	os.Remove(path)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/checkpoint"
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// base64Encrypter stands in for a real encrypter: what it writes does not
+// parse as CSV
+type base64Encrypter struct{}
+
+func (base64Encrypter) Extension() string { return ".b64" }
+
+func (base64Encrypter) Encrypt(dst io.Writer, src io.Reader) error {
+	enc := base64.NewEncoder(base64.StdEncoding, dst)
+	if _, err := io.Copy(enc, src); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+func TestResumeWithEncrypter(t *testing.T) {
+	dir := t.TempDir()
+	address := "0x1234567890abcdef1234567890abcdef12345678"
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	opts := runOptions{ctx: ctx, encrypter: base64Encrypter{}}
+
+	txs := []models.Transaction{
+		{Hash: "0x1", BlockNumber: 10, Timestamp: time.Unix(1700000000, 0).UTC(), From: address, Type: models.TypeEthTransfer, Value: "1.5"},
+		{Hash: "0x2", BlockNumber: 30, Timestamp: time.Unix(1700000100, 0).UTC(), From: address, Type: models.TypeEthTransfer, Value: "2"},
+	}
+	cp := &checkpoint.Checkpoint{Address: address, StartBlock: 0, EndBlock: 100}
+	cp.Completed = [][2]int64{{0, 20}}
+	err := saveCheckpoint(cp, txs, dir, opts)
+	assert.ErrorIs(t, err, errInterrupted)
+
+	info, err := os.Stat(filepath.Join(dir, address+"_tx_history_partial.csv"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), "the plaintext partial results are only readable by the owner")
+	}
+
+	opts.resume = true
+	resumed, rows, err := loadCheckpoint(address, 0, 100, dir, opts)
+	assert.NoError(t, err, "the partial results are read back in plaintext")
+	if assert.NotNil(t, resumed) {
+		assert.Equal(t, cp.Completed, resumed.Completed)
+	}
+	if assert.Len(t, rows, 1, "rows outside the completed ranges are fetched again") {
+		assert.Equal(t, "0x1", rows[0].Hash)
+		assert.Equal(t, "1.5", rows[0].Value)
+	}
+
+	clearCheckpoint(address, dir)
+	entries, _ := os.ReadDir(dir)
+	assert.Empty(t, entries)
+}
@@ -1,295 +1,611 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
+	"log/slog"
+	"math/big"
+	"net/http"
 	"os"
-	"path/filepath"
-	"sync"
-
-	"eth-tx-history/pkg/api"
-	"eth-tx-history/pkg/models"
-	"eth-tx-history/pkg/utils"
+	"strings"
+	"time"
+	// Embedded so -timezone works on hosts without a timezone database
+	_ "time/tzdata"
+
+	"github.com/haridev22/ct-assignement/pkg/alert"
+	"github.com/haridev22/ct-assignement/pkg/api"
+	"github.com/haridev22/ct-assignement/pkg/audit"
+	"github.com/haridev22/ct-assignement/pkg/cache"
+	"github.com/haridev22/ct-assignement/pkg/chains"
+	"github.com/haridev22/ct-assignement/pkg/dedupe"
+	"github.com/haridev22/ct-assignement/pkg/envflags"
+	"github.com/haridev22/ct-assignement/pkg/export"
+	"github.com/haridev22/ct-assignement/pkg/filter"
+	"github.com/haridev22/ct-assignement/pkg/manifest"
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/haridev22/ct-assignement/pkg/protect"
+	"github.com/haridev22/ct-assignement/pkg/report"
+	"github.com/haridev22/ct-assignement/pkg/runsummary"
+	"github.com/haridev22/ct-assignement/pkg/secrets"
+	"github.com/haridev22/ct-assignement/pkg/skipped"
+	"github.com/haridev22/ct-assignement/pkg/transport"
+	"github.com/haridev22/ct-assignement/pkg/vcr"
+	"github.com/haridev22/ct-assignement/pkg/version"
 )
 
 const (
 	// Default values
 	defaultOutputDir      = "./output"
 	defaultStartBlock     = 0
-	defaultEndBlock       = 999999999 // to get all transactions
+	defaultEndBlock       = 999999999 // open-ended; capped at the latest block at startup
 	maxConcurrentRequests = 4         // concurrent API requests
 )
 
-func main() {
-	//command line flags
-	address := flag.String("address", "", "Ethereum wallet address to fetch transactions for (required)")
-	apiKey := flag.String("apikey", "", "Etherscan API key (required)")
-	outputDir := flag.String("output", defaultOutputDir, "Directory to save CSV output")
-	startBlock := flag.Int64("start", defaultStartBlock, "Starting block number")
-	endBlock := flag.Int64("end", defaultEndBlock, "Ending block number")
-	batchBlocks := flag.Int64("batch", 0, "Process in smaller block ranges (e.g., 100000 blocks at a time)")
+// logger receives the diagnostics of the library packages, such as retried
+// requests. It writes to stderr so they stay apart from progress and rows.
+var logger = slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+// runOptions holds the settings shared by the single-pass and batch modes
+type runOptions struct {
+	csv          export.CSVOptions
+	filters      []filter.Func
+	storePath    string
+	feeBreakdown bool
+	l1Fees       bool
+	// alerts tags exported rows with the rules they match
+	alerts alert.Rules
+	// direction keeps the rows on one side of the exported address
+	direction filter.Direction
+	// bigquery receives the rows of each run when set
+	bigquery     *bigQuerySink
+	blockRewards bool
+	duplicates   dedupe.Policy
+	metadata     *cache.Cache
+	encrypter    protect.Encrypter
+	signer       protect.Signer
+	// minConfirmations caps the end block at the chain head minus this depth
+	minConfirmations int64
+	// audit records every provider call of the run when set
+	audit *audit.Log
+	// newestFirst processes batches from the end of the range backwards
+	newestFirst bool
+	// finalized caps the end block at the latest finalized block
+	finalized bool
+	// storeOnly records fetched rows in the store without writing exports
+	storeOnly bool
+	// ctx is cancelled by SIGINT or SIGTERM, or once the -deadline passes
+	ctx context.Context
+	// httpTimeout, when positive, bounds each HTTP request
+	httpTimeout time.Duration
+	// transport, when set, replaces the default HTTP transport of provider clients
+	transport http.RoundTripper
+	// resume continues an interrupted export from its checkpoint
+	resume bool
+	// progress replaces the per-page progress lines with a live display
+	progress bool
+	// summary collects the facts of the run for -summary-json
+	summary *runsummary.Summary
+	// continueOnError exports the transaction types that were fetched when
+	// others fail, instead of failing the export
+	continueOnError bool
+	// converter converts provider records to rows, strictly with -strict
+	converter api.Converter
+	// skipped reports the records of the export that could not be converted
+	skipped *skipped.Log
+	// budget, when set, stops the run like an interruption once its API
+	// calls are used up
+	budget *api.CallBudget
+	// splitBy writes one export file per month or year instead of one file
+	splitBy export.Period
+	// location is the timezone of exported timestamps, which also decides
+	// the period a row is split into
+	location *time.Location
+	// toStdout writes the export to stdout instead of the output directory
+	toStdout bool
+	// appendRows merges the rows into an existing export instead of
+	// replacing it
+	appendRows bool
+	// force overwrites an existing export instead of writing the new one
+	// under a timestamped name
+	force bool
+	// manifest collects the exported files for manifest.json when set
+	manifest *manifest.Manifest
+	// archiveRaw saves the raw provider responses next to the export
+	archiveRaw bool
+	// balances, when set, replays the native balance of the exported rows
+	// into a Running Balance column
+	balances *report.Balances
+	// preview, when positive, prints the first and last preview rows as a
+	// table instead of writing the export
+	preview int
+}
 
-	flag.Parse()
+// exportFlags are the flags that shape an exported file, shared by the
+// commands that write exports
+type exportFlags struct {
+	columns       *string
+	extraColumns  *string
+	onlyFailed    *bool
+	excludeFailed *bool
+	minValue      *string
+	maxValue      *string
+	direction     *string
+	duplicates    *string
+	encrypt       *string
+	sign          *string
+	timezone      *string
+	timeFormat    *string
+	delimiter     *string
+	quoteAll      *bool
+	noHeader      *bool
+	excelCompat   *bool
+	splitBy       *string
+	appendRows    *bool
+	force         *bool
+	manifest      *bool
+	alertRules    *string
+	balance       *bool
+	opening       *string
+}
 
-	if *address == "" {
-		log.Fatal("Error: Ethereum wallet address is required. Use -address flag.")
+func addExportFlags(fs *flag.FlagSet) *exportFlags {
+	return &exportFlags{
+		columns:       fs.String("columns", "", "Comma-separated columns to write, in this order, instead of the default ones (e.g. hash,timestamp,from,to,value)"),
+		extraColumns:  fs.String("extra-columns", "", "Comma-separated optional columns to append (nonce,gas_limit,tx_index)"),
+		onlyFailed:    fs.Bool("only-failed", false, "Export only failed (reverted) transactions"),
+		excludeFailed: fs.Bool("exclude-failed", false, "Leave failed (reverted) transactions out of the export"),
+		minValue:      fs.String("min-value", "", "Leave out rows moving less than this amount of their asset, such as dust transfers (e.g. 0.001)"),
+		maxValue:      fs.String("max-value", "", "Leave out rows moving more than this amount of their asset"),
+		direction:     fs.String("direction", "", "Export only the rows on one side of the address: in, out or self"),
+		duplicates:    fs.String("duplicates", string(dedupe.KeepAll), "Policy for rows sharing a hash: keep-all, prefer-token-rows or collapse-to-one"),
+		encrypt:       fs.String("encrypt", "", "Encrypt output files with a provider: age:RECIPIENT, gpg:RECIPIENT, vault:KEY or kms:KEY"),
+		sign:          fs.String("sign", "", "Write detached signatures of output files with a provider: gpg:KEYID, vault:KEY or kms:KEY"),
+		timezone:      fs.String("timezone", "", "Timezone of exported timestamps, such as UTC or Europe/Berlin (default: the local timezone)"),
+		delimiter:     fs.String("delimiter", ",", "Field delimiter: a single character, or tab for TSV"),
+		quoteAll:      fs.Bool("quote-all", false, "Quote every field instead of only those that need it"),
+		noHeader:      fs.Bool("no-header", false, "Leave out the header row"),
+		excelCompat:   fs.Bool("excel-compat", false, "Write a UTF-8 BOM and CRLF line endings, and keep long numbers as text so Excel opens the file without changing them"),
+		manifest:      fs.Bool("manifest", false, "Write a manifest.json listing the exported files with their SHA-256 checksums, row counts, block range, provider and tool version"),
+		force:         fs.Bool("force", false, "Overwrite an existing export instead of writing the new one under a timestamped name"),
+		appendRows:    fs.Bool("append", false, "Merge new rows into an existing export file, skipping rows it already contains, instead of replacing it"),
+		splitBy:       fs.String("split-by", "", "Write one file per period instead of one file: month or year"),
+		alertRules:    fs.String("alert-rules", "", "JSON file of alert rules; rows matching a rule are named in an Alert column"),
+		balance:       fs.Bool("running-balance", false, "Sort rows by block and add a Running Balance column replaying the ETH each row moves and the fees paid"),
+		opening:       fs.String("opening-balance", "0", "ETH balance before the first exported block, with -running-balance"),
+		timeFormat:    fs.String("time-format", "rfc3339", "Format of exported timestamps: rfc3339, datetime, date or a Go layout such as \"02.01.2006 15:04\""),
 	}
+}
 
-	// TODO: get api key from environment variable
-	if *apiKey == "" {
-		log.Fatal("Error: Etherscan API key is required. Use -apikey flag or set ETHERSCAN_API_KEY environment variable.")
+// options validates the flags and returns the run options they select,
+// exiting on invalid values
+func (f *exportFlags) options() runOptions {
+	base := models.DefaultColumns
+	if *f.columns != "" {
+		selected, err := models.ParseColumnKeys(*f.columns)
+		if err != nil {
+			log.Fatalf("Error: invalid -columns: %v", err)
+		}
+		if len(selected) == 0 {
+			log.Fatal("Error: -columns selects no columns.")
+		}
+		base = selected
 	}
-
-	client := api.NewEtherscanClient(*apiKey)
-
-	fmt.Printf("Fetching transactions for address: %s\n", *address)
-	fmt.Printf("Block range: %d to %d\n", *startBlock, *endBlock)
-
-	// iif batch size specifiedthen process in batches
-	if *batchBlocks > 0 {
-		processInBatches(client, *address, *startBlock, *endBlock, *batchBlocks, *outputDir)
-		return
+	extra, err := models.ParseColumnKeys(*f.extraColumns)
+	if err != nil {
+		log.Fatalf("Error: invalid -extra-columns: %v", err)
+	}
+	columns := append(append([]models.Column{}, base...), extra...)
+	seen := make(map[string]bool)
+	for _, col := range columns {
+		if seen[col.Key] {
+			log.Fatalf("Error: column %s is selected twice.", col.Key)
+		}
+		seen[col.Key] = true
+	}
+	if *f.onlyFailed && *f.excludeFailed {
+		log.Fatal("Error: -only-failed and -exclude-failed cannot be used together.")
+	}
+	duplicatePolicy, err := dedupe.ParsePolicy(*f.duplicates)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
 	}
 
-	var wg sync.WaitGroup
-	wg.Add(4) // four transaction types
-
-	// channel for transactions
-	normalTxCh := make(chan []api.NormalTransaction, 1)
-	internalTxCh := make(chan []api.InternalTransaction, 1)
-	erc20TxCh := make(chan []api.ERC20Transaction, 1)
-	erc721TxCh := make(chan []api.ERC721Transaction, 1)
-	errorCh := make(chan error, 4)
-
-	// Fetch normal ETH transactions with pagination
-	go func() {
-		defer wg.Done()
-		fmt.Println("Starting to fetch normal ETH transactions...")
-		txs, err := client.GetAllNormalTransactions(*address, *startBlock, *endBlock)
-		if err != nil {
-			errorCh <- fmt.Errorf("error fetching normal transactions: %w", err)
-			normalTxCh <- nil
-			return
+	opts := runOptions{
+		csv:        export.CSVOptions{Columns: columns, QuoteAll: *f.quoteAll, NoHeader: *f.noHeader, Excel: *f.excelCompat},
+		duplicates: duplicatePolicy,
+		metadata:   cache.New(),
+	}
+	if opts.csv.Delimiter, err = export.ParseDelimiter(*f.delimiter); err != nil {
+		log.Fatalf("Error: invalid -delimiter: %v", err)
+	}
+	loc := time.Local
+	if *f.timezone != "" {
+		if loc, err = time.LoadLocation(*f.timezone); err != nil {
+			log.Fatalf("Error: invalid -timezone: %v", err)
 		}
-		normalTxCh <- txs
-	}()
-
-	// Fetch internal transactions with pagination
-	go func() {
-		defer wg.Done()
-		fmt.Println("Starting to fetch internal transactions...")
-		txs, err := client.GetAllInternalTransactions(*address, *startBlock, *endBlock)
-		if err != nil {
-			errorCh <- fmt.Errorf("error fetching internal transactions: %w", err)
-			internalTxCh <- nil
-			return
+	}
+	layout, err := models.ParseTimeFormat(*f.timeFormat)
+	if err != nil {
+		log.Fatalf("Error: invalid -time-format: %v", err)
+	}
+	opts.location = loc
+	if opts.splitBy, err = export.ParsePeriod(*f.splitBy); err != nil {
+		log.Fatalf("Error: invalid -split-by: %v", err)
+	}
+	for i, col := range opts.csv.Columns {
+		if col.Key == "timestamp" {
+			opts.csv.Columns[i] = models.TimestampColumn(loc, layout)
 		}
-		internalTxCh <- txs
-	}()
-
-	// Fetch ERC-20 token transfers with pagination
-	go func() {
-		defer wg.Done()
-		fmt.Println("Starting to fetch ERC-20 token transfers...")
-		txs, err := client.GetAllERC20Transfers(*address, *startBlock, *endBlock)
-		if err != nil {
-			errorCh <- fmt.Errorf("error fetching ERC-20 transfers: %w", err)
-			erc20TxCh <- nil
-			return
+	}
+	if *f.onlyFailed {
+		opts.filters = append(opts.filters, filter.OnlyFailed())
+	}
+	if *f.excludeFailed {
+		opts.filters = append(opts.filters, filter.ExcludeFailed())
+	}
+	if *f.minValue != "" || *f.maxValue != "" {
+		minValue := parseAmountFlag("min-value", *f.minValue)
+		maxValue := parseAmountFlag("max-value", *f.maxValue)
+		if minValue != nil && maxValue != nil && minValue.Cmp(maxValue) > 0 {
+			log.Fatal("Error: -min-value is greater than -max-value.")
 		}
-		erc20TxCh <- txs
-	}()
-
-	// Fetch ERC-721 NFT transfers with pagination
-	go func() {
-		defer wg.Done()
-		fmt.Println("Starting to fetch ERC-721 NFT transfers...")
-		txs, err := client.GetAllERC721Transfers(*address, *startBlock, *endBlock)
-		if err != nil {
-			errorCh <- fmt.Errorf("error fetching ERC-721 transfers: %w", err)
-			erc721TxCh <- nil
-			return
+		opts.filters = append(opts.filters, filter.Value(minValue, maxValue))
+	}
+	if *f.direction != "" {
+		if opts.direction, err = filter.ParseDirection(*f.direction); err != nil {
+			log.Fatalf("Error: invalid -direction: %v", err)
+		}
+	}
+	opts.appendRows = *f.appendRows
+	opts.force = *f.force
+	if *f.manifest {
+		opts.manifest = manifest.New(version.Version)
+	}
+	if opts.appendRows && (*f.encrypt != "" || *f.sign != "") {
+		log.Fatal("Error: -append cannot be combined with -encrypt or -sign.")
+	}
+	if *f.encrypt != "" {
+		if opts.encrypter, err = protect.ParseEncrypter(*f.encrypt); err != nil {
+			log.Fatalf("Error: invalid -encrypt: %v", err)
+		}
+	}
+	if *f.sign != "" {
+		if opts.signer, err = protect.ParseSigner(*f.sign); err != nil {
+			log.Fatalf("Error: invalid -sign: %v", err)
 		}
-		erc721TxCh <- txs
-	}()
+	}
+	if *f.alertRules != "" {
+		if opts.alerts, err = alert.Load(*f.alertRules); err != nil {
+			log.Fatalf("Error: invalid -alert-rules: %v", err)
+		}
+		opts.csv.Columns = appendMissingColumns(opts.csv.Columns, "alert")
+	}
+	if *f.balance {
+		opening, ok := new(big.Rat).SetString(*f.opening)
+		if !ok {
+			log.Fatalf("Error: invalid -opening-balance %q.", *f.opening)
+		}
+		opts.balances = report.NewBalances(opening)
+		opts.csv.Columns = appendMissingColumns(opts.csv.Columns, "balance")
+	}
+	return opts
+}
+
+// parseAmountFlag parses the decimal amount given to flag name, returning
+// nil when it is empty and exiting when it is invalid
+func parseAmountFlag(name, value string) *big.Rat {
+	if value == "" {
+		return nil
+	}
+	amount, ok := new(big.Rat).SetString(value)
+	if !ok || amount.Sign() < 0 {
+		log.Fatalf("Error: invalid -%s %q (use a non-negative amount such as 0.001).", name, value)
+	}
+	return amount
+}
 
-	// Wait for all goroutines to complete
-	wg.Wait()
+// logFlags are the flags that control diagnostics, shared by the commands
+// that call a provider
+type logFlags struct {
+	quiet       *bool
+	verbose     *bool
+	veryVerbose *bool
+	format      *string
+}
 
-	// Check for errors
-	select {
-	case err := <-errorCh:
-		log.Fatalf("Error: %v", err)
-	default:
-		// No errors
+func addLogFlags(fs *flag.FlagSet) *logFlags {
+	return &logFlags{
+		quiet:       fs.Bool("q", false, "Only print errors"),
+		verbose:     fs.Bool("v", false, "Also log every page and block window fetched"),
+		veryVerbose: fs.Bool("vv", false, "Also log every request made to the provider, with its latency"),
+		format:      fs.String("log-format", "text", "Format of the logs written to stderr: text or json"),
 	}
+}
 
-	// Convert all transactions to a common model
-	var allTxs []models.Transaction
+// apply configures logger from the flags, exiting on invalid values
+func (f *logFlags) apply() {
+	level := slog.LevelInfo
+	switch {
+	case *f.quiet && (*f.verbose || *f.veryVerbose):
+		log.Fatal("Error: -q cannot be combined with -v or -vv.")
+	case *f.quiet:
+		level = slog.LevelError
+	case *f.veryVerbose:
+		level = api.LevelTrace
+	case *f.verbose:
+		level = slog.LevelDebug
+	}
 
-	// normal transactions
-	normalTxs := <-normalTxCh
-	for _, tx := range normalTxs {
-		model, err := api.ConvertNormalTxToModel(tx)
-		if err != nil {
-			log.Printf("Warning: Failed to process normal transaction %s: %v", tx.Hash, err)
-			continue
-		}
-		allTxs = append(allTxs, model)
+	handlerOpts := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	switch *f.format {
+	case "text":
+		handler = slog.NewTextHandler(os.Stderr, handlerOpts)
+	case "json":
+		handler = slog.NewJSONHandler(os.Stderr, handlerOpts)
+		// Fatal errors become JSON records too, so every stderr line parses
+		log.SetFlags(0)
+		log.SetOutput(slog.NewLogLogger(handler, slog.LevelError).Writer())
+	default:
+		log.Fatalf("Error: invalid -log-format %q (use text or json)", *f.format)
 	}
+	logger = slog.New(handler)
+}
 
-	// internal transactions
-	internalTxs := <-internalTxCh
-	for _, tx := range internalTxs {
-		model, err := api.ConvertInternalTxToModel(tx)
-		if err != nil {
-			log.Printf("Warning: Failed to process internal transaction %s: %v", tx.Hash, err)
-			continue
-		}
-		allTxs = append(allTxs, model)
+// transportFlags holds the proxy and TLS flags of the commands that call a
+// provider
+type transportFlags struct {
+	proxy         *string
+	headers       headerFlags
+	caBundle      *string
+	clientCert    *string
+	clientKey     *string
+	tlsMinVersion *string
+	insecure      *bool
+	record        *string
+	replay        *string
+}
+
+func addTransportFlags(fs *flag.FlagSet) *transportFlags {
+	f := &transportFlags{
+		proxy:         fs.String("proxy", "", "HTTP, HTTPS or SOCKS5 proxy URL for provider requests (default: HTTP_PROXY/HTTPS_PROXY)"),
+		headers:       headerFlags{},
+		caBundle:      fs.String("ca-bundle", "", "PEM file of extra CA certificates to trust, e.g. for a TLS-intercepting proxy"),
+		clientCert:    fs.String("client-cert", "", "PEM client certificate for mutual TLS (requires -client-key)"),
+		clientKey:     fs.String("client-key", "", "PEM private key of -client-cert"),
+		tlsMinVersion: fs.String("tls-min-version", "", "Minimum TLS version: 1.2 or 1.3"),
+		insecure:      fs.Bool("insecure-skip-verify", false, "Do not verify the provider's TLS certificate (unsafe)"),
+		record:        fs.String("record", "", "Record every provider response to fixture files in this directory"),
+		replay:        fs.String("replay", "", "Answer provider requests from fixtures recorded with -record, without network access"),
 	}
+	fs.Var(f.headers, "header", "Extra \"Name: value\" header sent with every provider request (repeatable)")
+	return f
+}
 
-	// ERC20 transactions
-	erc20Txs := <-erc20TxCh
-	for _, tx := range erc20Txs {
-		model, err := api.ConvertERC20TxToModel(tx)
-		if err != nil {
-			log.Printf("Warning: Failed to process ERC20 transaction %s: %v", tx.Hash, err)
-			continue
-		}
-		allTxs = append(allTxs, model)
+// roundTripper builds the transport described by the flags, or returns nil
+// to keep the default transport, exiting on invalid values
+func (f *transportFlags) roundTripper() http.RoundTripper {
+	switch {
+	case *f.record != "" && *f.replay != "":
+		log.Fatal("Error: -record and -replay cannot be used together.")
+	case *f.replay != "":
+		logger.Info("replaying provider responses", "dir", *f.replay)
+		return &vcr.Replayer{Dir: *f.replay}
+	case *f.record != "":
+		logger.Info("recording provider responses", "dir", *f.record)
+		return &vcr.Recorder{Dir: *f.record, Base: f.networkRoundTripper()}
 	}
+	return f.networkRoundTripper()
+}
 
-	// ERC721 transactions
-	erc721Txs := <-erc721TxCh
-	for _, tx := range erc721Txs {
-		model, err := api.ConvertERC721TxToModel(tx)
-		if err != nil {
-			log.Printf("Warning: Failed to process ERC721 transaction %s: %v", tx.Hash, err)
-			continue
-		}
-		allTxs = append(allTxs, model)
+// networkRoundTripper builds the proxy and TLS transport described by the
+// flags, or returns nil to keep the default transport
+func (f *transportFlags) networkRoundTripper() http.RoundTripper {
+	config := transport.Config{
+		Proxy:              *f.proxy,
+		Headers:            http.Header(f.headers),
+		CAFile:             *f.caBundle,
+		CertFile:           *f.clientCert,
+		KeyFile:            *f.clientKey,
+		MinTLSVersion:      *f.tlsMinVersion,
+		InsecureSkipVerify: *f.insecure,
 	}
+	if config.IsZero() {
+		return nil
+	}
+	rt, err := config.RoundTripper()
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	if config.InsecureSkipVerify {
+		logger.Warn("TLS certificate verification is disabled")
+	}
+	return rt
+}
 
-	fmt.Printf("Total transactions processed: %d\n", len(allTxs))
+// headerFlags collects repeated -header flags
+type headerFlags http.Header
 
-	// Export to CSV
-	fmt.Printf("Total transactions: %d\n", len(allTxs))
+func (h headerFlags) String() string {
+	return ""
+}
 
-	// Create output directory if it doesn't exist
-	if err := os.MkdirAll(*outputDir, 0755); err != nil {
-		log.Fatalf("Error creating output directory: %v", err)
+func (h headerFlags) Set(s string) error {
+	name, value, err := transport.ParseHeader(s)
+	if err != nil {
+		return err
 	}
+	http.Header(h).Add(name, value)
+	return nil
+}
 
-	// Export to CSV
-	filePath := filepath.Join(*outputDir, fmt.Sprintf("%s_tx_history.csv", *address))
-	if err := utils.ExportTransactionsToCSV(allTxs, filePath); err != nil {
-		log.Fatalf("Error exporting to CSV: %v", err)
-	}
+// stdout is the process's standard output, kept for results that must be
+// printed even when -q discards the status lines
+var stdout = os.Stdout
 
-	fmt.Printf("Exported transaction history to %s\n", filePath)
+// discardStdout drops the status lines printed to stdout, for -q
+func discardStdout() {
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	os.Stdout = devNull
 }
 
-// processInBatches processes transactions in smaller block ranges to avoid memory issues
-func processInBatches(client *api.EtherscanClient, address string, startBlock, endBlock, batchSize int64, outputDir string) {
-	var allTxs []models.Transaction
-	var processedBlocks int64
-	totalBlocks := endBlock - startBlock
-
-	// Process in batches
-	for currentStart := startBlock; currentStart < endBlock; currentStart += batchSize {
-		currentEnd := currentStart + batchSize
-		if currentEnd > endBlock {
-			currentEnd = endBlock
-		}
+// addChainFlag registers -chain, which selects the network to fetch from
+func addChainFlag(fs *flag.FlagSet) *string {
+	return fs.String("chain", chains.Ethereum.Name, "Network to fetch from: "+strings.Join(chains.Names(), ", "))
+}
 
-		fmt.Printf("\n=== Processing blocks %d to %d (%d%% complete) ===\n",
-			currentStart, currentEnd, int(float64(processedBlocks)/float64(totalBlocks)*100))
+// lookupChain resolves a -chain value, exiting on unknown names
+func lookupChain(name string) chains.Chain {
+	chain, err := chains.Lookup(name)
+	if err != nil {
+		log.Fatalf("Error: invalid -chain: %v", err)
+	}
+	return chain
+}
 
-		// Process each transaction type
-		var batchTxs []models.Transaction
+// converterFor returns the converter of rows fetched from chain. Rows moving
+// the native currency of other chains carry its symbol, while Ethereum rows
+// keep the empty symbol of earlier exports.
+func converterFor(chain chains.Chain) api.Converter {
+	converter := api.Converter{Chain: chain.Name}
+	if chain.Name != chains.Ethereum.Name {
+		converter.NativeSymbol = chain.NativeSymbol
+	}
+	return converter
+}
 
-		// Normal transactions
-		fmt.Println("Fetching normal transactions for batch...")
-		normalTxs, err := client.GetAllNormalTransactions(address, currentStart, currentEnd)
-		if err != nil {
-			fmt.Printf("Warning: Error fetching normal transactions for block range %d-%d: %v\n",
-				currentStart, currentEnd, err)
-		} else {
-			for _, tx := range normalTxs {
-				convertedTx, err := api.ConvertNormalTxToModel(tx)
-				if err == nil {
-					batchTxs = append(batchTxs, convertedTx)
-				}
-			}
-		}
+// exportToStdout handles -output -, which streams the export to stdout so
+// it can be piped. Status lines move to stderr, and the files kept next to
+// an export, such as checkpoints and the error report, go to the default
+// output directory.
+func exportToStdout(outputDir *string, opts *runOptions) {
+	if *outputDir != "-" {
+		return
+	}
+	if opts.splitBy != export.PeriodNone || opts.encrypter != nil || opts.signer != nil || opts.appendRows || opts.manifest != nil {
+		log.Fatal("Error: -output - cannot be combined with -split-by, -encrypt, -sign, -append or -manifest.")
+	}
+	opts.toStdout = true
+	*outputDir = defaultOutputDir
+	if os.Stdout == stdout {
+		os.Stdout = os.Stderr
+	}
+}
 
-		// Internal transactions
-		fmt.Println("Fetching internal transactions for batch...")
-		internalTxs, err := client.GetAllInternalTransactions(address, currentStart, currentEnd)
-		if err != nil {
-			fmt.Printf("Warning: Error fetching internal transactions for block range %d-%d: %v\n",
-				currentStart, currentEnd, err)
-		} else {
-			for _, tx := range internalTxs {
-				convertedTx, err := api.ConvertInternalTxToModel(tx)
-				if err == nil {
-					batchTxs = append(batchTxs, convertedTx)
-				}
-			}
-		}
+func main() {
+	args := os.Args[1:]
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		// Invocations without a subcommand predate them and mean fetch
+		commands[0].run(args)
+		return
+	}
 
-		// ERC20 transfers
-		fmt.Println("Fetching ERC20 transfers for batch...")
-		erc20Txs, err := client.GetAllERC20Transfers(address, currentStart, currentEnd)
-		if err != nil {
-			fmt.Printf("Warning: Error fetching ERC20 transfers for block range %d-%d: %v\n",
-				currentStart, currentEnd, err)
-		} else {
-			for _, tx := range erc20Txs {
-				convertedTx, err := api.ConvertERC20TxToModel(tx)
-				if err == nil {
-					batchTxs = append(batchTxs, convertedTx)
-				}
-			}
-		}
+	switch args[0] {
+	case "help":
+		runHelp(args[1:])
+		return
+	case completeCommand:
+		runComplete(args[1:])
+		return
+	}
+	cmd, ok := lookupCommand(args[0])
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n\n", args[0])
+		printUsage(os.Stderr)
+		os.Exit(2)
+	}
+	cmd.run(args[1:])
+}
 
-		// ERC721 transfers
-		fmt.Println("Fetching ERC721 transfers for batch...")
-		erc721Txs, err := client.GetAllERC721Transfers(address, currentStart, currentEnd)
-		if err != nil {
-			fmt.Printf("Warning: Error fetching ERC721 transfers for block range %d-%d: %v\n",
-				currentStart, currentEnd, err)
-		} else {
-			for _, tx := range erc721Txs {
-				convertedTx, err := api.ConvertERC721TxToModel(tx)
-				if err == nil {
-					batchTxs = append(batchTxs, convertedTx)
-				}
-			}
-		}
+// parseFlags parses args into fs, after setting flags from their
+// ETH_TX_HISTORY_* environment variables so the command line wins. The
+// usage printed by -h names the variable of each flag.
+func parseFlags(fs *flag.FlagSet, args []string) {
+	fs.Usage = func() { printFlags(fs.Output(), fs) }
+	if err := envflags.Apply(fs); err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	fs.Parse(args)
+}
 
-		// Append to all transactions
-		allTxs = append(allTxs, batchTxs...)
+// errorHint suggests a fix for the provider errors a user can act on
+func errorHint(err error) string {
+	switch {
+	case errors.Is(err, api.ErrInvalidAPIKey):
+		return " (check -apikey or ETHERSCAN_API_KEY)"
+	case errors.Is(err, api.ErrRateLimited):
+		return " (lower -rate-limit or pass several keys to -apikey)"
+	case errors.Is(err, api.ErrResultWindowExceeded):
+		return " (a single block holds more rows than the explorer returns for one query)"
+	}
+	return ""
+}
 
-		// Write intermediate results to CSV
-		intermediateFilePath := filepath.Join(outputDir,
-			fmt.Sprintf("%s_tx_history_blocks_%d_%d.csv", address, currentStart, currentEnd))
-		if err := utils.ExportTransactionsToCSV(batchTxs, intermediateFilePath); err != nil {
-			fmt.Printf("Warning: Error saving intermediate results: %v\n", err)
-		} else {
-			fmt.Printf("Saved intermediate results to %s\n", intermediateFilePath)
-		}
+// resolveAPIKey falls back to ETHERSCAN_API_KEY when no key was given and
+// reads keys given as secret references from their secrets manager. Each
+// key of a comma-separated list is resolved separately.
+func resolveAPIKey(apiKey string) string {
+	if apiKey == "" {
+		apiKey = os.Getenv("ETHERSCAN_API_KEY")
+	}
+	if apiKey == "" {
+		return ""
+	}
+	keys := strings.Split(apiKey, ",")
+	for i, key := range keys {
+		keys[i] = resolveSecret("-apikey", strings.TrimSpace(key))
+	}
+	return strings.Join(keys, ",")
+}
 
-		processedBlocks += (currentEnd - currentStart)
+// newClient creates an Etherscan client for apiKey on the explorer API of
+// chain, rotating between the keys of a comma-separated list at up to
+// callsPerSecond calls per key
+func newClient(apiKey string, callsPerSecond float64, chain chains.Chain) *api.EtherscanClient {
+	keys := strings.Split(apiKey, ",")
+	client := api.NewEtherscanClient(keys[0])
+	client.Logger = logger
+	client.Chain = chain
+	client.BaseURL = chain.ExplorerAPI
+	if len(keys) > 1 {
+		client.Keys = api.NewKeyPool(keys, callsPerSecond)
 	}
+	return client
+}
 
-	// Export final combined CSV
-	finalFilePath := filepath.Join(outputDir, fmt.Sprintf("%s_tx_history_full.csv", address))
-	if err := utils.ExportTransactionsToCSV(allTxs, finalFilePath); err != nil {
-		log.Fatalf("Error exporting to CSV: %v", err)
+// printAPIUsage reports the calls made during the run, against the
+// -max-api-calls budget when one is set, and the calls made with each key
+// when several are pooled
+func printAPIUsage(client *api.EtherscanClient) {
+	stats := client.Stats()
+	fmt.Printf("API usage: %d calls, %d retries", stats.Calls, stats.Retries)
+	if client.Budget != nil {
+		fmt.Printf(" (budget %d)", client.Budget.Max())
+	}
+	if client.Breaker != nil && client.Breaker.Trips() > 0 {
+		fmt.Printf(", circuit breaker tripped %d times", client.Breaker.Trips())
+	}
+	fmt.Println()
+	if client.Keys == nil {
+		return
 	}
+	for _, usage := range client.Usage() {
+		fmt.Printf("  %s  %d calls, %d rate limited\n", usage.Key, usage.Calls, usage.RateLimited)
+	}
+}
 
-	fmt.Printf("\nComplete! Exported %d transactions to %s\n", len(allTxs), finalFilePath)
+// resolveSecret resolves a vault:// or aws-sm:// reference given for
+// flagName, exiting when the secret cannot be read
+func resolveSecret(flagName, value string) string {
+	resolved, err := secrets.Resolve(value)
+	if err != nil {
+		log.Fatalf("Error: could not read %s secret: %v", flagName, err)
+	}
+	return resolved
 }
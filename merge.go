@@ -0,0 +1,81 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/haridev22/ct-assignement/pkg/dedupe"
+	"github.com/haridev22/ct-assignement/pkg/export"
+	"github.com/haridev22/ct-assignement/pkg/models"
+)
+
+// defineMerge defines the merge subcommand, which combines several exports
+// of an address, such as the intermediate files of a batch export or the
+// files of each chain, into a single deduplicated, chronological export
+func defineMerge(fs *flag.FlagSet) func() {
+	address := fs.String("address", "", "Address the rows belong to (default: taken from the names of the inputs)")
+	outputDir := fs.String("output", defaultOutputDir, "Directory to save the merged export, or - to write it to stdout")
+	exportOpts := addExportFlags(fs)
+	return func() {
+		inputs := fs.Args()
+		if len(inputs) < 2 {
+			log.Fatal("Error: merge requires at least two exports: merge [flags] file...")
+		}
+		if *address == "" {
+			for _, path := range inputs {
+				prefix, ok := exportAddress(path)
+				if !ok {
+					log.Fatalf("Error: -address is required when an input file name does not start with the address, as %s does.", path)
+				}
+				if *address != "" && !strings.EqualFold(prefix, *address) {
+					log.Fatalf("Error: the inputs belong to %s and %s; pass -address to merge them anyway.", *address, prefix)
+				}
+				*address = prefix
+			}
+		}
+		opts := exportOpts.options()
+		exportToStdout(outputDir, &opts)
+
+		sets := make([][]models.Transaction, len(inputs))
+		var carried []string
+		for i, path := range inputs {
+			txs, columns, err := export.ReadFile(path)
+			if err != nil {
+				log.Fatalf("Error reading %s: %v", path, err)
+			}
+			fmt.Printf("Read %d rows from %s\n", len(txs), path)
+			sets[i] = txs
+			for _, col := range columns {
+				carried = append(carried, col.Key)
+			}
+		}
+		if *exportOpts.columns == "" && *exportOpts.extraColumns == "" {
+			// Keep the optional columns of the inputs. Running balances of
+			// separate files do not add up, so they are only written when
+			// -running-balance replays the merged rows.
+			for _, key := range carried {
+				if key != "balance" && !containsColumn(models.DefaultColumns, key) {
+					opts.csv.Columns = appendMissingColumns(opts.csv.Columns, key)
+				}
+			}
+		}
+
+		// Drop the rows found in several inputs before -running-balance
+		// replays them; prepareExport then applies the -duplicates policy
+		merged := export.Merge(sets...)
+		unique := dedupe.Apply(merged, dedupe.KeepAll)
+		fmt.Printf("Merged %d rows from %d files, dropping %d duplicates\n", len(merged), len(inputs), len(merged)-len(unique))
+		txs := prepareExport(*address, unique, opts)
+		first, last := blockRange(txs)
+		describeManifestRun(opts, "merge", first, last, *address)
+
+		written, err := writeExport(txs, *outputDir, *address+"_tx_history_merged", opts)
+		if err != nil {
+			log.Fatalf("Error exporting to CSV: %v", err)
+		}
+		fmt.Printf("Exported %d transactions to %s\n", len(txs), written)
+		writeManifest(*outputDir, opts)
+	}
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/haridev22/ct-assignement/pkg/api"
+	"github.com/haridev22/ct-assignement/pkg/cache"
+	"github.com/haridev22/ct-assignement/pkg/chains"
+	"github.com/haridev22/ct-assignement/pkg/daterange"
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/haridev22/ct-assignement/pkg/skipped"
+)
+
+// parseChains resolves a comma-separated -chains list, exiting on unknown
+// or repeated names
+func parseChains(list string) []chains.Chain {
+	var result []chains.Chain
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		chain, err := chains.Lookup(name)
+		if err != nil {
+			log.Fatalf("Error: invalid -chains: %v", err)
+		}
+		if seen[chain.Name] {
+			log.Fatalf("Error: chain %s is listed twice in -chains.", chain.Name)
+		}
+		seen[chain.Name] = true
+		result = append(result, chain)
+	}
+	if len(result) == 0 {
+		log.Fatal("Error: -chains lists no chains.")
+	}
+	return result
+}
+
+// chainAPIKey returns the explorer API key for chain: the value of its
+// <CHAIN>_API_KEY environment variable, such as POLYGON_API_KEY, or
+// fallback when that is unset
+func chainAPIKey(chain chains.Chain, fallback string) string {
+	if key := os.Getenv(strings.ToUpper(chain.Name) + "_API_KEY"); key != "" {
+		return resolveAPIKey(key)
+	}
+	return fallback
+}
+
+// chainResult is the outcome of fetching one chain of a multichain export
+type chainResult struct {
+	chain  chains.Chain
+	client *api.EtherscanClient
+	txs    []models.Transaction
+	errs   []error
+}
+
+// runMultichain exports the transactions of address on every chain into one
+// chain-tagged file. Chains are fetched concurrently, each through its own
+// client, so every explorer's rate limit applies separately. Block numbers
+// differ between chains, so each chain covers its whole history or the
+// blocks of dateRange. Unless continuing on error, a chain that fails fails
+// the export.
+func runMultichain(list []chains.Chain, clientFor func(chains.Chain) *api.EtherscanClient, address string, dateRange daterange.Range, outputDir string, opts runOptions) error {
+	var err error
+	if opts.skipped, err = skipped.New(filepath.Join(outputDir, fmt.Sprintf("%s_errors.jsonl", address)), address, false); err != nil {
+		return err
+	}
+	defer closeErrorReport(opts)
+
+	names := make([]string, len(list))
+	for i, chain := range list {
+		names[i] = chain.Name
+	}
+	fmt.Printf("Fetching transactions for address %s on %s\n", address, strings.Join(names, ", "))
+
+	results := make([]chainResult, len(list))
+	var wg sync.WaitGroup
+	for i, chain := range list {
+		wg.Add(1)
+		go func(i int, chain chains.Chain) {
+			defer wg.Done()
+			results[i] = fetchChain(clientFor(chain), chain, address, dateRange, opts)
+		}(i, chain)
+	}
+	wg.Wait()
+
+	var allTxs []models.Transaction
+	var failed []string
+	for _, result := range results {
+		printChainUsage(result)
+		if len(result.errs) > 0 {
+			for _, err := range result.errs {
+				fmt.Printf("Warning: %s: %v%s\n", result.chain.Name, err, errorHint(err))
+			}
+			failed = append(failed, result.chain.Name)
+		}
+		allTxs = append(allTxs, result.txs...)
+	}
+	if opts.interrupted() {
+		return errInterrupted
+	}
+	if len(failed) > 0 && !opts.continueOnError {
+		return fmt.Errorf("fetching %s failed", strings.Join(failed, ", "))
+	}
+
+	// Block numbers of different chains are not comparable, so interleave
+	// the chains by time; the stable sort keeps rows of a chain in API order
+	sort.SliceStable(allTxs, func(i, j int) bool {
+		return allTxs[i].Timestamp.Before(allTxs[j].Timestamp)
+	})
+	allTxs = prepareExport(address, allTxs, opts)
+	fmt.Printf("Total transactions: %d\n", len(allTxs))
+
+	written, err := writeExport(allTxs, outputDir, address+"_multichain_tx_history", opts)
+	if err != nil {
+		return fmt.Errorf("error exporting to CSV: %w", err)
+	}
+	fmt.Printf("Exported transaction history to %s\n", written)
+	if len(failed) > 0 {
+		fmt.Printf("Warning: the export is incomplete, it lacks the rows of %s\n", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// fetchChain fetches, converts and enriches every transaction type of
+// address on chain
+func fetchChain(client *api.EtherscanClient, chain chains.Chain, address string, dateRange daterange.Range, opts runOptions) chainResult {
+	result := chainResult{chain: chain, client: client}
+	head, err := client.Preflight()
+	if err != nil {
+		result.errs = []error{fmt.Errorf("preflight check failed: %w", err)}
+		return result
+	}
+	startBlock, endBlock := int64(defaultStartBlock), head
+	if !dateRange.Start.IsZero() || !dateRange.End.IsZero() {
+		startBlock, endBlock = resolveDateRange(client, dateRange, startBlock, endBlock)
+	}
+	if opts.minConfirmations > 0 {
+		endBlock = confirmedEndBlock(startBlock, endBlock, head, opts.minConfirmations)
+	}
+	if opts.finalized {
+		finalized, err := client.GetFinalizedBlockNumber()
+		if err != nil {
+			result.errs = []error{fmt.Errorf("could not determine the latest finalized block: %w", err)}
+			return result
+		}
+		endBlock = finalizedEndBlock(startBlock, endBlock, finalized)
+	}
+	fmt.Printf("[%s] Fetching blocks %d to %d\n", chain.Name, startBlock, endBlock)
+
+	// Each chain converts and enriches its own rows. Block numbers and
+	// contract addresses repeat across chains, so metadata is cached per chain.
+	strict := opts.converter.Strict
+	opts.converter = converterFor(chain)
+	opts.converter.Strict = strict
+	opts.metadata = cache.New()
+	opts.l1Fees = chain.OPStack
+	result.txs, result.errs = fetchRange(client, opts, address, startBlock, endBlock)
+	if opts.blockRewards {
+		rewards, err := fetchBlockRewards(client, opts, address, startBlock, endBlock)
+		if err != nil {
+			result.errs = append(result.errs, &typeError{kind: "rewards", err: fmt.Errorf("error fetching block rewards: %w", err)})
+		}
+		result.txs = append(result.txs, rewards...)
+	}
+	if opts.interrupted() {
+		return result
+	}
+	fmt.Printf("[%s] Fetched %d transactions\n", chain.Name, len(result.txs))
+	enrichTransactions(client, opts, result.txs)
+	return result
+}
+
+// printChainUsage reports the API calls made on one chain
+func printChainUsage(result chainResult) {
+	stats := result.client.Stats()
+	fmt.Printf("[%s] API usage: %d calls, %d retries\n", result.chain.Name, stats.Calls, stats.Retries)
+}
@@ -0,0 +1,195 @@
+// Package alert evaluates user-defined rules against transactions, so that
+// notifications and exports can single out the rows that need attention.
+package alert
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+)
+
+// Directions of a rule, relative to the watched or exported address
+const (
+	DirectionIn  = "in"
+	DirectionOut = "out"
+)
+
+// Rule matches the rows that meet all of its conditions. Empty conditions
+// match every row; a list matches when any of its entries does.
+type Rule struct {
+	// Name identifies the rule in notifications and in the Alert column
+	Name string `json:"name"`
+	// ValueAbove matches rows moving more than this amount, in the units
+	// of the row's asset
+	ValueAbove string `json:"value_above,omitempty"`
+	// Tokens are asset symbols or token contract addresses. Native rows
+	// carry the symbol of their chain, such as ETH.
+	Tokens []string `json:"tokens,omitempty"`
+	// Counterparties are the addresses on the other side of the row, such
+	// as a denylist
+	Counterparties []string `json:"counterparties,omitempty"`
+	// Direction is in for rows received and out for rows sent. Transfers to
+	// the address itself are both.
+	Direction string `json:"direction,omitempty"`
+	// Types are transaction types such as ERC20_TRANSFER
+	Types []models.TransactionType `json:"types,omitempty"`
+}
+
+// Rules is a set of rules, as read from a rules file
+type Rules []Rule
+
+// file is the format of a rules file
+type file struct {
+	Rules Rules `json:"rules"`
+}
+
+// Load reads a rules file
+func Load(path string) (Rules, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Parse(f)
+}
+
+// Parse reads rules in the format {"rules": [{"name": ..., ...}]} and
+// validates them. Unknown fields are errors, so misspelled conditions do
+// not silently match every row.
+func Parse(r io.Reader) (Rules, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	var f file
+	if err := dec.Decode(&f); err != nil {
+		return nil, fmt.Errorf("invalid rules: %w", err)
+	}
+	if err := f.Rules.Validate(); err != nil {
+		return nil, err
+	}
+	return f.Rules, nil
+}
+
+// Validate checks that every rule has a unique name and valid conditions,
+// and normalizes the case of directions and types
+func (rules Rules) Validate() error {
+	seen := make(map[string]bool)
+	for i := range rules {
+		rule := &rules[i]
+		if rule.Name == "" {
+			return fmt.Errorf("rule %d has no name", i+1)
+		}
+		if seen[rule.Name] {
+			return fmt.Errorf("rule %q is defined twice", rule.Name)
+		}
+		seen[rule.Name] = true
+
+		if rule.ValueAbove != "" {
+			if _, ok := new(big.Rat).SetString(rule.ValueAbove); !ok {
+				return fmt.Errorf("rule %q: invalid value_above %q", rule.Name, rule.ValueAbove)
+			}
+		}
+		rule.Direction = strings.ToLower(rule.Direction)
+		if rule.Direction != "" && rule.Direction != DirectionIn && rule.Direction != DirectionOut {
+			return fmt.Errorf("rule %q: invalid direction %q (use in or out)", rule.Name, rule.Direction)
+		}
+		for j, t := range rule.Types {
+			rule.Types[j] = models.TransactionType(strings.ToUpper(string(t)))
+		}
+	}
+	return nil
+}
+
+// Matches reports whether the row tx of address meets the conditions of r
+func (r *Rule) Matches(address string, tx *models.Transaction) bool {
+	from := strings.EqualFold(tx.From, address)
+	to := strings.EqualFold(tx.To, address)
+	switch r.Direction {
+	case DirectionIn:
+		if !to {
+			return false
+		}
+	case DirectionOut:
+		if !from {
+			return false
+		}
+	}
+
+	if r.ValueAbove != "" {
+		threshold, ok := new(big.Rat).SetString(r.ValueAbove)
+		if !ok {
+			return false
+		}
+		value, ok := new(big.Rat).SetString(tx.Value)
+		if !ok || value.Cmp(threshold) <= 0 {
+			return false
+		}
+	}
+	if len(r.Tokens) > 0 && !containsFold(r.Tokens, tx.AssetSymbol, tx.AssetContractAddr) {
+		return false
+	}
+	if len(r.Counterparties) > 0 {
+		counterparty := tx.From
+		if from {
+			counterparty = tx.To
+		}
+		if !containsFold(r.Counterparties, counterparty) {
+			return false
+		}
+	}
+	if len(r.Types) > 0 {
+		matched := false
+		for _, t := range r.Types {
+			matched = matched || t == tx.Type
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// Match returns the names of the rules that the row tx of address meets
+func (rules Rules) Match(address string, tx *models.Transaction) []string {
+	var names []string
+	for i := range rules {
+		if rules[i].Matches(address, tx) {
+			names = append(names, rules[i].Name)
+		}
+	}
+	return names
+}
+
+// Tag sets the Alert field of each row of address to the names of the rules
+// it meets, separated by semicolons, and returns the number of rows tagged
+func (rules Rules) Tag(address string, txs []models.Transaction) int {
+	tagged := 0
+	for i := range txs {
+		names := rules.Match(address, &txs[i])
+		txs[i].Alert = strings.Join(names, ";")
+		if len(names) > 0 {
+			tagged++
+		}
+	}
+	return tagged
+}
+
+// containsFold reports whether any of values is in list, ignoring case.
+// Empty values never match.
+func containsFold(list []string, values ...string) bool {
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+		for _, item := range list {
+			if strings.EqualFold(strings.TrimSpace(item), value) {
+				return true
+			}
+		}
+	}
+	return false
+}
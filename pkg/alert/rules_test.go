@@ -0,0 +1,72 @@
+package alert
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	wallet   = "0x1111111111111111111111111111111111111111"
+	mixer    = "0x2222222222222222222222222222222222222222"
+	exchange = "0x3333333333333333333333333333333333333333"
+	usdc     = "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+)
+
+const rulesJSON = `{"rules": [
+	{"name": "large-eth-out", "direction": "OUT", "tokens": ["eth"], "value_above": "10"},
+	{"name": "usdc", "tokens": ["0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"], "types": ["erc20_transfer"]},
+	{"name": "denylist", "counterparties": ["0x2222222222222222222222222222222222222222"]}
+]}`
+
+func TestRules_Match(t *testing.T) {
+	rules, err := Parse(strings.NewReader(rulesJSON))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name string
+		tx   models.Transaction
+		want []string
+	}{
+		{"large send", models.Transaction{From: wallet, To: exchange, AssetSymbol: "ETH", Value: "12.5"}, []string{"large-eth-out"}},
+		{"small send", models.Transaction{From: wallet, To: exchange, AssetSymbol: "ETH", Value: "10"}, nil},
+		{"large receipt", models.Transaction{From: exchange, To: wallet, AssetSymbol: "ETH", Value: "50"}, nil},
+		{"usdc by contract", models.Transaction{From: exchange, To: wallet, Type: models.TypeERC20Transfer, AssetSymbol: "USDC", AssetContractAddr: usdc, Value: "5"}, []string{"usdc"}},
+		{"from denylisted", models.Transaction{From: mixer, To: wallet, AssetSymbol: "ETH", Value: "20"}, []string{"denylist"}},
+		{"to denylisted", models.Transaction{From: wallet, To: mixer, AssetSymbol: "ETH", Value: "20"}, []string{"large-eth-out", "denylist"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, rules.Match(wallet, &tt.tx))
+		})
+	}
+}
+
+func TestRules_Tag(t *testing.T) {
+	rules, err := Parse(strings.NewReader(rulesJSON))
+	assert.NoError(t, err)
+	txs := []models.Transaction{
+		{From: wallet, To: mixer, AssetSymbol: "ETH", Value: "20", Alert: "stale"},
+		{From: exchange, To: wallet, AssetSymbol: "ETH", Value: "1", Alert: "stale"},
+	}
+
+	assert.Equal(t, 1, rules.Tag(wallet, txs))
+	assert.Equal(t, "large-eth-out;denylist", txs[0].Alert)
+	assert.Empty(t, txs[1].Alert)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := map[string]string{
+		`{"rules": [{"tokens": ["ETH"]}]}`:                    "rule 1 has no name",
+		`{"rules": [{"name": "a"}, {"name": "a"}]}`:           `rule "a" is defined twice`,
+		`{"rules": [{"name": "a", "value_above": "lots"}]}`:   `invalid value_above "lots"`,
+		`{"rules": [{"name": "a", "direction": "sideways"}]}`: `invalid direction "sideways"`,
+		`{"rules": [{"name": "a", "counterparty": ["0x1"]}]}`: `unknown field "counterparty"`,
+	}
+	for input, want := range tests {
+		_, err := Parse(strings.NewReader(input))
+		assert.ErrorContains(t, err, want, input)
+	}
+}
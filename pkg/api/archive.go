@@ -0,0 +1,11 @@
+package api
+
+import "net/url"
+
+// archive saves the raw body of a response to the client's archive, if
+// any. A failure to archive is reported but does not fail the request.
+func (c *EtherscanClient) archive(params url.Values, body []byte) {
+	if err := c.Archive.Save(params, body); err != nil {
+		c.logger().Warn("failed to archive raw response", "error", err)
+	}
+}
@@ -0,0 +1,32 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/haridev22/ct-assignement/pkg/rawarchive"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEtherscanClient_Archive(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(APIResponse{Status: "1", Message: "OK", Result: json.RawMessage(`[{"hash":"0x1"}]`)})
+	}))
+	defer server.Close()
+
+	dir := filepath.Join(t.TempDir(), "0xabc_raw")
+	client := NewEtherscanClient("key")
+	client.BaseURL = server.URL
+	client.Archive = rawarchive.New(dir)
+
+	_, err := client.GetNormalTransactionsPaginated("0xabc", 0, 100, 1, 100)
+	assert.NoError(t, err)
+	assert.FileExists(t, filepath.Join(dir, "txlist_0-100_p1.json.gz"))
+
+	entries, _ := os.ReadDir(dir)
+	assert.Len(t, entries, 1)
+}
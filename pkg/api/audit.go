@@ -0,0 +1,39 @@
+package api
+
+import (
+	"encoding/json"
+	"net/url"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/audit"
+)
+
+// recordCall logs a request at LevelTrace and appends it to the client's
+// audit log, if any. The API key is left out of the params hash so entries
+// can be shared.
+func (c *EtherscanClient) recordCall(params url.Values, start time.Time, result json.RawMessage, err error) {
+	if c.Audit == nil && !c.logger().Enabled(c.context(), LevelTrace) {
+		return
+	}
+
+	entry := audit.Entry{
+		Time:       start.UTC(),
+		Provider:   "etherscan",
+		Endpoint:   audit.RedactURL(c.BaseURL),
+		Method:     params.Get("module") + "." + params.Get("action"),
+		ParamsHash: audit.HashValues(params, "apikey"),
+		Results:    audit.ResultCount(result),
+		LatencyMS:  time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	c.logger().Log(c.context(), LevelTrace, "request", "method", entry.Method, "params_hash", entry.ParamsHash,
+		"results", entry.Results, "latency_ms", entry.LatencyMS, "error", entry.Error)
+	if c.Audit == nil {
+		return
+	}
+	if werr := c.Audit.Record(entry); werr != nil {
+		c.logger().Warn("failed to write audit log", "error", werr)
+	}
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/haridev22/ct-assignement/pkg/audit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEtherscanClient_Audit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("action") == "eth_getTransactionReceipt" {
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"not found"}}`))
+			return
+		}
+		json.NewEncoder(w).Encode(APIResponse{
+			Status:  "1",
+			Message: "OK",
+			Result:  json.RawMessage(`[{"hash":"0x1"},{"hash":"0x2"},{"hash":"0x3"}]`),
+		})
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	log, err := audit.Open(path)
+	assert.NoError(t, err)
+
+	client := NewEtherscanClient("secret_api_key")
+	client.BaseURL = server.URL
+	client.Audit = log
+
+	_, err = client.GetNormalTransactionsPaginated("0xabc", 0, 100, 1, 100)
+	assert.NoError(t, err)
+	_, err = client.GetTransactionReceipt("0xmissing")
+	assert.Error(t, err)
+	assert.NoError(t, log.Close())
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "secret_api_key")
+
+	var entries []audit.Entry
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for scanner.Scan() {
+		var entry audit.Entry
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		entries = append(entries, entry)
+	}
+	if assert.Len(t, entries, 2) {
+		assert.Equal(t, "account.txlist", entries[0].Method)
+		assert.Equal(t, 3, entries[0].Results)
+		assert.Empty(t, entries[0].Error)
+		assert.Len(t, entries[0].ParamsHash, 64)
+
+		assert.Equal(t, "proxy.eth_getTransactionReceipt", entries[1].Method)
+		assert.Equal(t, -1, entries[1].Results)
+		assert.Contains(t, entries[1].Error, "not found")
+	}
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"fmt"
+	"math/big"
+	"net/url"
+	"strconv"
+)
+
+// GetBalance returns the native balance of address in wei at the latest block
+func (c *EtherscanClient) GetBalance(address string) (*big.Int, error) {
+	params := url.Values{}
+	params.Add("module", "account")
+	params.Add("action", "balance")
+	params.Add("address", address)
+	params.Add("tag", "latest")
+	params.Add("apikey", c.ApiKey)
+	return c.balanceRequest(params)
+}
+
+// GetBalanceAt returns the native balance of address in wei after block.
+// Etherscan only serves past balances to API Pro keys.
+func (c *EtherscanClient) GetBalanceAt(address string, block int64) (*big.Int, error) {
+	params := url.Values{}
+	params.Add("module", "account")
+	params.Add("action", "balancehistory")
+	params.Add("address", address)
+	params.Add("blockno", strconv.FormatInt(block, 10))
+	params.Add("apikey", c.ApiKey)
+	return c.balanceRequest(params)
+}
+
+// GetTokenBalance returns the balance of address in the ERC-20 token
+// contract at the latest block, in the token's smallest unit
+func (c *EtherscanClient) GetTokenBalance(address, contract string) (*big.Int, error) {
+	params := url.Values{}
+	params.Add("module", "account")
+	params.Add("action", "tokenbalance")
+	params.Add("contractaddress", contract)
+	params.Add("address", address)
+	params.Add("tag", "latest")
+	params.Add("apikey", c.ApiKey)
+	return c.balanceRequest(params)
+}
+
+func (c *EtherscanClient) balanceRequest(params url.Values) (*big.Int, error) {
+	var result string
+	if err := c.requestWithRetry(params, &result); err != nil {
+		return nil, fmt.Errorf("failed to fetch balance: %w", err)
+	}
+	balance, ok := new(big.Int).SetString(result, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid balance %q", result)
+	}
+	return balance, nil
+}
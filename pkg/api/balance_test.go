@@ -0,0 +1,53 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetBalance(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		assert.Equal(t, "0xwallet", query.Get("address"))
+		switch query.Get("action") {
+		case "balance":
+			assert.Equal(t, "latest", query.Get("tag"))
+			json.NewEncoder(w).Encode(APIResponse{Status: "1", Message: "OK", Result: json.RawMessage(`"1500000000000000000"`)})
+		case "balancehistory":
+			assert.Equal(t, "18000000", query.Get("blockno"))
+			json.NewEncoder(w).Encode(APIResponse{Status: "0", Message: "NOTOK", Result: json.RawMessage(`"Sorry, it looks like you are trying to access an API Pro endpoint."`)})
+		}
+	}))
+	defer server.Close()
+
+	client := NewEtherscanClient("dummy_api_key")
+	client.BaseURL = server.URL
+
+	balance, err := client.GetBalance("0xwallet")
+	assert.NoError(t, err)
+	assert.Equal(t, "1500000000000000000", balance.String())
+
+	_, err = client.GetBalanceAt("0xwallet", 18000000)
+	assert.ErrorContains(t, err, "API Pro")
+}
+
+func TestGetTokenBalance(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		assert.Equal(t, "tokenbalance", query.Get("action"))
+		assert.Equal(t, "0xusdc", query.Get("contractaddress"))
+		json.NewEncoder(w).Encode(APIResponse{Status: "1", Message: "OK", Result: json.RawMessage(`"250000000"`)})
+	}))
+	defer server.Close()
+
+	client := NewEtherscanClient("dummy_api_key")
+	client.BaseURL = server.URL
+
+	balance, err := client.GetTokenBalance("0xwallet", "0xusdc")
+	assert.NoError(t, err)
+	assert.Equal(t, "250000000", balance.String())
+}
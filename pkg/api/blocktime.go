@@ -0,0 +1,35 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Closest selects which block GetBlockNumberByTime returns when no block
+// was mined at exactly the requested time
+type Closest string
+
+const (
+	// ClosestBefore returns the last block mined at or before the time
+	ClosestBefore Closest = "before"
+	// ClosestAfter returns the first block mined at or after the time
+	ClosestAfter Closest = "after"
+)
+
+// GetBlockNumberByTime resolves a point in time to a block number
+func (c *EtherscanClient) GetBlockNumberByTime(t time.Time, closest Closest) (int64, error) {
+	params := url.Values{}
+	params.Add("module", "block")
+	params.Add("action", "getblocknobytime")
+	params.Add("timestamp", strconv.FormatInt(t.Unix(), 10))
+	params.Add("closest", string(closest))
+	params.Add("apikey", c.ApiKey)
+
+	var result string
+	if err := c.requestWithRetry(params, &result); err != nil {
+		return 0, fmt.Errorf("failed to resolve block at %s: %w", t.UTC().Format(time.RFC3339), err)
+	}
+	return strconv.ParseInt(result, 10, 64)
+}
@@ -0,0 +1,36 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetBlockNumberByTime(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		assert.Equal(t, "getblocknobytime", query.Get("action"))
+		assert.Equal(t, "1704067200", query.Get("timestamp"))
+		if query.Get("closest") == "after" {
+			json.NewEncoder(w).Encode(APIResponse{Status: "1", Message: "OK", Result: json.RawMessage(`"18908895"`)})
+			return
+		}
+		json.NewEncoder(w).Encode(APIResponse{Status: "0", Message: "NOTOK", Result: json.RawMessage(`"Error! No closest block found"`)})
+	}))
+	defer server.Close()
+
+	client := NewEtherscanClient("dummy_api_key")
+	client.BaseURL = server.URL
+
+	newYear := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	block, err := client.GetBlockNumberByTime(newYear, ClosestAfter)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(18908895), block)
+
+	_, err = client.GetBlockNumberByTime(newYear, ClosestBefore)
+	assert.ErrorContains(t, err, "2024-01-01T00:00:00Z")
+}
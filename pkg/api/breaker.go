@@ -0,0 +1,88 @@
+package api
+
+import (
+	"sync"
+	"time"
+)
+
+// CircuitBreaker pauses every request of a client after a run of
+// consecutive failures, so a failing provider gets a cool-down instead of
+// each page exhausting its retries against it. After the cool-down the
+// next failure trips the breaker again, while a success closes it. It is
+// safe for concurrent use.
+type CircuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	openUntil time.Time
+	trips     int
+
+	// now is replaced in tests
+	now func() time.Time
+}
+
+// NewCircuitBreaker creates a breaker that trips after threshold
+// consecutive failures and stays open for cooldown
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{threshold: threshold, cooldown: cooldown, now: time.Now}
+}
+
+// Trips returns how many times the breaker has tripped
+func (b *CircuitBreaker) Trips() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.trips
+}
+
+// pause returns how long requests must wait before the breaker closes, or
+// 0 when it is closed. A nil breaker never pauses.
+func (b *CircuitBreaker) pause() time.Duration {
+	if b == nil {
+		return 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return max(b.openUntil.Sub(b.now()), 0)
+}
+
+// record counts the outcome of a request, reporting whether a failure
+// tripped the breaker
+func (b *CircuitBreaker) record(ok bool) bool {
+	if b == nil {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if ok {
+		b.failures = 0
+		return false
+	}
+	b.failures++
+	if b.failures < b.threshold || b.now().Before(b.openUntil) {
+		return false
+	}
+	b.openUntil = b.now().Add(b.cooldown)
+	b.trips++
+	return true
+}
+
+// awaitBreaker waits while the client's circuit breaker is open, returning
+// early with the context's error when it is cancelled first
+func (c *EtherscanClient) awaitBreaker() error {
+	d := c.Breaker.pause()
+	if d == 0 {
+		return nil
+	}
+	c.logger().Debug("circuit breaker open, pausing request", "delay", d)
+	return c.wait(d)
+}
+
+// recordOutcome feeds the outcome of a request to the client's circuit
+// breaker, logging when it trips
+func (c *EtherscanClient) recordOutcome(ok bool) {
+	if c.Breaker.record(ok) {
+		c.logger().Warn("provider keeps failing, pausing all requests",
+			"consecutive_failures", c.Breaker.threshold, "cooldown", c.Breaker.cooldown)
+	}
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	breaker := NewCircuitBreaker(3, time.Minute)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	breaker.now = func() time.Time { return now }
+
+	assert.False(t, breaker.record(false))
+	assert.False(t, breaker.record(false))
+	assert.False(t, breaker.record(true), "a success resets the count")
+	assert.False(t, breaker.record(false))
+	assert.False(t, breaker.record(false))
+	assert.True(t, breaker.record(false))
+	assert.Equal(t, time.Minute, breaker.pause())
+
+	// Failures of requests already in flight do not extend the cool-down
+	assert.False(t, breaker.record(false))
+	assert.Equal(t, 1, breaker.Trips())
+
+	// After the cool-down a single failure trips it again
+	now = now.Add(time.Minute)
+	assert.Equal(t, time.Duration(0), breaker.pause())
+	assert.True(t, breaker.record(false))
+	assert.Equal(t, 2, breaker.Trips())
+}
+
+func TestNilCircuitBreaker(t *testing.T) {
+	var breaker *CircuitBreaker
+	assert.Equal(t, time.Duration(0), breaker.pause())
+	assert.False(t, breaker.record(false))
+}
+
+func TestEtherscanClient_BreakerPausesRequests(t *testing.T) {
+	var requests []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, time.Now())
+		if len(requests) <= 2 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		json.NewEncoder(w).Encode(APIResponse{Status: "1", Message: "OK", Result: json.RawMessage(`[]`)})
+	}))
+	defer server.Close()
+
+	client := NewEtherscanClient("dummy_api_key")
+	client.BaseURL = server.URL
+	client.RetryDelay = time.Millisecond
+	client.Breaker = NewCircuitBreaker(2, 100*time.Millisecond)
+
+	_, err := client.GetNormalTransactions("0x1", 0, 100)
+	assert.NoError(t, err)
+	if assert.Len(t, requests, 3) {
+		assert.GreaterOrEqual(t, requests[2].Sub(requests[1]), 100*time.Millisecond,
+			"the request after the trip waits out the cool-down")
+	}
+	assert.Equal(t, 1, client.Breaker.Trips())
+}
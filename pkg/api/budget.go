@@ -0,0 +1,43 @@
+package api
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// ErrBudgetExhausted is returned for requests beyond the client's call budget
+var ErrBudgetExhausted = errors.New("API call budget exhausted")
+
+// CallBudget caps the number of requests a client sends, so a run stops
+// before a key's daily quota is used up. It is safe for concurrent use.
+type CallBudget struct {
+	max  int64
+	used atomic.Int64
+}
+
+// NewCallBudget creates a budget allowing max requests
+func NewCallBudget(max int64) *CallBudget {
+	return &CallBudget{max: max}
+}
+
+// Max returns the number of requests the budget allows
+func (b *CallBudget) Max() int64 {
+	return b.max
+}
+
+// Used returns the number of requests made against the budget
+func (b *CallBudget) Used() int64 {
+	return min(b.used.Load(), b.max)
+}
+
+// Exhausted reports whether a request was refused because the budget ran
+// out. A nil budget is never exhausted.
+func (b *CallBudget) Exhausted() bool {
+	return b != nil && b.used.Load() > b.max
+}
+
+// take claims one request, reporting false once the budget is exhausted.
+// A nil budget allows every request.
+func (b *CallBudget) take() bool {
+	return b == nil || b.used.Add(1) <= b.max
+}
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCallBudget(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		json.NewEncoder(w).Encode(APIResponse{Status: "1", Message: "OK", Result: json.RawMessage(`[]`)})
+	}))
+	defer server.Close()
+
+	client := NewEtherscanClient("dummy_api_key")
+	client.BaseURL = server.URL
+	client.Budget = NewCallBudget(2)
+
+	for i := 0; i < 2; i++ {
+		_, err := client.GetNormalTransactions("0x1", 0, 100)
+		assert.NoError(t, err)
+	}
+	assert.False(t, client.Budget.Exhausted(), "a run that fits the budget exactly is not cut short")
+
+	_, err := client.GetNormalTransactions("0x1", 0, 100)
+	assert.ErrorIs(t, err, ErrBudgetExhausted)
+	assert.True(t, client.Budget.Exhausted())
+	assert.Equal(t, 2, requests, "no request is sent beyond the budget")
+	assert.Equal(t, int64(2), client.Budget.Used())
+	assert.Equal(t, int64(2), client.Stats().Calls)
+}
+
+func TestNilCallBudget(t *testing.T) {
+	var budget *CallBudget
+	assert.True(t, budget.take())
+	assert.False(t, budget.Exhausted())
+}
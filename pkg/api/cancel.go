@@ -0,0 +1,27 @@
+package api
+
+import (
+	"context"
+	"time"
+)
+
+// context returns the context requests are made under
+func (c *EtherscanClient) context() context.Context {
+	if c.Context == nil {
+		return context.Background()
+	}
+	return c.Context
+}
+
+// wait sleeps for d, returning early with the context's error when it is
+// cancelled first
+func (c *EtherscanClient) wait(d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-c.context().Done():
+		return c.context().Err()
+	}
+}
@@ -0,0 +1,38 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCancelledContextStopsRetries(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	client := NewEtherscanClient("dummy_api_key")
+	client.BaseURL = server.URL
+	client.RetryDelay = time.Hour
+	client.Context = ctx
+
+	time.AfterFunc(50*time.Millisecond, cancel)
+	started := time.Now()
+	_, err := client.GetNormalTransactions("0x1", 0, 100)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, calls)
+	assert.Less(t, time.Since(started), time.Minute)
+
+	// Requests made after cancellation never reach the server
+	_, err = client.GetNormalTransactions("0x1", 0, 100)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, calls)
+}
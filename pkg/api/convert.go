@@ -0,0 +1,63 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+)
+
+// ErrMalformedValue is wrapped by the errors of a strict Converter for
+// numeric fields that are not decimal integers
+var ErrMalformedValue = errors.New("malformed numeric value")
+
+// Converter converts provider records to the common model. By default a
+// malformed amount, gas price or decimals field is read as zero, as the
+// Convert*ToModel functions do. A Strict converter rejects the record
+// instead, so bad provider data cannot silently become wrong rows.
+type Converter struct {
+	Strict bool
+	// Chain is the name of the network the records come from, recorded in
+	// the Chain field of every row
+	Chain string
+	// NativeSymbol, when set, is recorded as the asset symbol of rows moving
+	// the native currency, which otherwise have none
+	NativeSymbol string
+}
+
+// amount parses the decimal integer field name of a record
+func (c Converter) amount(name, value string) (*big.Int, error) {
+	n, ok := new(big.Int).SetString(value, 10)
+	if ok {
+		return n, nil
+	}
+	if c.Strict {
+		return nil, fmt.Errorf("%w: %s %q", ErrMalformedValue, name, value)
+	}
+	return new(big.Int), nil
+}
+
+// gasFee returns gasPrice times gasUsed in wei
+func (c Converter) gasFee(gasPrice, gasUsed string) (*big.Int, error) {
+	price, err := c.amount("gasPrice", gasPrice)
+	if err != nil {
+		return nil, err
+	}
+	used, err := c.amount("gasUsed", gasUsed)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).Mul(price, used), nil
+}
+
+// decimals parses the token decimals of a transfer
+func (c Converter) decimals(value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err == nil && n >= 0 {
+		return n, nil
+	}
+	if c.Strict {
+		return 0, fmt.Errorf("%w: tokenDecimal %q", ErrMalformedValue, value)
+	}
+	return 0, nil
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConverter_Strict(t *testing.T) {
+	tx := NormalTransaction{
+		BlockNumber: "100",
+		TimeStamp:   "1700000000",
+		Hash:        "0xabc",
+		Value:       "1e18",
+		GasPrice:    "20000000000",
+		GasUsed:     "21000",
+	}
+
+	// By default the malformed value is read as zero
+	model, err := ConvertNormalTxToModel(tx)
+	assert.NoError(t, err)
+	assert.Equal(t, "0.000000000000000000", model.Value)
+	assert.Equal(t, "0.000420000000000000", model.GasFee)
+
+	_, err = Converter{Strict: true}.NormalTx(tx)
+	assert.ErrorIs(t, err, ErrMalformedValue)
+	assert.Contains(t, err.Error(), `value "1e18"`)
+
+	tx.Value = "1000000000000000000"
+	model, err = Converter{Strict: true}.NormalTx(tx)
+	assert.NoError(t, err)
+	assert.Equal(t, "1.000000000000000000", model.Value)
+}
+
+func TestConverter_LenientGasFee(t *testing.T) {
+	// An empty gas price used to panic on the nil product
+	model, err := ConvertERC721TxToModel(ERC721Transaction{BlockNumber: "1", TimeStamp: "1", GasUsed: "21000"})
+	assert.NoError(t, err)
+	assert.Equal(t, "0.000000000000000000", model.GasFee)
+
+	_, err = Converter{Strict: true}.ERC721Tx(ERC721Transaction{BlockNumber: "1", TimeStamp: "1", GasUsed: "21000"})
+	assert.ErrorIs(t, err, ErrMalformedValue)
+}
+
+func TestConverter_StrictDecimals(t *testing.T) {
+	tx := ERC20Transaction{BlockNumber: "1", TimeStamp: "1", Value: "1500", TokenDecimal: "three", GasPrice: "1", GasUsed: "1"}
+
+	model, err := ConvertERC20TxToModel(tx)
+	assert.NoError(t, err)
+	assert.Equal(t, "1500", model.Value)
+
+	_, err = Converter{Strict: true}.ERC20Tx(tx)
+	assert.ErrorIs(t, err, ErrMalformedValue)
+}
+
+func TestConverter_Chain(t *testing.T) {
+	model, err := Converter{Chain: "polygon"}.NormalTx(NormalTransaction{BlockNumber: "1", TimeStamp: "1", Value: "0", GasPrice: "1", GasUsed: "1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "polygon", model.Chain)
+
+	model, err = Converter{Chain: "polygon"}.MinedBlock(MinedBlock{BlockNumber: "1", TimeStamp: "1", BlockReward: "2000000000000000000"}, "0xminer")
+	assert.NoError(t, err)
+	assert.Equal(t, "polygon", model.Chain)
+}
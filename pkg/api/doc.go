@@ -0,0 +1,3 @@
+// Package api implements a client for the Etherscan account API and the
+// conversions from its raw response types to models.Transaction.
+package api
@@ -0,0 +1,143 @@
+package api
+
+import (
+	"fmt"
+	"math/big"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+)
+
+// ERC1155Transaction represents an ERC1155 token transfer from Etherscan API.
+// Etherscan expands TransferBatch events into one entry per token ID.
+type ERC1155Transaction struct {
+	BlockNumber      string `json:"blockNumber"`
+	TimeStamp        string `json:"timeStamp"`
+	Hash             string `json:"hash"`
+	From             string `json:"from"`
+	To               string `json:"to"`
+	TokenID          string `json:"tokenID"`
+	TokenValue       string `json:"tokenValue"`
+	ContractAddress  string `json:"contractAddress"`
+	TokenName        string `json:"tokenName"`
+	TokenSymbol      string `json:"tokenSymbol"`
+	GasPrice         string `json:"gasPrice"`
+	GasUsed          string `json:"gasUsed"`
+	Nonce            string `json:"nonce"`
+	Gas              string `json:"gas"`
+	TransactionIndex string `json:"transactionIndex"`
+}
+
+// GetERC1155TransfersPaginated fetches ERC1155 token transfers for the given address with pagination
+func (c *EtherscanClient) GetERC1155TransfersPaginated(address string, startBlock, endBlock int64, page, offset int) ([]ERC1155Transaction, error) {
+	params := url.Values{}
+	params.Add("module", "account")
+	params.Add("action", "token1155tx")
+	params.Add("address", address)
+	params.Add("startblock", strconv.FormatInt(startBlock, 10))
+	params.Add("endblock", strconv.FormatInt(endBlock, 10))
+	params.Add("page", strconv.Itoa(page))
+	params.Add("offset", strconv.Itoa(offset))
+	params.Add("sort", "asc")
+	params.Add("apikey", c.ApiKey)
+
+	var transactions []ERC1155Transaction
+	if err := c.requestWithRetry(params, &transactions); err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+// GetAllERC1155Transfers fetches all ERC1155 token transfers for the given address using pagination, in block windows sized for the chain (see fetchInWindows)
+func (c *EtherscanClient) GetAllERC1155Transfers(address string, startBlock, endBlock int64) ([]ERC1155Transaction, error) {
+	return fetchInWindows(c, "token1155tx", startBlock, endBlock, func(start, end int64) ([]ERC1155Transaction, error) {
+		return c.getAllERC1155Transfers(address, start, end)
+	})
+}
+
+// getAllERC1155Transfers fetches all ERC1155 token transfers for the given address within a single block window using pagination
+func (c *EtherscanClient) getAllERC1155Transfers(address string, startBlock, endBlock int64) ([]ERC1155Transaction, error) {
+	var allTransactions []ERC1155Transaction
+	page := 1
+	batchSize := DefaultOffset
+
+	for {
+		transactions, err := c.GetERC1155TransfersPaginated(address, startBlock, endBlock, page, batchSize)
+		if err != nil {
+			return nil, err
+		}
+		reached := endBlock
+		if len(transactions) == batchSize {
+			reached = parseBlockNumber(transactions[len(transactions)-1].BlockNumber)
+		}
+		c.reportPage("token1155tx", page, len(transactions), reached)
+
+		allTransactions = append(allTransactions, transactions...)
+
+		// If we got fewer results than the batch size, we've reached the end
+		if len(transactions) < batchSize {
+			break
+		}
+
+		page++
+		// Add a small delay between requests to avoid rate limits
+		time.Sleep(200 * time.Millisecond)
+	}
+
+	c.logger().Debug("fetched all pages", "action", "token1155tx", "rows", len(allTransactions))
+	return allTransactions, nil
+}
+
+// ConvertERC1155TxToModel converts an ERC1155 transaction to a generic transaction model.
+// Unlike ERC721, the Value is the transferred quantity of the token ID.
+func ConvertERC1155TxToModel(tx ERC1155Transaction) (models.Transaction, error) {
+	return Converter{}.ERC1155Tx(tx)
+}
+
+// ERC1155Tx converts an ERC1155 transaction to a generic transaction model
+func (c Converter) ERC1155Tx(tx ERC1155Transaction) (models.Transaction, error) {
+	timestamp, err := strconv.ParseInt(tx.TimeStamp, 10, 64)
+	if err != nil {
+		return models.Transaction{}, err
+	}
+
+	blockNumber, err := strconv.ParseInt(tx.BlockNumber, 10, 64)
+	if err != nil {
+		return models.Transaction{}, err
+	}
+
+	quantity, ok := new(big.Int).SetString(tx.TokenValue, 10)
+	if !ok {
+		return models.Transaction{}, fmt.Errorf("invalid token value %q", tx.TokenValue)
+	}
+
+	gasFee, err := c.gasFee(tx.GasPrice, tx.GasUsed)
+	if err != nil {
+		return models.Transaction{}, err
+	}
+
+	return models.Transaction{
+		Hash:              tx.Hash,
+		BlockNumber:       blockNumber,
+		Timestamp:         time.Unix(timestamp, 0),
+		From:              tx.From,
+		To:                tx.To,
+		Type:              models.TypeERC1155Transfer,
+		AssetContractAddr: tx.ContractAddress,
+		AssetSymbol:       tx.TokenSymbol,
+		TokenID:           tx.TokenID,
+		Value:             quantity.String(),
+		GasFee:            FormatWeiAsEth(gasFee),
+		RawValue:          quantity.String(),
+		RawGasFee:         gasFee.String(),
+		GasPrice:          tx.GasPrice,
+		Status:            models.StatusSuccess, // Transfer events are only emitted by successful transactions
+		Nonce:             tx.Nonce,
+		GasLimit:          tx.Gas,
+		TransactionIndex:  tx.TransactionIndex,
+		Chain:             c.Chain,
+	}, nil
+}
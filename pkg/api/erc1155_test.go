@@ -0,0 +1,59 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertERC1155TxToModel(t *testing.T) {
+	tx := ERC1155Transaction{
+		BlockNumber:     "14000000",
+		TimeStamp:       "1642000000",
+		Hash:            "0xbatch",
+		From:            "0xmarket",
+		To:              "0xplayer",
+		TokenID:         "42",
+		TokenValue:      "250",
+		ContractAddress: "0xgame",
+		TokenSymbol:     "ITEM",
+		GasPrice:        "20000000000",
+		GasUsed:         "100000",
+	}
+
+	result, err := ConvertERC1155TxToModel(tx)
+	assert.NoError(t, err)
+	assert.Equal(t, models.TypeERC1155Transfer, result.Type)
+	assert.Equal(t, "42", result.TokenID)
+	assert.Equal(t, "250", result.Value)
+	assert.Equal(t, int64(14000000), result.BlockNumber)
+	assert.Equal(t, "0.002000000000000000", result.GasFee)
+
+	tx.TokenValue = ""
+	_, err = ConvertERC1155TxToModel(tx)
+	assert.Error(t, err)
+}
+
+func TestGetAllERC1155Transfers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "token1155tx", r.URL.Query().Get("action"))
+		json.NewEncoder(w).Encode(APIResponse{
+			Status:  "1",
+			Message: "OK",
+			Result:  json.RawMessage(`[{"hash":"0xbatch","tokenID":"1","tokenValue":"5"},{"hash":"0xbatch","tokenID":"2","tokenValue":"7"}]`),
+		})
+	}))
+	defer server.Close()
+
+	client := NewEtherscanClient("dummy_api_key")
+	client.BaseURL = server.URL
+
+	transfers, err := client.GetAllERC1155Transfers("0xplayer", 0, 1000000)
+	assert.NoError(t, err)
+	assert.Len(t, transfers, 2)
+	assert.Equal(t, "7", transfers[1].TokenValue)
+}
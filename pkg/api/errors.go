@@ -0,0 +1,84 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Errors the client returns, or wraps, for the kinds of failure callers
+// commonly branch on. Use errors.Is to test for them.
+var (
+	// ErrRateLimited is returned when the provider still rate limits a
+	// request after every retry
+	ErrRateLimited = errors.New("rate limited")
+	// ErrNoTransactions reports an empty result set. The list endpoints
+	// return an empty slice instead.
+	ErrNoTransactions = errors.New("no transactions found")
+	// ErrInvalidAPIKey is returned when the provider rejects a key
+	ErrInvalidAPIKey = errors.New("invalid API key")
+	// ErrResultWindowExceeded is returned when page times offset exceeds
+	// the 10,000 results a single query can return
+	ErrResultWindowExceeded = errors.New("result window exceeded")
+)
+
+// APIError is a status 0 response from the explorer. It unwraps to one of
+// the sentinel errors when the reason is recognised.
+type APIError struct {
+	// Message is Etherscan's message, usually just "NOTOK"
+	Message string
+	// Detail is the reason given in the result field, if any
+	Detail string
+	kind   error
+}
+
+func (e *APIError) Error() string {
+	if e.Detail != "" && e.Detail != e.Message {
+		return fmt.Sprintf("API returned error: %s (%s)", e.Message, e.Detail)
+	}
+	return fmt.Sprintf("API returned error: %s", e.Message)
+}
+
+func (e *APIError) Unwrap() error {
+	return e.kind
+}
+
+// StatusError is an HTTP response with an unsuccessful status code. A 429
+// unwraps to ErrRateLimited.
+type StatusError struct {
+	StatusCode int
+	// Retries is the number of times the request was repeated
+	Retries int
+}
+
+func (e *StatusError) Error() string {
+	if e.Retries > 0 {
+		return fmt.Sprintf("API request failed with status code: %d after %d retries", e.StatusCode, e.Retries)
+	}
+	return fmt.Sprintf("API request failed with status code: %d", e.StatusCode)
+}
+
+func (e *StatusError) Unwrap() error {
+	if e.StatusCode == http.StatusTooManyRequests {
+		return ErrRateLimited
+	}
+	return nil
+}
+
+// classifyError maps the message and detail of a status 0 response to a
+// sentinel error, or nil when the reason is not recognised
+func classifyError(message, detail string) error {
+	text := strings.ToLower(message + " " + detail)
+	switch {
+	case strings.Contains(text, "no transactions found"), strings.Contains(text, "no records found"):
+		return ErrNoTransactions
+	case strings.Contains(text, "rate limit"):
+		return ErrRateLimited
+	case strings.Contains(text, "invalid api key"), strings.Contains(text, "missing/invalid api key"):
+		return ErrInvalidAPIKey
+	case strings.Contains(text, "result window is too large"):
+		return ErrResultWindowExceeded
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIErrorKinds(t *testing.T) {
+	tests := []struct {
+		message string
+		result  string
+		kind    error
+	}{
+		{"NOTOK", `"Max rate limit reached"`, ErrRateLimited},
+		{"NOTOK", `"Invalid API Key"`, ErrInvalidAPIKey},
+		{"NOTOK", `"Missing/Invalid API Key"`, ErrInvalidAPIKey},
+		{"NOTOK", `"Result window is too large, PageNo x Offset size must be less than or equal to 10000"`, ErrResultWindowExceeded},
+		{"No transactions found", `[]`, ErrNoTransactions},
+		{"No records found", `[]`, ErrNoTransactions},
+		{"NOTOK", `"Error! Invalid address format"`, nil},
+	}
+	for _, tt := range tests {
+		err := apiError(tt.message, json.RawMessage(tt.result))
+		var apiErr *APIError
+		assert.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, tt.kind, errors.Unwrap(err), tt.result)
+	}
+}
+
+func TestStatusError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := NewEtherscanClient("dummy_api_key")
+	client.BaseURL = server.URL
+	client.RetryDelay = time.Millisecond
+	client.MaxRetries = 1
+
+	_, err := client.GetNormalTransactions("0x1", 0, 100)
+	assert.ErrorIs(t, err, ErrRateLimited)
+	var statusErr *StatusError
+	if assert.True(t, errors.As(err, &statusErr)) {
+		assert.Equal(t, http.StatusTooManyRequests, statusErr.StatusCode)
+	}
+	assert.EqualError(t, err, "API request failed with status code: 429 after 1 retries")
+
+	assert.NoError(t, errors.Unwrap(&StatusError{StatusCode: http.StatusForbidden}))
+}
+
+func TestResultWindowExceeded(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"0","message":"NOTOK","result":"Result window is too large, PageNo x Offset size must be less than or equal to 10000"}`))
+	}))
+	defer server.Close()
+
+	client := NewEtherscanClient("dummy_api_key")
+	client.BaseURL = server.URL
+
+	_, err := client.GetNormalTransactionsPaginated("0x1", 0, 100, 11, 1000)
+	assert.ErrorIs(t, err, ErrResultWindowExceeded)
+}
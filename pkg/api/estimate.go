@@ -0,0 +1,132 @@
+package api
+
+import (
+	"math"
+	"time"
+)
+
+// ProbeOffset is the page size of an estimate probe. Etherscan serves at
+// most 10,000 rows per query, so a single probe counts up to that many rows
+// exactly.
+const ProbeOffset = 10000
+
+// pageDelay is the pause between consecutive pages of one transaction type
+const pageDelay = 200 * time.Millisecond
+
+// TypeEstimate is the estimated cost of fetching one transaction type
+type TypeEstimate struct {
+	// Action is the Etherscan action of the type, e.g. txlist
+	Action string
+	// Rows is the estimated number of rows in the range
+	Rows int
+	// Exact is set when the probe returned every row in the range
+	Exact bool
+	// Windows is the number of block windows the range is split into,
+	// across all batches
+	Windows int
+	// Pages is the estimated number of pages, at most DefaultOffset rows
+	// each, with a partial page allowed at the end of every window
+	Pages int
+	// Calls is the estimated number of API calls, including the chain head
+	// lookups made when a range is split into windows
+	Calls int
+	// Duration is the estimated time taken to fetch the type
+	Duration time.Duration
+}
+
+// Estimate probes each transaction type with a single request and
+// estimates the rows, pages, API calls and time a full export of address
+// over [startBlock, endBlock] would take, fetched in batches of batchSize
+// blocks when batchSize is positive. Counts above ProbeOffset are
+// extrapolated from the share of the range the probe covered.
+func (c *EtherscanClient) Estimate(address string, startBlock, endBlock, batchSize int64) ([]TypeEstimate, error) {
+	probes := []func() (TypeEstimate, error){
+		func() (TypeEstimate, error) {
+			return probeType(c, batchSize, "txlist", startBlock, endBlock, func() ([]NormalTransaction, error) {
+				return c.GetNormalTransactionsPaginated(address, startBlock, endBlock, 1, ProbeOffset)
+			}, func(tx NormalTransaction) string { return tx.BlockNumber })
+		},
+		func() (TypeEstimate, error) {
+			return probeType(c, batchSize, "txlistinternal", startBlock, endBlock, func() ([]InternalTransaction, error) {
+				return c.GetInternalTransactionsPaginated(address, startBlock, endBlock, 1, ProbeOffset)
+			}, func(tx InternalTransaction) string { return tx.BlockNumber })
+		},
+		func() (TypeEstimate, error) {
+			return probeType(c, batchSize, "tokentx", startBlock, endBlock, func() ([]ERC20Transaction, error) {
+				return c.GetERC20TransfersPaginated(address, startBlock, endBlock, 1, ProbeOffset)
+			}, func(tx ERC20Transaction) string { return tx.BlockNumber })
+		},
+		func() (TypeEstimate, error) {
+			return probeType(c, batchSize, "tokennfttx", startBlock, endBlock, func() ([]ERC721Transaction, error) {
+				return c.GetERC721TransfersPaginated(address, startBlock, endBlock, 1, ProbeOffset)
+			}, func(tx ERC721Transaction) string { return tx.BlockNumber })
+		},
+		func() (TypeEstimate, error) {
+			return probeType(c, batchSize, "token1155tx", startBlock, endBlock, func() ([]ERC1155Transaction, error) {
+				return c.GetERC1155TransfersPaginated(address, startBlock, endBlock, 1, ProbeOffset)
+			}, func(tx ERC1155Transaction) string { return tx.BlockNumber })
+		},
+	}
+
+	var estimates []TypeEstimate
+	for _, probe := range probes {
+		est, err := probe()
+		if err != nil {
+			return nil, err
+		}
+		estimates = append(estimates, est)
+	}
+	return estimates, nil
+}
+
+// probeType makes one request for up to ProbeOffset rows of action and
+// estimates the cost of fetching every row in the range
+func probeType[T any](c *EtherscanClient, batchSize int64, action string, startBlock, endBlock int64, fetch func() ([]T, error), block func(T) string) (TypeEstimate, error) {
+	started := time.Now()
+	rows, err := fetch()
+	if err != nil {
+		return TypeEstimate{}, err
+	}
+	latency := time.Since(started)
+
+	reached := endBlock
+	if len(rows) == ProbeOffset {
+		reached = parseBlockNumber(block(rows[len(rows)-1]))
+	}
+	est := estimateType(c.Chain.BlockRange(action), batchSize, startBlock, endBlock, len(rows), reached, latency)
+	est.Action = action
+	c.logger().Debug("probed row count", "action", action, "rows", len(rows), "estimate", est.Rows, "exact", est.Exact)
+	return est, nil
+}
+
+// estimateType estimates the cost of fetching a type whose probe returned
+// rows rows covering the range up to block reached, taking latency per
+// request. Every batch and every window of the chain ends with a partial
+// page.
+func estimateType(window, batchSize, startBlock, endBlock int64, rows int, reached int64, latency time.Duration) TypeEstimate {
+	est := TypeEstimate{Rows: rows, Exact: rows < ProbeOffset}
+	if !est.Exact && reached >= startBlock && reached < endBlock {
+		covered := float64(reached-startBlock+1) / float64(endBlock-startBlock+1)
+		est.Rows = int(math.Round(float64(rows) / covered))
+	}
+
+	span := endBlock - startBlock + 1
+	batches, batchSpan := int64(1), span
+	if batchSize > 0 && batchSize < span {
+		batches, batchSpan = (span+batchSize-1)/batchSize, batchSize
+	}
+	est.Windows = int(batches)
+	if window > 0 && batchSpan > window {
+		est.Windows = int(batches * ((batchSpan + window - 1) / window))
+		// fetchInWindows looks up the chain head before splitting a batch
+		est.Calls += int(batches)
+	}
+	est.Pages = (est.Rows+DefaultOffset-1)/DefaultOffset + est.Windows - 1
+	if est.Rows%DefaultOffset == 0 {
+		// A window ending on a full page needs one more, empty page
+		est.Pages++
+	}
+	est.Calls += est.Pages
+	est.Duration = time.Duration(est.Calls)*latency + time.Duration(est.Pages-est.Windows)*pageDelay
+	return est
+}
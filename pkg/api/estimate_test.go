@@ -0,0 +1,77 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateType(t *testing.T) {
+	// Fewer rows than a probe holds are exact: 2,500 rows need three pages
+	est := estimateType(0, 0, 1, 1000, 2500, 1000, 100*time.Millisecond)
+	assert.True(t, est.Exact)
+	assert.Equal(t, 2500, est.Rows)
+	assert.Equal(t, 3, est.Pages)
+	assert.Equal(t, 3, est.Calls)
+	assert.Equal(t, 300*time.Millisecond+2*pageDelay, est.Duration)
+
+	// A full probe reaching a quarter of the range is extrapolated
+	est = estimateType(0, 0, 1, 1000, ProbeOffset, 250, 0)
+	assert.False(t, est.Exact)
+	assert.Equal(t, 40000, est.Rows)
+	assert.Equal(t, 41, est.Pages)
+
+	// Split ranges need a head lookup and at least a page per window
+	est = estimateType(100, 0, 1, 1000, 0, 1000, 0)
+	assert.Equal(t, 10, est.Windows)
+	assert.Equal(t, 10, est.Pages)
+	assert.Equal(t, 11, est.Calls)
+
+	// Batches split the range first, each split further into windows
+	est = estimateType(100, 250, 1, 1000, 0, 1000, 0)
+	assert.Equal(t, 12, est.Windows)
+	assert.Equal(t, 12, est.Pages)
+	assert.Equal(t, 16, est.Calls)
+}
+
+func TestEstimate(t *testing.T) {
+	var actions []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		actions = append(actions, query.Get("action"))
+		assert.Equal(t, "10000", query.Get("offset"))
+
+		rows := []string{}
+		if query.Get("action") == "tokentx" {
+			for i := 0; i < ProbeOffset; i++ {
+				rows = append(rows, fmt.Sprintf(`{"blockNumber":"%d"}`, 1+i/20))
+			}
+		}
+		json.NewEncoder(w).Encode(APIResponse{
+			Status:  "1",
+			Message: "OK",
+			Result:  json.RawMessage("[" + strings.Join(rows, ",") + "]"),
+		})
+	}))
+	defer server.Close()
+
+	client := NewEtherscanClient("dummy_api_key")
+	client.BaseURL = server.URL
+
+	estimates, err := client.Estimate("0xwallet", 1, 1000, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"txlist", "txlistinternal", "tokentx", "tokennfttx", "token1155tx"}, actions)
+	assert.Len(t, estimates, 5)
+	assert.Equal(t, "tokentx", estimates[2].Action)
+	// 10,000 rows reached block 500 of 1,000
+	assert.Equal(t, 20000, estimates[2].Rows)
+	assert.False(t, estimates[2].Exact)
+	assert.Equal(t, 0, estimates[0].Rows)
+	assert.Equal(t, 1, estimates[0].Calls)
+}
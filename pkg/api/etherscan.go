@@ -1,16 +1,23 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"math/big"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
-	"eth-tx-history/pkg/models"
+	"github.com/haridev22/ct-assignement/pkg/audit"
+	"github.com/haridev22/ct-assignement/pkg/chains"
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/haridev22/ct-assignement/pkg/rawarchive"
 )
 
 const (
@@ -25,6 +32,33 @@ type EtherscanClient struct {
 	MaxRetries int
 	RetryDelay time.Duration
 	HTTPClient *http.Client
+	// Chain selects the block windows used to split long ranges
+	Chain chains.Chain
+	// Audit, when set, records every call made by the client
+	Audit *audit.Log
+	// Archive, when set, saves the raw body of every response
+	Archive *rawarchive.Archive
+	// Keys, when set, supplies the API key of every request instead of ApiKey
+	Keys *KeyPool
+	// Context, when set, aborts in-flight requests and retry waits once it is done
+	Context context.Context
+	// Progress, when set, receives every page fetched
+	Progress func(PageEvent)
+	// Logger receives the client's diagnostics. Defaults to slog.Default().
+	Logger *slog.Logger
+	// Budget, when set, caps the requests the client sends. Requests beyond
+	// it fail with ErrBudgetExhausted.
+	Budget *CallBudget
+	// Breaker, when set, pauses all requests after consecutive failures
+	Breaker *CircuitBreaker
+
+	calls    atomic.Int64
+	retries  atomic.Int64
+	keyCalls sync.Map // API key -> *atomic.Int64
+
+	// paceUntil is when rate limit headers allow the next request
+	paceMu    sync.Mutex
+	paceUntil time.Time
 }
 
 // NewEtherscanClient creates a new Etherscan API client
@@ -37,6 +71,7 @@ func NewEtherscanClient(apiKey string) *EtherscanClient {
 		HTTPClient: &http.Client{
 			Timeout: time.Second * 10,
 		},
+		Chain: chains.Ethereum,
 	}
 }
 
@@ -51,8 +86,12 @@ type NormalTransaction struct {
 	GasPrice          string `json:"gasPrice"`
 	GasUsed           string `json:"gasUsed"`
 	IsError           string `json:"isError"`
+	TxReceiptStatus   string `json:"txreceipt_status"`
 	ContractAddress   string `json:"contractAddress"`
 	CumulativeGasUsed string `json:"cumulativeGasUsed"`
+	Nonce             string `json:"nonce"`
+	Gas               string `json:"gas"`
+	TransactionIndex  string `json:"transactionIndex"`
 }
 
 // InternalTransaction represents an internal transaction from Etherscan API
@@ -65,7 +104,9 @@ type InternalTransaction struct {
 	Value           string `json:"value"`
 	ContractAddress string `json:"contractAddress"`
 	Type            string `json:"type"`
+	TraceID         string `json:"traceId"`
 	IsError         string `json:"isError"`
+	Gas             string `json:"gas"`
 }
 
 // ERC20Transaction represents an ERC20 token transfer from Etherscan API
@@ -82,6 +123,9 @@ type ERC20Transaction struct {
 	TokenDecimal      string `json:"tokenDecimal"`
 	GasPrice          string `json:"gasPrice"`
 	GasUsed           string `json:"gasUsed"`
+	Nonce             string `json:"nonce"`
+	Gas               string `json:"gas"`
+	TransactionIndex  string `json:"transactionIndex"`
 }
 
 // ERC721Transaction represents an ERC721 NFT transfer from Etherscan API
@@ -97,6 +141,9 @@ type ERC721Transaction struct {
 	TokenSymbol       string `json:"tokenSymbol"`
 	GasPrice          string `json:"gasPrice"`
 	GasUsed           string `json:"gasUsed"`
+	Nonce             string `json:"nonce"`
+	Gas               string `json:"gas"`
+	TransactionIndex  string `json:"transactionIndex"`
 }
 
 // APIResponse represents the response from Etherscan API
@@ -135,25 +182,32 @@ func (c *EtherscanClient) GetNormalTransactionsPaginated(address string, startBl
 		return nil, err
 	}
 	
-	// Log progress if not empty
-	if len(transactions) > 0 {
-		fmt.Printf("Fetched %d normal transactions (page %d)\n", len(transactions), page)
-	}
 	return transactions, nil
 }
 
-// GetAllNormalTransactions fetches all normal transactions for the given address using pagination
+// GetAllNormalTransactions fetches all normal transactions for the given address using pagination, in block windows sized for the chain (see fetchInWindows)
 func (c *EtherscanClient) GetAllNormalTransactions(address string, startBlock, endBlock int64) ([]NormalTransaction, error) {
+	return fetchInWindows(c, "txlist", startBlock, endBlock, func(start, end int64) ([]NormalTransaction, error) {
+		return c.getAllNormalTransactions(address, start, end)
+	})
+}
+
+// getAllNormalTransactions fetches all normal transactions for the given address within a single block window using pagination
+func (c *EtherscanClient) getAllNormalTransactions(address string, startBlock, endBlock int64) ([]NormalTransaction, error) {
 	var allTransactions []NormalTransaction
 	page := 1
 	batchSize := DefaultOffset
 
 	for {
-		fmt.Printf("Fetching normal transactions page %d...\n", page)
 		transactions, err := c.GetNormalTransactionsPaginated(address, startBlock, endBlock, page, batchSize)
 		if err != nil {
 			return nil, err
 		}
+		reached := endBlock
+		if len(transactions) == batchSize {
+			reached = parseBlockNumber(transactions[len(transactions)-1].BlockNumber)
+		}
+		c.reportPage("txlist", page, len(transactions), reached)
 		
 		allTransactions = append(allTransactions, transactions...)
 		
@@ -167,7 +221,7 @@ func (c *EtherscanClient) GetAllNormalTransactions(address string, startBlock, e
 		time.Sleep(200 * time.Millisecond)
 	}
 	
-	fmt.Printf("Total normal transactions fetched: %d\n", len(allTransactions))
+	c.logger().Debug("fetched all pages", "action", "txlist", "rows", len(allTransactions))
 	return allTransactions, nil
 }
 
@@ -194,25 +248,32 @@ func (c *EtherscanClient) GetInternalTransactionsPaginated(address string, start
 		return nil, err
 	}
 	
-	// Log progress if not empty
-	if len(transactions) > 0 {
-		fmt.Printf("Fetched %d internal transactions (page %d)\n", len(transactions), page)
-	}
 	return transactions, nil
 }
 
-// GetAllInternalTransactions fetches all internal transactions for the given address using pagination
+// GetAllInternalTransactions fetches all internal transactions for the given address using pagination, in block windows sized for the chain (see fetchInWindows)
 func (c *EtherscanClient) GetAllInternalTransactions(address string, startBlock, endBlock int64) ([]InternalTransaction, error) {
+	return fetchInWindows(c, "txlistinternal", startBlock, endBlock, func(start, end int64) ([]InternalTransaction, error) {
+		return c.getAllInternalTransactions(address, start, end)
+	})
+}
+
+// getAllInternalTransactions fetches all internal transactions for the given address within a single block window using pagination
+func (c *EtherscanClient) getAllInternalTransactions(address string, startBlock, endBlock int64) ([]InternalTransaction, error) {
 	var allTransactions []InternalTransaction
 	page := 1
 	batchSize := DefaultOffset
 
 	for {
-		fmt.Printf("Fetching internal transactions page %d...\n", page)
 		transactions, err := c.GetInternalTransactionsPaginated(address, startBlock, endBlock, page, batchSize)
 		if err != nil {
 			return nil, err
 		}
+		reached := endBlock
+		if len(transactions) == batchSize {
+			reached = parseBlockNumber(transactions[len(transactions)-1].BlockNumber)
+		}
+		c.reportPage("txlistinternal", page, len(transactions), reached)
 		
 		allTransactions = append(allTransactions, transactions...)
 		
@@ -226,7 +287,7 @@ func (c *EtherscanClient) GetAllInternalTransactions(address string, startBlock,
 		time.Sleep(200 * time.Millisecond)
 	}
 	
-	fmt.Printf("Total internal transactions fetched: %d\n", len(allTransactions))
+	c.logger().Debug("fetched all pages", "action", "txlistinternal", "rows", len(allTransactions))
 	return allTransactions, nil
 }
 
@@ -253,25 +314,32 @@ func (c *EtherscanClient) GetERC20TransfersPaginated(address string, startBlock,
 		return nil, err
 	}
 	
-	// Log progress if not empty
-	if len(transactions) > 0 {
-		fmt.Printf("Fetched %d ERC20 token transfers (page %d)\n", len(transactions), page)
-	}
 	return transactions, nil
 }
 
-// GetAllERC20Transfers fetches all ERC20 token transfers for the given address using pagination
+// GetAllERC20Transfers fetches all ERC20 token transfers for the given address using pagination, in block windows sized for the chain (see fetchInWindows)
 func (c *EtherscanClient) GetAllERC20Transfers(address string, startBlock, endBlock int64) ([]ERC20Transaction, error) {
+	return fetchInWindows(c, "tokentx", startBlock, endBlock, func(start, end int64) ([]ERC20Transaction, error) {
+		return c.getAllERC20Transfers(address, start, end)
+	})
+}
+
+// getAllERC20Transfers fetches all ERC20 token transfers for the given address within a single block window using pagination
+func (c *EtherscanClient) getAllERC20Transfers(address string, startBlock, endBlock int64) ([]ERC20Transaction, error) {
 	var allTransactions []ERC20Transaction
 	page := 1
 	batchSize := DefaultOffset
 
 	for {
-		fmt.Printf("Fetching ERC20 token transfers page %d...\n", page)
 		transactions, err := c.GetERC20TransfersPaginated(address, startBlock, endBlock, page, batchSize)
 		if err != nil {
 			return nil, err
 		}
+		reached := endBlock
+		if len(transactions) == batchSize {
+			reached = parseBlockNumber(transactions[len(transactions)-1].BlockNumber)
+		}
+		c.reportPage("tokentx", page, len(transactions), reached)
 		
 		allTransactions = append(allTransactions, transactions...)
 		
@@ -285,7 +353,7 @@ func (c *EtherscanClient) GetAllERC20Transfers(address string, startBlock, endBl
 		time.Sleep(200 * time.Millisecond)
 	}
 	
-	fmt.Printf("Total ERC20 token transfers fetched: %d\n", len(allTransactions))
+	c.logger().Debug("fetched all pages", "action", "tokentx", "rows", len(allTransactions))
 	return allTransactions, nil
 }
 
@@ -312,25 +380,32 @@ func (c *EtherscanClient) GetERC721TransfersPaginated(address string, startBlock
 		return nil, err
 	}
 	
-	// Log progress if not empty
-	if len(transactions) > 0 {
-		fmt.Printf("Fetched %d ERC721 NFT transfers (page %d)\n", len(transactions), page)
-	}
 	return transactions, nil
 }
 
-// GetAllERC721Transfers fetches all ERC721 NFT transfers for the given address using pagination
+// GetAllERC721Transfers fetches all ERC721 NFT transfers for the given address using pagination, in block windows sized for the chain (see fetchInWindows)
 func (c *EtherscanClient) GetAllERC721Transfers(address string, startBlock, endBlock int64) ([]ERC721Transaction, error) {
+	return fetchInWindows(c, "tokennfttx", startBlock, endBlock, func(start, end int64) ([]ERC721Transaction, error) {
+		return c.getAllERC721Transfers(address, start, end)
+	})
+}
+
+// getAllERC721Transfers fetches all ERC721 NFT transfers for the given address within a single block window using pagination
+func (c *EtherscanClient) getAllERC721Transfers(address string, startBlock, endBlock int64) ([]ERC721Transaction, error) {
 	var allTransactions []ERC721Transaction
 	page := 1
 	batchSize := DefaultOffset
 
 	for {
-		fmt.Printf("Fetching ERC721 NFT transfers page %d...\n", page)
 		transactions, err := c.GetERC721TransfersPaginated(address, startBlock, endBlock, page, batchSize)
 		if err != nil {
 			return nil, err
 		}
+		reached := endBlock
+		if len(transactions) == batchSize {
+			reached = parseBlockNumber(transactions[len(transactions)-1].BlockNumber)
+		}
+		c.reportPage("tokennfttx", page, len(transactions), reached)
 		
 		allTransactions = append(allTransactions, transactions...)
 		
@@ -344,50 +419,83 @@ func (c *EtherscanClient) GetAllERC721Transfers(address string, startBlock, endB
 		time.Sleep(200 * time.Millisecond)
 	}
 	
-	fmt.Printf("Total ERC721 NFT transfers fetched: %d\n", len(allTransactions))
+	c.logger().Debug("fetched all pages", "action", "tokennfttx", "rows", len(allTransactions))
 	return allTransactions, nil
 }
 
 // makeRequest makes an HTTP request to the Etherscan API with retries and exponential backoff
-func (c *EtherscanClient) makeRequest(url string) ([]byte, error) {
+func (c *EtherscanClient) makeRequest(params url.Values) ([]byte, error) {
 	var resp *http.Response
 	var err error
 	var body []byte
 	retries := 0
 	delay := c.RetryDelay
+	url := fmt.Sprintf("%s?%s", c.BaseURL, params.Encode())
 
 	for retries <= c.MaxRetries {
-		resp, err = c.HTTPClient.Get(url)
+		if err := c.awaitBreaker(); err != nil {
+			return nil, err
+		}
+		if err := c.pace(); err != nil {
+			return nil, err
+		}
+		var req *http.Request
+		req, err = http.NewRequestWithContext(c.context(), http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.countCall(params.Get("apikey")); err != nil {
+			return nil, err
+		}
+		resp, err = c.HTTPClient.Do(req)
 		if err != nil {
+			if c.context().Err() != nil {
+				return nil, err
+			}
+			c.recordOutcome(false)
 			retries++
 			if retries > c.MaxRetries {
 				return nil, err
 			}
-			fmt.Printf("Request failed (attempt %d/%d): %s. Retrying in %v...\n", 
-				retries, c.MaxRetries, err.Error(), delay)
-			time.Sleep(delay)
+			c.logger().Warn("request failed, retrying",
+				"attempt", retries, "max_retries", c.MaxRetries, "error", err, "delay", delay)
+			c.retries.Add(1)
+			if err := c.wait(delay); err != nil {
+				return nil, err
+			}
 			delay *= 2 // Exponential backoff
 			continue
 		}
 		defer resp.Body.Close()
+		c.observeRateLimit(resp)
 
 		// Check if we hit rate limits (status code 429) or other server errors (5xx)
 		if resp.StatusCode == 429 || resp.StatusCode >= 500 {
+			c.recordOutcome(false)
 			retries++
 			if retries > c.MaxRetries {
-				return nil, fmt.Errorf("API request failed with status code: %d after %d retries", 
-					resp.StatusCode, retries-1)
+				return nil, &StatusError{StatusCode: resp.StatusCode, Retries: retries - 1}
+			}
+			// The provider's Retry-After takes precedence over the backoff schedule
+			wait := delay
+			if after, ok := retryAfter(resp.Header, time.Now()); ok {
+				wait = after
+			}
+			c.logger().Warn("rate limit hit or server error, retrying",
+				"attempt", retries, "max_retries", c.MaxRetries, "status", resp.StatusCode, "delay", wait)
+			c.retries.Add(1)
+			if err := c.wait(wait); err != nil {
+				return nil, err
 			}
-			fmt.Printf("Rate limit hit or server error (attempt %d/%d): status %d. Retrying in %v...\n", 
-				retries, c.MaxRetries, resp.StatusCode, delay)
-			time.Sleep(delay)
 			delay *= 2 // Exponential backoff
 			continue
 		}
 
 		if resp.StatusCode != http.StatusOK {
-			return nil, fmt.Errorf("API request failed with status code: %d", resp.StatusCode)
+			c.recordOutcome(false)
+			return nil, &StatusError{StatusCode: resp.StatusCode}
 		}
+		c.recordOutcome(true)
 
 		body, err = io.ReadAll(resp.Body)
 		if err != nil {
@@ -401,20 +509,28 @@ func (c *EtherscanClient) makeRequest(url string) ([]byte, error) {
 }
 
 // requestWithRetry makes a request to the Etherscan API with retries and exponential backoff
-func (c *EtherscanClient) requestWithRetry(params url.Values, result interface{}) error {
-	apiURL := fmt.Sprintf("%s?%s", c.BaseURL, params.Encode())
-	body, err := c.makeRequest(apiURL)
+func (c *EtherscanClient) requestWithRetry(params url.Values, result interface{}) (err error) {
+	start := time.Now()
+	var apiResp APIResponse
+	defer func() { c.recordCall(params, start, apiResp.Result, err) }()
+
+	body, err := c.send(params)
 	if err != nil {
 		return err
 	}
+	c.archive(params, body)
 
-	var apiResp APIResponse
 	if err := json.Unmarshal(body, &apiResp); err != nil {
 		return err
 	}
 
 	if apiResp.Status != "1" {
-		return fmt.Errorf("API returned error: %s", apiResp.Message)
+		err := apiError(apiResp.Message, apiResp.Result)
+		// Etherscan reports an empty result set with status 0
+		if errors.Is(err, ErrNoTransactions) {
+			return nil
+		}
+		return err
 	}
 
 	if err := json.Unmarshal(apiResp.Result, result); err != nil {
@@ -424,91 +540,162 @@ func (c *EtherscanClient) requestWithRetry(params url.Values, result interface{}
 	return nil
 }
 
+// normalTxStatus derives the execution status of a normal transaction.
+// isError covers reverts; txreceipt_status is "0" for failed post-Byzantium receipts.
+func normalTxStatus(tx NormalTransaction) models.TransactionStatus {
+	if tx.IsError == "1" || tx.TxReceiptStatus == "0" {
+		return models.StatusFailed
+	}
+	return models.StatusSuccess
+}
+
+// statusFromIsError maps Etherscan's isError flag to a transaction status
+func statusFromIsError(isError string) models.TransactionStatus {
+	if isError == "1" {
+		return models.StatusFailed
+	}
+	return models.StatusSuccess
+}
+
 // ConvertNormalTxToModel converts a normal transaction to a generic transaction model
 func ConvertNormalTxToModel(tx NormalTransaction) (models.Transaction, error) {
+	return Converter{}.NormalTx(tx)
+}
+
+// NormalTx converts a normal transaction to a generic transaction model
+func (c Converter) NormalTx(tx NormalTransaction) (models.Transaction, error) {
 	timestamp, err := strconv.ParseInt(tx.TimeStamp, 10, 64)
 	if err != nil {
 		return models.Transaction{}, err
 	}
 
+	blockNumber, err := strconv.ParseInt(tx.BlockNumber, 10, 64)
+	if err != nil {
+		return models.Transaction{}, err
+	}
+
 	// Calculate gas fee
-	gasPrice, _ := new(big.Int).SetString(tx.GasPrice, 10)
-	gasUsed, _ := new(big.Int).SetString(tx.GasUsed, 10)
-	gasFee := new(big.Int).Mul(gasPrice, gasUsed)
-	
-	// Convert wei to ETH (1 ETH = 10^18 wei)
-	weiPerEth := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil))
-	gasFeeEth := new(big.Float).Quo(new(big.Float).SetInt(gasFee), weiPerEth)
-	
-	// Format to 18 decimal places
-	gasFeeStr := gasFeeEth.Text('f', 18)
-	
+	gasFee, err := c.gasFee(tx.GasPrice, tx.GasUsed)
+	if err != nil {
+		return models.Transaction{}, err
+	}
+
+	// Convert wei to ETH (1 ETH = 10^18 wei), exactly to 18 decimal places
+	gasFeeStr := FormatWeiAsEth(gasFee)
+
 	// Convert wei value to ETH
-	valueWei, _ := new(big.Int).SetString(tx.Value, 10)
-	valueEth := new(big.Float).Quo(new(big.Float).SetInt(valueWei), weiPerEth)
-	valueStr := valueEth.Text('f', 18)
+	valueWei, err := c.amount("value", tx.Value)
+	if err != nil {
+		return models.Transaction{}, err
+	}
+	valueStr := FormatWeiAsEth(valueWei)
 
 	return models.Transaction{
-		Hash:      tx.Hash,
-		Timestamp: time.Unix(timestamp, 0),
-		From:      tx.From,
-		To:        tx.To,
-		Type:      models.TypeEthTransfer,
-		Value:     valueStr,
-		GasFee:    gasFeeStr,
+		Hash:             tx.Hash,
+		BlockNumber:      blockNumber,
+		Timestamp:        time.Unix(timestamp, 0),
+		From:             tx.From,
+		To:               tx.To,
+		Type:             models.TypeEthTransfer,
+		AssetSymbol:      c.NativeSymbol,
+		Value:            valueStr,
+		GasFee:           gasFeeStr,
+		RawValue:         valueWei.String(),
+		RawGasFee:        gasFee.String(),
+		GasPrice:         tx.GasPrice,
+		Status:           normalTxStatus(tx),
+		Nonce:            tx.Nonce,
+		GasLimit:         tx.Gas,
+		TransactionIndex: tx.TransactionIndex,
+		Chain:            c.Chain,
 	}, nil
 }
 
 // ConvertInternalTxToModel converts an internal transaction to a generic transaction model
 func ConvertInternalTxToModel(tx InternalTransaction) (models.Transaction, error) {
+	return Converter{}.InternalTx(tx)
+}
+
+// InternalTx converts an internal transaction to a generic transaction model
+func (c Converter) InternalTx(tx InternalTransaction) (models.Transaction, error) {
 	timestamp, err := strconv.ParseInt(tx.TimeStamp, 10, 64)
 	if err != nil {
 		return models.Transaction{}, err
 	}
 
+	blockNumber, err := strconv.ParseInt(tx.BlockNumber, 10, 64)
+	if err != nil {
+		return models.Transaction{}, err
+	}
+
 	// Convert wei value to ETH
-	valueWei, _ := new(big.Int).SetString(tx.Value, 10)
-	weiPerEth := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil))
-	valueEth := new(big.Float).Quo(new(big.Float).SetInt(valueWei), weiPerEth)
-	valueStr := valueEth.Text('f', 18)
+	valueWei, err := c.amount("value", tx.Value)
+	if err != nil {
+		return models.Transaction{}, err
+	}
+	valueStr := FormatWeiAsEth(valueWei)
 
 	return models.Transaction{
-		Hash:      tx.Hash,
-		Timestamp: time.Unix(timestamp, 0),
-		From:      tx.From,
-		To:        tx.To,
-		Type:      models.TypeInternalTx,
-		Value:     valueStr,
-		GasFee:    "0", // Gas fees are paid by the parent transaction
+		Hash:        tx.Hash,
+		BlockNumber: blockNumber,
+		Timestamp:   time.Unix(timestamp, 0),
+		From:        tx.From,
+		To:          tx.To,
+		Type:        models.TypeInternalTx,
+		AssetSymbol: c.NativeSymbol,
+		Value:       valueStr,
+		GasFee:      "0", // Gas fees are paid by the parent transaction
+		RawValue:    valueWei.String(),
+		RawGasFee:   "0",
+		Status:      statusFromIsError(tx.IsError),
+		GasLimit:    tx.Gas,
+		ParentHash:  tx.Hash,
+		TraceID:     tx.TraceID,
+		CallType:    tx.Type,
+		Chain:       c.Chain,
 	}, nil
 }
 
 // ConvertERC20TxToModel converts an ERC20 transaction to a generic transaction model
 func ConvertERC20TxToModel(tx ERC20Transaction) (models.Transaction, error) {
+	return Converter{}.ERC20Tx(tx)
+}
+
+// ERC20Tx converts an ERC20 transaction to a generic transaction model
+func (c Converter) ERC20Tx(tx ERC20Transaction) (models.Transaction, error) {
 	timestamp, err := strconv.ParseInt(tx.TimeStamp, 10, 64)
 	if err != nil {
 		return models.Transaction{}, err
 	}
 
+	blockNumber, err := strconv.ParseInt(tx.BlockNumber, 10, 64)
+	if err != nil {
+		return models.Transaction{}, err
+	}
+
 	// Calculate gas fee
-	gasPrice, _ := new(big.Int).SetString(tx.GasPrice, 10)
-	gasUsed, _ := new(big.Int).SetString(tx.GasUsed, 10)
-	gasFee := new(big.Int).Mul(gasPrice, gasUsed)
-	
+	gasFee, err := c.gasFee(tx.GasPrice, tx.GasUsed)
+	if err != nil {
+		return models.Transaction{}, err
+	}
+
 	// Convert wei to ETH for gas fee
-	weiPerEth := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil))
-	gasFeeEth := new(big.Float).Quo(new(big.Float).SetInt(gasFee), weiPerEth)
-	gasFeeStr := gasFeeEth.Text('f', 18)
+	gasFeeStr := FormatWeiAsEth(gasFee)
 
 	// Convert token value based on decimals
-	tokenDecimals, _ := strconv.Atoi(tx.TokenDecimal)
-	tokenValue, _ := new(big.Int).SetString(tx.Value, 10)
-	divisor := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(tokenDecimals)), nil))
-	actualValue := new(big.Float).Quo(new(big.Float).SetInt(tokenValue), divisor)
-	valueStr := actualValue.Text('f', tokenDecimals)
+	tokenDecimals, err := c.decimals(tx.TokenDecimal)
+	if err != nil {
+		return models.Transaction{}, err
+	}
+	tokenValue, err := c.amount("value", tx.Value)
+	if err != nil {
+		return models.Transaction{}, err
+	}
+	valueStr := FormatTokenAmount(tokenValue, tokenDecimals)
 
 	return models.Transaction{
 		Hash:              tx.Hash,
+		BlockNumber:       blockNumber,
 		Timestamp:         time.Unix(timestamp, 0),
 		From:              tx.From,
 		To:                tx.To,
@@ -517,28 +704,46 @@ func ConvertERC20TxToModel(tx ERC20Transaction) (models.Transaction, error) {
 		AssetSymbol:       tx.TokenSymbol,
 		Value:             valueStr,
 		GasFee:            gasFeeStr,
+		RawValue:          tokenValue.String(),
+		RawGasFee:         gasFee.String(),
+		GasPrice:          tx.GasPrice,
+		Status:            models.StatusSuccess, // Transfer events are only emitted by successful transactions
+		Nonce:             tx.Nonce,
+		GasLimit:          tx.Gas,
+		TransactionIndex:  tx.TransactionIndex,
+		Chain:             c.Chain,
 	}, nil
 }
 
 // ConvertERC721TxToModel converts an ERC721 transaction to a generic transaction model
 func ConvertERC721TxToModel(tx ERC721Transaction) (models.Transaction, error) {
+	return Converter{}.ERC721Tx(tx)
+}
+
+// ERC721Tx converts an ERC721 transaction to a generic transaction model
+func (c Converter) ERC721Tx(tx ERC721Transaction) (models.Transaction, error) {
 	timestamp, err := strconv.ParseInt(tx.TimeStamp, 10, 64)
 	if err != nil {
 		return models.Transaction{}, err
 	}
 
+	blockNumber, err := strconv.ParseInt(tx.BlockNumber, 10, 64)
+	if err != nil {
+		return models.Transaction{}, err
+	}
+
 	// Calculate gas fee
-	gasPrice, _ := new(big.Int).SetString(tx.GasPrice, 10)
-	gasUsed, _ := new(big.Int).SetString(tx.GasUsed, 10)
-	gasFee := new(big.Int).Mul(gasPrice, gasUsed)
-	
+	gasFee, err := c.gasFee(tx.GasPrice, tx.GasUsed)
+	if err != nil {
+		return models.Transaction{}, err
+	}
+
 	// Convert wei to ETH for gas fee
-	weiPerEth := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil))
-	gasFeeEth := new(big.Float).Quo(new(big.Float).SetInt(gasFee), weiPerEth)
-	gasFeeStr := gasFeeEth.Text('f', 18)
+	gasFeeStr := FormatWeiAsEth(gasFee)
 
 	return models.Transaction{
 		Hash:              tx.Hash,
+		BlockNumber:       blockNumber,
 		Timestamp:         time.Unix(timestamp, 0),
 		From:              tx.From,
 		To:                tx.To,
@@ -548,5 +753,13 @@ func ConvertERC721TxToModel(tx ERC721Transaction) (models.Transaction, error) {
 		TokenID:           tx.TokenID,
 		Value:             "1", // NFTs have a quantity of 1
 		GasFee:            gasFeeStr,
+		RawValue:          "1",
+		RawGasFee:         gasFee.String(),
+		GasPrice:          tx.GasPrice,
+		Status:            models.StatusSuccess, // Transfer events are only emitted by successful transactions
+		Nonce:             tx.Nonce,
+		GasLimit:          tx.Gas,
+		TransactionIndex:  tx.TransactionIndex,
+		Chain:             c.Chain,
 	}, nil
 }
@@ -10,13 +10,14 @@ import (
 	"testing"
 	"time"
 
-	"eth-tx-history/pkg/models"
+	"github.com/haridev22/ct-assignement/pkg/models"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestConvertNormalTxToModel(t *testing.T) {
 	// Test case: Regular ETH transaction
 	tx := NormalTransaction{
+		BlockNumber:       "12345",
 		Hash:              "0x123abc",
 		TimeStamp:         "1630000000",
 		From:              "0xsender",
@@ -24,17 +25,36 @@ func TestConvertNormalTxToModel(t *testing.T) {
 		Value:             "1000000000000000000", // 1 ETH
 		GasPrice:          "20000000000", // 20 Gwei
 		GasUsed:           "21000", // Standard ETH transfer gas
+		Nonce:             "42",
+		Gas:               "21000",
+		TransactionIndex:  "7",
 	}
 
 	result, err := ConvertNormalTxToModel(tx)
 	assert.NoError(t, err)
 	assert.Equal(t, "0x123abc", result.Hash)
+	assert.Equal(t, int64(12345), result.BlockNumber)
 	assert.Equal(t, time.Unix(1630000000, 0), result.Timestamp)
 	assert.Equal(t, "0xsender", result.From)
 	assert.Equal(t, "0xreceiver", result.To)
 	assert.Equal(t, models.TypeEthTransfer, result.Type)
 	assert.Equal(t, "1.000000000000000000", result.Value)
 	assert.Equal(t, "0.000420000000000000", result.GasFee)
+	assert.Equal(t, "1000000000000000000", result.RawValue)
+	assert.Equal(t, "420000000000000", result.RawGasFee)
+	assert.Equal(t, "42", result.Nonce)
+	assert.Equal(t, "21000", result.GasLimit)
+	assert.Equal(t, "7", result.TransactionIndex)
+	assert.Equal(t, models.StatusSuccess, result.Status)
+
+	// Test case: Reverted transaction still pays gas
+	txFailed := tx
+	txFailed.IsError = "1"
+	txFailed.TxReceiptStatus = "0"
+	result, err = ConvertNormalTxToModel(txFailed)
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusFailed, result.Status)
+	assert.Equal(t, "0.000420000000000000", result.GasFee)
 
 	// Test case: Invalid timestamp
 	txInvalid := NormalTransaction{
@@ -42,11 +62,43 @@ func TestConvertNormalTxToModel(t *testing.T) {
 	}
 	_, err = ConvertNormalTxToModel(txInvalid)
 	assert.Error(t, err)
+
+	// Test case: Invalid block number
+	txInvalidBlock := NormalTransaction{
+		BlockNumber: "invalid",
+		TimeStamp:   "1630000000",
+	}
+	_, err = ConvertNormalTxToModel(txInvalidBlock)
+	assert.Error(t, err)
+}
+
+func TestConvertInternalTxToModel(t *testing.T) {
+	tx := InternalTransaction{
+		BlockNumber: "12345",
+		TimeStamp:   "1630000000",
+		Hash:        "0xparent",
+		From:        "0xrouter",
+		To:          "0xwallet",
+		Value:       "500000000000000000",
+		Type:        "call",
+		TraceID:     "0_1_1",
+		IsError:     "0",
+	}
+
+	result, err := ConvertInternalTxToModel(tx)
+	assert.NoError(t, err)
+	assert.Equal(t, models.TypeInternalTx, result.Type)
+	assert.Equal(t, "0xparent", result.ParentHash)
+	assert.Equal(t, "0_1_1", result.TraceID)
+	assert.Equal(t, "call", result.CallType)
+	assert.Equal(t, "0.500000000000000000", result.Value)
+	assert.Equal(t, "0", result.GasFee)
 }
 
 func TestConvertERC20TxToModel(t *testing.T) {
 	// Test case: Regular ERC20 token transaction
 	tx := ERC20Transaction{
+		BlockNumber:       "12345",
 		Hash:              "0x456def",
 		TimeStamp:         "1630000000",
 		From:              "0xsender",
@@ -57,11 +109,15 @@ func TestConvertERC20TxToModel(t *testing.T) {
 		Value:             "1000000000000000000", // 1 token
 		GasPrice:          "20000000000", // 20 Gwei
 		GasUsed:           "65000", // ERC-20 transfer gas
+		Nonce:             "8",
+		Gas:               "90000",
+		TransactionIndex:  "2",
 	}
 
 	result, err := ConvertERC20TxToModel(tx)
 	assert.NoError(t, err)
 	assert.Equal(t, "0x456def", result.Hash)
+	assert.Equal(t, int64(12345), result.BlockNumber)
 	assert.Equal(t, time.Unix(1630000000, 0), result.Timestamp)
 	assert.Equal(t, "0xsender", result.From)
 	assert.Equal(t, "0xreceiver", result.To)
@@ -69,11 +125,16 @@ func TestConvertERC20TxToModel(t *testing.T) {
 	assert.Equal(t, "0xtoken", result.AssetContractAddr)
 	assert.Equal(t, "TEST", result.AssetSymbol)
 	assert.Equal(t, "1.000000000000000000", result.Value)
+	assert.Equal(t, "1000000000000000000", result.RawValue)
+	assert.Equal(t, "8", result.Nonce)
+	assert.Equal(t, "90000", result.GasLimit)
+	assert.Equal(t, "2", result.TransactionIndex)
 }
 
 func TestConvertERC721TxToModel(t *testing.T) {
 	// Test case: NFT transfer
 	tx := ERC721Transaction{
+		BlockNumber:       "12345",
 		Hash:              "0x789ghi",
 		TimeStamp:         "1630000000",
 		From:              "0xsender",
@@ -88,6 +149,7 @@ func TestConvertERC721TxToModel(t *testing.T) {
 	result, err := ConvertERC721TxToModel(tx)
 	assert.NoError(t, err)
 	assert.Equal(t, "0x789ghi", result.Hash)
+	assert.Equal(t, int64(12345), result.BlockNumber)
 	assert.Equal(t, time.Unix(1630000000, 0), result.Timestamp)
 	assert.Equal(t, "0xsender", result.From)
 	assert.Equal(t, "0xreceiver", result.To)
@@ -359,3 +421,17 @@ func TestEtherscanClient_makeRequest(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "Error!")
 }
+
+func TestGetNormalTransactions_NoResults(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"0","message":"No transactions found","result":[]}`))
+	}))
+	defer server.Close()
+
+	client := NewEtherscanClient("dummy_api_key")
+	client.BaseURL = server.URL
+
+	txs, err := client.GetNormalTransactions("0xtest", 0, 100)
+	assert.NoError(t, err)
+	assert.Empty(t, txs)
+}
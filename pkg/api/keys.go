@@ -0,0 +1,158 @@
+package api
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCallsPerSecond is the rate limit of a free Etherscan API key
+const DefaultCallsPerSecond = 5
+
+// KeyPool spreads requests over several API keys, keeping each key under
+// its own rate limit. It is safe for concurrent use.
+type KeyPool struct {
+	mu       sync.Mutex
+	keys     []*pooledKey
+	interval time.Duration
+	next     int
+
+	// now and sleep are replaced in tests
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+type pooledKey struct {
+	key         string
+	nextFree    time.Time
+	calls       int
+	rateLimited int
+}
+
+// KeyUsage reports how much a pooled key was used
+type KeyUsage struct {
+	// Key is masked, so usage can be logged
+	Key         string
+	Calls       int
+	RateLimited int
+}
+
+// NewKeyPool creates a pool allowing callsPerSecond requests per key
+func NewKeyPool(keys []string, callsPerSecond float64) *KeyPool {
+	if callsPerSecond <= 0 {
+		callsPerSecond = DefaultCallsPerSecond
+	}
+	pool := &KeyPool{
+		interval: time.Duration(float64(time.Second) / callsPerSecond),
+		now:      time.Now,
+		sleep:    time.Sleep,
+	}
+	for _, key := range keys {
+		pool.keys = append(pool.keys, &pooledKey{key: key})
+	}
+	return pool
+}
+
+// Len returns the number of keys in the pool
+func (p *KeyPool) Len() int {
+	return len(p.keys)
+}
+
+// Acquire returns the key that is free soonest, rotating between keys that
+// are equally free, and waits until a request with it stays within its limit
+func (p *KeyPool) Acquire() string {
+	p.mu.Lock()
+	now := p.now()
+	best := -1
+	for i := range p.keys {
+		idx := (p.next + i) % len(p.keys)
+		if best < 0 || p.keys[idx].nextFree.Before(p.keys[best].nextFree) {
+			best = idx
+		}
+	}
+	k := p.keys[best]
+	slot := k.nextFree
+	if slot.Before(now) {
+		slot = now
+	}
+	k.nextFree = slot.Add(p.interval)
+	k.calls++
+	p.next = (best + 1) % len(p.keys)
+	p.mu.Unlock()
+
+	if wait := slot.Sub(now); wait > 0 {
+		p.sleep(wait)
+	}
+	return k.key
+}
+
+// Backoff keeps key out of rotation for d after the provider rate limited it
+func (p *KeyPool) Backoff(key string, d time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, k := range p.keys {
+		if k.key == key {
+			k.rateLimited++
+			if until := p.now().Add(d); k.nextFree.Before(until) {
+				k.nextFree = until
+			}
+		}
+	}
+}
+
+// Usage returns the calls made with each key, in pool order
+func (p *KeyPool) Usage() []KeyUsage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	usage := make([]KeyUsage, len(p.keys))
+	for i, k := range p.keys {
+		usage[i] = KeyUsage{Key: MaskKey(k.key), Calls: k.calls, RateLimited: k.rateLimited}
+	}
+	return usage
+}
+
+// MaskKey shortens an API key to its first four characters for display
+func MaskKey(key string) string {
+	if len(key) <= 4 {
+		return strings.Repeat("*", len(key))
+	}
+	return key[:4] + "****"
+}
+
+// send performs the request described by params, using the next key of the
+// pool when the client has one. A response reporting that the key hit its
+// rate limit is retried with another key.
+func (c *EtherscanClient) send(params url.Values) ([]byte, error) {
+	attempts := 1
+	if c.Keys != nil {
+		attempts += c.Keys.Len()
+	}
+	for attempt := 1; ; attempt++ {
+		key := ""
+		if c.Keys != nil {
+			key = c.Keys.Acquire()
+			params.Set("apikey", key)
+		}
+		body, err := c.makeRequest(params)
+		if err != nil || key == "" || attempt >= attempts || !isRateLimited(body) {
+			return body, err
+		}
+		c.Keys.Backoff(key, time.Second)
+		c.retries.Add(1)
+	}
+}
+
+// isRateLimited reports whether body is Etherscan's rate limit error, which
+// is returned with HTTP status 200
+func isRateLimited(body []byte) bool {
+	var resp struct {
+		Status string          `json:"status"`
+		Result json.RawMessage `json:"result"`
+	}
+	if json.Unmarshal(body, &resp) != nil || resp.Status != "0" {
+		return false
+	}
+	return strings.Contains(strings.ToLower(string(resp.Result)), "rate limit")
+}
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeClock makes a KeyPool sleep on a simulated clock
+func fakeClock(pool *KeyPool) *time.Time {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	pool.now = func() time.Time { return now }
+	pool.sleep = func(d time.Duration) { now = now.Add(d) }
+	return &now
+}
+
+func TestKeyPool_Acquire(t *testing.T) {
+	pool := NewKeyPool([]string{"key-a", "key-b"}, 2)
+	now := fakeClock(pool)
+	start := *now
+
+	var got []string
+	for i := 0; i < 6; i++ {
+		got = append(got, pool.Acquire())
+	}
+	assert.Equal(t, []string{"key-a", "key-b", "key-a", "key-b", "key-a", "key-b"}, got)
+	// Two keys at 2 calls/s each serve six calls in one second
+	assert.Equal(t, time.Second, now.Sub(start))
+
+	pool.Backoff("key-a", 10*time.Second)
+	assert.Equal(t, "key-b", pool.Acquire())
+	assert.Equal(t, "key-b", pool.Acquire(), "a rate limited key is skipped while it cools down")
+
+	assert.Equal(t, []KeyUsage{
+		{Key: "key-****", Calls: 3, RateLimited: 1},
+		{Key: "key-****", Calls: 5},
+	}, pool.Usage())
+}
+
+func TestEtherscanClient_KeyPoolRetriesRateLimit(t *testing.T) {
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.URL.Query().Get("apikey")
+		keys = append(keys, key)
+		if key == "limited" {
+			w.Write([]byte(`{"status":"0","message":"NOTOK","result":"Max rate limit reached"}`))
+			return
+		}
+		json.NewEncoder(w).Encode(APIResponse{Status: "1", Message: "OK", Result: json.RawMessage(`[{"hash":"0x1"}]`)})
+	}))
+	defer server.Close()
+
+	client := NewEtherscanClient("")
+	client.BaseURL = server.URL
+	client.Keys = NewKeyPool([]string{"limited", "fresh"}, 1000)
+
+	txs, err := client.GetNormalTransactionsPaginated("0xabc", 0, 100, 1, 100)
+	assert.NoError(t, err)
+	assert.Len(t, txs, 1)
+	assert.Equal(t, []string{"limited", "fresh"}, keys)
+	assert.Equal(t, 1, client.Keys.Usage()[0].RateLimited)
+}
+
+func TestMaskKey(t *testing.T) {
+	assert.Equal(t, "ABCD****", MaskKey("ABCDEFGHIJ"))
+	assert.Equal(t, "***", MaskKey("abc"))
+}
@@ -0,0 +1,15 @@
+package api
+
+import "log/slog"
+
+// LevelTrace is the level every request made by the client is logged at,
+// below slog.LevelDebug
+const LevelTrace = slog.LevelDebug - 4
+
+// logger returns the logger diagnostics are written to
+func (c *EtherscanClient) logger() *slog.Logger {
+	if c.Logger == nil {
+		return slog.Default()
+	}
+	return c.Logger
+}
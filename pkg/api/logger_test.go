@@ -0,0 +1,39 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoggerReceivesDiagnostics(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		json.NewEncoder(w).Encode(APIResponse{Status: "1", Message: "OK", Result: json.RawMessage(`[]`)})
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	client := NewEtherscanClient("dummy_api_key")
+	client.BaseURL = server.URL
+	client.RetryDelay = time.Millisecond
+	client.Logger = slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: LevelTrace}))
+
+	_, err := client.GetAllNormalTransactions("0x1", 0, 100)
+	assert.NoError(t, err)
+	assert.Contains(t, logs.String(), `level=WARN msg="rate limit hit or server error, retrying" attempt=1 max_retries=3 status=502`)
+	assert.Contains(t, logs.String(), `level=DEBUG msg="fetched page" action=txlist page=1 rows=0 block=100`)
+	assert.Contains(t, logs.String(), `level=DEBUG msg="fetched all pages" action=txlist rows=0`)
+	assert.Contains(t, logs.String(), `level=DEBUG-4 msg=request method=account.txlist`)
+}
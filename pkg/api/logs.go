@@ -0,0 +1,96 @@
+package api
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ApprovalTopic is the topic of Approval(address,address,uint256), shared by
+// ERC-20 and ERC-721 (which indexes the token ID as a fourth topic)
+const ApprovalTopic = "0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925"
+
+// EventLog represents an event log entry from the Etherscan logs module
+type EventLog struct {
+	Address          string   `json:"address"`
+	Topics           []string `json:"topics"`
+	Data             string   `json:"data"`
+	BlockNumber      string   `json:"blockNumber"`
+	TimeStamp        string   `json:"timeStamp"`
+	GasPrice         string   `json:"gasPrice"`
+	GasUsed          string   `json:"gasUsed"`
+	LogIndex         string   `json:"logIndex"`
+	TransactionHash  string   `json:"transactionHash"`
+	TransactionIndex string   `json:"transactionIndex"`
+}
+
+// GetLogsPaginated fetches event logs matching topic0 and topic1 with pagination
+func (c *EtherscanClient) GetLogsPaginated(topic0, topic1 string, startBlock, endBlock int64, page, offset int) ([]EventLog, error) {
+	params := url.Values{}
+	params.Add("module", "logs")
+	params.Add("action", "getLogs")
+	params.Add("fromBlock", strconv.FormatInt(startBlock, 10))
+	params.Add("toBlock", strconv.FormatInt(endBlock, 10))
+	params.Add("topic0", topic0)
+	params.Add("topic1", topic1)
+	params.Add("topic0_1_opr", "and")
+	params.Add("page", strconv.Itoa(page))
+	params.Add("offset", strconv.Itoa(offset))
+	params.Add("apikey", c.ApiKey)
+
+	var logs []EventLog
+	if err := c.requestWithRetry(params, &logs); err != nil {
+		return nil, err
+	}
+
+	return logs, nil
+}
+
+// GetAllApprovalLogs fetches all Approval events emitted for the given owner using pagination, in block windows sized for the chain (see fetchInWindows)
+func (c *EtherscanClient) GetAllApprovalLogs(owner string, startBlock, endBlock int64) ([]EventLog, error) {
+	return fetchInWindows(c, "getLogs", startBlock, endBlock, func(start, end int64) ([]EventLog, error) {
+		return c.getAllApprovalLogs(owner, start, end)
+	})
+}
+
+// getAllApprovalLogs fetches all Approval events emitted for the given owner within a single block window using pagination
+func (c *EtherscanClient) getAllApprovalLogs(owner string, startBlock, endBlock int64) ([]EventLog, error) {
+	var allLogs []EventLog
+	page := 1
+	batchSize := DefaultOffset
+	ownerTopic := "0x000000000000000000000000" + strings.ToLower(trimHexPrefix(owner))
+
+	for {
+		logs, err := c.GetLogsPaginated(ApprovalTopic, ownerTopic, startBlock, endBlock, page, batchSize)
+		if err != nil {
+			return nil, err
+		}
+		reached := endBlock
+		if len(logs) == batchSize {
+			reached = parseBlockNumber(logs[len(logs)-1].BlockNumber)
+		}
+		c.reportPage("getLogs", page, len(logs), reached)
+
+		allLogs = append(allLogs, logs...)
+
+		// If we got fewer results than the batch size, we've reached the end
+		if len(logs) < batchSize {
+			break
+		}
+
+		page++
+		// Add a small delay between requests to avoid rate limits
+		time.Sleep(200 * time.Millisecond)
+	}
+
+	c.logger().Debug("fetched all pages", "action", "getLogs", "rows", len(allLogs))
+	return allLogs, nil
+}
+
+func trimHexPrefix(s string) string {
+	if len(s) >= 2 && (s[:2] == "0x" || s[:2] == "0X") {
+		return s[2:]
+	}
+	return s
+}
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAllApprovalLogs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		assert.Equal(t, "logs", query.Get("module"))
+		assert.Equal(t, "getLogs", query.Get("action"))
+		assert.Equal(t, ApprovalTopic, query.Get("topic0"))
+		assert.Equal(t, "0x000000000000000000000000a39b189482f984388a34460636fea9eb181ad1a6", query.Get("topic1"))
+		assert.Equal(t, "and", query.Get("topic0_1_opr"))
+
+		json.NewEncoder(w).Encode(APIResponse{
+			Status:  "1",
+			Message: "OK",
+			Result:  json.RawMessage(`[{"address":"0xtoken","topics":["0x8c5b","0xowner","0xspender"],"data":"0x01","blockNumber":"0x10","timeStamp":"0x61290000","transactionHash":"0xabc"}]`),
+		})
+	}))
+	defer server.Close()
+
+	client := NewEtherscanClient("dummy_api_key")
+	client.BaseURL = server.URL
+
+	logs, err := client.GetAllApprovalLogs("0xA39B189482F984388A34460636FEA9EB181AD1A6", 0, 1000000)
+	assert.NoError(t, err)
+	assert.Len(t, logs, 1)
+	assert.Equal(t, "0xabc", logs[0].TransactionHash)
+	assert.Len(t, logs[0].Topics, 3)
+}
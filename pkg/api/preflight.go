@@ -0,0 +1,105 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// ChainMismatchError is returned by Preflight when the endpoint serves a
+// different chain than the client is configured for
+type ChainMismatchError struct {
+	Expected int64
+	Actual   int64
+}
+
+func (e *ChainMismatchError) Error() string {
+	return fmt.Sprintf("wrong chain: endpoint serves chain ID %d, expected %d", e.Actual, e.Expected)
+}
+
+// Preflight makes a cheap eth_blockNumber call with every key the client
+// uses, so a bad key or endpoint fails at startup rather than deep into a
+// run. When the endpoint supports eth_chainId it also checks the chain
+// against c.Chain. It returns the latest block number.
+func (c *EtherscanClient) Preflight() (int64, error) {
+	keys := []string{c.ApiKey}
+	if c.Keys != nil {
+		keys = keys[:0]
+		for _, k := range c.Keys.keys {
+			keys = append(keys, k.key)
+		}
+	}
+
+	var head int64
+	for _, key := range keys {
+		var hex string
+		if err := c.probe(key, "eth_blockNumber", &hex); err != nil {
+			return 0, err
+		}
+		number, err := ParseHexBig(hex)
+		if err != nil {
+			return 0, fmt.Errorf("unexpected eth_blockNumber result: %w", err)
+		}
+		head = number.Int64()
+	}
+
+	if c.Chain.ID != 0 {
+		var hex string
+		// Not every explorer proxies eth_chainId, so only a definite answer counts
+		if err := c.probe(keys[0], "eth_chainId", &hex); err == nil {
+			if id, err := ParseHexBig(hex); err == nil && id.Int64() != c.Chain.ID {
+				return 0, &ChainMismatchError{Expected: c.Chain.ID, Actual: id.Int64()}
+			}
+		}
+	}
+	return head, nil
+}
+
+// probe calls a proxy action with a specific key, bypassing the key pool
+func (c *EtherscanClient) probe(key, action string, result interface{}) (err error) {
+	params := url.Values{}
+	params.Add("module", "proxy")
+	params.Add("action", action)
+	params.Add("apikey", key)
+
+	start := time.Now()
+	var resp proxyResponse
+	defer func() { c.recordCall(params, start, resp.Result, err) }()
+
+	body, err := c.makeRequest(params)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return fmt.Errorf("unexpected response from %s: %w", c.BaseURL, err)
+	}
+	if resp.Status == "0" {
+		err := apiError(resp.Message, resp.Result)
+		if errors.Is(err, ErrInvalidAPIKey) {
+			return fmt.Errorf("%w %s", ErrInvalidAPIKey, MaskKey(key))
+		}
+		return err
+	}
+	if resp.Error != nil {
+		return fmt.Errorf("API returned error: %s", resp.Error.Message)
+	}
+	return json.Unmarshal(resp.Result, result)
+}
+
+// apiError describes a status 0 response. Etherscan's message is usually
+// just "NOTOK", with the reason in the result field.
+func apiError(message string, result json.RawMessage) error {
+	detail := resultText(result)
+	return &APIError{Message: message, Detail: detail, kind: classifyError(message, detail)}
+}
+
+// resultText returns result when it is a JSON string
+func resultText(result json.RawMessage) string {
+	var text string
+	if json.Unmarshal(result, &text) != nil {
+		return ""
+	}
+	return text
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/haridev22/ct-assignement/pkg/chains"
+	"github.com/stretchr/testify/assert"
+)
+
+func preflightServer(t *testing.T, chainID string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("apikey") == "BADKEY123" {
+			w.Write([]byte(`{"status":"0","message":"NOTOK","result":"Invalid API Key"}`))
+			return
+		}
+		switch query.Get("action") {
+		case "eth_blockNumber":
+			w.Write([]byte(`{"jsonrpc":"2.0","id":83,"result":"0x1406f40"}`))
+		case "eth_chainId":
+			if chainID == "" {
+				w.Write([]byte(`{"status":"0","message":"NOTOK","result":"Error! Missing Or invalid Action name"}`))
+				return
+			}
+			w.Write([]byte(`{"jsonrpc":"2.0","id":83,"result":"` + chainID + `"}`))
+		}
+	}))
+}
+
+func TestPreflight(t *testing.T) {
+	server := preflightServer(t, "0x1")
+	defer server.Close()
+
+	client := NewEtherscanClient("GOODKEY")
+	client.BaseURL = server.URL
+	head, err := client.Preflight()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(21000000), head)
+
+	client.Keys = NewKeyPool([]string{"GOODKEY", "BADKEY123"}, 1000)
+	_, err = client.Preflight()
+	assert.ErrorIs(t, err, ErrInvalidAPIKey)
+	assert.EqualError(t, err, "invalid API key BADK****", "pooled keys are checked one by one")
+}
+
+func TestPreflight_WrongChain(t *testing.T) {
+	server := preflightServer(t, "0x89")
+	defer server.Close()
+
+	client := NewEtherscanClient("GOODKEY")
+	client.BaseURL = server.URL
+	_, err := client.Preflight()
+	var mismatch *ChainMismatchError
+	if assert.ErrorAs(t, err, &mismatch) {
+		assert.Equal(t, int64(137), mismatch.Actual)
+		assert.Equal(t, int64(1), mismatch.Expected)
+	}
+
+	// Endpoints without eth_chainId skip the chain check
+	noChainID := preflightServer(t, "")
+	defer noChainID.Close()
+	client.BaseURL = noChainID.URL
+	client.Chain = chains.Ethereum
+	_, err = client.Preflight()
+	assert.NoError(t, err)
+}
+
+func TestAPIError(t *testing.T) {
+	assert.EqualError(t, apiError("NOTOK", []byte(`"Max rate limit reached"`)), "API returned error: NOTOK (Max rate limit reached)")
+	assert.EqualError(t, apiError("NOTOK", []byte(`[]`)), "API returned error: NOTOK")
+}
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"strconv"
+	"strings"
+)
+
+// PageEvent reports a page of results fetched by one of the GetAll methods
+type PageEvent struct {
+	// Action is the Etherscan action of the request, e.g. txlist
+	Action string
+	Page   int
+	Rows   int
+	// Block is how far the fetch has reached: the block of the last row of a
+	// full page, or the end of the window once it is exhausted. It is 0 when
+	// the endpoint is not ordered by block.
+	Block int64
+}
+
+// reportPage logs a fetched page and passes it to the Progress callback, if any
+func (c *EtherscanClient) reportPage(action string, page, rows int, block int64) {
+	c.logger().Debug("fetched page", "action", action, "page", page, "rows", rows, "block", block)
+	if c.Progress != nil {
+		c.Progress(PageEvent{Action: action, Page: page, Rows: rows, Block: block})
+	}
+}
+
+// parseBlockNumber parses a decimal or hex block number, returning 0 when
+// it is malformed
+func parseBlockNumber(s string) int64 {
+	if strings.HasPrefix(s, "0x") {
+		n, err := ParseHexBig(s)
+		if err != nil || !n.IsInt64() {
+			return 0
+		}
+		return n.Int64()
+	}
+	n, _ := strconv.ParseInt(s, 10, 64)
+	return n
+}
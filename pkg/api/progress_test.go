@@ -0,0 +1,46 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgressEvents(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rows := 3
+		if r.URL.Query().Get("page") == "1" {
+			rows = DefaultOffset
+		}
+		txs := make([]string, rows)
+		for i := range txs {
+			txs[i] = fmt.Sprintf(`{"blockNumber":"%d","hash":"0x%d"}`, 100+i, i)
+		}
+		json.NewEncoder(w).Encode(APIResponse{Status: "1", Message: "OK", Result: json.RawMessage("[" + strings.Join(txs, ",") + "]")})
+	}))
+	defer server.Close()
+
+	client := NewEtherscanClient("dummy_api_key")
+	client.BaseURL = server.URL
+	var events []PageEvent
+	client.Progress = func(e PageEvent) { events = append(events, e) }
+
+	txs, err := client.GetAllNormalTransactions("0x1", 0, 5000)
+	assert.NoError(t, err)
+	assert.Len(t, txs, DefaultOffset+3)
+	assert.Equal(t, []PageEvent{
+		{Action: "txlist", Page: 1, Rows: DefaultOffset, Block: 100 + DefaultOffset - 1},
+		{Action: "txlist", Page: 2, Rows: 3, Block: 5000},
+	}, events)
+}
+
+func TestParseBlockNumber(t *testing.T) {
+	assert.Equal(t, int64(1234), parseBlockNumber("1234"))
+	assert.Equal(t, int64(255), parseBlockNumber("0xff"))
+	assert.Equal(t, int64(0), parseBlockNumber("bogus"))
+}
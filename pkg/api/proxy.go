@@ -0,0 +1,139 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// proxyResponse is the JSON-RPC envelope returned by the proxy module
+type proxyResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      int             `json:"id"`
+	Result  json.RawMessage `json:"result"`
+	Error   *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+	// Set instead of a JSON-RPC envelope when Etherscan rejects the call
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// TransactionReceipt holds the fee-relevant fields of eth_getTransactionReceipt
+type TransactionReceipt struct {
+	TransactionHash   string `json:"transactionHash"`
+	BlockNumber       string `json:"blockNumber"`
+	GasUsed           string `json:"gasUsed"`
+	EffectiveGasPrice string `json:"effectiveGasPrice"`
+	Status            string `json:"status"`
+	Type              string `json:"type"`
+	// L1Fee is the fee for posting the transaction data to Ethereum, only
+	// reported by OP-stack chains
+	L1Fee string `json:"l1Fee"`
+}
+
+// Block holds the fields of eth_getBlockByNumber used by this package
+type Block struct {
+	Number        string `json:"number"`
+	Timestamp     string `json:"timestamp"`
+	BaseFeePerGas string `json:"baseFeePerGas"`
+}
+
+// GetTransactionReceipt fetches the receipt of a transaction via the proxy module
+func (c *EtherscanClient) GetTransactionReceipt(hash string) (*TransactionReceipt, error) {
+	params := url.Values{}
+	params.Add("module", "proxy")
+	params.Add("action", "eth_getTransactionReceipt")
+	params.Add("txhash", hash)
+	params.Add("apikey", c.ApiKey)
+
+	var receipt *TransactionReceipt
+	if err := c.proxyRequest(params, &receipt); err != nil {
+		return nil, err
+	}
+	if receipt == nil {
+		return nil, fmt.Errorf("receipt not found for transaction %s", hash)
+	}
+	return receipt, nil
+}
+
+// GetBlockByNumber fetches a block header (without transactions) via the proxy module
+func (c *EtherscanClient) GetBlockByNumber(number int64) (*Block, error) {
+	params := url.Values{}
+	params.Add("module", "proxy")
+	params.Add("action", "eth_getBlockByNumber")
+	params.Add("tag", "0x"+strconv.FormatInt(number, 16))
+	params.Add("boolean", "false")
+	params.Add("apikey", c.ApiKey)
+
+	var block *Block
+	if err := c.proxyRequest(params, &block); err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block %d not found", number)
+	}
+	return block, nil
+}
+
+// CallContract executes a read-only contract call at the latest block via
+// the proxy module and returns the hex-encoded result
+func (c *EtherscanClient) CallContract(to, data string) (string, error) {
+	params := url.Values{}
+	params.Add("module", "proxy")
+	params.Add("action", "eth_call")
+	params.Add("to", to)
+	params.Add("data", data)
+	params.Add("tag", "latest")
+	params.Add("apikey", c.ApiKey)
+
+	var result string
+	if err := c.proxyRequest(params, &result); err != nil {
+		return "", err
+	}
+	return result, nil
+}
+
+// proxyRequest makes a proxy module request and decodes the JSON-RPC result
+func (c *EtherscanClient) proxyRequest(params url.Values, result interface{}) (err error) {
+	start := time.Now()
+	var resp proxyResponse
+	defer func() { c.recordCall(params, start, resp.Result, err) }()
+
+	body, err := c.send(params)
+	if err != nil {
+		return err
+	}
+	c.archive(params, body)
+
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return err
+	}
+
+	if resp.Error != nil {
+		return fmt.Errorf("API returned error: %s", resp.Error.Message)
+	}
+	if resp.Status == "0" {
+		return apiError(resp.Message, resp.Result)
+	}
+
+	return json.Unmarshal(resp.Result, result)
+}
+
+// ParseHexBig parses a 0x-prefixed hex quantity as returned by JSON-RPC
+func ParseHexBig(s string) (*big.Int, error) {
+	digits := strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	if digits == "" {
+		return nil, fmt.Errorf("invalid hex quantity %q", s)
+	}
+	value, ok := new(big.Int).SetString(digits, 16)
+	if !ok {
+		return nil, fmt.Errorf("invalid hex quantity %q", s)
+	}
+	return value, nil
+}
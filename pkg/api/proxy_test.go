@@ -0,0 +1,77 @@
+package api
+
+import (
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTransactionReceipt(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		assert.Equal(t, "proxy", query.Get("module"))
+		assert.Equal(t, "eth_getTransactionReceipt", query.Get("action"))
+		assert.Equal(t, "0xabc", query.Get("txhash"))
+
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"transactionHash":"0xabc","blockNumber":"0x10","gasUsed":"0x5208","effectiveGasPrice":"0x6fc23ac00","status":"0x1"}}`))
+	}))
+	defer server.Close()
+
+	client := NewEtherscanClient("dummy_api_key")
+	client.BaseURL = server.URL
+
+	receipt, err := client.GetTransactionReceipt("0xabc")
+	assert.NoError(t, err)
+	assert.Equal(t, "0x5208", receipt.GasUsed)
+	assert.Equal(t, "0x6fc23ac00", receipt.EffectiveGasPrice)
+}
+
+func TestGetBlockByNumber_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "0x10", r.URL.Query().Get("tag"))
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32602,"message":"invalid argument"}}`))
+	}))
+	defer server.Close()
+
+	client := NewEtherscanClient("dummy_api_key")
+	client.BaseURL = server.URL
+
+	_, err := client.GetBlockByNumber(16)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid argument")
+}
+
+func TestCallContract(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		assert.Equal(t, "eth_call", query.Get("action"))
+		assert.Equal(t, "0xusdc", query.Get("to"))
+		assert.Equal(t, "0x313ce567", query.Get("data"))
+		assert.Equal(t, "latest", query.Get("tag"))
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x0000000000000000000000000000000000000000000000000000000000000006"}`))
+	}))
+	defer server.Close()
+
+	client := NewEtherscanClient("dummy_api_key")
+	client.BaseURL = server.URL
+
+	out, err := client.CallContract("0xusdc", "0x313ce567")
+	assert.NoError(t, err)
+	decimals, err := ParseHexBig(out)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), decimals.Int64())
+}
+
+func TestParseHexBig(t *testing.T) {
+	value, err := ParseHexBig("0x5208")
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(21000), value)
+
+	_, err = ParseHexBig("0x")
+	assert.Error(t, err)
+	_, err = ParseHexBig("0xzz")
+	assert.Error(t, err)
+}
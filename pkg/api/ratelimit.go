@@ -0,0 +1,91 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// epochThreshold separates reset headers given as Unix times from those
+// given as seconds until the reset
+const epochThreshold = 1_000_000_000
+
+// retryAfter returns the wait requested by a Retry-After header, given in
+// seconds or as an HTTP date
+func retryAfter(h http.Header, now time.Time) (time.Duration, bool) {
+	value := strings.TrimSpace(h.Get("Retry-After"))
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0), true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// rateLimitPause returns how long to wait before the next request so the
+// requests left in the provider's rate limit window, as reported by
+// RateLimit-Remaining and RateLimit-Reset headers (or their X- prefixed
+// forms), are spread evenly until the window resets
+func rateLimitPause(h http.Header, now time.Time) (time.Duration, bool) {
+	remaining, ok := headerInt(h, "RateLimit-Remaining", "X-RateLimit-Remaining")
+	if !ok {
+		return 0, false
+	}
+	reset, ok := headerInt(h, "RateLimit-Reset", "X-RateLimit-Reset")
+	if !ok {
+		return 0, false
+	}
+
+	untilReset := time.Duration(reset) * time.Second
+	if reset > epochThreshold {
+		untilReset = time.Unix(reset, 0).Sub(now)
+	}
+	if untilReset <= 0 {
+		return 0, false
+	}
+	return untilReset / time.Duration(max(remaining, 0)+1), true
+}
+
+// headerInt returns the first of names present in h as an integer
+func headerInt(h http.Header, names ...string) (int64, bool) {
+	for _, name := range names {
+		if value := strings.TrimSpace(h.Get(name)); value != "" {
+			n, err := strconv.ParseInt(value, 10, 64)
+			return n, err == nil
+		}
+	}
+	return 0, false
+}
+
+// observeRateLimit paces the client's following requests by the rate limit
+// headers of resp, if it has any
+func (c *EtherscanClient) observeRateLimit(resp *http.Response) {
+	now := time.Now()
+	pause, ok := rateLimitPause(resp.Header, now)
+	if !ok {
+		return
+	}
+	c.paceMu.Lock()
+	defer c.paceMu.Unlock()
+	if next := now.Add(pause); next.After(c.paceUntil) {
+		c.paceUntil = next
+	}
+}
+
+// pace waits until the rate limit headers of earlier responses allow
+// another request
+func (c *EtherscanClient) pace() error {
+	c.paceMu.Lock()
+	wait := time.Until(c.paceUntil)
+	c.paceMu.Unlock()
+	if wait <= 0 {
+		return nil
+	}
+	c.logger().Debug("pacing request to the provider's rate limit", "delay", wait)
+	return c.wait(wait)
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	_, ok := retryAfter(http.Header{}, now)
+	assert.False(t, ok)
+
+	d, ok := retryAfter(http.Header{"Retry-After": {"7"}}, now)
+	assert.True(t, ok)
+	assert.Equal(t, 7*time.Second, d)
+
+	d, ok = retryAfter(http.Header{"Retry-After": {"Mon, 01 Jan 2024 00:00:30 GMT"}}, now)
+	assert.True(t, ok)
+	assert.Equal(t, 30*time.Second, d)
+
+	_, ok = retryAfter(http.Header{"Retry-After": {"soon"}}, now)
+	assert.False(t, ok)
+}
+
+func TestRateLimitPause(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Three requests left in the next second are spread a quarter second apart
+	d, ok := rateLimitPause(http.Header{"X-Ratelimit-Remaining": {"3"}, "X-Ratelimit-Reset": {"1"}}, now)
+	assert.True(t, ok)
+	assert.Equal(t, 250*time.Millisecond, d)
+
+	// With none left the next request waits for the reset, given as a Unix time
+	d, ok = rateLimitPause(http.Header{"Ratelimit-Remaining": {"0"}, "Ratelimit-Reset": {"1704067210"}}, now)
+	assert.True(t, ok)
+	assert.Equal(t, 10*time.Second, d)
+
+	_, ok = rateLimitPause(http.Header{"X-Ratelimit-Remaining": {"3"}}, now)
+	assert.False(t, ok)
+}
+
+func TestEtherscanClient_HonorsRetryAfter(t *testing.T) {
+	var requests []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, time.Now())
+		if len(requests) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		json.NewEncoder(w).Encode(APIResponse{Status: "1", Message: "OK", Result: json.RawMessage(`[]`)})
+	}))
+	defer server.Close()
+
+	client := NewEtherscanClient("dummy_api_key")
+	client.BaseURL = server.URL
+	client.RetryDelay = time.Millisecond
+
+	_, err := client.GetNormalTransactions("0x1", 0, 100)
+	assert.NoError(t, err)
+	if assert.Len(t, requests, 2) {
+		assert.GreaterOrEqual(t, requests[1].Sub(requests[0]), time.Second)
+	}
+}
+
+func TestEtherscanClient_PacesByRateLimitHeaders(t *testing.T) {
+	var requests []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, time.Now())
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", "1")
+		json.NewEncoder(w).Encode(APIResponse{Status: "1", Message: "OK", Result: json.RawMessage(`[]`)})
+	}))
+	defer server.Close()
+
+	client := NewEtherscanClient("dummy_api_key")
+	client.BaseURL = server.URL
+
+	for i := 0; i < 2; i++ {
+		_, err := client.GetNormalTransactions("0x1", 0, 100)
+		assert.NoError(t, err)
+	}
+	if assert.Len(t, requests, 2) {
+		assert.GreaterOrEqual(t, requests[1].Sub(requests[0]), 900*time.Millisecond)
+	}
+}
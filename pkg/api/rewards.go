@@ -0,0 +1,114 @@
+package api
+
+import (
+	"fmt"
+	"math/big"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+)
+
+// MinedBlock represents a block validated by an address from Etherscan API
+type MinedBlock struct {
+	BlockNumber string `json:"blockNumber"`
+	TimeStamp   string `json:"timeStamp"`
+	BlockReward string `json:"blockReward"`
+}
+
+// GetMinedBlocksPaginated fetches blocks validated by the given address with pagination
+func (c *EtherscanClient) GetMinedBlocksPaginated(address string, page, offset int) ([]MinedBlock, error) {
+	params := url.Values{}
+	params.Add("module", "account")
+	params.Add("action", "getminedblocks")
+	params.Add("address", address)
+	params.Add("blocktype", "blocks")
+	params.Add("page", strconv.Itoa(page))
+	params.Add("offset", strconv.Itoa(offset))
+	params.Add("apikey", c.ApiKey)
+
+	var blocks []MinedBlock
+	if err := c.requestWithRetry(params, &blocks); err != nil {
+		return nil, err
+	}
+
+	return blocks, nil
+}
+
+// GetAllMinedBlocks fetches all blocks validated by the given address within
+// the block range. The endpoint has no range parameters, so every page is
+// fetched and the range is applied locally.
+func (c *EtherscanClient) GetAllMinedBlocks(address string, startBlock, endBlock int64) ([]MinedBlock, error) {
+	var allBlocks []MinedBlock
+	page := 1
+	batchSize := DefaultOffset
+
+	for {
+		blocks, err := c.GetMinedBlocksPaginated(address, page, batchSize)
+		if err != nil {
+			return nil, err
+		}
+		// Pages are not ordered by block, so there is no block to report
+		c.reportPage("getminedblocks", page, len(blocks), 0)
+
+		for _, block := range blocks {
+			number, err := strconv.ParseInt(block.BlockNumber, 10, 64)
+			if err != nil || number < startBlock || number > endBlock {
+				continue
+			}
+			allBlocks = append(allBlocks, block)
+		}
+
+		// If we got fewer results than the batch size, we've reached the end
+		if len(blocks) < batchSize {
+			break
+		}
+
+		page++
+		// Add a small delay between requests to avoid rate limits
+		time.Sleep(200 * time.Millisecond)
+	}
+
+	c.logger().Debug("fetched all pages", "action", "getminedblocks", "rows", len(allBlocks))
+	return allBlocks, nil
+}
+
+// ConvertMinedBlockToModel converts a mined block to a block reward row
+// credited to the given address
+func ConvertMinedBlockToModel(block MinedBlock, address string) (models.Transaction, error) {
+	return Converter{}.MinedBlock(block, address)
+}
+
+// MinedBlock converts a mined block to a block reward row credited to the
+// given address
+func (c Converter) MinedBlock(block MinedBlock, address string) (models.Transaction, error) {
+	timestamp, err := strconv.ParseInt(block.TimeStamp, 10, 64)
+	if err != nil {
+		return models.Transaction{}, err
+	}
+
+	blockNumber, err := strconv.ParseInt(block.BlockNumber, 10, 64)
+	if err != nil {
+		return models.Transaction{}, err
+	}
+
+	reward, ok := new(big.Int).SetString(block.BlockReward, 10)
+	if !ok {
+		return models.Transaction{}, fmt.Errorf("invalid block reward %q", block.BlockReward)
+	}
+
+	return models.Transaction{
+		BlockNumber: blockNumber,
+		Timestamp:   time.Unix(timestamp, 0),
+		To:          address,
+		Type:        models.TypeBlockReward,
+		AssetSymbol: c.NativeSymbol,
+		Value:       FormatWeiAsEth(reward),
+		GasFee:      "0", // Rewards are not transactions and pay no gas
+		RawValue:    reward.String(),
+		RawGasFee:   "0",
+		Status:      models.StatusSuccess,
+		Chain:       c.Chain,
+	}, nil
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAllMinedBlocks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		assert.Equal(t, "getminedblocks", query.Get("action"))
+		assert.Equal(t, "blocks", query.Get("blocktype"))
+
+		json.NewEncoder(w).Encode(APIResponse{
+			Status:  "1",
+			Message: "OK",
+			Result: json.RawMessage(`[
+				{"blockNumber":"300","timeStamp":"1630000300","blockReward":"2000000000000000000"},
+				{"blockNumber":"200","timeStamp":"1630000200","blockReward":"2000000000000000000"},
+				{"blockNumber":"100","timeStamp":"1630000100","blockReward":"2000000000000000000"}
+			]`),
+		})
+	}))
+	defer server.Close()
+
+	client := NewEtherscanClient("dummy_api_key")
+	client.BaseURL = server.URL
+
+	blocks, err := client.GetAllMinedBlocks("0xminer", 150, 300)
+	assert.NoError(t, err)
+	assert.Len(t, blocks, 2)
+	assert.Equal(t, "300", blocks[0].BlockNumber)
+	assert.Equal(t, "200", blocks[1].BlockNumber)
+}
+
+func TestConvertMinedBlockToModel(t *testing.T) {
+	block := MinedBlock{BlockNumber: "12345", TimeStamp: "1630000000", BlockReward: "2500000000000000000"}
+
+	result, err := ConvertMinedBlockToModel(block, "0xminer")
+	assert.NoError(t, err)
+	assert.Equal(t, models.TypeBlockReward, result.Type)
+	assert.Equal(t, int64(12345), result.BlockNumber)
+	assert.Equal(t, time.Unix(1630000000, 0), result.Timestamp)
+	assert.Equal(t, "0xminer", result.To)
+	assert.Equal(t, "2.500000000000000000", result.Value)
+	assert.Equal(t, "0", result.GasFee)
+
+	_, err = ConvertMinedBlockToModel(MinedBlock{BlockNumber: "1", TimeStamp: "1", BlockReward: "x"}, "0xminer")
+	assert.Error(t, err)
+}
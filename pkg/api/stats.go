@@ -0,0 +1,54 @@
+package api
+
+import "sync/atomic"
+
+// CallStats counts the HTTP requests made by a client
+type CallStats struct {
+	// Calls is the number of requests sent, including retries
+	Calls int64
+	// Retries is the number of requests repeated after a failure or rate limit
+	Retries int64
+}
+
+// Stats returns the requests made by the client so far
+func (c *EtherscanClient) Stats() CallStats {
+	return CallStats{Calls: c.calls.Load(), Retries: c.retries.Load()}
+}
+
+// Usage returns the requests sent with each API key, including retries, in
+// pool order when the client has a key pool
+func (c *EtherscanClient) Usage() []KeyUsage {
+	keys := []string{c.ApiKey}
+	var rateLimited []KeyUsage
+	if c.Keys != nil {
+		rateLimited = c.Keys.Usage()
+		keys = keys[:0]
+		for _, k := range c.Keys.keys {
+			keys = append(keys, k.key)
+		}
+	}
+
+	usage := make([]KeyUsage, len(keys))
+	for i, key := range keys {
+		usage[i].Key = MaskKey(key)
+		if n, ok := c.keyCalls.Load(key); ok {
+			usage[i].Calls = int(n.(*atomic.Int64).Load())
+		}
+		if rateLimited != nil {
+			usage[i].RateLimited = rateLimited[i].RateLimited
+		}
+	}
+	return usage
+}
+
+// countCall records a request sent with key, or returns ErrBudgetExhausted
+// when the client's budget allows no more requests
+func (c *EtherscanClient) countCall(key string) error {
+	if !c.Budget.take() {
+		return ErrBudgetExhausted
+	}
+	c.calls.Add(1)
+	n, _ := c.keyCalls.LoadOrStore(key, new(atomic.Int64))
+	n.(*atomic.Int64).Add(1)
+	return nil
+}
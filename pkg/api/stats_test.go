@@ -0,0 +1,62 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatsCountCallsAndRetries(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		json.NewEncoder(w).Encode(APIResponse{Status: "1", Message: "OK", Result: json.RawMessage(`[]`)})
+	}))
+	defer server.Close()
+
+	client := NewEtherscanClient("dummy_api_key")
+	client.BaseURL = server.URL
+	client.RetryDelay = time.Millisecond
+
+	_, err := client.GetNormalTransactions("0x1", 0, 100)
+	assert.NoError(t, err)
+	_, err = client.GetInternalTransactions("0x1", 0, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, CallStats{Calls: 3, Retries: 1}, client.Stats())
+}
+
+func TestUsageCountsEveryRequestPerKey(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Query().Get("apikey") == "key-b" && calls < 4 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(APIResponse{Status: "1", Message: "OK", Result: json.RawMessage(`[]`)})
+	}))
+	defer server.Close()
+
+	client := NewEtherscanClient("")
+	client.BaseURL = server.URL
+	client.RetryDelay = time.Millisecond
+	client.Keys = NewKeyPool([]string{"key-a", "key-b"}, 1000)
+
+	for i := 0; i < 2; i++ {
+		_, err := client.GetNormalTransactions("0x1", 0, 100)
+		assert.NoError(t, err)
+	}
+	// Retries of a server error are sent with the same key and count against it
+	assert.Equal(t, []KeyUsage{
+		{Key: "key-****", Calls: 1},
+		{Key: "key-****", Calls: 3},
+	}, client.Usage())
+}
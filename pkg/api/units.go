@@ -0,0 +1,17 @@
+package api
+
+import (
+	"math/big"
+)
+
+// FormatWeiAsEth formats a wei amount as ETH with 18 decimal places
+func FormatWeiAsEth(wei *big.Int) string {
+	return FormatTokenAmount(wei, 18)
+}
+
+// FormatTokenAmount formats a raw token amount using the token's decimals.
+// The division is exact, so the result parses back to the same raw amount.
+func FormatTokenAmount(raw *big.Int, decimals int) string {
+	divisor := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	return new(big.Rat).SetFrac(raw, divisor).FloatString(decimals)
+}
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatWeiAsEth_Exact(t *testing.T) {
+	// A binary float division came out one wei short here
+	wei, _ := new(big.Int).SetString("17734245977318995403", 10)
+	assert.Equal(t, "17.734245977318995403", FormatWeiAsEth(wei))
+
+	assert.Equal(t, "0.000000000000000001", FormatWeiAsEth(big.NewInt(1)))
+	assert.Equal(t, "0.000000000000000000", FormatWeiAsEth(new(big.Int)))
+}
+
+func TestFormatTokenAmount_RoundTrip(t *testing.T) {
+	for _, tc := range []struct {
+		raw      string
+		decimals int
+		want     string
+	}{
+		{"1500", 0, "1500"},
+		{"1500", 3, "1.500"},
+		{"999999999999999999999999", 6, "999999999999999999.999999"},
+		{"1", 30, "0.000000000000000000000000000001"},
+	} {
+		raw, _ := new(big.Int).SetString(tc.raw, 10)
+		formatted := FormatTokenAmount(raw, tc.decimals)
+		assert.Equal(t, tc.want, formatted)
+
+		parsed, ok := new(big.Rat).SetString(formatted)
+		assert.True(t, ok)
+		parsed.Mul(parsed, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(tc.decimals)), nil)))
+		assert.Equal(t, raw.String(), parsed.Num().String(), "formatted values parse back to the raw amount")
+	}
+}
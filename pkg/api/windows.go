@@ -0,0 +1,109 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// GetBlockNumber returns the number of the latest block via the proxy module
+func (c *EtherscanClient) GetBlockNumber() (int64, error) {
+	params := url.Values{}
+	params.Add("module", "proxy")
+	params.Add("action", "eth_blockNumber")
+	params.Add("apikey", c.ApiKey)
+
+	var hex string
+	if err := c.proxyRequest(params, &hex); err != nil {
+		return 0, err
+	}
+	number, err := ParseHexBig(hex)
+	if err != nil {
+		return 0, err
+	}
+	return number.Int64(), nil
+}
+
+// GetFinalizedBlockNumber returns the number of the latest finalized block,
+// which can no longer be reorganised, via the proxy module
+func (c *EtherscanClient) GetFinalizedBlockNumber() (int64, error) {
+	params := url.Values{}
+	params.Add("module", "proxy")
+	params.Add("action", "eth_getBlockByNumber")
+	params.Add("tag", "finalized")
+	params.Add("boolean", "false")
+	params.Add("apikey", c.ApiKey)
+
+	var block *Block
+	if err := c.proxyRequest(params, &block); err != nil {
+		return 0, err
+	}
+	if block == nil {
+		return 0, fmt.Errorf("finalized block not available")
+	}
+	number, err := ParseHexBig(block.Number)
+	if err != nil {
+		return 0, err
+	}
+	return number.Int64(), nil
+}
+
+// fetchInWindows calls fetch for consecutive block windows covering
+// [startBlock, endBlock], each no larger than the chain's recommended span
+// for action. Ranges that need splitting are first clamped to the chain
+// head, so open-ended ranges do not request empty windows past it. A window
+// holding more rows than the provider's result window is split further by
+// fetchBisecting. The GetAll methods of the transaction and log lists fetch
+// through it, so busy addresses need no -batch.
+func fetchInWindows[T any](c *EtherscanClient, action string, startBlock, endBlock int64, fetch func(start, end int64) ([]T, error)) ([]T, error) {
+	window := c.Chain.BlockRange(action)
+	if window <= 0 || endBlock-startBlock < window {
+		return fetchBisecting(c, action, startBlock, endBlock, fetch)
+	}
+
+	head, err := c.GetBlockNumber()
+	if err != nil {
+		c.logger().Warn("could not determine the latest block, fetching the range in one window",
+			"start", startBlock, "end", endBlock, "error", err)
+		return fetchBisecting(c, action, startBlock, endBlock, fetch)
+	}
+	if head < endBlock {
+		endBlock = head
+	}
+
+	var all []T
+	for start := startBlock; start <= endBlock; start += window {
+		end := min(start+window-1, endBlock)
+		if start > startBlock || end < endBlock {
+			c.logger().Debug("fetching block window", "action", action, "start", start, "end", end)
+		}
+		rows, err := fetchBisecting(c, action, start, end, fetch)
+		if err != nil {
+			return nil, fmt.Errorf("blocks %d to %d: %w", start, end, err)
+		}
+		all = append(all, rows...)
+	}
+	return all, nil
+}
+
+// fetchBisecting calls fetch for [startBlock, endBlock]. When the window
+// holds more rows than a query can return, it is split in halves that are
+// fetched the same way, down to single blocks, so rows stay in block order.
+func fetchBisecting[T any](c *EtherscanClient, action string, startBlock, endBlock int64, fetch func(start, end int64) ([]T, error)) ([]T, error) {
+	rows, err := fetch(startBlock, endBlock)
+	if !errors.Is(err, ErrResultWindowExceeded) || startBlock >= endBlock {
+		return rows, err
+	}
+
+	middle := startBlock + (endBlock-startBlock)/2
+	c.logger().Debug("result window exceeded, splitting block window", "action", action, "start", startBlock, "end", endBlock)
+	first, err := fetchBisecting(c, action, startBlock, middle, fetch)
+	if err != nil {
+		return nil, err
+	}
+	second, err := fetchBisecting(c, action, middle+1, endBlock, fetch)
+	if err != nil {
+		return nil, err
+	}
+	return append(first, second...), nil
+}
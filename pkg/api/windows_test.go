@@ -0,0 +1,130 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/haridev22/ct-assignement/pkg/chains"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAllNormalTransactions_SplitsIntoWindows(t *testing.T) {
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("action") == "eth_blockNumber" {
+			w.Write([]byte(`{"jsonrpc":"2.0","id":83,"result":"0x9c4"}`)) // 2500
+			return
+		}
+		ranges = append(ranges, query.Get("startblock")+"-"+query.Get("endblock"))
+		json.NewEncoder(w).Encode(APIResponse{
+			Status:  "1",
+			Message: "OK",
+			Result:  json.RawMessage(`[{"hash":"0x` + query.Get("startblock") + `"}]`),
+		})
+	}))
+	defer server.Close()
+
+	client := NewEtherscanClient("dummy_api_key")
+	client.BaseURL = server.URL
+	client.Chain = chains.Chain{DefaultMaxBlockRange: 1000}
+
+	txs, err := client.GetAllNormalTransactions("0xwallet", 0, 999999999)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0-999", "1000-1999", "2000-2500"}, ranges)
+	assert.Len(t, txs, 3)
+
+	// Ranges within the window are fetched directly, without a head lookup
+	ranges = nil
+	_, err = client.GetAllNormalTransactions("0xwallet", 100, 1099)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"100-1099"}, ranges)
+}
+
+func TestGetAllNormalTransactions_SplitsFullWindows(t *testing.T) {
+	// One row in each of blocks 0 to 39, of which a query returns at most
+	// 10, and more rows than that in busyBlock
+	const rows, limit, busyBlock = 40, 10, 100
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		start, _ := strconv.ParseInt(query.Get("startblock"), 10, 64)
+		end, _ := strconv.ParseInt(query.Get("endblock"), 10, 64)
+		ranges = append(ranges, query.Get("startblock")+"-"+query.Get("endblock"))
+
+		var result []map[string]string
+		for block := start; block <= end && block < rows; block++ {
+			result = append(result, map[string]string{"hash": fmt.Sprintf("0x%d", block), "blockNumber": strconv.FormatInt(block, 10)})
+		}
+		if start <= busyBlock && busyBlock <= end {
+			for i := 0; i <= limit; i++ {
+				result = append(result, map[string]string{"hash": fmt.Sprintf("0xbusy%d", i), "blockNumber": strconv.Itoa(busyBlock)})
+			}
+		}
+		if len(result) > limit {
+			json.NewEncoder(w).Encode(APIResponse{
+				Status:  "0",
+				Message: "NOTOK",
+				Result:  json.RawMessage(`"Result window is too large, PageNo x Offset size must be less than or equal to 10000"`),
+			})
+			return
+		}
+		body, _ := json.Marshal(result)
+		json.NewEncoder(w).Encode(APIResponse{Status: "1", Message: "OK", Result: body})
+	}))
+	defer server.Close()
+
+	client := NewEtherscanClient("dummy_api_key")
+	client.BaseURL = server.URL
+	client.Chain = chains.Chain{}
+
+	txs, err := client.GetAllNormalTransactions("0xwallet", 0, 63)
+	assert.NoError(t, err)
+	if assert.Len(t, txs, rows) {
+		for i, tx := range txs {
+			assert.Equal(t, strconv.Itoa(i), tx.BlockNumber, "rows keep their block order")
+		}
+	}
+	assert.Equal(t, []string{"0-63", "0-31", "0-15", "0-7", "8-15", "16-31", "16-23", "24-31", "32-63"}, ranges)
+
+	// A single block holding more rows than a query returns cannot be split
+	ranges = nil
+	_, err = client.GetAllNormalTransactions("0xwallet", 98, 101)
+	assert.ErrorIs(t, err, ErrResultWindowExceeded)
+	assert.Equal(t, []string{"98-101", "98-99", "100-101", "100-100"}, ranges)
+}
+
+func TestGetBlockNumber(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "eth_blockNumber", r.URL.Query().Get("action"))
+		w.Write([]byte(`{"jsonrpc":"2.0","id":83,"result":"0x1406f40"}`))
+	}))
+	defer server.Close()
+
+	client := NewEtherscanClient("dummy_api_key")
+	client.BaseURL = server.URL
+
+	number, err := client.GetBlockNumber()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(21000000), number)
+}
+
+func TestGetFinalizedBlockNumber(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "eth_getBlockByNumber", r.URL.Query().Get("action"))
+		assert.Equal(t, "finalized", r.URL.Query().Get("tag"))
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"number":"0x1406f20","timestamp":"0x6553f100"}}`))
+	}))
+	defer server.Close()
+
+	client := NewEtherscanClient("dummy_api_key")
+	client.BaseURL = server.URL
+
+	number, err := client.GetFinalizedBlockNumber()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(20999968), number)
+}
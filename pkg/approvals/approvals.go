@@ -0,0 +1,160 @@
+// Package approvals reconstructs the ERC-20 allowance history of an owner
+// from Approval events, to help audit risky unlimited approvals.
+package approvals
+
+import (
+	"encoding/csv"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/api"
+)
+
+// unlimitedThreshold treats allowances of at least 2^255 as unlimited;
+// wallets usually approve 2^256-1, but some use other very large values
+var unlimitedThreshold = new(big.Int).Lsh(big.NewInt(1), 255)
+
+// Approval is a single ERC-20 approval granted by the owner
+type Approval struct {
+	Hash        string
+	BlockNumber int64
+	LogIndex    int64
+	Timestamp   time.Time
+	Token       string
+	Spender     string
+	Allowance   *big.Int
+	Unlimited   bool
+	// Active is true for the latest non-zero approval of a token/spender
+	// pair. Allowance consumed by later transferFrom calls is not tracked.
+	Active bool
+}
+
+// FromLogs decodes Approval logs, skipping ERC-721 approvals (which index a
+// token ID instead of carrying an allowance), and marks the active ones.
+// The result is sorted chronologically.
+func FromLogs(logs []api.EventLog) ([]Approval, error) {
+	var result []Approval
+	for _, log := range logs {
+		if len(log.Topics) != 3 {
+			continue
+		}
+
+		blockNumber, err := api.ParseHexBig(log.BlockNumber)
+		if err != nil {
+			return nil, fmt.Errorf("log %s: %w", log.TransactionHash, err)
+		}
+		timestamp, err := api.ParseHexBig(log.TimeStamp)
+		if err != nil {
+			return nil, fmt.Errorf("log %s: %w", log.TransactionHash, err)
+		}
+		allowance, err := api.ParseHexBig(log.Data)
+		if err != nil {
+			return nil, fmt.Errorf("log %s: %w", log.TransactionHash, err)
+		}
+		var logIndex int64
+		if log.LogIndex != "" && log.LogIndex != "0x" {
+			idx, err := api.ParseHexBig(log.LogIndex)
+			if err != nil {
+				return nil, fmt.Errorf("log %s: %w", log.TransactionHash, err)
+			}
+			logIndex = idx.Int64()
+		}
+
+		result = append(result, Approval{
+			Hash:        log.TransactionHash,
+			BlockNumber: blockNumber.Int64(),
+			LogIndex:    logIndex,
+			Timestamp:   time.Unix(timestamp.Int64(), 0),
+			Token:       strings.ToLower(log.Address),
+			Spender:     topicAddress(log.Topics[2]),
+			Allowance:   allowance,
+			Unlimited:   allowance.Cmp(unlimitedThreshold) >= 0,
+		})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].BlockNumber != result[j].BlockNumber {
+			return result[i].BlockNumber < result[j].BlockNumber
+		}
+		return result[i].LogIndex < result[j].LogIndex
+	})
+
+	latest := make(map[string]int)
+	for i := range result {
+		latest[result[i].Token+"|"+result[i].Spender] = i
+	}
+	for _, i := range latest {
+		result[i].Active = result[i].Allowance.Sign() > 0
+	}
+
+	return result, nil
+}
+
+// CSVHeaders returns the header row of the approvals report
+func CSVHeaders() []string {
+	return []string{
+		"Transaction Hash",
+		"Block Number",
+		"Date & Time",
+		"Token Contract Address",
+		"Spender",
+		"Allowance",
+		"Unlimited",
+		"Active",
+	}
+}
+
+// CSVRecord converts an approval to a row of the approvals report
+func (a *Approval) CSVRecord() []string {
+	return []string{
+		a.Hash,
+		strconv.FormatInt(a.BlockNumber, 10),
+		a.Timestamp.Format(time.RFC3339),
+		a.Token,
+		a.Spender,
+		a.Allowance.String(),
+		strconv.FormatBool(a.Unlimited),
+		strconv.FormatBool(a.Active),
+	}
+}
+
+// WriteCSV writes the approvals report to a CSV file
+func WriteCSV(approvals []Approval, filePath string) error {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	file, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to create CSV file: %w", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+
+	if err := writer.Write(CSVHeaders()); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+	for i := range approvals {
+		if err := writer.Write(approvals[i].CSVRecord()); err != nil {
+			return fmt.Errorf("failed to write approval record: %w", err)
+		}
+	}
+	return nil
+}
+
+// topicAddress extracts the address from a 32-byte indexed topic
+func topicAddress(topic string) string {
+	topic = strings.TrimPrefix(strings.ToLower(topic), "0x")
+	if len(topic) < 40 {
+		return ""
+	}
+	return "0x" + topic[len(topic)-40:]
+}
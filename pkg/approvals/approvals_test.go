@@ -0,0 +1,80 @@
+package approvals
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/haridev22/ct-assignement/pkg/api"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	ownerTopic   = "0x000000000000000000000000a39b189482f984388a34460636fea9eb181ad1a6"
+	routerTopic  = "0x0000000000000000000000007a250d5630b4cf539739df2c5dacb4c659f2488d"
+	unlimitedHex = "0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"
+)
+
+func approvalLog(block, token, spenderTopic, data string) api.EventLog {
+	return api.EventLog{
+		Address:         token,
+		Topics:          []string{api.ApprovalTopic, ownerTopic, spenderTopic},
+		Data:            data,
+		BlockNumber:     block,
+		TimeStamp:       "0x61290000",
+		LogIndex:        "0x1",
+		TransactionHash: "0xhash" + block,
+	}
+}
+
+func TestFromLogs(t *testing.T) {
+	logs := []api.EventLog{
+		// Revoked later
+		approvalLog("0x20", "0xUSDC", routerTopic, unlimitedHex),
+		approvalLog("0x10", "0xDAI", routerTopic, "0x64"),
+		approvalLog("0x30", "0xUSDC", routerTopic, "0x0"),
+		approvalLog("0x40", "0xWETH", routerTopic, unlimitedHex),
+		// ERC-721 approval with token ID topic is skipped
+		{Address: "0xnft", Topics: []string{api.ApprovalTopic, ownerTopic, routerTopic, "0x01"}, BlockNumber: "0x50", TimeStamp: "0x1", Data: "0x"},
+	}
+
+	result, err := FromLogs(logs)
+	assert.NoError(t, err)
+	assert.Len(t, result, 4)
+
+	// Chronological order
+	assert.Equal(t, int64(16), result[0].BlockNumber)
+	assert.Equal(t, "0xdai", result[0].Token)
+	assert.Equal(t, "0x7a250d5630b4cf539739df2c5dacb4c659f2488d", result[0].Spender)
+	assert.Equal(t, "100", result[0].Allowance.String())
+	assert.True(t, result[0].Active)
+	assert.False(t, result[0].Unlimited)
+
+	assert.True(t, result[1].Unlimited)
+	assert.False(t, result[1].Active, "superseded by a revocation")
+	assert.False(t, result[2].Active, "a zero allowance is a revocation")
+
+	assert.True(t, result[3].Unlimited)
+	assert.True(t, result[3].Active)
+}
+
+func TestWriteCSV(t *testing.T) {
+	result, err := FromLogs([]api.EventLog{approvalLog("0x40", "0xWETH", routerTopic, unlimitedHex)})
+	assert.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "approvals.csv")
+	assert.NoError(t, WriteCSV(result, path))
+
+	file, err := os.Open(path)
+	assert.NoError(t, err)
+	defer file.Close()
+
+	records, err := csv.NewReader(file).ReadAll()
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, CSVHeaders(), records[0])
+	assert.Equal(t, "64", records[1][1])
+	assert.Equal(t, "true", records[1][6])
+	assert.Equal(t, "true", records[1][7])
+}
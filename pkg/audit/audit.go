@@ -0,0 +1,123 @@
+// Package audit records every outbound provider call in an append-only JSON
+// Lines log, so a report can be traced back to the requests that produced it.
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Entry is a single provider call
+type Entry struct {
+	Time     time.Time `json:"time"`
+	Provider string    `json:"provider"`
+	// Endpoint is the URL called, without query string or credentials
+	Endpoint string `json:"endpoint"`
+	// Method is the API action, such as "account.txlist" or "eth_getLogs"
+	Method string `json:"method"`
+	// ParamsHash identifies the request parameters without storing them
+	ParamsHash string `json:"params_hash"`
+	// Results is the number of items returned, or -1 for non-list results
+	Results   int    `json:"results"`
+	LatencyMS int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Log appends entries to a JSONL file. A nil *Log discards entries, so
+// clients can call Record unconditionally.
+type Log struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+}
+
+// Open opens path for appending, creating it and its directory if needed
+func Open(path string) (*Log, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	return &Log{path: path, file: file}, nil
+}
+
+// Path returns the file the log appends to
+func (l *Log) Path() string {
+	if l == nil {
+		return ""
+	}
+	return l.path
+}
+
+// Record appends e as one line
+func (l *Log) Record(e Entry) error {
+	if l == nil {
+		return nil
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, err = l.file.Write(line)
+	return err
+}
+
+// Close closes the underlying file
+func (l *Log) Close() error {
+	if l == nil {
+		return nil
+	}
+	return l.file.Close()
+}
+
+// Hash returns the hex SHA-256 of data
+func Hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// HashValues hashes query parameters in canonical (sorted) order, leaving
+// out the excluded keys such as API keys
+func HashValues(params url.Values, exclude ...string) string {
+	clean := make(url.Values, len(params))
+	for key, values := range params {
+		clean[key] = values
+	}
+	for _, key := range exclude {
+		clean.Del(key)
+	}
+	return Hash([]byte(clean.Encode()))
+}
+
+// RedactURL reduces a URL to its scheme and host, since node URLs often
+// embed API keys in their path or query
+func RedactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return "redacted"
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// ResultCount returns the number of items in a JSON array, or -1 when raw
+// is not an array
+func ResultCount(raw json.RawMessage) int {
+	var items []json.RawMessage
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return -1
+	}
+	return len(items)
+}
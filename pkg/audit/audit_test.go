@@ -0,0 +1,73 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLog_Append(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "audit.jsonl")
+
+	for run := 0; run < 2; run++ {
+		log, err := Open(path)
+		assert.NoError(t, err)
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				assert.NoError(t, log.Record(Entry{Time: time.Now(), Method: "account.txlist", Results: i}))
+			}(i)
+		}
+		wg.Wait()
+		assert.NoError(t, log.Close())
+	}
+
+	file, err := os.Open(path)
+	assert.NoError(t, err)
+	defer file.Close()
+
+	lines := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry Entry
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		assert.Equal(t, "account.txlist", entry.Method)
+		lines++
+	}
+	assert.Equal(t, 20, lines, "earlier runs must be kept")
+}
+
+func TestLog_Nil(t *testing.T) {
+	var log *Log
+	assert.NoError(t, log.Record(Entry{}))
+	assert.Equal(t, "", log.Path())
+}
+
+func TestHashValues(t *testing.T) {
+	a := url.Values{"action": {"txlist"}, "address": {"0x1"}, "apikey": {"secret1"}}
+	b := url.Values{"address": {"0x1"}, "action": {"txlist"}, "apikey": {"secret2"}}
+	assert.Equal(t, HashValues(a, "apikey"), HashValues(b, "apikey"))
+	assert.Equal(t, []string{"secret1"}, a["apikey"], "input must not be modified")
+
+	b.Set("address", "0x2")
+	assert.NotEqual(t, HashValues(a, "apikey"), HashValues(b, "apikey"))
+}
+
+func TestRedactURL(t *testing.T) {
+	assert.Equal(t, "https://mainnet.infura.io", RedactURL("https://mainnet.infura.io/v3/secret"))
+	assert.Equal(t, "redacted", RedactURL("not a url"))
+}
+
+func TestResultCount(t *testing.T) {
+	assert.Equal(t, 2, ResultCount(json.RawMessage(`[{},{}]`)))
+	assert.Equal(t, -1, ResultCount(json.RawMessage(`"0x10"`)))
+}
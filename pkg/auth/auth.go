@@ -0,0 +1,225 @@
+// Package auth authenticates HTTP requests with static API keys or signed
+// JWTs, and restricts each caller to the scopes it was granted.
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Scopes that can be granted to a key or token
+const (
+	// ScopeRead allows reading transactions, aggregates, watchlists and
+	// event streams
+	ScopeRead = "read"
+	// ScopeWatchlists allows creating, changing and deleting watchlists
+	ScopeWatchlists = "watchlists"
+	// ScopeAdmin allows everything, including runtime profiles
+	ScopeAdmin = "admin"
+)
+
+var knownScopes = map[string]bool{ScopeRead: true, ScopeWatchlists: true, ScopeAdmin: true}
+
+var (
+	// ErrUnauthenticated is returned for requests without valid credentials
+	ErrUnauthenticated = errors.New("missing or invalid credentials")
+	// ErrForbidden is returned for callers lacking the required scope
+	ErrForbidden = errors.New("insufficient scope")
+)
+
+// Identity is an authenticated caller
+type Identity struct {
+	// Name is the name of the key or the subject of the token
+	Name string
+	// Tenant is the tenant the caller acts for, or "" for the default
+	Tenant string
+	Scopes []string
+}
+
+// Allows reports whether the identity was granted scope. Admin allows every
+// scope, and managing watchlists includes reading.
+func (id Identity) Allows(scope string) bool {
+	for _, granted := range id.Scopes {
+		if granted == scope || granted == ScopeAdmin || (granted == ScopeWatchlists && scope == ScopeRead) {
+			return true
+		}
+	}
+	return false
+}
+
+// Key is a static API key
+type Key struct {
+	Name string `json:"name"`
+	// Key is the key itself. SHA256 can be given instead, as the hex SHA-256
+	// of the key, so the keys file does not hold usable secrets.
+	Key    string   `json:"key,omitempty"`
+	SHA256 string   `json:"sha256,omitempty"`
+	Tenant string   `json:"tenant,omitempty"`
+	Scopes []string `json:"scopes"`
+}
+
+type keysFile struct {
+	Keys []Key `json:"keys"`
+}
+
+// LoadKeys reads and validates the API keys file at path
+func LoadKeys(path string) ([]Key, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseKeys(f)
+}
+
+// ParseKeys reads keys in the format {"keys": [{"name": ..., ...}]} and
+// validates them. Unknown fields are errors, so a misspelled scopes field
+// does not leave a key without access.
+func ParseKeys(r io.Reader) ([]Key, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	var f keysFile
+	if err := dec.Decode(&f); err != nil {
+		return nil, fmt.Errorf("invalid keys: %w", err)
+	}
+	seen := make(map[string]bool)
+	for i := range f.Keys {
+		key := &f.Keys[i]
+		if key.Name == "" {
+			return nil, fmt.Errorf("key %d has no name", i+1)
+		}
+		if seen[key.Name] {
+			return nil, fmt.Errorf("key %q is defined twice", key.Name)
+		}
+		seen[key.Name] = true
+		if (key.Key == "") == (key.SHA256 == "") {
+			return nil, fmt.Errorf("key %q needs exactly one of key and sha256", key.Name)
+		}
+		if key.SHA256 != "" {
+			if sum, err := hex.DecodeString(key.SHA256); err != nil || len(sum) != sha256.Size {
+				return nil, fmt.Errorf("key %q has an invalid sha256 %q", key.Name, key.SHA256)
+			}
+			key.SHA256 = strings.ToLower(key.SHA256)
+		}
+		if err := checkScopes(key.Scopes); err != nil {
+			return nil, fmt.Errorf("key %q: %w", key.Name, err)
+		}
+	}
+	return f.Keys, nil
+}
+
+func checkScopes(scopes []string) error {
+	if len(scopes) == 0 {
+		return errors.New("no scopes granted")
+	}
+	for _, scope := range scopes {
+		if !knownScopes[scope] {
+			return fmt.Errorf("unknown scope %q (use read, watchlists or admin)", scope)
+		}
+	}
+	return nil
+}
+
+// Authenticator identifies the callers of HTTP requests. Credentials are
+// read from an "Authorization: Bearer" header, an X-API-Key header or, for
+// event streams opened by browsers that cannot set headers, an
+// access_token query parameter.
+type Authenticator struct {
+	Keys []Key
+	// JWTSecret verifies HS256 tokens. Without it only keys are accepted.
+	JWTSecret []byte
+	// Issuer and Audience, when set, must match the iss and aud claims
+	Issuer   string
+	Audience string
+
+	now func() time.Time
+}
+
+// Authenticate returns the identity of the caller of r
+func (a *Authenticator) Authenticate(r *http.Request) (Identity, error) {
+	credential := r.Header.Get("X-API-Key")
+	if header := r.Header.Get("Authorization"); header != "" {
+		scheme, token, ok := strings.Cut(header, " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") {
+			return Identity{}, ErrUnauthenticated
+		}
+		credential = strings.TrimSpace(token)
+	}
+	if credential == "" {
+		credential = r.URL.Query().Get("access_token")
+	}
+	if credential == "" {
+		return Identity{}, ErrUnauthenticated
+	}
+
+	if len(a.JWTSecret) > 0 && strings.Count(credential, ".") == 2 {
+		return a.verifyJWT(credential)
+	}
+	sum := sha256.Sum256([]byte(credential))
+	for _, key := range a.Keys {
+		expected := sha256.Sum256([]byte(key.Key))
+		if key.SHA256 != "" {
+			hex.Decode(expected[:], []byte(key.SHA256))
+		}
+		// Comparing digests keeps the time independent of the key length
+		if subtle.ConstantTimeCompare(sum[:], expected[:]) == 1 {
+			return Identity{Name: key.Name, Tenant: key.Tenant, Scopes: key.Scopes}, nil
+		}
+	}
+	return Identity{}, ErrUnauthenticated
+}
+
+// Require returns a handler that answers 401 to unauthenticated requests
+// and 403 to callers without the scope returned by scope, and passes the
+// others to next with their identity in the context
+func (a *Authenticator) Require(scope func(r *http.Request) string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := a.Authenticate(r)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="eth-tx-exporter"`)
+			writeError(w, http.StatusUnauthorized, err)
+			return
+		}
+		if required := scope(r); !id.Allows(required) {
+			writeError(w, http.StatusForbidden, fmt.Errorf("%w: %s requires the %s scope", ErrForbidden, r.URL.Path, required))
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(WithIdentity(r.Context(), id)))
+	})
+}
+
+type contextKey struct{}
+
+// WithIdentity returns a copy of ctx carrying id
+func WithIdentity(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the identity of an authenticated request
+func FromContext(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(contextKey{}).(Identity)
+	return id, ok
+}
+
+func (a *Authenticator) clock() time.Time {
+	if a.now != nil {
+		return a.now()
+	}
+	return time.Now()
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,97 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseKeys(t *testing.T) {
+	sum := sha256.Sum256([]byte("hashed-key"))
+	keys, err := ParseKeys(strings.NewReader(`{"keys": [
+		{"name": "dashboard", "key": "plain-key", "scopes": ["read"]},
+		{"name": "ops", "sha256": "` + strings.ToUpper(hex.EncodeToString(sum[:])) + `", "tenant": "treasury", "scopes": ["watchlists"]}
+	]}`))
+	assert.NoError(t, err)
+	if assert.Len(t, keys, 2) {
+		assert.Equal(t, hex.EncodeToString(sum[:]), keys[1].SHA256)
+	}
+
+	tests := map[string]string{
+		"unknown field": `{"keys": [{"name": "a", "key": "k", "scope": ["read"]}]}`,
+		"no name":       `{"keys": [{"key": "k", "scopes": ["read"]}]}`,
+		"duplicate":     `{"keys": [{"name": "a", "key": "k", "scopes": ["read"]}, {"name": "a", "key": "l", "scopes": ["read"]}]}`,
+		"no secret":     `{"keys": [{"name": "a", "scopes": ["read"]}]}`,
+		"both secrets":  `{"keys": [{"name": "a", "key": "k", "sha256": "00", "scopes": ["read"]}]}`,
+		"bad sha256":    `{"keys": [{"name": "a", "sha256": "abc", "scopes": ["read"]}]}`,
+		"no scopes":     `{"keys": [{"name": "a", "key": "k"}]}`,
+		"unknown scope": `{"keys": [{"name": "a", "key": "k", "scopes": ["write"]}]}`,
+	}
+	for name, input := range tests {
+		_, err := ParseKeys(strings.NewReader(input))
+		assert.Error(t, err, name)
+	}
+}
+
+func TestIdentity_Allows(t *testing.T) {
+	reader := Identity{Scopes: []string{ScopeRead}}
+	manager := Identity{Scopes: []string{ScopeWatchlists}}
+	admin := Identity{Scopes: []string{ScopeAdmin}}
+
+	assert.True(t, reader.Allows(ScopeRead))
+	assert.False(t, reader.Allows(ScopeWatchlists))
+	assert.True(t, manager.Allows(ScopeRead))
+	assert.False(t, manager.Allows(ScopeAdmin))
+	assert.True(t, admin.Allows(ScopeWatchlists))
+	assert.True(t, admin.Allows(ScopeAdmin))
+}
+
+func TestAuthenticator_Require(t *testing.T) {
+	sum := sha256.Sum256([]byte("ops-key"))
+	a := &Authenticator{
+		Keys: []Key{
+			{Name: "dashboard", Key: "read-key", Scopes: []string{ScopeRead}},
+			{Name: "ops", SHA256: hex.EncodeToString(sum[:]), Tenant: "treasury", Scopes: []string{ScopeWatchlists}},
+		},
+		JWTSecret: []byte("s3cret"),
+		now:       func() time.Time { return testNow },
+	}
+	scope := func(r *http.Request) string {
+		if r.Method == http.MethodGet {
+			return ScopeRead
+		}
+		return ScopeWatchlists
+	}
+	var seen Identity
+	handler := a.Require(scope, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen, _ = FromContext(r.Context())
+	}))
+	serve := func(method, target string, header ...string) int {
+		req := httptest.NewRequest(method, target, nil)
+		for i := 0; i+1 < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	token := sign("s3cret", "HS256", map[string]interface{}{"sub": "ci", "exp": testNow.Add(time.Hour).Unix(), "scope": "read"})
+
+	assert.Equal(t, http.StatusUnauthorized, serve(http.MethodGet, "/"))
+	assert.Equal(t, http.StatusUnauthorized, serve(http.MethodGet, "/", "X-API-Key", "wrong"))
+	assert.Equal(t, http.StatusUnauthorized, serve(http.MethodGet, "/", "Authorization", "Basic cmVhZC1rZXk="))
+	assert.Equal(t, http.StatusOK, serve(http.MethodGet, "/", "X-API-Key", "read-key"))
+	assert.Equal(t, "dashboard", seen.Name)
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodPost, "/", "Authorization", "Bearer read-key"))
+	assert.Equal(t, http.StatusOK, serve(http.MethodPost, "/", "Authorization", "Bearer ops-key"))
+	assert.Equal(t, "treasury", seen.Tenant)
+	assert.Equal(t, http.StatusOK, serve(http.MethodGet, "/events?access_token="+token))
+	assert.Equal(t, "ci", seen.Name)
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodDelete, "/", "Authorization", "Bearer "+token))
+}
@@ -0,0 +1,115 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// claims are the JWT claims read by the authenticator. Scopes are given
+// space-separated in scope, as in OAuth 2.0 access tokens.
+type claims struct {
+	Subject   string   `json:"sub"`
+	Issuer    string   `json:"iss"`
+	Audience  audience `json:"aud"`
+	ExpiresAt *int64   `json:"exp"`
+	NotBefore *int64   `json:"nbf"`
+	Scope     string   `json:"scope"`
+	Tenant    string   `json:"tenant"`
+}
+
+// audience is the aud claim, which is a string or a list of strings
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("aud must be a string or a list of strings")
+	}
+	*a = list
+	return nil
+}
+
+// leeway tolerates clock differences with the issuer
+const leeway = time.Minute
+
+// verifyJWT checks the signature and claims of an HS256 token. Tokens
+// without an expiry are rejected, so a leaked token does not work forever.
+func (a *Authenticator) verifyJWT(token string) (Identity, error) {
+	parts := strings.Split(token, ".")
+	invalid := func(reason string) (Identity, error) {
+		return Identity{}, fmt.Errorf("%w: %s", ErrUnauthenticated, reason)
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return invalid("malformed token header")
+	}
+	// Checking the algorithm first rejects "none" and key confusion
+	if header.Alg != "HS256" {
+		return invalid(fmt.Sprintf("unsupported algorithm %q", header.Alg))
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return invalid("malformed signature")
+	}
+	mac := hmac.New(sha256.New, a.JWTSecret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return invalid("bad signature")
+	}
+
+	var c claims
+	if err := decodeSegment(parts[1], &c); err != nil {
+		return invalid("malformed claims")
+	}
+	now := a.clock()
+	if c.ExpiresAt == nil {
+		return invalid("token has no expiry")
+	}
+	if now.After(time.Unix(*c.ExpiresAt, 0).Add(leeway)) {
+		return invalid("token expired")
+	}
+	if c.NotBefore != nil && now.Add(leeway).Before(time.Unix(*c.NotBefore, 0)) {
+		return invalid("token not valid yet")
+	}
+	if a.Issuer != "" && c.Issuer != a.Issuer {
+		return invalid("wrong issuer")
+	}
+	if a.Audience != "" && !c.Audience.contains(a.Audience) {
+		return invalid("wrong audience")
+	}
+	scopes := strings.Fields(c.Scope)
+	if err := checkScopes(scopes); err != nil {
+		return invalid(err.Error())
+	}
+	return Identity{Name: c.Subject, Tenant: c.Tenant, Scopes: scopes}, nil
+}
+
+func (a audience) contains(name string) bool {
+	for _, aud := range a {
+		if aud == name {
+			return true
+		}
+	}
+	return false
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testNow = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+// sign builds a token of claims whose header names alg, signed with HS256
+func sign(secret, alg string, claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": alg, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifyJWT(t *testing.T) {
+	a := &Authenticator{JWTSecret: []byte("s3cret"), Issuer: "https://idp.example", Audience: "exporter", now: func() time.Time { return testNow }}
+	valid := func() map[string]interface{} {
+		return map[string]interface{}{
+			"sub": "alice", "iss": "https://idp.example", "aud": []string{"exporter", "other"},
+			"exp": testNow.Add(time.Hour).Unix(), "scope": "read watchlists", "tenant": "treasury",
+		}
+	}
+
+	id, err := a.verifyJWT(sign("s3cret", "HS256", valid()))
+	assert.NoError(t, err)
+	assert.Equal(t, Identity{Name: "alice", Tenant: "treasury", Scopes: []string{"read", "watchlists"}}, id)
+
+	tests := []struct {
+		name   string
+		secret string
+		alg    string
+		change func(map[string]interface{})
+	}{
+		{"wrong secret", "other", "HS256", func(map[string]interface{}) {}},
+		{"alg none", "s3cret", "none", func(map[string]interface{}) {}},
+		{"expired", "s3cret", "HS256", func(c map[string]interface{}) { c["exp"] = testNow.Add(-2 * time.Minute).Unix() }},
+		{"no expiry", "s3cret", "HS256", func(c map[string]interface{}) { delete(c, "exp") }},
+		{"not before", "s3cret", "HS256", func(c map[string]interface{}) { c["nbf"] = testNow.Add(time.Hour).Unix() }},
+		{"issuer", "s3cret", "HS256", func(c map[string]interface{}) { c["iss"] = "https://evil.example" }},
+		{"audience", "s3cret", "HS256", func(c map[string]interface{}) { c["aud"] = "other" }},
+		{"unknown scope", "s3cret", "HS256", func(c map[string]interface{}) { c["scope"] = "read write" }},
+		{"no scope", "s3cret", "HS256", func(c map[string]interface{}) { delete(c, "scope") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := valid()
+			tt.change(c)
+			_, err := a.verifyJWT(sign(tt.secret, tt.alg, c))
+			assert.True(t, errors.Is(err, ErrUnauthenticated), "got %v", err)
+		})
+	}
+}
+
+func TestVerifyJWT_Leeway(t *testing.T) {
+	a := &Authenticator{JWTSecret: []byte("s3cret"), now: func() time.Time { return testNow }}
+	token := sign("s3cret", "HS256", map[string]interface{}{"exp": testNow.Add(-30 * time.Second).Unix(), "scope": "read", "aud": "anything"})
+
+	_, err := a.verifyJWT(token)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,175 @@
+package bigquery
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Scope is the OAuth scope requested for BigQuery
+const Scope = "https://www.googleapis.com/auth/bigquery"
+
+// TokenSource returns OAuth access tokens for API requests
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// StaticToken is an access token obtained elsewhere, such as from
+// gcloud auth print-access-token
+type StaticToken string
+
+// Token implements TokenSource
+func (t StaticToken) Token(context.Context) (string, error) {
+	return string(t), nil
+}
+
+// ServiceAccount exchanges a signed JWT for access tokens, as described by
+// the JSON key of a Google Cloud service account. Tokens are reused until
+// shortly before they expire.
+type ServiceAccount struct {
+	Email        string
+	PrivateKeyID string
+	PrivateKey   *rsa.PrivateKey
+	TokenURI     string
+	// ProjectID is the project the service account belongs to
+	ProjectID  string
+	HTTPClient *http.Client
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// serviceAccountFile is the JSON key file of a service account
+type serviceAccountFile struct {
+	Type         string `json:"type"`
+	ProjectID    string `json:"project_id"`
+	PrivateKeyID string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	ClientEmail  string `json:"client_email"`
+	TokenURI     string `json:"token_uri"`
+}
+
+// LoadServiceAccount reads the JSON key file of a service account
+func LoadServiceAccount(path string) (*ServiceAccount, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseServiceAccount(data)
+}
+
+// ParseServiceAccount reads the JSON key of a service account
+func ParseServiceAccount(data []byte) (*ServiceAccount, error) {
+	var f serviceAccountFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("invalid service account key: %w", err)
+	}
+	if f.Type != "service_account" || f.ClientEmail == "" || f.PrivateKey == "" {
+		return nil, errors.New("invalid service account key: not a service_account key with client_email and private_key")
+	}
+	block, _ := pem.Decode([]byte(f.PrivateKey))
+	if block == nil {
+		return nil, errors.New("invalid service account key: private_key is not PEM")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		if parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes); err != nil {
+			return nil, fmt.Errorf("invalid service account key: %w", err)
+		}
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("invalid service account key: private_key is not an RSA key")
+	}
+	if f.TokenURI == "" {
+		f.TokenURI = "https://oauth2.googleapis.com/token"
+	}
+	return &ServiceAccount{Email: f.ClientEmail, PrivateKeyID: f.PrivateKeyID, PrivateKey: key, TokenURI: f.TokenURI, ProjectID: f.ProjectID}, nil
+}
+
+// Token implements TokenSource
+func (sa *ServiceAccount) Token(ctx context.Context) (string, error) {
+	sa.mu.Lock()
+	defer sa.mu.Unlock()
+	now := time.Now()
+	if sa.token != "" && now.Before(sa.expires.Add(-time.Minute)) {
+		return sa.token, nil
+	}
+
+	assertion, err := sa.assertion(now)
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sa.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	client := sa.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get access token: %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to get access token: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &token); err != nil || token.AccessToken == "" {
+		return "", errors.New("failed to get access token: no access_token in the response")
+	}
+	sa.token, sa.expires = token.AccessToken, now.Add(time.Duration(token.ExpiresIn)*time.Second)
+	return sa.token, nil
+}
+
+// assertion builds the RS256 JWT that is exchanged for an access token
+func (sa *ServiceAccount) assertion(now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": sa.PrivateKeyID})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]interface{}{
+		"iss":   sa.Email,
+		"scope": Scope,
+		"aud":   sa.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, sa.PrivateKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
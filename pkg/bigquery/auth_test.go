@@ -0,0 +1,95 @@
+package bigquery
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testServiceAccountKey(t *testing.T, tokenURI string) (*rsa.PrivateKey, []byte) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, _ := x509.MarshalPKCS8PrivateKey(key)
+	data, _ := json.Marshal(map[string]string{
+		"type":           "service_account",
+		"project_id":     "proj",
+		"private_key_id": "kid1",
+		"private_key":    string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"client_email":   "loader@proj.iam.gserviceaccount.com",
+		"token_uri":      tokenURI,
+	})
+	return key, data
+}
+
+func TestServiceAccount_Token(t *testing.T) {
+	var calls int
+	var claims map[string]interface{}
+	var key *rsa.PrivateKey
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		r.ParseForm()
+		assert.Equal(t, "urn:ietf:params:oauth:grant-type:jwt-bearer", r.Form.Get("grant_type"))
+		parts := strings.Split(r.Form.Get("assertion"), ".")
+		digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		signature, _ := base64.RawURLEncoding.DecodeString(parts[2])
+		assert.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature))
+		payload, _ := base64.RawURLEncoding.DecodeString(parts[1])
+		json.Unmarshal(payload, &claims)
+		w.Write([]byte(`{"access_token": "ya29.token", "expires_in": 3599, "token_type": "Bearer"}`))
+	}))
+	defer srv.Close()
+	key, data := testServiceAccountKey(t, srv.URL)
+
+	sa, err := ParseServiceAccount(data)
+	assert.NoError(t, err)
+	assert.Equal(t, "proj", sa.ProjectID)
+	token, err := sa.Token(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "ya29.token", token)
+	token, _ = sa.Token(context.Background())
+	assert.Equal(t, "ya29.token", token)
+
+	assert.Equal(t, 1, calls, "the token is reused until it expires")
+	assert.Equal(t, "loader@proj.iam.gserviceaccount.com", claims["iss"])
+	assert.Equal(t, Scope, claims["scope"])
+	assert.Equal(t, srv.URL, claims["aud"])
+}
+
+func TestServiceAccount_TokenError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error": "invalid_grant"}`, http.StatusBadRequest)
+	}))
+	defer srv.Close()
+	_, data := testServiceAccountKey(t, srv.URL)
+	sa, _ := ParseServiceAccount(data)
+
+	_, err := sa.Token(context.Background())
+
+	assert.ErrorContains(t, err, "invalid_grant")
+}
+
+func TestParseServiceAccount_Invalid(t *testing.T) {
+	for name, input := range map[string]string{
+		"not json":      `nope`,
+		"user account":  `{"type": "authorized_user", "client_email": "a", "private_key": "b"}`,
+		"not pem":       `{"type": "service_account", "client_email": "a", "private_key": "b"}`,
+		"missing email": `{"type": "service_account", "private_key": "b"}`,
+	} {
+		_, err := ParseServiceAccount([]byte(input))
+		assert.Error(t, err, name)
+	}
+}
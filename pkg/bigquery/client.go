@@ -0,0 +1,434 @@
+// Package bigquery loads transaction histories into Google BigQuery
+// tables through its REST API, so analytics teams can query them with SQL.
+package bigquery
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+)
+
+const (
+	// DefaultBaseURL is the endpoint of the BigQuery API
+	DefaultBaseURL = "https://bigquery.googleapis.com/bigquery/v2"
+	// DefaultUploadURL is the endpoint of load job uploads
+	DefaultUploadURL = "https://bigquery.googleapis.com/upload/bigquery/v2"
+	// DefaultBatchRows is the number of rows sent in each load job. Rows
+	// take about 1 KB, which keeps uploads near 5 MB.
+	DefaultBatchRows = 5000
+	// DefaultTimeout bounds each API request
+	DefaultTimeout = 2 * time.Minute
+)
+
+// jobPollInterval is the time between checks of a running job
+var jobPollInterval = time.Second
+
+// tableID matches the dataset and table names BigQuery accepts
+var tableID = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// Client loads rows into the tables of one dataset
+type Client struct {
+	Project string
+	Dataset string
+	// Location is where the dataset is created, such as US or EU
+	Location  string
+	Token     TokenSource
+	BatchRows int
+	// BaseURL and UploadURL default to DefaultBaseURL and DefaultUploadURL
+	BaseURL    string
+	UploadURL  string
+	HTTPClient *http.Client
+
+	now func() time.Time
+}
+
+// ValidateName checks a dataset or table name
+func ValidateName(name string) error {
+	if !tableID.MatchString(name) || len(name) > 1024 {
+		return fmt.Errorf("invalid BigQuery name %q (use letters, digits and underscores)", name)
+	}
+	return nil
+}
+
+// Replace makes the rows of wallet on chain between startBlock and
+// endBlock in table exactly txs. The table and dataset are created when
+// missing, and columns added to the export schema since are added to the
+// table. Rows are loaded into a staging table in batches, then swapped in
+// by one transaction, so queries never see a partial load and repeated
+// syncs do not duplicate rows. Rows of other chains are left alone; txs
+// must all be of chain, and rows without a chain are loaded as of chain.
+func (c *Client) Replace(ctx context.Context, table, wallet, chain string, startBlock, endBlock int64, txs []models.Transaction) error {
+	if err := ValidateName(table); err != nil {
+		return err
+	}
+	if chain == "" {
+		return errors.New("no chain given")
+	}
+	for i := range txs {
+		if txs[i].Chain != "" && txs[i].Chain != chain {
+			return fmt.Errorf("row %s is on %s, not %s; load each chain separately", txs[i].Hash, txs[i].Chain, chain)
+		}
+	}
+	if err := c.ensureDataset(ctx); err != nil {
+		return err
+	}
+	schema := Schema()
+	if err := c.ensureTable(ctx, table, schema); err != nil {
+		return err
+	}
+
+	staging := table + "_staging_" + nonWord.ReplaceAllString(strings.ToLower(chain+"_"+wallet), "_")
+	if len(txs) > 0 {
+		defer c.deleteTable(context.WithoutCancel(ctx), staging)
+		loadedAt := c.clock()
+		batch := c.BatchRows
+		if batch <= 0 {
+			batch = DefaultBatchRows
+		}
+		for start := 0; start < len(txs); start += batch {
+			end := min(start+batch, len(txs))
+			disposition := "WRITE_APPEND"
+			if start == 0 {
+				disposition = "WRITE_TRUNCATE"
+			}
+			var data bytes.Buffer
+			enc := json.NewEncoder(&data)
+			for i := start; i < end; i++ {
+				row := Row(wallet, &txs[i], loadedAt)
+				row[ChainField] = chain
+				if err := enc.Encode(row); err != nil {
+					return err
+				}
+			}
+			if err := c.load(ctx, staging, schema, disposition, data.Bytes()); err != nil {
+				return fmt.Errorf("failed to load rows %d to %d: %w", start+1, end, err)
+			}
+		}
+	}
+
+	names := make([]string, len(schema))
+	for i, field := range schema {
+		names[i] = "`" + field.Name + "`"
+	}
+	// Rows loaded without a chain are Ethereum rows, as rows without one
+	// are everywhere else
+	script := fmt.Sprintf("BEGIN TRANSACTION;\nDELETE FROM %s WHERE wallet = @wallet AND IFNULL(%s, 'ethereum') = @chain AND block BETWEEN @start AND @end;\n", c.ref(table), ChainField)
+	if len(txs) > 0 {
+		columns := strings.Join(names, ", ")
+		script += fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s;\n", c.ref(table), columns, columns, c.ref(staging))
+	}
+	script += "COMMIT TRANSACTION;"
+	return c.query(ctx, script, map[string]interface{}{"wallet": strings.ToLower(wallet), "chain": chain, "start": startBlock, "end": endBlock})
+}
+
+var nonWord = regexp.MustCompile(`[^a-z0-9_]`)
+
+// ref returns the quoted SQL name of a table of the dataset
+func (c *Client) ref(table string) string {
+	return fmt.Sprintf("`%s.%s.%s`", c.Project, c.Dataset, table)
+}
+
+func (c *Client) ensureDataset(ctx context.Context) error {
+	path := fmt.Sprintf("/projects/%s/datasets/%s", url.PathEscape(c.Project), url.PathEscape(c.Dataset))
+	err := c.call(ctx, http.MethodGet, path, nil, nil)
+	if !isStatus(err, http.StatusNotFound) {
+		return err
+	}
+	body := map[string]interface{}{
+		"datasetReference": map[string]string{"projectId": c.Project, "datasetId": c.Dataset},
+	}
+	if c.Location != "" {
+		body["location"] = c.Location
+	}
+	err = c.call(ctx, http.MethodPost, fmt.Sprintf("/projects/%s/datasets", url.PathEscape(c.Project)), body, nil)
+	// Another run may have created it meanwhile
+	if isStatus(err, http.StatusConflict) {
+		return nil
+	}
+	return err
+}
+
+// tableResource is the part of a table resource the client reads and writes
+type tableResource struct {
+	Schema struct {
+		Fields []Field `json:"fields"`
+	} `json:"schema"`
+}
+
+// ensureTable creates table partitioned by month and clustered by wallet,
+// or adds the fields of schema it lacks
+func (c *Client) ensureTable(ctx context.Context, table string, schema []Field) error {
+	path := fmt.Sprintf("/projects/%s/datasets/%s/tables/%s", url.PathEscape(c.Project), url.PathEscape(c.Dataset), url.PathEscape(table))
+	var existing tableResource
+	err := c.call(ctx, http.MethodGet, path, nil, &existing)
+	if isStatus(err, http.StatusNotFound) {
+		body := map[string]interface{}{
+			"tableReference":   map[string]string{"projectId": c.Project, "datasetId": c.Dataset, "tableId": table},
+			"schema":           map[string]interface{}{"fields": schema},
+			"timePartitioning": map[string]string{"type": "MONTH", "field": "timestamp"},
+			"clustering":       map[string][]string{"fields": {WalletField}},
+		}
+		err = c.call(ctx, http.MethodPost, fmt.Sprintf("/projects/%s/datasets/%s/tables", url.PathEscape(c.Project), url.PathEscape(c.Dataset)), body, nil)
+		if isStatus(err, http.StatusConflict) {
+			return c.ensureTable(ctx, table, schema)
+		}
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	have := make(map[string]bool, len(existing.Schema.Fields))
+	for _, field := range existing.Schema.Fields {
+		have[field.Name] = true
+	}
+	fields := existing.Schema.Fields
+	for _, field := range schema {
+		if !have[field.Name] {
+			// Added columns must be nullable, as earlier rows lack them
+			field.Mode = "NULLABLE"
+			fields = append(fields, field)
+		}
+	}
+	if len(fields) == len(existing.Schema.Fields) {
+		return nil
+	}
+	return c.call(ctx, http.MethodPatch, path, map[string]interface{}{"schema": map[string]interface{}{"fields": fields}}, nil)
+}
+
+// job is the part of a job resource the client reads
+type job struct {
+	JobReference struct {
+		JobID    string `json:"jobId"`
+		Location string `json:"location"`
+	} `json:"jobReference"`
+	Status struct {
+		State       string     `json:"state"`
+		ErrorResult *jobError  `json:"errorResult"`
+		Errors      []jobError `json:"errors"`
+	} `json:"status"`
+}
+
+type jobError struct {
+	Reason   string `json:"reason"`
+	Message  string `json:"message"`
+	Location string `json:"location"`
+}
+
+// load uploads NDJSON rows into table in a load job and waits for it
+func (c *Client) load(ctx context.Context, table string, schema []Field, disposition string, data []byte) error {
+	config := map[string]interface{}{
+		"configuration": map[string]interface{}{
+			"load": map[string]interface{}{
+				"destinationTable":  map[string]string{"projectId": c.Project, "datasetId": c.Dataset, "tableId": table},
+				"schema":            map[string]interface{}{"fields": schema},
+				"sourceFormat":      "NEWLINE_DELIMITED_JSON",
+				"writeDisposition":  disposition,
+				"createDisposition": "CREATE_IF_NEEDED",
+			},
+		},
+		"jobReference": c.jobReference(),
+	}
+	metadata, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for _, part := range []struct {
+		contentType string
+		data        []byte
+	}{{"application/json; charset=UTF-8", metadata}, {"application/octet-stream", data}} {
+		w, err := mw.CreatePart(textproto.MIMEHeader{"Content-Type": {part.contentType}})
+		if err != nil {
+			return err
+		}
+		w.Write(part.data)
+	}
+	mw.Close()
+
+	endpoint := c.UploadURL
+	if endpoint == "" {
+		endpoint = DefaultUploadURL
+	}
+	endpoint += fmt.Sprintf("/projects/%s/jobs?uploadType=multipart", url.PathEscape(c.Project))
+	var started job
+	if err := c.do(ctx, http.MethodPost, endpoint, "multipart/related; boundary="+mw.Boundary(), body.Bytes(), &started); err != nil {
+		return err
+	}
+	return c.wait(ctx, started)
+}
+
+// query runs a GoogleSQL script with named parameters and waits for it
+func (c *Client) query(ctx context.Context, sql string, params map[string]interface{}) error {
+	var parameters []map[string]interface{}
+	for name, value := range params {
+		typ := "STRING"
+		if _, ok := value.(int64); ok {
+			typ = "INT64"
+		}
+		parameters = append(parameters, map[string]interface{}{
+			"name":           name,
+			"parameterType":  map[string]string{"type": typ},
+			"parameterValue": map[string]string{"value": fmt.Sprint(value)},
+		})
+	}
+	body := map[string]interface{}{
+		"configuration": map[string]interface{}{
+			"query": map[string]interface{}{
+				"query":           sql,
+				"useLegacySql":    false,
+				"parameterMode":   "NAMED",
+				"queryParameters": parameters,
+			},
+		},
+		"jobReference": c.jobReference(),
+	}
+	var started job
+	if err := c.call(ctx, http.MethodPost, fmt.Sprintf("/projects/%s/jobs", url.PathEscape(c.Project)), body, &started); err != nil {
+		return err
+	}
+	return c.wait(ctx, started)
+}
+
+func (c *Client) jobReference() map[string]string {
+	ref := map[string]string{"projectId": c.Project}
+	if c.Location != "" {
+		ref["location"] = c.Location
+	}
+	return ref
+}
+
+// wait polls a job until it is done, returning its error
+func (c *Client) wait(ctx context.Context, j job) error {
+	path := fmt.Sprintf("/projects/%s/jobs/%s", url.PathEscape(c.Project), url.PathEscape(j.JobReference.JobID))
+	if j.JobReference.Location != "" {
+		path += "?location=" + url.QueryEscape(j.JobReference.Location)
+	}
+	for j.Status.State != "DONE" {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(jobPollInterval):
+		}
+		if err := c.call(ctx, http.MethodGet, path, nil, &j); err != nil {
+			return err
+		}
+	}
+	if result := j.Status.ErrorResult; result != nil {
+		message := fmt.Sprintf("job %s failed: %s", j.JobReference.JobID, result.Message)
+		// The first row errors point at the offending values
+		for i, e := range j.Status.Errors {
+			if i == 3 {
+				break
+			}
+			if e.Message != result.Message {
+				message += "; " + e.Message
+			}
+		}
+		return errors.New(message)
+	}
+	return nil
+}
+
+func (c *Client) deleteTable(ctx context.Context, table string) error {
+	return c.call(ctx, http.MethodDelete, fmt.Sprintf("/projects/%s/datasets/%s/tables/%s", url.PathEscape(c.Project), url.PathEscape(c.Dataset), url.PathEscape(table)), nil, nil)
+}
+
+// statusError is an error response of the API
+type statusError struct {
+	Status  int
+	Message string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("BigQuery returned %d: %s", e.Status, e.Message)
+}
+
+func isStatus(err error, status int) bool {
+	var se *statusError
+	return errors.As(err, &se) && se.Status == status
+}
+
+// call sends a JSON request to path of the API and decodes the response
+// into out
+func (c *Client) call(ctx context.Context, method, path string, in, out interface{}) error {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return err
+		}
+	}
+	base := c.BaseURL
+	if base == "" {
+		base = DefaultBaseURL
+	}
+	return c.do(ctx, method, base+path, "application/json", body, out)
+}
+
+func (c *Client) do(ctx context.Context, method, endpoint, contentType string, body []byte, out interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, DefaultTimeout)
+	defer cancel()
+	token, err := c.Token.Token(ctx)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var apiError struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		message := strings.TrimSpace(string(data))
+		if json.Unmarshal(data, &apiError) == nil && apiError.Error.Message != "" {
+			message = apiError.Error.Message
+		}
+		return &statusError{Status: resp.StatusCode, Message: message}
+	}
+	if out != nil && len(data) > 0 {
+		return json.Unmarshal(data, out)
+	}
+	return nil
+}
+
+func (c *Client) clock() time.Time {
+	if c.now != nil {
+		return c.now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,200 @@
+package bigquery
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeAPI records the calls a client makes to a BigQuery project with one
+// dataset
+type fakeAPI struct {
+	mu      sync.Mutex
+	tables  map[string][]Field
+	dataset bool
+	// loads are the row counts and dispositions of load jobs by table
+	loads   []string
+	rows    map[string]int
+	queries []string
+	params  []interface{}
+	deleted []string
+	failJob string
+}
+
+func newFakeAPI(t *testing.T) (*fakeAPI, *Client) {
+	f := &fakeAPI{tables: make(map[string][]Field), rows: make(map[string]int)}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	jobPollInterval = time.Millisecond
+	c := &Client{Project: "proj", Dataset: "wallets", Location: "EU", Token: StaticToken("token"), BaseURL: srv.URL, UploadURL: srv.URL + "/upload", BatchRows: 2}
+	return f, c
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if r.Header.Get("Authorization") != "Bearer token" {
+		http.Error(w, `{"error": {"message": "unauthenticated"}}`, http.StatusUnauthorized)
+		return
+	}
+	const tables = "/projects/proj/datasets/wallets/tables"
+	path := r.URL.Path
+	switch {
+	case r.Method == http.MethodGet && path == "/projects/proj/datasets/wallets":
+		if !f.dataset {
+			http.Error(w, `{"error": {"message": "Not found: Dataset proj:wallets"}}`, http.StatusNotFound)
+		}
+	case r.Method == http.MethodPost && path == "/projects/proj/datasets":
+		f.dataset = true
+	case r.Method == http.MethodGet && strings.HasPrefix(path, tables+"/"):
+		fields, ok := f.tables[strings.TrimPrefix(path, tables+"/")]
+		if !ok {
+			http.Error(w, `{"error": {"message": "Not found: Table"}}`, http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"schema": map[string]interface{}{"fields": fields}})
+	case (r.Method == http.MethodPost && path == tables) || r.Method == http.MethodPatch:
+		var table struct {
+			TableReference struct{ TableID string }
+			Schema         struct{ Fields []Field }
+		}
+		json.NewDecoder(r.Body).Decode(&table)
+		name := table.TableReference.TableID
+		if name == "" {
+			name = strings.TrimPrefix(path, tables+"/")
+		}
+		f.tables[name] = table.Schema.Fields
+	case r.Method == http.MethodDelete:
+		f.deleted = append(f.deleted, strings.TrimPrefix(path, tables+"/"))
+	case r.Method == http.MethodPost && path == "/upload/projects/proj/jobs":
+		f.upload(w, r)
+	case r.Method == http.MethodPost && path == "/projects/proj/jobs":
+		var job struct {
+			Configuration struct {
+				Query struct {
+					Query           string
+					QueryParameters []struct {
+						Name           string
+						ParameterValue struct{ Value string }
+					}
+				}
+			}
+		}
+		json.NewDecoder(r.Body).Decode(&job)
+		f.queries = append(f.queries, job.Configuration.Query.Query)
+		for _, p := range job.Configuration.Query.QueryParameters {
+			f.params = append(f.params, p.Name+"="+p.ParameterValue.Value)
+		}
+		io.WriteString(w, `{"jobReference": {"jobId": "query1", "location": "EU"}, "status": {"state": "RUNNING"}}`)
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "/projects/proj/jobs/"):
+		id := strings.TrimPrefix(path, "/projects/proj/jobs/")
+		if id == f.failJob {
+			io.WriteString(w, `{"jobReference": {"jobId": "`+id+`"}, "status": {"state": "DONE", "errorResult": {"message": "Error while reading data"}, "errors": [{"message": "Error while reading data"}, {"message": "Invalid BIGNUMERIC value"}]}}`)
+			return
+		}
+		io.WriteString(w, `{"jobReference": {"jobId": "`+id+`"}, "status": {"state": "DONE"}}`)
+	default:
+		http.Error(w, "unexpected "+r.Method+" "+path, http.StatusBadRequest)
+	}
+}
+
+// upload reads a multipart load job
+func (f *fakeAPI) upload(w http.ResponseWriter, r *http.Request) {
+	_, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	reader := multipart.NewReader(r.Body, params["boundary"])
+	part, _ := reader.NextPart()
+	var config struct {
+		Configuration struct {
+			Load struct {
+				DestinationTable struct{ TableID string }
+				WriteDisposition string
+			}
+		}
+	}
+	json.NewDecoder(part).Decode(&config)
+	load := config.Configuration.Load
+	part, _ = reader.NextPart()
+	lines := 0
+	for scanner := bufio.NewScanner(part); scanner.Scan(); lines++ {
+	}
+	f.loads = append(f.loads, load.DestinationTable.TableID+" "+load.WriteDisposition)
+	f.rows[load.DestinationTable.TableID] += lines
+	io.WriteString(w, `{"jobReference": {"jobId": "load`+string(rune('0'+len(f.loads)))+`"}, "status": {"state": "PENDING"}}`)
+}
+
+func TestClient_Replace(t *testing.T) {
+	api, c := newFakeAPI(t)
+	txs := []models.Transaction{{Hash: "0x1", BlockNumber: 1}, {Hash: "0x2", BlockNumber: 2}, {Hash: "0x3", BlockNumber: 3}}
+
+	err := c.Replace(context.Background(), "history", "0xAbC", "ethereum", 0, 100, txs)
+
+	assert.NoError(t, err)
+	assert.True(t, api.dataset)
+	assert.Len(t, api.tables["history"], len(Schema()))
+	assert.Equal(t, []string{"history_staging_ethereum_0xabc WRITE_TRUNCATE", "history_staging_ethereum_0xabc WRITE_APPEND"}, api.loads)
+	assert.Equal(t, 3, api.rows["history_staging_ethereum_0xabc"])
+	if assert.Len(t, api.queries, 1) {
+		assert.Contains(t, api.queries[0], "DELETE FROM `proj.wallets.history` WHERE wallet = @wallet AND IFNULL(chain, 'ethereum') = @chain AND block BETWEEN @start AND @end")
+		assert.Contains(t, api.queries[0], "FROM `proj.wallets.history_staging_ethereum_0xabc`")
+	}
+	assert.ElementsMatch(t, []interface{}{"wallet=0xabc", "chain=ethereum", "start=0", "end=100"}, api.params)
+	assert.Equal(t, []string{"history_staging_ethereum_0xabc"}, api.deleted)
+}
+
+func TestClient_ReplaceChains(t *testing.T) {
+	api, c := newFakeAPI(t)
+	txs := []models.Transaction{{Hash: "0x1", BlockNumber: 1, Chain: "polygon"}, {Hash: "0x2", BlockNumber: 2}}
+
+	err := c.Replace(context.Background(), "history", "0xabc", "polygon", 0, 100, txs)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"history_staging_polygon_0xabc WRITE_TRUNCATE"}, api.loads, "each chain has its own staging table")
+	assert.Contains(t, api.params, "chain=polygon", "only the rows of the chain are replaced")
+}
+
+func TestClient_ReplaceAddsColumns(t *testing.T) {
+	api, c := newFakeAPI(t)
+	api.dataset = true
+	api.tables["history"] = Schema()[:5]
+
+	err := c.Replace(context.Background(), "history", "0xabc", "ethereum", 0, 100, nil)
+
+	assert.NoError(t, err)
+	assert.Len(t, api.tables["history"], len(Schema()))
+	assert.Equal(t, "NULLABLE", api.tables["history"][len(Schema())-1].Mode)
+	assert.Empty(t, api.loads, "no rows, so only the range is cleared")
+	if assert.Len(t, api.queries, 1) {
+		assert.NotContains(t, api.queries[0], "INSERT")
+	}
+}
+
+func TestClient_ReplaceErrors(t *testing.T) {
+	api, c := newFakeAPI(t)
+	api.failJob = "load1"
+	err := c.Replace(context.Background(), "history", "0xabc", "ethereum", 0, 100, []models.Transaction{{Hash: "0x1"}})
+	assert.ErrorContains(t, err, "failed to load rows 1 to 1: job load1 failed: Error while reading data; Invalid BIGNUMERIC value")
+	assert.Empty(t, api.queries)
+	assert.Equal(t, []string{"history_staging_ethereum_0xabc"}, api.deleted, "the staging table is removed")
+
+	c.Token = StaticToken("wrong")
+	err = c.Replace(context.Background(), "history", "0xabc", "ethereum", 0, 100, nil)
+	assert.ErrorContains(t, err, "BigQuery returned 401: unauthenticated")
+
+	err = c.Replace(context.Background(), "history", "0xabc", "ethereum", 0, 100, []models.Transaction{{Hash: "0x1", Chain: "polygon"}})
+	assert.ErrorContains(t, err, "row 0x1 is on polygon, not ethereum")
+
+	err = c.Replace(context.Background(), "history-2024", "0xabc", "ethereum", 0, 100, nil)
+	assert.ErrorContains(t, err, "invalid BigQuery name")
+}
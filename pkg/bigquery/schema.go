@@ -0,0 +1,103 @@
+package bigquery
+
+import (
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+)
+
+// Field is a column of a table schema
+type Field struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Mode        string `json:"mode,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Columns added to every row, besides the export columns
+const (
+	// WalletField is the address the rows were fetched for
+	WalletField = "wallet"
+	// LoadedAtField is the time the rows were loaded
+	LoadedAtField = "loaded_at"
+)
+
+// ChainField is the export column holding the network of a row, which
+// tells apart the rows of a wallet on each chain
+const ChainField = "chain"
+
+// int64Columns are the integer columns that fit INT64. Other integer
+// columns, such as token IDs and wei amounts, can hold 256-bit values and
+// are loaded as STRING.
+var int64Columns = map[string]bool{
+	"block":     true,
+	"nonce":     true,
+	"gas_limit": true,
+	"tx_index":  true,
+	"unix_time": true,
+}
+
+// Schema returns the table schema: the wallet and load time, then every
+// column of the export schema under its key. Decimal amounts are
+// BIGNUMERIC, so they can be summed exactly.
+func Schema() []Field {
+	fields := []Field{
+		{Name: WalletField, Type: "STRING", Mode: "REQUIRED", Description: "Address the rows were fetched for"},
+		{Name: LoadedAtField, Type: "TIMESTAMP", Mode: "REQUIRED", Description: "Time the rows were loaded"},
+	}
+	for _, col := range models.Schema() {
+		fields = append(fields, Field{Name: col.Key, Type: fieldType(col), Mode: "NULLABLE", Description: col.Header})
+	}
+	return fields
+}
+
+func fieldType(col models.Column) string {
+	switch {
+	case col.Type == models.ColumnTimestamp:
+		return "TIMESTAMP"
+	case col.Type == models.ColumnDecimal:
+		return "BIGNUMERIC"
+	case col.Type == models.ColumnInteger && int64Columns[col.Key]:
+		return "INT64"
+	}
+	return "STRING"
+}
+
+// maxBigNumericDigits bounds the integer and fractional digits of a
+// BIGNUMERIC value
+const maxBigNumericDigits = 38
+
+// Row returns tx as a JSON row of the schema. Empty values are null, and
+// so are amounts too large for BIGNUMERIC, such as the supply of a spam
+// token; the raw_value column keeps those exactly.
+func Row(wallet string, tx *models.Transaction, loadedAt time.Time) map[string]interface{} {
+	row := map[string]interface{}{
+		WalletField:   strings.ToLower(wallet),
+		LoadedAtField: loadedAt.UTC().Format(time.RFC3339Nano),
+	}
+	for _, col := range models.Schema() {
+		if col.Type == models.ColumnTimestamp {
+			if !tx.Timestamp.IsZero() {
+				row[col.Key] = tx.Timestamp.UTC().Format(time.RFC3339)
+			}
+			continue
+		}
+		value := col.Value(tx)
+		if value == "" || (col.Type == models.ColumnDecimal && !fitsBigNumeric(value)) {
+			continue
+		}
+		row[col.Key] = value
+	}
+	return row
+}
+
+// fitsBigNumeric reports whether value is a decimal BIGNUMERIC can hold
+func fitsBigNumeric(value string) bool {
+	if _, ok := new(big.Rat).SetString(value); !ok {
+		return false
+	}
+	whole, fraction, _ := strings.Cut(strings.TrimLeft(value, "+-"), ".")
+	return len(strings.TrimLeft(whole, "0")) <= maxBigNumericDigits && len(fraction) <= maxBigNumericDigits
+}
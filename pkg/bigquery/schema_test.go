@@ -0,0 +1,56 @@
+package bigquery
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchema(t *testing.T) {
+	types := make(map[string]string)
+	for _, field := range Schema() {
+		assert.NoError(t, ValidateName(field.Name))
+		types[field.Name] = field.Type
+	}
+
+	assert.Equal(t, "STRING", types[WalletField])
+	assert.Equal(t, "TIMESTAMP", types["timestamp"])
+	assert.Equal(t, "INT64", types["block"])
+	assert.Equal(t, "BIGNUMERIC", types["value"])
+	assert.Equal(t, "STRING", types["token_id"], "token IDs can exceed INT64")
+	assert.Equal(t, "STRING", types["hash"])
+	assert.Len(t, types, len(models.Schema())+2)
+}
+
+func TestRow(t *testing.T) {
+	loadedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tx := models.Transaction{
+		Hash:        "0xabc",
+		BlockNumber: 19000000,
+		Timestamp:   time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("CET", 3600)),
+		Type:        models.TypeEthTransfer,
+		Value:       "1.5",
+		GasFee:      strings.Repeat("9", 39) + ".0",
+	}
+
+	row := Row("0xWALLET", &tx, loadedAt)
+
+	assert.Equal(t, "0xwallet", row[WalletField])
+	assert.Equal(t, "2024-05-01T12:00:00Z", row[LoadedAtField])
+	assert.Equal(t, "2024-01-02T02:04:05Z", row["timestamp"])
+	assert.Equal(t, "19000000", row["block"])
+	assert.Equal(t, "1.5", row["value"])
+	assert.NotContains(t, row, "gas_fee", "too large for BIGNUMERIC")
+	assert.NotContains(t, row, "notes", "empty values are null")
+}
+
+func TestFitsBigNumeric(t *testing.T) {
+	assert.True(t, fitsBigNumeric("0.000000000000000001"))
+	assert.True(t, fitsBigNumeric("-"+strings.Repeat("9", 38)))
+	assert.False(t, fitsBigNumeric(strings.Repeat("9", 39)))
+	assert.False(t, fitsBigNumeric("1."+strings.Repeat("1", 39)))
+	assert.False(t, fitsBigNumeric("abc"))
+}
@@ -0,0 +1,186 @@
+// Package cache provides a namespaced in-memory cache shared by the
+// enrichment layers (prices, ENS names, token metadata, address labels).
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Well-known namespaces used by the enrichment layers
+const (
+	NamespacePrices        = "prices"
+	NamespaceENS           = "ens"
+	NamespaceTokenMetadata = "token_metadata"
+	NamespaceLabels        = "labels"
+)
+
+// NamespaceConfig controls expiry and size of a single namespace.
+// A zero TTL means entries never expire; a zero MaxEntries means unbounded.
+type NamespaceConfig struct {
+	TTL        time.Duration
+	MaxEntries int
+}
+
+// DefaultConfigs are applied by New for the well-known namespaces
+var DefaultConfigs = map[string]NamespaceConfig{
+	NamespacePrices:        {TTL: 5 * time.Minute, MaxEntries: 10000},
+	NamespaceENS:           {TTL: time.Hour, MaxEntries: 10000},
+	NamespaceTokenMetadata: {TTL: 24 * time.Hour, MaxEntries: 50000},
+	NamespaceLabels:        {TTL: 24 * time.Hour, MaxEntries: 50000},
+}
+
+// Stats holds usage counters for a namespace
+type Stats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	Expired   uint64
+	Entries   int
+}
+
+type entry struct {
+	key       string
+	value     interface{}
+	expiresAt time.Time
+}
+
+type namespace struct {
+	config  NamespaceConfig
+	entries map[string]*list.Element
+	order   *list.List // front is most recently used
+	stats   Stats
+}
+
+// Cache is a set of independently configured namespaces. It is safe for
+// concurrent use.
+type Cache struct {
+	mu         sync.Mutex
+	namespaces map[string]*namespace
+	now        func() time.Time
+}
+
+// New creates a cache with DefaultConfigs applied
+func New() *Cache {
+	c := &Cache{
+		namespaces: make(map[string]*namespace),
+		now:        time.Now,
+	}
+	for name, cfg := range DefaultConfigs {
+		c.Configure(name, cfg)
+	}
+	return c
+}
+
+// Configure sets the TTL and size bound of a namespace, creating it if
+// needed. Shrinking MaxEntries evicts least recently used entries.
+func (c *Cache) Configure(name string, cfg NamespaceConfig) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ns := c.namespace(name)
+	ns.config = cfg
+	c.evict(ns)
+}
+
+// Get returns the value stored under key in the namespace, if present and
+// not expired
+func (c *Cache) Get(name, key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ns := c.namespace(name)
+	elem, ok := ns.entries[key]
+	if !ok {
+		ns.stats.Misses++
+		return nil, false
+	}
+
+	e := elem.Value.(*entry)
+	if !e.expiresAt.IsZero() && !c.now().Before(e.expiresAt) {
+		ns.order.Remove(elem)
+		delete(ns.entries, key)
+		ns.stats.Expired++
+		ns.stats.Misses++
+		return nil, false
+	}
+
+	ns.order.MoveToFront(elem)
+	ns.stats.Hits++
+	return e.value, true
+}
+
+// Set stores value under key in the namespace using the namespace TTL
+func (c *Cache) Set(name, key string, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ns := c.namespace(name)
+	var expiresAt time.Time
+	if ns.config.TTL > 0 {
+		expiresAt = c.now().Add(ns.config.TTL)
+	}
+
+	if elem, ok := ns.entries[key]; ok {
+		e := elem.Value.(*entry)
+		e.value = value
+		e.expiresAt = expiresAt
+		ns.order.MoveToFront(elem)
+		return
+	}
+
+	ns.entries[key] = ns.order.PushFront(&entry{key: key, value: value, expiresAt: expiresAt})
+	c.evict(ns)
+}
+
+// Delete removes key from the namespace
+func (c *Cache) Delete(name, key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ns := c.namespace(name)
+	if elem, ok := ns.entries[key]; ok {
+		ns.order.Remove(elem)
+		delete(ns.entries, key)
+	}
+}
+
+// Stats returns the usage counters of a namespace
+func (c *Cache) Stats(name string) Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ns := c.namespace(name)
+	stats := ns.stats
+	stats.Entries = len(ns.entries)
+	return stats
+}
+
+// namespace returns the named namespace, creating an unbounded one if it
+// does not exist yet. The caller must hold c.mu.
+func (c *Cache) namespace(name string) *namespace {
+	ns, ok := c.namespaces[name]
+	if !ok {
+		ns = &namespace{
+			entries: make(map[string]*list.Element),
+			order:   list.New(),
+		}
+		c.namespaces[name] = ns
+	}
+	return ns
+}
+
+// evict drops least recently used entries until the namespace fits its
+// size bound. The caller must hold c.mu.
+func (c *Cache) evict(ns *namespace) {
+	if ns.config.MaxEntries <= 0 {
+		return
+	}
+	for len(ns.entries) > ns.config.MaxEntries {
+		oldest := ns.order.Back()
+		ns.order.Remove(oldest)
+		delete(ns.entries, oldest.Value.(*entry).key)
+		ns.stats.Evictions++
+	}
+}
@@ -0,0 +1,86 @@
+package cache
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_GetSet(t *testing.T) {
+	c := New()
+
+	_, ok := c.Get(NamespacePrices, "ETH")
+	assert.False(t, ok)
+
+	c.Set(NamespacePrices, "ETH", "3000.00")
+	value, ok := c.Get(NamespacePrices, "ETH")
+	assert.True(t, ok)
+	assert.Equal(t, "3000.00", value)
+
+	// Namespaces are independent
+	_, ok = c.Get(NamespaceENS, "ETH")
+	assert.False(t, ok)
+
+	stats := c.Stats(NamespacePrices)
+	assert.Equal(t, uint64(1), stats.Hits)
+	assert.Equal(t, uint64(1), stats.Misses)
+	assert.Equal(t, 1, stats.Entries)
+}
+
+func TestCache_TTL(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := New()
+	c.now = func() time.Time { return now }
+	c.Configure("short", NamespaceConfig{TTL: time.Minute})
+
+	c.Set("short", "key", 1)
+	now = now.Add(30 * time.Second)
+	_, ok := c.Get("short", "key")
+	assert.True(t, ok)
+
+	now = now.Add(time.Minute)
+	_, ok = c.Get("short", "key")
+	assert.False(t, ok)
+	assert.Equal(t, uint64(1), c.Stats("short").Expired)
+	assert.Equal(t, 0, c.Stats("short").Entries)
+}
+
+func TestCache_SizeBound(t *testing.T) {
+	c := New()
+	c.Configure("small", NamespaceConfig{MaxEntries: 2})
+
+	c.Set("small", "a", 1)
+	c.Set("small", "b", 2)
+	c.Get("small", "a") // a becomes most recently used
+	c.Set("small", "c", 3)
+
+	_, ok := c.Get("small", "b")
+	assert.False(t, ok, "least recently used entry should be evicted")
+	_, ok = c.Get("small", "a")
+	assert.True(t, ok)
+	_, ok = c.Get("small", "c")
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1), c.Stats("small").Evictions)
+}
+
+func TestCache_ConcurrentAccess(t *testing.T) {
+	c := New()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				key := fmt.Sprintf("%d-%d", i, j)
+				c.Set(NamespaceLabels, key, j)
+				c.Get(NamespaceLabels, key)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, 800, c.Stats(NamespaceLabels).Entries)
+}
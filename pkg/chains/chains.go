@@ -0,0 +1,149 @@
+// Package chains is the registry of supported networks and the limits of
+// their explorer APIs.
+package chains
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Chain describes a network and its Etherscan-compatible explorer API
+type Chain struct {
+	Name        string
+	ID          int64
+	ExplorerAPI string
+	// Explorer is the base URL of the explorer website
+	Explorer string
+	// NativeSymbol is the symbol of the network's native currency
+	NativeSymbol string
+	// OPStack marks rollups that charge an L1 data fee on top of gas
+	OPStack bool
+
+	// MaxBlockRange is the largest block span requested in one paginated
+	// query, per API action. Explorers stop paginating after 10,000 results
+	// per query, so busy chains and dense actions need smaller windows.
+	// DefaultMaxBlockRange applies to actions not listed; 0 disables chunking.
+	MaxBlockRange        map[string]int64
+	DefaultMaxBlockRange int64
+}
+
+// BlockRange returns the recommended block window for action
+func (c Chain) BlockRange(action string) int64 {
+	if window, ok := c.MaxBlockRange[action]; ok {
+		return window
+	}
+	return c.DefaultMaxBlockRange
+}
+
+// TxURL returns the explorer page of the transaction hash
+func (c Chain) TxURL(hash string) string {
+	return c.Explorer + "/tx/" + hash
+}
+
+// BlockURL returns the explorer page of a block
+func (c Chain) BlockURL(number int64) string {
+	return fmt.Sprintf("%s/block/%d", c.Explorer, number)
+}
+
+// Ethereum is the default chain
+var Ethereum = Chain{
+	Name:                 "ethereum",
+	ID:                   1,
+	ExplorerAPI:          "https://api.etherscan.io/api",
+	Explorer:             "https://etherscan.io",
+	NativeSymbol:         "ETH",
+	DefaultMaxBlockRange: 5_000_000,
+	MaxBlockRange:        map[string]int64{"getLogs": 2_000_000},
+}
+
+// Registry holds every known chain by name. Windows scale with block time:
+// a chain producing blocks ten times faster gets a window ten times larger.
+var Registry = map[string]Chain{
+	"ethereum": Ethereum,
+	"sepolia": {
+		Name:                 "sepolia",
+		ID:                   11155111,
+		ExplorerAPI:          "https://api-sepolia.etherscan.io/api",
+		Explorer:             "https://sepolia.etherscan.io",
+		NativeSymbol:         "ETH",
+		DefaultMaxBlockRange: 5_000_000,
+		MaxBlockRange:        map[string]int64{"getLogs": 2_000_000},
+	},
+	"polygon": {
+		Name:                 "polygon",
+		ID:                   137,
+		ExplorerAPI:          "https://api.polygonscan.com/api",
+		Explorer:             "https://polygonscan.com",
+		NativeSymbol:         "POL",
+		DefaultMaxBlockRange: 20_000_000,
+		MaxBlockRange:        map[string]int64{"getLogs": 5_000_000},
+	},
+	"arbitrum": {
+		Name:                 "arbitrum",
+		ID:                   42161,
+		ExplorerAPI:          "https://api.arbiscan.io/api",
+		Explorer:             "https://arbiscan.io",
+		NativeSymbol:         "ETH",
+		DefaultMaxBlockRange: 100_000_000,
+		MaxBlockRange:        map[string]int64{"getLogs": 20_000_000},
+	},
+	"optimism": {
+		Name:                 "optimism",
+		ID:                   10,
+		ExplorerAPI:          "https://api-optimistic.etherscan.io/api",
+		Explorer:             "https://optimistic.etherscan.io",
+		NativeSymbol:         "ETH",
+		OPStack:              true,
+		DefaultMaxBlockRange: 25_000_000,
+		MaxBlockRange:        map[string]int64{"getLogs": 5_000_000},
+	},
+	"base": {
+		Name:                 "base",
+		ID:                   8453,
+		ExplorerAPI:          "https://api.basescan.org/api",
+		Explorer:             "https://basescan.org",
+		NativeSymbol:         "ETH",
+		OPStack:              true,
+		DefaultMaxBlockRange: 25_000_000,
+		MaxBlockRange:        map[string]int64{"getLogs": 5_000_000},
+	},
+	"bsc": {
+		Name:                 "bsc",
+		ID:                   56,
+		ExplorerAPI:          "https://api.bscscan.com/api",
+		Explorer:             "https://bscscan.com",
+		NativeSymbol:         "BNB",
+		DefaultMaxBlockRange: 20_000_000,
+		MaxBlockRange:        map[string]int64{"getLogs": 5_000_000},
+	},
+}
+
+// Lookup finds a chain by name, case-insensitively
+func Lookup(name string) (Chain, error) {
+	chain, ok := Registry[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return Chain{}, fmt.Errorf("unknown chain %q (known: %s)", name, strings.Join(Names(), ", "))
+	}
+	return chain, nil
+}
+
+// LookupID finds a chain by its chain ID
+func LookupID(id int64) (Chain, bool) {
+	for _, chain := range Registry {
+		if chain.ID == id {
+			return chain, true
+		}
+	}
+	return Chain{}, false
+}
+
+// Names returns the registered chain names in sorted order
+func Names() []string {
+	names := make([]string, 0, len(Registry))
+	for name := range Registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,54 @@
+package chains
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChain_BlockRange(t *testing.T) {
+	assert.Equal(t, int64(2_000_000), Ethereum.BlockRange("getLogs"))
+	assert.Equal(t, int64(5_000_000), Ethereum.BlockRange("txlist"))
+	assert.Equal(t, int64(0), Chain{}.BlockRange("txlist"))
+}
+
+func TestLookup(t *testing.T) {
+	chain, err := Lookup(" Polygon ")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(137), chain.ID)
+
+	_, err = Lookup("dogechain")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "ethereum")
+}
+
+func TestRegistry_Consistent(t *testing.T) {
+	for name, chain := range Registry {
+		assert.Equal(t, name, chain.Name)
+		assert.NotEmpty(t, chain.ExplorerAPI, name)
+		assert.Positive(t, chain.DefaultMaxBlockRange, name)
+	}
+}
+
+func TestLookupID(t *testing.T) {
+	chain, ok := LookupID(137)
+	assert.True(t, ok)
+	assert.Equal(t, "polygon", chain.Name)
+
+	_, ok = LookupID(999999)
+	assert.False(t, ok)
+}
+
+func TestChain_URLs(t *testing.T) {
+	chain, err := Lookup("optimism")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://optimistic.etherscan.io/tx/0xabc", chain.TxURL("0xabc"))
+	assert.Equal(t, "https://optimistic.etherscan.io/block/7", chain.BlockURL(7))
+	assert.True(t, chain.OPStack)
+	assert.False(t, Ethereum.OPStack)
+
+	for name, chain := range Registry {
+		assert.NotEmpty(t, chain.Explorer, name)
+		assert.NotEmpty(t, chain.NativeSymbol, name)
+	}
+}
@@ -0,0 +1,205 @@
+// Package chart renders bar charts as SVG or PNG images, without external
+// dependencies, so reports can include them.
+package chart
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"strconv"
+)
+
+// Series is one set of bars, with a value per category
+type Series struct {
+	Name   string
+	Color  color.RGBA
+	Values []float64
+}
+
+// Chart is a bar chart with one group of bars per category
+type Chart struct {
+	Title string
+	// Unit is printed beside the title, such as ETH
+	Unit string
+	// Labels name the categories, such as months
+	Labels []string
+	Series []Series
+	// Width and Height default to DefaultWidth and DefaultHeight
+	Width, Height int
+}
+
+// Default image size in pixels
+const (
+	DefaultWidth  = 960
+	DefaultHeight = 320
+)
+
+// Colors used by the charts of a report
+var (
+	Blue  = color.RGBA{0x09, 0x69, 0xda, 0xff}
+	Green = color.RGBA{0x1a, 0x7f, 0x37, 0xff}
+	Red   = color.RGBA{0xcf, 0x22, 0x2e, 0xff}
+	Gray  = color.RGBA{0x59, 0x63, 0x6e, 0xff}
+	Grid  = color.RGBA{0xd1, 0xd9, 0xe0, 0xff}
+	White = color.RGBA{0xff, 0xff, 0xff, 0xff}
+)
+
+// Format is an image format
+type Format string
+
+const (
+	FormatSVG Format = "svg"
+	FormatPNG Format = "png"
+)
+
+// ParseFormat parses an image format name
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(s); f {
+	case FormatSVG, FormatPNG:
+		return f, nil
+	}
+	return "", fmt.Errorf("unknown format %q (use svg or png)", s)
+}
+
+// Write renders the chart in format
+func (c Chart) Write(w io.Writer, format Format) error {
+	if format == FormatPNG {
+		return c.PNG(w)
+	}
+	return c.SVG(w)
+}
+
+// Margins around the plot area in pixels
+const (
+	marginLeft   = 72
+	marginRight  = 16
+	marginTop    = 40
+	marginBottom = 32
+	gridLines    = 4
+)
+
+// layout is a chart resolved into shapes both renderers draw
+type layout struct {
+	Width, Height int
+	Lines         []line
+	Rects         []rect
+	Texts         []text
+}
+
+type line struct {
+	X1, Y1, X2, Y2 int
+	Color          color.RGBA
+}
+
+type rect struct {
+	X, Y, W, H int
+	Color      color.RGBA
+	// Title is shown as a tooltip in SVG
+	Title string
+}
+
+// Text anchors
+const (
+	anchorStart  = "start"
+	anchorMiddle = "middle"
+	anchorEnd    = "end"
+)
+
+type text struct {
+	// X and Y locate the baseline
+	X, Y   int
+	S      string
+	Anchor string
+	// Size is 1 for labels and 2 for the title
+	Size  int
+	Color color.RGBA
+}
+
+// layout places the title, legend, grid, bars and labels
+func (c Chart) layout() layout {
+	l := layout{Width: c.Width, Height: c.Height}
+	if l.Width <= 0 {
+		l.Width = DefaultWidth
+	}
+	if l.Height <= 0 {
+		l.Height = DefaultHeight
+	}
+	left, right := marginLeft, l.Width-marginRight
+	top, bottom := marginTop, l.Height-marginBottom
+
+	title := c.Title
+	if c.Unit != "" {
+		title += " (" + c.Unit + ")"
+	}
+	l.Texts = append(l.Texts, text{X: left, Y: 24, S: title, Anchor: anchorStart, Size: 2, Color: Gray})
+	// The legend runs right to left from the top right corner
+	x := right
+	for i := len(c.Series) - 1; i >= 0 && len(c.Series) > 1; i-- {
+		s := c.Series[i]
+		l.Texts = append(l.Texts, text{X: x, Y: 24, S: s.Name, Anchor: anchorEnd, Size: 1, Color: Gray})
+		x -= textWidth(s.Name, 1) + 14
+		l.Rects = append(l.Rects, rect{X: x, Y: 16, W: 10, H: 10, Color: s.Color})
+		x -= 16
+	}
+
+	peak := 0.0
+	for _, s := range c.Series {
+		for _, v := range s.Values {
+			peak = math.Max(peak, v)
+		}
+	}
+	step, decimals := niceStep(peak / gridLines)
+	for i := 0; i <= gridLines; i++ {
+		y := bottom - i*(bottom-top)/gridLines
+		l.Lines = append(l.Lines, line{X1: left, Y1: y, X2: right, Y2: y, Color: Grid})
+		label := strconv.FormatFloat(float64(i)*step, 'f', decimals, 64)
+		l.Texts = append(l.Texts, text{X: left - 6, Y: y + 4, S: label, Anchor: anchorEnd, Size: 1, Color: Gray})
+	}
+
+	if len(c.Labels) == 0 || len(c.Series) == 0 {
+		return l
+	}
+	scale := float64(bottom-top) / (step * gridLines)
+	slot := float64(right-left) / float64(len(c.Labels))
+	barWidth := max(1, int(slot*0.8)/len(c.Series))
+	// Label about twelve categories across the axis
+	every := (len(c.Labels) + 11) / 12
+	for i, label := range c.Labels {
+		groupX := left + int(float64(i)*slot+(slot-float64(barWidth*len(c.Series)))/2)
+		for j, s := range c.Series {
+			if i >= len(s.Values) || s.Values[i] <= 0 {
+				continue
+			}
+			h := max(1, int(math.Round(s.Values[i]*scale)))
+			l.Rects = append(l.Rects, rect{
+				X: groupX + j*barWidth, Y: bottom - h, W: barWidth, H: h, Color: s.Color,
+				Title: fmt.Sprintf("%s: %s %s", label, strconv.FormatFloat(s.Values[i], 'f', -1, 64), s.Name),
+			})
+		}
+		if i%every == 0 {
+			l.Texts = append(l.Texts, text{X: left + int(float64(i)*slot+slot/2), Y: bottom + 16, S: label, Anchor: anchorMiddle, Size: 1, Color: Gray})
+		}
+	}
+	return l
+}
+
+// niceStep rounds step up to 1, 2 or 5 times a power of ten, returning it
+// with the number of decimals needed to print its multiples
+func niceStep(step float64) (float64, int) {
+	if step <= 0 || math.IsNaN(step) || math.IsInf(step, 0) {
+		return 1, 0
+	}
+	exponent := int(math.Floor(math.Log10(step)))
+	magnitude := math.Pow(10, float64(exponent))
+	for _, m := range []float64{1, 2, 5, 10} {
+		if m*magnitude >= step*(1-1e-9) {
+			decimals := max(0, -exponent)
+			if m == 10 {
+				decimals = max(0, -exponent-1)
+			}
+			return m * magnitude, decimals
+		}
+	}
+	return 10 * magnitude, 0
+}
@@ -0,0 +1,87 @@
+package chart
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testChart() Chart {
+	return Chart{
+		Title:  "Flows",
+		Unit:   "ETH",
+		Labels: []string{"Jan 2024", "Feb 2024"},
+		Series: []Series{
+			{Name: "received", Color: Green, Values: []float64{4, 0}},
+			{Name: "sent", Color: Red, Values: []float64{1, 2.5}},
+		},
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	f, err := ParseFormat("png")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatPNG, f)
+
+	_, err = ParseFormat("gif")
+	assert.Error(t, err)
+}
+
+func TestNiceStep(t *testing.T) {
+	tests := []struct {
+		in       float64
+		step     float64
+		decimals int
+	}{
+		{1, 1, 0},
+		{0.9, 1, 0},
+		{1.2, 2, 0},
+		{3, 5, 0},
+		{7, 10, 0},
+		{0.012, 0.02, 2},
+		{250, 500, 0},
+		{0, 1, 0},
+	}
+	for _, tt := range tests {
+		step, decimals := niceStep(tt.in)
+		assert.InDelta(t, tt.step, step, 1e-12, "%v", tt.in)
+		assert.Equal(t, tt.decimals, decimals, "%v", tt.in)
+	}
+}
+
+func TestChart_layout(t *testing.T) {
+	l := testChart().layout()
+
+	assert.Equal(t, DefaultWidth, l.Width)
+	assert.Equal(t, DefaultHeight, l.Height)
+	assert.Equal(t, text{X: marginLeft, Y: 24, S: "Flows (ETH)", Anchor: anchorStart, Size: 2, Color: Gray}, l.Texts[0])
+	assert.Len(t, l.Lines, gridLines+1)
+
+	var ticks, labels []string
+	for _, tx := range l.Texts[1:] {
+		switch tx.Anchor {
+		case anchorEnd:
+			ticks = append(ticks, tx.S)
+		case anchorMiddle:
+			labels = append(labels, tx.S)
+		}
+	}
+	assert.Equal(t, []string{"sent", "received", "0", "1", "2", "3", "4"}, ticks, "legend, then the grid")
+	assert.Equal(t, []string{"Jan 2024", "Feb 2024"}, labels)
+
+	// Two legend keys and three bars; zero values draw nothing
+	assert.Len(t, l.Rects, 5)
+	bars := l.Rects[2:]
+	plot := DefaultHeight - marginBottom - marginTop
+	assert.Equal(t, plot, bars[0].H, "the largest value reaches the top grid line")
+	assert.Equal(t, marginTop, bars[0].Y)
+	assert.Equal(t, plot/4, bars[1].H)
+	assert.Equal(t, "Feb 2024: 2.5 sent", bars[2].Title)
+	assert.Equal(t, bars[0].X+bars[0].W, bars[1].X, "bars of a category are side by side")
+}
+
+func TestChart_layoutEmpty(t *testing.T) {
+	l := Chart{Title: "Nothing", Series: []Series{{Name: "a"}}}.layout()
+	assert.Len(t, l.Rects, 0)
+	assert.Equal(t, "4", l.Texts[len(l.Texts)-1].S, "an empty chart still has a scale")
+}
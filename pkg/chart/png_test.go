@@ -0,0 +1,41 @@
+package chart
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChart_PNG(t *testing.T) {
+	c := testChart()
+	c.Width, c.Height = 400, 200
+	var buf bytes.Buffer
+	assert.NoError(t, c.Write(&buf, FormatPNG))
+
+	img, err := png.Decode(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 400, 200), img.Bounds())
+
+	l := c.layout()
+	bar := l.Rects[2]
+	r, g, b, _ := img.At(bar.X+bar.W/2, bar.Y+bar.H/2).RGBA()
+	assert.Equal(t, [3]uint32{uint32(Green.R) * 0x101, uint32(Green.G) * 0x101, uint32(Green.B) * 0x101}, [3]uint32{r, g, b})
+	r, g, b, _ = img.At(1, 1).RGBA()
+	assert.Equal(t, [3]uint32{0xffff, 0xffff, 0xffff}, [3]uint32{r, g, b}, "the background is white")
+}
+
+func TestDrawText(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	drawText(img, text{X: 20, Y: 10, S: "i", Anchor: anchorMiddle, Size: 1, Color: Red})
+
+	// The I glyph starts one pixel into its cell, centered on X
+	assert.Equal(t, Red, img.RGBAAt(19, 3))
+	assert.NotEqual(t, Red, img.RGBAAt(18, 3))
+	assert.Equal(t, Red, img.RGBAAt(20, 9))
+	assert.NotEqual(t, Red, img.RGBAAt(20, 10), "text sits on the baseline")
+	assert.Equal(t, 17, textWidth("abc", 1))
+	assert.Equal(t, 0, textWidth("", 2))
+}
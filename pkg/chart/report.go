@@ -0,0 +1,75 @@
+package chart
+
+import (
+	"strings"
+
+	"github.com/haridev22/ct-assignement/pkg/report"
+)
+
+// Transactions charts the number of transactions per month
+func Transactions(months []report.Month) Chart {
+	values := make([]float64, len(months))
+	for i, m := range months {
+		values[i] = float64(m.Transactions)
+	}
+	return Chart{
+		Title:  "Transactions per month",
+		Labels: monthLabels(months),
+		Series: []Series{{Name: "transactions", Color: Blue, Values: values}},
+	}
+}
+
+// Gas charts the gas spent per month
+func Gas(months []report.Month) Chart {
+	values := make([]float64, len(months))
+	for i, m := range months {
+		values[i], _ = m.Gas.Float64()
+	}
+	return Chart{
+		Title:  "Gas spent per month",
+		Unit:   report.NativeAsset,
+		Labels: monthLabels(months),
+		Series: []Series{{Name: report.NativeAsset, Color: Gray, Values: values}},
+	}
+}
+
+// Flows charts the amounts of an asset received and sent per month. The
+// contract is empty for the native coin.
+func Flows(months []report.Month, symbol, contract string) Chart {
+	in := make([]float64, len(months))
+	out := make([]float64, len(months))
+	for i, m := range months {
+		if contract == "" {
+			in[i], _ = m.Incoming.Float64()
+			out[i], _ = m.Outgoing.Float64()
+			continue
+		}
+		for _, asset := range m.Assets {
+			if asset.Contract != strings.ToLower(contract) {
+				continue
+			}
+			incoming, _ := asset.Incoming.Float64()
+			outgoing, _ := asset.Outgoing.Float64()
+			in[i] += incoming
+			out[i] += outgoing
+		}
+	}
+	return Chart{
+		Title:  symbol + " received and sent per month",
+		Unit:   symbol,
+		Labels: monthLabels(months),
+		Series: []Series{
+			{Name: "received", Color: Green, Values: in},
+			{Name: "sent", Color: Red, Values: out},
+		},
+	}
+}
+
+// monthLabels names months like Jan 2024
+func monthLabels(months []report.Month) []string {
+	labels := make([]string, len(months))
+	for i, m := range months {
+		labels[i] = m.Start.Format("Jan 2006")
+	}
+	return labels
+}
@@ -0,0 +1,44 @@
+package chart
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/report"
+	"github.com/stretchr/testify/assert"
+)
+
+func testMonths() []report.Month {
+	jan := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	return []report.Month{
+		{
+			Start: jan, Transactions: 3, Incoming: big.NewRat(2, 1), Outgoing: big.NewRat(1, 2), Gas: big.NewRat(1, 1000),
+			Assets: []report.AssetTotal{{Symbol: "USDC", Contract: "0xusdc", Incoming: big.NewRat(1500, 1), Outgoing: new(big.Rat)}},
+		},
+		{Start: jan.AddDate(0, 1, 0), Incoming: new(big.Rat), Outgoing: new(big.Rat), Gas: new(big.Rat)},
+	}
+}
+
+func TestTransactions(t *testing.T) {
+	c := Transactions(testMonths())
+	assert.Equal(t, []string{"Jan 2024", "Feb 2024"}, c.Labels)
+	assert.Equal(t, []float64{3, 0}, c.Series[0].Values)
+}
+
+func TestGas(t *testing.T) {
+	c := Gas(testMonths())
+	assert.Equal(t, "ETH", c.Unit)
+	assert.Equal(t, []float64{0.001, 0}, c.Series[0].Values)
+}
+
+func TestFlows(t *testing.T) {
+	c := Flows(testMonths(), "ETH", "")
+	assert.Equal(t, []float64{2, 0}, c.Series[0].Values)
+	assert.Equal(t, []float64{0.5, 0}, c.Series[1].Values)
+
+	c = Flows(testMonths(), "USDC", "0xUSDC")
+	assert.Equal(t, "USDC received and sent per month", c.Title)
+	assert.Equal(t, []float64{1500, 0}, c.Series[0].Values)
+	assert.Equal(t, []float64{0, 0}, c.Series[1].Values)
+}
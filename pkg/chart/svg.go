@@ -0,0 +1,48 @@
+package chart
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"image/color"
+	"io"
+)
+
+// SVG renders the chart as an SVG document, which can also be inlined in
+// an HTML page
+func (c Chart) SVG(w io.Writer) error {
+	l := c.layout()
+	b := bufio.NewWriter(w)
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="Helvetica, Arial, sans-serif" role="img" aria-label="%s">`+"\n",
+		l.Width, l.Height, l.Width, l.Height, html.EscapeString(c.Title))
+	fmt.Fprintf(b, `<rect width="%d" height="%d" fill="%s"/>`+"\n", l.Width, l.Height, hexColor(White))
+	for _, ln := range l.Lines {
+		fmt.Fprintf(b, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s"/>`+"\n", ln.X1, ln.Y1, ln.X2, ln.Y2, hexColor(ln.Color))
+	}
+	for _, r := range l.Rects {
+		fmt.Fprintf(b, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s">`, r.X, r.Y, r.W, r.H, hexColor(r.Color))
+		if r.Title != "" {
+			fmt.Fprintf(b, "<title>%s</title>", html.EscapeString(r.Title))
+		}
+		b.WriteString("</rect>\n")
+	}
+	for _, t := range l.Texts {
+		fmt.Fprintf(b, `<text x="%d" y="%d" text-anchor="%s" font-size="%d" fill="%s">%s</text>`+"\n",
+			t.X, t.Y, t.Anchor, svgFontSize(t.Size), hexColor(t.Color), html.EscapeString(t.S))
+	}
+	b.WriteString("</svg>\n")
+	return b.Flush()
+}
+
+// svgFontSize is the font size of text of size, in pixels
+func svgFontSize(size int) int {
+	if size > 1 {
+		return 16
+	}
+	return 11
+}
+
+// hexColor formats c as #rrggbb
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
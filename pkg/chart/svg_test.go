@@ -0,0 +1,35 @@
+package chart
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChart_SVG(t *testing.T) {
+	c := testChart()
+	c.Title = "Flows <&>"
+	var buf bytes.Buffer
+	assert.NoError(t, c.Write(&buf, FormatSVG))
+	svg := buf.String()
+
+	assert.Contains(t, svg, `<svg xmlns="http://www.w3.org/2000/svg" width="960" height="320"`)
+	assert.Contains(t, svg, `aria-label="Flows &lt;&amp;&gt;"`)
+	assert.Contains(t, svg, `fill="#1a7f37"><title>Jan 2024: 4 received</title></rect>`)
+	assert.Contains(t, svg, `font-size="16" fill="#59636e">Flows &lt;&amp;&gt; (ETH)</text>`)
+
+	// The document is well-formed XML
+	decoder := xml.NewDecoder(&buf)
+	for {
+		_, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if !assert.NoError(t, err) {
+			break
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// Package checkpoint records how far an interrupted export got, so a later
+// run can pick up where it stopped.
+package checkpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Checkpoint describes an export that was interrupted
+type Checkpoint struct {
+	Address    string `json:"address"`
+	StartBlock int64  `json:"start_block"`
+	EndBlock   int64  `json:"end_block"`
+	// Completed lists the inclusive block ranges that were fully fetched
+	Completed [][2]int64 `json:"completed"`
+	// PartialFile holds the rows of the completed ranges
+	PartialFile   string    `json:"partial_file"`
+	InterruptedAt time.Time `json:"interrupted_at"`
+}
+
+// Path returns where the checkpoint of address is kept in outputDir
+func Path(outputDir, address string) string {
+	return filepath.Join(outputDir, fmt.Sprintf("%s_checkpoint.json", address))
+}
+
+// Done reports whether the range startBlock..endBlock was completed
+func (c *Checkpoint) Done(startBlock, endBlock int64) bool {
+	for _, r := range c.Completed {
+		if r[0] <= startBlock && endBlock <= r[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// Covers reports whether block lies in a completed range
+func (c *Checkpoint) Covers(block int64) bool {
+	return c.Done(block, block)
+}
+
+// Save writes the checkpoint to path atomically
+func (c *Checkpoint) Save(path string) error {
+	content, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create checkpoint directory: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, content, 0644); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to replace checkpoint: %w", err)
+	}
+	return nil
+}
+
+// Load reads the checkpoint at path
+func Load(path string) (*Checkpoint, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+	var c Checkpoint
+	if err := json.Unmarshal(content, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint %s: %w", path, err)
+	}
+	return &c, nil
+}
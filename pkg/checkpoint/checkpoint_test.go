@@ -0,0 +1,43 @@
+package checkpoint
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSaveAndLoad(t *testing.T) {
+	path := Path(t.TempDir(), "0xabc")
+	assert.Equal(t, "0xabc_checkpoint.json", filepath.Base(path))
+
+	saved := Checkpoint{
+		Address:       "0xabc",
+		StartBlock:    0,
+		EndBlock:      300,
+		Completed:     [][2]int64{{0, 100}, {100, 200}},
+		PartialFile:   "out/0xabc_tx_history_partial.csv",
+		InterruptedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+	assert.NoError(t, saved.Save(path))
+
+	loaded, err := Load(path)
+	assert.NoError(t, err)
+	assert.Equal(t, saved, *loaded)
+}
+
+func TestLoadMissing(t *testing.T) {
+	_, err := Load(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestDone(t *testing.T) {
+	c := Checkpoint{Completed: [][2]int64{{0, 100}, {200, 300}}}
+	assert.True(t, c.Done(0, 100))
+	assert.True(t, c.Done(250, 300))
+	assert.False(t, c.Done(100, 200))
+	assert.False(t, c.Done(50, 250))
+	assert.True(t, c.Covers(100))
+	assert.False(t, c.Covers(150))
+}
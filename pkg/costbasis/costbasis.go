@@ -0,0 +1,384 @@
+// Package costbasis tracks the lots an address acquires and matches its
+// disposals against them, producing the realized gain or loss of each
+// disposal in a fiat currency.
+package costbasis
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/haridev22/ct-assignement/pkg/report"
+)
+
+// Method selects the lots a disposal is matched against
+type Method string
+
+const (
+	// FIFO disposes of the oldest lots first
+	FIFO Method = "fifo"
+	// LIFO disposes of the newest lots first
+	LIFO Method = "lifo"
+	// HIFO disposes of the lots with the highest unit cost first
+	HIFO Method = "hifo"
+)
+
+// ParseMethod parses a method name
+func ParseMethod(s string) (Method, error) {
+	switch method := Method(strings.ToLower(s)); method {
+	case FIFO, LIFO, HIFO:
+		return method, nil
+	}
+	return "", fmt.Errorf("unknown method %q (use fifo, lifo or hifo)", s)
+}
+
+// Asset identifies a fungible asset
+type Asset struct {
+	Symbol string
+	// Contract is the lowercased token contract, empty for the native coin
+	Contract string
+}
+
+// String returns the symbol, followed by the contract for tokens
+func (a Asset) String() string {
+	if a.Contract == "" {
+		return a.Symbol
+	}
+	return fmt.Sprintf("%s (%s)", a.Symbol, a.Contract)
+}
+
+// Lot is an amount of an asset acquired in one transaction
+type Lot struct {
+	Asset    Asset
+	Hash     string
+	Acquired time.Time
+	Amount   *big.Rat
+	// Remaining is the part of Amount not disposed of yet
+	Remaining *big.Rat
+	// UnitCost is the price of one unit when the lot was acquired
+	UnitCost *big.Rat
+}
+
+// Match is the part of a lot a disposal used up
+type Match struct {
+	Hash     string
+	Acquired time.Time
+	Amount   *big.Rat
+	Cost     *big.Rat
+}
+
+// Disposal is an amount of an asset sent away in one transaction
+type Disposal struct {
+	Asset    Asset
+	Hash     string
+	Disposed time.Time
+	Amount   *big.Rat
+	Proceeds *big.Rat
+	// Cost is the cost basis of the matched lots
+	Cost *big.Rat
+	Lots []Match
+	// Unmatched is the amount no lot was left for, which is given a zero
+	// cost basis. It means acquisitions are missing from the history.
+	Unmatched *big.Rat
+}
+
+// Gain returns the realized gain, negative for a loss
+func (d Disposal) Gain() *big.Rat {
+	return new(big.Rat).Sub(d.Proceeds, d.Cost)
+}
+
+// Result is the outcome of matching the history of an address
+type Result struct {
+	Address string
+	Method  Method
+	// Disposals are in the order they happened
+	Disposals []Disposal
+	// Open are the lots not fully disposed of, in the order they were
+	// acquired
+	Open []Lot
+}
+
+// Compute matches the disposals of address against its acquisitions with
+// method, valuing both with prices. Incoming ETH and ERC-20 transfers are
+// acquisitions at their market value and outgoing ones disposals; failed
+// rows, self-transfers and NFTs are skipped, and gas fees are not treated
+// as disposals. Rows are processed by time, then block.
+func Compute(address string, transactions []models.Transaction, method Method, prices Prices) (*Result, error) {
+	rows := make([]*models.Transaction, 0, len(transactions))
+	seen := make(map[string]bool)
+	for i := range transactions {
+		tx := &transactions[i]
+		if seen[tx.RowID()] {
+			continue
+		}
+		seen[tx.RowID()] = true
+		rows = append(rows, tx)
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		if !rows[i].Timestamp.Equal(rows[j].Timestamp) {
+			return rows[i].Timestamp.Before(rows[j].Timestamp)
+		}
+		return rows[i].BlockNumber < rows[j].BlockNumber
+	})
+
+	result := &Result{Address: address, Method: method}
+	var lots []*Lot
+	for _, tx := range rows {
+		asset, ok := fungibleAsset(tx)
+		if !ok || tx.Failed() {
+			continue
+		}
+		incoming := strings.EqualFold(tx.To, address)
+		outgoing := strings.EqualFold(tx.From, address)
+		amount, ok := new(big.Rat).SetString(tx.Value)
+		if incoming == outgoing || !ok || amount.Sign() <= 0 {
+			continue
+		}
+		price, err := prices.Price(asset, tx.Timestamp)
+		if err != nil {
+			return nil, fmt.Errorf("transaction %s: %w", tx.Hash, err)
+		}
+
+		if incoming {
+			lots = append(lots, &Lot{
+				Asset:     asset,
+				Hash:      tx.Hash,
+				Acquired:  tx.Timestamp,
+				Amount:    amount,
+				Remaining: new(big.Rat).Set(amount),
+				UnitCost:  price,
+			})
+			continue
+		}
+		result.Disposals = append(result.Disposals, dispose(lots, method, asset, tx, amount, price))
+	}
+
+	for _, lot := range lots {
+		if lot.Remaining.Sign() > 0 {
+			result.Open = append(result.Open, *lot)
+		}
+	}
+	return result, nil
+}
+
+// dispose matches amount of asset against the open lots, in the order of
+// method, and reduces what remains of them
+func dispose(lots []*Lot, method Method, asset Asset, tx *models.Transaction, amount, price *big.Rat) Disposal {
+	var open []*Lot
+	for _, lot := range lots {
+		if lot.Asset == asset && lot.Remaining.Sign() > 0 {
+			open = append(open, lot)
+		}
+	}
+	switch method {
+	case LIFO:
+		for i, j := 0, len(open)-1; i < j; i, j = i+1, j-1 {
+			open[i], open[j] = open[j], open[i]
+		}
+	case HIFO:
+		sort.SliceStable(open, func(i, j int) bool { return open[i].UnitCost.Cmp(open[j].UnitCost) > 0 })
+	}
+
+	d := Disposal{
+		Asset:     asset,
+		Hash:      tx.Hash,
+		Disposed:  tx.Timestamp,
+		Amount:    amount,
+		Proceeds:  new(big.Rat).Mul(amount, price),
+		Cost:      new(big.Rat),
+		Unmatched: new(big.Rat).Set(amount),
+	}
+	for _, lot := range open {
+		if d.Unmatched.Sign() == 0 {
+			break
+		}
+		taken := new(big.Rat).Set(lot.Remaining)
+		if taken.Cmp(d.Unmatched) > 0 {
+			taken.Set(d.Unmatched)
+		}
+		cost := new(big.Rat).Mul(taken, lot.UnitCost)
+		lot.Remaining.Sub(lot.Remaining, taken)
+		d.Unmatched.Sub(d.Unmatched, taken)
+		d.Cost.Add(d.Cost, cost)
+		d.Lots = append(d.Lots, Match{Hash: lot.Hash, Acquired: lot.Acquired, Amount: taken, Cost: cost})
+	}
+	return d
+}
+
+// fungibleAsset returns the asset a row moves, and false for NFTs
+func fungibleAsset(tx *models.Transaction) (Asset, bool) {
+	switch tx.Type {
+	case models.TypeERC20Transfer:
+		return Asset{Symbol: tx.AssetSymbol, Contract: strings.ToLower(tx.AssetContractAddr)}, true
+	case models.TypeERC721Transfer, models.TypeERC1155Transfer:
+		return Asset{}, false
+	}
+	return Asset{Symbol: report.NativeAsset}, true
+}
+
+// Totals returns the summed proceeds, cost basis and gain of the disposals
+func (r *Result) Totals() (proceeds, cost, gain *big.Rat) {
+	proceeds, cost = new(big.Rat), new(big.Rat)
+	for _, d := range r.Disposals {
+		proceeds.Add(proceeds, d.Proceeds)
+		cost.Add(cost, d.Cost)
+	}
+	return proceeds, cost, new(big.Rat).Sub(proceeds, cost)
+}
+
+// Unmatched reports whether any disposal had no lots left to match, which
+// means acquisitions are missing from the history
+func (r *Result) Unmatched() bool {
+	for _, d := range r.Disposals {
+		if d.Unmatched.Sign() > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Realized returns the realized gain of each asset over the disposals from
+// start up to end
+func (r *Result) Realized(start, end time.Time) map[Asset]*big.Rat {
+	gains := make(map[Asset]*big.Rat)
+	for _, d := range r.Disposals {
+		if d.Disposed.Before(start) || !d.Disposed.Before(end) {
+			continue
+		}
+		if gains[d.Asset] == nil {
+			gains[d.Asset] = new(big.Rat)
+		}
+		gains[d.Asset].Add(gains[d.Asset], d.Gain())
+	}
+	return gains
+}
+
+// Write prints the disposals and totals in a plain-text layout
+func (r *Result) Write(w io.Writer) error {
+	if _, err := fmt.Fprintf(w, "Realized gains of %s (%s):\n", r.Address, strings.ToUpper(string(r.Method))); err != nil {
+		return err
+	}
+	if len(r.Disposals) == 0 {
+		if _, err := fmt.Fprintln(w, "  No disposals"); err != nil {
+			return err
+		}
+	}
+	for _, d := range r.Disposals {
+		line := fmt.Sprintf("  %s  %-12s %-20s proceeds %-14s cost %-14s gain %-14s %s",
+			d.Disposed.UTC().Format("2006-01-02"), d.Asset, report.FormatAmount(d.Amount),
+			amount(d.Proceeds), amount(d.Cost), amount(d.Gain()), d.Hash)
+		if d.Unmatched.Sign() > 0 {
+			line += fmt.Sprintf(" (%s without a lot)", report.FormatAmount(d.Unmatched))
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	proceeds, cost, gain := r.Totals()
+	if _, err := fmt.Fprintf(w, "Total: proceeds %s, cost %s, gain %s\nOpen lots: %d\n",
+		amount(proceeds), amount(cost), amount(gain), len(r.Open)); err != nil {
+		return err
+	}
+	if r.Unmatched() {
+		_, err := fmt.Fprintln(w, "Warning: disposals without a lot have a zero cost basis; acquisitions are missing from the history")
+		return err
+	}
+	return nil
+}
+
+// amount prints a fiat amount rounded to cents
+func amount(r *big.Rat) string {
+	return r.FloatString(2)
+}
+
+type resultJSON struct {
+	Address   string         `json:"address"`
+	Method    string         `json:"method"`
+	Proceeds  string         `json:"proceeds"`
+	Cost      string         `json:"cost"`
+	Gain      string         `json:"gain"`
+	Disposals []disposalJSON `json:"disposals"`
+	Open      []lotJSON      `json:"open_lots"`
+}
+
+type disposalJSON struct {
+	Symbol    string      `json:"symbol"`
+	Contract  string      `json:"contract,omitempty"`
+	Hash      string      `json:"hash"`
+	Disposed  time.Time   `json:"disposed"`
+	Amount    string      `json:"amount"`
+	Proceeds  string      `json:"proceeds"`
+	Cost      string      `json:"cost"`
+	Gain      string      `json:"gain"`
+	Unmatched string      `json:"unmatched,omitempty"`
+	Lots      []matchJSON `json:"lots"`
+}
+
+type matchJSON struct {
+	Hash     string    `json:"hash"`
+	Acquired time.Time `json:"acquired"`
+	Amount   string    `json:"amount"`
+	Cost     string    `json:"cost"`
+}
+
+type lotJSON struct {
+	Symbol    string    `json:"symbol"`
+	Contract  string    `json:"contract,omitempty"`
+	Hash      string    `json:"hash"`
+	Acquired  time.Time `json:"acquired"`
+	Amount    string    `json:"amount"`
+	Remaining string    `json:"remaining"`
+	UnitCost  string    `json:"unit_cost"`
+}
+
+// MarshalJSON implements json.Marshaler. Amounts are decimal strings, so
+// they keep their precision.
+func (r *Result) MarshalJSON() ([]byte, error) {
+	proceeds, cost, gain := r.Totals()
+	out := resultJSON{
+		Address:   r.Address,
+		Method:    string(r.Method),
+		Proceeds:  report.FormatAmount(proceeds),
+		Cost:      report.FormatAmount(cost),
+		Gain:      report.FormatAmount(gain),
+		Disposals: make([]disposalJSON, len(r.Disposals)),
+		Open:      make([]lotJSON, len(r.Open)),
+	}
+	for i, d := range r.Disposals {
+		dj := disposalJSON{
+			Symbol:   d.Asset.Symbol,
+			Contract: d.Asset.Contract,
+			Hash:     d.Hash,
+			Disposed: d.Disposed.UTC(),
+			Amount:   report.FormatAmount(d.Amount),
+			Proceeds: report.FormatAmount(d.Proceeds),
+			Cost:     report.FormatAmount(d.Cost),
+			Gain:     report.FormatAmount(d.Gain()),
+			Lots:     make([]matchJSON, len(d.Lots)),
+		}
+		if d.Unmatched.Sign() > 0 {
+			dj.Unmatched = report.FormatAmount(d.Unmatched)
+		}
+		for j, m := range d.Lots {
+			dj.Lots[j] = matchJSON{Hash: m.Hash, Acquired: m.Acquired.UTC(), Amount: report.FormatAmount(m.Amount), Cost: report.FormatAmount(m.Cost)}
+		}
+		out.Disposals[i] = dj
+	}
+	for i, lot := range r.Open {
+		out.Open[i] = lotJSON{
+			Symbol:    lot.Asset.Symbol,
+			Contract:  lot.Asset.Contract,
+			Hash:      lot.Hash,
+			Acquired:  lot.Acquired.UTC(),
+			Amount:    report.FormatAmount(lot.Amount),
+			Remaining: report.FormatAmount(lot.Remaining),
+			UnitCost:  report.FormatAmount(lot.UnitCost),
+		}
+	}
+	return json.Marshal(out)
+}
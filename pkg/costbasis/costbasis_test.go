@@ -0,0 +1,153 @@
+package costbasis
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+const wallet = "0xWallet"
+
+var day = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func testPrices(t *testing.T) *PriceTable {
+	table, err := ReadPrices(strings.NewReader("date,asset,price\n" +
+		"2024-01-01,ETH,1000\n2024-01-02,ETH,3000\n2024-01-03,ETH,2000\n2024-01-04,ETH,2500\n" +
+		"2024-01-01,0xUSDC,1\n"))
+	assert.NoError(t, err)
+	return table
+}
+
+// lotRows buys 1 ETH at 1000, 1 at 3000 and 1 at 2000, then sells 1.5 at
+// 2500
+func lotRows() []models.Transaction {
+	return []models.Transaction{
+		{Hash: "0x1", BlockNumber: 1, Timestamp: day, Type: models.TypeEthTransfer, From: "0xexchange", To: "0xwallet", Value: "1"},
+		{Hash: "0x2", BlockNumber: 2, Timestamp: day.AddDate(0, 0, 1), Type: models.TypeEthTransfer, From: "0xexchange", To: "0xwallet", Value: "1"},
+		{Hash: "0x3", BlockNumber: 3, Timestamp: day.AddDate(0, 0, 2), Type: models.TypeInternalTx, From: "0xrouter", To: "0xwallet", Value: "1"},
+		{Hash: "0x4", BlockNumber: 4, Timestamp: day.AddDate(0, 0, 3), Type: models.TypeEthTransfer, From: "0xwallet", To: "0xexchange", Value: "1.5"},
+	}
+}
+
+func TestParseMethod(t *testing.T) {
+	method, err := ParseMethod("HIFO")
+	assert.NoError(t, err)
+	assert.Equal(t, HIFO, method)
+
+	_, err = ParseMethod("average")
+	assert.Error(t, err)
+}
+
+func TestCompute_Methods(t *testing.T) {
+	tests := []struct {
+		method Method
+		cost   string
+		gain   string
+		open   []string
+	}{
+		{FIFO, "2500", "1250", []string{"0x2", "0x3"}},
+		{LIFO, "3500", "250", []string{"0x1", "0x2"}},
+		{HIFO, "4000", "-250", []string{"0x1", "0x3"}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.method), func(t *testing.T) {
+			result, err := Compute(wallet, lotRows(), tt.method, testPrices(t))
+			assert.NoError(t, err)
+			assert.Len(t, result.Disposals, 1)
+			d := result.Disposals[0]
+			assert.Equal(t, "3750", d.Proceeds.RatString())
+			assert.Equal(t, tt.cost, d.Cost.RatString())
+			assert.Equal(t, tt.gain, d.Gain().RatString())
+			assert.Len(t, d.Lots, 2)
+			assert.False(t, result.Unmatched())
+
+			var open []string
+			for _, lot := range result.Open {
+				open = append(open, lot.Hash)
+			}
+			assert.Equal(t, tt.open, open)
+		})
+	}
+}
+
+func TestCompute_PartialLot(t *testing.T) {
+	result, err := Compute(wallet, lotRows(), FIFO, testPrices(t))
+	assert.NoError(t, err)
+	assert.Equal(t, "1/2", result.Disposals[0].Lots[1].Amount.RatString())
+	assert.Equal(t, "1/2", result.Open[0].Remaining.RatString())
+	assert.Equal(t, "1", result.Open[0].Amount.RatString())
+}
+
+func TestCompute_Unmatched(t *testing.T) {
+	rows := []models.Transaction{
+		{Hash: "0x1", Timestamp: day, Type: models.TypeERC20Transfer, From: "0xwallet", To: "0xrouter", AssetSymbol: "USDC", AssetContractAddr: "0xusdc", Value: "100"},
+	}
+	result, err := Compute(wallet, rows, FIFO, testPrices(t))
+	assert.NoError(t, err)
+	assert.True(t, result.Unmatched())
+	assert.Equal(t, "100", result.Disposals[0].Unmatched.RatString())
+	assert.Equal(t, "100", result.Disposals[0].Gain().RatString(), "a disposal without a lot has a zero cost basis")
+}
+
+func TestCompute_Skipped(t *testing.T) {
+	rows := append(lotRows(),
+		models.Transaction{Hash: "0x5", Timestamp: day, Type: models.TypeEthTransfer, From: "0xwallet", To: "0xexchange", Value: "1", Status: models.StatusFailed},
+		models.Transaction{Hash: "0x6", Timestamp: day, Type: models.TypeEthTransfer, From: "0xwallet", To: "0xwallet", Value: "1"},
+		models.Transaction{Hash: "0x7", Timestamp: day, Type: models.TypeERC721Transfer, From: "0xwallet", To: "0xmarket", AssetSymbol: "PUNK", TokenID: "7", Value: "1"},
+		models.Transaction{Hash: "0x8", Timestamp: day, Type: models.TypeContractCall, From: "0xwallet", To: "0xrouter", Value: "0"},
+	)
+	rows = append(rows, rows[0])
+	result, err := Compute(wallet, rows, FIFO, testPrices(t))
+	assert.NoError(t, err)
+	assert.Len(t, result.Disposals, 1, "failed rows, self-transfers, NFTs, zero values and duplicates are skipped")
+}
+
+func TestCompute_MissingPrice(t *testing.T) {
+	rows := []models.Transaction{
+		{Hash: "0x1", Timestamp: day, Type: models.TypeERC20Transfer, From: "0xrouter", To: "0xwallet", AssetSymbol: "DAI", AssetContractAddr: "0xdai", Value: "5"},
+	}
+	_, err := Compute(wallet, rows, FIFO, testPrices(t))
+	assert.EqualError(t, err, "transaction 0x1: no price for DAI (0xdai) on or before 2024-01-01")
+}
+
+func TestResult_Write(t *testing.T) {
+	result, err := Compute(wallet, lotRows(), FIFO, testPrices(t))
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	assert.NoError(t, result.Write(&buf))
+	assert.Contains(t, buf.String(), "Realized gains of 0xWallet (FIFO):\n  2024-01-04  ETH          1.5")
+	assert.Contains(t, buf.String(), "proceeds 3750.00")
+	assert.Contains(t, buf.String(), "Total: proceeds 3750.00, cost 2500.00, gain 1250.00\nOpen lots: 2\n")
+	assert.NotContains(t, buf.String(), "Warning")
+}
+
+func TestResult_MarshalJSON(t *testing.T) {
+	result, err := Compute(wallet, lotRows(), HIFO, testPrices(t))
+	assert.NoError(t, err)
+	data, err := json.Marshal(result)
+	assert.NoError(t, err)
+
+	var got map[string]interface{}
+	assert.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, "hifo", got["method"])
+	assert.Equal(t, "-250", got["gain"])
+	disposal := got["disposals"].([]interface{})[0].(map[string]interface{})
+	assert.NotContains(t, disposal, "unmatched")
+	assert.Equal(t, map[string]interface{}{"hash": "0x2", "acquired": "2024-01-02T12:00:00Z", "amount": "1", "cost": "3000"}, disposal["lots"].([]interface{})[0])
+	assert.Len(t, got["open_lots"], 2)
+}
+
+func TestResult_Realized(t *testing.T) {
+	result, err := Compute(wallet, lotRows(), FIFO, testPrices(t))
+	assert.NoError(t, err)
+
+	gains := result.Realized(day.AddDate(0, 0, 3), day.AddDate(0, 0, 4))
+	assert.Equal(t, "1250", gains[Asset{Symbol: "ETH"}].RatString())
+	assert.Empty(t, result.Realized(day, day.AddDate(0, 0, 3)), "the end is excluded")
+}
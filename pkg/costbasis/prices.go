@@ -0,0 +1,110 @@
+package costbasis
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Prices values assets in a fiat currency
+type Prices interface {
+	// Price returns the price of one unit of asset at time at
+	Price(asset Asset, at time.Time) (*big.Rat, error)
+}
+
+// PriceTable holds daily prices, keyed by lowercased contract address or
+// by symbol
+type PriceTable struct {
+	byAsset map[string][]dailyPrice
+}
+
+type dailyPrice struct {
+	day   time.Time
+	price *big.Rat
+}
+
+// ReadPriceFile reads a price table from a CSV file
+func ReadPriceFile(path string) (*PriceTable, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadPrices(file)
+}
+
+// ReadPrices reads a price table from date,asset,price lines. The date is
+// YYYY-MM-DD in UTC and the asset is a token contract address or a symbol.
+// Blank lines, lines starting with # and a date,asset,price header are
+// skipped.
+func ReadPrices(r io.Reader) (*PriceTable, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 3
+	reader.TrimLeadingSpace = true
+
+	table := &PriceTable{byAsset: make(map[string][]dailyPrice)}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		if line == 1 && strings.EqualFold(record[0], "date") {
+			continue
+		}
+		day, err := time.Parse("2006-01-02", strings.TrimSpace(record[0]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid date %q, expected YYYY-MM-DD", line, record[0])
+		}
+		asset := strings.TrimSpace(record[1])
+		if asset == "" {
+			return nil, fmt.Errorf("line %d: missing asset", line)
+		}
+		price, ok := new(big.Rat).SetString(strings.TrimSpace(record[2]))
+		if !ok || price.Sign() < 0 {
+			return nil, fmt.Errorf("line %d: invalid price %q", line, record[2])
+		}
+		key := priceKey(asset)
+		table.byAsset[key] = append(table.byAsset[key], dailyPrice{day: day, price: price})
+	}
+	for _, prices := range table.byAsset {
+		sort.SliceStable(prices, func(i, j int) bool { return prices[i].day.Before(prices[j].day) })
+	}
+	return table, nil
+}
+
+// Price implements Prices with the last price on or before the day of at.
+// A token is looked up by its contract first, then by its symbol.
+func (t *PriceTable) Price(asset Asset, at time.Time) (*big.Rat, error) {
+	keys := []string{priceKey(asset.Symbol)}
+	if asset.Contract != "" {
+		keys = append([]string{priceKey(asset.Contract)}, keys...)
+	}
+	day := at.UTC().Truncate(24 * time.Hour)
+	for _, key := range keys {
+		prices := t.byAsset[key]
+		// Index of the first price after the day
+		i := sort.Search(len(prices), func(i int) bool { return prices[i].day.After(day) })
+		if i > 0 {
+			return prices[i-1].price, nil
+		}
+	}
+	return nil, fmt.Errorf("no price for %s on or before %s", asset, day.Format("2006-01-02"))
+}
+
+// priceKey normalizes a symbol or contract address
+func priceKey(asset string) string {
+	if strings.HasPrefix(asset, "0x") || strings.HasPrefix(asset, "0X") {
+		return strings.ToLower(asset)
+	}
+	return strings.ToUpper(asset)
+}
@@ -0,0 +1,61 @@
+package costbasis
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriceTable_Price(t *testing.T) {
+	table, err := ReadPrices(strings.NewReader("# daily closes\n2024-01-03,eth,2000\n2024-01-01,ETH,1000\n\n2024-01-01,0xA0b8,1.01\n2024-01-01,USDC,1\n"))
+	assert.NoError(t, err)
+
+	price, err := table.Price(Asset{Symbol: "ETH"}, time.Date(2024, 1, 2, 23, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, "1000", price.RatString(), "the last price on or before the day is used")
+
+	price, err = table.Price(Asset{Symbol: "ETH"}, time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, "2000", price.RatString())
+
+	price, err = table.Price(Asset{Symbol: "USDC", Contract: "0xa0b8"}, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, "101/100", price.RatString(), "a contract price comes before a symbol price")
+
+	price, err = table.Price(Asset{Symbol: "USDC", Contract: "0xother"}, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, "1", price.RatString())
+
+	_, err = table.Price(Asset{Symbol: "ETH"}, time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC))
+	assert.EqualError(t, err, "no price for ETH on or before 2023-12-31")
+}
+
+func TestReadPrices_Invalid(t *testing.T) {
+	for input, want := range map[string]string{
+		"01/02/2024,ETH,1\n":   "line 1: invalid date",
+		"2024-01-01,ETH,abc\n": "line 1: invalid price",
+		"2024-01-01,ETH,-1\n":  "line 1: invalid price",
+		"2024-01-01,,1\n":      "line 1: missing asset",
+		"2024-01-01,ETH\n":     "wrong number of fields",
+	} {
+		_, err := ReadPrices(strings.NewReader(input))
+		if assert.Error(t, err, input) {
+			assert.Contains(t, err.Error(), want)
+		}
+	}
+}
+
+func TestReadPriceFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prices.csv")
+	assert.NoError(t, os.WriteFile(path, []byte("2024-01-01,ETH,1000\n"), 0644))
+	table, err := ReadPriceFile(path)
+	assert.NoError(t, err)
+	assert.Len(t, table.byAsset, 1)
+
+	_, err = ReadPriceFile(filepath.Join(t.TempDir(), "missing.csv"))
+	assert.Error(t, err)
+}
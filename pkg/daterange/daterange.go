@@ -0,0 +1,90 @@
+// Package daterange parses the calendar flags of an export into the time
+// range they select, so it can be resolved to blocks.
+package daterange
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// Range is a time span. A zero Start or End leaves that side open.
+type Range struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Resolve combines -from-date, -to-date and -last into a Range. Dates are
+// YYYY-MM-DD in UTC or RFC 3339 timestamps; a -to-date day is included up
+// to its last second. last is relative to now and cannot be combined with
+// the dates.
+func Resolve(from, to, last string, now time.Time) (Range, error) {
+	var r Range
+	if last != "" {
+		if from != "" || to != "" {
+			return r, fmt.Errorf("-last cannot be combined with -from-date or -to-date")
+		}
+		span, err := ParseLast(last)
+		if err != nil {
+			return r, err
+		}
+		return Range{Start: now.Add(-span), End: now}, nil
+	}
+
+	if from != "" {
+		start, _, err := ParseDate(from)
+		if err != nil {
+			return r, fmt.Errorf("invalid -from-date: %w", err)
+		}
+		r.Start = start
+	}
+	if to != "" {
+		end, dateOnly, err := ParseDate(to)
+		if err != nil {
+			return r, fmt.Errorf("invalid -to-date: %w", err)
+		}
+		if dateOnly {
+			end = end.AddDate(0, 0, 1).Add(-time.Second)
+		}
+		r.End = end
+	}
+	if !r.Start.IsZero() && !r.End.IsZero() && r.End.Before(r.Start) {
+		return r, fmt.Errorf("-to-date %s is before -from-date %s", to, from)
+	}
+	return r, nil
+}
+
+// ParseLast parses a relative span such as "90d", "2w" or "12h"
+func ParseLast(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	units := map[string]time.Duration{
+		"h": time.Hour,
+		"d": 24 * time.Hour,
+		"w": 7 * 24 * time.Hour,
+	}
+	if len(s) >= 2 {
+		if unit, ok := units[s[len(s)-1:]]; ok {
+			n, err := strconv.Atoi(s[:len(s)-1])
+			if err == nil && n > 0 {
+				return time.Duration(n) * unit, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("invalid -last %q, expected a number followed by h, d or w (e.g. 90d)", s)
+}
+
+// ParseDate parses a YYYY-MM-DD date or an RFC 3339 timestamp, reporting
+// whether only a date was given
+func ParseDate(s string) (time.Time, bool, error) {
+	if t, err := time.Parse(dateLayout, s); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("%q is not a YYYY-MM-DD date or RFC 3339 timestamp", s)
+	}
+	return t, false, nil
+}
@@ -0,0 +1,53 @@
+package daterange
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolve(t *testing.T) {
+	now := time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+
+	r, err := Resolve("2024-01-01", "2024-03-31", "", now)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), r.Start)
+	assert.Equal(t, time.Date(2024, 3, 31, 23, 59, 59, 0, time.UTC), r.End)
+
+	r, err = Resolve("", "2024-03-31T10:00:00+02:00", "", now)
+	assert.NoError(t, err)
+	assert.True(t, r.Start.IsZero())
+	assert.Equal(t, time.Date(2024, 3, 31, 8, 0, 0, 0, time.UTC), r.End.UTC())
+
+	r, err = Resolve("", "", "90d", now)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 4, 1, 12, 0, 0, 0, time.UTC), r.Start)
+	assert.Equal(t, now, r.End)
+}
+
+func TestResolve_Errors(t *testing.T) {
+	now := time.Now()
+
+	_, err := Resolve("2024-01-01", "", "30d", now)
+	assert.Error(t, err)
+	_, err = Resolve("01/01/2024", "", "", now)
+	assert.ErrorContains(t, err, "-from-date")
+	_, err = Resolve("2024-02-01", "2024-01-01", "", now)
+	assert.ErrorContains(t, err, "before")
+}
+
+func TestParseLast(t *testing.T) {
+	d, err := ParseLast("2w")
+	assert.NoError(t, err)
+	assert.Equal(t, 14*24*time.Hour, d)
+
+	d, err = ParseLast("12h")
+	assert.NoError(t, err)
+	assert.Equal(t, 12*time.Hour, d)
+
+	for _, bad := range []string{"", "d", "0d", "-5d", "3m", "1.5d"} {
+		_, err := ParseLast(bad)
+		assert.Error(t, err, bad)
+	}
+}
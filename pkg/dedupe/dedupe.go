@@ -0,0 +1,160 @@
+// Package dedupe removes duplicate rows and applies the policy for hashes
+// that legitimately produce several rows (for example a swap that appears
+// both as a normal transaction and as ERC-20 transfers).
+package dedupe
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+)
+
+// Policy decides what happens to multiple rows sharing a transaction hash
+type Policy string
+
+const (
+	// KeepAll keeps every distinct row
+	KeepAll Policy = "keep-all"
+	// PreferTokenRows drops zero-value ETH_TRANSFER rows for hashes that
+	// also produced token rows, since the token rows carry the movement
+	PreferTokenRows Policy = "prefer-token-rows"
+	// CollapseToOne keeps a single row per hash, preferring token rows,
+	// then ETH transfers, then internal transfers
+	CollapseToOne Policy = "collapse-to-one"
+)
+
+// Policies lists the accepted policy names
+var Policies = []Policy{KeepAll, PreferTokenRows, CollapseToOne}
+
+// ParsePolicy validates a policy name
+func ParsePolicy(s string) (Policy, error) {
+	for _, p := range Policies {
+		if string(p) == s {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("unknown duplicate policy %q (expected keep-all, prefer-token-rows or collapse-to-one)", s)
+}
+
+// Apply drops rows delivered twice, as by overlapping block ranges, and
+// then applies policy. A row is only dropped when an earlier row has the
+// same row ID and the same fields, since explorers give transfers no log
+// index and two transfers of different amounts between the same parties in
+// one transaction share a row ID. KeepAll keeps every other row. Row order
+// is preserved.
+func Apply(transactions []models.Transaction, policy Policy) []models.Transaction {
+	kept := make(map[string][]int, len(transactions))
+	unique := make([]models.Transaction, 0, len(transactions))
+	for i := range transactions {
+		id := transactions[i].RowID()
+		if refetched(transactions, kept[id], &transactions[i]) {
+			continue
+		}
+		kept[id] = append(kept[id], i)
+		unique = append(unique, transactions[i])
+	}
+
+	switch policy {
+	case PreferTokenRows:
+		return preferTokenRows(unique)
+	case CollapseToOne:
+		return collapseToOne(unique)
+	default:
+		return unique
+	}
+}
+
+// refetched reports whether tx repeats one of the rows of transactions at
+// indexes in every field
+func refetched(transactions []models.Transaction, indexes []int, tx *models.Transaction) bool {
+	for _, i := range indexes {
+		if sameFields(&transactions[i], tx) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameFields reports whether a and b hold the same values, comparing their
+// timestamps as instants
+func sameFields(a, b *models.Transaction) bool {
+	x, y := *a, *b
+	x.Timestamp, y.Timestamp = time.Time{}, time.Time{}
+	return x == y && a.Timestamp.Equal(b.Timestamp)
+}
+
+func preferTokenRows(transactions []models.Transaction) []models.Transaction {
+	hasTokenRows := make(map[string]bool)
+	for i := range transactions {
+		if isTokenRow(&transactions[i]) {
+			hasTokenRows[hashKey(&transactions[i])] = true
+		}
+	}
+
+	result := make([]models.Transaction, 0, len(transactions))
+	for i := range transactions {
+		tx := &transactions[i]
+		if tx.Type == models.TypeEthTransfer && hasTokenRows[hashKey(tx)] && isZeroAmount(tx.Value) {
+			continue
+		}
+		result = append(result, *tx)
+	}
+	return result
+}
+
+func collapseToOne(transactions []models.Transaction) []models.Transaction {
+	best := make(map[string]int)
+	for i := range transactions {
+		tx := &transactions[i]
+		if tx.Hash == "" {
+			continue
+		}
+		key := hashKey(tx)
+		if prev, ok := best[key]; !ok || rank(tx) < rank(&transactions[prev]) {
+			best[key] = i
+		}
+	}
+
+	result := make([]models.Transaction, 0, len(best))
+	for i := range transactions {
+		tx := &transactions[i]
+		if tx.Hash != "" && best[hashKey(tx)] != i {
+			continue
+		}
+		result = append(result, *tx)
+	}
+	return result
+}
+
+// rank orders row types for CollapseToOne; lower wins
+func rank(tx *models.Transaction) int {
+	switch {
+	case isTokenRow(tx):
+		return 0
+	case tx.Type == models.TypeEthTransfer:
+		return 1
+	case tx.Type == models.TypeInternalTx:
+		return 2
+	default:
+		return 3
+	}
+}
+
+func isTokenRow(tx *models.Transaction) bool {
+	switch tx.Type {
+	case models.TypeERC20Transfer, models.TypeERC721Transfer, models.TypeERC1155Transfer:
+		return true
+	}
+	return false
+}
+
+func hashKey(tx *models.Transaction) string {
+	return strings.ToLower(tx.Hash)
+}
+
+// isZeroAmount reports whether a formatted decimal amount is zero
+func isZeroAmount(value string) bool {
+	return strings.Trim(value, "0.") == ""
+}
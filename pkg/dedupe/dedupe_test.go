@@ -0,0 +1,87 @@
+package dedupe
+
+import (
+	"testing"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func rows() []models.Transaction {
+	return []models.Transaction{
+		// Token swap: zero-value contract call plus two token legs
+		{Hash: "0xswap", Type: models.TypeEthTransfer, From: "0xme", To: "0xrouter", Value: "0.000000000000000000"},
+		{Hash: "0xswap", Type: models.TypeERC20Transfer, From: "0xme", To: "0xpool", AssetContractAddr: "0xusdc", Value: "100"},
+		{Hash: "0xswap", Type: models.TypeERC20Transfer, From: "0xpool", To: "0xme", AssetContractAddr: "0xdai", Value: "99"},
+		// ETH-paid mint: the ETH leg carries value and must survive prefer-token-rows
+		{Hash: "0xmint", Type: models.TypeEthTransfer, From: "0xme", To: "0xnft", Value: "0.1"},
+		{Hash: "0xmint", Type: models.TypeERC721Transfer, From: "0x0", To: "0xme", AssetContractAddr: "0xnft", TokenID: "1", Value: "1"},
+		// Plain transfer delivered twice by overlapping batches
+		{Hash: "0xplain", Type: models.TypeEthTransfer, From: "0xa", To: "0xme", Value: "1"},
+		{Hash: "0xplain", Type: models.TypeEthTransfer, From: "0xa", To: "0xme", Value: "1"},
+		// Hashless rows are never collapsed
+		{Type: models.TypeBlockReward, To: "0xme", BlockNumber: 1, Value: "2"},
+		{Type: models.TypeBlockReward, To: "0xme", BlockNumber: 2, Value: "2"},
+	}
+}
+
+func summary(transactions []models.Transaction) []string {
+	var result []string
+	for _, tx := range transactions {
+		result = append(result, tx.Hash+":"+string(tx.Type))
+	}
+	return result
+}
+
+func TestApply_KeepAll(t *testing.T) {
+	result := Apply(rows(), KeepAll)
+	assert.Len(t, result, 8, "only the exact duplicate is dropped")
+}
+
+func TestApply_SamePartyTransfers(t *testing.T) {
+	// Two USDC transfers of different amounts between the same parties in
+	// one transaction share a row ID but are both real
+	transfers := []models.Transaction{
+		{Hash: "0xbatch", Type: models.TypeERC20Transfer, From: "0xa", To: "0xme", AssetContractAddr: "0xusdc", Value: "10", RawValue: "10000000"},
+		{Hash: "0xbatch", Type: models.TypeERC20Transfer, From: "0xa", To: "0xme", AssetContractAddr: "0xusdc", Value: "25", RawValue: "25000000"},
+	}
+	result := Apply(transfers, KeepAll)
+	if assert.Len(t, result, 2) {
+		assert.Equal(t, "10", result[0].Value)
+		assert.Equal(t, "25", result[1].Value)
+	}
+
+	// A re-fetch of both, as by overlapping batches, is still dropped
+	assert.Len(t, Apply(append(transfers, transfers...), KeepAll), 2)
+}
+
+func TestApply_PreferTokenRows(t *testing.T) {
+	assert.Equal(t, []string{
+		"0xswap:ERC20_TRANSFER",
+		"0xswap:ERC20_TRANSFER",
+		"0xmint:ETH_TRANSFER",
+		"0xmint:ERC721_TRANSFER",
+		"0xplain:ETH_TRANSFER",
+		":BLOCK_REWARD",
+		":BLOCK_REWARD",
+	}, summary(Apply(rows(), PreferTokenRows)))
+}
+
+func TestApply_CollapseToOne(t *testing.T) {
+	assert.Equal(t, []string{
+		"0xswap:ERC20_TRANSFER",
+		"0xmint:ERC721_TRANSFER",
+		"0xplain:ETH_TRANSFER",
+		":BLOCK_REWARD",
+		":BLOCK_REWARD",
+	}, summary(Apply(rows(), CollapseToOne)))
+}
+
+func TestParsePolicy(t *testing.T) {
+	p, err := ParsePolicy("collapse-to-one")
+	assert.NoError(t, err)
+	assert.Equal(t, CollapseToOne, p)
+
+	_, err = ParsePolicy("newest")
+	assert.Error(t, err)
+}
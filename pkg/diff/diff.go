@@ -0,0 +1,186 @@
+// Package diff compares two sets of rows, such as two exports of the same
+// address, matching rows by their row IDs.
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+)
+
+// Field is a column whose value differs between two versions of a row
+type Field struct {
+	// Key is the column key, as -columns takes it
+	Key string `json:"column"`
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+// Change is a row present in both sets with different values
+type Change struct {
+	Old    models.Transaction
+	New    models.Transaction
+	Fields []Field
+}
+
+// Result is the difference between an old and a new set of rows
+type Result struct {
+	// Added rows are only in the new set, in its order
+	Added []models.Transaction
+	// Removed rows are only in the old set, in its order
+	Removed []models.Transaction
+	// Changed rows are in both sets, in the order of the new set
+	Changed   []Change
+	Unchanged int
+	// Columns are the columns compared
+	Columns []models.Column
+}
+
+// Empty reports whether the sets hold the same rows with the same values
+func (r Result) Empty() bool {
+	return len(r.Added) == 0 && len(r.Removed) == 0 && len(r.Changed) == 0
+}
+
+// Compare matches the rows of old and new by row ID and compares the
+// values of columns in the rows found in both. Row IDs leave out values
+// and fees, so a corrected amount shows as a change rather than as a
+// removed and an added row. Rows sharing a row ID within a set are paired
+// in order.
+func Compare(old, new []models.Transaction, columns []models.Column) Result {
+	result := Result{Columns: columns}
+	pending := make(map[string][]int)
+	for i := range old {
+		id := old[i].RowID()
+		pending[id] = append(pending[id], i)
+	}
+	matched := make([]bool, len(old))
+	for i := range new {
+		tx := &new[i]
+		id := tx.RowID()
+		if len(pending[id]) == 0 {
+			result.Added = append(result.Added, *tx)
+			continue
+		}
+		j := pending[id][0]
+		pending[id] = pending[id][1:]
+		matched[j] = true
+		if fields := compareRows(&old[j], tx, columns); len(fields) > 0 {
+			result.Changed = append(result.Changed, Change{Old: old[j], New: *tx, Fields: fields})
+		} else {
+			result.Unchanged++
+		}
+	}
+	for i := range old {
+		if !matched[i] {
+			result.Removed = append(result.Removed, old[i])
+		}
+	}
+	return result
+}
+
+// compareRows returns the columns whose values differ between a and b
+func compareRows(a, b *models.Transaction, columns []models.Column) []Field {
+	var fields []Field
+	for _, col := range columns {
+		if before, after := col.Value(a), col.Value(b); before != after {
+			fields = append(fields, Field{Key: col.Key, Old: before, New: after})
+		}
+	}
+	return fields
+}
+
+// CommonColumns returns the columns of a that b has too, so that a column
+// only one export carries does not make every row differ
+func CommonColumns(a, b []models.Column) []models.Column {
+	var common []models.Column
+	for _, col := range a {
+		for _, other := range b {
+			if col.Key == other.Key {
+				common = append(common, col)
+				break
+			}
+		}
+	}
+	return common
+}
+
+// Write prints the counts, then each added (+), removed (-) and changed
+// (~) row, with the old and new values of changed columns
+func (r Result) Write(w io.Writer) error {
+	if _, err := fmt.Fprintf(w, "Added:     %d\nRemoved:   %d\nChanged:   %d\nUnchanged: %d\n",
+		len(r.Added), len(r.Removed), len(r.Changed), r.Unchanged); err != nil {
+		return err
+	}
+	if !r.Empty() {
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+	}
+	for i := range r.Added {
+		if _, err := fmt.Fprintf(w, "+ %s\n", describe(&r.Added[i])); err != nil {
+			return err
+		}
+	}
+	for i := range r.Removed {
+		if _, err := fmt.Fprintf(w, "- %s\n", describe(&r.Removed[i])); err != nil {
+			return err
+		}
+	}
+	for i := range r.Changed {
+		c := &r.Changed[i]
+		if _, err := fmt.Fprintf(w, "~ %s\n", describe(&c.New)); err != nil {
+			return err
+		}
+		for _, f := range c.Fields {
+			if _, err := fmt.Fprintf(w, "    %s: %q -> %q\n", f.Key, f.Old, f.New); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// describe summarizes a row on one line
+func describe(tx *models.Transaction) string {
+	asset := tx.AssetSymbol
+	if asset == "" {
+		asset = "ETH"
+	}
+	if tx.TokenID != "" {
+		asset += " #" + tx.TokenID
+	}
+	return fmt.Sprintf("%s %s block %d %s %s -> %s %s %s",
+		tx.Timestamp.UTC().Format(time.DateTime), tx.Hash, tx.BlockNumber, tx.Type, tx.From, tx.To, tx.Value, asset)
+}
+
+type changeJSON struct {
+	RowID  string             `json:"row_id"`
+	Row    models.Transaction `json:"row"`
+	Fields []Field            `json:"changes"`
+}
+
+// MarshalJSON encodes the result with the counts and the rows of each kind
+func (r Result) MarshalJSON() ([]byte, error) {
+	changed := make([]changeJSON, len(r.Changed))
+	for i, c := range r.Changed {
+		changed[i] = changeJSON{RowID: c.New.RowID(), Row: c.New, Fields: c.Fields}
+	}
+	rows := func(txs []models.Transaction) []models.Transaction {
+		if txs == nil {
+			return []models.Transaction{}
+		}
+		return txs
+	}
+	return json.Marshal(struct {
+		Added     int                  `json:"added"`
+		Removed   int                  `json:"removed"`
+		Changed   int                  `json:"changed"`
+		Unchanged int                  `json:"unchanged"`
+		AddedRows []models.Transaction `json:"added_rows"`
+		Removals  []models.Transaction `json:"removed_rows"`
+		Changes   []changeJSON         `json:"changed_rows"`
+	}{len(r.Added), len(r.Removed), len(r.Changed), r.Unchanged, rows(r.Added), rows(r.Removed), changed})
+}
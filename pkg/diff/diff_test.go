@@ -0,0 +1,119 @@
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func diffRows() []models.Transaction {
+	at := time.Unix(1700000000, 0)
+	return []models.Transaction{
+		{Hash: "0xa", BlockNumber: 1, Timestamp: at, Type: models.TypeEthTransfer, From: "0x1", To: "0x2", Value: "1", GasFee: "0.001", Status: models.StatusSuccess},
+		{Hash: "0xb", BlockNumber: 2, Timestamp: at, Type: models.TypeERC20Transfer, From: "0x2", To: "0x1", Value: "5", AssetSymbol: "USDC", AssetContractAddr: "0xc", Status: models.StatusSuccess},
+		{Hash: "0xc", BlockNumber: 3, Timestamp: at, Type: models.TypeEthTransfer, From: "0x1", To: "0x3", Value: "2", Status: models.StatusSuccess},
+	}
+}
+
+func TestCompare(t *testing.T) {
+	old := diffRows()
+	new := diffRows()[1:]
+	new[0].Value = "5.5"
+	new[0].Status = models.StatusFailed
+	// Addresses differing only in case are the same row
+	new[1].From = "0X1"
+	new = append(new, models.Transaction{Hash: "0xd", BlockNumber: 4, Type: models.TypeEthTransfer, From: "0x3", To: "0x1", Value: "3"})
+
+	result := Compare(old, new, models.DefaultColumns)
+	if assert.Len(t, result.Added, 1) {
+		assert.Equal(t, "0xd", result.Added[0].Hash)
+	}
+	if assert.Len(t, result.Removed, 1) {
+		assert.Equal(t, "0xa", result.Removed[0].Hash)
+	}
+	if assert.Len(t, result.Changed, 2) {
+		assert.Equal(t, "0xb", result.Changed[0].New.Hash)
+		assert.Equal(t, []Field{
+			{Key: "value", Old: "5", New: "5.5"},
+			{Key: "status", Old: "SUCCESS", New: "FAILED"},
+		}, result.Changed[0].Fields)
+		assert.Equal(t, "from", result.Changed[1].Fields[0].Key)
+	}
+	assert.Equal(t, 0, result.Unchanged)
+	assert.False(t, result.Empty())
+
+	same := Compare(old, diffRows(), models.DefaultColumns)
+	assert.True(t, same.Empty())
+	assert.Equal(t, 3, same.Unchanged)
+}
+
+func TestCompare_Duplicates(t *testing.T) {
+	row := diffRows()[0]
+	result := Compare([]models.Transaction{row}, []models.Transaction{row, row}, models.DefaultColumns)
+	assert.Equal(t, 1, result.Unchanged)
+	assert.Len(t, result.Added, 1, "a repeated row ID is paired once")
+}
+
+func TestCommonColumns(t *testing.T) {
+	extra, _ := models.ParseColumnKeys("hash,nonce,value")
+	common := CommonColumns(models.DefaultColumns, extra)
+	var keys []string
+	for _, col := range common {
+		keys = append(keys, col.Key)
+	}
+	assert.Equal(t, []string{"hash", "value"}, keys)
+}
+
+func TestResult_Write(t *testing.T) {
+	old := diffRows()
+	new := diffRows()[1:]
+	new[0].Value = "6"
+
+	var out bytes.Buffer
+	assert.NoError(t, Compare(old, new, models.DefaultColumns).Write(&out))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if assert.Len(t, lines, 8) {
+		assert.Equal(t, "Added:     0", lines[0])
+		assert.Equal(t, "Removed:   1", lines[1])
+		assert.Equal(t, "Changed:   1", lines[2])
+		assert.Equal(t, "Unchanged: 1", lines[3])
+		assert.Equal(t, "- 2023-11-14 22:13:20 0xa block 1 ETH_TRANSFER 0x1 -> 0x2 1 ETH", lines[5])
+		assert.Equal(t, "~ 2023-11-14 22:13:20 0xb block 2 ERC20_TRANSFER 0x2 -> 0x1 6 USDC", lines[6])
+		assert.Equal(t, `    value: "5" -> "6"`, lines[7])
+	}
+
+	out.Reset()
+	assert.NoError(t, Compare(old, old, models.DefaultColumns).Write(&out))
+	assert.Equal(t, "Added:     0\nRemoved:   0\nChanged:   0\nUnchanged: 3\n", out.String())
+}
+
+func TestResult_MarshalJSON(t *testing.T) {
+	new := diffRows()
+	new[2].Value = "2.5"
+	encoded, err := json.Marshal(Compare(diffRows()[:2], new, models.DefaultColumns))
+	assert.NoError(t, err)
+
+	var decoded struct {
+		Added       int                  `json:"added"`
+		Changed     int                  `json:"changed"`
+		AddedRows   []models.Transaction `json:"added_rows"`
+		RemovedRows []models.Transaction `json:"removed_rows"`
+		ChangedRows []struct {
+			RowID   string  `json:"row_id"`
+			Changes []Field `json:"changes"`
+		} `json:"changed_rows"`
+	}
+	assert.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, 1, decoded.Added)
+	assert.Equal(t, 0, decoded.Changed)
+	assert.NotNil(t, decoded.RemovedRows, "empty lists are encoded as []")
+	assert.Empty(t, decoded.ChangedRows)
+	if assert.Len(t, decoded.AddedRows, 1) {
+		assert.Equal(t, "0xc", decoded.AddedRows[0].Hash)
+	}
+}
@@ -0,0 +1,166 @@
+package enrich
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/haridev22/ct-assignement/pkg/api"
+	"github.com/haridev22/ct-assignement/pkg/cache"
+	"github.com/haridev22/ct-assignement/pkg/chains"
+)
+
+// MaxTokenDecimals is the largest decimals value whose unit still fits in a
+// uint256 (10^77 < 2^256 < 10^78)
+const MaxTokenDecimals = 77
+
+// KnownTokenDecimals holds the decimals of widely held tokens by chain and
+// contract, which take precedence over whatever a transfer row reports. The
+// same address can be another token on another chain, so only the chains
+// listed here are checked against it.
+var KnownTokenDecimals = map[string]map[string]int{
+	chains.Ethereum.Name: {
+		"0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2": 18, // WETH
+		"0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48": 6,  // USDC
+		"0xdac17f958d2ee523a2206206994597c13d831ec7": 6,  // USDT
+		"0x6b175474e89094c44da98b954eedeac495271d0f": 18, // DAI
+		"0x2260fac5e5542a773aa44fbcf7dd3c740ab0c599": 8,  // WBTC
+		"0x514910771af9ca656af840dff83e8264ecf986ca": 18, // LINK
+		"0x1f9840a85d5af5bf1d1762f925bdaddc4201f984": 18, // UNI
+	},
+}
+
+// KnownDecimals returns the decimals KnownTokenDecimals lists for contract
+// on chain, where an empty chain means Ethereum
+func KnownDecimals(chain, contract string) (int, bool) {
+	decimals, ok := KnownTokenDecimals[chainName(chain)][strings.ToLower(contract)]
+	return decimals, ok
+}
+
+// chainName returns the name of chain, taking rows without one as Ethereum
+// rows
+func chainName(chain string) string {
+	if chain == "" {
+		return chains.Ethereum.Name
+	}
+	return chain
+}
+
+// DecimalsCorrection records a transfer whose tokenDecimal was replaced
+type DecimalsCorrection struct {
+	// Index of the transfer in the slice passed to GuardTokenDecimals
+	Index    int
+	Hash     string
+	Contract string
+	Reported string
+	// Decimals is the value used instead, or -1 when none could be determined
+	Decimals int
+}
+
+// Note describes the correction for the Notes column
+func (c DecimalsCorrection) Note() string {
+	if c.Decimals < 0 {
+		return fmt.Sprintf("invalid token decimals %q; amount shown in raw units", c.Reported)
+	}
+	return fmt.Sprintf("token decimals corrected from %q to %d", c.Reported, c.Decimals)
+}
+
+// GuardTokenDecimals checks the tokenDecimal of every transfer against the
+// decimals known for its contract and corrects outliers in place, since a
+// single bad value silently scales the exported amount by orders of
+// magnitude. The transfers are of chain, where an empty chain means
+// Ethereum. The expected decimals of a contract come from
+// KnownTokenDecimals, then the token metadata cache, then the value most
+// transfers of the contract agree on; the result is cached for later runs
+// under the chain and contract. Rows with an unusable value and no expected
+// decimals are set to 0 so the raw amount is exported unscaled.
+func GuardTokenDecimals(c *cache.Cache, chain string, transfers []api.ERC20Transaction) []DecimalsCorrection {
+	if c == nil {
+		c = cache.New()
+	}
+
+	// Tally the plausible values reported for each contract
+	votes := make(map[string]map[int]int)
+	for _, tx := range transfers {
+		decimals, ok := parseDecimals(tx.TokenDecimal)
+		if !ok {
+			continue
+		}
+		contract := strings.ToLower(tx.ContractAddress)
+		if votes[contract] == nil {
+			votes[contract] = make(map[int]int)
+		}
+		votes[contract][decimals]++
+	}
+
+	expected := make(map[string]int)
+	var corrections []DecimalsCorrection
+	for i := range transfers {
+		tx := &transfers[i]
+		contract := strings.ToLower(tx.ContractAddress)
+
+		want, ok := expected[contract]
+		if !ok {
+			want = expectedDecimals(c, chain, contract, votes[contract])
+			expected[contract] = want
+		}
+
+		reported, valid := parseDecimals(tx.TokenDecimal)
+		if valid && (want < 0 || reported == want) {
+			continue
+		}
+
+		correction := DecimalsCorrection{
+			Index:    i,
+			Hash:     tx.Hash,
+			Contract: tx.ContractAddress,
+			Reported: tx.TokenDecimal,
+			Decimals: want,
+		}
+		corrections = append(corrections, correction)
+
+		if want < 0 {
+			want = 0
+		}
+		tx.TokenDecimal = strconv.Itoa(want)
+	}
+
+	return corrections
+}
+
+// expectedDecimals resolves the decimals of contract on chain, or -1 when
+// unknown
+func expectedDecimals(c *cache.Cache, chain, contract string, votes map[int]int) int {
+	if decimals, ok := KnownDecimals(chain, contract); ok {
+		return decimals
+	}
+
+	// Ethereum keeps the keys cached before chains were told apart
+	key := "decimals:" + contract
+	if name := chainName(chain); name != chains.Ethereum.Name {
+		key = "decimals:" + name + ":" + contract
+	}
+	if cached, ok := c.Get(cache.NamespaceTokenMetadata, key); ok {
+		return cached.(int)
+	}
+
+	best, bestVotes := -1, 0
+	for decimals, n := range votes {
+		// Break ties towards more decimals: a spurious "0" is the common failure
+		if n > bestVotes || (n == bestVotes && decimals > best) {
+			best, bestVotes = decimals, n
+		}
+	}
+	if best >= 0 {
+		c.Set(cache.NamespaceTokenMetadata, key, best)
+	}
+	return best
+}
+
+func parseDecimals(s string) (int, bool) {
+	decimals, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || decimals < 0 || decimals > MaxTokenDecimals {
+		return 0, false
+	}
+	return decimals, true
+}
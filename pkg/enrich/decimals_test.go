@@ -0,0 +1,87 @@
+package enrich
+
+import (
+	"testing"
+
+	"github.com/haridev22/ct-assignement/pkg/api"
+	"github.com/haridev22/ct-assignement/pkg/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func transfer(hash, contract, decimals string) api.ERC20Transaction {
+	return api.ERC20Transaction{Hash: hash, ContractAddress: contract, TokenDecimal: decimals, Value: "1000000"}
+}
+
+func TestGuardTokenDecimals_KnownToken(t *testing.T) {
+	transfers := []api.ERC20Transaction{
+		transfer("0x1", "0xA0b86991c6218b36c1d19d4a2e9eb0ce3606eB48", "6"),
+		transfer("0x2", "0xA0b86991c6218b36c1d19d4a2e9eb0ce3606eB48", "0"),
+	}
+
+	corrections := GuardTokenDecimals(nil, "", transfers)
+	assert.Len(t, corrections, 1)
+	assert.Equal(t, 1, corrections[0].Index)
+	assert.Equal(t, 6, corrections[0].Decimals)
+	assert.Equal(t, `token decimals corrected from "0" to 6`, corrections[0].Note())
+	assert.Equal(t, "6", transfers[1].TokenDecimal)
+}
+
+func TestGuardTokenDecimals_Majority(t *testing.T) {
+	c := cache.New()
+	transfers := []api.ERC20Transaction{
+		transfer("0x1", "0xtoken", "18"),
+		transfer("0x2", "0xtoken", "18"),
+		transfer("0x3", "0xtoken", "0"),
+		transfer("0x4", "0xtoken", "255"),
+	}
+
+	corrections := GuardTokenDecimals(c, "ethereum", transfers)
+	assert.Len(t, corrections, 2)
+	for _, tx := range transfers {
+		assert.Equal(t, "18", tx.TokenDecimal)
+	}
+
+	// The consensus is remembered for later batches holding only bad rows
+	later := []api.ERC20Transaction{transfer("0x5", "0xTOKEN", "0")}
+	corrections = GuardTokenDecimals(c, "ethereum", later)
+	assert.Len(t, corrections, 1)
+	assert.Equal(t, "18", later[0].TokenDecimal)
+}
+
+func TestGuardTokenDecimals_Unknown(t *testing.T) {
+	transfers := []api.ERC20Transaction{
+		transfer("0x1", "0xzero", "0"),
+		transfer("0x2", "0xbroken", "abc"),
+	}
+
+	corrections := GuardTokenDecimals(nil, "", transfers)
+
+	// A token that consistently reports 0 decimals is left alone
+	assert.Len(t, corrections, 1)
+	assert.Equal(t, "0x2", corrections[0].Hash)
+	assert.Equal(t, -1, corrections[0].Decimals)
+	assert.Contains(t, corrections[0].Note(), "raw units")
+	assert.Equal(t, "0", transfers[1].TokenDecimal)
+}
+
+func TestGuardTokenDecimals_OtherChain(t *testing.T) {
+	// The mainnet USDC address is not known to be USDC on another chain
+	usdc := "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+	c := cache.New()
+	transfers := []api.ERC20Transaction{transfer("0x1", usdc, "18"), transfer("0x2", usdc, "18")}
+	assert.Empty(t, GuardTokenDecimals(c, "polygon", transfers))
+	assert.Equal(t, "18", transfers[0].TokenDecimal)
+
+	// Decimals agreed on one chain are not applied on another
+	mainnet := []api.ERC20Transaction{transfer("0x3", "0xtoken", "6"), transfer("0x4", "0xtoken", "6")}
+	GuardTokenDecimals(c, "ethereum", mainnet)
+	bsc := []api.ERC20Transaction{transfer("0x5", "0xtoken", "18")}
+	assert.Empty(t, GuardTokenDecimals(c, "bsc", bsc))
+	assert.Equal(t, "18", bsc[0].TokenDecimal)
+
+	known, ok := KnownDecimals("", "0xA0b86991c6218b36c1d19d4a2e9eb0ce3606eB48")
+	assert.True(t, ok, "rows without a chain are Ethereum rows")
+	assert.Equal(t, 6, known)
+	_, ok = KnownDecimals("polygon", usdc)
+	assert.False(t, ok)
+}
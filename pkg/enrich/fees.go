@@ -0,0 +1,126 @@
+// Package enrich adds information to transactions that the account list
+// endpoints do not return, using additional provider calls.
+package enrich
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/haridev22/ct-assignement/pkg/api"
+	"github.com/haridev22/ct-assignement/pkg/cache"
+	"github.com/haridev22/ct-assignement/pkg/models"
+)
+
+// NamespaceBaseFees caches block base fees by block number
+const NamespaceBaseFees = "base_fees"
+
+// FeeSource provides the receipts and blocks needed for the fee breakdown
+type FeeSource interface {
+	GetTransactionReceipt(hash string) (*api.TransactionReceipt, error)
+	GetBlockByNumber(number int64) (*api.Block, error)
+}
+
+// FeeBreakdown splits the gas fee of every fee-paying row into the EIP-1559
+// base fee (burned) and priority fee (paid to the block producer), and
+// records the effective gas price. Rows that share a hash share a receipt.
+// It returns the number of rows enriched; failures for individual
+// transactions are joined into the returned error and leave those rows as-is.
+func FeeBreakdown(src FeeSource, c *cache.Cache, transactions []models.Transaction) (int, error) {
+	if c == nil {
+		c = cache.New()
+	}
+
+	byHash := make(map[string][]int)
+	var order []string
+	for i := range transactions {
+		if transactions[i].GasFee == "" || transactions[i].GasFee == "0" {
+			continue
+		}
+		hash := transactions[i].Hash
+		if _, ok := byHash[hash]; !ok {
+			order = append(order, hash)
+		}
+		byHash[hash] = append(byHash[hash], i)
+	}
+
+	var errs []error
+	enriched := 0
+	for _, hash := range order {
+		breakdown, err := feeBreakdown(src, c, hash)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("fee breakdown for %s: %w", hash, err))
+			continue
+		}
+		for _, i := range byHash[hash] {
+			transactions[i].EffectiveGasPrice = breakdown.effectiveGasPrice
+			transactions[i].BaseFee = breakdown.baseFee
+			transactions[i].PriorityFee = breakdown.priorityFee
+			enriched++
+		}
+	}
+
+	return enriched, errors.Join(errs...)
+}
+
+type fees struct {
+	effectiveGasPrice string
+	baseFee           string
+	priorityFee       string
+}
+
+func feeBreakdown(src FeeSource, c *cache.Cache, hash string) (fees, error) {
+	receipt, err := cachedReceipt(src, c, hash)
+	if err != nil {
+		return fees{}, err
+	}
+
+	gasUsed, err := api.ParseHexBig(receipt.GasUsed)
+	if err != nil {
+		return fees{}, err
+	}
+	effective, err := api.ParseHexBig(receipt.EffectiveGasPrice)
+	if err != nil {
+		return fees{}, err
+	}
+	blockNumber, err := api.ParseHexBig(receipt.BlockNumber)
+	if err != nil {
+		return fees{}, err
+	}
+
+	baseFeePerGas, err := blockBaseFee(src, c, blockNumber.Int64())
+	if err != nil {
+		return fees{}, err
+	}
+
+	// Pre-London blocks have no base fee, so the whole price is priority fee
+	tip := new(big.Int).Sub(effective, baseFeePerGas)
+	return fees{
+		effectiveGasPrice: effective.String(),
+		baseFee:           api.FormatWeiAsEth(new(big.Int).Mul(baseFeePerGas, gasUsed)),
+		priorityFee:       api.FormatWeiAsEth(new(big.Int).Mul(tip, gasUsed)),
+	}, nil
+}
+
+func blockBaseFee(src FeeSource, c *cache.Cache, number int64) (*big.Int, error) {
+	key := strconv.FormatInt(number, 10)
+	if cached, ok := c.Get(NamespaceBaseFees, key); ok {
+		return cached.(*big.Int), nil
+	}
+
+	block, err := src.GetBlockByNumber(number)
+	if err != nil {
+		return nil, err
+	}
+
+	baseFee := new(big.Int)
+	if block.BaseFeePerGas != "" {
+		if baseFee, err = api.ParseHexBig(block.BaseFeePerGas); err != nil {
+			return nil, err
+		}
+	}
+
+	c.Set(NamespaceBaseFees, key, baseFee)
+	return baseFee, nil
+}
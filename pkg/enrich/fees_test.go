@@ -0,0 +1,92 @@
+package enrich
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/haridev22/ct-assignement/pkg/api"
+	"github.com/haridev22/ct-assignement/pkg/cache"
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeFeeSource struct {
+	receipts    map[string]*api.TransactionReceipt
+	blocks      map[int64]*api.Block
+	blockCalls  int
+	receiptHits map[string]int
+}
+
+func (f *fakeFeeSource) GetTransactionReceipt(hash string) (*api.TransactionReceipt, error) {
+	f.receiptHits[hash]++
+	receipt, ok := f.receipts[hash]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return receipt, nil
+}
+
+func (f *fakeFeeSource) GetBlockByNumber(number int64) (*api.Block, error) {
+	f.blockCalls++
+	return f.blocks[number], nil
+}
+
+func TestFeeBreakdown(t *testing.T) {
+	src := &fakeFeeSource{
+		receipts: map[string]*api.TransactionReceipt{
+			// 21000 gas at 30 gwei effective, base fee 25 gwei
+			"0x1": {BlockNumber: "0x10", GasUsed: "0x5208", EffectiveGasPrice: "0x6fc23ac00"},
+			// Same block, so the base fee comes from the cache
+			"0x2": {BlockNumber: "0x10", GasUsed: "0x5208", EffectiveGasPrice: "0x5d21dba00"},
+		},
+		blocks: map[int64]*api.Block{
+			16: {Number: "0x10", BaseFeePerGas: "0x5d21dba00"},
+		},
+		receiptHits: make(map[string]int),
+	}
+
+	transactions := []models.Transaction{
+		{Hash: "0x1", Type: models.TypeEthTransfer, GasFee: "0.00063"},
+		{Hash: "0x1", Type: models.TypeERC20Transfer, GasFee: "0.00063"},
+		{Hash: "0x2", Type: models.TypeEthTransfer, GasFee: "0.000525"},
+		{Hash: "0x3", Type: models.TypeInternalTx, GasFee: "0"},
+	}
+
+	enriched, err := FeeBreakdown(src, cache.New(), transactions)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, enriched)
+
+	assert.Equal(t, "30000000000", transactions[0].EffectiveGasPrice)
+	assert.Equal(t, "0.000525000000000000", transactions[0].BaseFee)
+	assert.Equal(t, "0.000105000000000000", transactions[0].PriorityFee)
+	assert.Equal(t, transactions[0].BaseFee, transactions[1].BaseFee)
+	assert.Equal(t, "0.000000000000000000", transactions[2].PriorityFee)
+	assert.Empty(t, transactions[3].EffectiveGasPrice)
+
+	assert.Equal(t, 1, src.receiptHits["0x1"], "rows sharing a hash share one receipt")
+	assert.Equal(t, 1, src.blockCalls, "base fee is cached per block")
+}
+
+func TestFeeBreakdown_PartialFailure(t *testing.T) {
+	src := &fakeFeeSource{
+		receipts: map[string]*api.TransactionReceipt{
+			// Pre-London block without a base fee
+			"0x1": {BlockNumber: "0x1", GasUsed: "0x5208", EffectiveGasPrice: "0x4a817c800"},
+		},
+		blocks:      map[int64]*api.Block{1: {Number: "0x1"}},
+		receiptHits: make(map[string]int),
+	}
+
+	transactions := []models.Transaction{
+		{Hash: "0x1", GasFee: "0.00042"},
+		{Hash: "0xmissing", GasFee: "0.00042"},
+	}
+
+	enriched, err := FeeBreakdown(src, nil, transactions)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "0xmissing")
+	assert.Equal(t, 1, enriched)
+	assert.Equal(t, "0.000000000000000000", transactions[0].BaseFee)
+	assert.Equal(t, "0.000420000000000000", transactions[0].PriorityFee)
+	assert.Empty(t, transactions[1].BaseFee)
+}
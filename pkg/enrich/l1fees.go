@@ -0,0 +1,88 @@
+package enrich
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/haridev22/ct-assignement/pkg/api"
+	"github.com/haridev22/ct-assignement/pkg/cache"
+	"github.com/haridev22/ct-assignement/pkg/models"
+)
+
+// NamespaceReceipts caches receipts by transaction hash, so the fee
+// breakdown and the L1 fees of a run fetch each receipt once
+const NamespaceReceipts = "receipts"
+
+// ReceiptSource provides transaction receipts
+type ReceiptSource interface {
+	GetTransactionReceipt(hash string) (*api.TransactionReceipt, error)
+}
+
+// L1Fees records the L1 data fee of every fee-paying row on an OP-stack
+// chain such as Optimism or Base, where posting the transaction data to
+// Ethereum is charged on top of gasPrice×gasUsed and often dominates the
+// cost. Receipts without an L1 fee, such as those of deposits, record a zero
+// fee. Rows that share a hash share a receipt. It returns the number of rows
+// enriched; failures for individual transactions are joined into the
+// returned error and leave those rows as-is.
+func L1Fees(src ReceiptSource, c *cache.Cache, transactions []models.Transaction) (int, error) {
+	if c == nil {
+		c = cache.New()
+	}
+
+	var errs []error
+	fees := make(map[string]string)
+	enriched := 0
+	for i := range transactions {
+		tx := &transactions[i]
+		if tx.GasFee == "" || tx.GasFee == "0" {
+			continue
+		}
+		fee, ok := fees[tx.Hash]
+		if !ok {
+			l1Fee, err := l1Fee(src, c, tx.Hash)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("L1 fee for %s: %w", tx.Hash, err))
+				fees[tx.Hash] = ""
+				continue
+			}
+			fee = api.FormatWeiAsEth(l1Fee)
+			fees[tx.Hash] = fee
+		}
+		if fee == "" {
+			continue
+		}
+		tx.L1Fee = fee
+		enriched++
+	}
+
+	return enriched, errors.Join(errs...)
+}
+
+func l1Fee(src ReceiptSource, c *cache.Cache, hash string) (*big.Int, error) {
+	receipt, err := cachedReceipt(src, c, hash)
+	if err != nil {
+		return nil, err
+	}
+	if receipt.L1Fee == "" {
+		return new(big.Int), nil
+	}
+	return api.ParseHexBig(receipt.L1Fee)
+}
+
+// cachedReceipt fetches the receipt of hash through the receipt cache
+func cachedReceipt(src ReceiptSource, c *cache.Cache, hash string) (*api.TransactionReceipt, error) {
+	if cached, ok := c.Get(NamespaceReceipts, hash); ok {
+		return cached.(*api.TransactionReceipt), nil
+	}
+	receipt, err := src.GetTransactionReceipt(hash)
+	if err != nil {
+		return nil, err
+	}
+	if receipt == nil {
+		return nil, fmt.Errorf("receipt not found")
+	}
+	c.Set(NamespaceReceipts, hash, receipt)
+	return receipt, nil
+}
@@ -0,0 +1,49 @@
+package enrich
+
+import (
+	"testing"
+
+	"github.com/haridev22/ct-assignement/pkg/api"
+	"github.com/haridev22/ct-assignement/pkg/cache"
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestL1Fees(t *testing.T) {
+	src := &fakeFeeSource{
+		receipts: map[string]*api.TransactionReceipt{
+			"0x1": {BlockNumber: "0x10", GasUsed: "0x5208", EffectiveGasPrice: "0x3b9aca00", L1Fee: "0x886c98b76000"},
+			// Deposits carry no L1 fee
+			"0x2": {BlockNumber: "0x10", GasUsed: "0x5208", EffectiveGasPrice: "0x0"},
+		},
+		blocks:      map[int64]*api.Block{16: {Number: "0x10", BaseFeePerGas: "0x3b9aca00"}},
+		receiptHits: make(map[string]int),
+	}
+
+	transactions := []models.Transaction{
+		{Hash: "0x1", Type: models.TypeEthTransfer, GasFee: "0.000021"},
+		{Hash: "0x1", Type: models.TypeERC20Transfer, GasFee: "0.000021"},
+		{Hash: "0x2", Type: models.TypeEthTransfer, GasFee: "0.000001"},
+		{Hash: "0x3", Type: models.TypeInternalTx, GasFee: "0"},
+		{Hash: "0x4", Type: models.TypeEthTransfer, GasFee: "0.000021"},
+	}
+
+	c := cache.New()
+	enriched, err := L1Fees(src, c, transactions)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "0x4")
+	assert.Equal(t, 3, enriched)
+
+	assert.Equal(t, "0.000150000000000000", transactions[0].L1Fee)
+	assert.Equal(t, "0.000150000000000000", transactions[1].L1Fee)
+	assert.Equal(t, "0.000000000000000000", transactions[2].L1Fee)
+	assert.Empty(t, transactions[3].L1Fee)
+	assert.Empty(t, transactions[4].L1Fee)
+	assert.Equal(t, 1, src.receiptHits["0x1"])
+
+	// The fee breakdown reuses the cached receipts
+	_, err = FeeBreakdown(src, c, transactions[:3])
+	assert.NoError(t, err)
+	assert.Equal(t, 1, src.receiptHits["0x1"])
+	assert.Equal(t, 1, src.receiptHits["0x2"])
+}
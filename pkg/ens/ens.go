@@ -0,0 +1,146 @@
+// Package ens resolves addresses to their primary ENS names.
+package ens
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/haridev22/ct-assignement/pkg/cache"
+	"github.com/haridev22/ct-assignement/pkg/rpc"
+)
+
+// Registry is the address of the ENS registry on Ethereum mainnet
+const Registry = "0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e"
+
+const (
+	// selectorResolver is the selector of the registry's resolver(bytes32)
+	selectorResolver = "0178b8bf"
+	// selectorName is the selector of a resolver's name(bytes32)
+	selectorName = "691f3431"
+	// selectorAddr is the selector of a resolver's addr(bytes32)
+	selectorAddr = "3b3b57de"
+)
+
+// Caller executes read-only contract calls at the latest block
+type Caller interface {
+	CallContract(to, data string) (string, error)
+}
+
+// Resolver looks up the primary names of addresses
+type Resolver struct {
+	Node Caller
+	// Registry is the ENS registry, the mainnet Registry when empty
+	Registry string
+	// Cache, when set, keeps the names looked up, including addresses
+	// without a name
+	Cache *cache.Cache
+}
+
+// Name returns the primary ENS name of address, or "" when it has none.
+// Like wallets do, a name is only returned when it resolves back to
+// address, since anyone can set any reverse record.
+func (r *Resolver) Name(address string) (string, error) {
+	key := strings.ToLower(address)
+	if r.Cache != nil {
+		if cached, ok := r.Cache.Get(cache.NamespaceENS, key); ok {
+			return cached.(string), nil
+		}
+	}
+	name, err := r.lookup(key)
+	if err != nil {
+		return "", err
+	}
+	if r.Cache != nil {
+		r.Cache.Set(cache.NamespaceENS, key, name)
+	}
+	return name, nil
+}
+
+func (r *Resolver) lookup(address string) (string, error) {
+	reverse := Namehash(strings.TrimPrefix(address, "0x") + ".addr.reverse")
+	resolver, err := r.resolver(reverse)
+	if err != nil || resolver == "" {
+		return "", err
+	}
+	out, err := r.Node.CallContract(resolver, "0x"+selectorName+hex.EncodeToString(reverse))
+	if err != nil {
+		// Resolvers without name() revert; the address has no name
+		return "", nil
+	}
+	name := decodeString(out)
+	if name == "" {
+		return "", nil
+	}
+
+	forward := Namehash(name)
+	if resolver, err = r.resolver(forward); err != nil || resolver == "" {
+		return "", err
+	}
+	out, err = r.Node.CallContract(resolver, "0x"+selectorAddr+hex.EncodeToString(forward))
+	if err != nil || !strings.EqualFold(wordAddress(out), address) {
+		return "", nil
+	}
+	return name, nil
+}
+
+// resolver returns the resolver of node, or "" when it has none
+func (r *Resolver) resolver(node []byte) (string, error) {
+	registry := r.Registry
+	if registry == "" {
+		registry = Registry
+	}
+	out, err := r.Node.CallContract(registry, "0x"+selectorResolver+hex.EncodeToString(node))
+	if err != nil {
+		return "", fmt.Errorf("ENS registry call failed: %w", err)
+	}
+	resolver := wordAddress(out)
+	if resolver == "" || strings.Trim(resolver[2:], "0") == "" {
+		return "", nil
+	}
+	return resolver, nil
+}
+
+// Namehash computes the ENS node of a name as defined by EIP-137. Names are
+// lowercased, which covers the ASCII names most addresses use; full
+// UTS-46 normalisation is not applied.
+func Namehash(name string) []byte {
+	node := make([]byte, 32)
+	if name == "" {
+		return node
+	}
+	labels := strings.Split(strings.ToLower(name), ".")
+	for i := len(labels) - 1; i >= 0; i-- {
+		node = rpc.Keccak256(append(node, rpc.Keccak256([]byte(labels[i]))...))
+	}
+	return node
+}
+
+// wordAddress returns the address in the last 20 bytes of a 32-byte ABI
+// word, or "" when out is not a word
+func wordAddress(out string) string {
+	digits := strings.TrimPrefix(out, "0x")
+	if len(digits) < 64 {
+		return ""
+	}
+	return "0x" + strings.ToLower(digits[24:64])
+}
+
+// decodeString decodes an ABI-encoded string return value
+func decodeString(out string) string {
+	raw, err := hex.DecodeString(strings.TrimPrefix(out, "0x"))
+	if err != nil || len(raw) < 64 {
+		return ""
+	}
+	offset := new(big.Int).SetBytes(raw[:32])
+	if !offset.IsInt64() || offset.Int64()+32 > int64(len(raw)) {
+		return ""
+	}
+	start := offset.Int64()
+	length := new(big.Int).SetBytes(raw[start : start+32])
+	if !length.IsInt64() || start+32+length.Int64() > int64(len(raw)) {
+		return ""
+	}
+	return string(raw[start+32 : start+32+length.Int64()])
+}
@@ -0,0 +1,89 @@
+package ens
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/haridev22/ct-assignement/pkg/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamehash(t *testing.T) {
+	assert.Equal(t, strings.Repeat("0", 64), hex.EncodeToString(Namehash("")))
+	assert.Equal(t, "93cdeb708b7545dc668eb9280176169d1c33cfd8ed6f04690a0bcc88a93fc4ae", hex.EncodeToString(Namehash("eth")))
+	assert.Equal(t, "de9b09fd7c5f901e23a3f19fecc54828e9c848539801e86591bd9801b019f84f", hex.EncodeToString(Namehash("Foo.eth")))
+}
+
+const (
+	alice        = "0x1111111111111111111111111111111111111111"
+	impersonator = "0x2222222222222222222222222222222222222222"
+	resolverAddr = "0x4976fb03c32e5b8cfe2b6ccb31c09ba78ebaba41"
+)
+
+// fakeNode answers ENS calls from a registry where alice.eth resolves to
+// alice, and both alice and the impersonator claim alice.eth as their name
+type fakeNode struct {
+	calls int
+}
+
+func (f *fakeNode) CallContract(to, data string) (string, error) {
+	f.calls++
+	word := func(address string) string { return strings.Repeat("0", 24) + address[2:] }
+	selector, node := data[2:10], data[10:]
+	names := map[string]bool{
+		hex.EncodeToString(Namehash(alice[2:] + ".addr.reverse")):        true,
+		hex.EncodeToString(Namehash(impersonator[2:] + ".addr.reverse")): true,
+		hex.EncodeToString(Namehash("alice.eth")):                        true,
+	}
+	switch {
+	case to == Registry && selector == selectorResolver:
+		if !names[node] {
+			return "0x" + strings.Repeat("0", 64), nil
+		}
+		return "0x" + word(resolverAddr), nil
+	case to == resolverAddr && selector == selectorName:
+		name := "alice.eth"
+		return "0x" + fmt.Sprintf("%064x%064x", 32, len(name)) + hex.EncodeToString([]byte(name)) + strings.Repeat("0", 64-2*len(name)), nil
+	case to == resolverAddr && selector == selectorAddr && node == hex.EncodeToString(Namehash("alice.eth")):
+		return "0x" + word(alice), nil
+	}
+	return "", errors.New("execution reverted")
+}
+
+func TestResolver_Name(t *testing.T) {
+	node := &fakeNode{}
+	r := &Resolver{Node: node, Cache: cache.New()}
+
+	name, err := r.Name(strings.ToUpper(alice[:2]) + alice[2:])
+	assert.NoError(t, err)
+	assert.Equal(t, "alice.eth", name)
+
+	// The reverse record of the impersonator does not resolve back to it
+	name, err = r.Name(impersonator)
+	assert.NoError(t, err)
+	assert.Empty(t, name)
+
+	name, err = r.Name("0x3333333333333333333333333333333333333333")
+	assert.NoError(t, err)
+	assert.Empty(t, name)
+
+	// Names, and their absence, are cached
+	calls := node.calls
+	r.Name(alice)
+	r.Name("0x3333333333333333333333333333333333333333")
+	assert.Equal(t, calls, node.calls)
+}
+
+type failingNode struct{}
+
+func (failingNode) CallContract(to, data string) (string, error) {
+	return "", errors.New("connection refused")
+}
+
+func TestResolver_NameErrors(t *testing.T) {
+	_, err := (&Resolver{Node: failingNode{}}).Name(alice)
+	assert.ErrorContains(t, err, "connection refused")
+}
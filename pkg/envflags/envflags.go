@@ -0,0 +1,45 @@
+// Package envflags lets every command line flag be set from an environment
+// variable, so the tool can be configured in CI and containers without
+// putting secrets on the command line.
+package envflags
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Prefix is prepended to the variable name of every flag
+const Prefix = "ETH_TX_HISTORY_"
+
+// Name returns the environment variable for a flag, e.g.
+// ETH_TX_HISTORY_MIN_CONFIRMATIONS for -min-confirmations
+func Name(flagName string) string {
+	return Prefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// Apply sets the flags of fs from their environment variables. Call it
+// before fs.Parse so flags given on the command line take precedence.
+func Apply(fs *flag.FlagSet) error {
+	return ApplyFrom(fs, os.LookupEnv)
+}
+
+// ApplyFrom is Apply with a custom variable lookup
+func ApplyFrom(fs *flag.FlagSet, lookup func(string) (string, bool)) error {
+	var err error
+	fs.VisitAll(func(f *flag.Flag) {
+		if err != nil {
+			return
+		}
+		name := Name(f.Name)
+		value, ok := lookup(name)
+		if !ok {
+			return
+		}
+		if setErr := fs.Set(f.Name, value); setErr != nil {
+			err = fmt.Errorf("invalid value %q for %s: %w", value, name, setErr)
+		}
+	})
+	return err
+}
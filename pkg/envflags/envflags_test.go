@@ -0,0 +1,50 @@
+package envflags
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestName(t *testing.T) {
+	assert.Equal(t, "ETH_TX_HISTORY_APIKEY", Name("apikey"))
+	assert.Equal(t, "ETH_TX_HISTORY_MIN_CONFIRMATIONS", Name("min-confirmations"))
+}
+
+func TestApplyFrom(t *testing.T) {
+	env := map[string]string{
+		"ETH_TX_HISTORY_APIKEY":        "from-env",
+		"ETH_TX_HISTORY_BATCH":         "1000",
+		"ETH_TX_HISTORY_FEE_BREAKDOWN": "true",
+		"ETH_TX_HISTORY_OUTPUT":        "/env/output",
+	}
+	lookup := func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	apiKey := fs.String("apikey", "", "")
+	batch := fs.Int64("batch", 0, "")
+	feeBreakdown := fs.Bool("fee-breakdown", false, "")
+	output := fs.String("output", "./output", "")
+	start := fs.Int64("start", 0, "")
+
+	assert.NoError(t, ApplyFrom(fs, lookup))
+	assert.NoError(t, fs.Parse([]string{"-output", "/cli/output"}))
+
+	assert.Equal(t, "from-env", *apiKey)
+	assert.Equal(t, int64(1000), *batch)
+	assert.True(t, *feeBreakdown)
+	assert.Equal(t, "/cli/output", *output, "the command line wins over the environment")
+	assert.Equal(t, int64(0), *start)
+}
+
+func TestApplyFrom_InvalidValue(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Int64("batch", 0, "")
+
+	err := ApplyFrom(fs, func(name string) (string, bool) { return "lots", true })
+	assert.ErrorContains(t, err, "ETH_TX_HISTORY_BATCH")
+}
@@ -0,0 +1,88 @@
+package export
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+)
+
+// AppendCSV merges transactions into the export at filePath, written with
+// the same opts, and returns the number of rows added and the number of
+// rows in the file. Rows already in the file, by row ID, are left as they
+// are, so running the same incremental export twice changes nothing. Row
+// IDs are taken from the columns the file holds, as fields such as the
+// trace ID of an internal transfer are lost when the file does not write
+// them; rows that share an ID there are counted, so a file holding one of
+// them only absorbs one new row of that ID. A missing file is created. The
+// file is replaced atomically, so an interrupted run cannot truncate it.
+func AppendCSV(transactions []models.Transaction, filePath string, opts CSVOptions) (added, total int, err error) {
+	existing, columns, err := ReadCSVFileWithOptions(filePath, opts)
+	if errors.Is(err, os.ErrNotExist) {
+		return len(transactions), len(transactions), WriteCSVWithOptions(transactions, filePath, opts)
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	if !sameColumns(columns, opts.Columns) {
+		return 0, 0, fmt.Errorf("%s has different columns than the export; append with the column options it was written with", filePath)
+	}
+
+	inFile := make(map[string]int, len(existing))
+	for i := range existing {
+		inFile[writtenRowID(&existing[i], columns)]++
+	}
+	merged := existing
+	for i := range transactions {
+		id := writtenRowID(&transactions[i], columns)
+		if inFile[id] > 0 {
+			inFile[id]--
+			continue
+		}
+		merged = append(merged, transactions[i])
+	}
+	added, total = len(merged)-len(existing), len(merged)
+	if added == 0 {
+		return 0, total, nil
+	}
+
+	tmp := filepath.Join(filepath.Dir(filePath), "."+filepath.Base(filePath)+".tmp")
+	if err := WriteCSVWithOptions(merged, tmp, opts); err != nil {
+		os.Remove(tmp)
+		return 0, 0, err
+	}
+	if err := os.Rename(tmp, filePath); err != nil {
+		os.Remove(tmp)
+		return 0, 0, fmt.Errorf("failed to replace CSV file: %w", err)
+	}
+	return added, total, nil
+}
+
+// writtenRowID returns the row ID of tx as it reads back from a file
+// holding columns
+func writtenRowID(tx *models.Transaction, columns []models.Column) string {
+	var written models.Transaction
+	for _, col := range columns {
+		// Values written by Value always parse back
+		_ = col.Set(&written, col.Value(tx))
+	}
+	return written.RowID()
+}
+
+// sameColumns reports whether the columns of a file are the selected ones
+func sameColumns(columns, selected []models.Column) bool {
+	if len(selected) == 0 {
+		selected = models.DefaultColumns
+	}
+	if len(columns) != len(selected) {
+		return false
+	}
+	for i := range columns {
+		if columns[i].Key != selected[i].Key {
+			return false
+		}
+	}
+	return true
+}
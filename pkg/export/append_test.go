@@ -0,0 +1,102 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppendCSV(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "0xabc_tx_history.csv")
+	first := []models.Transaction{
+		{Hash: "0x1", BlockNumber: 1, Timestamp: time.Unix(1700000000, 0), Type: models.TypeEthTransfer, Value: "1.0"},
+		{Hash: "0x2", BlockNumber: 2, Timestamp: time.Unix(1700000100, 0), Type: models.TypeEthTransfer, Value: "2.0"},
+	}
+
+	added, total, err := AppendCSV(first, path, CSVOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, added)
+	assert.Equal(t, 2, total)
+
+	// The overlapping row is kept as already exported
+	second := []models.Transaction{
+		{Hash: "0x2", BlockNumber: 2, Timestamp: time.Unix(1700000100, 0), Type: models.TypeEthTransfer, Value: "9.0"},
+		{Hash: "0x3", BlockNumber: 3, Timestamp: time.Unix(1700000200, 0), Type: models.TypeEthTransfer, Value: "3.0"},
+	}
+	added, total, err = AppendCSV(second, path, CSVOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, added)
+	assert.Equal(t, 3, total)
+
+	added, _, err = AppendCSV(second, path, CSVOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, added, "appending the same rows again is a no-op")
+
+	txs, _, err := ReadCSVFile(path)
+	assert.NoError(t, err)
+	if assert.Len(t, txs, 3) {
+		assert.Equal(t, "2.0", txs[1].Value)
+		assert.Equal(t, "0x3", txs[2].Hash)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	assert.Len(t, entries, 1, "no temporary file is left behind")
+}
+
+func TestAppendCSV_DifferentColumns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "0xabc_tx_history.csv")
+	assert.NoError(t, WriteCSV([]models.Transaction{{Hash: "0x1"}}, path))
+
+	columns, _ := models.ParseColumnKeys("hash,value")
+	_, _, err := AppendCSV([]models.Transaction{{Hash: "0x2"}}, path, CSVOptions{Columns: columns})
+	assert.Error(t, err)
+}
+
+func TestAppendCSV_NoHeader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "0xabc_tx_history.tsv")
+	columns, _ := models.ParseColumnKeys("hash,block,value")
+	opts := CSVOptions{Columns: columns, Delimiter: '\t', NoHeader: true}
+
+	_, _, err := AppendCSV([]models.Transaction{{Hash: "0x1", BlockNumber: 1}}, path, opts)
+	assert.NoError(t, err)
+	added, _, err := AppendCSV([]models.Transaction{{Hash: "0x1", BlockNumber: 1}, {Hash: "0x2", BlockNumber: 2}}, path, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, added)
+
+	content, _ := os.ReadFile(path)
+	assert.Equal(t, "0x1\t1\t\n0x2\t2\t\n", string(content))
+}
+
+func TestAppendCSV_FieldsNotWritten(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "0xabc_tx_history.csv")
+	// The default columns write neither the trace ID nor the chain
+	txs := []models.Transaction{
+		{Hash: "0x1", BlockNumber: 1, Timestamp: time.Unix(1700000000, 0), Type: models.TypeInternalTx, Value: "1.0", TraceID: "0_1"},
+		{Hash: "0x1", BlockNumber: 1, Timestamp: time.Unix(1700000000, 0), Type: models.TypeInternalTx, Value: "2.0", TraceID: "0_2"},
+		{Hash: "0x2", BlockNumber: 2, Timestamp: time.Unix(1700000100, 0), Type: models.TypeEthTransfer, Value: "3.0", Chain: "polygon"},
+	}
+
+	added, _, err := AppendCSV(txs[:1], path, CSVOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, added)
+	added, total, err := AppendCSV(txs, path, CSVOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, added, "a row in the file only matches one new row of its written ID")
+	assert.Equal(t, 3, total)
+
+	added, total, err = AppendCSV(txs, path, CSVOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, added, "appending the same rows again is a no-op")
+	assert.Equal(t, 3, total)
+
+	// New rows that only differ in fields the file does not write are kept
+	other := filepath.Join(t.TempDir(), "0xabc_tx_history.csv")
+	assert.NoError(t, WriteCSV(txs[2:], other))
+	added, total, err = AppendCSV(txs[:2], other, CSVOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, added)
+	assert.Equal(t, 3, total)
+}
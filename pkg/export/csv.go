@@ -0,0 +1,194 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+)
+
+// CSVOptions controls how WriteCSVWithOptions lays out the file
+type CSVOptions struct {
+	// Columns to write, in order. Defaults to models.DefaultColumns.
+	Columns []models.Column
+	// Delimiter separates the fields. Defaults to a comma; a tab writes TSV.
+	Delimiter rune
+	// QuoteAll quotes every field instead of only those that need it
+	QuoteAll bool
+	// NoHeader leaves out the header row
+	NoHeader bool
+	// Excel writes a UTF-8 byte order mark and CRLF line endings, and
+	// wraps numbers Excel would round or reformat as ="..." text formulas
+	Excel bool
+}
+
+// utf8BOM marks a file as UTF-8 for Excel
+const utf8BOM = "\xef\xbb\xbf"
+
+// Extension returns the file extension for files written with o, .tsv for
+// tab-separated files and .csv otherwise
+func (o CSVOptions) Extension() string {
+	if o.Delimiter == '\t' {
+		return ".tsv"
+	}
+	return ".csv"
+}
+
+// ParseDelimiter reads a field delimiter given as a single character, or
+// as tab or \t for TSV
+func ParseDelimiter(s string) (rune, error) {
+	switch strings.ToLower(s) {
+	case "tab", `\t`, "\t":
+		return '\t', nil
+	}
+	runes := []rune(s)
+	if len(runes) != 1 || runes[0] == '"' || runes[0] == '\r' || runes[0] == '\n' || runes[0] == utf8.RuneError {
+		return 0, fmt.Errorf("invalid delimiter %q (use a single character, or tab)", s)
+	}
+	return runes[0], nil
+}
+
+// WriteCSV writes transactions to a CSV file
+func WriteCSV(transactions []models.Transaction, filePath string) error {
+	return WriteCSVWithOptions(transactions, filePath, CSVOptions{})
+}
+
+// WriteCSVWithOptions writes transactions to a CSV file using the given options
+func WriteCSVWithOptions(transactions []models.Transaction, filePath string, opts CSVOptions) error {
+	// Create directory if it doesn't exist
+	dir := filepath.Dir(filePath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	file, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to create CSV file: %w", err)
+	}
+	defer file.Close()
+
+	return WriteCSVTo(file, transactions, opts)
+}
+
+// WriteCSVTo writes transactions as CSV to w using the given options, for
+// streams such as stdout
+func WriteCSVTo(w io.Writer, transactions []models.Transaction, opts CSVOptions) error {
+	columns := opts.Columns
+	if len(columns) == 0 {
+		columns = models.DefaultColumns
+	}
+
+	if opts.Excel {
+		if _, err := io.WriteString(w, utf8BOM); err != nil {
+			return fmt.Errorf("failed to write CSV file: %w", err)
+		}
+	}
+	writer := newRecordWriter(w, opts)
+
+	// Write CSV header
+	if !opts.NoHeader {
+		if err := writer.Write(models.Headers(columns)); err != nil {
+			return fmt.Errorf("failed to write CSV header: %w", err)
+		}
+	}
+
+	// Write transaction records
+	for _, tx := range transactions {
+		record := tx.Record(columns)
+		if opts.Excel {
+			for i := range record {
+				record[i] = excelText(record[i])
+			}
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write transaction record: %w", err)
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// recordWriter writes delimited records
+type recordWriter interface {
+	Write(record []string) error
+	Flush()
+	Error() error
+}
+
+// newRecordWriter returns a writer of records laid out as opts selects
+func newRecordWriter(w io.Writer, opts CSVOptions) recordWriter {
+	delimiter := opts.Delimiter
+	if delimiter == 0 {
+		delimiter = ','
+	}
+	if opts.QuoteAll {
+		return &quotingWriter{w: bufio.NewWriter(w), delimiter: delimiter, crlf: opts.Excel}
+	}
+	writer := csv.NewWriter(w)
+	writer.Comma = delimiter
+	writer.UseCRLF = opts.Excel
+	return writer
+}
+
+// excelSignificantDigits is the precision of Excel numbers
+const excelSignificantDigits = 15
+
+// excelText wraps field in a ="..." formula when Excel would otherwise
+// read it as a number and change it: rounding it beyond 15 significant
+// digits, or dropping leading zeros
+func excelText(field string) string {
+	digits := strings.TrimPrefix(field, "-")
+	if digits == "" || strings.Count(digits, ".") > 1 || strings.Trim(digits, "0123456789.") != "" || digits == "." {
+		return field
+	}
+	integer, _, _ := strings.Cut(digits, ".")
+	significant := len(strings.TrimLeft(strings.ReplaceAll(digits, ".", ""), "0"))
+	if significant > excelSignificantDigits || (len(integer) > 1 && integer[0] == '0') {
+		return `="` + field + `"`
+	}
+	return field
+}
+
+// quotingWriter writes records with every field quoted, which csv.Writer
+// cannot do
+type quotingWriter struct {
+	w         *bufio.Writer
+	delimiter rune
+	crlf      bool
+	err       error
+}
+
+func (q *quotingWriter) Write(record []string) error {
+	for i, field := range record {
+		if i > 0 {
+			q.w.WriteRune(q.delimiter)
+		}
+		q.w.WriteString(`"` + strings.ReplaceAll(field, `"`, `""`) + `"`)
+	}
+	newline := "\n"
+	if q.crlf {
+		newline = "\r\n"
+	}
+	_, err := q.w.WriteString(newline)
+	if err != nil && q.err == nil {
+		q.err = err
+	}
+	return err
+}
+
+func (q *quotingWriter) Flush() {
+	if err := q.w.Flush(); err != nil && q.err == nil {
+		q.err = err
+	}
+}
+
+func (q *quotingWriter) Error() error {
+	return q.err
+}
@@ -0,0 +1,188 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteCSV(t *testing.T) {
+	// Create temporary directory for test output
+	tempDir, err := os.MkdirTemp("", "csv-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	// Create test transactions
+	transactions := []models.Transaction{
+		{
+			Hash:              "0x123abc",
+			Timestamp:         time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC),
+			From:              "0xsender1",
+			To:                "0xreceiver1",
+			Type:              models.TypeEthTransfer,
+			Value:             "1.500000000000000000",
+			GasFee:            "0.000210000000000000",
+		},
+		{
+			Hash:              "0x456def",
+			Timestamp:         time.Date(2023, 1, 2, 12, 0, 0, 0, time.UTC),
+			From:              "0xsender2",
+			To:                "0xreceiver2",
+			Type:              models.TypeERC20Transfer,
+			AssetContractAddr: "0xtoken",
+			AssetSymbol:       "USDC",
+			Value:             "100.000000",
+			GasFee:            "0.000650000000000000",
+		},
+		{
+			Hash:              "0x789ghi",
+			Timestamp:         time.Date(2023, 1, 3, 12, 0, 0, 0, time.UTC),
+			From:              "0xsender3",
+			To:                "0xreceiver3",
+			Type:              models.TypeERC721Transfer,
+			AssetContractAddr: "0xnft",
+			AssetSymbol:       "BAYC",
+			TokenID:           "1234",
+			Value:             "1",
+			GasFee:            "0.001200000000000000",
+		},
+	}
+
+	// Generate file path
+	outputPath := tempDir + "/transactions_export.csv"
+	
+	// Export transactions
+	err = WriteCSV(transactions, outputPath)
+	assert.NoError(t, err)
+	assert.FileExists(t, outputPath)
+
+	// Verify CSV content
+	file, err := os.Open(outputPath)
+	assert.NoError(t, err)
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	records, err := reader.ReadAll()
+	assert.NoError(t, err)
+
+	// Check header
+	assert.Equal(t, models.CSVHeaders(), records[0])
+	
+	// Check number of rows (header + 3 transactions)
+	assert.Len(t, records, 4)
+	
+	// Check specific record values
+	assert.Equal(t, "0x123abc", records[1][0]) // Hash of first transaction
+	assert.Equal(t, "0xsender1", records[1][3]) // From address of first transaction
+	assert.Equal(t, "USDC", records[2][7]) // Token symbol of second transaction
+	assert.Equal(t, "1234", records[3][8]) // Token ID of third transaction
+}
+
+func TestWriteCSV_EmptyList(t *testing.T) {
+	// Create temporary directory for test output
+	tempDir, err := os.MkdirTemp("", "csv-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	// Generate file path
+	outputPath := tempDir + "/empty_transactions.csv"
+	
+	// Test with empty transaction list
+	err = WriteCSV([]models.Transaction{}, outputPath)
+	assert.NoError(t, err)
+	assert.FileExists(t, outputPath)
+
+	// Verify CSV has only header row
+	file, err := os.Open(outputPath)
+	assert.NoError(t, err)
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	records, err := reader.ReadAll()
+	assert.NoError(t, err)
+	assert.Len(t, records, 1) // Only header row
+	assert.Equal(t, models.CSVHeaders(), records[0])
+}
+
+func TestWriteCSV_InvalidPath(t *testing.T) {
+	// Test with invalid output path that requires non-existent directories
+	err := WriteCSV([]models.Transaction{}, "/dev/null/impossible/path.csv")
+	assert.Error(t, err)
+}
+
+func TestWriteCSVWithOptions_ExtraColumns(t *testing.T) {
+	outputPath := t.TempDir() + "/extra_columns.csv"
+
+	columns := append([]models.Column{}, models.DefaultColumns...)
+	extra, err := models.ParseColumnKeys("nonce,gas_limit,tx_index")
+	assert.NoError(t, err)
+	columns = append(columns, extra...)
+
+	transactions := []models.Transaction{
+		{Hash: "0xabc", Nonce: "5", GasLimit: "21000", TransactionIndex: "12"},
+	}
+	err = WriteCSVWithOptions(transactions, outputPath, CSVOptions{Columns: columns})
+	assert.NoError(t, err)
+
+	file, err := os.Open(outputPath)
+	assert.NoError(t, err)
+	defer file.Close()
+
+	records, err := csv.NewReader(file).ReadAll()
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, []string{"Nonce", "Gas Limit", "Transaction Index"}, records[0][len(records[0])-3:])
+	assert.Equal(t, []string{"5", "21000", "12"}, records[1][len(records[1])-3:])
+}
+
+func TestWriteCSVWithOptions_Layout(t *testing.T) {
+	path := t.TempDir() + "/transactions.tsv"
+	transactions := []models.Transaction{{Hash: "0x1", Value: "1.5", AssetSymbol: `Say "hi"`}}
+	columns, _ := models.ParseColumnKeys("hash,symbol,value")
+
+	delimiter, err := ParseDelimiter("tab")
+	assert.NoError(t, err)
+	opts := CSVOptions{Columns: columns, Delimiter: delimiter, NoHeader: true}
+	assert.Equal(t, ".tsv", opts.Extension())
+	assert.NoError(t, WriteCSVWithOptions(transactions, path, opts))
+	content, _ := os.ReadFile(path)
+	assert.Equal(t, "0x1\t\"Say \"\"hi\"\"\"\t1.5\n", string(content))
+
+	opts = CSVOptions{Columns: columns, Delimiter: ';', QuoteAll: true}
+	assert.NoError(t, WriteCSVWithOptions(transactions, path, opts))
+	content, _ = os.ReadFile(path)
+	assert.Equal(t, "\"Transaction Hash\";\"Asset Symbol / Name\";\"Value / Amount\"\n\"0x1\";\"Say \"\"hi\"\"\";\"1.5\"\n", string(content))
+
+	_, err = ParseDelimiter(";;")
+	assert.Error(t, err)
+}
+
+func TestWriteCSVTo(t *testing.T) {
+	var buf bytes.Buffer
+	columns, _ := models.ParseColumnKeys("hash,value")
+	assert.NoError(t, WriteCSVTo(&buf, []models.Transaction{{Hash: "0x1", Value: "1.5"}}, CSVOptions{Columns: columns}))
+	assert.Equal(t, "Transaction Hash,Value / Amount\n0x1,1.5\n", buf.String())
+}
+
+func TestWriteCSVWithOptions_Excel(t *testing.T) {
+	path := t.TempDir() + "/transactions.csv"
+	transactions := []models.Transaction{{Hash: "0x1", TokenID: "115792089237316195423570985008687907853269984665640564039457584007913129639935", Value: "1.500000000000000000", GasFee: "0.5", Nonce: "007"}}
+	columns, _ := models.ParseColumnKeys("hash,token_id,value,gas_fee,nonce")
+
+	assert.NoError(t, WriteCSVWithOptions(transactions, path, CSVOptions{Columns: columns, Excel: true}))
+	content, _ := os.ReadFile(path)
+	assert.Equal(t, "\xef\xbb\xbfTransaction Hash,Token ID,Value / Amount,Gas Fee (ETH),Nonce\r\n"+
+		`0x1,"=""115792089237316195423570985008687907853269984665640564039457584007913129639935""","=""1.500000000000000000""",0.5,"=""007"""`+"\r\n", string(content))
+
+	// The guarded file reads back unchanged
+	file, _ := os.Open(path)
+	defer file.Close()
+	read, _, err := ReadCSV(file)
+	assert.NoError(t, err)
+	assert.Equal(t, transactions, read)
+}
@@ -0,0 +1,2 @@
+// Package export writes models.Transaction slices to output files.
+package export
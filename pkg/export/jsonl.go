@@ -0,0 +1,127 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+)
+
+// ReadJSONL reads rows written one JSON object per line, as the ndjson
+// formats print them. Blank lines are skipped.
+func ReadJSONL(r io.Reader) ([]models.Transaction, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	var transactions []models.Transaction
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var tx models.Transaction
+		if err := json.Unmarshal([]byte(text), &tx); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		transactions = append(transactions, tx)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read JSON lines: %w", err)
+	}
+	return transactions, nil
+}
+
+// ReadJSON reads rows written as a JSON array, or as an object holding
+// them in a transactions array, as the HTTP API serves pages of rows
+func ReadJSON(r io.Reader) ([]models.Transaction, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JSON: %w", err)
+	}
+	content = bytes.TrimSpace(content)
+	var transactions []models.Transaction
+	if bytes.HasPrefix(content, []byte("[")) {
+		err = json.Unmarshal(content, &transactions)
+	} else {
+		var page struct {
+			Transactions []models.Transaction `json:"transactions"`
+		}
+		if err = json.Unmarshal(content, &page); err == nil && page.Transactions == nil {
+			err = errors.New("no transactions array")
+		}
+		transactions = page.Transactions
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode JSON: %w", err)
+	}
+	return transactions, nil
+}
+
+// IsJSONL reports whether filePath names a JSON Lines file, by its
+// .jsonl or .ndjson extension
+func IsJSONL(filePath string) bool {
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".jsonl", ".ndjson":
+		return true
+	}
+	return false
+}
+
+// ReadFile reads an export from filePath by its extension: JSON Lines for
+// .jsonl and .ndjson, JSON for .json, tab-separated values for .tsv and
+// comma-separated values otherwise. JSON carries no header, so the columns
+// of JSON files are the default ones and the optional ones any row fills.
+func ReadFile(filePath string) ([]models.Transaction, []models.Column, error) {
+	ext := strings.ToLower(filepath.Ext(filePath))
+	if !IsJSONL(filePath) && ext != ".json" {
+		var opts CSVOptions
+		if ext == ".tsv" {
+			opts.Delimiter = '\t'
+		}
+		return ReadCSVFileWithOptions(filePath, opts)
+	}
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open JSON file: %w", err)
+	}
+	defer file.Close()
+	read := ReadJSONL
+	if ext == ".json" {
+		read = ReadJSON
+	}
+	transactions, err := read(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	return transactions, filledColumns(transactions), nil
+}
+
+// filledColumns returns the default columns and the optional columns that
+// hold a value in any of transactions. Columns derived from the default
+// ones, such as the Unix timestamp, only count when they say more than the
+// default columns do.
+func filledColumns(transactions []models.Transaction) []models.Column {
+	columns := append([]models.Column{}, models.DefaultColumns...)
+	bases := make([]models.Transaction, len(transactions))
+	for i := range transactions {
+		for _, col := range models.DefaultColumns {
+			// Values written by Value always parse back
+			_ = col.Set(&bases[i], col.Value(&transactions[i]))
+		}
+	}
+	for _, col := range models.OptionalColumns {
+		for i := range transactions {
+			if value := col.Value(&transactions[i]); value != "" && value != col.Value(&bases[i]) {
+				columns = append(columns, col)
+				break
+			}
+		}
+	}
+	return columns
+}
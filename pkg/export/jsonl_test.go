@@ -0,0 +1,81 @@
+package export
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadJSONL(t *testing.T) {
+	rows := []models.Transaction{
+		{Hash: "0xabc", BlockNumber: 12, Timestamp: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), Type: models.TypeEthTransfer, Value: "1.5"},
+		{Hash: "0xdef", BlockNumber: 13, Type: models.TypeERC20Transfer, AssetSymbol: "USDC", Nonce: "7"},
+	}
+	var buf bytes.Buffer
+	assert.NoError(t, NewStreamWriter(&buf, StreamNDJSON).Write(rows))
+	buf.WriteString("\n")
+
+	txs, err := ReadJSONL(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, rows, txs, "rows printed as ndjson read back unchanged")
+
+	_, err = ReadJSONL(strings.NewReader("{\"hash\":\"0xabc\"}\nnot json\n"))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "line 2")
+	}
+}
+
+func TestReadJSON(t *testing.T) {
+	txs, err := ReadJSON(strings.NewReader(`[{"hash":"0xabc","block_number":12}]`))
+	assert.NoError(t, err)
+	if assert.Len(t, txs, 1) {
+		assert.Equal(t, int64(12), txs[0].BlockNumber)
+	}
+
+	txs, err = ReadJSON(strings.NewReader(`{"address":"0x1","total":2,"transactions":[{"hash":"0xabc"},{"hash":"0xdef"}]}`))
+	assert.NoError(t, err)
+	assert.Len(t, txs, 2, "pages of the HTTP API are read")
+
+	_, err = ReadJSON(strings.NewReader(`{"error":"not found"}`))
+	assert.Error(t, err)
+}
+
+func TestReadFile(t *testing.T) {
+	dir := t.TempDir()
+	rows := []models.Transaction{{Hash: "0xabc", BlockNumber: 12, Timestamp: time.Unix(1700000000, 0).UTC(), Type: models.TypeEthTransfer, Value: "1", Nonce: "3"}}
+
+	jsonl := filepath.Join(dir, "rows.ndjson")
+	var buf bytes.Buffer
+	assert.NoError(t, NewStreamWriter(&buf, StreamNDJSON).Write(rows))
+	assert.NoError(t, os.WriteFile(jsonl, buf.Bytes(), 0644))
+	txs, cols, err := ReadFile(jsonl)
+	assert.NoError(t, err)
+	assert.Equal(t, rows, txs)
+	assert.Len(t, cols, len(models.DefaultColumns)+1)
+	assert.Equal(t, "nonce", cols[len(cols)-1].Key, "optional columns with values are included")
+
+	page := filepath.Join(dir, "rows.json")
+	assert.NoError(t, os.WriteFile(page, []byte(`{"transactions":[{"hash":"0xabc","block_number":12}]}`), 0644))
+	txs, cols, err = ReadFile(page)
+	assert.NoError(t, err)
+	assert.Len(t, txs, 1)
+	assert.Len(t, cols, len(models.DefaultColumns))
+
+	tsv := filepath.Join(dir, "rows.tsv")
+	assert.NoError(t, WriteCSVWithOptions(rows, tsv, CSVOptions{Delimiter: '\t'}))
+	txs, cols, err = ReadFile(tsv)
+	assert.NoError(t, err)
+	if assert.Len(t, txs, 1) {
+		assert.Equal(t, "0xabc", txs[0].Hash)
+	}
+	assert.Equal(t, len(models.DefaultColumns), len(cols))
+
+	assert.True(t, IsJSONL("a/b.JSONL"))
+	assert.False(t, IsJSONL("a/b.csv"))
+}
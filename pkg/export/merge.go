@@ -0,0 +1,25 @@
+package export
+
+import (
+	"sort"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+)
+
+// Merge concatenates the rows of several exports and sorts them by time,
+// then by block. Block numbers of different chains are not comparable, so
+// time comes first; the stable sort keeps the rows of a block in file
+// order. Duplicates are left for dedupe.Apply to drop.
+func Merge(sets ...[]models.Transaction) []models.Transaction {
+	var merged []models.Transaction
+	for _, set := range sets {
+		merged = append(merged, set...)
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		if !merged[i].Timestamp.Equal(merged[j].Timestamp) {
+			return merged[i].Timestamp.Before(merged[j].Timestamp)
+		}
+		return merged[i].BlockNumber < merged[j].BlockNumber
+	})
+	return merged
+}
@@ -0,0 +1,29 @@
+package export
+
+import (
+	"testing"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerge(t *testing.T) {
+	at := time.Unix(1700000000, 0)
+	first := []models.Transaction{
+		{Hash: "0xb", BlockNumber: 20, Timestamp: at.Add(time.Minute)},
+		{Hash: "0xc", BlockNumber: 20, Timestamp: at.Add(time.Minute)},
+	}
+	second := []models.Transaction{
+		{Hash: "0xa", BlockNumber: 10, Timestamp: at},
+		{Hash: "0xd", BlockNumber: 5, Timestamp: at.Add(time.Hour)},
+		{Hash: "0xb", BlockNumber: 20, Timestamp: at.Add(time.Minute)},
+	}
+
+	var hashes []string
+	for _, tx := range Merge(first, second) {
+		hashes = append(hashes, tx.Hash)
+	}
+	assert.Equal(t, []string{"0xa", "0xb", "0xc", "0xb", "0xd"}, hashes, "rows of a block keep their file order")
+	assert.Empty(t, Merge())
+}
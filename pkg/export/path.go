@@ -0,0 +1,31 @@
+package export
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// AvailablePath returns path when neither it nor path+suffix exists, and
+// otherwise path with the time now inserted before its extension, such as
+// 0xabc_tx_history_20240501T120000.csv, so an earlier export is never
+// overwritten. suffix is the extension a protected output gains, if any.
+func AvailablePath(path, suffix string, now time.Time) string {
+	if !exists(path) && !exists(path+suffix) {
+		return path
+	}
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext) + "_" + now.UTC().Format("20060102T150405")
+	candidate := base + ext
+	for n := 2; exists(candidate) || exists(candidate+suffix); n++ {
+		candidate = fmt.Sprintf("%s_%d%s", base, n, ext)
+	}
+	return candidate
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,35 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAvailablePath(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "0xabc_tx_history.csv")
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, path, AvailablePath(path, "", now))
+
+	assert.NoError(t, os.WriteFile(path, nil, 0644))
+	stamped := filepath.Join(dir, "0xabc_tx_history_20240501T120000.csv")
+	assert.Equal(t, stamped, AvailablePath(path, "", now))
+
+	assert.NoError(t, os.WriteFile(stamped, nil, 0644))
+	assert.Equal(t, filepath.Join(dir, "0xabc_tx_history_20240501T120000_2.csv"), AvailablePath(path, "", now))
+}
+
+func TestAvailablePath_ProtectedOutput(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "0xabc_tx_history.csv")
+	// An encrypted export replaces the plaintext file
+	assert.NoError(t, os.WriteFile(path+".age", nil, 0644))
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, filepath.Join(dir, "0xabc_tx_history_20240501T120000.csv"), AvailablePath(path, ".age", now))
+}
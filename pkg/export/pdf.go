@@ -0,0 +1,91 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// PDF page layout: landscape A4 in points, with a monospaced font small
+// enough for wide report tables
+const (
+	pdfPageWidth  = 842
+	pdfPageHeight = 595
+	pdfMargin     = 36
+	pdfFontSize   = 7
+	pdfLeading    = 9
+)
+
+// pdfLinesPerPage is the number of text lines that fit on a page
+const pdfLinesPerPage = (pdfPageHeight - 2*pdfMargin) / pdfLeading
+
+// WriteTextPDF writes sections of plain text as a PDF document in a
+// monospaced font, so column layouts stay aligned. Each section starts on a
+// new page and continues on further pages when it is too long. Characters
+// outside printable ASCII are replaced with '?'.
+func WriteTextPDF(w io.Writer, sections [][]string) error {
+	var pages [][]string
+	for _, section := range sections {
+		for len(section) > pdfLinesPerPage {
+			pages = append(pages, section[:pdfLinesPerPage])
+			section = section[pdfLinesPerPage:]
+		}
+		pages = append(pages, section)
+	}
+
+	// Objects 1 to 3 are the catalog, the page tree and the font; each page
+	// is followed by its content stream
+	objects := make([]string, 3, 3+2*len(pages))
+	kids := make([]string, len(pages))
+	for i, page := range pages {
+		pageID := 4 + 2*i
+		kids[i] = fmt.Sprintf("%d 0 R", pageID)
+		var content bytes.Buffer
+		fmt.Fprintf(&content, "BT\n/F1 %d Tf\n%d TL\n%d %d Td\n", pdfFontSize, pdfLeading, pdfMargin, pdfPageHeight-pdfMargin-pdfFontSize)
+		for _, line := range page {
+			fmt.Fprintf(&content, "(%s) Tj T*\n", pdfEscape(line))
+		}
+		content.WriteString("ET")
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>", pdfPageWidth, pdfPageHeight, pageID+1),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.String()),
+		)
+	}
+	objects[0] = "<< /Type /Catalog /Pages 2 0 R >>"
+	objects[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages))
+	objects[2] = "<< /Type /Font /Subtype /Type1 /BaseFont /Courier >>"
+
+	var doc bytes.Buffer
+	doc.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = doc.Len()
+		fmt.Fprintf(&doc, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := doc.Len()
+	fmt.Fprintf(&doc, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&doc, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&doc, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	_, err := w.Write(doc.Bytes())
+	return err
+}
+
+// pdfEscape escapes a line for a PDF string literal
+func pdfEscape(line string) string {
+	var b strings.Builder
+	for _, r := range line {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		case r < ' ' || r > '~':
+			b.WriteByte('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
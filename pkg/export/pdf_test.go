@@ -0,0 +1,36 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteTextPDF(t *testing.T) {
+	long := make([]string, pdfLinesPerPage+1)
+	for i := range long {
+		long[i] = fmt.Sprintf("line %d", i)
+	}
+	var buf bytes.Buffer
+	assert.NoError(t, WriteTextPDF(&buf, [][]string{{"Statement (Q1)", `C:\ café`}, long}))
+	doc := buf.Bytes()
+
+	assert.True(t, bytes.HasPrefix(doc, []byte("%PDF-1.4\n")))
+	assert.True(t, bytes.HasSuffix(doc, []byte("%%EOF\n")))
+	assert.Contains(t, buf.String(), "/Count 3 >>", "a long section continues on a new page")
+	assert.Contains(t, buf.String(), `(Statement \(Q1\)) Tj T*`)
+	assert.Contains(t, buf.String(), `(C:\\ caf?) Tj T*`)
+
+	// Every cross-reference entry points at its object
+	start := regexp.MustCompile(`startxref\n(\d+)`).FindSubmatch(doc)
+	xref, _ := strconv.Atoi(string(start[1]))
+	assert.True(t, bytes.HasPrefix(doc[xref:], []byte("xref\n")))
+	for i, entry := range regexp.MustCompile(`(\d{10}) 00000 n`).FindAllSubmatch(doc, -1) {
+		offset, _ := strconv.Atoi(string(entry[1]))
+		assert.True(t, bytes.HasPrefix(doc[offset:], []byte(fmt.Sprintf("%d 0 obj\n", i+1))), "object %d", i+1)
+	}
+}
@@ -0,0 +1,140 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+)
+
+// PreviewOptions configures WritePreview
+type PreviewOptions struct {
+	// Rows is the number of rows printed from each end of the export
+	Rows int
+	// Address, when set, colors rows it received green and rows it sent red
+	Address string
+	// Color adds ANSI colors, for terminals
+	Color bool
+}
+
+// ANSI escapes used by the preview
+const (
+	ansiReset = "\033[0m"
+	ansiBold  = "\033[1m"
+	ansiDim   = "\033[2m"
+	ansiRed   = "\033[31m"
+	ansiGreen = "\033[32m"
+)
+
+var previewHeader = []string{"TIME", "BLOCK", "TYPE", "FROM", "TO", "VALUE", "ASSET", "HASH"}
+
+// WritePreview prints the first and last opts.Rows transactions as an
+// aligned table, so the rows an export would contain can be checked
+// without opening it. Addresses and hashes are shortened to fit a
+// terminal.
+func WritePreview(w io.Writer, transactions []models.Transaction, opts PreviewOptions) error {
+	head, tail := transactions, []models.Transaction(nil)
+	if opts.Rows > 0 && len(transactions) > 2*opts.Rows {
+		head, tail = transactions[:opts.Rows], transactions[len(transactions)-opts.Rows:]
+	}
+
+	cells := [][]string{previewHeader}
+	for _, part := range [][]models.Transaction{head, tail} {
+		for i := range part {
+			cells = append(cells, previewCells(&part[i]))
+		}
+	}
+	widths := make([]int, len(previewHeader))
+	for _, row := range cells {
+		for i, cell := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+
+	style := func(s, code string) string {
+		if !opts.Color || code == "" {
+			return s
+		}
+		return code + s + ansiReset
+	}
+	line := func(row []string) string {
+		var b strings.Builder
+		for i, cell := range row {
+			b.WriteString(cell)
+			if i < len(row)-1 {
+				b.WriteString(strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)+2))
+			}
+		}
+		return b.String()
+	}
+
+	if _, err := fmt.Fprintln(w, style(line(previewHeader), ansiBold)); err != nil {
+		return err
+	}
+	printRows := func(part []models.Transaction, offset int) error {
+		for i := range part {
+			tx := &part[i]
+			if _, err := fmt.Fprintln(w, style(line(cells[offset+i]), previewColor(tx, opts.Address))); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := printRows(head, 1); err != nil {
+		return err
+	}
+	if len(tail) > 0 {
+		omitted := len(transactions) - len(head) - len(tail)
+		if _, err := fmt.Fprintln(w, style(fmt.Sprintf("… %d more rows …", omitted), ansiDim)); err != nil {
+			return err
+		}
+		if err := printRows(tail, 1+len(head)); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "%d rows\n", len(transactions))
+	return err
+}
+
+// previewCells returns the table cells of a row
+func previewCells(tx *models.Transaction) []string {
+	asset := tx.AssetSymbol
+	if asset == "" {
+		asset = "ETH"
+	}
+	if tx.Failed() {
+		asset += " (failed)"
+	}
+	return []string{
+		tx.Timestamp.UTC().Format(time.DateTime), fmt.Sprint(tx.BlockNumber), string(tx.Type),
+		shorten(tx.From), shorten(tx.To), tx.Value, asset, shorten(tx.Hash),
+	}
+}
+
+// previewColor returns the escape a row is printed in: dim when it failed,
+// otherwise green or red when address received or sent it
+func previewColor(tx *models.Transaction, address string) string {
+	switch {
+	case tx.Failed():
+		return ansiDim
+	case address == "" || strings.EqualFold(tx.From, tx.To):
+		return ""
+	case strings.EqualFold(tx.To, address):
+		return ansiGreen
+	case strings.EqualFold(tx.From, address):
+		return ansiRed
+	}
+	return ""
+}
+
+// shorten abbreviates a hex address or hash to its first and last
+// characters
+func shorten(s string) string {
+	if len(s) <= 14 {
+		return s
+	}
+	return s[:8] + "…" + s[len(s)-4:]
+}
@@ -0,0 +1,77 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+const previewWallet = "0x1111111111111111111111111111111111111111"
+
+func previewRows(n int) []models.Transaction {
+	var txs []models.Transaction
+	for i := 0; i < n; i++ {
+		tx := models.Transaction{
+			Hash:        fmt.Sprintf("0x%064x", i+1),
+			BlockNumber: int64(100 + i),
+			Timestamp:   time.Unix(1700000000+int64(i)*60, 0),
+			Type:        models.TypeEthTransfer,
+			From:        previewWallet,
+			To:          "0x2222222222222222222222222222222222222222",
+			Value:       fmt.Sprint(i + 1),
+		}
+		if i%2 == 1 {
+			tx.From, tx.To = tx.To, tx.From
+		}
+		txs = append(txs, tx)
+	}
+	return txs
+}
+
+func TestWritePreview(t *testing.T) {
+	var out bytes.Buffer
+	assert.NoError(t, WritePreview(&out, previewRows(7), PreviewOptions{Rows: 2}))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if assert.Len(t, lines, 7) {
+		assert.Equal(t, "TIME                 BLOCK  TYPE          FROM           TO             VALUE  ASSET  HASH", lines[0])
+		assert.Equal(t, "2023-11-14 22:13:20  100    ETH_TRANSFER  0x111111…1111  0x222222…2222  1      ETH    0x000000…0001", lines[1])
+		assert.Contains(t, lines[2], "  101  ")
+		assert.Equal(t, "… 3 more rows …", lines[3])
+		assert.Contains(t, lines[4], "  105  ")
+		assert.Contains(t, lines[5], "  106  ")
+		assert.Equal(t, "7 rows", lines[6])
+	}
+	assert.NotContains(t, out.String(), "\033[", "no colors unless asked")
+}
+
+func TestWritePreviewShort(t *testing.T) {
+	var out bytes.Buffer
+	assert.NoError(t, WritePreview(&out, previewRows(4), PreviewOptions{Rows: 2}))
+	assert.NotContains(t, out.String(), "more rows", "every row fits in the head and tail")
+	assert.Len(t, strings.Split(strings.TrimSpace(out.String()), "\n"), 6)
+
+	out.Reset()
+	assert.NoError(t, WritePreview(&out, nil, PreviewOptions{Rows: 2}))
+	assert.Equal(t, "0 rows", strings.Split(strings.TrimSpace(out.String()), "\n")[1])
+}
+
+func TestWritePreviewColor(t *testing.T) {
+	txs := previewRows(3)
+	txs[2].Status = models.StatusFailed
+	var out bytes.Buffer
+	assert.NoError(t, WritePreview(&out, txs, PreviewOptions{Rows: 5, Address: previewWallet, Color: true}))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if assert.Len(t, lines, 5) {
+		assert.True(t, strings.HasPrefix(lines[0], ansiBold+"TIME"))
+		assert.True(t, strings.HasPrefix(lines[1], ansiRed), "the wallet sent the first row")
+		assert.True(t, strings.HasPrefix(lines[2], ansiGreen), "the wallet received the second row")
+		assert.True(t, strings.HasPrefix(lines[3], ansiDim), "failed rows are dimmed")
+		assert.Contains(t, lines[3], "ETH (failed)")
+		assert.True(t, strings.HasSuffix(lines[1], ansiReset))
+	}
+}
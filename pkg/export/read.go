@@ -0,0 +1,98 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+)
+
+// ReadCSV reads an export written by any schema version. It returns the
+// transactions and the registered columns found in the file, in file order.
+func ReadCSV(r io.Reader) ([]models.Transaction, []models.Column, error) {
+	return ReadCSVWithOptions(r, CSVOptions{})
+}
+
+// ReadCSVWithOptions reads an export written with opts. Columns of opts
+// replace the registered columns of the same key, so values formatted for
+// example in another timezone parse back. A file written with NoHeader is
+// read as having the columns of opts.
+func ReadCSVWithOptions(r io.Reader, opts CSVOptions) ([]models.Transaction, []models.Column, error) {
+	reader := csv.NewReader(r)
+	if opts.Delimiter != 0 {
+		reader.Comma = opts.Delimiter
+	}
+
+	columns := opts.Columns
+	if len(columns) == 0 {
+		columns = models.DefaultColumns
+	}
+	line := 1
+	if !opts.NoHeader {
+		headers, err := reader.Read()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read CSV header: %w", err)
+		}
+		if len(headers) > 0 {
+			headers[0] = strings.TrimPrefix(headers[0], utf8BOM)
+		}
+		if columns, err = models.ColumnsForHeaders(headers); err != nil {
+			return nil, nil, err
+		}
+		line = 2
+	}
+	for i := range columns {
+		for _, col := range opts.Columns {
+			if col.Key == columns[i].Key {
+				columns[i] = col
+			}
+		}
+	}
+
+	var transactions []models.Transaction
+	for ; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read CSV record: %w", err)
+		}
+		if opts.NoHeader && line == 1 {
+			record[0] = strings.TrimPrefix(record[0], utf8BOM)
+		}
+
+		var tx models.Transaction
+		for i, col := range columns {
+			// Undo the text formulas of -excel-compat
+			value := record[i]
+			if len(value) >= 3 && strings.HasPrefix(value, `="`) && strings.HasSuffix(value, `"`) {
+				value = value[2 : len(value)-1]
+			}
+			if err := col.Set(&tx, value); err != nil {
+				return nil, nil, fmt.Errorf("line %d: invalid %s: %w", line, col.Header, err)
+			}
+		}
+		transactions = append(transactions, tx)
+	}
+
+	return transactions, columns, nil
+}
+
+// ReadCSVFile reads an export from filePath
+func ReadCSVFile(filePath string) ([]models.Transaction, []models.Column, error) {
+	return ReadCSVFileWithOptions(filePath, CSVOptions{})
+}
+
+// ReadCSVFileWithOptions reads an export written with opts from filePath
+func ReadCSVFileWithOptions(filePath string, opts CSVOptions) ([]models.Transaction, []models.Column, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open CSV file: %w", err)
+	}
+	defer file.Close()
+	return ReadCSVWithOptions(file, opts)
+}
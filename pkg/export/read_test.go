@@ -0,0 +1,47 @@
+package export
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadCSV_OlderSchema(t *testing.T) {
+	input := "Transaction Hash,Date & Time,From Address,To Address,Transaction Type,Asset Contract Address,Asset Symbol / Name,Token ID,Value / Amount,Gas Fee (ETH)\n" +
+		"0xabc,2021-08-27T12:00:00Z,0xfrom,0xto,ETH_TRANSFER,,ETH,,1.5,0.0021\n"
+
+	txs, cols, err := ReadCSV(strings.NewReader(input))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, models.DetectSchemaVersion(cols))
+	assert.Len(t, txs, 1)
+	assert.Equal(t, "0xabc", txs[0].Hash)
+	assert.Equal(t, time.Date(2021, 8, 27, 12, 0, 0, 0, time.UTC), txs[0].Timestamp)
+	assert.Equal(t, models.TypeEthTransfer, txs[0].Type)
+	assert.Equal(t, "1.5", txs[0].Value)
+}
+
+func TestReadCSV_InvalidValue(t *testing.T) {
+	input := "Transaction Hash,Block Number\n0xabc,12\n0xdef,twelve\n"
+
+	_, _, err := ReadCSV(strings.NewReader(input))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "line 3")
+	assert.Contains(t, err.Error(), "Block Number")
+}
+
+func TestReadCSVWithOptions(t *testing.T) {
+	opts := CSVOptions{Columns: []models.Column{models.TimestampColumn(time.UTC, time.DateTime)}}
+	input := "Transaction Hash,Date & Time\n0xabc,2021-08-27 12:00:00\n"
+
+	_, _, err := ReadCSV(strings.NewReader(input))
+	assert.Error(t, err, "the default column expects RFC 3339")
+
+	txs, _, err := ReadCSVWithOptions(strings.NewReader(input), opts)
+	assert.NoError(t, err)
+	if assert.Len(t, txs, 1) {
+		assert.Equal(t, time.Date(2021, 8, 27, 12, 0, 0, 0, time.UTC), txs[0].Timestamp)
+	}
+}
@@ -0,0 +1,55 @@
+package export
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+)
+
+// Period is the span of time covered by each file of a split export
+type Period string
+
+const (
+	// PeriodNone writes a single file
+	PeriodNone  Period = ""
+	PeriodMonth Period = "month"
+	PeriodYear  Period = "year"
+)
+
+// ParsePeriod validates a -split-by value
+func ParsePeriod(s string) (Period, error) {
+	switch p := Period(s); p {
+	case PeriodNone, PeriodMonth, PeriodYear:
+		return p, nil
+	}
+	return "", fmt.Errorf("unknown period %q (use month or year)", s)
+}
+
+// Key names the period containing t in loc, such as 2023-01 for a month or
+// 2023 for a year
+func (p Period) Key(t time.Time, loc *time.Location) string {
+	t = t.In(loc)
+	if p == PeriodYear {
+		return t.Format("2006")
+	}
+	return t.Format("2006-01")
+}
+
+// Split groups transactions by the period of their timestamp in loc. It
+// returns the period keys in chronological order with the rows of each,
+// keeping the order of the rows within a period.
+func (p Period) Split(transactions []models.Transaction, loc *time.Location) ([]string, map[string][]models.Transaction) {
+	groups := make(map[string][]models.Transaction)
+	var keys []string
+	for _, tx := range transactions {
+		key := p.Key(tx.Timestamp, loc)
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], tx)
+	}
+	sort.Strings(keys)
+	return keys, groups
+}
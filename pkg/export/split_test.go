@@ -0,0 +1,39 @@
+package export
+
+import (
+	"testing"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeriod_Split(t *testing.T) {
+	txs := []models.Transaction{
+		{Hash: "0x1", Timestamp: time.Date(2023, 1, 31, 23, 30, 0, 0, time.UTC)},
+		{Hash: "0x2", Timestamp: time.Date(2022, 12, 5, 0, 0, 0, 0, time.UTC)},
+		{Hash: "0x3", Timestamp: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)},
+	}
+
+	keys, groups := PeriodMonth.Split(txs, time.UTC)
+	assert.Equal(t, []string{"2022-12", "2023-01"}, keys)
+	assert.Equal(t, []string{"0x1", "0x3"}, []string{groups["2023-01"][0].Hash, groups["2023-01"][1].Hash})
+
+	// In Berlin the first row already belongs to February
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	keys, _ = PeriodMonth.Split(txs, berlin)
+	assert.Equal(t, []string{"2022-12", "2023-01", "2023-02"}, keys)
+
+	keys, groups = PeriodYear.Split(txs, time.UTC)
+	assert.Equal(t, []string{"2022", "2023"}, keys)
+	assert.Len(t, groups["2023"], 2)
+}
+
+func TestParsePeriod(t *testing.T) {
+	p, err := ParsePeriod("year")
+	assert.NoError(t, err)
+	assert.Equal(t, PeriodYear, p)
+
+	_, err = ParsePeriod("week")
+	assert.Error(t, err)
+}
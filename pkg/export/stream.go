@@ -0,0 +1,78 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+)
+
+// StreamFormat selects how a StreamWriter prints rows
+type StreamFormat string
+
+const (
+	// StreamTable prints aligned, human-readable columns
+	StreamTable StreamFormat = "table"
+	// StreamNDJSON prints one JSON object per row
+	StreamNDJSON StreamFormat = "ndjson"
+)
+
+// ParseStreamFormat validates a stream format name
+func ParseStreamFormat(s string) (StreamFormat, error) {
+	switch f := StreamFormat(s); f {
+	case StreamTable, StreamNDJSON:
+		return f, nil
+	}
+	return "", fmt.Errorf("unknown format %q (use table or ndjson)", s)
+}
+
+// StreamWriter prints rows as they arrive, for terminals and pipes
+type StreamWriter struct {
+	w          io.Writer
+	format     StreamFormat
+	wroteTitle bool
+}
+
+// NewStreamWriter creates a StreamWriter printing to w
+func NewStreamWriter(w io.Writer, format StreamFormat) *StreamWriter {
+	return &StreamWriter{w: w, format: format}
+}
+
+const tableLayout = "%-20s %-10s %-17s %-42s %-42s %-24s %s\n"
+
+// Write prints transactions, preceded by a header line on the first table write
+func (s *StreamWriter) Write(transactions []models.Transaction) error {
+	if s.format == StreamNDJSON {
+		encoder := json.NewEncoder(s.w)
+		for i := range transactions {
+			if err := encoder.Encode(&transactions[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if !s.wroteTitle {
+		if _, err := fmt.Fprintf(s.w, tableLayout, "TIME", "BLOCK", "TYPE", "FROM", "TO", "VALUE", "ASSET / HASH"); err != nil {
+			return err
+		}
+		s.wroteTitle = true
+	}
+	for _, tx := range transactions {
+		asset := tx.AssetSymbol
+		if asset == "" {
+			asset = "ETH"
+		}
+		if tx.Failed() {
+			asset += " (failed)"
+		}
+		if _, err := fmt.Fprintf(s.w, tableLayout,
+			tx.Timestamp.UTC().Format(time.DateTime), fmt.Sprint(tx.BlockNumber), tx.Type,
+			tx.From, tx.To, tx.Value, asset+" "+tx.Hash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamWriter(t *testing.T) {
+	txs := []models.Transaction{
+		{Hash: "0x1", BlockNumber: 17, Timestamp: time.Unix(1700000000, 0), Type: models.TypeEthTransfer, Value: "1.5"},
+		{Hash: "0x2", BlockNumber: 18, Type: models.TypeERC20Transfer, AssetSymbol: "USDC", Value: "10", Status: models.StatusFailed},
+	}
+
+	var table bytes.Buffer
+	writer := NewStreamWriter(&table, StreamTable)
+	assert.NoError(t, writer.Write(txs[:1]))
+	assert.NoError(t, writer.Write(txs[1:]))
+	lines := strings.Split(strings.TrimSpace(table.String()), "\n")
+	if assert.Len(t, lines, 3, "the header is printed once") {
+		assert.True(t, strings.HasPrefix(lines[0], "TIME"))
+		assert.Contains(t, lines[1], "2023-11-14 22:13:20")
+		assert.Contains(t, lines[1], "ETH 0x1")
+		assert.Contains(t, lines[2], "USDC (failed) 0x2")
+	}
+
+	var ndjson bytes.Buffer
+	assert.NoError(t, NewStreamWriter(&ndjson, StreamNDJSON).Write(txs))
+	lines = strings.Split(strings.TrimSpace(ndjson.String()), "\n")
+	if assert.Len(t, lines, 2) {
+		var decoded models.Transaction
+		assert.NoError(t, json.Unmarshal([]byte(lines[1]), &decoded))
+		assert.Equal(t, "USDC", decoded.AssetSymbol)
+	}
+}
+
+func TestParseStreamFormat(t *testing.T) {
+	format, err := ParseStreamFormat("ndjson")
+	assert.NoError(t, err)
+	assert.Equal(t, StreamNDJSON, format)
+
+	_, err = ParseStreamFormat("xml")
+	assert.Error(t, err)
+}
@@ -0,0 +1,153 @@
+// Package filter selects which transactions make it into an export.
+package filter
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+)
+
+// Func reports whether a transaction should be kept
+type Func func(tx *models.Transaction) bool
+
+// Apply returns the transactions accepted by every filter
+func Apply(transactions []models.Transaction, filters ...Func) []models.Transaction {
+	if len(filters) == 0 {
+		return transactions
+	}
+
+	kept := make([]models.Transaction, 0, len(transactions))
+	for i := range transactions {
+		if accept(&transactions[i], filters) {
+			kept = append(kept, transactions[i])
+		}
+	}
+	return kept
+}
+
+func accept(tx *models.Transaction, filters []Func) bool {
+	for _, f := range filters {
+		if !f(tx) {
+			return false
+		}
+	}
+	return true
+}
+
+// OnlyFailed keeps only transactions that reverted
+func OnlyFailed() Func {
+	return func(tx *models.Transaction) bool {
+		return tx.Failed()
+	}
+}
+
+// ExcludeFailed drops transactions that reverted
+func ExcludeFailed() Func {
+	return func(tx *models.Transaction) bool {
+		return !tx.Failed()
+	}
+}
+
+// Types keeps transactions of the given types
+func Types(types ...models.TransactionType) Func {
+	return func(tx *models.Transaction) bool {
+		for _, t := range types {
+			if tx.Type == t {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Hash keeps the rows of one transaction, or of its internal transfers
+func Hash(hash string) Func {
+	return func(tx *models.Transaction) bool {
+		return strings.EqualFold(tx.Hash, hash) || strings.EqualFold(tx.ParentHash, hash)
+	}
+}
+
+// Counterparty keeps transactions sent from or to address
+func Counterparty(address string) Func {
+	return func(tx *models.Transaction) bool {
+		return strings.EqualFold(tx.From, address) || strings.EqualFold(tx.To, address)
+	}
+}
+
+// Blocks keeps transactions mined in the inclusive block range
+// [start, end]. A negative bound leaves that side open.
+func Blocks(start, end int64) Func {
+	return func(tx *models.Transaction) bool {
+		return (start < 0 || tx.BlockNumber >= start) && (end < 0 || tx.BlockNumber <= end)
+	}
+}
+
+// Between keeps transactions mined within [start, end]. A zero bound leaves
+// that side open.
+func Between(start, end time.Time) Func {
+	return func(tx *models.Transaction) bool {
+		return (start.IsZero() || !tx.Timestamp.Before(start)) && (end.IsZero() || !tx.Timestamp.After(end))
+	}
+}
+
+// Token keeps the rows of one asset, given as its contract address or its
+// symbol
+func Token(token string) Func {
+	return func(tx *models.Transaction) bool {
+		return strings.EqualFold(tx.AssetContractAddr, token) || strings.EqualFold(tx.AssetSymbol, token)
+	}
+}
+
+// Value keeps rows moving at least min and at most max, in the units of
+// their own asset. A nil bound leaves that side open. Rows without a value
+// that parses, such as contract calls, move zero.
+func Value(min, max *big.Rat) Func {
+	return func(tx *models.Transaction) bool {
+		value, ok := new(big.Rat).SetString(tx.Value)
+		if !ok {
+			value = new(big.Rat)
+		}
+		return (min == nil || value.Cmp(min) >= 0) && (max == nil || value.Cmp(max) <= 0)
+	}
+}
+
+// Direction is the side of a row an address is on
+type Direction string
+
+const (
+	// DirectionIn rows were received by the address from another one
+	DirectionIn Direction = "in"
+	// DirectionOut rows were sent by the address to another one
+	DirectionOut Direction = "out"
+	// DirectionSelf rows were sent by the address to itself
+	DirectionSelf Direction = "self"
+)
+
+// ParseDirection validates a direction name
+func ParseDirection(s string) (Direction, error) {
+	switch d := Direction(strings.ToLower(s)); d {
+	case DirectionIn, DirectionOut, DirectionSelf:
+		return d, nil
+	}
+	return "", fmt.Errorf("unknown direction %q (use in, out or self)", s)
+}
+
+// InDirection keeps the rows address is on the given side of. Rows address
+// is neither the sender nor the recipient of are dropped.
+func InDirection(address string, direction Direction) Func {
+	return func(tx *models.Transaction) bool {
+		from, to := strings.EqualFold(tx.From, address), strings.EqualFold(tx.To, address)
+		switch direction {
+		case DirectionIn:
+			return to && !from
+		case DirectionOut:
+			return from && !to
+		case DirectionSelf:
+			return from && to
+		}
+		return false
+	}
+}
@@ -0,0 +1,137 @@
+package filter
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func testTransactions() []models.Transaction {
+	return []models.Transaction{
+		{Hash: "0x1", Status: models.StatusSuccess},
+		{Hash: "0x2", Status: models.StatusFailed},
+		{Hash: "0x3", Status: models.StatusSuccess},
+	}
+}
+
+func hashes(transactions []models.Transaction) []string {
+	var result []string
+	for _, tx := range transactions {
+		result = append(result, tx.Hash)
+	}
+	return result
+}
+
+func TestApply_NoFilters(t *testing.T) {
+	assert.Equal(t, []string{"0x1", "0x2", "0x3"}, hashes(Apply(testTransactions())))
+}
+
+func TestOnlyFailed(t *testing.T) {
+	assert.Equal(t, []string{"0x2"}, hashes(Apply(testTransactions(), OnlyFailed())))
+}
+
+func TestExcludeFailed(t *testing.T) {
+	assert.Equal(t, []string{"0x1", "0x3"}, hashes(Apply(testTransactions(), ExcludeFailed())))
+}
+
+func TestApply_Combined(t *testing.T) {
+	assert.Empty(t, Apply(testTransactions(), OnlyFailed(), ExcludeFailed()))
+}
+
+func TestTypes(t *testing.T) {
+	txs := []models.Transaction{
+		{Hash: "0x1", Type: models.TypeEthTransfer},
+		{Hash: "0x2", Type: models.TypeERC20Transfer},
+		{Hash: "0x3", Type: models.TypeInternalTx},
+	}
+	assert.Equal(t, []string{"0x2", "0x3"}, hashes(Apply(txs, Types(models.TypeERC20Transfer, models.TypeInternalTx))))
+}
+
+func TestHash(t *testing.T) {
+	txs := []models.Transaction{
+		{Hash: "0xAB"},
+		{Hash: "0xcd", ParentHash: "0xab"},
+		{Hash: "0xef"},
+	}
+	assert.Equal(t, []string{"0xAB", "0xcd"}, hashes(Apply(txs, Hash("0xab"))))
+}
+
+func TestCounterparty(t *testing.T) {
+	txs := []models.Transaction{
+		{Hash: "0x1", From: "0xAAA", To: "0xbbb"},
+		{Hash: "0x2", From: "0xbbb", To: "0xaaa"},
+		{Hash: "0x3", From: "0xbbb", To: "0xccc"},
+	}
+	assert.Equal(t, []string{"0x1", "0x2"}, hashes(Apply(txs, Counterparty("0xaaa"))))
+}
+
+func TestBlocks(t *testing.T) {
+	txs := []models.Transaction{
+		{Hash: "0x1", BlockNumber: 10},
+		{Hash: "0x2", BlockNumber: 20},
+		{Hash: "0x3", BlockNumber: 30},
+	}
+	assert.Equal(t, []string{"0x2", "0x3"}, hashes(Apply(txs, Blocks(20, -1))))
+	assert.Equal(t, []string{"0x1", "0x2"}, hashes(Apply(txs, Blocks(-1, 20))))
+}
+
+func TestBetween(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	txs := []models.Transaction{
+		{Hash: "0x1", Timestamp: day(1)},
+		{Hash: "0x2", Timestamp: day(2)},
+		{Hash: "0x3", Timestamp: day(3)},
+	}
+	assert.Equal(t, []string{"0x2", "0x3"}, hashes(Apply(txs, Between(day(2), time.Time{}))))
+	assert.Equal(t, []string{"0x1", "0x2"}, hashes(Apply(txs, Between(time.Time{}, day(2)))))
+}
+
+func TestToken(t *testing.T) {
+	txs := []models.Transaction{
+		{Hash: "0x1", AssetContractAddr: "0xA0b8", AssetSymbol: "USDC"},
+		{Hash: "0x2", AssetContractAddr: "0xdac1", AssetSymbol: "USDT"},
+		{Hash: "0x3"},
+	}
+	assert.Equal(t, []string{"0x1"}, hashes(Apply(txs, Token("usdc"))))
+	assert.Equal(t, []string{"0x2"}, hashes(Apply(txs, Token("0xDAC1"))))
+}
+
+func TestValue(t *testing.T) {
+	txs := []models.Transaction{
+		{Hash: "0x1", Value: "0.0001"},
+		{Hash: "0x2", Value: "0.5"},
+		{Hash: "0x3", Value: "250"},
+		{Hash: "0x4"},
+	}
+	rat := func(s string) *big.Rat {
+		r, _ := new(big.Rat).SetString(s)
+		return r
+	}
+	assert.Equal(t, []string{"0x2", "0x3"}, hashes(Apply(txs, Value(rat("0.01"), nil))))
+	assert.Equal(t, []string{"0x1", "0x2", "0x4"}, hashes(Apply(txs, Value(nil, rat("0.5")))), "bounds are inclusive")
+	assert.Equal(t, []string{"0x2"}, hashes(Apply(txs, Value(rat("0.01"), rat("1")))))
+}
+
+func TestInDirection(t *testing.T) {
+	const address = "0xAbC"
+	txs := []models.Transaction{
+		{Hash: "0x1", From: "0xdef", To: "0xabc"},
+		{Hash: "0x2", From: "0xabc", To: "0xdef"},
+		{Hash: "0x3", From: "0xABC", To: "0xabc"},
+		{Hash: "0x4", From: "0xdef", To: "0x123"},
+	}
+	assert.Equal(t, []string{"0x1"}, hashes(Apply(txs, InDirection(address, DirectionIn))))
+	assert.Equal(t, []string{"0x2"}, hashes(Apply(txs, InDirection(address, DirectionOut))))
+	assert.Equal(t, []string{"0x3"}, hashes(Apply(txs, InDirection(address, DirectionSelf))))
+}
+
+func TestParseDirection(t *testing.T) {
+	d, err := ParseDirection("IN")
+	assert.NoError(t, err)
+	assert.Equal(t, DirectionIn, d)
+	_, err = ParseDirection("sideways")
+	assert.Error(t, err)
+}
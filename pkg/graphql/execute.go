@@ -0,0 +1,341 @@
+package graphql
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Object is a GraphQL object: each field resolves its value from the
+// arguments of the query. A resolver returns an Object, a []Object, a
+// scalar (string, bool, int, int64, float64 or a slice of them) or nil.
+type Object map[string]Resolver
+
+// Resolver resolves a field
+type Resolver func(args Args) (interface{}, error)
+
+// Request is a GraphQL request as sent over HTTP
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// Response is the result of a request. Data is omitted when the request
+// could not be executed at all.
+type Response struct {
+	Data   *OrderedMap `json:"data,omitempty"`
+	Errors []Error     `json:"errors,omitempty"`
+}
+
+// Error is an error of a response
+type Error struct {
+	Message string   `json:"message"`
+	Path    []string `json:"path,omitempty"`
+}
+
+// Execute runs req against root, the object of the Query type. Errors of
+// individual fields are reported with their path, and their value is null.
+func Execute(root Object, req Request) Response {
+	doc, err := Parse(req.Query)
+	if err != nil {
+		return Response{Errors: []Error{{Message: "syntax error: " + err.Error()}}}
+	}
+	op, err := selectOperation(doc, req.OperationName)
+	if err != nil {
+		return Response{Errors: []Error{{Message: err.Error()}}}
+	}
+	vars, err := resolveVariables(op, req.Variables)
+	if err != nil {
+		return Response{Errors: []Error{{Message: err.Error()}}}
+	}
+
+	e := &executor{vars: vars}
+	data := e.object(root, op.Selection, nil)
+	return Response{Data: data, Errors: e.errors}
+}
+
+func selectOperation(doc *Document, name string) (Operation, error) {
+	if name == "" {
+		if len(doc.Operations) > 1 {
+			return Operation{}, fmt.Errorf("operationName is required when the document has several operations")
+		}
+		return doc.Operations[0], nil
+	}
+	for _, op := range doc.Operations {
+		if op.Name == name {
+			return op, nil
+		}
+	}
+	return Operation{}, fmt.Errorf("unknown operation %q", name)
+}
+
+func resolveVariables(op Operation, given map[string]interface{}) (map[string]interface{}, error) {
+	vars := make(map[string]interface{})
+	for _, def := range op.Variables {
+		value, ok := given[def.Name]
+		if !ok && def.Default.Literal != nil {
+			value, ok = constantValue(def.Default), true
+		}
+		if def.Required && (!ok || value == nil) {
+			return nil, fmt.Errorf("variable $%s of type %s! is required", def.Name, def.Type)
+		}
+		vars[def.Name] = value
+	}
+	return vars, nil
+}
+
+// constantValue converts a literal to the values of decoded JSON
+func constantValue(v Value) interface{} {
+	switch literal := v.Literal.(type) {
+	case Enum:
+		return string(literal)
+	case []Value:
+		list := make([]interface{}, len(literal))
+		for i, item := range literal {
+			list[i] = constantValue(item)
+		}
+		return list
+	case map[string]Value:
+		object := make(map[string]interface{}, len(literal))
+		for name, item := range literal {
+			object[name] = constantValue(item)
+		}
+		return object
+	}
+	return v.Literal
+}
+
+type executor struct {
+	vars   map[string]interface{}
+	errors []Error
+}
+
+func (e *executor) fail(path []string, err error) {
+	e.errors = append(e.errors, Error{Message: err.Error(), Path: append([]string(nil), path...)})
+}
+
+func (e *executor) object(obj Object, selection []Field, path []string) *OrderedMap {
+	result := &OrderedMap{}
+	for _, field := range selection {
+		fieldPath := append(append([]string(nil), path...), field.ResponseKey())
+		resolve, ok := obj[field.Name]
+		if !ok {
+			e.fail(fieldPath, fmt.Errorf("unknown field %q", field.Name))
+			result.Set(field.ResponseKey(), nil)
+			continue
+		}
+		args, err := e.arguments(field.Arguments)
+		if err != nil {
+			e.fail(fieldPath, err)
+			result.Set(field.ResponseKey(), nil)
+			continue
+		}
+		value, err := resolve(args)
+		if err != nil {
+			e.fail(fieldPath, err)
+			result.Set(field.ResponseKey(), nil)
+			continue
+		}
+		result.Set(field.ResponseKey(), e.value(value, field, fieldPath))
+	}
+	return result
+}
+
+func (e *executor) value(value interface{}, field Field, path []string) interface{} {
+	switch v := value.(type) {
+	case Object:
+		if len(field.Selection) == 0 {
+			e.fail(path, fmt.Errorf("field %q of object type must have a selection of subfields", field.Name))
+			return nil
+		}
+		return e.object(v, field.Selection, path)
+	case []Object:
+		if len(field.Selection) == 0 {
+			e.fail(path, fmt.Errorf("field %q of object type must have a selection of subfields", field.Name))
+			return nil
+		}
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = e.object(item, field.Selection, append(path, fmt.Sprint(i)))
+		}
+		return list
+	}
+	if len(field.Selection) > 0 {
+		e.fail(path, fmt.Errorf("field %q of scalar type cannot have a selection", field.Name))
+		return nil
+	}
+	return value
+}
+
+func (e *executor) arguments(arguments []Argument) (Args, error) {
+	args := make(Args, len(arguments))
+	for _, arg := range arguments {
+		value, err := e.resolve(arg.Value)
+		if err != nil {
+			return nil, err
+		}
+		args[arg.Name] = value
+	}
+	return args, nil
+}
+
+func (e *executor) resolve(v Value) (interface{}, error) {
+	if v.Variable != "" {
+		value, ok := e.vars[v.Variable]
+		if !ok {
+			return nil, fmt.Errorf("variable $%s is not defined", v.Variable)
+		}
+		return value, nil
+	}
+	switch literal := v.Literal.(type) {
+	case []Value:
+		list := make([]interface{}, len(literal))
+		for i, item := range literal {
+			value, err := e.resolve(item)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = value
+		}
+		return list, nil
+	case map[string]Value:
+		object := make(map[string]interface{}, len(literal))
+		for name, item := range literal {
+			value, err := e.resolve(item)
+			if err != nil {
+				return nil, err
+			}
+			object[name] = value
+		}
+		return object, nil
+	case Enum:
+		return string(literal), nil
+	}
+	return v.Literal, nil
+}
+
+// Args are the arguments of a field, as literals of the query or values of
+// its JSON variables
+type Args map[string]interface{}
+
+// String returns a string argument, or "" when it is absent
+func (a Args) String(name string) (string, error) {
+	switch v := a[name].(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	}
+	return "", fmt.Errorf("argument %q must be a string", name)
+}
+
+// Int returns an integer argument, or def when it is absent
+func (a Args) Int(name string, def int64) (int64, error) {
+	switch v := a[name].(type) {
+	case nil:
+		return def, nil
+	case int64:
+		return v, nil
+	case float64:
+		// JSON variables decode as float64
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			return int64(v), nil
+		}
+	}
+	return 0, fmt.Errorf("argument %q must be an integer", name)
+}
+
+// Strings returns a list of strings argument. A single string is read as a
+// list of one, as GraphQL coerces it.
+func (a Args) Strings(name string) ([]string, error) {
+	switch v := a[name].(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []string{v}, nil
+	case []interface{}:
+		list := make([]string, len(v))
+		for i, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("argument %q must be a list of strings", name)
+			}
+			list[i] = s
+		}
+		return list, nil
+	}
+	return nil, fmt.Errorf("argument %q must be a list of strings", name)
+}
+
+// Check reports arguments not in names, which the field does not accept
+func (a Args) Check(names ...string) error {
+	var unknown []string
+	for name := range a {
+		known := false
+		for _, n := range names {
+			if n == name {
+				known = true
+				break
+			}
+		}
+		if !known {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown argument %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// OrderedMap is a JSON object that keeps the order of its keys, as
+// responses follow the order of the query
+type OrderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+// Set sets key to value, keeping the position of an existing key
+func (m *OrderedMap) Set(key string, value interface{}) {
+	if m.values == nil {
+		m.values = make(map[string]interface{})
+	}
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+// Get returns the value of key
+func (m *OrderedMap) Get(key string) interface{} {
+	return m.values[key]
+}
+
+// MarshalJSON implements json.Marshaler
+func (m *OrderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,78 @@
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testRoot() Object {
+	item := func(n int64) Object {
+		return Object{
+			"id":   func(Args) (interface{}, error) { return n, nil },
+			"name": func(Args) (interface{}, error) { return fmt.Sprintf("item %d", n), nil },
+		}
+	}
+	return Object{
+		"items": func(args Args) (interface{}, error) {
+			limit, err := args.Int("limit", 2)
+			if err != nil {
+				return nil, err
+			}
+			var items []Object
+			for i := int64(1); i <= limit; i++ {
+				items = append(items, item(i))
+			}
+			return items, nil
+		},
+		"echo": func(args Args) (interface{}, error) {
+			return args.Strings("values")
+		},
+		"broken": func(Args) (interface{}, error) { return nil, fmt.Errorf("boom") },
+	}
+}
+
+func execute(t *testing.T, req Request) string {
+	body, err := json.Marshal(Execute(testRoot(), req))
+	assert.NoError(t, err)
+	return string(body)
+}
+
+func TestExecute(t *testing.T) {
+	assert.Equal(t, `{"data":{"items":[{"name":"item 1","id":1},{"name":"item 2","id":2}]}}`,
+		execute(t, Request{Query: `{ items { name id } }`}))
+
+	// Aliases, variables from JSON and single values coerced to lists
+	assert.Equal(t, `{"data":{"few":[{"id":1}],"echo":["a"]}}`,
+		execute(t, Request{
+			Query:     `query Q($n: Int!, $v: [String]) { few: items(limit: $n) { id } echo(values: $v) }`,
+			Variables: map[string]interface{}{"n": float64(1), "v": "a"},
+		}))
+
+	// Defaults apply to variables that are not given
+	assert.Equal(t, `{"data":{"items":[{"id":1}]}}`,
+		execute(t, Request{Query: `query ($n: Int = 1) { items(limit: $n) { id } }`}))
+}
+
+func TestExecute_Errors(t *testing.T) {
+	assert.Equal(t, `{"data":{"broken":null,"nope":null,"items":null},"errors":[`+
+		`{"message":"boom","path":["broken"]},`+
+		`{"message":"unknown field \"nope\"","path":["nope"]},`+
+		`{"message":"argument \"limit\" must be an integer","path":["items"]}]}`,
+		execute(t, Request{Query: `{ broken nope items(limit: "x") { id } }`}))
+
+	assert.Contains(t, execute(t, Request{Query: `{ items }`}), "must have a selection of subfields")
+	assert.Contains(t, execute(t, Request{Query: `{ items { id { x } } }`}), "cannot have a selection")
+	assert.Contains(t, execute(t, Request{Query: `query ($n: Int!) { items(limit: $n) { id } }`}), "variable $n of type Int! is required")
+	assert.Contains(t, execute(t, Request{Query: `{ items(limit: $n) { id } }`}), "variable $n is not defined")
+	assert.Contains(t, execute(t, Request{Query: `query A { echo } query B { echo }`}), "operationName is required")
+	assert.Contains(t, execute(t, Request{Query: `query A { echo }`, OperationName: "B"}), `unknown operation \"B\"`)
+	assert.Equal(t, `{"errors":[{"message":"syntax error: expected a name, got end of document"}]}`, execute(t, Request{Query: `{`}))
+}
+
+func TestArgs_Check(t *testing.T) {
+	assert.NoError(t, Args{"a": 1}.Check("a", "b"))
+	assert.EqualError(t, Args{"c": 1, "a": 1, "b": 2}.Check("a"), "unknown argument b, c")
+}
@@ -0,0 +1,491 @@
+// Package graphql implements the subset of GraphQL needed to query stored
+// transactions: query operations with arguments, variables and aliases.
+// Fragments, directives, mutations, subscriptions and introspection are
+// not supported.
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Document is a parsed query document
+type Document struct {
+	Operations []Operation
+}
+
+// Operation is a query operation
+type Operation struct {
+	Name      string
+	Variables []VariableDefinition
+	Selection []Field
+}
+
+// VariableDefinition declares a variable of an operation
+type VariableDefinition struct {
+	Name     string
+	Type     string
+	Default  Value
+	Required bool
+}
+
+// Field is a selected field, with the fields selected on its result
+type Field struct {
+	Alias     string
+	Name      string
+	Arguments []Argument
+	Selection []Field
+}
+
+// ResponseKey is the key of the field in the result
+func (f Field) ResponseKey() string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+	return f.Name
+}
+
+// Argument is a named argument of a field
+type Argument struct {
+	Name  string
+	Value Value
+}
+
+// Value is a literal or variable in a query. Variable holds the name of a
+// variable; otherwise Literal holds a string, int64, float64, bool, nil, an
+// Enum, a []Value or a map[string]Value.
+type Value struct {
+	Variable string
+	Literal  interface{}
+}
+
+// Enum is an enum value literal
+type Enum string
+
+// Parse parses a query document
+func Parse(query string) (*Document, error) {
+	p := &parser{lexer: lexer{input: query}}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	doc := &Document{}
+	for p.tok.kind != tokenEOF {
+		op, err := p.parseOperation()
+		if err != nil {
+			return nil, err
+		}
+		doc.Operations = append(doc.Operations, op)
+	}
+	if len(doc.Operations) == 0 {
+		return nil, fmt.Errorf("document has no operations")
+	}
+	return doc, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenPunct
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+type lexer struct {
+	input string
+	pos   int
+}
+
+// next returns the next token, skipping whitespace, commas and comments
+func (l *lexer) next() (token, error) {
+	for l.pos < len(l.input) {
+		c := l.input[l.pos]
+		if c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',' {
+			l.pos++
+		} else if c == '#' {
+			for l.pos < len(l.input) && l.input[l.pos] != '\n' {
+				l.pos++
+			}
+		} else {
+			break
+		}
+	}
+	start := l.pos
+	if l.pos >= len(l.input) {
+		return token{kind: tokenEOF, pos: start}, nil
+	}
+
+	c := l.input[l.pos]
+	switch {
+	case strings.HasPrefix(l.input[l.pos:], "..."):
+		l.pos += 3
+		return token{kind: tokenPunct, value: "...", pos: start}, nil
+	case strings.IndexByte("!$()[]{}:=@|&", c) >= 0:
+		l.pos++
+		return token{kind: tokenPunct, value: string(c), pos: start}, nil
+	case c == '_' || isLetter(c):
+		for l.pos < len(l.input) && (l.input[l.pos] == '_' || isLetter(l.input[l.pos]) || isDigit(l.input[l.pos])) {
+			l.pos++
+		}
+		return token{kind: tokenName, value: l.input[start:l.pos], pos: start}, nil
+	case c == '-' || isDigit(c):
+		return l.number()
+	case c == '"':
+		return l.string()
+	}
+	r, _ := utf8.DecodeRuneInString(l.input[l.pos:])
+	return token{}, fmt.Errorf("unexpected character %q at offset %d", r, start)
+}
+
+func (l *lexer) number() (token, error) {
+	start := l.pos
+	kind := tokenInt
+	if l.input[l.pos] == '-' {
+		l.pos++
+	}
+	digits := func() {
+		for l.pos < len(l.input) && isDigit(l.input[l.pos]) {
+			l.pos++
+		}
+	}
+	digits()
+	if l.pos < len(l.input) && l.input[l.pos] == '.' {
+		kind = tokenFloat
+		l.pos++
+		digits()
+	}
+	if l.pos < len(l.input) && (l.input[l.pos] == 'e' || l.input[l.pos] == 'E') {
+		kind = tokenFloat
+		l.pos++
+		if l.pos < len(l.input) && (l.input[l.pos] == '+' || l.input[l.pos] == '-') {
+			l.pos++
+		}
+		digits()
+	}
+	return token{kind: kind, value: l.input[start:l.pos], pos: start}, nil
+}
+
+func (l *lexer) string() (token, error) {
+	start := l.pos
+	l.pos++
+	for l.pos < len(l.input) {
+		switch l.input[l.pos] {
+		case '\\':
+			l.pos += 2
+		case '\n':
+			return token{}, fmt.Errorf("unterminated string at offset %d", start)
+		case '"':
+			l.pos++
+			value, err := strconv.Unquote(l.input[start:l.pos])
+			if err != nil {
+				return token{}, fmt.Errorf("invalid string at offset %d", start)
+			}
+			return token{kind: tokenString, value: value, pos: start}, nil
+		default:
+			l.pos++
+		}
+	}
+	return token{}, fmt.Errorf("unterminated string at offset %d", start)
+}
+
+func isLetter(c byte) bool { return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' }
+func isDigit(c byte) bool  { return c >= '0' && c <= '9' }
+
+type parser struct {
+	lexer lexer
+	tok   token
+}
+
+func (p *parser) advance() error {
+	tok, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *parser) peek(punct string) bool {
+	return p.tok.kind == tokenPunct && p.tok.value == punct
+}
+
+func (p *parser) expect(punct string) error {
+	if !p.peek(punct) {
+		return p.unexpected("expected " + strconv.Quote(punct))
+	}
+	return p.advance()
+}
+
+func (p *parser) name() (string, error) {
+	if p.tok.kind != tokenName {
+		return "", p.unexpected("expected a name")
+	}
+	name := p.tok.value
+	return name, p.advance()
+}
+
+func (p *parser) unexpected(want string) error {
+	if p.tok.kind == tokenEOF {
+		return fmt.Errorf("%s, got end of document", want)
+	}
+	return fmt.Errorf("%s, got %q at offset %d", want, p.tok.value, p.tok.pos)
+}
+
+func (p *parser) parseOperation() (Operation, error) {
+	var op Operation
+	if p.peek("{") {
+		var err error
+		op.Selection, err = p.parseSelectionSet()
+		return op, err
+	}
+	if p.tok.kind != tokenName {
+		return op, p.unexpected("expected an operation")
+	}
+	switch p.tok.value {
+	case "query":
+	case "mutation", "subscription":
+		return op, fmt.Errorf("%s operations are not supported", p.tok.value)
+	case "fragment":
+		return op, fmt.Errorf("fragments are not supported")
+	default:
+		return op, p.unexpected("expected an operation")
+	}
+	if err := p.advance(); err != nil {
+		return op, err
+	}
+	if p.tok.kind == tokenName {
+		op.Name = p.tok.value
+		if err := p.advance(); err != nil {
+			return op, err
+		}
+	}
+	if p.peek("(") {
+		var err error
+		if op.Variables, err = p.parseVariableDefinitions(); err != nil {
+			return op, err
+		}
+	}
+	var err error
+	op.Selection, err = p.parseSelectionSet()
+	return op, err
+}
+
+func (p *parser) parseVariableDefinitions() ([]VariableDefinition, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	var defs []VariableDefinition
+	for !p.peek(")") {
+		if err := p.expect("$"); err != nil {
+			return nil, err
+		}
+		var def VariableDefinition
+		var err error
+		if def.Name, err = p.name(); err != nil {
+			return nil, err
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		if def.Type, def.Required, err = p.parseType(); err != nil {
+			return nil, err
+		}
+		if p.peek("=") {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			if def.Default, err = p.parseValue(true); err != nil {
+				return nil, err
+			}
+		}
+		defs = append(defs, def)
+	}
+	return defs, p.advance()
+}
+
+// parseType parses a type reference such as String!, [String] or [Int!]!
+func (p *parser) parseType() (string, bool, error) {
+	var typ string
+	if p.peek("[") {
+		if err := p.advance(); err != nil {
+			return "", false, err
+		}
+		inner, required, err := p.parseType()
+		if err != nil {
+			return "", false, err
+		}
+		if required {
+			inner += "!"
+		}
+		if err := p.expect("]"); err != nil {
+			return "", false, err
+		}
+		typ = "[" + inner + "]"
+	} else {
+		name, err := p.name()
+		if err != nil {
+			return "", false, err
+		}
+		typ = name
+	}
+	if p.peek("!") {
+		return typ, true, p.advance()
+	}
+	return typ, false, nil
+}
+
+func (p *parser) parseSelectionSet() ([]Field, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	var fields []Field
+	for !p.peek("}") {
+		if p.peek("...") {
+			return nil, fmt.Errorf("fragments are not supported")
+		}
+		field, err := p.parseField()
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty selection set at offset %d", p.tok.pos)
+	}
+	return fields, p.advance()
+}
+
+func (p *parser) parseField() (Field, error) {
+	var field Field
+	var err error
+	if field.Name, err = p.name(); err != nil {
+		return field, err
+	}
+	if p.peek(":") {
+		if err := p.advance(); err != nil {
+			return field, err
+		}
+		field.Alias = field.Name
+		if field.Name, err = p.name(); err != nil {
+			return field, err
+		}
+	}
+	if p.peek("(") {
+		if field.Arguments, err = p.parseArguments(); err != nil {
+			return field, err
+		}
+	}
+	if p.peek("@") {
+		return field, fmt.Errorf("directives are not supported")
+	}
+	if p.peek("{") {
+		if field.Selection, err = p.parseSelectionSet(); err != nil {
+			return field, err
+		}
+	}
+	return field, nil
+}
+
+func (p *parser) parseArguments() ([]Argument, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	var args []Argument
+	for !p.peek(")") {
+		var arg Argument
+		var err error
+		if arg.Name, err = p.name(); err != nil {
+			return nil, err
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		if arg.Value, err = p.parseValue(false); err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	return args, p.advance()
+}
+
+// parseValue parses a value. Constant values, such as variable defaults,
+// cannot refer to variables.
+func (p *parser) parseValue(constant bool) (Value, error) {
+	tok := p.tok
+	switch {
+	case tok.kind == tokenPunct && tok.value == "$" && !constant:
+		if err := p.advance(); err != nil {
+			return Value{}, err
+		}
+		name, err := p.name()
+		return Value{Variable: name}, err
+	case tok.kind == tokenPunct && tok.value == "[":
+		if err := p.advance(); err != nil {
+			return Value{}, err
+		}
+		list := []Value{}
+		for !p.peek("]") {
+			item, err := p.parseValue(constant)
+			if err != nil {
+				return Value{}, err
+			}
+			list = append(list, item)
+		}
+		return Value{Literal: list}, p.advance()
+	case tok.kind == tokenPunct && tok.value == "{":
+		if err := p.advance(); err != nil {
+			return Value{}, err
+		}
+		object := map[string]Value{}
+		for !p.peek("}") {
+			name, err := p.name()
+			if err != nil {
+				return Value{}, err
+			}
+			if err := p.expect(":"); err != nil {
+				return Value{}, err
+			}
+			if object[name], err = p.parseValue(constant); err != nil {
+				return Value{}, err
+			}
+		}
+		return Value{Literal: object}, p.advance()
+	case tok.kind == tokenInt:
+		n, err := strconv.ParseInt(tok.value, 10, 64)
+		if err != nil {
+			return Value{}, fmt.Errorf("invalid integer %s at offset %d", tok.value, tok.pos)
+		}
+		return Value{Literal: n}, p.advance()
+	case tok.kind == tokenFloat:
+		f, err := strconv.ParseFloat(tok.value, 64)
+		if err != nil {
+			return Value{}, fmt.Errorf("invalid number %s at offset %d", tok.value, tok.pos)
+		}
+		return Value{Literal: f}, p.advance()
+	case tok.kind == tokenString:
+		return Value{Literal: tok.value}, p.advance()
+	case tok.kind == tokenName:
+		var literal interface{}
+		switch tok.value {
+		case "true":
+			literal = true
+		case "false":
+			literal = false
+		case "null":
+		default:
+			literal = Enum(tok.value)
+		}
+		return Value{Literal: literal}, p.advance()
+	}
+	return Value{}, p.unexpected("expected a value")
+}
@@ -0,0 +1,71 @@
+package graphql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	doc, err := Parse(`
+		# Rows of a wallet
+		query Rows($address: String!, $types: [String!] = ["ERC20_TRANSFER"], $limit: Int) {
+			page: transactions(address: $address, types: $types, limit: 10, since: "2024-01-01") {
+				total
+				items { hash value }
+			}
+		}`)
+	assert.NoError(t, err)
+	assert.Len(t, doc.Operations, 1)
+
+	op := doc.Operations[0]
+	assert.Equal(t, "Rows", op.Name)
+	assert.Equal(t, []VariableDefinition{
+		{Name: "address", Type: "String", Required: true},
+		{Name: "types", Type: "[String!]", Default: Value{Literal: []Value{{Literal: "ERC20_TRANSFER"}}}},
+		{Name: "limit", Type: "Int"},
+	}, op.Variables)
+
+	field := op.Selection[0]
+	assert.Equal(t, "page", field.ResponseKey())
+	assert.Equal(t, "transactions", field.Name)
+	assert.Equal(t, []Argument{
+		{Name: "address", Value: Value{Variable: "address"}},
+		{Name: "types", Value: Value{Variable: "types"}},
+		{Name: "limit", Value: Value{Literal: int64(10)}},
+		{Name: "since", Value: Value{Literal: "2024-01-01"}},
+	}, field.Arguments)
+	assert.Equal(t, "items", field.Selection[1].Name)
+	assert.Len(t, field.Selection[1].Selection, 2)
+}
+
+func TestParse_Values(t *testing.T) {
+	doc, err := Parse(`{ f(a: -1.5e2, b: true, c: null, d: ASC, e: {x: [1 2]}, s: "q\"é") }`)
+	assert.NoError(t, err)
+	args := doc.Operations[0].Selection[0].Arguments
+	assert.Equal(t, -150.0, args[0].Value.Literal)
+	assert.Equal(t, true, args[1].Value.Literal)
+	assert.Nil(t, args[2].Value.Literal)
+	assert.Equal(t, Enum("ASC"), args[3].Value.Literal)
+	assert.Equal(t, map[string]Value{"x": {Literal: []Value{{Literal: int64(1)}, {Literal: int64(2)}}}}, args[4].Value.Literal)
+	assert.Equal(t, `q"é`, args[5].Value.Literal)
+}
+
+func TestParse_Errors(t *testing.T) {
+	for query, message := range map[string]string{
+		"":                      "no operations",
+		"{ a ":                  "end of document",
+		"{ }":                   "empty selection set",
+		"mutation { a }":        "mutation operations are not supported",
+		"{ ...f }":              "fragments are not supported",
+		"{ a @skip(if: true) }": "directives are not supported",
+		`{ a(b: "x) }`:          "unterminated string",
+		"{ a(b: $c) }  query ($d: Int = $e) { a }": "expected a value",
+		"{ a % }": "unexpected character",
+	} {
+		_, err := Parse(query)
+		if assert.Error(t, err, query) {
+			assert.Contains(t, err.Error(), message, query)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+// Package health serves liveness and readiness endpoints for orchestrators
+// such as Kubernetes.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultTimeout bounds each check when Checker.Timeout is zero
+	DefaultTimeout = 5 * time.Second
+	// DefaultInterval is how long results are reused when Checker.Interval
+	// is zero, so frequent probes do not spend provider quota
+	DefaultInterval = 30 * time.Second
+)
+
+// Statuses of checks and of a whole report
+const (
+	StatusOK          = "ok"
+	StatusError       = "error"
+	StatusUnavailable = "unavailable"
+)
+
+// Check verifies one dependency, such as the provider or the store
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// Result is the outcome of a check
+type Result struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	// Duration is the time the check took, in milliseconds
+	Duration int64 `json:"duration_ms"`
+}
+
+// Report is the response of the readiness endpoint
+type Report struct {
+	Status    string            `json:"status"`
+	CheckedAt time.Time         `json:"checked_at"`
+	Checks    map[string]Result `json:"checks"`
+}
+
+// Live answers liveness probes. A process that can answer is alive.
+var Live = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": StatusOK})
+})
+
+// Checker answers readiness probes by running its checks. It is ready when
+// every check passes.
+type Checker struct {
+	Checks []Check
+	// Timeout bounds each check. Defaults to DefaultTimeout.
+	Timeout time.Duration
+	// Interval is how long a report is reused. Defaults to DefaultInterval.
+	Interval time.Duration
+
+	mu   sync.Mutex
+	last *Report
+}
+
+// Check runs the checks concurrently, or returns the previous report while
+// it is recent
+func (c *Checker) Check(ctx context.Context) Report {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	interval := c.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	if c.last != nil && time.Since(c.last.CheckedAt) < interval {
+		return *c.last
+	}
+
+	report := Report{Status: StatusOK, CheckedAt: time.Now(), Checks: make(map[string]Result, len(c.Checks))}
+	results := make([]Result, len(c.Checks))
+	var wg sync.WaitGroup
+	for i, check := range c.Checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			results[i] = c.run(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+	for i, check := range c.Checks {
+		report.Checks[check.Name] = results[i]
+		if results[i].Status != StatusOK {
+			report.Status = StatusUnavailable
+		}
+	}
+	c.last = &report
+	return report
+}
+
+// run runs a check, failing it when it outlasts the timeout even if the
+// check ignores its context
+func (c *Checker) run(ctx context.Context, check Check) Result {
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() { done <- check.Run(ctx) }()
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("timed out after %s", timeout)
+	}
+
+	result := Result{Status: StatusOK, Duration: time.Since(start).Milliseconds()}
+	if err != nil {
+		result.Status, result.Error = StatusError, err.Error()
+	}
+	return result
+}
+
+// ServeHTTP answers readiness probes with the report, with status 200 when
+// ready and 503 otherwise
+func (c *Checker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	report := c.Check(r.Context())
+	status := http.StatusOK
+	if report.Status != StatusOK {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, report)
+}
+
+// Register mounts Live at /healthz and c at /readyz
+func (c *Checker) Register(mux *http.ServeMux) {
+	mux.Handle("GET /healthz", Live)
+	mux.Handle("GET /readyz", c)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
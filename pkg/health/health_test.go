@@ -0,0 +1,69 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecker_Ready(t *testing.T) {
+	calls := 0
+	c := &Checker{Checks: []Check{{Name: "store", Run: func(context.Context) error {
+		calls++
+		return nil
+	}}}}
+	mux := http.NewServeMux()
+	c.Register(mux)
+
+	for i := 0; i < 2; i++ {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var report Report
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+		assert.Equal(t, StatusOK, report.Status)
+		assert.Equal(t, StatusOK, report.Checks["store"].Status)
+	}
+	assert.Equal(t, 1, calls, "a recent report is reused")
+}
+
+func TestChecker_NotReady(t *testing.T) {
+	c := &Checker{
+		Checks: []Check{
+			{Name: "store", Run: func(context.Context) error { return nil }},
+			{Name: "provider", Run: func(context.Context) error { return errors.New("invalid API key") }},
+			{Name: "slow", Run: func(context.Context) error {
+				time.Sleep(time.Second)
+				return nil
+			}},
+		},
+		Timeout:  10 * time.Millisecond,
+		Interval: time.Nanosecond,
+	}
+
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	var report Report
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	assert.Equal(t, StatusUnavailable, report.Status)
+	assert.Equal(t, StatusOK, report.Checks["store"].Status)
+	assert.Equal(t, Result{Status: StatusError, Error: "invalid API key", Duration: report.Checks["provider"].Duration}, report.Checks["provider"])
+	assert.Equal(t, "timed out after 10ms", report.Checks["slow"].Error)
+}
+
+func TestLive(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Live.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, rec.Body.String())
+}
@@ -0,0 +1,257 @@
+// Package holdings reads the current token balances of an address from the
+// chain, to show them beside what its transfer history says it holds.
+package holdings
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/api"
+	"github.com/haridev22/ct-assignement/pkg/enrich"
+	"github.com/haridev22/ct-assignement/pkg/models"
+)
+
+// selectorDecimals is the selector of the ERC-20 decimals() function
+const selectorDecimals = "0x313ce567"
+
+// Source reads current token balances and metadata
+type Source interface {
+	// Balances returns the balance of owner in each contract, in the
+	// token's smallest unit, with nil for balances that could not be read
+	Balances(owner string, contracts []string) ([]*big.Int, error)
+	// Decimals returns the decimals of a token contract
+	Decimals(contract string) (int, error)
+}
+
+// Caller executes read-only contract calls at the latest block. Both the
+// Etherscan client and the JSON-RPC client implement it.
+type Caller interface {
+	CallContract(to, data string) (string, error)
+}
+
+// Position is the current balance of an address in one token, beside the
+// transfers of the token in its history
+type Position struct {
+	Symbol string
+	// Contract is the lowercased token contract
+	Contract string
+	// Balance is the on-chain balance, nil when it could not be read
+	Balance *big.Rat
+	// History is the balance the transfers in the history add up to
+	History      *big.Rat
+	Transfers    int
+	LastTransfer time.Time
+}
+
+// Position statuses
+const (
+	StatusHeld       = "held"
+	StatusEmpty      = "empty"
+	StatusDiffers    = "differs"
+	StatusUnreadable = "unreadable"
+)
+
+// Status is StatusHeld for a balance the history accounts for, StatusEmpty
+// for a closed position, StatusDiffers when the history does not add up to
+// the balance, as with rebasing tokens or airdrops that emit no transfer,
+// and StatusUnreadable when the balance could not be read
+func (p Position) Status() string {
+	switch {
+	case p.Balance == nil:
+		return StatusUnreadable
+	case p.Balance.Cmp(p.History) != 0:
+		return StatusDiffers
+	case p.Balance.Sign() == 0:
+		return StatusEmpty
+	}
+	return StatusHeld
+}
+
+// Read reads the current balance of owner in every ERC-20 token its rows
+// moved. Positions are sorted by symbol and contract.
+func Read(source Source, owner string, transactions []models.Transaction) ([]Position, error) {
+	byContract := make(map[string]*Position)
+	decimals := make(map[string]int)
+	// chain is the chain the rows of each contract were fetched from, as
+	// known decimals are of a contract on one chain
+	chain := make(map[string]string)
+	seen := make(map[string]bool)
+	for i := range transactions {
+		tx := &transactions[i]
+		if tx.Type != models.TypeERC20Transfer || seen[tx.RowID()] {
+			continue
+		}
+		seen[tx.RowID()] = true
+		contract := strings.ToLower(tx.AssetContractAddr)
+		p := byContract[contract]
+		if p == nil {
+			p = &Position{Symbol: tx.AssetSymbol, Contract: contract, History: new(big.Rat)}
+			byContract[contract] = p
+			chain[contract] = tx.Chain
+		}
+		p.Transfers++
+		if tx.Timestamp.After(p.LastTransfer) {
+			p.LastTransfer = tx.Timestamp
+		}
+		if _, ok := decimals[contract]; !ok {
+			if d, ok := rowDecimals(tx); ok {
+				decimals[contract] = d
+			}
+		}
+
+		amount, ok := new(big.Rat).SetString(tx.Value)
+		if !ok || tx.Failed() {
+			continue
+		}
+		if strings.EqualFold(tx.To, owner) {
+			p.History.Add(p.History, amount)
+		}
+		if strings.EqualFold(tx.From, owner) {
+			p.History.Sub(p.History, amount)
+		}
+	}
+
+	positions := make([]Position, 0, len(byContract))
+	for _, p := range byContract {
+		positions = append(positions, *p)
+	}
+	if len(positions) == 0 {
+		return positions, nil
+	}
+	sort.Slice(positions, func(i, j int) bool {
+		if positions[i].Symbol != positions[j].Symbol {
+			return positions[i].Symbol < positions[j].Symbol
+		}
+		return positions[i].Contract < positions[j].Contract
+	})
+	contracts := make([]string, len(positions))
+	for i := range positions {
+		contracts[i] = positions[i].Contract
+	}
+
+	balances, err := source.Balances(owner, contracts)
+	if err != nil {
+		return nil, err
+	}
+	for i := range positions {
+		if balances[i] == nil {
+			continue
+		}
+		contract := positions[i].Contract
+		d, ok := decimals[contract]
+		if known, isKnown := enrich.KnownDecimals(chain[contract], contract); isKnown {
+			d, ok = known, true
+		}
+		if !ok {
+			if d, err = source.Decimals(contract); err != nil {
+				// Without decimals the balance cannot be compared
+				continue
+			}
+		}
+		divisor := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(d)), nil)
+		positions[i].Balance = new(big.Rat).SetFrac(balances[i], divisor)
+	}
+	return positions, nil
+}
+
+// rowDecimals derives the decimals of a transfer from its amount and raw
+// amount, when the row carries both
+func rowDecimals(tx *models.Transaction) (int, bool) {
+	raw, ok := new(big.Rat).SetString(tx.RawValue)
+	if !ok || raw.Sign() <= 0 {
+		return 0, false
+	}
+	amount, ok := new(big.Rat).SetString(tx.Value)
+	if !ok || amount.Sign() <= 0 {
+		return 0, false
+	}
+	scale := new(big.Rat).Quo(raw, amount)
+	if !scale.IsInt() {
+		return 0, false
+	}
+	digits := scale.Num().String()
+	if strings.TrimLeft(digits[1:], "0") != "" || digits[0] != '1' || len(digits)-1 > enrich.MaxTokenDecimals {
+		return 0, false
+	}
+	return len(digits) - 1, true
+}
+
+// decodeDecimals parses the result of a decimals() call
+func decodeDecimals(out string) (int, error) {
+	value, err := api.ParseHexBig(out)
+	if err != nil {
+		return 0, err
+	}
+	if !value.IsInt64() || value.Int64() > enrich.MaxTokenDecimals {
+		return 0, fmt.Errorf("invalid decimals %s", value)
+	}
+	return int(value.Int64()), nil
+}
+
+// Write prints the positions in a plain-text table
+func Write(w io.Writer, owner string, positions []Position) error {
+	if _, err := fmt.Fprintf(w, "Token holdings of %s:\n", owner); err != nil {
+		return err
+	}
+	if len(positions) == 0 {
+		_, err := fmt.Fprintln(w, "  No ERC-20 transfers in the history")
+		return err
+	}
+	for _, p := range positions {
+		balance := "?"
+		if p.Balance != nil {
+			balance = formatRat(p.Balance)
+		}
+		name := fmt.Sprintf("%s (%s)", p.Symbol, p.Contract)
+		line := fmt.Sprintf("  %-56s %-24s %-10s %d transfer(s), last %s", name, balance, p.Status(), p.Transfers, p.LastTransfer.UTC().Format("2006-01-02"))
+		if p.Status() == StatusDiffers {
+			line += ", history adds up to " + formatRat(p.History)
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type positionJSON struct {
+	Symbol       string    `json:"symbol"`
+	Contract     string    `json:"contract"`
+	Balance      *string   `json:"balance"`
+	History      string    `json:"history"`
+	Status       string    `json:"status"`
+	Transfers    int       `json:"transfers"`
+	LastTransfer time.Time `json:"last_transfer"`
+}
+
+// MarshalJSON implements json.Marshaler. Amounts are decimal strings, so
+// they keep their precision; an unreadable balance is null.
+func (p Position) MarshalJSON() ([]byte, error) {
+	out := positionJSON{
+		Symbol:       p.Symbol,
+		Contract:     p.Contract,
+		History:      formatRat(p.History),
+		Status:       p.Status(),
+		Transfers:    p.Transfers,
+		LastTransfer: p.LastTransfer,
+	}
+	if p.Balance != nil {
+		balance := formatRat(p.Balance)
+		out.Balance = &balance
+	}
+	return json.Marshal(out)
+}
+
+// formatRat prints r as a decimal without trailing zeros
+func formatRat(r *big.Rat) string {
+	s := r.FloatString(enrich.MaxTokenDecimals)
+	if strings.Contains(s, ".") {
+		s = strings.TrimSuffix(strings.TrimRight(s, "0"), ".")
+	}
+	return s
+}
@@ -0,0 +1,109 @@
+package holdings
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+const owner = "0x1111111111111111111111111111111111111111"
+
+// fakeSource answers from fixed balances and decimals
+type fakeSource struct {
+	balances map[string]*big.Int
+	decimals map[string]int
+	asked    []string
+}
+
+func (f *fakeSource) Balances(owner string, contracts []string) ([]*big.Int, error) {
+	result := make([]*big.Int, len(contracts))
+	for i, contract := range contracts {
+		result[i] = f.balances[contract]
+	}
+	return result, nil
+}
+
+func (f *fakeSource) Decimals(contract string) (int, error) {
+	f.asked = append(f.asked, contract)
+	d, ok := f.decimals[contract]
+	if !ok {
+		return 0, errors.New("execution reverted")
+	}
+	return d, nil
+}
+
+func historyRows() []models.Transaction {
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	return []models.Transaction{
+		{Hash: "0x1", Timestamp: day, Type: models.TypeERC20Transfer, From: "0xexchange", To: owner, AssetContractAddr: "0xA0b86991c6218b36c1d19d4a2e9eb0ce3606eb48", AssetSymbol: "USDC", Value: "1500"},
+		{Hash: "0x2", Timestamp: day.AddDate(0, 1, 0), Type: models.TypeERC20Transfer, From: owner, To: "0xshop", AssetContractAddr: "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48", AssetSymbol: "USDC", Value: "500"},
+		{Hash: "0x3", Timestamp: day, Type: models.TypeERC20Transfer, From: "0xfaucet", To: owner, AssetContractAddr: "0xdust", AssetSymbol: "DUST", Value: "0.5", RawValue: "500"},
+		{Hash: "0x4", Timestamp: day, Type: models.TypeERC20Transfer, From: "0xpool", To: owner, AssetContractAddr: "0xsteth", AssetSymbol: "stETH", Value: "1"},
+		{Hash: "0x5", Timestamp: day, Type: models.TypeERC20Transfer, From: "0xscam", To: owner, AssetContractAddr: "0xbroken", AssetSymbol: "BRK", Value: "1"},
+		{Hash: "0x6", Timestamp: day, Type: models.TypeEthTransfer, From: owner, To: "0xshop", Value: "1"},
+	}
+}
+
+func TestRead(t *testing.T) {
+	steth, _ := new(big.Int).SetString("1010000000000000000", 10)
+	source := &fakeSource{
+		balances: map[string]*big.Int{
+			"0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48": big.NewInt(1000000000),
+			"0xdust":  big.NewInt(0),
+			"0xsteth": steth,
+		},
+		decimals: map[string]int{"0xsteth": 18},
+	}
+
+	positions, err := Read(source, owner, historyRows())
+	assert.NoError(t, err)
+
+	var got []string
+	for _, p := range positions {
+		balance := "nil"
+		if p.Balance != nil {
+			balance = formatRat(p.Balance)
+		}
+		got = append(got, p.Symbol+" "+balance+" "+p.Status())
+	}
+	assert.Equal(t, []string{"BRK nil unreadable", "DUST 0 differs", "USDC 1000 held", "stETH 1.01 differs"}, got)
+	// USDC decimals are known, and DUST decimals follow from its raw amount
+	assert.Equal(t, []string{"0xsteth"}, source.asked)
+	assert.Equal(t, 2, positions[2].Transfers)
+	assert.Equal(t, time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), positions[2].LastTransfer)
+}
+
+func TestRead_NoTokens(t *testing.T) {
+	positions, err := Read(&fakeSource{}, owner, historyRows()[5:])
+	assert.NoError(t, err)
+	assert.Empty(t, positions)
+
+	var buf bytes.Buffer
+	assert.NoError(t, Write(&buf, owner, positions))
+	assert.Contains(t, buf.String(), "No ERC-20 transfers")
+}
+
+func TestWrite(t *testing.T) {
+	positions := []Position{
+		{Symbol: "USDC", Contract: "0xusdc", Balance: big.NewRat(1000, 1), History: big.NewRat(1000, 1), Transfers: 2, LastTransfer: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{Symbol: "stETH", Contract: "0xsteth", Balance: big.NewRat(101, 100), History: big.NewRat(1, 1), Transfers: 1},
+		{Symbol: "BRK", Contract: "0xbroken", History: big.NewRat(1, 1), Transfers: 1},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, Write(&buf, owner, positions))
+	assert.Contains(t, buf.String(), "Token holdings of "+owner+":\n")
+	assert.Regexp(t, `USDC \(0xusdc\) +1000 +held +2 transfer\(s\), last 2024-04-01\n`, buf.String())
+	assert.Contains(t, buf.String(), "differs    1 transfer(s), last 0001-01-01, history adds up to 1\n")
+	assert.Regexp(t, `BRK \(0xbroken\) +\? +unreadable`, buf.String())
+
+	data, err := json.Marshal(positions[2])
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"symbol":"BRK","contract":"0xbroken","balance":null,"history":"1","status":"unreadable","transfers":1,"last_transfer":"0001-01-01T00:00:00Z"}`, string(data))
+}
@@ -0,0 +1,226 @@
+package holdings
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/haridev22/ct-assignement/pkg/api"
+)
+
+// Etherscan reads balances with one tokenbalance call per contract
+type Etherscan struct {
+	Client *api.EtherscanClient
+}
+
+// Balances implements Source. It fails only when no balance could be read.
+func (e Etherscan) Balances(owner string, contracts []string) ([]*big.Int, error) {
+	balances := make([]*big.Int, len(contracts))
+	var lastErr error
+	read := 0
+	for i, contract := range contracts {
+		balance, err := e.Client.GetTokenBalance(owner, contract)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		balances[i] = balance
+		read++
+	}
+	if read == 0 {
+		return nil, lastErr
+	}
+	return balances, nil
+}
+
+// Decimals implements Source with a decimals() call via the proxy module
+func (e Etherscan) Decimals(contract string) (int, error) {
+	out, err := e.Client.CallContract(contract, selectorDecimals)
+	if err != nil {
+		return 0, err
+	}
+	return decodeDecimals(out)
+}
+
+// DefaultMulticall is the address of the Multicall3 contract, which is
+// deployed at the same address on most EVM chains
+const DefaultMulticall = "0xcA11bde05977b3631167028862bE2a4173976CA11"
+
+// DefaultBatchSize is the number of balanceOf calls aggregated into one call
+const DefaultBatchSize = 100
+
+const (
+	// selectorBalanceOf is the selector of balanceOf(address)
+	selectorBalanceOf = "70a08231"
+	// selectorAggregate3 is the selector of Multicall3's
+	// aggregate3((address,bool,bytes)[])
+	selectorAggregate3 = "82ad56cb"
+)
+
+// Multicall reads balances through a node, aggregating the balanceOf calls
+// of up to BatchSize contracts into one call of a Multicall3 contract
+type Multicall struct {
+	Node Caller
+	// Address is the Multicall3 contract, DefaultMulticall when empty
+	Address string
+	// BatchSize defaults to DefaultBatchSize
+	BatchSize int
+}
+
+// Balances implements Source. Contracts whose balanceOf reverts, and
+// invalid contract addresses, get a nil balance.
+func (m Multicall) Balances(owner string, contracts []string) ([]*big.Int, error) {
+	address, size := m.Address, m.BatchSize
+	if address == "" {
+		address = DefaultMulticall
+	}
+	if size <= 0 {
+		size = DefaultBatchSize
+	}
+
+	var valid []int
+	for i, contract := range contracts {
+		if _, err := addressWord(contract); err == nil {
+			valid = append(valid, i)
+		}
+	}
+	balances := make([]*big.Int, len(contracts))
+	for start := 0; start < len(valid); start += size {
+		indexes := valid[start:min(start+size, len(valid))]
+		batch := make([]string, len(indexes))
+		for i, index := range indexes {
+			batch[i] = contracts[index]
+		}
+		data, err := encodeBalanceCalls(owner, batch)
+		if err != nil {
+			return nil, err
+		}
+		out, err := m.Node.CallContract(address, data)
+		if err != nil {
+			return nil, fmt.Errorf("multicall failed: %w", err)
+		}
+		results, err := decodeAggregate3(out, len(batch))
+		if err != nil {
+			return nil, fmt.Errorf("invalid multicall result: %w", err)
+		}
+		for i, index := range indexes {
+			balances[index] = results[i]
+		}
+	}
+	return balances, nil
+}
+
+// Decimals implements Source with a decimals() call
+func (m Multicall) Decimals(contract string) (int, error) {
+	out, err := m.Node.CallContract(contract, selectorDecimals)
+	if err != nil {
+		return 0, err
+	}
+	return decodeDecimals(out)
+}
+
+// encodeBalanceCalls ABI-encodes an aggregate3 call of balanceOf(owner) on
+// each contract, allowing each call to fail
+func encodeBalanceCalls(owner string, contracts []string) (string, error) {
+	ownerWord, err := addressWord(owner)
+	if err != nil {
+		return "", err
+	}
+	callData := selectorBalanceOf + ownerWord
+
+	var b strings.Builder
+	b.WriteString("0x" + selectorAggregate3)
+	b.WriteString(uintWord(32)) // offset of the array
+	b.WriteString(uintWord(len(contracts)))
+	// Each (address, bool, bytes) tuple takes three head words, the length
+	// of callData and callData padded to two words
+	const tupleSize = 6 * 32
+	for i := range contracts {
+		b.WriteString(uintWord(len(contracts)*32 + i*tupleSize))
+	}
+	for _, contract := range contracts {
+		target, err := addressWord(contract)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(target)
+		b.WriteString(uintWord(1)) // allowFailure
+		b.WriteString(uintWord(3 * 32))
+		b.WriteString(uintWord(len(callData) / 2))
+		b.WriteString(callData + strings.Repeat("0", 2*64-len(callData)))
+	}
+	return b.String(), nil
+}
+
+// decodeAggregate3 decodes the (bool success, bytes returnData)[] result of
+// aggregate3 as balances, with nil for failed calls
+func decodeAggregate3(out string, n int) ([]*big.Int, error) {
+	raw, err := hex.DecodeString(strings.TrimPrefix(out, "0x"))
+	if err != nil {
+		return nil, err
+	}
+	word := func(at int) (int, error) {
+		if at < 0 || at+32 > len(raw) {
+			return 0, fmt.Errorf("result too short")
+		}
+		value := new(big.Int).SetBytes(raw[at : at+32])
+		if !value.IsInt64() || value.Int64() > int64(len(raw)) {
+			return 0, fmt.Errorf("offset out of range")
+		}
+		return int(value.Int64()), nil
+	}
+
+	array, err := word(0)
+	if err != nil {
+		return nil, err
+	}
+	length, err := word(array)
+	if err != nil {
+		return nil, err
+	}
+	if length != n {
+		return nil, fmt.Errorf("expected %d results, got %d", n, length)
+	}
+	items := array + 32
+	balances := make([]*big.Int, n)
+	for i := range balances {
+		offset, err := word(items + i*32)
+		if err != nil {
+			return nil, err
+		}
+		tuple := items + offset
+		success, err := word(tuple)
+		if err != nil {
+			return nil, err
+		}
+		dataOffset, err := word(tuple + 32)
+		if err != nil {
+			return nil, err
+		}
+		size, err := word(tuple + dataOffset)
+		if err != nil {
+			return nil, err
+		}
+		data := tuple + dataOffset + 32
+		if success == 0 || size < 32 || data+32 > len(raw) {
+			continue
+		}
+		balances[i] = new(big.Int).SetBytes(raw[data : data+32])
+	}
+	return balances, nil
+}
+
+// addressWord left-pads a 0x address to a 32-byte ABI word
+func addressWord(address string) (string, error) {
+	digits := strings.ToLower(strings.TrimPrefix(address, "0x"))
+	if _, err := hex.DecodeString(digits); err != nil || len(digits) != 40 {
+		return "", fmt.Errorf("invalid address %q", address)
+	}
+	return strings.Repeat("0", 24) + digits, nil
+}
+
+// uintWord encodes n as a 32-byte ABI word
+func uintWord(n int) string {
+	return fmt.Sprintf("%064x", n)
+}
@@ -0,0 +1,132 @@
+package holdings
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/haridev22/ct-assignement/pkg/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEtherscan(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch query.Get("action") {
+		case "tokenbalance":
+			if query.Get("contractaddress") == "0xbroken" {
+				json.NewEncoder(w).Encode(api.APIResponse{Status: "0", Message: "NOTOK", Result: json.RawMessage(`"Error! Invalid contract address format"`)})
+				return
+			}
+			json.NewEncoder(w).Encode(api.APIResponse{Status: "1", Message: "OK", Result: json.RawMessage(`"250000000"`)})
+		case "eth_call":
+			assert.Equal(t, selectorDecimals, query.Get("data"))
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x0000000000000000000000000000000000000000000000000000000000000006"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := api.NewEtherscanClient("dummy_api_key")
+	client.BaseURL = server.URL
+	source := Etherscan{Client: client}
+
+	balances, err := source.Balances(owner, []string{"0xusdc", "0xbroken"})
+	assert.NoError(t, err)
+	assert.Equal(t, "250000000", balances[0].String())
+	assert.Nil(t, balances[1])
+
+	_, err = source.Balances(owner, []string{"0xbroken"})
+	assert.Error(t, err)
+
+	decimals, err := source.Decimals("0xusdc")
+	assert.NoError(t, err)
+	assert.Equal(t, 6, decimals)
+}
+
+// fakeMulticall answers aggregate3 calls of balanceOf from fixed balances,
+// failing the calls to contracts without a balance
+type fakeMulticall struct {
+	t        *testing.T
+	balances map[string]int64
+	calls    int
+}
+
+func (f *fakeMulticall) CallContract(to, data string) (string, error) {
+	if to != DefaultMulticall {
+		return "", errors.New("unexpected contract " + to)
+	}
+	f.calls++
+	data = strings.TrimPrefix(data, "0x"+selectorAggregate3)
+	word := func(i int) string { return data[i*64 : (i+1)*64] }
+	n := int(mustParse(word(1)))
+
+	var heads, tuples strings.Builder
+	for i := 0; i < n; i++ {
+		// Skip the array offset, length and n tuple offsets to the tuple
+		tuple := 2 + n + i*6
+		contract := "0x" + word(tuple)[24:]
+		callData := data[(tuple+4)*64 : (tuple+4)*64+72]
+		assert.Equal(f.t, selectorBalanceOf+strings.Repeat("0", 24)+owner[2:], callData)
+
+		heads.WriteString(uintWord(n*32 + i*4*32))
+		balance, ok := f.balances[contract]
+		if !ok {
+			tuples.WriteString(uintWord(0) + uintWord(64) + uintWord(0) + uintWord(0))
+			continue
+		}
+		tuples.WriteString(uintWord(1) + uintWord(64) + uintWord(32) + fmt.Sprintf("%064x", balance))
+	}
+	return "0x" + uintWord(32) + uintWord(n) + heads.String() + tuples.String(), nil
+}
+
+func mustParse(word string) int64 {
+	n, _ := new(big.Int).SetString(word, 16)
+	return n.Int64()
+}
+
+func TestMulticall(t *testing.T) {
+	node := &fakeMulticall{t: t, balances: map[string]int64{
+		"0x000000000000000000000000000000000000000a": 10,
+		"0x000000000000000000000000000000000000000c": 30,
+	}}
+	source := Multicall{Node: node, BatchSize: 2}
+
+	balances, err := source.Balances(owner, []string{
+		"0x000000000000000000000000000000000000000A",
+		"0x000000000000000000000000000000000000000b",
+		"0x000000000000000000000000000000000000000c",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, node.calls, "contracts are read in batches")
+	if assert.Len(t, balances, 3) {
+		assert.Equal(t, int64(10), balances[0].Int64())
+		assert.Nil(t, balances[1], "reverted calls have no balance")
+		assert.Equal(t, int64(30), balances[2].Int64())
+	}
+
+	// Invalid contract addresses are not sent to the node
+	balances, err = source.Balances(owner, []string{"0xnot-an-address", "0x000000000000000000000000000000000000000a"})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, node.calls)
+	assert.Nil(t, balances[0])
+	assert.Equal(t, int64(10), balances[1].Int64())
+
+	_, err = source.Balances("0xnot-an-owner", []string{"0x000000000000000000000000000000000000000a"})
+	assert.ErrorContains(t, err, "invalid address")
+}
+
+func TestDecodeAggregate3_Invalid(t *testing.T) {
+	_, err := decodeAggregate3("0x"+uintWord(32)+uintWord(2), 1)
+	assert.ErrorContains(t, err, "expected 1 results")
+
+	_, err = decodeAggregate3("0x"+uintWord(32)+uintWord(1)+uintWord(4096), 1)
+	assert.Error(t, err)
+
+	_, err = decodeAggregate3("0xzz", 1)
+	assert.Error(t, err)
+}
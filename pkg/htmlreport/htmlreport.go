@@ -0,0 +1,175 @@
+// Package htmlreport renders the history of an address as a self-contained
+// HTML page, with summary cards, charts and a sortable table of its rows,
+// for readers who would not open a CSV.
+package htmlreport
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/chains"
+	"github.com/haridev22/ct-assignement/pkg/chart"
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/haridev22/ct-assignement/pkg/report"
+)
+
+// Options adjust a report
+type Options struct {
+	// Title defaults to the address
+	Title string
+	// Chain links the rows without a Chain to its explorer
+	Chain chains.Chain
+	// Generated is printed in the footer, the current time when zero
+	Generated time.Time
+}
+
+// page is the data of the template
+type page struct {
+	Title     string
+	Address   string
+	Generated string
+	Cards     []card
+	// Charts are inline SVG images
+	Charts []template.HTML
+	Types  []typeBar
+	Assets []report.AssetTotal
+	Rows   []row
+}
+
+type card struct {
+	Label  string
+	Value  string
+	Detail string
+}
+
+type row struct {
+	Time      string
+	Unix      int64
+	Hash      string
+	URL       string
+	Block     int64
+	Type      models.TransactionType
+	From      string
+	To        string
+	Direction string
+	Asset     string
+	Value     string
+	Fee       string
+	Failed    bool
+}
+
+type typeBar struct {
+	Type    models.TransactionType
+	Count   int
+	Percent float64
+}
+
+// Write renders the report of address over transactions to w
+func Write(w io.Writer, address string, transactions []models.Transaction, opts Options) error {
+	if opts.Title == "" {
+		opts.Title = address
+	}
+	if opts.Generated.IsZero() {
+		opts.Generated = time.Now()
+	}
+	if opts.Chain.Name == "" {
+		opts.Chain = chains.Ethereum
+	}
+
+	overview := report.Describe(address, transactions)
+	native := overview.Native()
+	p := page{
+		Title:     opts.Title,
+		Address:   address,
+		Generated: opts.Generated.UTC().Format("2006-01-02 15:04 UTC"),
+		Assets:    overview.Assets,
+	}
+	p.Cards = []card{
+		{"Transactions", fmt.Sprint(overview.Total), fmt.Sprintf("%d failed", overview.Failed)},
+		{report.NativeAsset + " in", report.FormatAmount(native.Incoming), ""},
+		{report.NativeAsset + " out", report.FormatAmount(native.Outgoing), "net " + report.FormatAmount(native.Net())},
+		{"Gas spent", report.FormatAmount(overview.GasSpent), report.NativeAsset},
+		{"Counterparties", fmt.Sprint(overview.Counterparties), ""},
+	}
+	if overview.Total > 0 {
+		p.Cards = append(p.Cards, card{"Period", overview.FirstTime.UTC().Format("2006-01-02"), "to " + overview.LastTime.UTC().Format("2006-01-02")})
+	}
+
+	if months := report.Monthly(address, transactions); len(months) > 0 {
+		for _, c := range []chart.Chart{
+			chart.Transactions(months),
+			chart.Flows(months, report.NativeAsset, ""),
+			chart.Gas(months),
+		} {
+			var svg strings.Builder
+			if err := c.SVG(&svg); err != nil {
+				return err
+			}
+			p.Charts = append(p.Charts, template.HTML(svg.String()))
+		}
+	}
+
+	for t, n := range overview.ByType {
+		p.Types = append(p.Types, typeBar{Type: t, Count: n, Percent: 100 * float64(n) / float64(overview.Total)})
+	}
+	sort.Slice(p.Types, func(i, j int) bool {
+		if p.Types[i].Count != p.Types[j].Count {
+			return p.Types[i].Count > p.Types[j].Count
+		}
+		return p.Types[i].Type < p.Types[j].Type
+	})
+
+	p.Rows = make([]row, len(transactions))
+	for i := range transactions {
+		tx := &transactions[i]
+		chain := opts.Chain
+		if tx.Chain != "" {
+			if c, err := chains.Lookup(tx.Chain); err == nil {
+				chain = c
+			}
+		}
+		asset := tx.AssetSymbol
+		if asset == "" && tx.AssetContractAddr == "" {
+			asset = report.NativeAsset
+		}
+		if tx.TokenID != "" {
+			asset += " #" + tx.TokenID
+		}
+		p.Rows[i] = row{
+			Time:      tx.Timestamp.UTC().Format("2006-01-02 15:04:05"),
+			Unix:      tx.Timestamp.Unix(),
+			Hash:      tx.Hash,
+			URL:       chain.TxURL(tx.Hash),
+			Block:     tx.BlockNumber,
+			Type:      tx.Type,
+			From:      tx.From,
+			To:        tx.To,
+			Direction: direction(address, tx),
+			Asset:     asset,
+			Value:     tx.Value,
+			Fee:       tx.GasFee,
+			Failed:    tx.Failed(),
+		}
+	}
+	return pageTemplate.Execute(w, p)
+}
+
+// direction is in, out or self as seen from address, or empty for rows
+// between other addresses
+func direction(address string, tx *models.Transaction) string {
+	incoming := strings.EqualFold(tx.To, address)
+	outgoing := strings.EqualFold(tx.From, address)
+	switch {
+	case incoming && outgoing:
+		return "self"
+	case incoming:
+		return "in"
+	case outgoing:
+		return "out"
+	}
+	return ""
+}
@@ -0,0 +1,51 @@
+package htmlreport
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/chains"
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+const wallet = "0xWallet"
+
+func testRows() []models.Transaction {
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	return []models.Transaction{
+		{Hash: "0xaaaa1111bbbb2222", BlockNumber: 10, Timestamp: day, Type: models.TypeEthTransfer, From: "0xexchange", To: "0xwallet", Value: "2.0", GasFee: "0.001"},
+		{Hash: "0xcccc3333dddd4444", BlockNumber: 20, Timestamp: day.AddDate(0, 1, 0), Type: models.TypeERC20Transfer, From: "0xwallet", To: "0xrouter", AssetContractAddr: "0xusdc", AssetSymbol: "<b>USDC</b>", Value: "100", GasFee: "0.002", Status: models.StatusFailed, Chain: "polygon"},
+	}
+}
+
+func TestWrite(t *testing.T) {
+	var buf bytes.Buffer
+	err := Write(&buf, wallet, testRows(), Options{Title: "Review", Generated: time.Date(2024, 6, 1, 8, 30, 0, 0, time.UTC)})
+	assert.NoError(t, err)
+	page := buf.String()
+
+	assert.Contains(t, page, "<title>Review</title>")
+	assert.Contains(t, page, `<div class="label">Transactions</div><div class="value">2</div><div class="detail">1 failed</div>`)
+	assert.Contains(t, page, `<div class="label">Period</div><div class="value">2024-03-01</div><div class="detail">to 2024-04-01</div>`)
+	assert.Contains(t, page, "<figure><svg")
+	assert.Contains(t, page, "<title>Mar 2024: 2 received</title>", "charts are inlined")
+	assert.Contains(t, page, `<span class="name mono">ETH_TRANSFER</span><span class="bar" style="width:50.0%"></span>1`)
+	assert.Contains(t, page, `href="https://etherscan.io/tx/0xaaaa1111bbbb2222"`, "rows without a chain link to the default explorer")
+	assert.Contains(t, page, `href="https://polygonscan.com/tx/0xcccc3333dddd4444"`)
+	assert.Contains(t, page, `<td class="num in" data-value="2.0">2.0</td>`)
+	assert.Contains(t, page, `<tr class="failed" title="Failed">`)
+	assert.Contains(t, page, "&lt;b&gt;USDC&lt;/b&gt;", "values are escaped")
+	assert.NotContains(t, page, "<b>USDC</b>")
+	assert.Contains(t, page, "Generated 2024-06-01 08:30 UTC")
+	assert.NotContains(t, page, "ZgotmplZ", "no value is rejected by the template escaper")
+}
+
+func TestWrite_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, Write(&buf, wallet, nil, Options{Chain: chains.Ethereum}))
+	assert.Contains(t, buf.String(), "<title>0xWallet</title>")
+	assert.NotContains(t, buf.String(), "<svg", "no chart without rows")
+	assert.NotContains(t, buf.String(), "Transaction types")
+}
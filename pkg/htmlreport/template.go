@@ -0,0 +1,141 @@
+package htmlreport
+
+import (
+	"html/template"
+
+	"github.com/haridev22/ct-assignement/pkg/report"
+)
+
+// pageTemplate is the report page. Styles and scripts are inline so the
+// file can be shared on its own.
+var pageTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"amount": report.FormatAmount,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2rem; color: #1f2328; background: #fff; }
+h1 { font-size: 1.5rem; margin-bottom: 0.25rem; }
+h2 { font-size: 1.1rem; margin-top: 2rem; }
+.address, .mono { font-family: ui-monospace, Menlo, Consolas, monospace; }
+.address { color: #59636e; }
+.cards { display: flex; flex-wrap: wrap; gap: 1rem; margin-top: 1.5rem; }
+.card { border: 1px solid #d1d9e0; border-radius: 6px; padding: 0.75rem 1rem; min-width: 9rem; }
+.card .label { font-size: 0.8rem; color: #59636e; }
+.card .value { font-size: 1.4rem; font-weight: 600; margin: 0.2rem 0; }
+.card .detail { font-size: 0.8rem; color: #59636e; }
+figure { margin: 1rem 0; }
+figure svg { max-width: 100%; height: auto; }
+.types { max-width: 40rem; }
+.types div { display: flex; align-items: center; margin: 0.2rem 0; font-size: 0.85rem; }
+.types .name { width: 11rem; }
+.types .bar { background: #0969da; height: 0.8rem; margin-right: 0.5rem; }
+table { border-collapse: collapse; font-size: 0.85rem; }
+th, td { padding: 0.3rem 0.6rem; border-bottom: 1px solid #d1d9e0; text-align: left; white-space: nowrap; }
+th[data-sort] { cursor: pointer; user-select: none; }
+th[data-sort]::after { content: " \2195"; color: #8c959f; }
+td.num { text-align: right; }
+tr.failed td { color: #8c959f; text-decoration: line-through; }
+td.in { color: #1a7f37; }
+td.out { color: #cf222e; }
+#filter { margin: 0.5rem 0; padding: 0.3rem; width: 20rem; }
+footer { margin-top: 2rem; font-size: 0.8rem; color: #59636e; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<div class="address">{{.Address}}</div>
+
+<div class="cards">
+{{- range .Cards}}
+<div class="card"><div class="label">{{.Label}}</div><div class="value">{{.Value}}</div><div class="detail">{{.Detail}}</div></div>
+{{- end}}
+</div>
+
+{{- if .Charts}}
+<h2>Activity</h2>
+{{- range .Charts}}
+<figure>{{.}}</figure>
+{{- end}}
+{{- end}}
+
+{{- if .Types}}
+<h2>Transaction types</h2>
+<div class="types">
+{{- range .Types}}
+<div><span class="name mono">{{.Type}}</span><span class="bar" style="width:{{printf "%.1f" .Percent}}%"></span>{{.Count}}</div>
+{{- end}}
+</div>
+{{- end}}
+
+{{- if .Assets}}
+<h2>Assets</h2>
+<table>
+<thead><tr><th>Asset</th><th>Contract</th><th>Transfers</th><th>In</th><th>Out</th><th>Net</th></tr></thead>
+<tbody>
+{{- range .Assets}}
+<tr><td>{{.Symbol}}</td><td class="mono">{{.Contract}}</td><td class="num">{{.Count}}</td><td class="num">{{amount .Incoming}}</td><td class="num">{{amount .Outgoing}}</td><td class="num">{{amount .Net}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- end}}
+
+<h2>Transactions</h2>
+<input id="filter" type="search" placeholder="Filter rows">
+<table id="rows">
+<thead><tr>
+<th data-sort="num">Date (UTC)</th><th data-sort="text">Hash</th><th data-sort="num">Block</th><th data-sort="text">Type</th>
+<th data-sort="text">From</th><th data-sort="text">To</th><th data-sort="text">Asset</th><th data-sort="num">Value</th><th data-sort="num">Gas Fee</th>
+</tr></thead>
+<tbody>
+{{- range .Rows}}
+<tr{{if .Failed}} class="failed" title="Failed"{{end}}>
+<td data-value="{{.Unix}}">{{.Time}}</td><td class="mono"><a href="{{.URL}}">{{printf "%.12s" .Hash}}…</a></td><td class="num" data-value="{{.Block}}">{{.Block}}</td><td>{{.Type}}</td>
+<td class="mono">{{.From}}</td><td class="mono">{{.To}}</td><td>{{.Asset}}</td><td class="num {{.Direction}}" data-value="{{.Value}}">{{.Value}}</td><td class="num" data-value="{{.Fee}}">{{.Fee}}</td>
+</tr>
+{{- end}}
+</tbody>
+</table>
+
+<footer>Generated {{.Generated}}</footer>
+
+<script>
+(function () {
+  var table = document.getElementById("rows");
+  var body = table.tBodies[0];
+  var headers = table.tHead.rows[0].cells;
+  function key(row, i, numeric) {
+    var cell = row.cells[i];
+    var value = cell.getAttribute("data-value");
+    if (value === null) value = cell.textContent;
+    return numeric ? parseFloat(value) || 0 : value.toLowerCase();
+  }
+  Array.prototype.forEach.call(headers, function (th, i) {
+    th.addEventListener("click", function () {
+      var numeric = th.getAttribute("data-sort") === "num";
+      var ascending = th.getAttribute("aria-sort") !== "ascending";
+      Array.prototype.forEach.call(headers, function (h) { h.removeAttribute("aria-sort"); });
+      th.setAttribute("aria-sort", ascending ? "ascending" : "descending");
+      var rows = Array.prototype.slice.call(body.rows);
+      rows.sort(function (a, b) {
+        var x = key(a, i, numeric), y = key(b, i, numeric);
+        var order = x < y ? -1 : x > y ? 1 : 0;
+        return ascending ? order : -order;
+      });
+      rows.forEach(function (row) { body.appendChild(row); });
+    });
+  });
+  document.getElementById("filter").addEventListener("input", function (e) {
+    var needle = e.target.value.toLowerCase();
+    Array.prototype.forEach.call(body.rows, function (row) {
+      row.style.display = row.textContent.toLowerCase().indexOf(needle) === -1 ? "none" : "";
+    });
+  });
+})();
+</script>
+</body>
+</html>
+`))
@@ -0,0 +1,133 @@
+// Package manifest describes the files of an export with their SHA-256
+// checksums in a manifest.json next to them, so an audit can verify that
+// the artifacts have not been altered since the run that wrote them.
+package manifest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// FileName is the name of the manifest in the output directory
+const FileName = "manifest.json"
+
+// File is a single file of an export
+type File struct {
+	// Name is the path of the file relative to the manifest
+	Name   string `json:"name"`
+	SHA256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+	// Rows is the number of transactions in an export file, and is left
+	// out for files such as signatures
+	Rows *int `json:"rows,omitempty"`
+}
+
+// Manifest collects the files written by a run. Its methods are safe for
+// concurrent use and do nothing on a nil Manifest, so callers need not
+// check whether a manifest was requested.
+type Manifest struct {
+	mu sync.Mutex
+
+	ToolVersion string    `json:"tool_version"`
+	CreatedAt   time.Time `json:"created_at"`
+	// Provider is where the rows came from: etherscan, json-rpc or store
+	Provider   string   `json:"provider"`
+	Addresses  []string `json:"addresses"`
+	StartBlock int64    `json:"start_block"`
+	EndBlock   int64    `json:"end_block"`
+	// Chains lists the networks of a multichain export, whose block ranges
+	// differ per chain and so are not recorded
+	Chains []string `json:"chains,omitempty"`
+	Files  []File   `json:"files"`
+
+	paths []string
+	rows  []*int
+}
+
+// New starts a manifest of a run of the given tool version
+func New(toolVersion string) *Manifest {
+	return &Manifest{ToolVersion: toolVersion, Addresses: []string{}, Files: []File{}}
+}
+
+// AddExport records an export file of rows transactions
+func (m *Manifest) AddExport(path string, rows int) {
+	m.add(path, &rows)
+}
+
+// AddFile records a file that holds no rows, such as a signature
+func (m *Manifest) AddFile(path string) {
+	m.add(path, nil)
+}
+
+func (m *Manifest) add(path string, rows *int) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.paths = append(m.paths, path)
+	m.rows = append(m.rows, rows)
+}
+
+// Len returns the number of files recorded
+func (m *Manifest) Len() int {
+	if m == nil {
+		return 0
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.paths)
+}
+
+// WriteFile checksums the recorded files and writes the manifest to path.
+// File names are made relative to the directory of path.
+func (m *Manifest) WriteFile(path string) error {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	dir := filepath.Dir(path)
+	m.Files = m.Files[:0]
+	for i, p := range m.paths {
+		sum, size, err := checksum(p)
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(dir, p)
+		if err != nil {
+			name = p
+		}
+		m.Files = append(m.Files, File{Name: filepath.ToSlash(name), SHA256: sum, Size: size, Rows: m.rows[i]})
+	}
+	m.CreatedAt = time.Now().UTC()
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// checksum returns the hex SHA-256 digest and size of the file at path
+func checksum(path string) (string, int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to checksum %s: %w", path, err)
+	}
+	defer file.Close()
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to checksum %s: %w", path, err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), size, nil
+}
@@ -0,0 +1,59 @@
+package manifest
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManifest_WriteFile(t *testing.T) {
+	dir := t.TempDir()
+	export := filepath.Join(dir, "0xabc_tx_history.csv")
+	signature := export + ".asc"
+	assert.NoError(t, os.WriteFile(export, []byte("hello"), 0644))
+	assert.NoError(t, os.WriteFile(signature, []byte("sig"), 0644))
+
+	m := New("v1.0.0")
+	m.Provider = "etherscan"
+	m.Addresses = append(m.Addresses, "0xabc")
+	m.StartBlock, m.EndBlock = 100, 200
+	m.AddExport(export, 2)
+	m.AddFile(signature)
+	assert.Equal(t, 2, m.Len())
+	assert.NoError(t, m.WriteFile(filepath.Join(dir, FileName)))
+
+	data, err := os.ReadFile(filepath.Join(dir, FileName))
+	assert.NoError(t, err)
+	var written Manifest
+	assert.NoError(t, json.Unmarshal(data, &written))
+	assert.Equal(t, "v1.0.0", written.ToolVersion)
+	assert.Equal(t, "etherscan", written.Provider)
+	assert.Equal(t, []string{"0xabc"}, written.Addresses)
+	assert.Equal(t, int64(200), written.EndBlock)
+	if assert.Len(t, written.Files, 2) {
+		assert.Equal(t, "0xabc_tx_history.csv", written.Files[0].Name)
+		// sha256 of "hello"
+		assert.Equal(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", written.Files[0].SHA256)
+		assert.Equal(t, int64(5), written.Files[0].Size)
+		if assert.NotNil(t, written.Files[0].Rows) {
+			assert.Equal(t, 2, *written.Files[0].Rows)
+		}
+		assert.Nil(t, written.Files[1].Rows)
+	}
+}
+
+func TestManifest_MissingFile(t *testing.T) {
+	m := New("v1.0.0")
+	m.AddExport(filepath.Join(t.TempDir(), "gone.csv"), 0)
+	assert.Error(t, m.WriteFile(filepath.Join(t.TempDir(), FileName)))
+}
+
+func TestNilManifest(t *testing.T) {
+	var m *Manifest
+	m.AddExport("x", 1)
+	assert.Equal(t, 0, m.Len())
+	assert.NoError(t, m.WriteFile("x"))
+}
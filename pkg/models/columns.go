@@ -0,0 +1,275 @@
+package models
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/chains"
+)
+
+// Column describes a single exportable column
+type Column struct {
+	Key    string
+	Header string
+	Type   ColumnType
+	// AddedIn is the schema version that introduced the column
+	AddedIn int
+	Value   func(t *Transaction) string
+	// Set parses a value written by Value back into the transaction
+	Set func(t *Transaction, value string) error
+}
+
+// DefaultColumns are the columns written when no selection is made
+var DefaultColumns = []Column{
+	stringColumn("hash", "Transaction Hash", ColumnHash, 1, func(t *Transaction) *string { return &t.Hash }),
+	{
+		Key: "block", Header: "Block Number", Type: ColumnInteger, AddedIn: 2,
+		Value: func(t *Transaction) string { return strconv.FormatInt(t.BlockNumber, 10) },
+		Set: func(t *Transaction, value string) (err error) {
+			t.BlockNumber, err = strconv.ParseInt(value, 10, 64)
+			return err
+		},
+	},
+	TimestampColumn(time.Local, time.RFC3339),
+	stringColumn("from", "From Address", ColumnAddress, 1, func(t *Transaction) *string { return &t.From }),
+	stringColumn("to", "To Address", ColumnAddress, 1, func(t *Transaction) *string { return &t.To }),
+	{
+		Key: "type", Header: "Transaction Type", Type: ColumnEnum, AddedIn: 1,
+		Value: func(t *Transaction) string { return string(t.Type) },
+		Set: func(t *Transaction, value string) error {
+			t.Type = TransactionType(value)
+			return nil
+		},
+	},
+	stringColumn("contract", "Asset Contract Address", ColumnAddress, 1, func(t *Transaction) *string { return &t.AssetContractAddr }),
+	stringColumn("symbol", "Asset Symbol / Name", ColumnString, 1, func(t *Transaction) *string { return &t.AssetSymbol }),
+	stringColumn("token_id", "Token ID", ColumnInteger, 1, func(t *Transaction) *string { return &t.TokenID }),
+	stringColumn("value", "Value / Amount", ColumnDecimal, 1, func(t *Transaction) *string { return &t.Value }),
+	stringColumn("gas_fee", "Gas Fee (ETH)", ColumnDecimal, 1, func(t *Transaction) *string { return &t.GasFee }),
+	{
+		Key: "status", Header: "Status", Type: ColumnEnum, AddedIn: 3,
+		Value: func(t *Transaction) string { return string(t.Status) },
+		Set: func(t *Transaction, value string) error {
+			t.Status = TransactionStatus(value)
+			return nil
+		},
+	},
+}
+
+// OptionalColumns can be appended to the default set on request
+var OptionalColumns = []Column{
+	stringColumn("nonce", "Nonce", ColumnInteger, 2, func(t *Transaction) *string { return &t.Nonce }),
+	stringColumn("gas_limit", "Gas Limit", ColumnInteger, 2, func(t *Transaction) *string { return &t.GasLimit }),
+	stringColumn("tx_index", "Transaction Index", ColumnInteger, 2, func(t *Transaction) *string { return &t.TransactionIndex }),
+	stringColumn("effective_gas_price", "Effective Gas Price (Wei)", ColumnInteger, 3, func(t *Transaction) *string { return &t.EffectiveGasPrice }),
+	stringColumn("base_fee", "Base Fee Burned (ETH)", ColumnDecimal, 3, func(t *Transaction) *string { return &t.BaseFee }),
+	stringColumn("priority_fee", "Priority Fee (ETH)", ColumnDecimal, 3, func(t *Transaction) *string { return &t.PriorityFee }),
+	stringColumn("notes", "Notes", ColumnString, 4, func(t *Transaction) *string { return &t.Notes }),
+	stringColumn("parent_hash", "Parent Transaction Hash", ColumnHash, 5, func(t *Transaction) *string { return &t.ParentHash }),
+	stringColumn("trace_id", "Trace ID", ColumnString, 5, func(t *Transaction) *string { return &t.TraceID }),
+	stringColumn("call_type", "Call Type", ColumnEnum, 5, func(t *Transaction) *string { return &t.CallType }),
+	stringColumn("raw_value", "Raw Value (Smallest Unit)", ColumnInteger, 6, func(t *Transaction) *string { return &t.RawValue }),
+	stringColumn("raw_gas_fee", "Raw Gas Fee (Wei)", ColumnInteger, 6, func(t *Transaction) *string { return &t.RawGasFee }),
+	gweiColumn("gas_price_gwei", "Gas Price (Gwei)", 7, func(t *Transaction) *string { return &t.GasPrice }),
+	gweiColumn("gas_fee_gwei", "Gas Fee (Gwei)", 7, func(t *Transaction) *string { return &t.RawGasFee }),
+	{
+		Key: "unix_time", Header: "Unix Timestamp", Type: ColumnInteger, AddedIn: 8,
+		Value: func(t *Transaction) string { return strconv.FormatInt(t.Timestamp.Unix(), 10) },
+		Set: func(t *Transaction, value string) error {
+			seconds, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return err
+			}
+			t.Timestamp = time.Unix(seconds, 0)
+			return nil
+		},
+	},
+	stringColumn("chain", "Chain", ColumnEnum, 9, func(t *Transaction) *string { return &t.Chain }),
+	{
+		Key: "explorer_url", Header: "Explorer Link", Type: ColumnString, AddedIn: 10,
+		Value: explorerURL,
+		// The link is derived from the hash and chain, so there is nothing to read back
+		Set: func(t *Transaction, value string) error { return nil },
+	},
+	stringColumn("l1_fee", "L1 Data Fee (ETH)", ColumnDecimal, 11, func(t *Transaction) *string { return &t.L1Fee }),
+	{
+		Key: "total_fee", Header: "Total Fee (ETH)", Type: ColumnDecimal, AddedIn: 11,
+		Value: totalFee,
+		// The total is derived from the gas and L1 fees, so there is nothing to read back
+		Set: func(t *Transaction, value string) error { return nil },
+	},
+	stringColumn("alert", "Alert", ColumnString, 12, func(t *Transaction) *string { return &t.Alert }),
+	stringColumn("balance", "Running Balance (ETH)", ColumnDecimal, 13, func(t *Transaction) *string { return &t.Balance }),
+}
+
+// totalFee is the gas fee plus the L1 data fee of OP-stack rows. Rows without
+// an L1 fee total their gas fee.
+func totalFee(t *Transaction) string {
+	if t.L1Fee == "" {
+		return t.GasFee
+	}
+	total, ok := new(big.Rat).SetString(t.L1Fee)
+	if !ok {
+		return ""
+	}
+	if t.GasFee != "" {
+		gasFee, ok := new(big.Rat).SetString(t.GasFee)
+		if !ok {
+			return ""
+		}
+		total.Add(total, gasFee)
+	}
+	return total.FloatString(18)
+}
+
+// explorerURL links a row to its transaction, or to its block for rows
+// without a hash, on the explorer of its chain. Rows without a chain are
+// Ethereum rows; chains missing from the registry get no link.
+func explorerURL(t *Transaction) string {
+	chain := chains.Ethereum
+	if t.Chain != "" {
+		var err error
+		if chain, err = chains.Lookup(t.Chain); err != nil {
+			return ""
+		}
+	}
+	if t.Hash == "" {
+		return chain.BlockURL(t.BlockNumber)
+	}
+	return chain.TxURL(t.Hash)
+}
+
+// stringColumn builds a column backed directly by a string field
+func stringColumn(key, header string, typ ColumnType, addedIn int, field func(t *Transaction) *string) Column {
+	return Column{
+		Key:     key,
+		Header:  header,
+		Type:    typ,
+		AddedIn: addedIn,
+		Value:   func(t *Transaction) string { return *field(t) },
+		Set: func(t *Transaction, value string) error {
+			*field(t) = value
+			return nil
+		},
+	}
+}
+
+// TimeFormats are the named layouts accepted by ParseTimeFormat
+var TimeFormats = map[string]string{
+	"rfc3339":  time.RFC3339,
+	"datetime": time.DateTime,
+	"date":     time.DateOnly,
+}
+
+// ParseTimeFormat resolves a named time format, or returns a Go reference
+// time layout such as "02.01.2006 15:04" unchanged
+func ParseTimeFormat(s string) (string, error) {
+	if layout, ok := TimeFormats[strings.ToLower(s)]; ok {
+		return layout, nil
+	}
+	if !strings.Contains(s, "2006") {
+		return "", fmt.Errorf("unknown time format %q (use rfc3339, datetime, date or a Go layout such as \"02.01.2006 15:04\")", s)
+	}
+	return s, nil
+}
+
+// TimestampColumn builds the Date & Time column for timestamps shown in loc
+// with layout. Values are parsed back in loc, so a layout without a zone
+// offset still round-trips.
+func TimestampColumn(loc *time.Location, layout string) Column {
+	return Column{
+		Key: "timestamp", Header: "Date & Time", Type: ColumnTimestamp, AddedIn: 1,
+		Value: func(t *Transaction) string { return t.Timestamp.In(loc).Format(layout) },
+		Set: func(t *Transaction, value string) (err error) {
+			t.Timestamp, err = time.ParseInLocation(layout, value, loc)
+			return err
+		},
+	}
+}
+
+// weiPerGwei is the number of wei in one Gwei
+var weiPerGwei = new(big.Rat).SetInt64(1_000_000_000)
+
+// gweiColumn builds a column showing a wei amount field in Gwei. Values
+// are exact, with nine decimal places.
+func gweiColumn(key, header string, addedIn int, field func(t *Transaction) *string) Column {
+	return Column{
+		Key:     key,
+		Header:  header,
+		Type:    ColumnDecimal,
+		AddedIn: addedIn,
+		Value: func(t *Transaction) string {
+			wei, ok := new(big.Rat).SetString(*field(t))
+			if !ok {
+				return ""
+			}
+			return wei.Quo(wei, weiPerGwei).FloatString(9)
+		},
+		Set: func(t *Transaction, value string) error {
+			if value == "" {
+				*field(t) = ""
+				return nil
+			}
+			gwei, ok := new(big.Rat).SetString(value)
+			if !ok {
+				return fmt.Errorf("invalid Gwei amount %q", value)
+			}
+			wei := gwei.Mul(gwei, weiPerGwei)
+			if !wei.IsInt() {
+				return fmt.Errorf("amount %q in Gwei is not a whole number of wei", value)
+			}
+			*field(t) = wei.Num().String()
+			return nil
+		},
+	}
+}
+
+// LookupColumn finds a default or optional column by key
+func LookupColumn(key string) (Column, bool) {
+	for _, set := range [][]Column{DefaultColumns, OptionalColumns} {
+		for _, col := range set {
+			if col.Key == key {
+				return col, true
+			}
+		}
+	}
+	return Column{}, false
+}
+
+// ParseColumnKeys resolves a comma-separated list of column keys
+func ParseColumnKeys(list string) ([]Column, error) {
+	var cols []Column
+	for _, key := range strings.Split(list, ",") {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+		col, ok := LookupColumn(key)
+		if !ok {
+			return nil, fmt.Errorf("unknown column %q", key)
+		}
+		cols = append(cols, col)
+	}
+	return cols, nil
+}
+
+// Headers returns the header row for the given columns
+func Headers(cols []Column) []string {
+	headers := make([]string, len(cols))
+	for i, col := range cols {
+		headers[i] = col.Header
+	}
+	return headers
+}
+
+// Record converts a transaction to a row for the given columns
+func (t *Transaction) Record(cols []Column) []string {
+	record := make([]string, len(cols))
+	for i, col := range cols {
+		record[i] = col.Value(t)
+	}
+	return record
+}
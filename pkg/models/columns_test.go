@@ -0,0 +1,113 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseColumnKeys(t *testing.T) {
+	cols, err := ParseColumnKeys("nonce, gas_limit,tx_index")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Nonce", "Gas Limit", "Transaction Index"}, Headers(cols))
+
+	cols, err = ParseColumnKeys("")
+	assert.NoError(t, err)
+	assert.Empty(t, cols)
+
+	_, err = ParseColumnKeys("nonce,bogus")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "bogus")
+}
+
+func TestTransaction_Record(t *testing.T) {
+	tx := Transaction{
+		Hash:             "0xabc",
+		Nonce:            "7",
+		GasLimit:         "21000",
+		TransactionIndex: "3",
+	}
+
+	cols, err := ParseColumnKeys("hash,tx_index,nonce,gas_limit")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0xabc", "3", "7", "21000"}, tx.Record(cols))
+}
+
+func TestGweiColumns(t *testing.T) {
+	tx := Transaction{GasPrice: "20000000001", RawGasFee: "420000000021000"}
+
+	cols, err := ParseColumnKeys("gas_price_gwei,gas_fee_gwei")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"20.000000001", "420000.000021000"}, tx.Record(cols))
+
+	var parsed Transaction
+	assert.NoError(t, cols[0].Set(&parsed, "20.000000001"))
+	assert.NoError(t, cols[1].Set(&parsed, "420000.000021000"))
+	assert.Equal(t, tx, parsed)
+
+	// Internal transfers carry no gas price
+	assert.Equal(t, "", cols[0].Value(&Transaction{}))
+	assert.Error(t, cols[0].Set(&parsed, "0.0000000001"))
+}
+
+func TestTimestampColumn(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	assert.NoError(t, err)
+	layout, err := ParseTimeFormat("datetime")
+	assert.NoError(t, err)
+	col := TimestampColumn(berlin, layout)
+
+	tx := Transaction{Timestamp: time.Date(2024, 7, 1, 22, 30, 0, 0, time.UTC)}
+	assert.Equal(t, "2024-07-02 00:30:00", col.Value(&tx))
+
+	var parsed Transaction
+	assert.NoError(t, col.Set(&parsed, "2024-07-02 00:30:00"))
+	assert.True(t, tx.Timestamp.Equal(parsed.Timestamp))
+}
+
+func TestParseTimeFormat(t *testing.T) {
+	layout, err := ParseTimeFormat("RFC3339")
+	assert.NoError(t, err)
+	assert.Equal(t, time.RFC3339, layout)
+
+	layout, err = ParseTimeFormat("02.01.2006 15:04")
+	assert.NoError(t, err)
+	assert.Equal(t, "02.01.2006 15:04", layout)
+
+	_, err = ParseTimeFormat("excel")
+	assert.Error(t, err)
+}
+
+func TestUnixTimeColumn(t *testing.T) {
+	cols, err := ParseColumnKeys("timestamp,unix_time")
+	assert.NoError(t, err)
+	tx := Transaction{Timestamp: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	assert.Equal(t, "1704067200", tx.Record(cols)[1])
+
+	var parsed Transaction
+	assert.NoError(t, cols[1].Set(&parsed, "1704067200"))
+	assert.True(t, tx.Timestamp.Equal(parsed.Timestamp))
+	assert.Error(t, cols[1].Set(&parsed, "2024-01-01"))
+}
+
+func TestExplorerURLColumn(t *testing.T) {
+	col, ok := LookupColumn("explorer_url")
+	assert.True(t, ok)
+
+	assert.Equal(t, "https://etherscan.io/tx/0xabc", col.Value(&Transaction{Hash: "0xabc"}))
+	assert.Equal(t, "https://polygonscan.com/tx/0xabc", col.Value(&Transaction{Hash: "0xabc", Chain: "polygon"}))
+	assert.Equal(t, "https://basescan.org/block/42", col.Value(&Transaction{BlockNumber: 42, Chain: "base"}))
+	assert.Empty(t, col.Value(&Transaction{Hash: "0xabc", Chain: "324"}))
+}
+
+func TestTotalFeeColumn(t *testing.T) {
+	cols, err := ParseColumnKeys("l1_fee,total_fee")
+	assert.NoError(t, err)
+
+	tx := Transaction{GasFee: "0.000021000000000000", L1Fee: "0.000150000000000001"}
+	assert.Equal(t, []string{"0.000150000000000001", "0.000171000000000001"}, tx.Record(cols))
+
+	tx = Transaction{GasFee: "0.000021000000000000"}
+	assert.Equal(t, []string{"", "0.000021000000000000"}, tx.Record(cols))
+}
@@ -0,0 +1,3 @@
+// Package models defines the provider-independent transaction model that
+// every exporter consumes.
+package models
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// RowID returns a deterministic identifier for the logical row a
+// transaction represents. A single hash can produce several rows (an ETH
+// transfer plus token transfers, for example), so the ID also covers the
+// row type, parties, asset and token ID. Values and fees are deliberately
+// excluded so that a corrected row keeps its identity. Rows without a hash,
+// such as block rewards, are identified by their block instead. Internal
+// transfers carrying a trace ID are told apart by it, since one transaction
+// can move value between the same parties more than once. Rows of chains
+// other than Ethereum are told apart by their chain too, so rows of several
+// chains can share a file, while Ethereum rows keep the IDs they had before
+// chains were recorded. Rows that match in all of these are told apart by
+// RowIDs.
+func (t *Transaction) RowID() string {
+	return rowID(t.rowKey())
+}
+
+// RowIDs returns the row IDs of transactions, which must hold every row of
+// the transactions they cover, in the order the provider returned them.
+// Explorers give token and ETH rows no log index, so two transfers of the
+// same asset between the same parties in one transaction share a RowID.
+// The first of them keeps it; later ones are told apart by their ordinal
+// among those rows, so neither replaces the other.
+func RowIDs(transactions []Transaction) []string {
+	ids := make([]string, len(transactions))
+	seen := make(map[string]int, len(transactions))
+	for i := range transactions {
+		key := transactions[i].rowKey()
+		ordinal := seen[key]
+		seen[key]++
+		if ordinal > 0 {
+			key += "|ordinal:" + strconv.Itoa(ordinal)
+		}
+		ids[i] = rowID(key)
+	}
+	return ids
+}
+
+// rowKey returns the fields that identify the row, joined
+func (t *Transaction) rowKey() string {
+	hash := strings.ToLower(t.Hash)
+	if hash == "" {
+		hash = "block:" + strconv.FormatInt(t.BlockNumber, 10)
+	}
+	key := strings.Join([]string{
+		hash,
+		string(t.Type),
+		strings.ToLower(t.From),
+		strings.ToLower(t.To),
+		strings.ToLower(t.AssetContractAddr),
+		t.TokenID,
+	}, "|")
+	if t.TraceID != "" {
+		key += "|" + t.TraceID
+	}
+	if t.Chain != "" && t.Chain != "ethereum" {
+		key += "|chain:" + t.Chain
+	}
+	return key
+}
+
+func rowID(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:16])
+}
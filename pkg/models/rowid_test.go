@@ -0,0 +1,71 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransaction_RowID(t *testing.T) {
+	tx := Transaction{Hash: "0xABC", Type: TypeEthTransfer, From: "0x1", To: "0x2", Value: "1"}
+
+	// Stable and case-insensitive on hex fields
+	lower := tx
+	lower.Hash = "0xabc"
+	assert.Equal(t, tx.RowID(), lower.RowID())
+
+	// Corrections to value or fee keep the identity
+	corrected := tx
+	corrected.Value = "2"
+	corrected.GasFee = "0.1"
+	assert.Equal(t, tx.RowID(), corrected.RowID())
+
+	// Different row type in the same transaction gets its own identity
+	token := tx
+	token.Type = TypeERC20Transfer
+	token.AssetContractAddr = "0xtoken"
+	assert.NotEqual(t, tx.RowID(), token.RowID())
+}
+
+func TestTransaction_RowID_Hashless(t *testing.T) {
+	reward1 := Transaction{Type: TypeBlockReward, To: "0xminer", BlockNumber: 100}
+	reward2 := Transaction{Type: TypeBlockReward, To: "0xminer", BlockNumber: 101}
+	assert.NotEqual(t, reward1.RowID(), reward2.RowID())
+}
+
+func TestTransaction_RowID_Trace(t *testing.T) {
+	refund1 := Transaction{Hash: "0xabc", Type: TypeInternalTx, From: "0xpool", To: "0xme", TraceID: "0_1"}
+	refund2 := refund1
+	refund2.TraceID = "0_2"
+	assert.NotEqual(t, refund1.RowID(), refund2.RowID())
+
+	// Rows without a trace keep the identity they had before traces were recorded
+	untraced := Transaction{Hash: "0xabc", Type: TypeEthTransfer, From: "0x1", To: "0x2"}
+	assert.Equal(t, "c3073d4ab4176c67", untraced.RowID()[:16])
+}
+
+func TestTransaction_RowID_Chain(t *testing.T) {
+	mainnet := Transaction{Hash: "0xabc", Type: TypeEthTransfer, From: "0x1", To: "0x2", Chain: "ethereum"}
+	polygon := mainnet
+	polygon.Chain = "polygon"
+	assert.NotEqual(t, mainnet.RowID(), polygon.RowID())
+
+	// Ethereum rows keep the identity they had before chains were recorded
+	assert.Equal(t, "c3073d4ab4176c67", mainnet.RowID()[:16])
+}
+
+func TestRowIDs(t *testing.T) {
+	first := Transaction{Hash: "0xabc", Type: TypeERC20Transfer, From: "0x1", To: "0x2", AssetContractAddr: "0xusdc", Value: "10"}
+	second := first
+	second.Value = "25"
+	other := first
+	other.To = "0x3"
+
+	ids := RowIDs([]Transaction{first, second, other})
+	assert.Equal(t, first.RowID(), ids[0], "the first row keeps its RowID")
+	assert.NotEqual(t, ids[0], ids[1], "a second transfer between the same parties gets its own ID")
+	assert.Equal(t, other.RowID(), ids[2])
+
+	// The ordinal follows the order of the rows, not their values
+	assert.Equal(t, ids, RowIDs([]Transaction{first, second, other}))
+}
@@ -0,0 +1,77 @@
+package models
+
+import "fmt"
+
+// SchemaVersion is the version of the CSV schema written by this build.
+// The column registry (DefaultColumns and OptionalColumns) is the changelog:
+// each column records the version that introduced it.
+//
+//	1  original ten columns
+//	2  Block Number; optional nonce, gas_limit and tx_index
+//	3  Status; optional effective_gas_price, base_fee and priority_fee
+//	4  optional notes
+//	5  optional parent_hash, trace_id and call_type
+//	6  optional raw_value and raw_gas_fee
+//	7  optional gas_price_gwei and gas_fee_gwei
+//	8  optional unix_time
+//	9  optional chain
+//	10 optional explorer_url
+//	11 optional l1_fee and total_fee
+//	12 optional alert
+//	13 optional balance
+const SchemaVersion = 13
+
+// ColumnType is the data type of a column's values
+type ColumnType string
+
+const (
+	ColumnString    ColumnType = "string"
+	ColumnInteger   ColumnType = "integer"
+	ColumnDecimal   ColumnType = "decimal"
+	ColumnTimestamp ColumnType = "timestamp"
+	ColumnAddress   ColumnType = "address"
+	ColumnHash      ColumnType = "hash"
+	ColumnEnum      ColumnType = "enum"
+)
+
+// Schema returns every registered column, defaults first
+func Schema() []Column {
+	cols := make([]Column, 0, len(DefaultColumns)+len(OptionalColumns))
+	cols = append(cols, DefaultColumns...)
+	return append(cols, OptionalColumns...)
+}
+
+// LookupHeader finds a registered column by its CSV header
+func LookupHeader(header string) (Column, bool) {
+	for _, col := range Schema() {
+		if col.Header == header {
+			return col, true
+		}
+	}
+	return Column{}, false
+}
+
+// ColumnsForHeaders resolves the header row of an export to registered columns
+func ColumnsForHeaders(headers []string) ([]Column, error) {
+	cols := make([]Column, len(headers))
+	for i, header := range headers {
+		col, ok := LookupHeader(header)
+		if !ok {
+			return nil, fmt.Errorf("unknown column header %q", header)
+		}
+		cols[i] = col
+	}
+	return cols, nil
+}
+
+// DetectSchemaVersion returns the oldest schema version that can have
+// produced an export with the given columns
+func DetectSchemaVersion(cols []Column) int {
+	version := 1
+	for _, col := range cols {
+		if col.AddedIn > version {
+			version = col.AddedIn
+		}
+	}
+	return version
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchema_Registry(t *testing.T) {
+	keys := make(map[string]bool)
+	headers := make(map[string]bool)
+	for _, col := range Schema() {
+		assert.False(t, keys[col.Key], "duplicate key %s", col.Key)
+		assert.False(t, headers[col.Header], "duplicate header %s", col.Header)
+		keys[col.Key] = true
+		headers[col.Header] = true
+
+		assert.NotEmpty(t, col.Type, col.Key)
+		assert.NotNil(t, col.Set, col.Key)
+		assert.True(t, col.AddedIn >= 1 && col.AddedIn <= SchemaVersion, col.Key)
+	}
+}
+
+func TestDetectSchemaVersion(t *testing.T) {
+	original := []string{
+		"Transaction Hash", "Date & Time", "From Address", "To Address", "Transaction Type",
+		"Asset Contract Address", "Asset Symbol / Name", "Token ID", "Value / Amount", "Gas Fee (ETH)",
+	}
+	cols, err := ColumnsForHeaders(original)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, DetectSchemaVersion(cols))
+
+	assert.Equal(t, SchemaVersion, DetectSchemaVersion(Schema()))
+
+	_, err = ColumnsForHeaders([]string{"Transaction Hash", "Mystery"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Mystery")
+}
+
+func TestColumn_SetRoundTrip(t *testing.T) {
+	tx := Transaction{Hash: "0xabc", BlockNumber: 42, Type: TypeERC20Transfer, Status: StatusFailed, Nonce: "7"}
+
+	var parsed Transaction
+	for _, col := range Schema() {
+		assert.NoError(t, col.Set(&parsed, col.Value(&tx)), col.Key)
+	}
+	assert.Equal(t, tx.Record(Schema()), parsed.Record(Schema()))
+}
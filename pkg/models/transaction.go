@@ -14,50 +14,82 @@ const (
 	TypeERC1155Transfer TransactionType = "ERC1155_TRANSFER"
 	TypeContractCall    TransactionType = "CONTRACT_CALL"
 	TypeInternalTx      TransactionType = "INTERNAL_TRANSFER"
+	TypeBlockReward     TransactionType = "BLOCK_REWARD"
+)
+
+// TransactionTypes lists every transaction type
+var TransactionTypes = []TransactionType{
+	TypeEthTransfer, TypeERC20Transfer, TypeERC721Transfer, TypeERC1155Transfer,
+	TypeContractCall, TypeInternalTx, TypeBlockReward,
+}
+
+// TransactionStatus represents the execution outcome of a transaction
+type TransactionStatus string
+
+const (
+	StatusSuccess TransactionStatus = "SUCCESS"
+	StatusFailed  TransactionStatus = "FAILED"
 )
 
 // Transaction represents a processed transaction ready for CSV export
 type Transaction struct {
-	Hash              string        `json:"hash"`
-	Timestamp         time.Time     `json:"timestamp"`
-	From              string        `json:"from"`
-	To                string        `json:"to"`
-	Type              TransactionType `json:"type"`
-	AssetContractAddr string        `json:"asset_contract_address,omitempty"`
-	AssetSymbol       string        `json:"asset_symbol,omitempty"`
-	TokenID           string        `json:"token_id,omitempty"`
-	Value             string        `json:"value"`
-	GasFee            string        `json:"gas_fee"`
+	Hash              string            `json:"hash"`
+	BlockNumber       int64             `json:"block_number"`
+	Timestamp         time.Time         `json:"timestamp"`
+	From              string            `json:"from"`
+	To                string            `json:"to"`
+	Type              TransactionType   `json:"type"`
+	AssetContractAddr string            `json:"asset_contract_address,omitempty"`
+	AssetSymbol       string            `json:"asset_symbol,omitempty"`
+	TokenID           string            `json:"token_id,omitempty"`
+	Value             string            `json:"value"`
+	GasFee            string            `json:"gas_fee"`
+	Status            TransactionStatus `json:"status"`
+	Nonce             string            `json:"nonce,omitempty"`
+	GasLimit          string            `json:"gas_limit,omitempty"`
+	TransactionIndex  string            `json:"transaction_index,omitempty"`
+	EffectiveGasPrice string            `json:"effective_gas_price,omitempty"`
+	BaseFee           string            `json:"base_fee,omitempty"`
+	PriorityFee       string            `json:"priority_fee,omitempty"`
+	// RawValue and RawGasFee are Value and GasFee as integers in the
+	// smallest unit of the asset (wei for ETH), as the provider reports them
+	RawValue  string `json:"raw_value,omitempty"`
+	RawGasFee string `json:"raw_gas_fee,omitempty"`
+	// GasPrice is the price paid per unit of gas in wei
+	GasPrice string `json:"gas_price,omitempty"`
+	// ParentHash is set on internal transfers to the hash of the originating
+	// transaction, with TraceID locating the call within its trace
+	ParentHash string `json:"parent_hash,omitempty"`
+	TraceID    string `json:"trace_id,omitempty"`
+	// CallType is the kind of internal call, such as call or create
+	CallType string `json:"call_type,omitempty"`
+	// Notes flags rows whose provider data was corrected or looks suspect
+	Notes string `json:"notes,omitempty"`
+	// L1Fee is the L1 data fee of a transaction on an OP-stack chain, in
+	// the native currency, charged on top of GasFee
+	L1Fee string `json:"l1_fee,omitempty"`
+	// Chain is the network of the transaction, such as ethereum or polygon,
+	// so rows of several chains can share a file
+	Chain string `json:"chain,omitempty"`
+	// Alert names the alert rules the row matched, separated by semicolons
+	Alert string `json:"alert,omitempty"`
+	// Balance is the native balance of the exported address after the row,
+	// replayed from the rows before it
+	Balance string `json:"balance,omitempty"`
+}
+
+// Failed reports whether the transaction reverted. Gas is still charged
+// for failed transactions.
+func (t *Transaction) Failed() bool {
+	return t.Status == StatusFailed
 }
 
 // CSVRecord converts a transaction to a slice of strings for CSV output
 func (t *Transaction) CSVRecord() []string {
-	return []string{
-		t.Hash,
-		t.Timestamp.Format(time.RFC3339),
-		t.From,
-		t.To,
-		string(t.Type),
-		t.AssetContractAddr,
-		t.AssetSymbol,
-		t.TokenID,
-		t.Value,
-		t.GasFee,
-	}
+	return t.Record(DefaultColumns)
 }
 
 // CSVHeaders returns the CSV header row
 func CSVHeaders() []string {
-	return []string{
-		"Transaction Hash",
-		"Date & Time",
-		"From Address",
-		"To Address",
-		"Transaction Type",
-		"Asset Contract Address",
-		"Asset Symbol / Name",
-		"Token ID",
-		"Value / Amount",
-		"Gas Fee (ETH)",
-	}
+	return Headers(DefaultColumns)
 }
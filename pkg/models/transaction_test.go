@@ -11,6 +11,7 @@ func TestTransaction_CSVRecord(t *testing.T) {
 	// Test case: Complete transaction with all fields
 	tx := Transaction{
 		Hash:              "0xabc123",
+		BlockNumber:       16830000,
 		Timestamp:         time.Date(2023, 3, 15, 12, 30, 45, 0, time.UTC),
 		From:              "0xsender",
 		To:                "0xreceiver",
@@ -20,62 +21,77 @@ func TestTransaction_CSVRecord(t *testing.T) {
 		TokenID:           "42",
 		Value:             "1.500000000000000000",
 		GasFee:            "0.000210000000000000",
+		Status:            StatusSuccess,
 	}
 
 	record := tx.CSVRecord()
 
 	// Check each field in the CSV record
 	assert.Equal(t, "0xabc123", record[0], "Transaction hash should match")
-	assert.Equal(t, "2023-03-15T12:30:45Z", record[1], "Timestamp format should be RFC3339")
-	assert.Equal(t, "0xsender", record[2], "From address should match")
-	assert.Equal(t, "0xreceiver", record[3], "To address should match")
-	assert.Equal(t, "ETH_TRANSFER", record[4], "Transaction type should match")
-	assert.Equal(t, "0xcontract", record[5], "Asset contract address should match")
-	assert.Equal(t, "ETH", record[6], "Asset symbol should match")
-	assert.Equal(t, "42", record[7], "Token ID should match")
-	assert.Equal(t, "1.500000000000000000", record[8], "Value should match")
-	assert.Equal(t, "0.000210000000000000", record[9], "Gas fee should match")
+	assert.Equal(t, "16830000", record[1], "Block number should match")
+	assert.Equal(t, "2023-03-15T12:30:45Z", record[2], "Timestamp format should be RFC3339")
+	assert.Equal(t, "0xsender", record[3], "From address should match")
+	assert.Equal(t, "0xreceiver", record[4], "To address should match")
+	assert.Equal(t, "ETH_TRANSFER", record[5], "Transaction type should match")
+	assert.Equal(t, "0xcontract", record[6], "Asset contract address should match")
+	assert.Equal(t, "ETH", record[7], "Asset symbol should match")
+	assert.Equal(t, "42", record[8], "Token ID should match")
+	assert.Equal(t, "1.500000000000000000", record[9], "Value should match")
+	assert.Equal(t, "0.000210000000000000", record[10], "Gas fee should match")
+	assert.Equal(t, "SUCCESS", record[11], "Status should match")
 
 	// Test case: Minimal transaction with only required fields
 	minimalTx := Transaction{
-		Hash:      "0xdef456",
-		Timestamp: time.Date(2023, 3, 16, 0, 0, 0, 0, time.UTC),
-		From:      "0xminimal",
-		To:        "0xminimal",
-		Type:      TypeInternalTx,
-		Value:     "0.1",
-		GasFee:    "0",
+		Hash:        "0xdef456",
+		BlockNumber: 16830001,
+		Timestamp:   time.Date(2023, 3, 16, 0, 0, 0, 0, time.UTC),
+		From:        "0xminimal",
+		To:          "0xminimal",
+		Type:        TypeInternalTx,
+		Value:       "0.1",
+		GasFee:      "0",
+		Status:      StatusFailed,
 	}
 
 	minimalRecord := minimalTx.CSVRecord()
 	
 	assert.Equal(t, "0xdef456", minimalRecord[0], "Transaction hash should match")
-	assert.Equal(t, "2023-03-16T00:00:00Z", minimalRecord[1], "Timestamp format should be RFC3339")
-	assert.Equal(t, "0xminimal", minimalRecord[2], "From address should match")
-	assert.Equal(t, "0xminimal", minimalRecord[3], "To address should match")
-	assert.Equal(t, "INTERNAL_TRANSFER", minimalRecord[4], "Transaction type should match")
-	assert.Equal(t, "", minimalRecord[5], "Asset contract address should be empty")
-	assert.Equal(t, "", minimalRecord[6], "Asset symbol should be empty")
-	assert.Equal(t, "", minimalRecord[7], "Token ID should be empty")
-	assert.Equal(t, "0.1", minimalRecord[8], "Value should match")
-	assert.Equal(t, "0", minimalRecord[9], "Gas fee should match")
+	assert.Equal(t, "16830001", minimalRecord[1], "Block number should match")
+	assert.Equal(t, "2023-03-16T00:00:00Z", minimalRecord[2], "Timestamp format should be RFC3339")
+	assert.Equal(t, "0xminimal", minimalRecord[3], "From address should match")
+	assert.Equal(t, "0xminimal", minimalRecord[4], "To address should match")
+	assert.Equal(t, "INTERNAL_TRANSFER", minimalRecord[5], "Transaction type should match")
+	assert.Equal(t, "", minimalRecord[6], "Asset contract address should be empty")
+	assert.Equal(t, "", minimalRecord[7], "Asset symbol should be empty")
+	assert.Equal(t, "", minimalRecord[8], "Token ID should be empty")
+	assert.Equal(t, "0.1", minimalRecord[9], "Value should match")
+	assert.Equal(t, "0", minimalRecord[10], "Gas fee should match")
+	assert.Equal(t, "FAILED", minimalRecord[11], "Status should match")
 }
 
 func TestCSVHeaders(t *testing.T) {
 	headers := CSVHeaders()
 	
 	// Check the number of headers
-	assert.Len(t, headers, 10, "There should be 10 headers")
+	assert.Len(t, headers, 12, "There should be 12 headers")
 	
 	// Check specific headers
 	assert.Equal(t, "Transaction Hash", headers[0])
-	assert.Equal(t, "Date & Time", headers[1])
-	assert.Equal(t, "From Address", headers[2])
-	assert.Equal(t, "To Address", headers[3])
-	assert.Equal(t, "Transaction Type", headers[4])
-	assert.Equal(t, "Asset Contract Address", headers[5])
-	assert.Equal(t, "Asset Symbol / Name", headers[6])
-	assert.Equal(t, "Token ID", headers[7])
-	assert.Equal(t, "Value / Amount", headers[8])
-	assert.Equal(t, "Gas Fee (ETH)", headers[9])
+	assert.Equal(t, "Block Number", headers[1])
+	assert.Equal(t, "Date & Time", headers[2])
+	assert.Equal(t, "From Address", headers[3])
+	assert.Equal(t, "To Address", headers[4])
+	assert.Equal(t, "Transaction Type", headers[5])
+	assert.Equal(t, "Asset Contract Address", headers[6])
+	assert.Equal(t, "Asset Symbol / Name", headers[7])
+	assert.Equal(t, "Token ID", headers[8])
+	assert.Equal(t, "Value / Amount", headers[9])
+	assert.Equal(t, "Gas Fee (ETH)", headers[10])
+	assert.Equal(t, "Status", headers[11])
+}
+
+func TestTransaction_Failed(t *testing.T) {
+	assert.True(t, (&Transaction{Status: StatusFailed}).Failed())
+	assert.False(t, (&Transaction{Status: StatusSuccess}).Failed())
+	assert.False(t, (&Transaction{}).Failed())
 }
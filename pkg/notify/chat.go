@@ -0,0 +1,80 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// TelegramAPI is the base URL of the Telegram Bot API
+const TelegramAPI = "https://api.telegram.org"
+
+// Slack posts the Message of each notification to a Slack incoming webhook
+type Slack struct {
+	WebhookURL string
+	HTTPClient *http.Client
+}
+
+// Notify implements Notifier
+func (s *Slack) Notify(ctx context.Context, n Notification) error {
+	body, err := json.Marshal(map[string]interface{}{"text": Message(n), "unfurl_links": false})
+	if err != nil {
+		return err
+	}
+	return postJSON(ctx, s.HTTPClient, s.WebhookURL, body)
+}
+
+// Discord posts the Message of each notification to a Discord webhook
+type Discord struct {
+	WebhookURL string
+	HTTPClient *http.Client
+}
+
+// Notify implements Notifier
+func (d *Discord) Notify(ctx context.Context, n Notification) error {
+	// Angle brackets keep Discord from embedding a preview of the link
+	message := Message(n)
+	if n.URL != "" {
+		message = strings.Replace(message, n.URL, "<"+n.URL+">", 1)
+	}
+	body, err := json.Marshal(map[string]string{"content": message})
+	if err != nil {
+		return err
+	}
+	return postJSON(ctx, d.HTTPClient, d.WebhookURL, body)
+}
+
+// Telegram sends the Message of each notification to a chat through a bot
+type Telegram struct {
+	// Token is the bot token given by BotFather
+	Token string
+	// ChatID is the numeric ID or @username of the chat or channel
+	ChatID string
+	// APIURL defaults to TelegramAPI
+	APIURL     string
+	HTTPClient *http.Client
+}
+
+// Notify implements Notifier
+func (t *Telegram) Notify(ctx context.Context, n Notification) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"chat_id":                  t.ChatID,
+		"text":                     Message(n),
+		"disable_web_page_preview": true,
+	})
+	if err != nil {
+		return err
+	}
+	base := t.APIURL
+	if base == "" {
+		base = TelegramAPI
+	}
+	err = postJSON(ctx, t.HTTPClient, base+"/bot"+t.Token+"/sendMessage", body)
+	if err != nil && t.Token != "" {
+		// Transport errors quote the request URL, which contains the token
+		return errors.New(strings.ReplaceAll(err.Error(), t.Token, "[token]"))
+	}
+	return err
+}
@@ -0,0 +1,68 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// capture serves one request and records its path and JSON body
+func capture(t *testing.T) (*httptest.Server, *string, map[string]interface{}) {
+	var path string
+	body := make(map[string]interface{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &path, body
+}
+
+var received = Notification{
+	Address:      wallet,
+	NativeSymbol: "ETH",
+	URL:          "https://etherscan.io/tx/0x1",
+	Transaction:  models.Transaction{From: other, To: wallet, Type: models.TypeEthTransfer, Value: "2"},
+}
+
+func TestSlack_Notify(t *testing.T) {
+	srv, _, body := capture(t)
+
+	err := (&Slack{WebhookURL: srv.URL}).Notify(context.Background(), received)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Received 2 ETH from 0xabcd…1234\nhttps://etherscan.io/tx/0x1", body["text"])
+}
+
+func TestDiscord_Notify(t *testing.T) {
+	srv, _, body := capture(t)
+
+	err := (&Discord{WebhookURL: srv.URL}).Notify(context.Background(), received)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Received 2 ETH from 0xabcd…1234\n<https://etherscan.io/tx/0x1>", body["content"])
+}
+
+func TestTelegram_Notify(t *testing.T) {
+	srv, path, body := capture(t)
+
+	err := (&Telegram{Token: "123:secret", ChatID: "@alerts", APIURL: srv.URL}).Notify(context.Background(), received)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "/bot123:secret/sendMessage", *path)
+	assert.Equal(t, "@alerts", body["chat_id"])
+	assert.Equal(t, true, body["disable_web_page_preview"])
+}
+
+func TestTelegram_NotifyHidesToken(t *testing.T) {
+	err := (&Telegram{Token: "123:secret", ChatID: "1", APIURL: "http://127.0.0.1:0"}).Notify(context.Background(), received)
+
+	if assert.Error(t, err) {
+		assert.NotContains(t, err.Error(), "secret")
+	}
+}
@@ -0,0 +1,107 @@
+package notify
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+)
+
+// Message formats n as a one-line alert such as "Received 5,000 USDC from
+// 0xabcd…1234", prefixed with the label of the address and followed by the
+// matched alert rules and the explorer link when known
+func Message(n Notification) string {
+	tx := &n.Transaction
+	from := strings.EqualFold(tx.From, n.Address)
+	to := strings.EqualFold(tx.To, n.Address)
+
+	var b strings.Builder
+	if n.Label != "" {
+		fmt.Fprintf(&b, "%s: ", n.Label)
+	}
+	if tx.Failed() {
+		b.WriteString("Failed: ")
+	}
+	asset := assetText(n)
+	switch {
+	case tx.Type == models.TypeContractCall && isZero(tx.Value):
+		fmt.Fprintf(&b, "Called %s", ShortAddress(tx.To))
+	case from && to:
+		fmt.Fprintf(&b, "Sent %s to itself", asset)
+	case to:
+		fmt.Fprintf(&b, "Received %s from %s", asset, ShortAddress(tx.From))
+	case from:
+		fmt.Fprintf(&b, "Sent %s to %s", asset, ShortAddress(tx.To))
+	default:
+		fmt.Fprintf(&b, "%s moved from %s to %s", asset, ShortAddress(tx.From), ShortAddress(tx.To))
+	}
+	if len(n.Alerts) > 0 {
+		fmt.Fprintf(&b, " [%s]", strings.Join(n.Alerts, ", "))
+	}
+	if n.URL != "" {
+		fmt.Fprintf(&b, "\n%s", n.URL)
+	}
+	return b.String()
+}
+
+// assetText describes the amount and asset of a row, such as "1.5 ETH" or
+// "NFT BAYC #42"
+func assetText(n Notification) string {
+	tx := &n.Transaction
+	symbol := tx.AssetSymbol
+	if symbol == "" {
+		symbol = n.NativeSymbol
+	}
+	if symbol == "" && tx.AssetContractAddr != "" {
+		symbol = ShortAddress(tx.AssetContractAddr)
+	}
+	switch tx.Type {
+	case models.TypeERC721Transfer:
+		return fmt.Sprintf("NFT %s #%s", symbol, tx.TokenID)
+	case models.TypeERC1155Transfer:
+		return fmt.Sprintf("%s %s #%s", FormatNumber(tx.Value), symbol, tx.TokenID)
+	}
+	return strings.TrimSpace(FormatNumber(tx.Value) + " " + symbol)
+}
+
+// FormatNumber groups the integer digits of a decimal with commas and drops
+// trailing fractional zeros, so "5000.500000" reads "5,000.5". Other values
+// are returned as they are.
+func FormatNumber(value string) string {
+	if _, ok := new(big.Rat).SetString(value); !ok || strings.ContainsAny(value, "eE/") {
+		return value
+	}
+	sign := ""
+	if strings.HasPrefix(value, "-") {
+		sign, value = "-", value[1:]
+	}
+	whole, fraction, _ := strings.Cut(value, ".")
+	fraction = strings.TrimRight(fraction, "0")
+
+	var b strings.Builder
+	b.WriteString(sign)
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(digit)
+	}
+	if fraction != "" {
+		b.WriteString("." + fraction)
+	}
+	return b.String()
+}
+
+// ShortAddress abbreviates an address to its first and last characters
+func ShortAddress(address string) string {
+	if len(address) <= 12 {
+		return address
+	}
+	return address[:6] + "…" + address[len(address)-4:]
+}
+
+func isZero(value string) bool {
+	r, ok := new(big.Rat).SetString(value)
+	return !ok || r.Sign() == 0
+}
@@ -0,0 +1,77 @@
+package notify
+
+import (
+	"testing"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	wallet = "0x1111111111111111111111111111111111111111"
+	other  = "0xabcdef0000000000000000000000000000001234"
+)
+
+func TestMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		n    Notification
+		want string
+	}{
+		{
+			name: "received token",
+			n: Notification{Address: wallet, Transaction: models.Transaction{
+				From: other, To: wallet, Type: models.TypeERC20Transfer, AssetSymbol: "USDC", Value: "5000.000000",
+			}},
+			want: "Received 5,000 USDC from 0xabcd…1234",
+		},
+		{
+			name: "sent native with label and link",
+			n: Notification{Address: wallet, Label: "treasury", NativeSymbol: "ETH", URL: "https://etherscan.io/tx/0x1", Transaction: models.Transaction{
+				From: wallet, To: other, Type: models.TypeEthTransfer, Value: "1.250000000000000000",
+			}},
+			want: "treasury: Sent 1.25 ETH to 0xabcd…1234\nhttps://etherscan.io/tx/0x1",
+		},
+		{
+			name: "matched rules",
+			n: Notification{Address: wallet, NativeSymbol: "ETH", Alerts: []string{"large-eth-out", "denylist"}, Transaction: models.Transaction{
+				From: wallet, To: other, Type: models.TypeEthTransfer, Value: "20",
+			}},
+			want: "Sent 20 ETH to 0xabcd…1234 [large-eth-out, denylist]",
+		},
+		{
+			name: "nft",
+			n: Notification{Address: wallet, Transaction: models.Transaction{
+				From: other, To: wallet, Type: models.TypeERC721Transfer, AssetSymbol: "BAYC", TokenID: "42", Value: "1",
+			}},
+			want: "Received NFT BAYC #42 from 0xabcd…1234",
+		},
+		{
+			name: "failed contract call",
+			n: Notification{Address: wallet, NativeSymbol: "ETH", Transaction: models.Transaction{
+				From: wallet, To: other, Type: models.TypeContractCall, Value: "0", Status: models.StatusFailed,
+			}},
+			want: "Failed: Called 0xabcd…1234",
+		},
+		{
+			name: "self transfer",
+			n: Notification{Address: wallet, NativeSymbol: "POL", Transaction: models.Transaction{
+				From: wallet, To: wallet, Type: models.TypeEthTransfer, Value: "2",
+			}},
+			want: "Sent 2 POL to itself",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Message(tt.n))
+		})
+	}
+}
+
+func TestFormatNumber(t *testing.T) {
+	assert.Equal(t, "1,234,567.89", FormatNumber("1234567.890"))
+	assert.Equal(t, "-1,000", FormatNumber("-1000.0"))
+	assert.Equal(t, "999", FormatNumber("999"))
+	assert.Equal(t, "0.001", FormatNumber("0.001000"))
+	assert.Equal(t, "n/a", FormatNumber("n/a"))
+}
@@ -0,0 +1,149 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// natsMessage is a message received by fakeNATS
+type natsMessage struct {
+	Subject string
+	Payload []byte
+}
+
+// fakeNATS is a NATS server speaking enough of the protocol to test NATS. It
+// rejects connections whose CONNECT lacks token, and closes every
+// connection once dropAfter messages are acknowledged when dropAfter is
+// positive.
+type fakeNATS struct {
+	listener  net.Listener
+	token     string
+	dropAfter int
+	connects  chan map[string]interface{}
+	messages  chan natsMessage
+}
+
+func newFakeNATS(t *testing.T) *fakeNATS {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fakeNATS{listener: listener, connects: make(chan map[string]interface{}, 10), messages: make(chan natsMessage, 10)}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+	return f
+}
+
+func (f *fakeNATS) url(userinfo string) string {
+	return "nats://" + userinfo + f.listener.Addr().String()
+}
+
+func (f *fakeNATS) serve(conn net.Conn) {
+	defer conn.Close()
+	fmt.Fprint(conn, "INFO {\"server_id\":\"fake\",\"max_payload\":1048576}\r\n")
+	reader := bufio.NewReader(conn)
+	received := 0
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		verb, args, _ := strings.Cut(strings.TrimSpace(line), " ")
+		switch verb {
+		case "CONNECT":
+			var options map[string]interface{}
+			json.Unmarshal([]byte(args), &options)
+			f.connects <- options
+			if f.token != "" && options["auth_token"] != f.token {
+				fmt.Fprint(conn, "-ERR 'Authorization Violation'\r\n")
+				return
+			}
+		case "PING":
+			fmt.Fprint(conn, "PONG\r\n")
+			if f.dropAfter > 0 && received >= f.dropAfter {
+				return
+			}
+		case "PUB":
+			fields := strings.Fields(args)
+			size, _ := strconv.Atoi(fields[len(fields)-1])
+			payload := make([]byte, size+2)
+			if _, err := io.ReadFull(reader, payload); err != nil {
+				return
+			}
+			f.messages <- natsMessage{Subject: fields[0], Payload: payload[:size]}
+			received++
+		}
+	}
+}
+
+func TestNATS_Notify(t *testing.T) {
+	server := newFakeNATS(t)
+	server.token = "s3cret"
+	c := &NATS{URL: server.url("s3cret@"), Subject: "eth.{type}.{address}", Name: "watch"}
+	defer c.Close()
+
+	n := Notification{Address: "0xWallet", Transaction: models.Transaction{Hash: "0xabc", Type: models.TypeEthTransfer}}
+	assert.NoError(t, c.Notify(context.Background(), n))
+	assert.NoError(t, c.Notify(context.Background(), n))
+
+	options := <-server.connects
+	assert.Equal(t, "s3cret", options["auth_token"])
+	assert.Equal(t, "watch", options["name"])
+	assert.Empty(t, server.connects, "the connection is reused")
+	message := <-server.messages
+	assert.Equal(t, "eth.ETH_TRANSFER.0xwallet", message.Subject)
+	var received Notification
+	assert.NoError(t, json.Unmarshal(message.Payload, &received))
+	assert.Equal(t, n, received)
+}
+
+func TestNATS_Reconnects(t *testing.T) {
+	server := newFakeNATS(t)
+	server.dropAfter = 1
+	c := &NATS{URL: server.url("user:pass@"), Subject: "eth.transactions"}
+	defer c.Close()
+
+	assert.NoError(t, c.Notify(context.Background(), Notification{Address: "0x1"}))
+	// The dropped connection fails one publish, and the next one reconnects
+	if err := c.Notify(context.Background(), Notification{Address: "0x2"}); err != nil {
+		assert.NoError(t, c.Notify(context.Background(), Notification{Address: "0x2"}))
+	}
+
+	options := <-server.connects
+	assert.Equal(t, "user", options["user"])
+	assert.Equal(t, "pass", options["pass"])
+	assert.Len(t, server.connects, 1)
+	assert.Len(t, server.messages, 2)
+}
+
+func TestNATS_Errors(t *testing.T) {
+	server := newFakeNATS(t)
+	server.token = "s3cret"
+
+	err := (&NATS{URL: server.url("wrong@"), Subject: "eth"}).Notify(context.Background(), Notification{})
+	assert.ErrorContains(t, err, "Authorization Violation")
+
+	err = (&NATS{URL: server.url(""), Subject: "eth {address}"}).Notify(context.Background(), Notification{Address: "0x1"})
+	assert.ErrorContains(t, err, "invalid NATS subject")
+
+	err = (&NATS{URL: "http://" + server.listener.Addr().String(), Subject: "eth"}).Notify(context.Background(), Notification{})
+	assert.ErrorContains(t, err, "unsupported NATS URL scheme")
+}
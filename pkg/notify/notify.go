@@ -0,0 +1,76 @@
+// Package notify delivers alerts about transactions detected by a watcher,
+// so activity on monitored addresses reaches people and other services.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+)
+
+// DefaultTimeout bounds a delivery when the HTTP client has no timeout
+const DefaultTimeout = 10 * time.Second
+
+// Notification reports a transaction of a watched address
+type Notification struct {
+	Address     string             `json:"address"`
+	Label       string             `json:"label,omitempty"`
+	Transaction models.Transaction `json:"transaction"`
+	// NativeSymbol is the symbol of amounts of rows without an asset
+	// symbol, such as ETH
+	NativeSymbol string `json:"native_symbol,omitempty"`
+	// URL is the explorer page of the transaction
+	URL string `json:"url,omitempty"`
+	// Alerts names the alert rules the transaction matched
+	Alerts []string `json:"alerts,omitempty"`
+}
+
+// Notifier delivers notifications
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+// Webhook posts each notification as a JSON object to URL
+type Webhook struct {
+	URL string
+	// HTTPClient sends the requests. Defaults to a client with DefaultTimeout.
+	HTTPClient *http.Client
+}
+
+// Notify implements Notifier
+func (w *Webhook) Notify(ctx context.Context, n Notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	return postJSON(ctx, w.HTTPClient, w.URL, body)
+}
+
+// postJSON posts body to url, treating statuses other than 2xx as errors
+func postJSON(ctx context.Context, client *http.Client, url string, body []byte) error {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s returned %s: %s", req.URL.Host, resp.Status, bytes.TrimSpace(message))
+	}
+	return nil
+}
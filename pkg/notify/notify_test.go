@@ -0,0 +1,40 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhook_Notify(t *testing.T) {
+	var received Notification
+	var contentType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer srv.Close()
+
+	n := Notification{Address: "0xwallet", Label: "treasury", Transaction: models.Transaction{Hash: "0xabc", Value: "1.5"}}
+	err := (&Webhook{URL: srv.URL}).Notify(context.Background(), n)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "application/json", contentType)
+	assert.Equal(t, n, received)
+}
+
+func TestWebhook_NotifyStatusError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad token", http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	err := (&Webhook{URL: srv.URL}).Notify(context.Background(), Notification{})
+
+	assert.ErrorContains(t, err, "401 Unauthorized: bad token")
+}
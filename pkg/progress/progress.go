@@ -0,0 +1,173 @@
+// Package progress renders live fetch progress for each transaction type:
+// pages fetched, rows so far, an estimated total and the time remaining.
+package progress
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// redrawInterval limits how often the progress block is redrawn
+const redrawInterval = 100 * time.Millisecond
+
+// Tracker collects page counts per transaction type over a block range and
+// redraws a progress block in place. It is safe for concurrent use.
+type Tracker struct {
+	mu         sync.Mutex
+	w          io.Writer
+	startBlock int64
+	endBlock   int64
+	started    time.Time
+	now        func() time.Time
+	counters   []*counter
+	drawn      int
+	lastDraw   time.Time
+}
+
+type counter struct {
+	name    string
+	pages   int
+	rows    int
+	reached int64
+	// unordered is set for types whose pages are not ordered by block, so
+	// their coverage of the range is unknown
+	unordered bool
+}
+
+// New creates a tracker for the given types over startBlock..endBlock,
+// drawing to w
+func New(w io.Writer, startBlock, endBlock int64, names ...string) *Tracker {
+	t := &Tracker{w: w, startBlock: startBlock, endBlock: endBlock, now: time.Now}
+	t.started = t.now()
+	for _, name := range names {
+		t.counters = append(t.counters, &counter{name: name})
+	}
+	return t
+}
+
+// IsTerminal reports whether f is an interactive terminal, where progress
+// can be redrawn in place
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Page records a page of rows for name. block is how far the fetch of name
+// has reached, or 0 when unknown. A nil Tracker ignores pages.
+func (t *Tracker) Page(name string, rows int, block int64) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	c := t.counter(name)
+	c.pages++
+	c.rows += rows
+	if block == 0 {
+		c.unordered = true
+	}
+	if block > c.reached {
+		c.reached = block
+	}
+	t.draw(false)
+}
+
+// Finish draws the final state
+func (t *Tracker) Finish() {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.draw(true)
+}
+
+func (t *Tracker) counter(name string) *counter {
+	for _, c := range t.counters {
+		if c.name == name {
+			return c
+		}
+	}
+	c := &counter{name: name}
+	t.counters = append(t.counters, c)
+	return c
+}
+
+// fraction returns the share of the block range c has covered. A type is
+// done once it covers the whole range.
+func (t *Tracker) fraction(c *counter) float64 {
+	span := t.endBlock - t.startBlock + 1
+	if span <= 0 || c.reached < t.startBlock {
+		return 0
+	}
+	return min(float64(c.reached-t.startBlock+1)/float64(span), 1)
+}
+
+// estimate extrapolates the final row count of c from the rows fetched so
+// far and the share of the range covered, or returns -1 before any block
+// has been covered
+func (t *Tracker) estimate(c *counter) int {
+	f := t.fraction(c)
+	if f == 0 || c.unordered {
+		return -1
+	}
+	return int(float64(c.rows) / f)
+}
+
+// ETA estimates the time left from the average share of the range covered
+// by each type, or returns -1 before anything was covered
+func (t *Tracker) ETA() time.Duration {
+	var total float64
+	ordered := 0
+	for _, c := range t.counters {
+		if !c.unordered {
+			total += t.fraction(c)
+			ordered++
+		}
+	}
+	if ordered == 0 || total == 0 {
+		return -1
+	}
+	f := total / float64(ordered)
+	elapsed := t.now().Sub(t.started)
+	return time.Duration(float64(elapsed) * (1 - f) / f).Round(time.Second)
+}
+
+// draw redraws the progress block over the previous one, at most every
+// redrawInterval unless force is set
+func (t *Tracker) draw(force bool) {
+	now := t.now()
+	if !force && now.Sub(t.lastDraw) < redrawInterval {
+		return
+	}
+	t.lastDraw = now
+
+	var b strings.Builder
+	if t.drawn > 0 {
+		// Move back to the first line of the previous block
+		fmt.Fprintf(&b, "\033[%dA", t.drawn)
+	}
+	totalRows := 0
+	for _, c := range t.counters {
+		totalRows += c.rows
+		fmt.Fprintf(&b, "\r\033[K  %-9s %4d pages %9d rows", c.name, c.pages, c.rows)
+		switch est := t.estimate(c); {
+		case est >= 0 && t.fraction(c) >= 1:
+			b.WriteString("  done")
+		case est >= 0:
+			fmt.Fprintf(&b, "  ~%d total  %3.0f%%", est, t.fraction(c)*100)
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "\r\033[K  %-9s %19d rows", "total", totalRows)
+	if eta := t.ETA(); eta > 0 {
+		fmt.Fprintf(&b, "  ETA %s", eta)
+	}
+	b.WriteString("\n")
+	t.drawn = len(t.counters) + 1
+	io.WriteString(t.w, b.String())
+}
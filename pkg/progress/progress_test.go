@@ -0,0 +1,58 @@
+package progress
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateAndETA(t *testing.T) {
+	var out bytes.Buffer
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tracker := New(&out, 1, 1000, "normal", "erc20")
+	tracker.now = func() time.Time { return clock }
+	tracker.started = clock
+
+	assert.Equal(t, time.Duration(-1), tracker.ETA())
+
+	// normal covered a quarter of the range with 250 rows
+	tracker.Page("normal", 250, 250)
+	assert.Equal(t, 1000, tracker.estimate(tracker.counter("normal")))
+	assert.Equal(t, -1, tracker.estimate(tracker.counter("erc20")))
+
+	// erc20 finished with 10 rows
+	tracker.Page("erc20", 10, 1000)
+	assert.Equal(t, 10, tracker.estimate(tracker.counter("erc20")))
+
+	// Types not ordered by block have no estimate and do not count towards the ETA
+	tracker.Page("rewards", 3, 0)
+	assert.Equal(t, -1, tracker.estimate(tracker.counter("rewards")))
+
+	// Average coverage is 62.5% after 50s, so 30s remain
+	clock = clock.Add(50 * time.Second)
+	assert.Equal(t, 30*time.Second, tracker.ETA())
+}
+
+func TestDrawRedrawsInPlace(t *testing.T) {
+	var out bytes.Buffer
+	tracker := New(&out, 0, 99, "normal")
+	tracker.Page("normal", 40, 49)
+	tracker.Finish()
+
+	lines := strings.Split(out.String(), "\n")
+	assert.Contains(t, lines[0], "normal")
+	assert.Contains(t, lines[0], "40 rows")
+	assert.Contains(t, lines[0], "~80 total")
+	assert.Contains(t, lines[1], "total")
+	// The second draw moves back over the two lines of the first
+	assert.True(t, strings.HasPrefix(lines[2], "\033[2A"))
+}
+
+func TestNilTracker(t *testing.T) {
+	var tracker *Tracker
+	tracker.Page("normal", 1, 1)
+	tracker.Finish()
+}
@@ -0,0 +1,66 @@
+package protect
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+// Command is a provider backed by an external tool that reads the input on
+// stdin and writes the ciphertext or signature to stdout
+type Command struct {
+	Path string
+	Args []string
+	Ext  string
+}
+
+// AgeEncrypter encrypts to an age recipient with the age tool
+func AgeEncrypter(recipient string) Command {
+	return Command{Path: "age", Args: []string{"--encrypt", "--recipient", recipient}, Ext: ".age"}
+}
+
+// GPGEncrypter encrypts to a GnuPG recipient
+func GPGEncrypter(recipient string) Command {
+	return Command{Path: "gpg", Args: []string{"--batch", "--yes", "--encrypt", "--recipient", recipient, "--output", "-"}, Ext: ".gpg"}
+}
+
+// GPGSigner creates detached signatures with a GnuPG key
+func GPGSigner(keyID string) Command {
+	return Command{Path: "gpg", Args: []string{"--batch", "--yes", "--detach-sign", "--local-user", keyID, "--output", "-"}, Ext: ".sig"}
+}
+
+// Extension implements Encrypter and Signer
+func (c Command) Extension() string {
+	return c.Ext
+}
+
+// Encrypt implements Encrypter
+func (c Command) Encrypt(dst io.Writer, src io.Reader) error {
+	return c.run(dst, src)
+}
+
+// Sign implements Signer
+func (c Command) Sign(src io.Reader) ([]byte, error) {
+	var out bytes.Buffer
+	if err := c.run(&out, src); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+func (c Command) run(dst io.Writer, src io.Reader) error {
+	var stderr bytes.Buffer
+	cmd := exec.Command(c.Path, c.Args...)
+	cmd.Stdin = src
+	cmd.Stdout = dst
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%s: %w: %s", c.Path, err, msg)
+		}
+		return fmt.Errorf("%s: %w", c.Path, err)
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package protect
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// KeyService is a key management service holding the master key, such as
+// AWS KMS or a Vault transit engine. Key material never leaves the service
+// except as short-lived data keys.
+type KeyService interface {
+	// DataKey returns a new 256-bit data key and the key wrapped by the
+	// master key
+	DataKey() (plaintext, wrapped []byte, err error)
+	// Unwrap recovers a data key from its wrapped form
+	Unwrap(wrapped []byte) ([]byte, error)
+	// Sign signs a SHA-256 digest with the master key
+	Sign(digest []byte) ([]byte, error)
+}
+
+// envelopeMagic starts every envelope-encrypted file
+const envelopeMagic = "ETXENV1\n"
+
+// Envelope encrypts files with AES-256-GCM under a fresh data key from a
+// KeyService, storing the wrapped data key in the file header
+type Envelope struct {
+	Keys KeyService
+}
+
+// Extension implements Encrypter
+func (e *Envelope) Extension() string {
+	return ".enc"
+}
+
+// Encrypt implements Encrypter
+func (e *Envelope) Encrypt(dst io.Writer, src io.Reader) error {
+	plaintext, err := io.ReadAll(src)
+	if err != nil {
+		return err
+	}
+	key, wrapped, err := e.Keys.DataKey()
+	if err != nil {
+		return fmt.Errorf("failed to obtain data key: %w", err)
+	}
+	if len(wrapped) > 0xffff {
+		return errors.New("wrapped data key too large")
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	var header bytes.Buffer
+	header.WriteString(envelopeMagic)
+	binary.Write(&header, binary.BigEndian, uint16(len(wrapped)))
+	header.Write(wrapped)
+	header.Write(nonce)
+
+	// The header is authenticated so the wrapped key cannot be swapped
+	sealed := gcm.Seal(nil, nonce, plaintext, header.Bytes())
+	if _, err := dst.Write(header.Bytes()); err != nil {
+		return err
+	}
+	_, err = dst.Write(sealed)
+	return err
+}
+
+// Decrypt reverses Encrypt
+func (e *Envelope) Decrypt(dst io.Writer, src io.Reader) error {
+	data, err := io.ReadAll(src)
+	if err != nil {
+		return err
+	}
+	if !bytes.HasPrefix(data, []byte(envelopeMagic)) || len(data) < len(envelopeMagic)+2 {
+		return errors.New("not an envelope-encrypted file")
+	}
+	pos := len(envelopeMagic)
+	wrappedLen := int(binary.BigEndian.Uint16(data[pos:]))
+	pos += 2
+	if len(data) < pos+wrappedLen {
+		return errors.New("truncated envelope header")
+	}
+	wrapped := data[pos : pos+wrappedLen]
+	pos += wrappedLen
+
+	key, err := e.Keys.Unwrap(wrapped)
+	if err != nil {
+		return fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+	if len(data) < pos+gcm.NonceSize() {
+		return errors.New("truncated envelope header")
+	}
+	nonce := data[pos : pos+gcm.NonceSize()]
+	pos += gcm.NonceSize()
+
+	plaintext, err := gcm.Open(nil, nonce, data[pos:], data[:pos])
+	if err != nil {
+		return fmt.Errorf("failed to decrypt: %w", err)
+	}
+	_, err = dst.Write(plaintext)
+	return err
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// KeySigner signs the SHA-256 digest of files with a KeyService
+type KeySigner struct {
+	Keys KeyService
+}
+
+// Extension implements Signer
+func (s *KeySigner) Extension() string {
+	return ".sig"
+}
+
+// Sign implements Signer
+func (s *KeySigner) Sign(src io.Reader) ([]byte, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, src); err != nil {
+		return nil, err
+	}
+	return s.Keys.Sign(h.Sum(nil))
+}
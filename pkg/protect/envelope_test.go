@@ -0,0 +1,65 @@
+package protect
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeKeys wraps data keys by reversing them
+type fakeKeys struct{}
+
+func (fakeKeys) DataKey() ([]byte, []byte, error) {
+	key := bytes.Repeat([]byte{7}, 32)
+	key[0] = 1
+	return key, reverse(key), nil
+}
+
+func (fakeKeys) Unwrap(wrapped []byte) ([]byte, error) {
+	if len(wrapped) != 32 {
+		return nil, errors.New("bad wrapped key")
+	}
+	return reverse(wrapped), nil
+}
+
+func (fakeKeys) Sign(digest []byte) ([]byte, error) {
+	return append([]byte("sig:"), digest...), nil
+}
+
+func reverse(b []byte) []byte {
+	out := make([]byte, len(b))
+	for i := range b {
+		out[len(b)-1-i] = b[i]
+	}
+	return out
+}
+
+func TestEnvelope_RoundTrip(t *testing.T) {
+	env := &Envelope{Keys: fakeKeys{}}
+	plaintext := []byte("Transaction Hash,Value\n0xabc,1.5\n")
+
+	var encrypted bytes.Buffer
+	assert.NoError(t, env.Encrypt(&encrypted, bytes.NewReader(plaintext)))
+	assert.NotContains(t, encrypted.String(), "0xabc")
+
+	var decrypted bytes.Buffer
+	assert.NoError(t, env.Decrypt(&decrypted, bytes.NewReader(encrypted.Bytes())))
+	assert.Equal(t, plaintext, decrypted.Bytes())
+
+	// Tampering with the ciphertext is detected
+	tampered := encrypted.Bytes()
+	tampered[len(tampered)-1] ^= 1
+	assert.Error(t, env.Decrypt(&decrypted, bytes.NewReader(tampered)))
+
+	assert.Error(t, env.Decrypt(&decrypted, bytes.NewReader([]byte("plain csv"))))
+}
+
+func TestKeySigner(t *testing.T) {
+	signature, err := (&KeySigner{Keys: fakeKeys{}}).Sign(bytes.NewReader([]byte("data")))
+	assert.NoError(t, err)
+	digest := sha256.Sum256([]byte("data"))
+	assert.Equal(t, append([]byte("sig:"), digest[:]...), signature)
+}
@@ -0,0 +1,151 @@
+package protect
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/secrets"
+)
+
+// DefaultKMSSigningAlgorithm signs with an ECC_NIST_P256 key
+const DefaultKMSSigningAlgorithm = "ECDSA_SHA_256"
+
+// AWSKMS is a KeyService backed by an AWS KMS key. Data keys come from
+// GenerateDataKey and are unwrapped with Decrypt, so the master key never
+// leaves KMS. Requests are signed with Signature Version 4 using static
+// credentials.
+type AWSKMS struct {
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	// KeyID is the key ID, ARN or alias of the KMS key
+	KeyID string
+	// SigningAlgorithm is the algorithm Sign asks for, which must suit the
+	// key; DefaultKMSSigningAlgorithm by default
+	SigningAlgorithm string
+	// Endpoint overrides the regional endpoint, e.g. for VPC endpoints
+	Endpoint   string
+	HTTPClient *http.Client
+}
+
+// AWSKMSFromEnv configures a KMS key from the standard AWS_REGION (or
+// AWS_DEFAULT_REGION), AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and
+// optional AWS_SESSION_TOKEN variables, with an optional
+// AWS_KMS_SIGNING_ALGORITHM
+func AWSKMSFromEnv(keyID string) (*AWSKMS, error) {
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	kms := &AWSKMS{
+		Region:           region,
+		AccessKeyID:      os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey:  os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:     os.Getenv("AWS_SESSION_TOKEN"),
+		KeyID:            keyID,
+		SigningAlgorithm: os.Getenv("AWS_KMS_SIGNING_ALGORITHM"),
+		HTTPClient:       &http.Client{Timeout: 30 * time.Second},
+	}
+	if kms.Region == "" || kms.AccessKeyID == "" || kms.SecretAccessKey == "" {
+		return nil, errors.New("kms provider requires AWS_REGION, AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
+	}
+	return kms, nil
+}
+
+// DataKey implements KeyService
+func (k *AWSKMS) DataKey() ([]byte, []byte, error) {
+	var resp struct {
+		Plaintext      []byte `json:"Plaintext"`
+		CiphertextBlob []byte `json:"CiphertextBlob"`
+	}
+	if err := k.call("GenerateDataKey", map[string]interface{}{"KeyId": k.KeyID, "KeySpec": "AES_256"}, &resp); err != nil {
+		return nil, nil, err
+	}
+	return resp.Plaintext, resp.CiphertextBlob, nil
+}
+
+// Unwrap implements KeyService
+func (k *AWSKMS) Unwrap(wrapped []byte) ([]byte, error) {
+	var resp struct {
+		Plaintext []byte `json:"Plaintext"`
+	}
+	// The key is named so a blob wrapped by another key is refused
+	if err := k.call("Decrypt", map[string]interface{}{"KeyId": k.KeyID, "CiphertextBlob": wrapped}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Plaintext, nil
+}
+
+// Sign implements KeyService. KMS keys sign with asymmetric keys only, so
+// the key of -sign is not the symmetric key of -encrypt. The signature is
+// returned as KMS encodes it, which `aws kms verify` accepts.
+func (k *AWSKMS) Sign(digest []byte) ([]byte, error) {
+	algorithm := k.SigningAlgorithm
+	if algorithm == "" {
+		algorithm = DefaultKMSSigningAlgorithm
+	}
+	var resp struct {
+		Signature []byte `json:"Signature"`
+	}
+	body := map[string]interface{}{
+		"KeyId":            k.KeyID,
+		"Message":          digest,
+		"MessageType":      "DIGEST",
+		"SigningAlgorithm": algorithm,
+	}
+	if err := k.call("Sign", body, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Signature, nil
+}
+
+// call sends a request of the KMS JSON protocol. Byte slices are encoded
+// as base64, as KMS expects binary fields.
+func (k *AWSKMS) call(action string, body interface{}, result interface{}) error {
+	endpoint := k.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://kms.%s.amazonaws.com", k.Region)
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, endpoint+"/", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "TrentService."+action)
+	if k.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", k.SessionToken)
+	}
+	secrets.SignV4(req, payload, "kms", k.Region, k.AccessKeyID, k.SecretAccessKey, time.Now())
+
+	client := k.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("kms request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("kms %s failed with status code %d: %s", action, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	return json.Unmarshal(data, result)
+}
@@ -0,0 +1,64 @@
+package protect
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAWSKMS(t *testing.T) {
+	key := bytes.Repeat([]byte{42}, 32)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Contains(t, r.Header.Get("Authorization"), "AKID/")
+		assert.Contains(t, r.Header.Get("Authorization"), "/eu-west-1/kms/aws4_request")
+		assert.Equal(t, "session", r.Header.Get("X-Amz-Security-Token"))
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["KeyId"] != "alias/reports" {
+			http.Error(w, `{"__type": "NotFoundException"}`, http.StatusBadRequest)
+			return
+		}
+
+		var resp map[string]interface{}
+		switch r.Header.Get("X-Amz-Target") {
+		case "TrentService.GenerateDataKey":
+			assert.Equal(t, "AES_256", body["KeySpec"])
+			resp = map[string]interface{}{"Plaintext": key, "CiphertextBlob": []byte("wrapped")}
+		case "TrentService.Decrypt":
+			// Binary fields travel as base64
+			assert.Equal(t, "d3JhcHBlZA==", body["CiphertextBlob"])
+			resp = map[string]interface{}{"Plaintext": key}
+		case "TrentService.Sign":
+			assert.Equal(t, "DIGEST", body["MessageType"])
+			assert.Equal(t, DefaultKMSSigningAlgorithm, body["SigningAlgorithm"])
+			resp = map[string]interface{}{"Signature": []byte("signature")}
+		default:
+			http.Error(w, "unknown target", http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	kms := &AWSKMS{Region: "eu-west-1", AccessKeyID: "AKID", SecretAccessKey: "secret", SessionToken: "session", KeyID: "alias/reports", Endpoint: server.URL}
+	env := &Envelope{Keys: kms}
+
+	var encrypted, decrypted bytes.Buffer
+	assert.NoError(t, env.Encrypt(&encrypted, bytes.NewReader([]byte("secret report"))))
+	assert.Contains(t, encrypted.String(), "wrapped", "the wrapped data key is in the header")
+	assert.NotContains(t, encrypted.String(), "secret report")
+	assert.NoError(t, env.Decrypt(&decrypted, &encrypted))
+	assert.Equal(t, "secret report", decrypted.String())
+
+	signature, err := (&KeySigner{Keys: kms}).Sign(bytes.NewReader([]byte("secret report")))
+	assert.NoError(t, err)
+	assert.Equal(t, "signature", string(signature))
+
+	kms.KeyID = "alias/missing"
+	_, _, err = kms.DataKey()
+	assert.ErrorContains(t, err, "kms GenerateDataKey failed with status code 400: {\"__type\": \"NotFoundException\"}")
+}
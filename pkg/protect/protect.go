@@ -0,0 +1,151 @@
+// Package protect encrypts and signs output files through pluggable
+// providers, so exports can be protected with local tools (age, GPG) or with
+// keys held in an external key management service.
+package protect
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Encrypter encrypts the contents of an output file
+type Encrypter interface {
+	// Extension is appended to the name of encrypted files, such as ".age"
+	Extension() string
+	Encrypt(dst io.Writer, src io.Reader) error
+}
+
+// Signer produces a detached signature for the contents of an output file
+type Signer interface {
+	// Extension is appended to the name of signature files, such as ".sig"
+	Extension() string
+	Sign(src io.Reader) ([]byte, error)
+}
+
+// ParseEncrypter builds an encrypter from a provider spec:
+//
+//	age:RECIPIENT    encrypt with the age command-line tool
+//	gpg:RECIPIENT    encrypt with GnuPG
+//	vault:KEY        envelope encryption with a Vault transit key
+//	kms:KEY          envelope encryption with an AWS KMS key
+func ParseEncrypter(spec string) (Encrypter, error) {
+	provider, arg, err := splitSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+	switch provider {
+	case "age":
+		return AgeEncrypter(arg), nil
+	case "gpg":
+		return GPGEncrypter(arg), nil
+	case "vault":
+		vault, err := VaultTransitFromEnv(arg)
+		if err != nil {
+			return nil, err
+		}
+		return &Envelope{Keys: vault}, nil
+	case "kms":
+		kms, err := AWSKMSFromEnv(arg)
+		if err != nil {
+			return nil, err
+		}
+		return &Envelope{Keys: kms}, nil
+	}
+	return nil, fmt.Errorf("unknown encryption provider %q (expected age, gpg, vault or kms)", provider)
+}
+
+// ParseSigner builds a signer from a provider spec:
+//
+//	gpg:KEYID        detached signature with GnuPG
+//	vault:KEY        signature from a Vault transit key
+//	kms:KEY          signature from an asymmetric AWS KMS key
+func ParseSigner(spec string) (Signer, error) {
+	provider, arg, err := splitSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+	switch provider {
+	case "gpg":
+		return GPGSigner(arg), nil
+	case "vault":
+		vault, err := VaultTransitFromEnv(arg)
+		if err != nil {
+			return nil, err
+		}
+		return &KeySigner{Keys: vault}, nil
+	case "kms":
+		kms, err := AWSKMSFromEnv(arg)
+		if err != nil {
+			return nil, err
+		}
+		return &KeySigner{Keys: kms}, nil
+	}
+	return nil, fmt.Errorf("unknown signing provider %q (expected gpg, vault or kms)", provider)
+}
+
+func splitSpec(spec string) (provider, arg string, err error) {
+	provider, arg, ok := strings.Cut(spec, ":")
+	if !ok || provider == "" || arg == "" {
+		return "", "", fmt.Errorf("invalid provider spec %q, expected PROVIDER:KEY", spec)
+	}
+	return strings.ToLower(provider), arg, nil
+}
+
+// File protects the file at path. With an encrypter the file is replaced by
+// its encrypted form; with a signer a detached signature of the resulting
+// file is written next to it. It returns the paths of the files written.
+func File(path string, enc Encrypter, signer Signer) ([]string, error) {
+	var written []string
+
+	if enc != nil {
+		encrypted := path + enc.Extension()
+		if err := encryptFile(enc, path, encrypted); err != nil {
+			return nil, fmt.Errorf("failed to encrypt %s: %w", path, err)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("failed to remove plaintext %s: %w", path, err)
+		}
+		path = encrypted
+	}
+	written = append(written, path)
+
+	if signer != nil {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		signature, err := signer.Sign(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to sign %s: %w", path, err)
+		}
+		sigPath := path + signer.Extension()
+		if err := os.WriteFile(sigPath, signature, 0644); err != nil {
+			return nil, fmt.Errorf("failed to write signature: %w", err)
+		}
+		written = append(written, sigPath)
+	}
+
+	return written, nil
+}
+
+func encryptFile(enc Encrypter, src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if err := enc.Encrypt(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	return out.Close()
+}
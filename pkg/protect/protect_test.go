@@ -0,0 +1,101 @@
+package protect
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type upperEncrypter struct{}
+
+func (upperEncrypter) Extension() string { return ".up" }
+
+func (upperEncrypter) Encrypt(dst io.Writer, src io.Reader) error {
+	data, err := io.ReadAll(src)
+	if err != nil {
+		return err
+	}
+	_, err = dst.Write(bytes.ToUpper(data))
+	return err
+}
+
+type lengthSigner struct{}
+
+func (lengthSigner) Extension() string { return ".sig" }
+
+func (lengthSigner) Sign(src io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(src)
+	return []byte{byte(len(data))}, err
+}
+
+func TestFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "export.csv")
+	assert.NoError(t, os.WriteFile(path, []byte("hash,value\n"), 0644))
+
+	written, err := File(path, upperEncrypter{}, lengthSigner{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{path + ".up", path + ".up.sig"}, written)
+
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err), "plaintext must be removed")
+
+	encrypted, _ := os.ReadFile(path + ".up")
+	assert.Equal(t, "HASH,VALUE\n", string(encrypted))
+	signature, _ := os.ReadFile(path + ".up.sig")
+	assert.Equal(t, []byte{11}, signature)
+}
+
+func TestFile_SignOnly(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "export.csv")
+	assert.NoError(t, os.WriteFile(path, []byte("data"), 0644))
+
+	written, err := File(path, nil, lengthSigner{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{path, path + ".sig"}, written)
+}
+
+func TestParseEncrypter(t *testing.T) {
+	enc, err := ParseEncrypter("age:age1examplerecipient")
+	assert.NoError(t, err)
+	assert.Equal(t, ".age", enc.Extension())
+
+	enc, err = ParseEncrypter("GPG:reports@example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, ".gpg", enc.Extension())
+
+	_, err = ParseEncrypter("rot13:key")
+	assert.Error(t, err)
+	_, err = ParseEncrypter("age")
+	assert.Error(t, err)
+
+	t.Setenv("VAULT_ADDR", "")
+	_, err = ParseSigner("vault:reports")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "VAULT_ADDR")
+
+	t.Setenv("AWS_REGION", "eu-west-1")
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	enc, err = ParseEncrypter("kms:alias/reports")
+	assert.NoError(t, err)
+	assert.Equal(t, ".enc", enc.Extension())
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	_, err = ParseSigner("kms:alias/signing")
+	assert.ErrorContains(t, err, "AWS_ACCESS_KEY_ID")
+}
+
+func TestCommand(t *testing.T) {
+	if _, err := os.Stat("/bin/cat"); err != nil {
+		t.Skip("cat not available")
+	}
+	var out bytes.Buffer
+	assert.NoError(t, Command{Path: "cat"}.Encrypt(&out, bytes.NewReader([]byte("payload"))))
+	assert.Equal(t, "payload", out.String())
+
+	_, err := Command{Path: "false"}.Sign(bytes.NewReader(nil))
+	assert.Error(t, err)
+}
@@ -0,0 +1,129 @@
+package protect
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// VaultTransit is a KeyService backed by the transit secrets engine of
+// HashiCorp Vault
+type VaultTransit struct {
+	Addr  string
+	Token string
+	// Mount is the path the transit engine is mounted at, "transit" by default
+	Mount      string
+	Key        string
+	HTTPClient *http.Client
+}
+
+// VaultTransitFromEnv configures a transit key from VAULT_ADDR and
+// VAULT_TOKEN, with an optional VAULT_TRANSIT_MOUNT
+func VaultTransitFromEnv(key string) (*VaultTransit, error) {
+	addr, token := os.Getenv("VAULT_ADDR"), os.Getenv("VAULT_TOKEN")
+	if addr == "" || token == "" {
+		return nil, errors.New("vault provider requires VAULT_ADDR and VAULT_TOKEN")
+	}
+	return &VaultTransit{
+		Addr:       addr,
+		Token:      token,
+		Mount:      os.Getenv("VAULT_TRANSIT_MOUNT"),
+		Key:        key,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// DataKey implements KeyService
+func (v *VaultTransit) DataKey() ([]byte, []byte, error) {
+	var resp struct {
+		Plaintext  string `json:"plaintext"`
+		Ciphertext string `json:"ciphertext"`
+	}
+	if err := v.post("datakey/plaintext/"+v.Key, map[string]interface{}{"bits": 256}, &resp); err != nil {
+		return nil, nil, err
+	}
+	key, err := base64.StdEncoding.DecodeString(resp.Plaintext)
+	if err != nil {
+		return nil, nil, err
+	}
+	return key, []byte(resp.Ciphertext), nil
+}
+
+// Unwrap implements KeyService
+func (v *VaultTransit) Unwrap(wrapped []byte) ([]byte, error) {
+	var resp struct {
+		Plaintext string `json:"plaintext"`
+	}
+	if err := v.post("decrypt/"+v.Key, map[string]interface{}{"ciphertext": string(wrapped)}, &resp); err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(resp.Plaintext)
+}
+
+// Sign implements KeyService. The signature is returned in Vault's
+// "vault:v1:..." form, which `vault write transit/verify` accepts.
+func (v *VaultTransit) Sign(digest []byte) ([]byte, error) {
+	var resp struct {
+		Signature string `json:"signature"`
+	}
+	body := map[string]interface{}{
+		"input":     base64.StdEncoding.EncodeToString(digest),
+		"prehashed": true,
+	}
+	if err := v.post("sign/"+v.Key+"/sha2-256", body, &resp); err != nil {
+		return nil, err
+	}
+	return []byte(resp.Signature), nil
+}
+
+func (v *VaultTransit) post(path string, body interface{}, result interface{}) error {
+	mount := v.Mount
+	if mount == "" {
+		mount = "transit"
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/v1/%s/%s", strings.TrimRight(v.Addr, "/"), mount, path)
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Vault-Token", v.Token)
+	req.Header.Set("Content-Type", "application/json")
+
+	client := v.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("vault request %s failed with status code %d: %s", path, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return err
+	}
+	return json.Unmarshal(envelope.Data, result)
+}
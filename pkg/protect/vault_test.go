@@ -0,0 +1,55 @@
+package protect
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVaultTransit(t *testing.T) {
+	key := bytes.Repeat([]byte{42}, 32)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "s.token", r.Header.Get("X-Vault-Token"))
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		var data map[string]interface{}
+		switch r.URL.Path {
+		case "/v1/transit/datakey/plaintext/reports":
+			data = map[string]interface{}{"plaintext": base64.StdEncoding.EncodeToString(key), "ciphertext": "vault:v1:wrapped"}
+		case "/v1/transit/decrypt/reports":
+			assert.Equal(t, "vault:v1:wrapped", body["ciphertext"])
+			data = map[string]interface{}{"plaintext": base64.StdEncoding.EncodeToString(key)}
+		case "/v1/transit/sign/reports/sha2-256":
+			assert.Equal(t, true, body["prehashed"])
+			data = map[string]interface{}{"signature": "vault:v1:signature"}
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+	}))
+	defer server.Close()
+
+	vault := &VaultTransit{Addr: server.URL, Token: "s.token", Key: "reports"}
+	env := &Envelope{Keys: vault}
+
+	var encrypted, decrypted bytes.Buffer
+	assert.NoError(t, env.Encrypt(&encrypted, bytes.NewReader([]byte("secret report"))))
+	assert.NoError(t, env.Decrypt(&decrypted, &encrypted))
+	assert.Equal(t, "secret report", decrypted.String())
+
+	signature, err := (&KeySigner{Keys: vault}).Sign(bytes.NewReader([]byte("secret report")))
+	assert.NoError(t, err)
+	assert.Equal(t, "vault:v1:signature", string(signature))
+
+	vault.Key = "missing"
+	_, _, err = vault.DataKey()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "404")
+}
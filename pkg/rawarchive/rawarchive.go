@@ -0,0 +1,88 @@
+// Package rawarchive saves the raw JSON responses of provider calls as
+// gzipped files, one per page, next to the processed export. The archive is
+// an audit trail of what the provider returned, and lets a later version of
+// the converters process the responses again without fetching them.
+package rawarchive
+
+import (
+	"compress/gzip"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/haridev22/ct-assignement/pkg/audit"
+)
+
+// Archive writes responses to a directory, which is only created once the
+// first response is saved. A nil *Archive discards responses, so clients can
+// call Save unconditionally.
+type Archive struct {
+	dir  string
+	once sync.Once
+	err  error
+}
+
+// New returns an archive of responses saved to dir
+func New(dir string) *Archive {
+	return &Archive{dir: dir}
+}
+
+// Dir returns the directory responses are saved to
+func (a *Archive) Dir() string {
+	if a == nil {
+		return ""
+	}
+	return a.dir
+}
+
+// FileName names the archive file of the request described by params. Pages
+// of a block range are named by action, range and page, such as
+// txlist_0-99999_p2.json.gz, so fetching a page again replaces its file.
+// Other requests are named by action and a hash of their parameters.
+func FileName(params url.Values) string {
+	action := params.Get("action")
+	if action == "" {
+		action = "request"
+	}
+	start, end := params.Get("startblock"), params.Get("endblock")
+	if start == "" || end == "" {
+		return fmt.Sprintf("%s_%s.json.gz", action, audit.HashValues(params, "apikey")[:12])
+	}
+	name := fmt.Sprintf("%s_%s-%s", action, start, end)
+	if page := params.Get("page"); page != "" {
+		name += "_p" + page
+	}
+	return name + ".json.gz"
+}
+
+// Save writes the response body of the request described by params
+func (a *Archive) Save(params url.Values, body []byte) error {
+	if a == nil {
+		return nil
+	}
+	a.once.Do(func() {
+		if err := os.MkdirAll(a.dir, 0755); err != nil {
+			a.err = fmt.Errorf("failed to create raw archive directory: %w", err)
+		}
+	})
+	if a.err != nil {
+		return a.err
+	}
+
+	file, err := os.Create(filepath.Join(a.dir, FileName(params)))
+	if err != nil {
+		return fmt.Errorf("failed to create raw archive file: %w", err)
+	}
+	zw := gzip.NewWriter(file)
+	if _, err := zw.Write(body); err != nil {
+		file.Close()
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
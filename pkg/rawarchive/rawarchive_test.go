@@ -0,0 +1,49 @@
+package rawarchive
+
+import (
+	"compress/gzip"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileName(t *testing.T) {
+	page := url.Values{"action": {"txlist"}, "startblock": {"0"}, "endblock": {"99999"}, "page": {"2"}, "apikey": {"secret"}}
+	assert.Equal(t, "txlist_0-99999_p2.json.gz", FileName(page))
+
+	other := url.Values{"action": {"eth_blockNumber"}, "apikey": {"secret"}}
+	name := FileName(other)
+	assert.Regexp(t, `^eth_blockNumber_[0-9a-f]{12}\.json\.gz$`, name)
+	other.Set("apikey", "another")
+	assert.Equal(t, name, FileName(other), "the API key does not change the name")
+}
+
+func TestArchive_Save(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "0xabc_raw")
+	archive := New(dir)
+	_, err := os.Stat(dir)
+	assert.True(t, os.IsNotExist(err), "nothing saved, no directory")
+
+	params := url.Values{"action": {"txlist"}, "startblock": {"0"}, "endblock": {"10"}, "page": {"1"}}
+	body := []byte(`{"status":"1","message":"OK","result":[]}`)
+	assert.NoError(t, archive.Save(params, body))
+
+	file, err := os.Open(filepath.Join(dir, "txlist_0-10_p1.json.gz"))
+	assert.NoError(t, err)
+	defer file.Close()
+	zr, err := gzip.NewReader(file)
+	assert.NoError(t, err)
+	saved, err := io.ReadAll(zr)
+	assert.NoError(t, err)
+	assert.Equal(t, body, saved)
+}
+
+func TestNilArchive(t *testing.T) {
+	var archive *Archive
+	assert.NoError(t, archive.Save(url.Values{}, nil))
+	assert.Empty(t, archive.Dir())
+}
@@ -0,0 +1,157 @@
+package report
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+)
+
+// Balances replays the native currency movements of addresses to compute
+// their balance after every row. Balances are kept between calls to Apply,
+// so each batch of a batched export continues where the previous one ended.
+type Balances struct {
+	// Opening is the balance of every address before its first row
+	Opening *big.Rat
+	running map[string]*runningBalance
+}
+
+type runningBalance struct {
+	balance *big.Rat
+	// applied holds the balance written to each replayed row, so rows
+	// fetched again, such as those of the block shared by consecutive
+	// batches, are not counted twice
+	applied map[string]string
+	// charged holds the hashes whose fee has been deducted
+	charged map[string]bool
+}
+
+// Shortfall is a block after which a replayed balance is below zero. Either
+// rows moving funds into the address are missing, such as internal
+// transfers, or the opening balance is too low.
+type Shortfall struct {
+	Block   int64
+	Balance *big.Rat
+}
+
+// NewBalances returns balances starting at opening, or at zero when opening
+// is nil
+func NewBalances(opening *big.Rat) *Balances {
+	if opening == nil {
+		opening = new(big.Rat)
+	}
+	return &Balances{Opening: opening, running: make(map[string]*runningBalance)}
+}
+
+func (b *Balances) of(address string) *runningBalance {
+	key := strings.ToLower(address)
+	r := b.running[key]
+	if r == nil {
+		r = &runningBalance{
+			balance: new(big.Rat).Set(b.Opening),
+			applied: make(map[string]string),
+			charged: make(map[string]bool),
+		}
+		b.running[key] = r
+	}
+	return r
+}
+
+// Balance returns the balance of address after the rows applied so far
+func (b *Balances) Balance(address string) *big.Rat {
+	return new(big.Rat).Set(b.of(address).balance)
+}
+
+// Apply sorts transactions by block, keeping the order of rows within a
+// block, and sets the Balance of each row to the balance of address after
+// it. Successful native rows move their value, and sent transactions are
+// charged their gas and L1 fees even when they failed. Token rows leave the
+// balance unchanged. Apply returns the blocks after which the balance is
+// below zero.
+func (b *Balances) Apply(address string, transactions []models.Transaction) []Shortfall {
+	sort.SliceStable(transactions, func(i, j int) bool {
+		return transactions[i].BlockNumber < transactions[j].BlockNumber
+	})
+
+	r := b.of(address)
+	var shortfalls []Shortfall
+	replayed := false
+	for i := range transactions {
+		tx := &transactions[i]
+		id := tx.RowID()
+		if balance, ok := r.applied[id]; ok {
+			tx.Balance = balance
+		} else {
+			r.balance.Add(r.balance, r.change(address, tx))
+			tx.Balance = formatRat(r.balance)
+			r.applied[id] = tx.Balance
+			replayed = true
+		}
+
+		endOfBlock := i == len(transactions)-1 || transactions[i+1].BlockNumber != tx.BlockNumber
+		if endOfBlock {
+			if replayed && r.balance.Sign() < 0 {
+				shortfalls = append(shortfalls, Shortfall{Block: tx.BlockNumber, Balance: new(big.Rat).Set(r.balance)})
+			}
+			replayed = false
+		}
+	}
+	return shortfalls
+}
+
+// change is the amount tx adds to the balance of address
+func (r *runningBalance) change(address string, tx *models.Transaction) *big.Rat {
+	change := new(big.Rat)
+	incoming := strings.EqualFold(tx.To, address)
+	outgoing := strings.EqualFold(tx.From, address)
+
+	if assetKey(tx) == NativeAsset && !tx.Failed() {
+		if amount, ok := new(big.Rat).SetString(tx.Value); ok {
+			if incoming {
+				change.Add(change, amount)
+			}
+			if outgoing {
+				change.Sub(change, amount)
+			}
+		}
+	}
+
+	// Every sent transaction has an ETH_TRANSFER row; token rows repeat its
+	// fee, so only that row is charged
+	hash := strings.ToLower(tx.Hash)
+	if tx.Type == models.TypeEthTransfer && outgoing && !r.charged[hash] {
+		r.charged[hash] = true
+		for _, fee := range []string{tx.GasFee, tx.L1Fee} {
+			if amount, ok := new(big.Rat).SetString(fee); ok {
+				change.Sub(change, amount)
+			}
+		}
+	}
+	return change
+}
+
+// Resume continues the balance of address from the rows an interrupted run
+// exported, which must carry their balance
+func (b *Balances) Resume(address string, transactions []models.Transaction) error {
+	rows := append([]models.Transaction(nil), transactions...)
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].BlockNumber < rows[j].BlockNumber
+	})
+
+	r := b.of(address)
+	for i := range rows {
+		tx := &rows[i]
+		balance, ok := new(big.Rat).SetString(tx.Balance)
+		if !ok {
+			return fmt.Errorf("row %s has no running balance", tx.RowID())
+		}
+		r.balance = balance
+		r.applied[tx.RowID()] = tx.Balance
+		if tx.Type == models.TypeEthTransfer && strings.EqualFold(tx.From, address) {
+			r.charged[strings.ToLower(tx.Hash)] = true
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package report
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBalances_Apply(t *testing.T) {
+	txs := []models.Transaction{
+		// A token row of the swap repeats its fee and is listed out of order
+		{Hash: "0x2", BlockNumber: 20, Type: models.TypeERC20Transfer, From: "0xrouter", To: "0xwallet", AssetSymbol: "USDC", Value: "100", GasFee: "0.01"},
+		{Hash: "0x1", BlockNumber: 10, Type: models.TypeEthTransfer, From: "0xexchange", To: "0xwallet", Value: "2", GasFee: "0.001"},
+		{Hash: "0x2", BlockNumber: 20, Type: models.TypeEthTransfer, From: "0xwallet", To: "0xrouter", Value: "0.5", GasFee: "0.01", L1Fee: "0.002"},
+		{Hash: "0x3", BlockNumber: 30, Type: models.TypeEthTransfer, From: "0xwallet", To: "0xrouter", Value: "1", GasFee: "0.02", Status: models.StatusFailed},
+		{Hash: "0x4", BlockNumber: 40, Type: models.TypeInternalTx, From: "0xrouter", To: "0xwallet", Value: "0.25"},
+	}
+
+	balances := NewBalances(big.NewRat(1, 10))
+	shortfalls := balances.Apply(wallet, txs)
+	assert.Empty(t, shortfalls)
+
+	// Rows are sorted by block, keeping their order within a block
+	var got []string
+	for _, tx := range txs {
+		got = append(got, tx.Hash+" "+tx.Balance)
+	}
+	assert.Equal(t, []string{"0x1 2.1", "0x2 2.1", "0x2 1.588", "0x3 1.568", "0x4 1.818"}, got)
+	assert.Equal(t, "1.818", formatRat(balances.Balance(wallet)))
+}
+
+func TestBalances_ApplyAcrossBatches(t *testing.T) {
+	deposit := models.Transaction{Hash: "0x1", BlockNumber: 100, Type: models.TypeEthTransfer, From: "0xexchange", To: "0xwallet", Value: "1"}
+	spend := models.Transaction{Hash: "0x2", BlockNumber: 200, Type: models.TypeEthTransfer, From: "0xwallet", To: "0xshop", Value: "0.4", GasFee: "0.1"}
+	later := models.Transaction{Hash: "0x3", BlockNumber: 300, Type: models.TypeEthTransfer, From: "0xwallet", To: "0xshop", Value: "0.1", GasFee: "0.1"}
+
+	balances := NewBalances(nil)
+	first := []models.Transaction{deposit, spend}
+	balances.Apply(wallet, first)
+
+	// The next batch fetches the shared boundary block again
+	second := []models.Transaction{spend, later}
+	balances.Apply(wallet, second)
+	assert.Equal(t, "0.5", first[1].Balance)
+	assert.Equal(t, "0.5", second[0].Balance)
+	assert.Equal(t, "0.3", second[1].Balance)
+
+	// Other addresses start from the opening balance
+	assert.Equal(t, "0", formatRat(balances.Balance("0xother")))
+}
+
+func TestBalances_ApplyShortfalls(t *testing.T) {
+	txs := []models.Transaction{
+		{Hash: "0x1", BlockNumber: 10, Type: models.TypeEthTransfer, From: "0xwallet", To: "0xshop", Value: "1", GasFee: "0.1"},
+		// The refund in the same block is missing from the provider data
+		{Hash: "0x2", BlockNumber: 20, Type: models.TypeEthTransfer, From: "0xwallet", To: "0xshop", Value: "1", GasFee: "0.1"},
+		{Hash: "0x3", BlockNumber: 20, Type: models.TypeEthTransfer, From: "0xexchange", To: "0xwallet", Value: "5"},
+	}
+
+	// A balance that dips below zero within a block is not reported
+	shortfalls := NewBalances(big.NewRat(2, 1)).Apply(wallet, txs)
+	assert.Empty(t, shortfalls)
+
+	shortfalls = NewBalances(nil).Apply(wallet, txs)
+	if assert.Len(t, shortfalls, 1) {
+		assert.Equal(t, int64(10), shortfalls[0].Block)
+		assert.Equal(t, "-1.1", formatRat(shortfalls[0].Balance))
+	}
+}
+
+func TestBalances_Resume(t *testing.T) {
+	exported := []models.Transaction{
+		{Hash: "0x1", BlockNumber: 100, Type: models.TypeEthTransfer, From: "0xexchange", To: "0xwallet", Value: "1", Balance: "1"},
+		{Hash: "0x2", BlockNumber: 200, Type: models.TypeEthTransfer, From: "0xwallet", To: "0xshop", Value: "0.4", GasFee: "0.1", Balance: "0.5"},
+	}
+	balances := NewBalances(nil)
+	assert.NoError(t, balances.Resume(wallet, exported))
+	assert.Equal(t, "0.5", formatRat(balances.Balance(wallet)))
+
+	// The boundary block is not replayed again
+	next := []models.Transaction{exported[1], {Hash: "0x3", BlockNumber: 300, Type: models.TypeInternalTx, From: "0xrouter", To: "0xwallet", Value: "0.2"}}
+	next[0].Balance = ""
+	balances.Apply(wallet, next)
+	assert.Equal(t, "0.5", next[0].Balance)
+	assert.Equal(t, "0.7", next[1].Balance)
+
+	// Rows exported without -running-balance cannot be continued
+	exported[0].Balance = ""
+	assert.Error(t, NewBalances(nil).Resume(wallet, exported))
+}
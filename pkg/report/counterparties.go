@@ -0,0 +1,201 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+)
+
+// Counterparty is an address that sent to or received from the reported
+// address, with the amounts that moved between them
+type Counterparty struct {
+	// Address is lowercased
+	Address string
+	// Label is the name given to the address in a labels file
+	Label string
+	// Name is the primary ENS name of the address
+	Name string
+	// Transactions is the number of distinct transactions with the address
+	Transactions int
+	// Assets are the amounts received from the address (Incoming) and sent
+	// to it (Outgoing), native coin first. Failed rows move nothing.
+	Assets []AssetTotal
+}
+
+// Native returns the native coin moved with the counterparty
+func (c Counterparty) Native() AssetTotal {
+	for _, asset := range c.Assets {
+		if asset.Contract == "" {
+			return asset
+		}
+	}
+	return AssetTotal{Symbol: NativeAsset, Incoming: new(big.Rat), Outgoing: new(big.Rat)}
+}
+
+// CounterpartyOrder selects how counterparties are ranked
+type CounterpartyOrder string
+
+const (
+	// ByTransactions ranks by the number of transactions
+	ByTransactions CounterpartyOrder = "count"
+	// ByIncoming ranks by the native amount received from the counterparty
+	ByIncoming CounterpartyOrder = "in"
+	// ByOutgoing ranks by the native amount sent to the counterparty
+	ByOutgoing CounterpartyOrder = "out"
+)
+
+// ParseCounterpartyOrder parses a ranking name
+func ParseCounterpartyOrder(s string) (CounterpartyOrder, error) {
+	switch order := CounterpartyOrder(s); order {
+	case ByTransactions, ByIncoming, ByOutgoing:
+		return order, nil
+	}
+	return "", fmt.Errorf("unknown order %q (use count, in or out)", s)
+}
+
+// Counterparties returns every address that address sent to or received
+// from, ranked by order; ties are broken by transactions, then address.
+// Rows between two other addresses and self-transfers are skipped.
+func Counterparties(address string, transactions []models.Transaction, order CounterpartyOrder) []Counterparty {
+	type tally struct {
+		hashes map[string]bool
+		rows   []models.Transaction
+	}
+	byAddress := make(map[string]*tally)
+	for i := range transactions {
+		tx := &transactions[i]
+		incoming := strings.EqualFold(tx.To, address)
+		outgoing := strings.EqualFold(tx.From, address)
+		party := tx.To
+		if incoming {
+			party = tx.From
+		}
+		if incoming == outgoing || party == "" {
+			continue
+		}
+		key := strings.ToLower(party)
+		t := byAddress[key]
+		if t == nil {
+			t = &tally{hashes: make(map[string]bool)}
+			byAddress[key] = t
+		}
+		t.hashes[strings.ToLower(tx.Hash)] = true
+		t.rows = append(t.rows, *tx)
+	}
+
+	result := make([]Counterparty, 0, len(byAddress))
+	for party, t := range byAddress {
+		result = append(result, Counterparty{
+			Address:      party,
+			Transactions: len(t.hashes),
+			Assets:       Totals(address, t.rows),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		var cmp int
+		switch order {
+		case ByIncoming:
+			cmp = a.Native().Incoming.Cmp(b.Native().Incoming)
+		case ByOutgoing:
+			cmp = a.Native().Outgoing.Cmp(b.Native().Outgoing)
+		}
+		if cmp != 0 {
+			return cmp > 0
+		}
+		if a.Transactions != b.Transactions {
+			return a.Transactions > b.Transactions
+		}
+		return a.Address < b.Address
+	})
+	return result
+}
+
+// CounterpartyReport is the ranked counterparties of an address
+type CounterpartyReport struct {
+	Address string
+	Order   CounterpartyOrder
+	// Total is the number of counterparties before Top was applied
+	Total int
+	Top   []Counterparty
+}
+
+// Write prints the report in a plain-text layout
+func (r CounterpartyReport) Write(w io.Writer) error {
+	if _, err := fmt.Fprintf(w, "Counterparties of %s: %d, top %d by %s\n", r.Address, r.Total, len(r.Top), orderNames[r.Order]); err != nil {
+		return err
+	}
+	for i, c := range r.Top {
+		name := c.Address
+		switch {
+		case c.Label != "" && c.Name != "":
+			name += fmt.Sprintf(" (%s, %s)", c.Label, c.Name)
+		case c.Label != "":
+			name += fmt.Sprintf(" (%s)", c.Label)
+		case c.Name != "":
+			name += fmt.Sprintf(" (%s)", c.Name)
+		}
+		native := c.Native()
+		if _, err := fmt.Fprintf(w, "%3d. %s\n     %d transaction(s), %s %s in, %s out\n",
+			i+1, name, c.Transactions, NativeAsset, formatRat(native.Incoming), formatRat(native.Outgoing)); err != nil {
+			return err
+		}
+		for _, asset := range c.Assets {
+			if asset.Contract == "" {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "     %s (%s): %d transfer(s), %s in, %s out\n",
+				asset.Symbol, asset.Contract, asset.Count, formatRat(asset.Incoming), formatRat(asset.Outgoing)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+var orderNames = map[CounterpartyOrder]string{
+	ByTransactions: "transactions",
+	ByIncoming:     NativeAsset + " received",
+	ByOutgoing:     NativeAsset + " sent",
+}
+
+type counterpartyReportJSON struct {
+	Address        string             `json:"address"`
+	Order          string             `json:"order"`
+	Counterparties int                `json:"counterparties"`
+	Top            []counterpartyJSON `json:"top"`
+}
+
+type counterpartyJSON struct {
+	Address      string      `json:"address"`
+	Label        string      `json:"label,omitempty"`
+	Name         string      `json:"ens,omitempty"`
+	Transactions int         `json:"transactions"`
+	Assets       []assetJSON `json:"assets"`
+}
+
+// MarshalJSON implements json.Marshaler. Amounts are decimal strings, so
+// they keep their precision.
+func (r CounterpartyReport) MarshalJSON() ([]byte, error) {
+	out := counterpartyReportJSON{
+		Address:        r.Address,
+		Order:          string(r.Order),
+		Counterparties: r.Total,
+		Top:            make([]counterpartyJSON, len(r.Top)),
+	}
+	for i, c := range r.Top {
+		out.Top[i] = counterpartyJSON{
+			Address:      c.Address,
+			Label:        c.Label,
+			Name:         c.Name,
+			Transactions: c.Transactions,
+			Assets:       assetsJSON(c.Assets),
+		}
+	}
+	return json.Marshal(out)
+}
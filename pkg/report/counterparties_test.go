@@ -0,0 +1,83 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCounterparties(t *testing.T) {
+	got := Counterparties(wallet, overviewRows(), ByTransactions)
+
+	assert.Len(t, got, 2)
+	assert.Equal(t, "0xexchange", got[0].Address, "addresses are compared case-insensitively")
+	assert.Equal(t, 2, got[0].Transactions)
+	assert.Equal(t, "2", FormatAmount(got[0].Native().Incoming))
+	assert.Equal(t, "0", FormatAmount(got[0].Native().Outgoing), "failed rows move nothing")
+
+	assert.Equal(t, "0xrouter", got[1].Address)
+	assert.Equal(t, 1, got[1].Transactions, "rows of one transaction count once")
+	assert.Equal(t, "0.5", FormatAmount(got[1].Native().Outgoing))
+	assert.Len(t, got[1].Assets, 2)
+	assert.Equal(t, "1500.25", FormatAmount(got[1].Assets[1].Incoming))
+}
+
+func TestCounterparties_Order(t *testing.T) {
+	rows := append(overviewRows(),
+		models.Transaction{Hash: "0x4", BlockNumber: 40, Type: models.TypeEthTransfer, From: "0xwallet", To: "0xwallet", Value: "9"},
+		models.Transaction{Hash: "0x5", BlockNumber: 50, Type: models.TypeERC20Transfer, From: "0xrouter", To: "0xother", Value: "9"},
+		models.Transaction{Hash: "0x6", BlockNumber: 60, Type: models.TypeContractCall, From: "0xwallet", To: "", Value: "0"},
+	)
+
+	in := Counterparties(wallet, rows, ByIncoming)
+	assert.Len(t, in, 2, "self-transfers, rows of other addresses and empty addresses are skipped")
+	assert.Equal(t, "0xexchange", in[0].Address)
+
+	out := Counterparties(wallet, rows, ByOutgoing)
+	assert.Equal(t, "0xrouter", out[0].Address)
+}
+
+func TestParseCounterpartyOrder(t *testing.T) {
+	order, err := ParseCounterpartyOrder("in")
+	assert.NoError(t, err)
+	assert.Equal(t, ByIncoming, order)
+
+	_, err = ParseCounterpartyOrder("value")
+	assert.Error(t, err)
+}
+
+func TestCounterpartyReport_Write(t *testing.T) {
+	top := Counterparties(wallet, overviewRows(), ByTransactions)
+	top[0].Label = "Exchange"
+	top[1].Name = "router.eth"
+	r := CounterpartyReport{Address: wallet, Order: ByTransactions, Total: 2, Top: top}
+
+	var buf bytes.Buffer
+	assert.NoError(t, r.Write(&buf))
+	assert.Equal(t, "Counterparties of 0xWallet: 2, top 2 by transactions\n"+
+		"  1. 0xexchange (Exchange)\n     2 transaction(s), ETH 2 in, 0 out\n"+
+		"  2. 0xrouter (router.eth)\n     1 transaction(s), ETH 0 in, 0.5 out\n"+
+		"     USDC (0xusdc): 1 transfer(s), 1500.25 in, 0 out\n", buf.String())
+}
+
+func TestCounterpartyReport_MarshalJSON(t *testing.T) {
+	top := Counterparties(wallet, overviewRows(), ByOutgoing)[:1]
+	top[0].Name = "router.eth"
+	data, err := json.Marshal(CounterpartyReport{Address: wallet, Order: ByOutgoing, Total: 2, Top: top})
+	assert.NoError(t, err)
+
+	var got map[string]interface{}
+	assert.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, "out", got["order"])
+	assert.Equal(t, 2.0, got["counterparties"])
+	entries := got["top"].([]interface{})
+	assert.Len(t, entries, 1)
+	entry := entries[0].(map[string]interface{})
+	assert.Equal(t, "0xrouter", entry["address"])
+	assert.Equal(t, "router.eth", entry["ens"])
+	assert.NotContains(t, entry, "label")
+	assert.Len(t, entry["assets"], 2)
+}
@@ -0,0 +1,184 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+)
+
+// NativeAsset is the holdings key of the chain's native coin
+const NativeAsset = "ETH"
+
+// Delta describes how the rows of an address changed between two snapshots,
+// typically the previous run and the current one
+type Delta struct {
+	// PreviousRun and PreviousTime identify the earlier snapshot for display
+	PreviousRun  int64
+	PreviousTime time.Time
+
+	New     int
+	Removed int
+	// GasSpent is the gas paid by the address in transactions not present
+	// in the earlier snapshot, in ETH
+	GasSpent *big.Rat
+	// Holdings is the net change per asset, keyed by NativeAsset or by
+	// "SYMBOL (contract)" for tokens. Assets that did not change are omitted.
+	Holdings map[string]*big.Rat
+}
+
+// Compare computes the changes from previous to current for address
+func Compare(address string, previous, current []models.Transaction) Delta {
+	delta := Delta{
+		GasSpent: new(big.Rat),
+		Holdings: make(map[string]*big.Rat),
+	}
+
+	before := make(map[string]bool, len(previous))
+	for i := range previous {
+		before[previous[i].RowID()] = true
+	}
+	after := make(map[string]bool, len(current))
+	for i := range current {
+		after[current[i].RowID()] = true
+		if !before[current[i].RowID()] {
+			delta.New++
+		}
+	}
+	for key := range before {
+		if !after[key] {
+			delta.Removed++
+		}
+	}
+
+	delta.GasSpent.Sub(gasSpent(address, current), gasSpent(address, previous))
+
+	// Corrected rows change the holdings too, so compare full balances
+	// rather than only the new rows
+	now, then := flows(address, current), flows(address, previous)
+	for asset, amount := range now {
+		if prev, ok := then[asset]; ok {
+			amount = new(big.Rat).Sub(amount, prev)
+		}
+		if amount.Sign() != 0 {
+			delta.Holdings[asset] = amount
+		}
+	}
+	for asset, amount := range then {
+		if _, ok := now[asset]; !ok && amount.Sign() != 0 {
+			delta.Holdings[asset] = new(big.Rat).Neg(amount)
+		}
+	}
+
+	return delta
+}
+
+// Write prints the delta in a plain-text layout
+func (d Delta) Write(w io.Writer) error {
+	since := fmt.Sprintf("run %d", d.PreviousRun)
+	if !d.PreviousTime.IsZero() {
+		since += " (" + d.PreviousTime.Format(time.RFC3339) + ")"
+	}
+	if _, err := fmt.Fprintf(w, "Changes since %s:\n  New transactions:    %d\n  Removed:             %d\n  Gas spent (ETH):     %s\n",
+		since, d.New, d.Removed, formatRat(d.GasSpent)); err != nil {
+		return err
+	}
+
+	if len(d.Holdings) == 0 {
+		_, err := fmt.Fprintln(w, "  Holdings unchanged")
+		return err
+	}
+	assets := make([]string, 0, len(d.Holdings))
+	for asset := range d.Holdings {
+		assets = append(assets, asset)
+	}
+	sort.Strings(assets)
+	if _, err := fmt.Fprintln(w, "  Holdings change:"); err != nil {
+		return err
+	}
+	for _, asset := range assets {
+		amount := formatRat(d.Holdings[asset])
+		if d.Holdings[asset].Sign() > 0 {
+			amount = "+" + amount
+		}
+		if _, err := fmt.Fprintf(w, "    %-40s %s\n", asset, amount); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// gasSpent sums the fees of transactions sent by address. Every sent
+// transaction has an ETH_TRANSFER row; token rows repeat its fee, so they
+// are not counted again.
+func gasSpent(address string, transactions []models.Transaction) *big.Rat {
+	total := new(big.Rat)
+	seen := make(map[string]bool)
+	for i := range transactions {
+		tx := &transactions[i]
+		if tx.Type != models.TypeEthTransfer || !strings.EqualFold(tx.From, address) {
+			continue
+		}
+		hash := strings.ToLower(tx.Hash)
+		if seen[hash] {
+			continue
+		}
+		seen[hash] = true
+		if fee, ok := new(big.Rat).SetString(tx.GasFee); ok {
+			total.Add(total, fee)
+		}
+	}
+	return total
+}
+
+// flows returns the net amount of every asset moved into address, with the
+// gas it paid deducted from the native balance
+func flows(address string, transactions []models.Transaction) map[string]*big.Rat {
+	result := make(map[string]*big.Rat)
+	add := func(asset string, amount *big.Rat) {
+		if result[asset] == nil {
+			result[asset] = new(big.Rat)
+		}
+		result[asset].Add(result[asset], amount)
+	}
+
+	for i := range transactions {
+		tx := &transactions[i]
+		amount, ok := new(big.Rat).SetString(tx.Value)
+		if !ok || tx.Failed() {
+			continue
+		}
+		incoming := strings.EqualFold(tx.To, address)
+		outgoing := strings.EqualFold(tx.From, address)
+		if incoming == outgoing {
+			// Self-transfers and rows that do not involve address cancel out
+			continue
+		}
+		if outgoing {
+			amount.Neg(amount)
+		}
+		add(assetKey(tx), amount)
+	}
+
+	add(NativeAsset, new(big.Rat).Neg(gasSpent(address, transactions)))
+	return result
+}
+
+func assetKey(tx *models.Transaction) string {
+	switch tx.Type {
+	case models.TypeERC20Transfer, models.TypeERC721Transfer, models.TypeERC1155Transfer:
+		return fmt.Sprintf("%s (%s)", tx.AssetSymbol, strings.ToLower(tx.AssetContractAddr))
+	}
+	return NativeAsset
+}
+
+// formatRat prints r as a decimal without trailing zeros
+func formatRat(r *big.Rat) string {
+	s := r.FloatString(18)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
@@ -0,0 +1,50 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+const wallet = "0xWallet"
+
+func TestCompare(t *testing.T) {
+	deposit := models.Transaction{Hash: "0x1", Type: models.TypeEthTransfer, From: "0xexchange", To: "0xwallet", Value: "2.0", GasFee: "0.001"}
+	previous := []models.Transaction{deposit}
+
+	swap := models.Transaction{Hash: "0x2", Type: models.TypeEthTransfer, From: "0xwallet", To: "0xrouter", Value: "0.5", GasFee: "0.002"}
+	bought := models.Transaction{Hash: "0x2", Type: models.TypeERC20Transfer, From: "0xrouter", To: "0xwallet",
+		AssetContractAddr: "0xUSDC", AssetSymbol: "USDC", Value: "1500.25", GasFee: "0.002"}
+	failed := models.Transaction{Hash: "0x3", Type: models.TypeEthTransfer, From: "0xwallet", To: "0xrouter", Value: "1.0", GasFee: "0.003", Status: models.StatusFailed}
+	current := []models.Transaction{deposit, swap, bought, failed}
+
+	delta := Compare(wallet, previous, current)
+	assert.Equal(t, 3, delta.New)
+	assert.Equal(t, 0, delta.Removed)
+	assert.Equal(t, "0.005", formatRat(delta.GasSpent))
+	assert.Equal(t, "-0.505", formatRat(delta.Holdings[NativeAsset]))
+	assert.Equal(t, "1500.25", formatRat(delta.Holdings["USDC (0xusdc)"]))
+
+	// A reorged-out deposit is reported as removed and reduces holdings
+	delta = Compare(wallet, previous, nil)
+	assert.Equal(t, 1, delta.Removed)
+	assert.Equal(t, "-2", formatRat(delta.Holdings[NativeAsset]))
+}
+
+func TestDelta_Write(t *testing.T) {
+	delta := Compare(wallet, nil, nil)
+	delta.PreviousRun = 4
+
+	var buf bytes.Buffer
+	assert.NoError(t, delta.Write(&buf))
+	assert.Contains(t, buf.String(), "Changes since run 4:")
+	assert.Contains(t, buf.String(), "Holdings unchanged")
+
+	delta = Compare(wallet, nil, []models.Transaction{{Hash: "0x1", Type: models.TypeEthTransfer, From: "0xa", To: "0xwallet", Value: "1.5"}})
+	buf.Reset()
+	assert.NoError(t, delta.Write(&buf))
+	assert.Contains(t, buf.String(), "New transactions:    1")
+	assert.Contains(t, buf.String(), "+1.5")
+}
@@ -0,0 +1,174 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+)
+
+// Holding is the amount of one asset an address holds
+type Holding struct {
+	// Symbol is the asset symbol, NativeAsset for the native coin
+	Symbol string
+	// Contract is the lowercased token contract, empty for the native coin
+	Contract string
+	// TokenID identifies the NFT of an ERC-721 or ERC-1155 holding
+	TokenID string
+	Amount  *big.Rat
+}
+
+// Portfolio is what an address held after a block, derived from its
+// transfer history
+type Portfolio struct {
+	Address string
+	Block   int64
+	// Holdings are sorted with the native coin first, then by symbol,
+	// contract and token ID
+	Holdings []Holding
+}
+
+// HoldingsAt derives the portfolio of address after block from the rows up
+// to that block. The native balance is replayed like a running balance,
+// starting from zero; token balances are the net of their transfers. Assets
+// the address no longer holds are left out.
+func HoldingsAt(address string, block int64, transactions []models.Transaction) Portfolio {
+	var rows []models.Transaction
+	for i := range transactions {
+		if transactions[i].BlockNumber <= block {
+			rows = append(rows, transactions[i])
+		}
+	}
+
+	balances := NewBalances(nil)
+	balances.Apply(address, rows)
+	portfolio := Portfolio{Address: address, Block: block}
+	if native := balances.Balance(address); native.Sign() != 0 {
+		portfolio.Holdings = append(portfolio.Holdings, Holding{Symbol: NativeAsset, Amount: native})
+	}
+
+	tokens := make(map[string]*Holding)
+	seen := make(map[string]bool)
+	for i := range rows {
+		tx := &rows[i]
+		if assetKey(tx) == NativeAsset || tx.Failed() || seen[tx.RowID()] {
+			continue
+		}
+		seen[tx.RowID()] = true
+		amount, ok := new(big.Rat).SetString(tx.Value)
+		if !ok {
+			continue
+		}
+		incoming := strings.EqualFold(tx.To, address)
+		outgoing := strings.EqualFold(tx.From, address)
+		if incoming == outgoing {
+			continue
+		}
+		if outgoing {
+			amount.Neg(amount)
+		}
+
+		contract := strings.ToLower(tx.AssetContractAddr)
+		tokenID := ""
+		if tx.Type != models.TypeERC20Transfer {
+			tokenID = tx.TokenID
+		}
+		key := contract + "|" + tokenID
+		holding := tokens[key]
+		if holding == nil {
+			holding = &Holding{Symbol: tx.AssetSymbol, Contract: contract, TokenID: tokenID, Amount: new(big.Rat)}
+			tokens[key] = holding
+		}
+		holding.Amount.Add(holding.Amount, amount)
+	}
+
+	var held []Holding
+	for _, holding := range tokens {
+		if holding.Amount.Sign() != 0 {
+			held = append(held, *holding)
+		}
+	}
+	sort.Slice(held, func(i, j int) bool {
+		a, b := held[i], held[j]
+		if a.Symbol != b.Symbol {
+			return a.Symbol < b.Symbol
+		}
+		if a.Contract != b.Contract {
+			return a.Contract < b.Contract
+		}
+		return a.TokenID < b.TokenID
+	})
+	portfolio.Holdings = append(portfolio.Holdings, held...)
+	return portfolio
+}
+
+// Negative reports whether any holding is below zero, which means rows are
+// missing from the history
+func (p Portfolio) Negative() bool {
+	for _, holding := range p.Holdings {
+		if holding.Amount.Sign() < 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Write prints the portfolio in a plain-text layout
+func (p Portfolio) Write(w io.Writer) error {
+	if _, err := fmt.Fprintf(w, "Holdings of %s after block %d:\n", p.Address, p.Block); err != nil {
+		return err
+	}
+	if len(p.Holdings) == 0 {
+		_, err := fmt.Fprintln(w, "  None")
+		return err
+	}
+	for _, holding := range p.Holdings {
+		name := holding.Symbol
+		if holding.Contract != "" {
+			name = fmt.Sprintf("%s (%s)", holding.Symbol, holding.Contract)
+		}
+		if holding.TokenID != "" {
+			name += " #" + holding.TokenID
+		}
+		if _, err := fmt.Fprintf(w, "  %-56s %s\n", name, formatRat(holding.Amount)); err != nil {
+			return err
+		}
+	}
+	if p.Negative() {
+		_, err := fmt.Fprintln(w, "Warning: negative holdings mean rows are missing from the history")
+		return err
+	}
+	return nil
+}
+
+type portfolioJSON struct {
+	Address  string        `json:"address"`
+	Block    int64         `json:"block"`
+	Holdings []holdingJSON `json:"holdings"`
+}
+
+type holdingJSON struct {
+	Symbol   string `json:"symbol"`
+	Contract string `json:"contract,omitempty"`
+	TokenID  string `json:"token_id,omitempty"`
+	Amount   string `json:"amount"`
+}
+
+// MarshalJSON implements json.Marshaler. Amounts are decimal strings, so
+// they keep their precision.
+func (p Portfolio) MarshalJSON() ([]byte, error) {
+	out := portfolioJSON{Address: p.Address, Block: p.Block, Holdings: make([]holdingJSON, len(p.Holdings))}
+	for i, holding := range p.Holdings {
+		out.Holdings[i] = holdingJSON{
+			Symbol:   holding.Symbol,
+			Contract: holding.Contract,
+			TokenID:  holding.TokenID,
+			Amount:   formatRat(holding.Amount),
+		}
+	}
+	return json.Marshal(out)
+}
@@ -0,0 +1,64 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func holdingRows() []models.Transaction {
+	return append(overviewRows(),
+		models.Transaction{Hash: "0x4", BlockNumber: 40, Type: models.TypeERC20Transfer, From: "0xwallet", To: "0xshop", AssetContractAddr: "0xUSDC", AssetSymbol: "USDC", Value: "500.25"},
+		models.Transaction{Hash: "0x5", BlockNumber: 40, Type: models.TypeERC721Transfer, From: "0xmint", To: "0xwallet", AssetContractAddr: "0xPunks", AssetSymbol: "PUNK", TokenID: "7", Value: "1"},
+		models.Transaction{Hash: "0x6", BlockNumber: 50, Type: models.TypeERC721Transfer, From: "0xwallet", To: "0xbuyer", AssetContractAddr: "0xPunks", AssetSymbol: "PUNK", TokenID: "7", Value: "1"},
+	)
+}
+
+func TestHoldingsAt(t *testing.T) {
+	p := HoldingsAt(wallet, 45, holdingRows())
+
+	var got []string
+	for _, h := range p.Holdings {
+		got = append(got, h.Symbol+" "+h.TokenID+" "+formatRat(h.Amount))
+	}
+	// The failed transaction at block 30 still paid its gas
+	assert.Equal(t, []string{"ETH  1.495", "PUNK 7 1", "USDC  1000"}, got)
+	assert.False(t, p.Negative())
+
+	// Sold NFTs are no longer held
+	p = HoldingsAt(wallet, 50, holdingRows())
+	assert.Len(t, p.Holdings, 2)
+
+	// Nothing is held before the first row
+	p = HoldingsAt(wallet, 5, holdingRows())
+	assert.Empty(t, p.Holdings)
+}
+
+func TestPortfolio_Write(t *testing.T) {
+	p := HoldingsAt(wallet, 45, holdingRows())
+
+	var buf bytes.Buffer
+	assert.NoError(t, p.Write(&buf))
+	assert.Contains(t, buf.String(), "Holdings of 0xWallet after block 45:\n  ETH ")
+	assert.Contains(t, buf.String(), "  PUNK (0xpunks) #7")
+	assert.NotContains(t, buf.String(), "Warning")
+
+	// A transfer out without the transfer in leaves a negative holding
+	p = HoldingsAt(wallet, 50, holdingRows()[4:])
+	buf.Reset()
+	assert.NoError(t, p.Write(&buf))
+	assert.True(t, p.Negative())
+	assert.Contains(t, buf.String(), "Warning: negative holdings")
+}
+
+func TestPortfolio_MarshalJSON(t *testing.T) {
+	data, err := json.Marshal(HoldingsAt(wallet, 20, holdingRows()))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"address":"0xWallet","block":20,"holdings":[
+		{"symbol":"ETH","amount":"1.498"},
+		{"symbol":"USDC","contract":"0xusdc","amount":"1500.25"}
+	]}`, string(data))
+}
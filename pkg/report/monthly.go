@@ -0,0 +1,80 @@
+package report
+
+import (
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+)
+
+// Month is the activity of an address in one calendar month
+type Month struct {
+	// Start is the first day of the month in UTC
+	Start time.Time
+	// Transactions is the number of distinct transactions
+	Transactions int
+	// Incoming and Outgoing are the native amounts received and sent
+	Incoming *big.Rat
+	Outgoing *big.Rat
+	// Gas is the gas paid for the transactions the address sent
+	Gas *big.Rat
+	// Assets are the totals of every asset moved in the month, as Totals
+	// returns them
+	Assets []AssetTotal
+}
+
+// Monthly returns the activity of address per month, from the month of the
+// first row to that of the last, including months without activity. Failed
+// rows move nothing but count as transactions.
+func Monthly(address string, transactions []models.Transaction) []Month {
+	if len(transactions) == 0 {
+		return nil
+	}
+	first, last := transactions[0].Timestamp, transactions[0].Timestamp
+	for i := range transactions {
+		if ts := transactions[i].Timestamp; ts.Before(first) {
+			first = ts
+		} else if ts.After(last) {
+			last = ts
+		}
+	}
+
+	var months []Month
+	index := make(map[time.Time]int)
+	for start := monthOf(first); !start.After(last); start = start.AddDate(0, 1, 0) {
+		index[start] = len(months)
+		months = append(months, Month{Start: start, Incoming: new(big.Rat), Outgoing: new(big.Rat), Gas: new(big.Rat)})
+	}
+
+	byMonth := make([][]models.Transaction, len(months))
+	hashes := make([]map[string]bool, len(months))
+	for i := range transactions {
+		tx := &transactions[i]
+		n := index[monthOf(tx.Timestamp)]
+		byMonth[n] = append(byMonth[n], *tx)
+		if hashes[n] == nil {
+			hashes[n] = make(map[string]bool)
+		}
+		hashes[n][strings.ToLower(tx.Hash)] = true
+	}
+	for n := range months {
+		m := &months[n]
+		m.Transactions = len(hashes[n])
+		m.Gas = gasSpent(address, byMonth[n])
+		m.Assets = Totals(address, byMonth[n])
+		for _, total := range m.Assets {
+			if total.Contract == "" {
+				m.Incoming.Add(m.Incoming, total.Incoming)
+				m.Outgoing.Add(m.Outgoing, total.Outgoing)
+			}
+		}
+	}
+	return months
+}
+
+// monthOf returns the first day of the month of t in UTC
+func monthOf(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,35 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMonthly(t *testing.T) {
+	rows := append(overviewRows(), models.Transaction{
+		Hash: "0x4", BlockNumber: 40, Timestamp: time.Date(2024, 5, 20, 0, 0, 0, 0, time.UTC),
+		Type: models.TypeInternalTx, From: "0xrouter", To: "0xwallet", Value: "0.25",
+	})
+	months := Monthly(wallet, rows)
+
+	assert.Len(t, months, 3, "months without activity are included")
+	march := months[0]
+	assert.Equal(t, "2024-03-01", march.Start.Format("2006-01-02"))
+	assert.Equal(t, 3, march.Transactions, "rows of one transaction count once")
+	assert.Equal(t, "2", FormatAmount(march.Incoming))
+	assert.Equal(t, "0.5", FormatAmount(march.Outgoing), "failed rows move nothing")
+	assert.Equal(t, "0.005", FormatAmount(march.Gas))
+	assert.Len(t, march.Assets, 2)
+	assert.Equal(t, "1500.25", FormatAmount(march.Assets[1].Incoming))
+
+	assert.Equal(t, 0, months[1].Transactions)
+	assert.Equal(t, "0", FormatAmount(months[1].Incoming))
+	assert.Equal(t, "0.25", FormatAmount(months[2].Incoming))
+}
+
+func TestMonthly_Empty(t *testing.T) {
+	assert.Empty(t, Monthly(wallet, nil))
+}
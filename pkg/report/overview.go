@@ -0,0 +1,161 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+)
+
+// Overview answers the usual first questions about the history of an
+// address: how many transactions of each type, how much of each asset
+// moved in and out, the gas spent, the counterparties and the dates
+type Overview struct {
+	Address string
+	Summary
+	// Assets are the totals of every asset, native coin first
+	Assets   []AssetTotal
+	GasSpent *big.Rat
+	// Counterparties is the number of distinct addresses the address sent
+	// to or received from
+	Counterparties int
+}
+
+// Describe computes the Overview of address over transactions
+func Describe(address string, transactions []models.Transaction) Overview {
+	counterparties := make(map[string]bool)
+	for i := range transactions {
+		tx := &transactions[i]
+		for _, party := range []string{tx.From, tx.To} {
+			if party != "" && !strings.EqualFold(party, address) {
+				counterparties[strings.ToLower(party)] = true
+			}
+		}
+	}
+	return Overview{
+		Address:        address,
+		Summary:        Summarize(transactions),
+		Assets:         Totals(address, transactions),
+		GasSpent:       gasSpent(address, transactions),
+		Counterparties: len(counterparties),
+	}
+}
+
+// Native returns the totals of the native coin, which are zero when no
+// native amount moved
+func (o Overview) Native() AssetTotal {
+	for _, asset := range o.Assets {
+		if asset.Contract == "" && asset.Symbol == NativeAsset {
+			return asset
+		}
+	}
+	return AssetTotal{Symbol: NativeAsset, Incoming: new(big.Rat), Outgoing: new(big.Rat)}
+}
+
+// Write prints the overview in a plain-text layout
+func (o Overview) Write(w io.Writer) error {
+	if _, err := fmt.Fprintf(w, "Address: %s\n", o.Address); err != nil {
+		return err
+	}
+	if err := o.Summary.Write(w); err != nil {
+		return err
+	}
+	if o.Total == 0 {
+		return nil
+	}
+
+	native := o.Native()
+	if _, err := fmt.Fprintf(w, "%s: %s in, %s out, %s net\nGas spent: %s %s\nCounterparties: %d\n",
+		NativeAsset, formatRat(native.Incoming), formatRat(native.Outgoing), formatRat(native.Net()),
+		formatRat(o.GasSpent), NativeAsset, o.Counterparties); err != nil {
+		return err
+	}
+
+	var tokens []AssetTotal
+	for _, asset := range o.Assets {
+		if asset.Contract != "" {
+			tokens = append(tokens, asset)
+		}
+	}
+	if len(tokens) == 0 {
+		return nil
+	}
+	if _, err := fmt.Fprintln(w, "Token volume:"); err != nil {
+		return err
+	}
+	for _, token := range tokens {
+		name := fmt.Sprintf("%s (%s)", token.Symbol, token.Contract)
+		if _, err := fmt.Fprintf(w, "  %-56s %d transfer(s), %s in, %s out\n",
+			name, token.Count, formatRat(token.Incoming), formatRat(token.Outgoing)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// overviewJSON is the JSON form of an Overview. Amounts are decimal
+// strings, so they keep their precision.
+type overviewJSON struct {
+	Address        string         `json:"address"`
+	Transactions   int            `json:"transactions"`
+	Failed         int            `json:"failed"`
+	ByType         map[string]int `json:"by_type"`
+	FirstBlock     int64          `json:"first_block,omitempty"`
+	LastBlock      int64          `json:"last_block,omitempty"`
+	FirstTime      *time.Time     `json:"first_time,omitempty"`
+	LastTime       *time.Time     `json:"last_time,omitempty"`
+	GasSpent       string         `json:"gas_spent"`
+	Counterparties int            `json:"counterparties"`
+	Assets         []assetJSON    `json:"assets"`
+}
+
+type assetJSON struct {
+	Symbol   string `json:"symbol"`
+	Contract string `json:"contract,omitempty"`
+	Count    int    `json:"count"`
+	Incoming string `json:"incoming"`
+	Outgoing string `json:"outgoing"`
+	Net      string `json:"net"`
+}
+
+// MarshalJSON implements json.Marshaler
+func (o Overview) MarshalJSON() ([]byte, error) {
+	out := overviewJSON{
+		Address:        o.Address,
+		Transactions:   o.Total,
+		Failed:         o.Failed,
+		ByType:         make(map[string]int, len(o.ByType)),
+		GasSpent:       formatRat(o.GasSpent),
+		Counterparties: o.Counterparties,
+		Assets:         assetsJSON(o.Assets),
+	}
+	for t, n := range o.ByType {
+		out.ByType[string(t)] = n
+	}
+	if o.Total > 0 {
+		out.FirstBlock, out.LastBlock = o.FirstBlock, o.LastBlock
+		first, last := o.FirstTime.UTC(), o.LastTime.UTC()
+		out.FirstTime, out.LastTime = &first, &last
+	}
+	return json.Marshal(out)
+}
+
+// assetsJSON converts asset totals to their JSON form
+func assetsJSON(assets []AssetTotal) []assetJSON {
+	out := make([]assetJSON, len(assets))
+	for i, asset := range assets {
+		out[i] = assetJSON{
+			Symbol:   asset.Symbol,
+			Contract: asset.Contract,
+			Count:    asset.Count,
+			Incoming: formatRat(asset.Incoming),
+			Outgoing: formatRat(asset.Outgoing),
+			Net:      formatRat(asset.Net()),
+		}
+	}
+	return out
+}
@@ -0,0 +1,61 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func overviewRows() []models.Transaction {
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	return []models.Transaction{
+		{Hash: "0x1", BlockNumber: 10, Timestamp: day, Type: models.TypeEthTransfer, From: "0xExchange", To: "0xwallet", Value: "2.0", GasFee: "0.001"},
+		{Hash: "0x2", BlockNumber: 20, Timestamp: day.AddDate(0, 0, 1), Type: models.TypeEthTransfer, From: "0xwallet", To: "0xrouter", Value: "0.5", GasFee: "0.002"},
+		{Hash: "0x2", BlockNumber: 20, Timestamp: day.AddDate(0, 0, 1), Type: models.TypeERC20Transfer, From: "0xrouter", To: "0xwallet", AssetContractAddr: "0xUSDC", AssetSymbol: "USDC", Value: "1500.25", GasFee: "0.002"},
+		{Hash: "0x3", BlockNumber: 30, Timestamp: day.AddDate(0, 0, 9), Type: models.TypeEthTransfer, From: "0xwallet", To: "0xexchange", Value: "1.0", GasFee: "0.003", Status: models.StatusFailed},
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	o := Describe(wallet, overviewRows())
+
+	assert.Equal(t, 4, o.Total)
+	assert.Equal(t, 1, o.Failed)
+	assert.Equal(t, 2, o.Counterparties, "addresses are compared case-insensitively")
+	assert.Equal(t, "0.005", FormatAmount(o.GasSpent))
+	assert.Equal(t, "1.5", FormatAmount(o.Native().Net()))
+
+	var buf bytes.Buffer
+	assert.NoError(t, o.Write(&buf))
+	assert.Contains(t, buf.String(), "Address: 0xWallet\nTransactions: 4 (1 failed)\n")
+	assert.Contains(t, buf.String(), "Dates: 2024-03-01T00:00:00Z to 2024-03-10T00:00:00Z\n")
+	assert.Contains(t, buf.String(), "ETH: 2 in, 0.5 out, 1.5 net\nGas spent: 0.005 ETH\nCounterparties: 2\n")
+	assert.Contains(t, buf.String(), "Token volume:\n  USDC (0xusdc)")
+	assert.Contains(t, buf.String(), "1 transfer(s), 1500.25 in, 0 out\n")
+}
+
+func TestDescribe_Empty(t *testing.T) {
+	o := Describe(wallet, nil)
+
+	var buf bytes.Buffer
+	assert.NoError(t, o.Write(&buf))
+	assert.Equal(t, "Address: 0xWallet\nTransactions: 0 (0 failed)\n", buf.String())
+	assert.Equal(t, "0", FormatAmount(o.Native().Net()))
+}
+
+func TestOverview_MarshalJSON(t *testing.T) {
+	data, err := json.Marshal(Describe(wallet, overviewRows()))
+	assert.NoError(t, err)
+
+	var got map[string]interface{}
+	assert.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, "0.005", got["gas_spent"])
+	assert.Equal(t, "2024-03-01T00:00:00Z", got["first_time"])
+	assert.Equal(t, map[string]interface{}{"ERC20_TRANSFER": 1.0, "ETH_TRANSFER": 3.0}, got["by_type"])
+	assets := got["assets"].([]interface{})
+	assert.Equal(t, map[string]interface{}{"symbol": "USDC", "contract": "0xusdc", "count": 1.0, "incoming": "1500.25", "outgoing": "0", "net": "1500.25"}, assets[1])
+}
@@ -0,0 +1,87 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+)
+
+// weiPerEther converts on-chain balances to the units of exported rows
+var weiPerEther = new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+
+// Reconciliation compares the balance replayed from the rows of an address
+// with the balance the chain reports after the same block
+type Reconciliation struct {
+	Address string
+	Block   int64
+	// Replayed and OnChain are in ETH
+	Replayed *big.Rat
+	OnChain  *big.Rat
+}
+
+// Reconcile compares the replayed balance of address with its on-chain
+// balance in wei after block
+func Reconcile(address string, block int64, replayed *big.Rat, onChainWei *big.Int) Reconciliation {
+	return Reconciliation{
+		Address:  address,
+		Block:    block,
+		Replayed: new(big.Rat).Set(replayed),
+		OnChain:  new(big.Rat).SetFrac(onChainWei, weiPerEther),
+	}
+}
+
+// Discrepancy is the on-chain balance less the replayed balance. It is
+// positive when the chain holds more than the rows account for.
+func (r Reconciliation) Discrepancy() *big.Rat {
+	return new(big.Rat).Sub(r.OnChain, r.Replayed)
+}
+
+// Matches reports whether the replayed balance equals the on-chain balance
+func (r Reconciliation) Matches() bool {
+	return r.Discrepancy().Sign() == 0
+}
+
+// Causes lists the usual reasons for a discrepancy in its direction
+func (r Reconciliation) Causes() []string {
+	switch r.Discrepancy().Sign() {
+	case 1:
+		return []string{
+			"internal transfers the provider did not return, such as contract payouts",
+			"validator withdrawals, which are credited without a transaction",
+			"a genesis allocation, or an -opening-balance below the balance before the first exported block",
+			"block rewards, which are only exported with -block-rewards",
+		}
+	case -1:
+		return []string{
+			"sent transactions or outgoing internal transfers the provider did not return",
+			"fees missing from the rows, such as L1 data fees the provider did not report",
+			"an -opening-balance above the balance before the first exported block",
+		}
+	}
+	return nil
+}
+
+// Write prints the reconciliation in a plain-text layout
+func (r Reconciliation) Write(w io.Writer) error {
+	if r.Matches() {
+		_, err := fmt.Fprintf(w, "Balance reconciliation at block %d: the replayed balance matches the on-chain balance of %s %s\n",
+			r.Block, formatRat(r.OnChain), NativeAsset)
+		return err
+	}
+
+	discrepancy := r.Discrepancy()
+	amount := formatRat(discrepancy)
+	if discrepancy.Sign() > 0 {
+		amount = "+" + amount
+	}
+	if _, err := fmt.Fprintf(w, "Warning: balance reconciliation at block %d failed:\n  Replayed balance:  %s %s\n  On-chain balance:  %s %s\n  Discrepancy:       %s %s\n  Likely causes:\n",
+		r.Block, formatRat(r.Replayed), NativeAsset, formatRat(r.OnChain), NativeAsset, amount, NativeAsset); err != nil {
+		return err
+	}
+	for _, cause := range r.Causes() {
+		if _, err := fmt.Fprintf(w, "    - %s\n", cause); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package report
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReconcile(t *testing.T) {
+	wei, _ := new(big.Int).SetString("1818000000000000000", 10)
+	r := Reconcile(wallet, 18000000, big.NewRat(1818, 1000), wei)
+	assert.True(t, r.Matches())
+	assert.Empty(t, r.Causes())
+
+	var buf bytes.Buffer
+	assert.NoError(t, r.Write(&buf))
+	assert.Equal(t, "Balance reconciliation at block 18000000: the replayed balance matches the on-chain balance of 1.818 ETH\n", buf.String())
+}
+
+func TestReconcile_Discrepancy(t *testing.T) {
+	wei, _ := new(big.Int).SetString("2068000000000000000", 10)
+	r := Reconcile(wallet, 18000000, big.NewRat(1818, 1000), wei)
+	assert.False(t, r.Matches())
+	assert.Equal(t, "0.25", formatRat(r.Discrepancy()))
+	assert.Contains(t, r.Causes()[0], "internal transfers")
+
+	var buf bytes.Buffer
+	assert.NoError(t, r.Write(&buf))
+	assert.Contains(t, buf.String(), "Replayed balance:  1.818 ETH\n")
+	assert.Contains(t, buf.String(), "On-chain balance:  2.068 ETH\n")
+	assert.Contains(t, buf.String(), "Discrepancy:       +0.25 ETH\n")
+	assert.Contains(t, buf.String(), "    - validator withdrawals")
+
+	// The chain holds less than the rows account for
+	r = Reconcile(wallet, 18000000, big.NewRat(3, 1), wei)
+	assert.Equal(t, "-0.932", formatRat(r.Discrepancy()))
+	assert.Contains(t, r.Causes()[0], "sent transactions")
+}
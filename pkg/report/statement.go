@@ -0,0 +1,326 @@
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+)
+
+// Period is a span of time a statement covers
+type Period struct {
+	// Name is the period as given, such as 2024, 2024-Q1 or 2024-03
+	Name  string
+	Start time.Time
+	// End is the start of the next period
+	End time.Time
+}
+
+// ParsePeriod parses a year (2024), quarter (2024-Q1) or month (2024-03)
+// in UTC
+func ParsePeriod(s string) (Period, error) {
+	year, rest, _ := strings.Cut(s, "-")
+	y, err := strconv.Atoi(year)
+	if err != nil || len(year) != 4 {
+		return Period{}, fmt.Errorf("invalid period %q (use 2024, 2024-Q1 or 2024-03)", s)
+	}
+	start := time.Date(y, time.January, 1, 0, 0, 0, 0, time.UTC)
+	switch {
+	case rest == "":
+		return Period{Name: s, Start: start, End: start.AddDate(1, 0, 0)}, nil
+	case len(rest) == 2 && (rest[0] == 'Q' || rest[0] == 'q') && rest[1] >= '1' && rest[1] <= '4':
+		start = start.AddDate(0, 3*int(rest[1]-'1'), 0)
+		return Period{Name: fmt.Sprintf("%d-Q%c", y, rest[1]), Start: start, End: start.AddDate(0, 3, 0)}, nil
+	}
+	if m, err := strconv.Atoi(rest); err == nil && len(rest) == 2 && m >= 1 && m <= 12 {
+		start = start.AddDate(0, m-1, 0)
+		return Period{Name: s, Start: start, End: start.AddDate(0, 1, 0)}, nil
+	}
+	return Period{}, fmt.Errorf("invalid period %q (use 2024, 2024-Q1 or 2024-03)", s)
+}
+
+// Split divides the period into months or quarters
+func (p Period) Split(by string) ([]Period, error) {
+	var months int
+	switch by {
+	case "month":
+		months = 1
+	case "quarter":
+		months = 3
+	default:
+		return nil, fmt.Errorf("unknown split %q (use month or quarter)", by)
+	}
+	var periods []Period
+	for start := p.Start; start.Before(p.End); start = start.AddDate(0, months, 0) {
+		name := start.Format("2006-01")
+		if months == 3 {
+			name = fmt.Sprintf("%d-Q%d", start.Year(), (int(start.Month())+2)/3)
+		}
+		end := start.AddDate(0, months, 0)
+		if end.After(p.End) {
+			return nil, fmt.Errorf("period %s cannot be split by %s", p.Name, by)
+		}
+		periods = append(periods, Period{Name: name, Start: start, End: end})
+	}
+	return periods, nil
+}
+
+// Last returns the last day of the period
+func (p Period) Last() time.Time {
+	return p.End.AddDate(0, 0, -1)
+}
+
+// Statement is the movements of every asset of an address over a period
+type Statement struct {
+	Address string
+	Period  Period
+	// Transactions is the number of distinct transactions in the period
+	Transactions int
+	// Lines are sorted with the native coin first, then by symbol, contract
+	// and token ID
+	Lines []StatementLine
+}
+
+// StatementLine is the movements of one asset over a statement period
+type StatementLine struct {
+	Symbol string
+	// Contract is the lowercased token contract, empty for the native coin
+	Contract string
+	// TokenID identifies the NFT of an ERC-721 or ERC-1155 line
+	TokenID  string
+	Opening  *big.Rat
+	Inflows  *big.Rat
+	Outflows *big.Rat
+	// Fees are the gas and L1 fees paid, for the native coin only
+	Fees    *big.Rat
+	Closing *big.Rat
+	// Gain is the realized gain of the disposals of the asset in the
+	// period, in fiat, or nil when it was not computed
+	Gain *big.Rat
+}
+
+// StatementOf builds the statement of address for period. The native
+// opening balance is replayed like a running balance from opening; token
+// balances are the net of their transfers. Failed rows move nothing, but
+// their fees are paid. Assets without a balance or movement in the period
+// are left out.
+func StatementOf(address string, period Period, opening *big.Rat, transactions []models.Transaction) Statement {
+	var before, during []models.Transaction
+	for i := range transactions {
+		tx := &transactions[i]
+		switch {
+		case tx.Timestamp.Before(period.Start):
+			before = append(before, *tx)
+		case tx.Timestamp.Before(period.End):
+			during = append(during, *tx)
+		}
+	}
+
+	lines := make(map[string]*StatementLine)
+	line := func(tx *models.Transaction) *StatementLine {
+		key, l := "", &StatementLine{Symbol: NativeAsset}
+		if assetKey(tx) != NativeAsset {
+			l = &StatementLine{Symbol: tx.AssetSymbol, Contract: strings.ToLower(tx.AssetContractAddr)}
+			if tx.Type != models.TypeERC20Transfer {
+				l.TokenID = tx.TokenID
+			}
+			key = l.Contract + "|" + l.TokenID
+		}
+		if existing := lines[key]; existing != nil {
+			return existing
+		}
+		for _, amount := range []**big.Rat{&l.Opening, &l.Inflows, &l.Outflows, &l.Fees} {
+			*amount = new(big.Rat)
+		}
+		lines[key] = l
+		return l
+	}
+
+	balances := NewBalances(opening)
+	balances.Apply(address, before)
+	line(&models.Transaction{}).Opening = balances.Balance(address)
+	seen := make(map[string]bool)
+	for i := range before {
+		tx := &before[i]
+		if assetKey(tx) == NativeAsset || tx.Failed() || seen[tx.RowID()] {
+			continue
+		}
+		seen[tx.RowID()] = true
+		if amount, ok := new(big.Rat).SetString(tx.Value); ok {
+			l := line(tx)
+			if strings.EqualFold(tx.To, address) {
+				l.Opening.Add(l.Opening, amount)
+			}
+			if strings.EqualFold(tx.From, address) {
+				l.Opening.Sub(l.Opening, amount)
+			}
+		}
+	}
+
+	statement := Statement{Address: address, Period: period}
+	hashes := make(map[string]bool)
+	charged := make(map[string]bool)
+	for i := range during {
+		tx := &during[i]
+		if seen[tx.RowID()] {
+			continue
+		}
+		seen[tx.RowID()] = true
+		hashes[strings.ToLower(tx.Hash)] = true
+		incoming := strings.EqualFold(tx.To, address)
+		outgoing := strings.EqualFold(tx.From, address)
+
+		hash := strings.ToLower(tx.Hash)
+		if tx.Type == models.TypeEthTransfer && outgoing && !charged[hash] {
+			charged[hash] = true
+			native := line(&models.Transaction{})
+			for _, fee := range []string{tx.GasFee, tx.L1Fee} {
+				if amount, ok := new(big.Rat).SetString(fee); ok {
+					native.Fees.Add(native.Fees, amount)
+				}
+			}
+		}
+		amount, ok := new(big.Rat).SetString(tx.Value)
+		if !ok || tx.Failed() || !(incoming || outgoing) {
+			continue
+		}
+		l := line(tx)
+		if incoming {
+			l.Inflows.Add(l.Inflows, amount)
+		}
+		if outgoing {
+			l.Outflows.Add(l.Outflows, amount)
+		}
+	}
+	statement.Transactions = len(hashes)
+
+	for _, l := range lines {
+		l.Closing = new(big.Rat).Add(l.Opening, l.Inflows)
+		l.Closing.Sub(l.Closing, l.Outflows)
+		l.Closing.Sub(l.Closing, l.Fees)
+		if l.Opening.Sign() != 0 || l.Closing.Sign() != 0 || l.Inflows.Sign() != 0 || l.Outflows.Sign() != 0 || l.Fees.Sign() != 0 {
+			statement.Lines = append(statement.Lines, *l)
+		}
+	}
+	sort.Slice(statement.Lines, func(i, j int) bool {
+		a, b := statement.Lines[i], statement.Lines[j]
+		if (a.Contract == "") != (b.Contract == "") {
+			return a.Contract == ""
+		}
+		if a.Symbol != b.Symbol {
+			return a.Symbol < b.Symbol
+		}
+		if a.Contract != b.Contract {
+			return a.Contract < b.Contract
+		}
+		return a.TokenID < b.TokenID
+	})
+	return statement
+}
+
+// Asset returns the name of the line's asset
+func (l StatementLine) Asset() string {
+	name := l.Symbol
+	if l.Contract != "" {
+		name = fmt.Sprintf("%s (%s)", l.Symbol, l.Contract)
+	}
+	if l.TokenID != "" {
+		name += " #" + l.TokenID
+	}
+	return name
+}
+
+// Gain returns the realized gain of all lines, or nil when none was
+// computed
+func (s Statement) Gain() *big.Rat {
+	var total *big.Rat
+	for _, l := range s.Lines {
+		if l.Gain != nil {
+			if total == nil {
+				total = new(big.Rat)
+			}
+			total.Add(total, l.Gain)
+		}
+	}
+	return total
+}
+
+// Write prints the statement in a plain-text layout
+func (s Statement) Write(w io.Writer) error {
+	for _, line := range s.TextLines() {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TextLines returns the lines Write prints
+func (s Statement) TextLines() []string {
+	lines := []string{
+		fmt.Sprintf("Statement of %s", s.Address),
+		fmt.Sprintf("Period: %s (%s to %s)", s.Period.Name, s.Period.Start.Format("2006-01-02"), s.Period.Last().Format("2006-01-02")),
+		fmt.Sprintf("Transactions: %d", s.Transactions),
+	}
+	if len(s.Lines) == 0 {
+		return append(lines, "  No balances or movements")
+	}
+	gain := s.Gain()
+	header := fmt.Sprintf("  %-56s %18s %18s %18s %14s %18s", "Asset", "Opening", "Inflows", "Outflows", "Fees", "Closing")
+	if gain != nil {
+		header += fmt.Sprintf(" %14s", "Realized gain")
+	}
+	lines = append(lines, header)
+	for _, l := range s.Lines {
+		fees := ""
+		if l.Contract == "" {
+			fees = formatRat(l.Fees)
+		}
+		line := fmt.Sprintf("  %-56s %18s %18s %18s %14s %18s", l.Asset(),
+			formatRat(l.Opening), formatRat(l.Inflows), formatRat(l.Outflows), fees, formatRat(l.Closing))
+		if gain != nil && l.Gain != nil {
+			line += fmt.Sprintf(" %14s", l.Gain.FloatString(2))
+		}
+		lines = append(lines, strings.TrimRight(line, " "))
+	}
+	if gain != nil {
+		lines = append(lines, fmt.Sprintf("Realized gain: %s", gain.FloatString(2)))
+	}
+	return lines
+}
+
+// statementHeaders are the columns of WriteStatementsCSV
+var statementHeaders = []string{"Address", "Period", "Start", "End", "Asset Symbol", "Asset Contract Address", "Token ID", "Opening", "Inflows", "Outflows", "Fees", "Closing", "Realized Gain"}
+
+// WriteStatementsCSV writes one row per statement line. Realized Gain is
+// empty when it was not computed.
+func WriteStatementsCSV(w io.Writer, statements []Statement) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(statementHeaders); err != nil {
+		return err
+	}
+	for _, s := range statements {
+		for _, l := range s.Lines {
+			gain := ""
+			if l.Gain != nil {
+				gain = l.Gain.FloatString(2)
+			}
+			record := []string{
+				s.Address, s.Period.Name, s.Period.Start.Format("2006-01-02"), s.Period.Last().Format("2006-01-02"),
+				l.Symbol, l.Contract, l.TokenID,
+				formatRat(l.Opening), formatRat(l.Inflows), formatRat(l.Outflows), formatRat(l.Fees), formatRat(l.Closing), gain,
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
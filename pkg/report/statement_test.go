@@ -0,0 +1,130 @@
+package report
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePeriod(t *testing.T) {
+	tests := []struct {
+		in         string
+		name       string
+		start, end string
+	}{
+		{"2024", "2024", "2024-01-01", "2025-01-01"},
+		{"2024-Q1", "2024-Q1", "2024-01-01", "2024-04-01"},
+		{"2024-q4", "2024-Q4", "2024-10-01", "2025-01-01"},
+		{"2024-02", "2024-02", "2024-02-01", "2024-03-01"},
+	}
+	for _, tt := range tests {
+		p, err := ParsePeriod(tt.in)
+		assert.NoError(t, err, tt.in)
+		assert.Equal(t, tt.name, p.Name)
+		assert.Equal(t, tt.start, p.Start.Format("2006-01-02"))
+		assert.Equal(t, tt.end, p.End.Format("2006-01-02"))
+	}
+
+	for _, in := range []string{"", "24", "2024-Q5", "2024-13", "2024-1", "2024-H1"} {
+		_, err := ParsePeriod(in)
+		assert.Error(t, err, in)
+	}
+}
+
+func TestPeriod_Split(t *testing.T) {
+	year, _ := ParsePeriod("2024")
+	quarters, err := year.Split("quarter")
+	assert.NoError(t, err)
+	assert.Len(t, quarters, 4)
+	assert.Equal(t, "2024-Q3", quarters[2].Name)
+
+	q1, _ := ParsePeriod("2024-Q1")
+	months, err := q1.Split("month")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2024-01", "2024-02", "2024-03"}, []string{months[0].Name, months[1].Name, months[2].Name})
+	assert.Equal(t, "2024-03-31", months[2].Last().Format("2006-01-02"))
+
+	month, _ := ParsePeriod("2024-02")
+	_, err = month.Split("quarter")
+	assert.Error(t, err)
+	_, err = year.Split("week")
+	assert.Error(t, err)
+}
+
+// statementRows spread overviewRows over two months
+func statementRows() []models.Transaction {
+	rows := overviewRows()
+	rows = append(rows, models.Transaction{Hash: "0x4", BlockNumber: 40, Timestamp: time.Date(2024, 4, 2, 0, 0, 0, 0, time.UTC), Type: models.TypeERC20Transfer, From: "0xwallet", To: "0xexchange", AssetContractAddr: "0xUSDC", AssetSymbol: "USDC", Value: "500"})
+	return rows
+}
+
+func TestStatementOf(t *testing.T) {
+	march, _ := ParsePeriod("2024-03")
+	s := StatementOf(wallet, march, nil, statementRows())
+
+	assert.Equal(t, 3, s.Transactions)
+	assert.Len(t, s.Lines, 2)
+	eth := s.Lines[0]
+	assert.Equal(t, NativeAsset, eth.Symbol)
+	assert.Equal(t, "0", FormatAmount(eth.Opening))
+	assert.Equal(t, "2", FormatAmount(eth.Inflows))
+	assert.Equal(t, "0.5", FormatAmount(eth.Outflows), "failed rows move nothing")
+	assert.Equal(t, "0.005", FormatAmount(eth.Fees), "failed rows pay their fee, and a hash is charged once")
+	assert.Equal(t, "1.495", FormatAmount(eth.Closing))
+	assert.Equal(t, "0xusdc", s.Lines[1].Contract)
+	assert.Equal(t, "1500.25", FormatAmount(s.Lines[1].Closing))
+
+	april, _ := ParsePeriod("2024-04")
+	s = StatementOf(wallet, april, nil, statementRows())
+	assert.Equal(t, 1, s.Transactions)
+	assert.Equal(t, "1.495", FormatAmount(s.Lines[0].Opening))
+	assert.Equal(t, "1.495", FormatAmount(s.Lines[0].Closing))
+	assert.Equal(t, "1500.25", FormatAmount(s.Lines[1].Opening))
+	assert.Equal(t, "500", FormatAmount(s.Lines[1].Outflows))
+	assert.Equal(t, "1000.25", FormatAmount(s.Lines[1].Closing))
+}
+
+func TestStatementOf_Empty(t *testing.T) {
+	p, _ := ParsePeriod("2023")
+	s := StatementOf(wallet, p, big.NewRat(1, 1), nil)
+	assert.Len(t, s.Lines, 1, "the opening balance is kept without movements")
+
+	s = StatementOf(wallet, p, nil, nil)
+	var buf bytes.Buffer
+	assert.NoError(t, s.Write(&buf))
+	assert.Equal(t, "Statement of 0xWallet\nPeriod: 2023 (2023-01-01 to 2023-12-31)\nTransactions: 0\n  No balances or movements\n", buf.String())
+}
+
+func TestStatement_Write(t *testing.T) {
+	march, _ := ParsePeriod("2024-03")
+	s := StatementOf(wallet, march, nil, statementRows())
+
+	var buf bytes.Buffer
+	assert.NoError(t, s.Write(&buf))
+	assert.Contains(t, buf.String(), "Period: 2024-03 (2024-03-01 to 2024-03-31)\nTransactions: 3\n")
+	assert.Regexp(t, `\n  ETH +0 +2 +0\.5 +0\.005 +1\.495\n`, buf.String())
+	assert.NotContains(t, buf.String(), "Realized gain")
+
+	s.Lines[0].Gain = big.NewRat(251, 2)
+	s.Lines[1].Gain = big.NewRat(-1, 4)
+	buf.Reset()
+	assert.NoError(t, s.Write(&buf))
+	assert.Regexp(t, `1\.495 +125\.50\n`, buf.String())
+	assert.Contains(t, buf.String(), "Realized gain: 125.25\n")
+}
+
+func TestWriteStatementsCSV(t *testing.T) {
+	march, _ := ParsePeriod("2024-03")
+	s := StatementOf(wallet, march, nil, statementRows())
+	s.Lines[0].Gain = big.NewRat(10, 1)
+
+	var buf bytes.Buffer
+	assert.NoError(t, WriteStatementsCSV(&buf, []Statement{s}))
+	assert.Equal(t, "Address,Period,Start,End,Asset Symbol,Asset Contract Address,Token ID,Opening,Inflows,Outflows,Fees,Closing,Realized Gain\n"+
+		"0xWallet,2024-03,2024-03-01,2024-03-31,ETH,,,0,2,0.5,0.005,1.495,10.00\n"+
+		"0xWallet,2024-03,2024-03-01,2024-03-31,USDC,0xusdc,,0,1500.25,0,0,1500.25,\n", buf.String())
+}
@@ -0,0 +1,78 @@
+// Package report derives human-oriented summaries from exported transactions.
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+)
+
+// Summary holds aggregate figures for a set of transactions
+type Summary struct {
+	Total      int
+	ByType     map[models.TransactionType]int
+	Failed     int
+	FirstBlock int64
+	LastBlock  int64
+	FirstTime  time.Time
+	LastTime   time.Time
+}
+
+// Summarize computes a Summary over transactions
+func Summarize(transactions []models.Transaction) Summary {
+	summary := Summary{
+		ByType: make(map[models.TransactionType]int),
+	}
+
+	for i := range transactions {
+		tx := &transactions[i]
+		summary.Total++
+		summary.ByType[tx.Type]++
+		if tx.Failed() {
+			summary.Failed++
+		}
+
+		if summary.Total == 1 || tx.BlockNumber < summary.FirstBlock {
+			summary.FirstBlock = tx.BlockNumber
+		}
+		if tx.BlockNumber > summary.LastBlock {
+			summary.LastBlock = tx.BlockNumber
+		}
+		if summary.FirstTime.IsZero() || tx.Timestamp.Before(summary.FirstTime) {
+			summary.FirstTime = tx.Timestamp
+		}
+		if tx.Timestamp.After(summary.LastTime) {
+			summary.LastTime = tx.Timestamp
+		}
+	}
+
+	return summary
+}
+
+// Write prints the summary in a plain-text layout
+func (s Summary) Write(w io.Writer) error {
+	if _, err := fmt.Fprintf(w, "Transactions: %d (%d failed)\n", s.Total, s.Failed); err != nil {
+		return err
+	}
+	if s.Total == 0 {
+		return nil
+	}
+
+	types := make([]string, 0, len(s.ByType))
+	for t := range s.ByType {
+		types = append(types, string(t))
+	}
+	sort.Strings(types)
+	for _, t := range types {
+		if _, err := fmt.Fprintf(w, "  %-20s %d\n", t, s.ByType[models.TransactionType(t)]); err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "Blocks: %d to %d\nDates: %s to %s\n",
+		s.FirstBlock, s.LastBlock, s.FirstTime.Format(time.RFC3339), s.LastTime.Format(time.RFC3339))
+	return err
+}
@@ -0,0 +1,43 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummarize(t *testing.T) {
+	transactions := []models.Transaction{
+		{BlockNumber: 20, Timestamp: time.Unix(2000, 0).UTC(), Type: models.TypeEthTransfer, Status: models.StatusSuccess},
+		{BlockNumber: 10, Timestamp: time.Unix(1000, 0).UTC(), Type: models.TypeERC20Transfer, Status: models.StatusSuccess},
+		{BlockNumber: 30, Timestamp: time.Unix(3000, 0).UTC(), Type: models.TypeEthTransfer, Status: models.StatusFailed},
+	}
+
+	summary := Summarize(transactions)
+	assert.Equal(t, 3, summary.Total)
+	assert.Equal(t, 1, summary.Failed)
+	assert.Equal(t, 2, summary.ByType[models.TypeEthTransfer])
+	assert.Equal(t, 1, summary.ByType[models.TypeERC20Transfer])
+	assert.Equal(t, int64(10), summary.FirstBlock)
+	assert.Equal(t, int64(30), summary.LastBlock)
+	assert.Equal(t, time.Unix(1000, 0).UTC(), summary.FirstTime)
+	assert.Equal(t, time.Unix(3000, 0).UTC(), summary.LastTime)
+
+	var buf bytes.Buffer
+	assert.NoError(t, summary.Write(&buf))
+	assert.Contains(t, buf.String(), "Transactions: 3 (1 failed)")
+	assert.Contains(t, buf.String(), "ERC20_TRANSFER")
+	assert.Contains(t, buf.String(), "Blocks: 10 to 30")
+}
+
+func TestSummarize_Empty(t *testing.T) {
+	summary := Summarize(nil)
+	assert.Equal(t, 0, summary.Total)
+
+	var buf bytes.Buffer
+	assert.NoError(t, summary.Write(&buf))
+	assert.Equal(t, "Transactions: 0 (0 failed)\n", buf.String())
+}
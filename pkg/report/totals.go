@@ -0,0 +1,85 @@
+package report
+
+import (
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+)
+
+// AssetTotal sums the amounts of one asset moved to and from an address
+type AssetTotal struct {
+	// Symbol is the asset symbol, NativeAsset for native rows without one
+	Symbol string
+	// Contract is the lowercased token contract, empty for the native coin
+	Contract string
+	// Count is the number of rows moving the asset
+	Count    int
+	Incoming *big.Rat
+	Outgoing *big.Rat
+}
+
+// Net is the amount received less the amount sent
+func (t AssetTotal) Net() *big.Rat {
+	return new(big.Rat).Sub(t.Incoming, t.Outgoing)
+}
+
+// Totals sums the amounts of every asset moved to and from address,
+// skipping failed rows. Self-transfers count as both incoming and outgoing.
+// Assets are sorted with the native coin first, then by symbol and
+// contract. Gas is not included; see GasSpent.
+func Totals(address string, transactions []models.Transaction) []AssetTotal {
+	byKey := make(map[string]*AssetTotal)
+	for i := range transactions {
+		tx := &transactions[i]
+		amount, ok := new(big.Rat).SetString(tx.Value)
+		if !ok || tx.Failed() {
+			continue
+		}
+		contract := strings.ToLower(tx.AssetContractAddr)
+		symbol := tx.AssetSymbol
+		if symbol == "" && contract == "" {
+			symbol = NativeAsset
+		}
+		key := contract + "|" + symbol
+		total := byKey[key]
+		if total == nil {
+			total = &AssetTotal{Symbol: symbol, Contract: contract, Incoming: new(big.Rat), Outgoing: new(big.Rat)}
+			byKey[key] = total
+		}
+		total.Count++
+		if strings.EqualFold(tx.To, address) {
+			total.Incoming.Add(total.Incoming, amount)
+		}
+		if strings.EqualFold(tx.From, address) {
+			total.Outgoing.Add(total.Outgoing, amount)
+		}
+	}
+
+	result := make([]AssetTotal, 0, len(byKey))
+	for _, total := range byKey {
+		result = append(result, *total)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if (a.Contract == "") != (b.Contract == "") {
+			return a.Contract == ""
+		}
+		if a.Symbol != b.Symbol {
+			return a.Symbol < b.Symbol
+		}
+		return a.Contract < b.Contract
+	})
+	return result
+}
+
+// GasSpent sums the gas fees paid by address, counting each transaction once
+func GasSpent(address string, transactions []models.Transaction) *big.Rat {
+	return gasSpent(address, transactions)
+}
+
+// FormatAmount prints r as a decimal without trailing zeros
+func FormatAmount(r *big.Rat) string {
+	return formatRat(r)
+}
@@ -0,0 +1,29 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTotals(t *testing.T) {
+	txs := []models.Transaction{
+		{Hash: "0x1", Type: models.TypeEthTransfer, From: "0xexchange", To: "0xwallet", Value: "2.0", GasFee: "0.001"},
+		{Hash: "0x2", Type: models.TypeEthTransfer, From: "0xwallet", To: "0xrouter", Value: "0.5", GasFee: "0.002"},
+		{Hash: "0x2", Type: models.TypeERC20Transfer, From: "0xrouter", To: "0xwallet", AssetContractAddr: "0xUSDC", AssetSymbol: "USDC", Value: "1500.25", GasFee: "0.002"},
+		{Hash: "0x3", Type: models.TypeEthTransfer, From: "0xwallet", To: "0xrouter", Value: "1.0", GasFee: "0.003", Status: models.StatusFailed},
+	}
+
+	totals := Totals(wallet, txs)
+	assert.Len(t, totals, 2)
+	assert.Equal(t, NativeAsset, totals[0].Symbol)
+	assert.Equal(t, 2, totals[0].Count)
+	assert.Equal(t, "2", FormatAmount(totals[0].Incoming))
+	assert.Equal(t, "0.5", FormatAmount(totals[0].Outgoing))
+	assert.Equal(t, "1.5", FormatAmount(totals[0].Net()))
+	assert.Equal(t, "0xusdc", totals[1].Contract)
+	assert.Equal(t, "1500.25", FormatAmount(totals[1].Net()))
+
+	assert.Equal(t, "0.005", FormatAmount(GasSpent(wallet, txs)))
+}
@@ -0,0 +1,47 @@
+package rpc
+
+import (
+	"encoding/hex"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// Keccak256 returns the Keccak-256 hash used throughout Ethereum
+func Keccak256(data []byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// BloomMayContain reports whether a 2048-bit logs bloom (hex encoded) may
+// contain data. A false result is definitive; a true result must be
+// confirmed by fetching the logs.
+func BloomMayContain(bloomHex string, data []byte) bool {
+	bloom, err := hex.DecodeString(strings.TrimPrefix(bloomHex, "0x"))
+	if err != nil || len(bloom) != 256 {
+		// Without a usable bloom we cannot rule anything out
+		return true
+	}
+
+	hash := Keccak256(data)
+	for i := 0; i < 6; i += 2 {
+		bit := (uint(hash[i])<<8 | uint(hash[i+1])) & 2047
+		if bloom[256-1-bit/8]&(1<<(bit%8)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// AddressTopic left-pads an address to the 32-byte form used in indexed
+// event topics
+func AddressTopic(address string) []byte {
+	raw, err := hex.DecodeString(strings.TrimPrefix(strings.ToLower(address), "0x"))
+	if err != nil || len(raw) > 32 {
+		return nil
+	}
+	topic := make([]byte, 32)
+	copy(topic[32-len(raw):], raw)
+	return topic
+}
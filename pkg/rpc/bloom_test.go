@@ -0,0 +1,47 @@
+package rpc
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// bloomWith builds a hex bloom containing each of items
+func bloomWith(items ...[]byte) string {
+	bloom := make([]byte, 256)
+	for _, item := range items {
+		hash := Keccak256(item)
+		for i := 0; i < 6; i += 2 {
+			bit := (uint(hash[i])<<8 | uint(hash[i+1])) & 2047
+			bloom[256-1-bit/8] |= 1 << (bit % 8)
+		}
+	}
+	return "0x" + hex.EncodeToString(bloom)
+}
+
+func TestKeccak256(t *testing.T) {
+	assert.Equal(t, "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470",
+		hex.EncodeToString(Keccak256(nil)))
+	assert.Equal(t, "ddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",
+		hex.EncodeToString(Keccak256([]byte("Transfer(address,address,uint256)"))))
+}
+
+func TestBloomMayContain(t *testing.T) {
+	wallet := AddressTopic("0x00000000000000000000000000000000000000aa")
+	other := AddressTopic("0x00000000000000000000000000000000000000bb")
+
+	bloom := bloomWith(wallet)
+	assert.True(t, BloomMayContain(bloom, wallet))
+	assert.False(t, BloomMayContain(bloom, other))
+
+	// An empty bloom rules everything out; a malformed one rules nothing out
+	assert.False(t, BloomMayContain(bloomWith(), wallet))
+	assert.True(t, BloomMayContain("0x1234", wallet))
+}
+
+func TestAddressTopic(t *testing.T) {
+	topic := AddressTopic("0xA39B189482F984388A34460636FEA9EB181AD1A6")
+	assert.Equal(t, "000000000000000000000000a39b189482f984388a34460636fea9eb181ad1a6", hex.EncodeToString(topic))
+	assert.Nil(t, AddressTopic("not-an-address"))
+}
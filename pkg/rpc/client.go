@@ -0,0 +1,306 @@
+// Package rpc is a minimal Ethereum JSON-RPC client for networks that have
+// no block explorer API.
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/audit"
+)
+
+// Client calls a JSON-RPC endpoint over HTTP
+type Client struct {
+	URL        string
+	HTTPClient *http.Client
+	// Audit, when set, records every call made by the client
+	Audit *audit.Log
+	// Logger receives the client's diagnostics. Defaults to slog.Default().
+	Logger *slog.Logger
+	// Context, when set, aborts in-flight calls once it is done
+	Context context.Context
+
+	nextID int64
+}
+
+// NewClient creates a new JSON-RPC client for url
+func NewClient(url string) *Client {
+	return &Client{
+		URL: url,
+		HTTPClient: &http.Client{
+			Timeout: time.Second * 30,
+		},
+	}
+}
+
+type request struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      int64         `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type response struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Call invokes method with params and decodes the result into result
+func (c *Client) Call(method string, result interface{}, params ...interface{}) (err error) {
+	if params == nil {
+		params = []interface{}{}
+	}
+	start := time.Now()
+	var rpcResp response
+	defer func() { c.recordCall(method, params, start, rpcResp.Result, err) }()
+	payload, err := json.Marshal(request{
+		JSONRPC: "2.0",
+		ID:      atomic.AddInt64(&c.nextID, 1),
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(c.context(), http.MethodPost, c.URL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("RPC request %s failed with status code: %d", method, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(body, &rpcResp); err != nil {
+		return err
+	}
+	if rpcResp.Error != nil {
+		return fmt.Errorf("RPC %s returned error %d: %s", method, rpcResp.Error.Code, rpcResp.Error.Message)
+	}
+
+	return json.Unmarshal(rpcResp.Result, result)
+}
+
+// context returns the context calls are made under
+func (c *Client) context() context.Context {
+	if c.Context == nil {
+		return context.Background()
+	}
+	return c.Context
+}
+
+// LevelTrace is the level every call made by the client is logged at,
+// below slog.LevelDebug
+const LevelTrace = slog.LevelDebug - 4
+
+// recordCall logs a call at LevelTrace and appends it to the audit log, if
+// any. Only the host of the node URL is kept since providers embed API keys
+// in the path.
+func (c *Client) recordCall(method string, params []interface{}, start time.Time, result json.RawMessage, err error) {
+	if c.Audit == nil && !c.logger().Enabled(context.Background(), LevelTrace) {
+		return
+	}
+
+	encoded, _ := json.Marshal(params)
+	entry := audit.Entry{
+		Time:       start.UTC(),
+		Provider:   "rpc",
+		Endpoint:   audit.RedactURL(c.URL),
+		Method:     method,
+		ParamsHash: audit.Hash(encoded),
+		Results:    audit.ResultCount(result),
+		LatencyMS:  time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	c.logger().Log(context.Background(), LevelTrace, "call", "method", method, "params_hash", entry.ParamsHash,
+		"results", entry.Results, "latency_ms", entry.LatencyMS, "error", entry.Error)
+	if c.Audit == nil {
+		return
+	}
+	if werr := c.Audit.Record(entry); werr != nil {
+		c.logger().Warn("failed to write audit log", "error", werr)
+	}
+}
+
+// logger returns the logger diagnostics are written to
+func (c *Client) logger() *slog.Logger {
+	if c.Logger == nil {
+		return slog.Default()
+	}
+	return c.Logger
+}
+
+// Transaction is a transaction object as embedded in a full block
+type Transaction struct {
+	Hash             string `json:"hash"`
+	From             string `json:"from"`
+	To               string `json:"to"`
+	Value            string `json:"value"`
+	Nonce            string `json:"nonce"`
+	Gas              string `json:"gas"`
+	GasPrice         string `json:"gasPrice"`
+	TransactionIndex string `json:"transactionIndex"`
+}
+
+// Block is a block with full transaction objects
+type Block struct {
+	Number        string        `json:"number"`
+	Timestamp     string        `json:"timestamp"`
+	LogsBloom     string        `json:"logsBloom"`
+	BaseFeePerGas string        `json:"baseFeePerGas"`
+	Transactions  []Transaction `json:"transactions"`
+}
+
+// Receipt holds the fields of a transaction receipt used by the scanner
+type Receipt struct {
+	TransactionHash   string `json:"transactionHash"`
+	GasUsed           string `json:"gasUsed"`
+	EffectiveGasPrice string `json:"effectiveGasPrice"`
+	Status            string `json:"status"`
+}
+
+// Log is an event log entry
+type Log struct {
+	Address          string   `json:"address"`
+	Topics           []string `json:"topics"`
+	Data             string   `json:"data"`
+	BlockNumber      string   `json:"blockNumber"`
+	TransactionHash  string   `json:"transactionHash"`
+	TransactionIndex string   `json:"transactionIndex"`
+	LogIndex         string   `json:"logIndex"`
+}
+
+// BlockNumber returns the number of the latest block
+func (c *Client) BlockNumber() (int64, error) {
+	var hex string
+	if err := c.Call("eth_blockNumber", &hex); err != nil {
+		return 0, err
+	}
+	return ParseHexInt(hex)
+}
+
+// ChainID returns the chain ID of the network the node serves
+func (c *Client) ChainID() (int64, error) {
+	var hex string
+	if err := c.Call("eth_chainId", &hex); err != nil {
+		return 0, err
+	}
+	return ParseHexInt(hex)
+}
+
+// FinalizedBlockNumber returns the number of the latest finalized block
+func (c *Client) FinalizedBlockNumber() (int64, error) {
+	var block *struct {
+		Number string `json:"number"`
+	}
+	if err := c.Call("eth_getBlockByNumber", &block, "finalized", false); err != nil {
+		return 0, err
+	}
+	if block == nil {
+		return 0, fmt.Errorf("finalized block not available")
+	}
+	return ParseHexInt(block.Number)
+}
+
+// BlockByNumber returns a block including its full transactions
+func (c *Client) BlockByNumber(number int64) (*Block, error) {
+	var block *Block
+	if err := c.Call("eth_getBlockByNumber", &block, ToHex(number), true); err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block %d not found", number)
+	}
+	return block, nil
+}
+
+// TransactionReceipt returns the receipt of a mined transaction
+func (c *Client) TransactionReceipt(hash string) (*Receipt, error) {
+	var receipt *Receipt
+	if err := c.Call("eth_getTransactionReceipt", &receipt, hash); err != nil {
+		return nil, err
+	}
+	if receipt == nil {
+		return nil, fmt.Errorf("receipt not found for transaction %s", hash)
+	}
+	return receipt, nil
+}
+
+// LogFilter selects logs for eth_getLogs
+type LogFilter struct {
+	FromBlock string        `json:"fromBlock"`
+	ToBlock   string        `json:"toBlock"`
+	Address   string        `json:"address,omitempty"`
+	Topics    []interface{} `json:"topics,omitempty"`
+}
+
+// Logs returns the logs matching filter
+func (c *Client) Logs(filter LogFilter) ([]Log, error) {
+	var logs []Log
+	if err := c.Call("eth_getLogs", &logs, filter); err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// Balance returns the native balance of address in wei after block
+func (c *Client) Balance(address string, block int64) (*big.Int, error) {
+	var hex string
+	if err := c.Call("eth_getBalance", &hex, address, ToHex(block)); err != nil {
+		return nil, err
+	}
+	if len(hex) < 3 || hex[:2] != "0x" {
+		return nil, fmt.Errorf("invalid hex quantity %q", hex)
+	}
+	balance, ok := new(big.Int).SetString(hex[2:], 16)
+	if !ok {
+		return nil, fmt.Errorf("invalid hex quantity %q", hex)
+	}
+	return balance, nil
+}
+
+// CallContract executes a read-only contract call at the latest block
+func (c *Client) CallContract(to, data string) (string, error) {
+	var result string
+	err := c.Call("eth_call", &result, map[string]string{"to": to, "data": data}, "latest")
+	return result, err
+}
+
+// ToHex encodes a block number as a JSON-RPC quantity
+func ToHex(n int64) string {
+	return "0x" + strconv.FormatInt(n, 16)
+}
+
+// ParseHexInt parses a JSON-RPC quantity that fits in an int64
+func ParseHexInt(s string) (int64, error) {
+	if len(s) < 3 || s[:2] != "0x" {
+		return 0, fmt.Errorf("invalid hex quantity %q", s)
+	}
+	return strconv.ParseInt(s[2:], 16, 64)
+}
@@ -0,0 +1,123 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/haridev22/ct-assignement/pkg/audit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_Call(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req request
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "2.0", req.JSONRPC)
+
+		switch req.Method {
+		case "eth_blockNumber":
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x10d4f"}`))
+		case "eth_getBlockByNumber":
+			if req.Params[0] == "finalized" {
+				w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"number":"0x10d2f"}}`))
+				return
+			}
+			assert.Equal(t, []interface{}{"0x10", true}, req.Params)
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"number":"0x10","timestamp":"0x5f5e100","transactions":[{"hash":"0xabc","from":"0x1","to":"0x2","value":"0x1"}]}}`))
+		default:
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"method not found"}}`))
+		}
+	}))
+	defer server.Close()
+
+	auditPath := filepath.Join(t.TempDir(), "audit.jsonl")
+	auditLog, err := audit.Open(auditPath)
+	assert.NoError(t, err)
+	client := NewClient(server.URL)
+	client.Audit = auditLog
+
+	number, err := client.BlockNumber()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(68943), number)
+
+	finalized, err := client.FinalizedBlockNumber()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(68911), finalized)
+
+	block, err := client.BlockByNumber(16)
+	assert.NoError(t, err)
+	assert.Len(t, block.Transactions, 1)
+	assert.Equal(t, "0xabc", block.Transactions[0].Hash)
+
+	_, err = client.TransactionReceipt("0xabc")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "method not found")
+
+	assert.NoError(t, auditLog.Close())
+	data, err := os.ReadFile(auditPath)
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if assert.Len(t, lines, 4) {
+		var entry audit.Entry
+		assert.NoError(t, json.Unmarshal([]byte(lines[3]), &entry))
+		assert.Equal(t, "eth_getTransactionReceipt", entry.Method)
+		assert.Equal(t, "rpc", entry.Provider)
+		assert.Contains(t, entry.Error, "method not found")
+	}
+}
+
+func TestClient_CallCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("no request is sent once the context is done")
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	client := NewClient(server.URL)
+	client.Context = ctx
+
+	_, err := client.BlockNumber()
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestParseHexInt(t *testing.T) {
+	n, err := ParseHexInt(ToHex(123456))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(123456), n)
+
+	_, err = ParseHexInt("123")
+	assert.Error(t, err)
+}
+
+func TestClient_ChainID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x89"}`))
+	}))
+	defer server.Close()
+
+	id, err := NewClient(server.URL).ChainID()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(137), id)
+}
+
+func TestClient_Balance(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req request
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "eth_getBalance", req.Method)
+		assert.Equal(t, []interface{}{"0xwallet", "0x3e8"}, req.Params)
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0xde0b6b3a7640000"}`))
+	}))
+	defer server.Close()
+
+	balance, err := NewClient(server.URL).Balance("0xwallet", 1000)
+	assert.NoError(t, err)
+	assert.Equal(t, "1000000000000000000", balance.String())
+}
@@ -0,0 +1,158 @@
+// Package runsummary describes the outcome of an export run as JSON, so
+// automation can verify a run without parsing its console output.
+package runsummary
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+)
+
+// Status is the outcome of a run
+type Status string
+
+const (
+	StatusComplete    Status = "complete"
+	StatusFailed      Status = "failed"
+	StatusInterrupted Status = "interrupted"
+	// StatusPartial is a run that exported its rows, but failed to fetch
+	// some transaction types
+	StatusPartial Status = "partial"
+)
+
+// Summary collects the facts of a run. Its methods are safe for concurrent
+// use and do nothing on a nil Summary, so callers need not check whether a
+// summary was requested.
+type Summary struct {
+	mu sync.Mutex
+
+	Status     Status    `json:"status"`
+	Address    string    `json:"address,omitempty"`
+	Addresses  []string  `json:"addresses,omitempty"`
+	StartBlock int64     `json:"start_block"`
+	EndBlock   int64     `json:"end_block"`
+	StartedAt  time.Time `json:"started_at"`
+	DurationMS int64     `json:"duration_ms"`
+	// Rows counts the exported rows per transaction type
+	Rows        map[models.TransactionType]int `json:"rows"`
+	TotalRows   int                            `json:"total_rows"`
+	APICalls    int64                          `json:"api_calls"`
+	Retries     int64                          `json:"retries"`
+	OutputFiles []string                       `json:"output_files"`
+	ErrorCount  int                            `json:"error_count"`
+	Errors      []string                       `json:"errors,omitempty"`
+	// Incomplete names the transaction types whose rows are missing from
+	// the export, in full or for some blocks
+	Incomplete []string `json:"incomplete,omitempty"`
+}
+
+// New starts a summary of a run beginning now
+func New(startBlock, endBlock int64) *Summary {
+	return &Summary{
+		StartBlock:  startBlock,
+		EndBlock:    endBlock,
+		StartedAt:   time.Now().UTC(),
+		Rows:        map[models.TransactionType]int{},
+		OutputFiles: []string{},
+	}
+}
+
+// CountRows adds exported rows to the per-type counts
+func (s *Summary) CountRows(txs []models.Transaction) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range txs {
+		s.Rows[txs[i].Type]++
+	}
+	s.TotalRows += len(txs)
+}
+
+// AddOutput records a file written by the run
+func (s *Summary) AddOutput(path string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.OutputFiles = append(s.OutputFiles, path)
+}
+
+// AddError records an error the run reported, whether or not it stopped the
+// run. The errors joined by errors.Join are recorded separately.
+func (s *Summary) AddError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			s.AddError(e)
+		}
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Errors = append(s.Errors, err.Error())
+	s.ErrorCount++
+}
+
+// MarkIncomplete records that rows of the transaction type kind are missing
+func (s *Summary) MarkIncomplete(kind string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := sort.SearchStrings(s.Incomplete, kind)
+	if i < len(s.Incomplete) && s.Incomplete[i] == kind {
+		return
+	}
+	s.Incomplete = append(s.Incomplete, "")
+	copy(s.Incomplete[i+1:], s.Incomplete[i:])
+	s.Incomplete[i] = kind
+}
+
+// Finish sets the outcome and duration of the run. A complete run with
+// incomplete transaction types is recorded as partial.
+func (s *Summary) Finish(status Status, apiCalls, retries int64) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if status == StatusComplete && len(s.Incomplete) > 0 {
+		status = StatusPartial
+	}
+	s.Status = status
+	s.APICalls, s.Retries = apiCalls, retries
+	s.DurationMS = time.Since(s.StartedAt).Milliseconds()
+}
+
+// Write encodes the summary as indented JSON
+func (s *Summary) Write(w io.Writer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(s)
+}
+
+// WriteFile writes the summary to path
+func (s *Summary) WriteFile(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := s.Write(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,85 @@
+package runsummary
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummary(t *testing.T) {
+	s := New(100, 200)
+	s.Address = "0xabc"
+	s.CountRows([]models.Transaction{
+		{Type: models.TypeEthTransfer},
+		{Type: models.TypeERC20Transfer},
+		{Type: models.TypeERC20Transfer},
+	})
+	s.AddOutput("out/0xabc_tx_history.csv")
+	s.AddError(errors.New("fetching ERC721 transfers: timeout"))
+	s.AddError(nil)
+	s.Finish(StatusComplete, 12, 2)
+
+	var buf bytes.Buffer
+	assert.NoError(t, s.Write(&buf))
+
+	var decoded map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, "complete", decoded["status"])
+	assert.Equal(t, "0xabc", decoded["address"])
+	assert.Equal(t, float64(100), decoded["start_block"])
+	assert.Equal(t, float64(200), decoded["end_block"])
+	assert.Equal(t, map[string]interface{}{"ETH_TRANSFER": float64(1), "ERC20_TRANSFER": float64(2)}, decoded["rows"])
+	assert.Equal(t, float64(3), decoded["total_rows"])
+	assert.Equal(t, float64(12), decoded["api_calls"])
+	assert.Equal(t, float64(2), decoded["retries"])
+	assert.Equal(t, []interface{}{"out/0xabc_tx_history.csv"}, decoded["output_files"])
+	assert.Equal(t, float64(1), decoded["error_count"])
+	assert.NotContains(t, decoded, "addresses")
+}
+
+func TestWriteFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "summary.json")
+	s := New(0, 10)
+	s.Finish(StatusFailed, 0, 0)
+	assert.NoError(t, s.WriteFile(path))
+
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), `"status": "failed"`)
+	assert.Contains(t, string(content), `"output_files": []`)
+}
+
+func TestNilSummary(t *testing.T) {
+	var s *Summary
+	s.CountRows([]models.Transaction{{}})
+	s.AddOutput("x")
+	s.AddError(errors.New("x"))
+	s.Finish(StatusComplete, 1, 0)
+}
+
+func TestAddJoinedErrors(t *testing.T) {
+	s := New(0, 100)
+	s.AddError(errors.Join(errors.New("error fetching normal transactions: timeout"), errors.New("error fetching ERC-20 transfers: rate limited")))
+	assert.Equal(t, 2, s.ErrorCount)
+	assert.Equal(t, []string{"error fetching normal transactions: timeout", "error fetching ERC-20 transfers: rate limited"}, s.Errors)
+}
+
+func TestMarkIncomplete(t *testing.T) {
+	s := New(0, 100)
+	s.MarkIncomplete("erc20")
+	s.MarkIncomplete("normal")
+	s.MarkIncomplete("erc20")
+	s.Finish(StatusComplete, 5, 0)
+	assert.Equal(t, []string{"erc20", "normal"}, s.Incomplete)
+	assert.Equal(t, StatusPartial, s.Status)
+
+	// A failed run stays failed
+	s.Finish(StatusFailed, 5, 0)
+	assert.Equal(t, StatusFailed, s.Status)
+}
@@ -0,0 +1,123 @@
+// Package sample plans block-range samples and extrapolates totals from
+// them, so a wallet can be sized without a full backfill.
+package sample
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultStrata is the number of equal sub-ranges a block range is split into
+const DefaultStrata = 100
+
+// z95 is the normal quantile for a two-sided 95% confidence interval
+const z95 = 1.96
+
+// Window is an inclusive block sub-range
+type Window struct {
+	Index      int
+	StartBlock int64
+	EndBlock   int64
+}
+
+// ParseFraction parses "10%" or "0.1" into a fraction in (0, 1]
+func ParseFraction(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	percent := strings.HasSuffix(s, "%")
+	value, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid sample size %q", s)
+	}
+	if percent {
+		value /= 100
+	}
+	if value <= 0 || value > 1 {
+		return 0, fmt.Errorf("sample size %q must be between 0 and 100%%", s)
+	}
+	return value, nil
+}
+
+// Plan splits [startBlock, endBlock] into strata equal windows and picks
+// ceil(fraction*strata) of them. The choice is pseudo-random but seeded
+// from the address, so the same inputs always sample the same windows.
+func Plan(address string, startBlock, endBlock int64, fraction float64, strata int) []Window {
+	span := endBlock - startBlock + 1
+	if span <= 0 || strata <= 0 {
+		return nil
+	}
+	if int64(strata) > span {
+		strata = int(span)
+	}
+
+	n := int(math.Ceil(fraction * float64(strata)))
+	if n > strata {
+		n = strata
+	}
+
+	seed := fnv.New64a()
+	seed.Write([]byte(strings.ToLower(address)))
+	rng := rand.New(rand.NewSource(int64(seed.Sum64())))
+	picked := rng.Perm(strata)[:n]
+	sort.Ints(picked)
+
+	windows := make([]Window, n)
+	for i, idx := range picked {
+		windows[i] = Window{
+			Index:      idx,
+			StartBlock: startBlock + span*int64(idx)/int64(strata),
+			EndBlock:   startBlock + span*int64(idx+1)/int64(strata) - 1,
+		}
+	}
+	return windows
+}
+
+// Estimate is an extrapolated total with a 95% confidence interval
+type Estimate struct {
+	Sampled int
+	Total   float64
+	Low     float64
+	High    float64
+}
+
+// Extrapolate estimates the population total from per-window counts,
+// using the finite population correction for sampling without replacement
+func Extrapolate(counts []int, strata int) Estimate {
+	n := len(counts)
+	if n == 0 {
+		return Estimate{}
+	}
+
+	sum := 0
+	for _, c := range counts {
+		sum += c
+	}
+	mean := float64(sum) / float64(n)
+	total := mean * float64(strata)
+
+	var variance float64
+	if n > 1 {
+		for _, c := range counts {
+			d := float64(c) - mean
+			variance += d * d
+		}
+		variance /= float64(n - 1)
+	}
+
+	fpc := 1 - float64(n)/float64(strata)
+	if fpc < 0 {
+		fpc = 0
+	}
+	margin := z95 * float64(strata) * math.Sqrt(fpc*variance/float64(n))
+
+	low := total - margin
+	// The total can never be below what was actually observed
+	if low < float64(sum) {
+		low = float64(sum)
+	}
+	return Estimate{Sampled: sum, Total: total, Low: low, High: total + margin}
+}
@@ -0,0 +1,68 @@
+package sample
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFraction(t *testing.T) {
+	f, err := ParseFraction("10%")
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.1, f, 1e-9)
+
+	f, err = ParseFraction("0.25")
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.25, f, 1e-9)
+
+	for _, bad := range []string{"", "0%", "150%", "abc"} {
+		_, err := ParseFraction(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestPlan(t *testing.T) {
+	windows := Plan("0xabc", 0, 999, 0.1, 100)
+	assert.Len(t, windows, 10)
+
+	// Deterministic for the same inputs
+	assert.Equal(t, windows, Plan("0xABC", 0, 999, 0.1, 100))
+
+	for i, w := range windows {
+		assert.Equal(t, int64(10), w.EndBlock-w.StartBlock+1)
+		if i > 0 {
+			assert.Greater(t, w.StartBlock, windows[i-1].EndBlock)
+		}
+	}
+
+	// A full sample covers the whole range exactly
+	full := Plan("0xabc", 5, 104, 1, 10)
+	assert.Len(t, full, 10)
+	assert.Equal(t, int64(5), full[0].StartBlock)
+	assert.Equal(t, int64(104), full[9].EndBlock)
+
+	// Tiny ranges cap the number of strata
+	assert.Len(t, Plan("0xabc", 0, 2, 1, 100), 3)
+	assert.Empty(t, Plan("0xabc", 10, 5, 0.5, 100))
+}
+
+func TestExtrapolate(t *testing.T) {
+	est := Extrapolate([]int{10, 10, 10, 10}, 40)
+	assert.InDelta(t, 400, est.Total, 1e-9)
+	assert.InDelta(t, 400, est.Low, 1e-9, "no variance means no uncertainty")
+	assert.InDelta(t, 400, est.High, 1e-9)
+	assert.Equal(t, 40, est.Sampled)
+
+	est = Extrapolate([]int{0, 20, 5, 15}, 40)
+	assert.InDelta(t, 400, est.Total, 1e-9)
+	assert.Less(t, est.Low, est.Total)
+	assert.Greater(t, est.High, est.Total)
+	assert.GreaterOrEqual(t, est.Low, float64(est.Sampled))
+
+	// A census has no sampling error
+	est = Extrapolate([]int{1, 5, 9}, 3)
+	assert.InDelta(t, 15, est.Total, 1e-9)
+	assert.InDelta(t, 15, est.High, 1e-9)
+
+	assert.Equal(t, Estimate{}, Extrapolate(nil, 10))
+}
@@ -0,0 +1,403 @@
+// Package scan builds the standard export for an address by reading blocks
+// directly from a JSON-RPC node, for networks that have no explorer API.
+//
+// Every block in the range is fetched with its transactions to find native
+// coin transfers. Token transfers (ERC-20, ERC-721 and ERC-1155) are only
+// looked up in blocks whose logs bloom may contain the address, which skips
+// most blocks cheaply. Internal transfers need tracing APIs and are not
+// detected.
+package scan
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/api"
+	"github.com/haridev22/ct-assignement/pkg/cache"
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/haridev22/ct-assignement/pkg/rpc"
+)
+
+// TransferTopic is the topic of Transfer(address,address,uint256), shared
+// by ERC-20 and ERC-721
+const TransferTopic = "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+
+// ERC-1155 transfer topics
+const (
+	// TransferSingle(address,address,address,uint256,uint256)
+	TransferSingleTopic = "0xc3d58168c5ae7397731d063d5bbf3d657854427343f4c083240f7aacaa2d0f62"
+	// TransferBatch(address,address,address,uint256[],uint256[])
+	TransferBatchTopic = "0x4a39dc06d4c0dbc64b70af90fd698a233a518aa5d07e595d983b8c0526c8f7fb"
+)
+
+// Function selectors for token metadata calls
+const (
+	selectorSymbol   = "0x95d89b41"
+	selectorDecimals = "0x313ce567"
+)
+
+// Node is the subset of the JSON-RPC API used by the scanner
+type Node interface {
+	BlockByNumber(number int64) (*rpc.Block, error)
+	TransactionReceipt(hash string) (*rpc.Receipt, error)
+	Logs(filter rpc.LogFilter) ([]rpc.Log, error)
+	CallContract(to, data string) (string, error)
+}
+
+// Scanner scans blocks for activity of a single address
+type Scanner struct {
+	Node  Node
+	Cache *cache.Cache
+
+	// Progress, if set, is called after each scanned block
+	Progress func(block int64)
+}
+
+// NewScanner creates a scanner reading from node
+func NewScanner(node Node) *Scanner {
+	return &Scanner{Node: node, Cache: cache.New()}
+}
+
+type tokenMetadata struct {
+	symbol   string
+	decimals int
+}
+
+// Scan returns the transactions of address in [startBlock, endBlock]
+func (s *Scanner) Scan(address string, startBlock, endBlock int64) ([]models.Transaction, error) {
+	address = strings.ToLower(address)
+	addressTopic := rpc.AddressTopic(address)
+	if addressTopic == nil {
+		return nil, fmt.Errorf("invalid address %q", address)
+	}
+	topicHex := "0x" + hex.EncodeToString(addressTopic)
+
+	var result []models.Transaction
+	for number := startBlock; number <= endBlock; number++ {
+		block, err := s.Node.BlockByNumber(number)
+		if err != nil {
+			return nil, fmt.Errorf("block %d: %w", number, err)
+		}
+		txs, err := s.scanBlock(number, block, address, addressTopic, topicHex)
+		if err != nil {
+			return nil, fmt.Errorf("block %d: %w", number, err)
+		}
+		result = append(result, txs...)
+
+		if s.Progress != nil {
+			s.Progress(number)
+		}
+	}
+	return result, nil
+}
+
+func (s *Scanner) scanBlock(number int64, block *rpc.Block, address string, addressTopic []byte, topicHex string) ([]models.Transaction, error) {
+	timestamp, err := rpc.ParseHexInt(block.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+	blockTime := time.Unix(timestamp, 0)
+
+	receipts := make(map[string]*rpc.Receipt)
+	receipt := func(hash string) (*rpc.Receipt, error) {
+		if r, ok := receipts[hash]; ok {
+			return r, nil
+		}
+		r, err := s.Node.TransactionReceipt(hash)
+		if err != nil {
+			return nil, err
+		}
+		receipts[hash] = r
+		return r, nil
+	}
+
+	var result []models.Transaction
+	gasPrices := make(map[string]string)
+	for _, tx := range block.Transactions {
+		gasPrices[tx.Hash] = tx.GasPrice
+		if strings.ToLower(tx.From) != address && strings.ToLower(tx.To) != address {
+			continue
+		}
+
+		r, err := receipt(tx.Hash)
+		if err != nil {
+			return nil, err
+		}
+		value, err := api.ParseHexBig(tx.Value)
+		if err != nil {
+			return nil, err
+		}
+		fee, price := gasFee(r, tx.GasPrice)
+
+		result = append(result, models.Transaction{
+			Hash:             tx.Hash,
+			BlockNumber:      number,
+			Timestamp:        blockTime,
+			From:             tx.From,
+			To:               tx.To,
+			Type:             models.TypeEthTransfer,
+			Value:            api.FormatWeiAsEth(value),
+			GasFee:           api.FormatWeiAsEth(fee),
+			RawValue:         value.String(),
+			RawGasFee:        fee.String(),
+			GasPrice:         price.String(),
+			Status:           receiptStatus(r),
+			Nonce:            hexToDecimal(tx.Nonce),
+			GasLimit:         hexToDecimal(tx.Gas),
+			TransactionIndex: hexToDecimal(tx.TransactionIndex),
+		})
+	}
+
+	if !rpc.BloomMayContain(block.LogsBloom, addressTopic) {
+		return result, nil
+	}
+
+	logs, err := s.Node.Logs(rpc.LogFilter{
+		FromBlock: rpc.ToHex(number),
+		ToBlock:   rpc.ToHex(number),
+		Topics:    []interface{}{[]string{TransferTopic, TransferSingleTopic, TransferBatchTopic}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, log := range logs {
+		fromTopic, toTopic, ok := transferParties(log)
+		if !ok || (fromTopic != topicHex && toTopic != topicHex) {
+			continue
+		}
+
+		r, err := receipt(log.TransactionHash)
+		if err != nil {
+			return nil, err
+		}
+		meta := s.tokenMetadata(log.Address)
+		fee, price := gasFee(r, gasPrices[log.TransactionHash])
+
+		tx := models.Transaction{
+			Hash:              log.TransactionHash,
+			BlockNumber:       number,
+			Timestamp:         blockTime,
+			From:              topicAddress(fromTopic),
+			To:                topicAddress(toTopic),
+			AssetContractAddr: log.Address,
+			AssetSymbol:       meta.symbol,
+			GasFee:            api.FormatWeiAsEth(fee),
+			RawGasFee:         fee.String(),
+			GasPrice:          price.String(),
+			Status:            models.StatusSuccess, // Transfer events are only emitted by successful transactions
+			TransactionIndex:  hexToDecimal(log.TransactionIndex),
+		}
+
+		switch {
+		case log.Topics[0] != TransferTopic:
+			rows, err := erc1155Rows(tx, log)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, rows...)
+			continue
+		case len(log.Topics) == 4:
+			// ERC-721 indexes the token ID instead of carrying an amount
+			tokenID, err := api.ParseHexBig(log.Topics[3])
+			if err != nil {
+				return nil, err
+			}
+			tx.Type = models.TypeERC721Transfer
+			tx.TokenID = tokenID.String()
+			tx.Value = "1"
+			tx.RawValue = "1"
+		default:
+			amount, err := api.ParseHexBig(log.Data)
+			if err != nil {
+				return nil, err
+			}
+			tx.Type = models.TypeERC20Transfer
+			tx.Value = api.FormatTokenAmount(amount, meta.decimals)
+			tx.RawValue = amount.String()
+		}
+		result = append(result, tx)
+	}
+
+	return result, nil
+}
+
+// transferParties returns the from and to topics of a transfer log. ERC-1155
+// events index the operator first, so the parties move one position along.
+func transferParties(log rpc.Log) (from, to string, ok bool) {
+	if len(log.Topics) == 0 {
+		return "", "", false
+	}
+	switch log.Topics[0] {
+	case TransferTopic:
+		if len(log.Topics) >= 3 {
+			return log.Topics[1], log.Topics[2], true
+		}
+	case TransferSingleTopic, TransferBatchTopic:
+		if len(log.Topics) == 4 {
+			return log.Topics[2], log.Topics[3], true
+		}
+	}
+	return "", "", false
+}
+
+// erc1155Rows expands a TransferSingle or TransferBatch log into one row per
+// token ID carrying the transferred quantity. Repeated IDs within a batch are
+// summed, since the rows would otherwise share an identity.
+func erc1155Rows(base models.Transaction, log rpc.Log) ([]models.Transaction, error) {
+	raw, err := hex.DecodeString(strings.TrimPrefix(log.Data, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("log data of %s: %w", log.TransactionHash, err)
+	}
+
+	var ids, quantities []*big.Int
+	if log.Topics[0] == TransferSingleTopic {
+		if len(raw) < 64 {
+			return nil, fmt.Errorf("short TransferSingle data in %s", log.TransactionHash)
+		}
+		ids = []*big.Int{new(big.Int).SetBytes(raw[:32])}
+		quantities = []*big.Int{new(big.Int).SetBytes(raw[32:64])}
+	} else {
+		if ids, err = decodeUint256Array(raw, 0); err == nil {
+			quantities, err = decodeUint256Array(raw, 32)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("TransferBatch data in %s: %w", log.TransactionHash, err)
+		}
+		if len(ids) != len(quantities) {
+			return nil, fmt.Errorf("TransferBatch in %s has %d IDs but %d values", log.TransactionHash, len(ids), len(quantities))
+		}
+	}
+
+	var rows []models.Transaction
+	index := make(map[string]int)
+	for i, id := range ids {
+		key := id.String()
+		if j, ok := index[key]; ok {
+			sum, _ := new(big.Int).SetString(rows[j].Value, 10)
+			rows[j].Value = sum.Add(sum, quantities[i]).String()
+			rows[j].RawValue = rows[j].Value
+			continue
+		}
+		row := base
+		row.Type = models.TypeERC1155Transfer
+		row.TokenID = key
+		row.Value = quantities[i].String()
+		row.RawValue = row.Value
+		index[key] = len(rows)
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// decodeUint256Array decodes a dynamic uint256[] whose offset is stored in
+// the head word at position head
+func decodeUint256Array(raw []byte, head int) ([]*big.Int, error) {
+	if len(raw) < head+32 {
+		return nil, fmt.Errorf("missing array offset")
+	}
+	offset := new(big.Int).SetBytes(raw[head : head+32])
+	if !offset.IsInt64() || offset.Int64()+32 > int64(len(raw)) {
+		return nil, fmt.Errorf("array offset out of range")
+	}
+	start := int(offset.Int64())
+	length := new(big.Int).SetBytes(raw[start : start+32])
+	if !length.IsInt64() || int64(start)+32+length.Int64()*32 > int64(len(raw)) {
+		return nil, fmt.Errorf("array length out of range")
+	}
+
+	values := make([]*big.Int, length.Int64())
+	for i := range values {
+		word := start + 32 + i*32
+		values[i] = new(big.Int).SetBytes(raw[word : word+32])
+	}
+	return values, nil
+}
+
+// tokenMetadata resolves symbol and decimals of a token contract, caching
+// the result. Tokens that do not implement the optional metadata functions
+// get an empty symbol and zero decimals.
+func (s *Scanner) tokenMetadata(contract string) tokenMetadata {
+	key := strings.ToLower(contract)
+	if cached, ok := s.Cache.Get(cache.NamespaceTokenMetadata, key); ok {
+		return cached.(tokenMetadata)
+	}
+
+	var meta tokenMetadata
+	if out, err := s.Node.CallContract(contract, selectorSymbol); err == nil {
+		meta.symbol = decodeABIString(out)
+	}
+	if out, err := s.Node.CallContract(contract, selectorDecimals); err == nil {
+		if decimals, err := api.ParseHexBig(out); err == nil && decimals.IsInt64() && decimals.Int64() <= 77 {
+			meta.decimals = int(decimals.Int64())
+		}
+	}
+
+	s.Cache.Set(cache.NamespaceTokenMetadata, key, meta)
+	return meta
+}
+
+// gasFee returns the gas fee of a receipt and the gas price paid in wei,
+// or zero when the receipt does not say
+func gasFee(r *rpc.Receipt, gasPriceHex string) (fee, price *big.Int) {
+	priceHex := r.EffectiveGasPrice
+	if priceHex == "" {
+		priceHex = gasPriceHex
+	}
+	price, err := api.ParseHexBig(priceHex)
+	if err != nil {
+		price = new(big.Int)
+	}
+	gasUsed, err := api.ParseHexBig(r.GasUsed)
+	if err != nil {
+		return new(big.Int), price
+	}
+	return new(big.Int).Mul(gasUsed, price), price
+}
+
+func receiptStatus(r *rpc.Receipt) models.TransactionStatus {
+	if r.Status == "0x0" {
+		return models.StatusFailed
+	}
+	return models.StatusSuccess
+}
+
+func hexToDecimal(s string) string {
+	value, err := api.ParseHexBig(s)
+	if err != nil {
+		return ""
+	}
+	return value.String()
+}
+
+// topicAddress extracts the address from a 32-byte indexed topic
+func topicAddress(topic string) string {
+	topic = strings.TrimPrefix(topic, "0x")
+	if len(topic) < 40 {
+		return ""
+	}
+	return "0x" + topic[len(topic)-40:]
+}
+
+// decodeABIString decodes a string return value, also accepting the
+// bytes32 encoding used by some early tokens
+func decodeABIString(out string) string {
+	raw, err := hex.DecodeString(strings.TrimPrefix(out, "0x"))
+	if err != nil {
+		return ""
+	}
+	if len(raw) == 32 {
+		return strings.TrimRight(string(raw), "\x00")
+	}
+	if len(raw) < 64 {
+		return ""
+	}
+	length := new(big.Int).SetBytes(raw[32:64])
+	if !length.IsInt64() || 64+length.Int64() > int64(len(raw)) {
+		return ""
+	}
+	return string(raw[64 : 64+length.Int64()])
+}
@@ -0,0 +1,194 @@
+package scan
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/haridev22/ct-assignement/pkg/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	wallet      = "0x00000000000000000000000000000000000000aa"
+	walletTopic = "0x00000000000000000000000000000000000000000000000000000000000000aa"
+	otherTopic  = "0x00000000000000000000000000000000000000000000000000000000000000bb"
+)
+
+type fakeNode struct {
+	blocks    map[int64]*rpc.Block
+	receipts  map[string]*rpc.Receipt
+	logs      map[string][]rpc.Log
+	logCalls  int
+	metaCalls int
+}
+
+func (f *fakeNode) BlockByNumber(number int64) (*rpc.Block, error) {
+	block, ok := f.blocks[number]
+	if !ok {
+		return nil, errors.New("missing block")
+	}
+	return block, nil
+}
+
+func (f *fakeNode) TransactionReceipt(hash string) (*rpc.Receipt, error) {
+	return f.receipts[hash], nil
+}
+
+func (f *fakeNode) Logs(filter rpc.LogFilter) ([]rpc.Log, error) {
+	f.logCalls++
+	return f.logs[filter.FromBlock], nil
+}
+
+func (f *fakeNode) CallContract(to, data string) (string, error) {
+	f.metaCalls++
+	switch data {
+	case selectorSymbol:
+		// ABI-encoded "USDC"
+		return "0x" +
+			"0000000000000000000000000000000000000000000000000000000000000020" +
+			"0000000000000000000000000000000000000000000000000000000000000004" +
+			"5553444300000000000000000000000000000000000000000000000000000000", nil
+	case selectorDecimals:
+		return "0x0000000000000000000000000000000000000000000000000000000000000006", nil
+	}
+	return "", errors.New("unknown selector")
+}
+
+func bloomFor(topic string) string {
+	raw, _ := hex.DecodeString(topic[2:])
+	bloom := make([]byte, 256)
+	hash := rpc.Keccak256(raw)
+	for i := 0; i < 6; i += 2 {
+		bit := (uint(hash[i])<<8 | uint(hash[i+1])) & 2047
+		bloom[256-1-bit/8] |= 1 << (bit % 8)
+	}
+	return "0x" + hex.EncodeToString(bloom)
+}
+
+func TestScanner_Scan(t *testing.T) {
+	node := &fakeNode{
+		blocks: map[int64]*rpc.Block{
+			1: {
+				Timestamp: "0x61290000",
+				LogsBloom: bloomFor(otherTopic),
+				Transactions: []rpc.Transaction{
+					{Hash: "0xeth", From: "0x00000000000000000000000000000000000000bb", To: wallet, Value: "0xde0b6b3a7640000", GasPrice: "0x4a817c800", Nonce: "0x1", Gas: "0x5208", TransactionIndex: "0x0"},
+					{Hash: "0xunrelated", From: "0x01", To: "0x02", Value: "0x1"},
+				},
+			},
+			2: {
+				Timestamp: "0x61290010",
+				LogsBloom: bloomFor(walletTopic),
+				Transactions: []rpc.Transaction{
+					{Hash: "0xtoken", From: "0x00000000000000000000000000000000000000bb", To: "0x00000000000000000000000000000000000000cc", GasPrice: "0x4a817c800"},
+				},
+			},
+		},
+		receipts: map[string]*rpc.Receipt{
+			"0xeth":   {GasUsed: "0x5208", Status: "0x1"},
+			"0xtoken": {GasUsed: "0xfde8", EffectiveGasPrice: "0x4a817c800", Status: "0x1"},
+		},
+		logs: map[string][]rpc.Log{
+			"0x2": {
+				{Address: "0x00000000000000000000000000000000000000cc", TransactionHash: "0xtoken",
+					Topics: []string{TransferTopic, otherTopic, walletTopic}, Data: "0x1e8480"},
+				{Address: "0x00000000000000000000000000000000000000cc", TransactionHash: "0xtoken",
+					Topics: []string{TransferTopic, otherTopic, otherTopic}, Data: "0x1"},
+			},
+		},
+	}
+
+	scanner := NewScanner(node)
+	var progress []int64
+	scanner.Progress = func(block int64) { progress = append(progress, block) }
+
+	txs, err := scanner.Scan(wallet, 1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 2}, progress)
+	assert.Equal(t, 1, node.logCalls, "bloom should skip the log lookup for block 1")
+	assert.Len(t, txs, 2)
+
+	eth := txs[0]
+	assert.Equal(t, models.TypeEthTransfer, eth.Type)
+	assert.Equal(t, "1.000000000000000000", eth.Value)
+	assert.Equal(t, "0.000420000000000000", eth.GasFee)
+	assert.Equal(t, "1000000000000000000", eth.RawValue)
+	assert.Equal(t, "420000000000000", eth.RawGasFee)
+	assert.Equal(t, "20000000000", eth.GasPrice)
+	assert.Equal(t, "1", eth.Nonce)
+	assert.Equal(t, models.StatusSuccess, eth.Status)
+
+	token := txs[1]
+	assert.Equal(t, models.TypeERC20Transfer, token.Type)
+	assert.Equal(t, int64(2), token.BlockNumber)
+	assert.Equal(t, "USDC", token.AssetSymbol)
+	assert.Equal(t, "2.000000", token.Value)
+	assert.Equal(t, "2000000", token.RawValue)
+	assert.Equal(t, wallet, token.To)
+}
+
+func word(n int) string {
+	return fmt.Sprintf("%064x", n)
+}
+
+func TestErc1155Rows(t *testing.T) {
+	base := models.Transaction{Hash: "0xgame", GasFee: "0.1"}
+
+	single := rpc.Log{
+		TransactionHash: "0xgame",
+		Topics:          []string{TransferSingleTopic, otherTopic, otherTopic, walletTopic},
+		Data:            "0x" + word(7) + word(30),
+	}
+	rows, err := erc1155Rows(base, single)
+	assert.NoError(t, err)
+	assert.Len(t, rows, 1)
+	assert.Equal(t, models.TypeERC1155Transfer, rows[0].Type)
+	assert.Equal(t, "7", rows[0].TokenID)
+	assert.Equal(t, "30", rows[0].Value)
+
+	// ids [1, 2, 1] with values [5, 10, 3]
+	batch := rpc.Log{
+		TransactionHash: "0xgame",
+		Topics:          []string{TransferBatchTopic, otherTopic, otherTopic, walletTopic},
+		Data: "0x" + word(64) + word(192) +
+			word(3) + word(1) + word(2) + word(1) +
+			word(3) + word(5) + word(10) + word(3),
+	}
+	rows, err = erc1155Rows(base, batch)
+	assert.NoError(t, err)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, "1", rows[0].TokenID)
+	assert.Equal(t, "8", rows[0].Value, "repeated IDs are summed")
+	assert.Equal(t, "2", rows[1].TokenID)
+	assert.Equal(t, "10", rows[1].Value)
+	assert.Equal(t, "0.1", rows[1].GasFee)
+
+	batch.Data = "0x" + word(64) + word(4096)
+	_, err = erc1155Rows(base, batch)
+	assert.Error(t, err)
+}
+
+func TestTransferParties(t *testing.T) {
+	from, to, ok := transferParties(rpc.Log{Topics: []string{TransferBatchTopic, "0xoperator", otherTopic, walletTopic}})
+	assert.True(t, ok)
+	assert.Equal(t, otherTopic, from)
+	assert.Equal(t, walletTopic, to)
+
+	_, _, ok = transferParties(rpc.Log{Topics: []string{TransferSingleTopic, otherTopic, walletTopic}})
+	assert.False(t, ok)
+}
+
+func TestScanner_MissingBlock(t *testing.T) {
+	_, err := NewScanner(&fakeNode{}).Scan(wallet, 5, 5)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "block 5")
+}
+
+func TestDecodeABIString(t *testing.T) {
+	// bytes32 encoding used by early tokens such as MKR
+	assert.Equal(t, "MKR", decodeABIString("0x4d4b520000000000000000000000000000000000000000000000000000000000"))
+	assert.Equal(t, "", decodeABIString("0x"))
+}
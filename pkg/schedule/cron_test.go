@@ -0,0 +1,63 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCron_Next(t *testing.T) {
+	base := time.Date(2024, 5, 14, 10, 7, 30, 0, time.UTC) // a Tuesday
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"*/15 * * * *", time.Date(2024, 5, 14, 10, 15, 0, 0, time.UTC)},
+		{"* * * * *", time.Date(2024, 5, 14, 10, 8, 0, 0, time.UTC)},
+		{"0 6 1 * *", time.Date(2024, 6, 1, 6, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2024, 5, 14, 11, 0, 0, 0, time.UTC)},
+		{"30 9 * * mon-fri", time.Date(2024, 5, 15, 9, 30, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2024, 5, 19, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 jan,jul *", time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)},
+		{"5/20 10 * * *", time.Date(2024, 5, 14, 10, 25, 0, 0, time.UTC)},
+		// With both day fields restricted, either one matches
+		{"0 0 20 * fri", time.Date(2024, 5, 17, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			c, err := Parse(tt.expr)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, c.Next(base))
+			}
+		})
+	}
+}
+
+func TestCron_NextNever(t *testing.T) {
+	c, err := Parse("0 0 30 2 *")
+	assert.NoError(t, err)
+	assert.True(t, c.Next(time.Now()).IsZero())
+}
+
+func TestCron_NextKeepsLocation(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("timezone data unavailable")
+	}
+	c, err := Parse("0 6 * * *")
+	assert.NoError(t, err)
+
+	next := c.Next(time.Date(2024, 3, 30, 12, 0, 0, 0, berlin))
+	// Across the switch to summer time, still 06:00 local time
+	assert.Equal(t, time.Date(2024, 3, 31, 6, 0, 0, 0, berlin), next)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "* * * foo *"} {
+		_, err := Parse(expr)
+		assert.Error(t, err, expr)
+	}
+}
@@ -0,0 +1,92 @@
+package schedule
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// File is the format of a schedule file:
+//
+//	{"timezone": "Europe/Berlin", "jobs": [
+//	  {"name": "sync", "schedule": "*/15 * * * *", "args": ["sync", "-store", "history.json"], "lock": "history"}
+//	]}
+type File struct {
+	// Timezone is the IANA name of the timezone of the schedules. Defaults
+	// to the local timezone.
+	Timezone string  `json:"timezone,omitempty"`
+	Jobs     []Entry `json:"jobs"`
+}
+
+// Entry is a job of a schedule file
+type Entry struct {
+	Name     string `json:"name"`
+	Schedule string `json:"schedule"`
+	// Args are the command-line arguments the job runs with
+	Args []string `json:"args"`
+	Lock string   `json:"lock,omitempty"`
+
+	Cron *Cron `json:"-"`
+}
+
+// LoadFile reads a schedule file
+func LoadFile(path string) (*File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseFile(f)
+}
+
+// ParseFile reads and validates a schedule file, parsing the schedule of
+// every entry
+func ParseFile(r io.Reader) (*File, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	var f File
+	if err := dec.Decode(&f); err != nil {
+		return nil, fmt.Errorf("invalid schedule: %w", err)
+	}
+	if f.Timezone != "" {
+		if _, err := time.LoadLocation(f.Timezone); err != nil {
+			return nil, fmt.Errorf("invalid timezone: %w", err)
+		}
+	}
+	if len(f.Jobs) == 0 {
+		return nil, fmt.Errorf("schedule lists no jobs")
+	}
+
+	seen := make(map[string]bool)
+	for i := range f.Jobs {
+		entry := &f.Jobs[i]
+		if entry.Name == "" {
+			return nil, fmt.Errorf("job %d has no name", i+1)
+		}
+		if seen[entry.Name] {
+			return nil, fmt.Errorf("job %q is defined twice", entry.Name)
+		}
+		seen[entry.Name] = true
+		if len(entry.Args) == 0 {
+			return nil, fmt.Errorf("job %q has no args", entry.Name)
+		}
+		cron, err := Parse(entry.Schedule)
+		if err != nil {
+			return nil, fmt.Errorf("job %q: %w", entry.Name, err)
+		}
+		entry.Cron = cron
+	}
+	return &f, nil
+}
+
+// Location returns the timezone of the schedules
+func (f *File) Location() *time.Location {
+	if f.Timezone == "" {
+		return time.Local
+	}
+	// ParseFile checked the name
+	loc, _ := time.LoadLocation(f.Timezone)
+	return loc
+}
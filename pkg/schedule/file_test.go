@@ -0,0 +1,37 @@
+package schedule
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFile(t *testing.T) {
+	f, err := ParseFile(strings.NewReader(`{"timezone": "UTC", "jobs": [
+		{"name": "sync", "schedule": "*/15 * * * *", "args": ["sync", "-store", "history.json"], "lock": "history"}
+	]}`))
+
+	if assert.NoError(t, err) && assert.Len(t, f.Jobs, 1) {
+		assert.Equal(t, []string{"sync", "-store", "history.json"}, f.Jobs[0].Args)
+		assert.NotNil(t, f.Jobs[0].Cron)
+		assert.Equal(t, time.UTC, f.Location())
+	}
+}
+
+func TestParseFile_Invalid(t *testing.T) {
+	tests := map[string]string{
+		`{"jobs": []}`: "lists no jobs",
+		`{"jobs": [{"schedule": "@daily", "args": ["sync"]}]}`:                                                                     "job 1 has no name",
+		`{"jobs": [{"name": "a", "schedule": "@daily"}]}`:                                                                          `job "a" has no args`,
+		`{"jobs": [{"name": "a", "schedule": "daily", "args": ["sync"]}]}`:                                                         `job "a": invalid cron expression`,
+		`{"jobs": [{"name": "a", "schedule": "@daily", "args": ["sync"]}, {"name": "a", "schedule": "@daily", "args": ["sync"]}]}`: `job "a" is defined twice`,
+		`{"timezone": "Mars/Olympus", "jobs": [{"name": "a", "schedule": "@daily", "args": ["sync"]}]}`:                            "invalid timezone",
+		`{"jobs": [{"name": "a", "cron": "@daily", "args": ["sync"]}]}`:                                                            `unknown field "cron"`,
+	}
+	for input, want := range tests {
+		_, err := ParseFile(strings.NewReader(input))
+		assert.ErrorContains(t, err, want, input)
+	}
+}
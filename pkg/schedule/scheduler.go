@@ -0,0 +1,139 @@
+package schedule
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Job is a task run on a cron schedule
+type Job struct {
+	Name string
+	Cron *Cron
+	// Lock names a lock shared with other jobs. Jobs with the same lock,
+	// such as two jobs writing one store, wait for each other instead of
+	// running at the same time.
+	Lock string
+	Run  func(ctx context.Context) error
+}
+
+// Scheduler runs jobs at the times of their schedules until its context
+// is cancelled. A job still running when it is due again is skipped for
+// that time, so slow runs never pile up.
+type Scheduler struct {
+	Jobs []Job
+	// Location is the timezone of the schedules. Defaults to time.Local.
+	Location *time.Location
+	// Logger receives job starts, failures and skips. Defaults to
+	// slog.Default().
+	Logger *slog.Logger
+
+	// now and after are replaced by tests
+	now   func() time.Time
+	after func(time.Duration) <-chan time.Time
+}
+
+// Run schedules every job and blocks until ctx is cancelled and running
+// jobs have returned
+func (s *Scheduler) Run(ctx context.Context) {
+	locks := make(map[string]*sync.Mutex)
+	for _, job := range s.Jobs {
+		if job.Lock != "" && locks[job.Lock] == nil {
+			locks[job.Lock] = &sync.Mutex{}
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, job := range s.Jobs {
+		wg.Add(1)
+		go func(job Job) {
+			defer wg.Done()
+			s.loop(ctx, job, locks[job.Lock])
+		}(job)
+	}
+	wg.Wait()
+}
+
+// loop runs job at each of its times until ctx is cancelled
+func (s *Scheduler) loop(ctx context.Context, job Job, lock *sync.Mutex) {
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		running bool
+	)
+	defer wg.Wait()
+
+	for {
+		now := s.clock()
+		next := job.Cron.Next(now)
+		if next.IsZero() {
+			s.logger().Warn("job schedule never matches", "job", job.Name)
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.wait(next.Sub(now)):
+		}
+
+		mu.Lock()
+		busy := running
+		running = true
+		mu.Unlock()
+		if busy {
+			s.logger().Warn("job still running, skipping this run", "job", job.Name, "due", next)
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				mu.Lock()
+				running = false
+				mu.Unlock()
+			}()
+			if lock != nil {
+				lock.Lock()
+				defer lock.Unlock()
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			start := time.Now()
+			s.logger().Info("job started", "job", job.Name)
+			if err := job.Run(ctx); err != nil {
+				s.logger().Error("job failed", "job", job.Name, "duration", time.Since(start).Round(time.Millisecond), "error", err)
+				return
+			}
+			s.logger().Info("job finished", "job", job.Name, "duration", time.Since(start).Round(time.Millisecond))
+		}()
+	}
+}
+
+func (s *Scheduler) clock() time.Time {
+	now := time.Now
+	if s.now != nil {
+		now = s.now
+	}
+	loc := s.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	return now().In(loc)
+}
+
+func (s *Scheduler) wait(d time.Duration) <-chan time.Time {
+	if s.after != nil {
+		return s.after(d)
+	}
+	return time.After(d)
+}
+
+func (s *Scheduler) logger() *slog.Logger {
+	if s.Logger != nil {
+		return s.Logger
+	}
+	return slog.Default()
+}
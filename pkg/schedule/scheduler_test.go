@@ -0,0 +1,148 @@
+package schedule
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeClock moves time forward by each requested wait instantly
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+func TestScheduler_Run(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 5, 14, 10, 7, 0, 0, time.UTC)}
+	every15, _ := Parse("*/15 * * * *")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var mu sync.Mutex
+	var runs []time.Time
+	s := &Scheduler{
+		Jobs: []Job{{Name: "sync", Cron: every15, Run: func(context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			runs = append(runs, clock.Now())
+			if len(runs) == 3 {
+				cancel()
+			}
+			return nil
+		}}},
+		Location: time.UTC,
+		now:      clock.Now,
+		after: func(d time.Duration) <-chan time.Time {
+			// Let the previous run finish before time moves on
+			time.Sleep(time.Millisecond)
+			return clock.After(d)
+		},
+	}
+	s.Run(ctx)
+
+	assert.GreaterOrEqual(t, len(runs), 3)
+	assert.Equal(t, time.Date(2024, 5, 14, 10, 15, 0, 0, time.UTC), runs[0])
+	assert.Equal(t, time.Date(2024, 5, 14, 10, 30, 0, 0, time.UTC), runs[1])
+}
+
+func TestScheduler_SkipsOverlappingRuns(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 5, 14, 10, 0, 0, 0, time.UTC)}
+	everyMinute, _ := Parse("* * * * *")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	release := make(chan struct{})
+	var mu sync.Mutex
+	started, waits := 0, 0
+	s := &Scheduler{
+		Jobs: []Job{{Name: "slow", Cron: everyMinute, Run: func(context.Context) error {
+			mu.Lock()
+			started++
+			mu.Unlock()
+			<-release
+			return nil
+		}}},
+		Location: time.UTC,
+		now:      clock.Now,
+		after: func(d time.Duration) <-chan time.Time {
+			mu.Lock()
+			waits++
+			if waits == 5 {
+				cancel()
+				close(release)
+			}
+			mu.Unlock()
+			time.Sleep(time.Millisecond)
+			return clock.After(d)
+		},
+	}
+	s.Run(ctx)
+
+	assert.Equal(t, 1, started, "runs due while the first is still going are skipped")
+}
+
+func TestScheduler_SharedLock(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 5, 14, 10, 0, 0, 0, time.UTC)}
+	// Different minutes give the jobs different waits, so each fires once
+	first, _ := Parse("0 * * * *")
+	second, _ := Parse("1 * * * *")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var mu sync.Mutex
+	active, maxActive, done := 0, 0, 0
+	fired := make(map[time.Duration]bool)
+	job := func(context.Context) error {
+		mu.Lock()
+		active++
+		maxActive = max(maxActive, active)
+		mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		mu.Lock()
+		active--
+		done++
+		if done == 2 {
+			cancel()
+		}
+		mu.Unlock()
+		return nil
+	}
+	s := &Scheduler{
+		Jobs: []Job{
+			{Name: "sync", Cron: first, Lock: "store", Run: job},
+			{Name: "report", Cron: second, Lock: "store", Run: job},
+		},
+		Location: time.UTC,
+		now:      clock.Now,
+		after: func(d time.Duration) <-chan time.Time {
+			mu.Lock()
+			defer mu.Unlock()
+			ch := make(chan time.Time, 1)
+			// Only the first wait of each job fires; later ones wait for cancellation
+			if !fired[d] {
+				fired[d] = true
+				ch <- clock.Now()
+			}
+			return ch
+		},
+	}
+	s.Run(ctx)
+
+	assert.Equal(t, 2, done)
+	assert.Equal(t, 1, maxActive, "jobs sharing a lock do not overlap")
+}
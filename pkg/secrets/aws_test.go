@@ -0,0 +1,60 @@
+package secrets
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSignV4 checks the get-vanilla case of the AWS Signature Version 4 test suite
+func TestSignV4(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	assert.NoError(t, err)
+
+	SignV4(req, nil, "service", "us-east-1", "AKIDEXAMPLE", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+
+	assert.Equal(t, "20150830T123600Z", req.Header.Get("X-Amz-Date"))
+	assert.Equal(t, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, "+
+		"SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		req.Header.Get("Authorization"))
+}
+
+func TestAWSSecretsManager_Read(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secretsmanager.GetSecretValue", r.Header.Get("X-Amz-Target"))
+		assert.Contains(t, r.Header.Get("Authorization"), "/eu-west-1/secretsmanager/aws4_request")
+		var req struct{ SecretId string }
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		switch req.SecretId {
+		case "prod/etherscan":
+			w.Write([]byte(`{"Name":"prod/etherscan","SecretString":"{\"api_key\":\"KEY123\",\"owner\":\"ops\"}"}`))
+		case "plain":
+			w.Write([]byte(`{"Name":"plain","SecretString":"PLAINKEY"}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"__type":"ResourceNotFoundException"}`))
+		}
+	}))
+	defer server.Close()
+
+	sm := &AWSSecretsManager{Region: "eu-west-1", AccessKeyID: "AKID", SecretAccessKey: "secret", Endpoint: server.URL, HTTPClient: server.Client()}
+
+	value, err := sm.Read("prod/etherscan", "api_key")
+	assert.NoError(t, err)
+	assert.Equal(t, "KEY123", value)
+
+	value, err = sm.Read("plain", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "PLAINKEY", value)
+
+	_, err = sm.Read("prod/etherscan", "missing")
+	assert.ErrorContains(t, err, `no field "missing"`)
+
+	_, err = sm.Read("unknown", "")
+	assert.ErrorContains(t, err, "ResourceNotFoundException")
+}
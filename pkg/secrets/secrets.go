@@ -0,0 +1,80 @@
+// Package secrets resolves secret references such as
+// vault://secret/etherscan#api_key to their values, so API keys can stay in
+// a secrets manager instead of on the command line.
+package secrets
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Resolve returns the secret value refers to. Supported references are
+// vault://PATH[#FIELD] (HashiCorp Vault KV, v1 or v2) and
+// aws-sm://SECRET-ID[#FIELD] (AWS Secrets Manager). Any other value is
+// returned unchanged, so plain keys keep working.
+func Resolve(value string) (string, error) {
+	scheme, ref, ok := strings.Cut(value, "://")
+	if !ok {
+		return value, nil
+	}
+	path, field, _ := strings.Cut(ref, "#")
+
+	switch scheme {
+	case "vault":
+		vault, err := VaultKVFromEnv()
+		if err != nil {
+			return "", err
+		}
+		return vault.Read(path, field)
+	case "aws-sm":
+		sm, err := AWSSecretsManagerFromEnv()
+		if err != nil {
+			return "", err
+		}
+		return sm.Read(path, field)
+	}
+	return value, nil
+}
+
+// selectField picks field from a secret's key/value pairs. Without a field
+// the secret must hold exactly one value.
+func selectField(name string, values map[string]interface{}, field string) (string, error) {
+	if field == "" {
+		if len(values) != 1 {
+			keys := make([]string, 0, len(values))
+			for key := range values {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			return "", fmt.Errorf("secret %s has fields %s; select one with #FIELD", name, strings.Join(keys, ", "))
+		}
+		for key := range values {
+			field = key
+		}
+	}
+
+	value, ok := values[field]
+	if !ok {
+		return "", fmt.Errorf("secret %s has no field %q", name, field)
+	}
+	s, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("field %q of secret %s is not a string", field, name)
+	}
+	return s, nil
+}
+
+// selectFromString picks field from a secret stored as a single string,
+// which may itself be a JSON object of key/value pairs
+func selectFromString(name, secret, field string) (string, error) {
+	if field == "" {
+		return secret, nil
+	}
+	var values map[string]interface{}
+	if err := json.Unmarshal([]byte(secret), &values); err != nil {
+		return "", fmt.Errorf("secret %s is not a JSON object, so field %q cannot be selected", name, field)
+	}
+	return selectField(name, values, field)
+}
@@ -0,0 +1,42 @@
+package secrets
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolve_PlainValues(t *testing.T) {
+	for _, value := range []string{"ABC123", "", "https://mainnet.infura.io/v3/key"} {
+		resolved, err := Resolve(value)
+		assert.NoError(t, err)
+		assert.Equal(t, value, resolved)
+	}
+}
+
+func TestResolve_Vault(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"data":{"api_key":"FROMVAULT","other":"x"}}}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("VAULT_ADDR", server.URL)
+	t.Setenv("VAULT_TOKEN", "root")
+
+	value, err := Resolve("vault://secret/etherscan#api_key")
+	assert.NoError(t, err)
+	assert.Equal(t, "FROMVAULT", value)
+
+	t.Setenv("VAULT_TOKEN", "")
+	_, err = Resolve("vault://secret/etherscan#api_key")
+	assert.ErrorContains(t, err, "VAULT_ADDR and VAULT_TOKEN")
+}
+
+func TestResolve_AWSRequiresCredentials(t *testing.T) {
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_DEFAULT_REGION", "")
+	_, err := Resolve("aws-sm://prod/etherscan")
+	assert.ErrorContains(t, err, "AWS_REGION")
+}
@@ -0,0 +1,90 @@
+package secrets
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// VaultKV reads secrets from a HashiCorp Vault key/value engine
+type VaultKV struct {
+	Addr       string
+	Token      string
+	HTTPClient *http.Client
+}
+
+// VaultKVFromEnv configures Vault from VAULT_ADDR and VAULT_TOKEN
+func VaultKVFromEnv() (*VaultKV, error) {
+	addr, token := os.Getenv("VAULT_ADDR"), os.Getenv("VAULT_TOKEN")
+	if addr == "" || token == "" {
+		return nil, errors.New("vault:// secrets require VAULT_ADDR and VAULT_TOKEN")
+	}
+	return &VaultKV{Addr: addr, Token: token, HTTPClient: &http.Client{Timeout: 30 * time.Second}}, nil
+}
+
+// Read returns field of the secret at path. The path is read from a KV v2
+// engine first (MOUNT/data/PATH) and from a KV v1 engine if that fails.
+func (v *VaultKV) Read(path, field string) (string, error) {
+	path = strings.Trim(path, "/")
+	mount, rest, ok := strings.Cut(path, "/")
+	if !ok {
+		return "", fmt.Errorf("vault secret path %q must include the engine mount, e.g. secret/etherscan", path)
+	}
+
+	var v2 struct {
+		Data struct {
+			Data map[string]interface{} `json:"data"`
+		} `json:"data"`
+	}
+	found, err := v.get(mount+"/data/"+rest, &v2)
+	if err != nil {
+		return "", err
+	}
+	if found && v2.Data.Data != nil {
+		return selectField(path, v2.Data.Data, field)
+	}
+
+	var v1 struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	found, err = v.get(path, &v1)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return "", fmt.Errorf("vault secret %s not found", path)
+	}
+	return selectField(path, v1.Data, field)
+}
+
+// get reads a Vault path, reporting false when it does not exist
+func (v *VaultKV) get(path string, result interface{}) (bool, error) {
+	req, err := http.NewRequest(http.MethodGet, strings.TrimRight(v.Addr, "/")+"/v1/"+path, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("X-Vault-Token", v.Token)
+
+	resp, err := v.HTTPClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("vault request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("vault read of %s failed with status %d: %s", path, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return true, json.Unmarshal(body, result)
+}
@@ -0,0 +1,44 @@
+package secrets
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVaultKV_Read(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "root", r.Header.Get("X-Vault-Token"))
+		switch r.URL.Path {
+		case "/v1/secret/data/etherscan":
+			w.Write([]byte(`{"data":{"data":{"api_key":"V2KEY"},"metadata":{"version":3}}}`))
+		case "/v1/kv/infura":
+			w.Write([]byte(`{"data":{"project_id":"abc","project_secret":"def"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[]}`))
+		}
+	}))
+	defer server.Close()
+
+	vault := &VaultKV{Addr: server.URL, Token: "root", HTTPClient: server.Client()}
+
+	value, err := vault.Read("secret/etherscan", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "V2KEY", value, "a single-value secret needs no field")
+
+	value, err = vault.Read("kv/infura", "project_id")
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", value, "KV v1 mounts are read directly")
+
+	_, err = vault.Read("kv/infura", "")
+	assert.EqualError(t, err, "secret kv/infura has fields project_id, project_secret; select one with #FIELD")
+
+	_, err = vault.Read("secret/missing", "")
+	assert.EqualError(t, err, "vault secret secret/missing not found")
+
+	_, err = vault.Read("etherscan", "")
+	assert.ErrorContains(t, err, "must include the engine mount")
+}
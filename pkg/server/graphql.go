@@ -0,0 +1,266 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/filter"
+	"github.com/haridev22/ct-assignement/pkg/graphql"
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/haridev22/ct-assignement/pkg/report"
+	"github.com/haridev22/ct-assignement/pkg/store"
+)
+
+// Schema documents the GraphQL schema served at /graphql
+const Schema = `type Query {
+  transactions(address: String!, types: [String!], token: String, counterparty: String, hash: String,
+    since: String, until: String, fromBlock: Int, toBlock: Int, offset: Int = 0, limit: Int = 100): TransactionPage!
+  aggregate(address: String!, types: [String!], token: String, counterparty: String, hash: String,
+    since: String, until: String, fromBlock: Int, toBlock: Int): Aggregate!
+}
+
+type TransactionPage {
+  total: Int!
+  offset: Int!
+  limit: Int!
+  items: [Transaction!]!
+}
+
+type Transaction {
+  hash: String!
+  blockNumber: Int!
+  timestamp: String!
+  from: String!
+  to: String!
+  type: String!
+  contract: String!
+  symbol: String!
+  tokenId: String!
+  value: String!
+  gasFee: String!
+  status: String!
+  chain: String!
+}
+
+type Aggregate {
+  count: Int!
+  failed: Int!
+  gasSpent: String!
+  firstTimestamp: String
+  lastTimestamp: String
+  byType: [TypeCount!]!
+  assets: [AssetTotal!]!
+}
+
+type TypeCount {
+  type: String!
+  count: Int!
+}
+
+type AssetTotal {
+  symbol: String!
+  contract: String!
+  count: Int!
+  incoming: String!
+  outgoing: String!
+  net: String!
+}
+`
+
+// rowArguments are the arguments shared by the fields that select rows
+var rowArguments = []string{"address", "types", "token", "counterparty", "hash", "since", "until", "fromBlock", "toBlock"}
+
+// handleGraphQL executes a GraphQL query, sent as the JSON body of a POST
+// or as the query, variables and operationName parameters of a GET
+func (s *Server) handleGraphQL(w http.ResponseWriter, r *http.Request) {
+	var req graphql.Request
+	if r.Method == http.MethodPost {
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+			return
+		}
+	} else {
+		query := r.URL.Query()
+		req.Query = query.Get("query")
+		req.OperationName = query.Get("operationName")
+		if variables := query.Get("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				writeError(w, http.StatusBadRequest, fmt.Errorf("invalid variables: %w", err))
+				return
+			}
+		}
+	}
+
+	st, err := s.Store()
+	if err != nil {
+		s.logger().Error("failed to open store", "error", err)
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("store unavailable"))
+		return
+	}
+
+	resp := graphql.Execute(queryRoot(st), req)
+	w.Header().Set("Content-Type", mediaTypes[FormatJSON])
+	if resp.Data == nil {
+		w.WriteHeader(http.StatusBadRequest)
+	}
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		s.logger().Warn("failed to write response", "path", r.URL.Path, "error", err)
+	}
+}
+
+// queryRoot is the Query object of st
+func queryRoot(st *store.Store) graphql.Object {
+	return graphql.Object{
+		"transactions": func(args graphql.Args) (interface{}, error) {
+			if err := args.Check(append(rowArguments, "offset", "limit")...); err != nil {
+				return nil, err
+			}
+			txs, err := selectRows(st, args)
+			if err != nil {
+				return nil, err
+			}
+			offset, err := args.Int("offset", 0)
+			if err != nil {
+				return nil, err
+			}
+			limit, err := args.Int("limit", DefaultLimit)
+			if err != nil {
+				return nil, err
+			}
+			if offset < 0 || limit < 1 || limit > MaxLimit {
+				return nil, fmt.Errorf("offset must not be negative and limit must be 1 to %d", MaxLimit)
+			}
+
+			items := []graphql.Object{}
+			for i := offset; i < int64(len(txs)) && i < offset+limit; i++ {
+				items = append(items, transactionObject(txs[i]))
+			}
+			return graphql.Object{
+				"total":  constant(len(txs)),
+				"offset": constant(offset),
+				"limit":  constant(limit),
+				"items":  constant(items),
+			}, nil
+		},
+		"aggregate": func(args graphql.Args) (interface{}, error) {
+			if err := args.Check(rowArguments...); err != nil {
+				return nil, err
+			}
+			txs, err := selectRows(st, args)
+			if err != nil {
+				return nil, err
+			}
+			address, _ := args.String("address")
+			return aggregateObject(address, txs), nil
+		},
+	}
+}
+
+// selectRows returns the latest rows of the address argument that match
+// the filter arguments
+func selectRows(st *store.Store, args graphql.Args) ([]models.Transaction, error) {
+	address, err := args.String("address")
+	if err != nil {
+		return nil, err
+	}
+	if address == "" {
+		return nil, fmt.Errorf("argument \"address\" is required")
+	}
+	if len(st.Runs(address)) == 0 {
+		return nil, fmt.Errorf("address %s has not been synced", address)
+	}
+
+	q := rowQuery{}
+	if q.types, err = args.Strings("types"); err != nil {
+		return nil, err
+	}
+	for name, target := range map[string]*string{
+		"token": &q.token, "counterparty": &q.counterparty, "hash": &q.hash, "since": &q.since, "until": &q.until,
+	} {
+		if *target, err = args.String(name); err != nil {
+			return nil, err
+		}
+	}
+	if q.fromBlock, err = args.Int("fromBlock", -1); err != nil {
+		return nil, err
+	}
+	if q.toBlock, err = args.Int("toBlock", -1); err != nil {
+		return nil, err
+	}
+	filters, err := q.filters()
+	if err != nil {
+		return nil, err
+	}
+	return filter.Apply(st.Latest(address), filters...), nil
+}
+
+func transactionObject(tx models.Transaction) graphql.Object {
+	return graphql.Object{
+		"hash":        constant(tx.Hash),
+		"blockNumber": constant(tx.BlockNumber),
+		"timestamp":   constant(tx.Timestamp.UTC().Format(time.RFC3339)),
+		"from":        constant(tx.From),
+		"to":          constant(tx.To),
+		"type":        constant(string(tx.Type)),
+		"contract":    constant(tx.AssetContractAddr),
+		"symbol":      constant(tx.AssetSymbol),
+		"tokenId":     constant(tx.TokenID),
+		"value":       constant(tx.Value),
+		"gasFee":      constant(tx.GasFee),
+		"status":      constant(string(tx.Status)),
+		"chain":       constant(tx.Chain),
+	}
+}
+
+func aggregateObject(address string, txs []models.Transaction) graphql.Object {
+	summary := report.Summarize(txs)
+	var types []string
+	for typ := range summary.ByType {
+		types = append(types, string(typ))
+	}
+	sort.Strings(types)
+	byType := []graphql.Object{}
+	for _, typ := range types {
+		count := summary.ByType[models.TransactionType(typ)]
+		byType = append(byType, graphql.Object{"type": constant(typ), "count": constant(count)})
+	}
+	assets := []graphql.Object{}
+	for _, total := range report.Totals(address, txs) {
+		assets = append(assets, graphql.Object{
+			"symbol":   constant(total.Symbol),
+			"contract": constant(total.Contract),
+			"count":    constant(total.Count),
+			"incoming": constant(report.FormatAmount(total.Incoming)),
+			"outgoing": constant(report.FormatAmount(total.Outgoing)),
+			"net":      constant(report.FormatAmount(total.Net())),
+		})
+	}
+
+	var first, last interface{}
+	if summary.Total > 0 {
+		first = summary.FirstTime.UTC().Format(time.RFC3339)
+		last = summary.LastTime.UTC().Format(time.RFC3339)
+	}
+	return graphql.Object{
+		"count":          constant(summary.Total),
+		"failed":         constant(summary.Failed),
+		"gasSpent":       constant(report.FormatAmount(report.GasSpent(address, txs))),
+		"firstTimestamp": constant(first),
+		"lastTimestamp":  constant(last),
+		"byType":         constant(byType),
+		"assets":         constant(assets),
+	}
+}
+
+// constant resolves a field without arguments to value
+func constant(value interface{}) graphql.Resolver {
+	return func(args graphql.Args) (interface{}, error) {
+		if len(args) > 0 {
+			return nil, fmt.Errorf("field takes no arguments")
+		}
+		return value, nil
+	}
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/haridev22/ct-assignement/pkg/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func postGraphQL(t *testing.T, s *store.Store, body string) (int, map[string]interface{}) {
+	rec := httptest.NewRecorder()
+	New(func() (*store.Store, error) { return s, nil }).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body)))
+	var resp map[string]interface{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	return rec.Code, resp
+}
+
+func TestGraphQL_Transactions(t *testing.T) {
+	code, resp := postGraphQL(t, testStore(t), `{
+		"query": "query ($a: String!) { transactions(address: $a, types: [\"ETH_TRANSFER\"], since: \"2024-01-02\", limit: 5) { total items { hash blockNumber timestamp } } }",
+		"variables": {"a": "0xWallet"}
+	}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, map[string]interface{}{
+		"transactions": map[string]interface{}{
+			"total": float64(1),
+			"items": []interface{}{
+				map[string]interface{}{"hash": "0x3", "blockNumber": float64(30), "timestamp": "2024-01-03T12:00:00Z"},
+			},
+		},
+	}, resp["data"])
+	assert.Nil(t, resp["errors"])
+}
+
+func TestGraphQL_Aggregate(t *testing.T) {
+	_, resp := postGraphQL(t, testStore(t), `{"query": "{ aggregate(address: \"0xwallet\") { count byType { type count } assets { symbol net } } }"}`)
+	assert.Equal(t, map[string]interface{}{
+		"aggregate": map[string]interface{}{
+			"count": float64(3),
+			"byType": []interface{}{
+				map[string]interface{}{"type": "ERC20_TRANSFER", "count": float64(1)},
+				map[string]interface{}{"type": "ETH_TRANSFER", "count": float64(2)},
+			},
+			"assets": []interface{}{
+				map[string]interface{}{"symbol": "ETH", "net": "3"},
+			},
+		},
+	}, resp["data"])
+}
+
+func TestGraphQL_Errors(t *testing.T) {
+	s := testStore(t)
+	code, resp := postGraphQL(t, s, `{"query": "{ transactions(address: \"0xunknown\") { total } }"}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Contains(t, resp["errors"].([]interface{})[0].(map[string]interface{})["message"], "has not been synced")
+
+	_, resp = postGraphQL(t, s, `{"query": "{ transactions(address: \"0xwallet\", colour: \"red\") { total } }"}`)
+	assert.Contains(t, resp["errors"].([]interface{})[0].(map[string]interface{})["message"], "unknown argument colour")
+
+	code, _ = postGraphQL(t, s, `{"query": "{ transactions("}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestGraphQL_Get(t *testing.T) {
+	rec := httptest.NewRecorder()
+	target := "/graphql?query=" + url.QueryEscape("{ aggregate(address: \"0xwallet\") { count } }")
+	New(func() (*store.Store, error) { return testStore(t), nil }).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"data":{"aggregate":{"count":3}}}`, rec.Body.String())
+}
+
+func TestGraphQL_Schema(t *testing.T) {
+	rec := httptest.NewRecorder()
+	New(nil).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/graphql/schema", nil))
+	assert.Equal(t, Schema, rec.Body.String())
+}
@@ -0,0 +1,324 @@
+// Package server serves the histories synced into a store over HTTP, so
+// other services can query them instead of parsing exports.
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/daterange"
+	"github.com/haridev22/ct-assignement/pkg/export"
+	"github.com/haridev22/ct-assignement/pkg/filter"
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/haridev22/ct-assignement/pkg/store"
+)
+
+const (
+	// DefaultLimit is the page size when a request gives no limit
+	DefaultLimit = 100
+	// MaxLimit is the largest page a request can ask for
+	MaxLimit = 1000
+)
+
+// Response formats, selected by the format parameter or the Accept header
+const (
+	FormatJSON   = "json"
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+)
+
+var mediaTypes = map[string]string{
+	FormatJSON:   "application/json",
+	FormatCSV:    "text/csv",
+	FormatNDJSON: "application/x-ndjson",
+}
+
+// Server answers queries about the addresses of a store
+type Server struct {
+	// Store returns the store to serve. It is called for every request, so
+	// it can pick up rows synced while the server runs.
+	Store func() (*store.Store, error)
+	// Logger receives request errors. Defaults to slog.Default().
+	Logger *slog.Logger
+	// Update applies a change to the store and saves it. Without it the
+	// server is read-only, and watchlist changes answer 405.
+	Update func(change func(*store.Store) error) error
+	// Tenant returns the tenant whose watchlists a request sees. Defaults
+	// to the TenantHeader header, or DefaultTenant without one.
+	Tenant func(r *http.Request) string
+
+	mux *http.ServeMux
+}
+
+// New creates a server reading from open
+func New(open func() (*store.Store, error)) *Server {
+	s := &Server{Store: open, mux: http.NewServeMux()}
+	s.mux.HandleFunc("GET /addresses/{address}/transactions", s.handleTransactions)
+	s.mux.HandleFunc("GET /graphql", s.handleGraphQL)
+	s.mux.HandleFunc("POST /graphql", s.handleGraphQL)
+	s.watchlistRoutes()
+	s.mux.HandleFunc("GET /graphql/schema", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		io.WriteString(w, Schema)
+	})
+	return s
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Page is the JSON response of the transactions endpoint
+type Page struct {
+	Address string `json:"address"`
+	// Total is the number of rows matching the filters, across all pages
+	Total        int                  `json:"total"`
+	Offset       int                  `json:"offset"`
+	Limit        int                  `json:"limit"`
+	Next         string               `json:"next,omitempty"`
+	Transactions []models.Transaction `json:"transactions"`
+}
+
+// handleTransactions serves the latest rows of an address. The filters
+// mirror the query command: type, hash and counterparty, plus token,
+// from_block, to_block, since and until. Results are paged with offset and
+// limit.
+func (s *Server) handleTransactions(w http.ResponseWriter, r *http.Request) {
+	address := r.PathValue("address")
+	query := r.URL.Query()
+
+	format, err := negotiate(query.Get("format"), r.Header.Get("Accept"))
+	if err != nil {
+		writeError(w, http.StatusNotAcceptable, err)
+		return
+	}
+	rows, err := parseRowQuery(query)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	filters, err := rows.filters()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	offset, limit, err := parsePage(query)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	var columns []models.Column
+	if format == FormatCSV && query.Get("columns") != "" {
+		if columns, err = models.ParseColumnKeys(query.Get("columns")); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	st, err := s.Store()
+	if err != nil {
+		s.logger().Error("failed to open store", "error", err)
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("store unavailable"))
+		return
+	}
+	if len(st.Runs(address)) == 0 {
+		writeError(w, http.StatusNotFound, fmt.Errorf("address %s has not been synced", address))
+		return
+	}
+
+	txs := filter.Apply(st.Latest(address), filters...)
+	page := Page{Address: address, Total: len(txs), Offset: offset, Limit: limit}
+	end := min(offset+limit, len(txs))
+	page.Transactions = []models.Transaction{}
+	if offset < len(txs) {
+		page.Transactions = txs[offset:end]
+	}
+	if end < len(txs) {
+		page.Next = nextURL(r.URL, end, limit)
+	}
+
+	w.Header().Set("Content-Type", mediaTypes[format])
+	w.Header().Set("X-Total-Count", strconv.Itoa(page.Total))
+	if page.Next != "" {
+		w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, page.Next))
+	}
+	switch format {
+	case FormatCSV:
+		err = export.WriteCSVTo(w, page.Transactions, export.CSVOptions{Columns: columns})
+	case FormatNDJSON:
+		err = export.NewStreamWriter(w, export.StreamNDJSON).Write(page.Transactions)
+	default:
+		err = json.NewEncoder(w).Encode(page)
+	}
+	if err != nil {
+		s.logger().Warn("failed to write response", "path", r.URL.Path, "error", err)
+	}
+}
+
+// negotiate picks the response format from the format parameter or, when
+// it is empty, the first supported media type of the Accept header.
+// Quality values are not weighed; clients list their preference first.
+func negotiate(format, accept string) (string, error) {
+	if format != "" {
+		if _, ok := mediaTypes[format]; !ok {
+			return "", fmt.Errorf("unknown format %q (use json, csv or ndjson)", format)
+		}
+		return format, nil
+	}
+	if strings.TrimSpace(accept) == "" {
+		return FormatJSON, nil
+	}
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		switch mediaType {
+		case "*/*", "application/*":
+			return FormatJSON, nil
+		case "text/*":
+			return FormatCSV, nil
+		}
+		for name, candidate := range mediaTypes {
+			if mediaType == candidate {
+				return name, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("none of %q can be served (use application/json, text/csv or application/x-ndjson)", accept)
+}
+
+// rowQuery selects the rows of an address, for both the REST and GraphQL
+// endpoints
+type rowQuery struct {
+	types        []string
+	hash         string
+	counterparty string
+	token        string
+	// fromBlock and toBlock are negative when not given
+	fromBlock int64
+	toBlock   int64
+	since     string
+	until     string
+}
+
+// parseRowQuery reads the row filters of a REST request
+func parseRowQuery(query url.Values) (rowQuery, error) {
+	q := rowQuery{
+		hash:         query.Get("hash"),
+		counterparty: query.Get("counterparty"),
+		token:        query.Get("token"),
+		fromBlock:    -1,
+		toBlock:      -1,
+		since:        query.Get("since"),
+		until:        query.Get("until"),
+	}
+	if types := query.Get("type"); types != "" {
+		q.types = strings.Split(types, ",")
+	}
+	for name, target := range map[string]*int64{"from_block": &q.fromBlock, "to_block": &q.toBlock} {
+		if value := query.Get(name); value != "" {
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || n < 0 {
+				return q, fmt.Errorf("invalid %s %q", name, value)
+			}
+			*target = n
+		}
+	}
+	return q, nil
+}
+
+// filters returns the row filters selected by q
+func (q rowQuery) filters() ([]filter.Func, error) {
+	var filters []filter.Func
+	if len(q.types) > 0 {
+		var selected []models.TransactionType
+		for _, t := range q.types {
+			selected = append(selected, models.TransactionType(strings.ToUpper(strings.TrimSpace(t))))
+		}
+		filters = append(filters, filter.Types(selected...))
+	}
+	if q.hash != "" {
+		filters = append(filters, filter.Hash(q.hash))
+	}
+	if q.counterparty != "" {
+		filters = append(filters, filter.Counterparty(q.counterparty))
+	}
+	if q.token != "" {
+		filters = append(filters, filter.Token(q.token))
+	}
+	if q.fromBlock >= 0 || q.toBlock >= 0 {
+		filters = append(filters, filter.Blocks(q.fromBlock, q.toBlock))
+	}
+
+	var since, until time.Time
+	if q.since != "" {
+		t, _, err := daterange.ParseDate(q.since)
+		if err != nil {
+			return nil, fmt.Errorf("invalid since: %w", err)
+		}
+		since = t
+	}
+	if q.until != "" {
+		t, dateOnly, err := daterange.ParseDate(q.until)
+		if err != nil {
+			return nil, fmt.Errorf("invalid until: %w", err)
+		}
+		// A date includes its whole day
+		if dateOnly {
+			t = t.AddDate(0, 0, 1).Add(-time.Second)
+		}
+		until = t
+	}
+	if !since.IsZero() || !until.IsZero() {
+		filters = append(filters, filter.Between(since, until))
+	}
+	return filters, nil
+}
+
+// parsePage reads the offset and limit of a request
+func parsePage(query url.Values) (offset, limit int, err error) {
+	limit = DefaultLimit
+	if value := query.Get("limit"); value != "" {
+		if limit, err = strconv.Atoi(value); err != nil || limit < 1 || limit > MaxLimit {
+			return 0, 0, fmt.Errorf("invalid limit %q (use 1 to %d)", value, MaxLimit)
+		}
+	}
+	if value := query.Get("offset"); value != "" {
+		if offset, err = strconv.Atoi(value); err != nil || offset < 0 {
+			return 0, 0, fmt.Errorf("invalid offset %q", value)
+		}
+	}
+	return offset, limit, nil
+}
+
+// nextURL returns the request URL of the page starting at offset
+func nextURL(u *url.URL, offset, limit int) string {
+	query := u.Query()
+	query.Set("offset", strconv.Itoa(offset))
+	query.Set("limit", strconv.Itoa(limit))
+	return u.Path + "?" + query.Encode()
+}
+
+// writeError answers with a JSON error body
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", mediaTypes[FormatJSON])
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
+
+func (s *Server) logger() *slog.Logger {
+	if s.Logger != nil {
+		return s.Logger
+	}
+	return slog.Default()
+}
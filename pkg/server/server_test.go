@@ -0,0 +1,123 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/haridev22/ct-assignement/pkg/store"
+	"github.com/stretchr/testify/assert"
+)
+
+const testAddress = "0xwallet"
+
+func testStore(t *testing.T) *store.Store {
+	s, err := store.Open(filepath.Join(t.TempDir(), "store.json"))
+	assert.NoError(t, err)
+	var txs []models.Transaction
+	for i, typ := range []models.TransactionType{models.TypeEthTransfer, models.TypeERC20Transfer, models.TypeEthTransfer} {
+		txs = append(txs, models.Transaction{
+			Hash:        "0x" + string(rune('1'+i)),
+			BlockNumber: int64(10 * (i + 1)),
+			Timestamp:   time.Date(2024, 1, i+1, 12, 0, 0, 0, time.UTC),
+			From:        "0xother",
+			To:          testAddress,
+			Type:        typ,
+			Value:       "1",
+		})
+	}
+	_, err = s.Upsert(s.BeginRun(testAddress), txs)
+	assert.NoError(t, err)
+	return s
+}
+
+func get(t *testing.T, s *store.Store, target, accept string) *httptest.ResponseRecorder {
+	srv := New(func() (*store.Store, error) { return s, nil })
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestTransactions_JSON(t *testing.T) {
+	rec := get(t, testStore(t), "/addresses/0xWallet/transactions?limit=2", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "3", rec.Header().Get("X-Total-Count"))
+
+	var page Page
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+	assert.Equal(t, 3, page.Total)
+	assert.Len(t, page.Transactions, 2)
+	assert.Equal(t, "/addresses/0xWallet/transactions?limit=2&offset=2", page.Next)
+	assert.Equal(t, `</addresses/0xWallet/transactions?limit=2&offset=2>; rel="next"`, rec.Header().Get("Link"))
+
+	rec = get(t, testStore(t), page.Next, "")
+	var last Page
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &last))
+	assert.Len(t, last.Transactions, 1)
+	assert.Empty(t, last.Next)
+}
+
+func TestTransactions_Filters(t *testing.T) {
+	s := testStore(t)
+	for target, want := range map[string]int{
+		"type=erc20_transfer":               1,
+		"from_block=20":                     2,
+		"to_block=20":                       2,
+		"since=2024-01-02&until=2024-01-02": 1,
+		"counterparty=0xOTHER":              3,
+		"hash=0x3":                          1,
+	} {
+		rec := get(t, s, "/addresses/0xwallet/transactions?"+target, "")
+		assert.Equal(t, http.StatusOK, rec.Code, target)
+		var page Page
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+		assert.Equal(t, want, page.Total, target)
+	}
+
+	for _, target := range []string{"from_block=x", "since=yesterday", "limit=0", "limit=5000", "offset=-1"} {
+		rec := get(t, s, "/addresses/0xwallet/transactions?"+target, "")
+		assert.Equal(t, http.StatusBadRequest, rec.Code, target)
+		assert.Contains(t, rec.Body.String(), `"error"`)
+	}
+}
+
+func TestTransactions_Formats(t *testing.T) {
+	s := testStore(t)
+
+	rec := get(t, s, "/addresses/0xwallet/transactions?columns=hash,block", "text/csv")
+	assert.Equal(t, "text/csv", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "Transaction Hash,Block Number\n0x1,10\n0x2,20\n0x3,30\n", rec.Body.String())
+
+	rec = get(t, s, "/addresses/0xwallet/transactions", "application/x-ndjson")
+	assert.Equal(t, "application/x-ndjson", rec.Header().Get("Content-Type"))
+	assert.Len(t, strings.Split(strings.TrimSpace(rec.Body.String()), "\n"), 3)
+
+	// The format parameter wins over the Accept header
+	rec = get(t, s, "/addresses/0xwallet/transactions?format=csv", "application/json")
+	assert.Equal(t, "text/csv", rec.Header().Get("Content-Type"))
+
+	rec = get(t, s, "/addresses/0xwallet/transactions", "text/html, */*;q=0.8")
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	rec = get(t, s, "/addresses/0xwallet/transactions", "application/xml")
+	assert.Equal(t, http.StatusNotAcceptable, rec.Code)
+}
+
+func TestTransactions_UnknownAddress(t *testing.T) {
+	rec := get(t, testStore(t), "/addresses/0xunknown/transactions", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = httptest.NewRecorder()
+	New(nil).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/addresses/0xwallet/transactions", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/store"
+)
+
+// StoreFile returns an opener of the store at path for Server.Store. The
+// store is loaded once and loaded again only after the file changes, so
+// rows recorded by sync runs show up without restarting the server.
+func StoreFile(path string) func() (*store.Store, error) {
+	var (
+		mu      sync.Mutex
+		current *store.Store
+		modTime time.Time
+		size    int64
+	)
+	return func() (*store.Store, error) {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read store: %w", err)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if current != nil && info.ModTime().Equal(modTime) && info.Size() == size {
+			return current, nil
+		}
+		loaded, err := store.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		current, modTime, size = loaded, info.ModTime(), info.Size()
+		return current, nil
+	}
+}
+
+// UpdateFile returns an updater of the store at path for Server.Update.
+// Each change is applied to a freshly loaded store under the store's lock,
+// so rows recorded by sync runs since the last read are kept, and changes
+// are applied one at a time.
+func UpdateFile(path string) func(change func(*store.Store) error) error {
+	return func(change func(*store.Store) error) error {
+		return store.Update(path, change)
+	}
+}
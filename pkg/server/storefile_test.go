@@ -0,0 +1,39 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/haridev22/ct-assignement/pkg/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStoreFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+	open := StoreFile(path)
+	_, err := open()
+	assert.Error(t, err)
+
+	s, _ := store.Open(path)
+	s.Upsert(s.BeginRun(testAddress), []models.Transaction{{Hash: "0x1", Type: models.TypeEthTransfer}})
+	assert.NoError(t, s.Save())
+
+	first, err := open()
+	assert.NoError(t, err)
+	again, _ := open()
+	assert.Same(t, first, again)
+	assert.Len(t, first.Latest(testAddress), 1)
+
+	// A sync rewriting the file is picked up
+	s.Upsert(s.BeginRun(testAddress), []models.Transaction{{Hash: "0x1", Type: models.TypeEthTransfer}, {Hash: "0x2", Type: models.TypeEthTransfer}})
+	assert.NoError(t, s.Save())
+	later := time.Now().Add(time.Second)
+	assert.NoError(t, os.Chtimes(path, later, later))
+	reloaded, err := open()
+	assert.NoError(t, err)
+	assert.NotSame(t, first, reloaded)
+	assert.Len(t, reloaded.Latest(testAddress), 2)
+}
@@ -0,0 +1,195 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/haridev22/ct-assignement/pkg/alert"
+	"github.com/haridev22/ct-assignement/pkg/store"
+)
+
+const (
+	// TenantHeader selects the tenant of a request when Server.Tenant is nil
+	TenantHeader = "X-Tenant"
+	// DefaultTenant is the tenant of requests that name none
+	DefaultTenant = "default"
+	// maxWatchlistBody bounds the size of a watchlist in a request
+	maxWatchlistBody = 1 << 20
+)
+
+// watchlistRoutes registers the watchlist endpoints
+func (s *Server) watchlistRoutes() {
+	s.mux.HandleFunc("GET /watchlists", s.handleListWatchlists)
+	s.mux.HandleFunc("POST /watchlists", s.handleCreateWatchlist)
+	s.mux.HandleFunc("GET /watchlists/{id}", s.handleGetWatchlist)
+	s.mux.HandleFunc("PUT /watchlists/{id}", s.handleUpdateWatchlist)
+	s.mux.HandleFunc("DELETE /watchlists/{id}", s.handleDeleteWatchlist)
+}
+
+func (s *Server) handleListWatchlists(w http.ResponseWriter, r *http.Request) {
+	st, ok := s.openStore(w)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string][]store.Watchlist{"watchlists": st.Watchlists(s.tenant(r))})
+}
+
+func (s *Server) handleGetWatchlist(w http.ResponseWriter, r *http.Request) {
+	id, ok := watchlistID(w, r)
+	if !ok {
+		return
+	}
+	st, ok := s.openStore(w)
+	if !ok {
+		return
+	}
+	watchlist, err := st.Watchlist(s.tenant(r), id)
+	if err != nil {
+		s.writeWatchlistError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, watchlist)
+}
+
+func (s *Server) handleCreateWatchlist(w http.ResponseWriter, r *http.Request) {
+	watchlist, ok := decodeWatchlist(w, r)
+	if !ok {
+		return
+	}
+	watchlist.Tenant = s.tenant(r)
+	err := s.update(func(st *store.Store) (err error) {
+		watchlist, err = st.CreateWatchlist(watchlist)
+		return err
+	})
+	if err != nil {
+		s.writeWatchlistError(w, err)
+		return
+	}
+	w.Header().Set("Location", fmt.Sprintf("/watchlists/%d", watchlist.ID))
+	writeJSON(w, http.StatusCreated, watchlist)
+}
+
+func (s *Server) handleUpdateWatchlist(w http.ResponseWriter, r *http.Request) {
+	id, ok := watchlistID(w, r)
+	if !ok {
+		return
+	}
+	watchlist, ok := decodeWatchlist(w, r)
+	if !ok {
+		return
+	}
+	watchlist.ID, watchlist.Tenant = id, s.tenant(r)
+	err := s.update(func(st *store.Store) (err error) {
+		watchlist, err = st.UpdateWatchlist(watchlist)
+		return err
+	})
+	if err != nil {
+		s.writeWatchlistError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, watchlist)
+}
+
+func (s *Server) handleDeleteWatchlist(w http.ResponseWriter, r *http.Request) {
+	id, ok := watchlistID(w, r)
+	if !ok {
+		return
+	}
+	err := s.update(func(st *store.Store) error {
+		return st.DeleteWatchlist(s.tenant(r), id)
+	})
+	if err != nil {
+		s.writeWatchlistError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// errReadOnly is returned for changes to a server without Update
+var errReadOnly = errors.New("this server is read-only")
+
+// update applies change through s.Update
+func (s *Server) update(change func(*store.Store) error) error {
+	if s.Update == nil {
+		return errReadOnly
+	}
+	return s.Update(change)
+}
+
+// openStore returns the store to read, answering with an error when it
+// cannot be opened
+func (s *Server) openStore(w http.ResponseWriter) (*store.Store, bool) {
+	st, err := s.Store()
+	if err != nil {
+		s.logger().Error("failed to open store", "error", err)
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("store unavailable"))
+		return nil, false
+	}
+	return st, true
+}
+
+// tenant returns the tenant of r
+func (s *Server) tenant(r *http.Request) string {
+	if s.Tenant != nil {
+		return s.Tenant(r)
+	}
+	if tenant := r.Header.Get(TenantHeader); tenant != "" {
+		return tenant
+	}
+	return DefaultTenant
+}
+
+// writeWatchlistError answers with the status matching a watchlist error
+func (s *Server) writeWatchlistError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, store.ErrWatchlistNotFound):
+		writeError(w, http.StatusNotFound, err)
+	case errors.Is(err, store.ErrWatchlistExists):
+		writeError(w, http.StatusConflict, err)
+	case errors.Is(err, store.ErrInvalidWatchlist):
+		writeError(w, http.StatusBadRequest, err)
+	case errors.Is(err, errReadOnly):
+		writeError(w, http.StatusMethodNotAllowed, err)
+	default:
+		s.logger().Error("failed to update store", "error", err)
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("store unavailable"))
+	}
+}
+
+func watchlistID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id < 1 {
+		writeError(w, http.StatusNotFound, store.ErrWatchlistNotFound)
+		return 0, false
+	}
+	return id, true
+}
+
+// decodeWatchlist reads the watchlist in the body of r. The name,
+// addresses and rules are taken from it; the ID, tenant and times are not.
+// Unknown fields are errors, as in rules files.
+func decodeWatchlist(w http.ResponseWriter, r *http.Request) (store.Watchlist, bool) {
+	var body struct {
+		Name      string                 `json:"name"`
+		Addresses []store.WatchedAddress `json:"addresses"`
+		Rules     alert.Rules            `json:"rules"`
+	}
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxWatchlistBody))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid watchlist: %w", err))
+		return store.Watchlist{}, false
+	}
+	watchlist := store.Watchlist{Name: body.Name, Addresses: body.Addresses, Rules: body.Rules}
+	return watchlist, true
+}
+
+// writeJSON answers with v as JSON
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", mediaTypes[FormatJSON])
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
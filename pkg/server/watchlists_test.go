@@ -0,0 +1,96 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/haridev22/ct-assignement/pkg/store"
+	"github.com/stretchr/testify/assert"
+)
+
+// watchlistServer serves a store file with watchlist changes enabled
+func watchlistServer(t *testing.T) *Server {
+	path := filepath.Join(t.TempDir(), "store.json")
+	srv := New(StoreFile(path))
+	srv.Update = UpdateFile(path)
+	return srv
+}
+
+func do(srv *Server, method, target, tenant, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if tenant != "" {
+		req.Header.Set(TenantHeader, tenant)
+	}
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestWatchlists_CRUD(t *testing.T) {
+	srv := watchlistServer(t)
+
+	rec := do(srv, http.MethodPost, "/watchlists", "acme", `{
+		"name": "treasury",
+		"addresses": [{"address": "0x1111111111111111111111111111111111111111", "label": "cold", "chains": ["ethereum"]}],
+		"rules": [{"name": "large", "value_above": "100"}]
+	}`)
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	assert.Equal(t, "/watchlists/1", rec.Header().Get("Location"))
+	var created store.Watchlist
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, "acme", created.Tenant)
+
+	rec = do(srv, http.MethodGet, "/watchlists/1", "acme", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = do(srv, http.MethodGet, "/watchlists/1", "", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code, "other tenants cannot see the watchlist")
+	rec = do(srv, http.MethodGet, "/watchlists", "", "")
+	assert.JSONEq(t, `{"watchlists": []}`, rec.Body.String())
+
+	rec = do(srv, http.MethodPut, "/watchlists/1", "acme", `{"name": "treasury", "addresses": []}`)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var list struct{ Watchlists []store.Watchlist }
+	rec = do(srv, http.MethodGet, "/watchlists", "acme", "")
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	if assert.Len(t, list.Watchlists, 1) {
+		assert.Empty(t, list.Watchlists[0].Addresses)
+		assert.Empty(t, list.Watchlists[0].Rules)
+	}
+
+	rec = do(srv, http.MethodDelete, "/watchlists/1", "acme", "")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = do(srv, http.MethodDelete, "/watchlists/1", "acme", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestWatchlists_Errors(t *testing.T) {
+	srv := watchlistServer(t)
+	assert.Equal(t, http.StatusCreated, do(srv, http.MethodPost, "/watchlists", "", `{"name": "ops"}`).Code)
+
+	tests := []struct {
+		method, target, body string
+		status               int
+	}{
+		{http.MethodPost, "/watchlists", `{"name": "ops"}`, http.StatusConflict},
+		{http.MethodPost, "/watchlists", `{"name": "x", "addresses": [{"address": "nope"}]}`, http.StatusBadRequest},
+		{http.MethodPost, "/watchlists", `{"name": "x", "rules": [{"name": "r", "valu_above": "1"}]}`, http.StatusBadRequest},
+		{http.MethodPost, "/watchlists", `not json`, http.StatusBadRequest},
+		{http.MethodPut, "/watchlists/99", `{"name": "x"}`, http.StatusNotFound},
+		{http.MethodGet, "/watchlists/abc", ``, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		rec := do(srv, tt.method, tt.target, "", tt.body)
+		assert.Equal(t, tt.status, rec.Code, "%s %s %s", tt.method, tt.target, tt.body)
+	}
+}
+
+func TestWatchlists_ReadOnly(t *testing.T) {
+	srv := New(StoreFile(filepath.Join(t.TempDir(), "store.json")))
+
+	rec := do(srv, http.MethodPost, "/watchlists", "", `{"name": "ops"}`)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
@@ -0,0 +1,115 @@
+// Package skipped records the provider records an export leaves out because
+// they could not be converted, in a JSON Lines sidecar next to the export,
+// so users can audit exactly what was dropped.
+package skipped
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Entry is a single skipped record
+type Entry struct {
+	Time    time.Time `json:"time"`
+	Address string    `json:"address"`
+	// Type is the transaction type the record was fetched as, such as
+	// "normal" or "erc20"
+	Type string `json:"type"`
+	Hash string `json:"hash,omitempty"`
+	// Reason is the error that made the record unusable
+	Reason string `json:"reason"`
+	// Record is the record as the provider returned it
+	Record json.RawMessage `json:"record"`
+}
+
+// Log appends entries to a JSONL file, which is only created once the first
+// record is skipped. A nil *Log discards entries, so callers can call Record
+// unconditionally.
+type Log struct {
+	mu      sync.Mutex
+	path    string
+	address string
+	file    *os.File
+	count   int
+}
+
+// New returns a log of the records of address skipped into path. Unless
+// appending, a file left at path by an earlier run is removed, so the
+// sidecar only ever describes the export next to it.
+func New(path, address string, appending bool) (*Log, error) {
+	if !appending {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to remove old error report: %w", err)
+		}
+	}
+	return &Log{path: path, address: address}, nil
+}
+
+// Path returns the file the log appends to
+func (l *Log) Path() string {
+	if l == nil {
+		return ""
+	}
+	return l.path
+}
+
+// Count returns the number of records skipped so far
+func (l *Log) Count() int {
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.count
+}
+
+// Record appends record of the given type, skipped because of reason
+func (l *Log) Record(kind, hash string, record interface{}, reason error) error {
+	if l == nil {
+		return nil
+	}
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	line, err := json.Marshal(Entry{
+		Time:    time.Now().UTC(),
+		Address: l.address,
+		Type:    kind,
+		Hash:    hash,
+		Reason:  reason.Error(),
+		Record:  raw,
+	})
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+			return fmt.Errorf("failed to create error report directory: %w", err)
+		}
+		if l.file, err = os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644); err != nil {
+			return fmt.Errorf("failed to open error report: %w", err)
+		}
+	}
+	if _, err := l.file.Write(line); err != nil {
+		return err
+	}
+	l.count++
+	return nil
+}
+
+// Close closes the underlying file, if one was created
+func (l *Log) Close() error {
+	if l == nil || l.file == nil {
+		return nil
+	}
+	return l.file.Close()
+}
@@ -0,0 +1,67 @@
+package skipped
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLog_Record(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out", "0xabc_errors.jsonl")
+
+	log, err := New(path, "0xabc", false)
+	assert.NoError(t, err)
+	assert.NoError(t, log.Close())
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err), "nothing skipped, no file")
+
+	log, err = New(path, "0xabc", false)
+	assert.NoError(t, err)
+	record := map[string]string{"hash": "0x1", "value": "not-a-number"}
+	assert.NoError(t, log.Record("normal", "0x1", record, errors.New("invalid value")))
+	assert.NoError(t, log.Record("erc20", "0x2", record, errors.New("invalid decimals")))
+	assert.Equal(t, 2, log.Count())
+	assert.NoError(t, log.Close())
+
+	file, err := os.Open(path)
+	assert.NoError(t, err)
+	defer file.Close()
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var e Entry
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &e))
+		entries = append(entries, e)
+	}
+	if assert.Len(t, entries, 2) {
+		assert.Equal(t, "0xabc", entries[0].Address)
+		assert.Equal(t, "normal", entries[0].Type)
+		assert.Equal(t, "invalid value", entries[0].Reason)
+		assert.JSONEq(t, `{"hash":"0x1","value":"not-a-number"}`, string(entries[0].Record))
+	}
+}
+
+func TestNew_RemovesOldReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "0xabc_errors.jsonl")
+	assert.NoError(t, os.WriteFile(path, []byte("{}\n"), 0644))
+
+	_, err := New(path, "0xabc", true)
+	assert.NoError(t, err)
+	assert.FileExists(t, path, "a resumed export keeps the records skipped before")
+
+	_, err = New(path, "0xabc", false)
+	assert.NoError(t, err)
+	assert.NoFileExists(t, path)
+}
+
+func TestNilLog(t *testing.T) {
+	var log *Log
+	assert.NoError(t, log.Record("normal", "", nil, errors.New("x")))
+	assert.Equal(t, 0, log.Count())
+	assert.NoError(t, log.Close())
+}
@@ -0,0 +1,32 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Update opens the store at path, applies change and saves the store,
+// holding an exclusive lock on it throughout. Processes updating the same
+// store, such as a sync, the watcher and the server, take turns, so none
+// of them overwrites the runs another recorded. Nothing is saved when
+// change fails.
+func Update(path string, change func(*Store) error) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create store directory: %w", err)
+	}
+	unlock, err := lock(path + ".lock")
+	if err != nil {
+		return fmt.Errorf("failed to lock store: %w", err)
+	}
+	defer unlock()
+
+	s, err := Open(path)
+	if err != nil {
+		return err
+	}
+	if err := change(s); err != nil {
+		return err
+	}
+	return s.Save()
+}
@@ -0,0 +1,57 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdate_KeepsConcurrentRuns(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "store.json")
+
+	var wg sync.WaitGroup
+	for i := 1; i <= 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := Update(path, func(s *Store) error {
+				run := s.BeginRun(testAddress)
+				_, err := s.Upsert(run, []models.Transaction{testTx(fmt.Sprintf("0x%d", i), int64(i), "1.0")})
+				return err
+			})
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	s, err := Open(path)
+	assert.NoError(t, err)
+	assert.Len(t, s.Runs(testAddress), 20, "no update overwrites another")
+	assert.Len(t, s.Latest(testAddress), 20)
+
+	entries, _ := os.ReadDir(dir)
+	for _, entry := range entries {
+		assert.Contains(t, []string{"store.json", "store.json.lock"}, entry.Name(), "no temporary file is left behind")
+	}
+}
+
+func TestUpdate_ChangeFails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+	failed := errors.New("failed")
+
+	err := Update(path, func(s *Store) error {
+		s.BeginRun(testAddress)
+		return failed
+	})
+
+	assert.ErrorIs(t, err, failed)
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err), "nothing is saved")
+}
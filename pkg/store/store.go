@@ -0,0 +1,410 @@
+// Package store keeps a versioned local history of exported rows.
+//
+// Rows are never overwritten. When a later run produces a different
+// version of a row (a corrected fee, a better label) the previous version
+// is marked as superseded and kept, and rows that disappear from the
+// provider are soft-deleted. This lets exports select either the latest
+// state or the state as of any earlier run.
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+)
+
+// Run records a single sync into the store
+type Run struct {
+	ID        int64     `json:"id"`
+	Address   string    `json:"address"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+// Row is one version of a logical row
+type Row struct {
+	ID           int64              `json:"id"`
+	Key          string             `json:"key"`
+	Address      string             `json:"address"`
+	RunID        int64              `json:"run_id"`
+	RecordedAt   time.Time          `json:"recorded_at"`
+	SupersededBy int64              `json:"superseded_by,omitempty"`
+	Deleted      bool               `json:"deleted,omitempty"`
+	Transaction  models.Transaction `json:"transaction"`
+}
+
+// Current reports whether the row is the latest version of its key
+func (r *Row) Current() bool {
+	return r.SupersededBy == 0
+}
+
+// Stats counts what a sync changed
+type Stats struct {
+	Inserted   int
+	Superseded int
+	Deleted    int
+	Unchanged  int
+}
+
+type data struct {
+	NextRunID       int64       `json:"next_run_id"`
+	NextRowID       int64       `json:"next_row_id"`
+	NextWatchlistID int64       `json:"next_watchlist_id,omitempty"`
+	Runs            []Run       `json:"runs"`
+	Rows            []Row       `json:"rows"`
+	Watchlists      []Watchlist `json:"watchlists,omitempty"`
+}
+
+// Store is a file-backed versioned row store. It is safe for concurrent use.
+type Store struct {
+	mu   sync.Mutex
+	path string
+	data data
+	now  func() time.Time
+
+	// current maps address|key to the index of the current row version
+	current map[string]int
+}
+
+// Open loads the store at path, creating an empty one if the file does not exist
+func Open(path string) (*Store, error) {
+	s := &Store{
+		path: path,
+		data: data{NextRunID: 1, NextRowID: 1},
+		now:  time.Now,
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read store: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(content, &s.data); err != nil {
+			return nil, fmt.Errorf("failed to parse store %s: %w", path, err)
+		}
+	}
+
+	s.reindex()
+	return s, nil
+}
+
+// Save writes the store back to disk atomically. Use Update to change a
+// store other processes may change too.
+func (s *Store) Save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	content, err := json.Marshal(s.data)
+	if err != nil {
+		return fmt.Errorf("failed to encode store: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create store directory: %w", err)
+	}
+
+	// A temporary file of its own keeps processes saving the same store
+	// from writing into each other's file
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), "."+filepath.Base(s.path)+"-*")
+	if err != nil {
+		return fmt.Errorf("failed to write store: %w", err)
+	}
+	_, err = tmp.Write(content)
+	if err == nil {
+		// Temporary files are only readable by their owner
+		err = tmp.Chmod(0644)
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), s.path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to replace store: %w", err)
+	}
+	return nil
+}
+
+// BeginRun registers a new run for address
+func (s *Store) BeginRun(address string) Run {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	run := Run{
+		ID:        s.data.NextRunID,
+		Address:   normalizeAddress(address),
+		StartedAt: s.now().UTC(),
+	}
+	s.data.NextRunID++
+	s.data.Runs = append(s.data.Runs, run)
+	return run
+}
+
+// Runs returns all runs recorded for address, oldest first
+func (s *Store) Runs(address string) []Run {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	address = normalizeAddress(address)
+	var runs []Run
+	for _, run := range s.data.Runs {
+		if run.Address == address {
+			runs = append(runs, run)
+		}
+	}
+	return runs
+}
+
+// Upsert records transactions fetched by run. New rows are inserted,
+// changed rows supersede their previous version and identical rows are
+// left untouched. Rows are identified by models.RowIDs, so transactions
+// must hold every row of the transactions they cover.
+func (s *Store) Upsert(run Run, transactions []models.Transaction) (Stats, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var stats Stats
+	for i, key := range models.RowIDs(transactions) {
+		changed, err := s.put(run, key, transactions[i], false)
+		if err != nil {
+			return stats, err
+		}
+		switch changed {
+		case putInserted:
+			stats.Inserted++
+		case putSuperseded:
+			stats.Superseded++
+		default:
+			stats.Unchanged++
+		}
+	}
+	return stats, nil
+}
+
+// Sync upserts transactions and soft-deletes current rows for the run's
+// address within [startBlock, endBlock] that the provider no longer
+// returns. It must only be called with a complete result set for the range.
+func (s *Store) Sync(run Run, startBlock, endBlock int64, transactions []models.Transaction) (Stats, error) {
+	stats, err := s.Upsert(run, transactions)
+	if err != nil {
+		return stats, err
+	}
+
+	seen := make(map[string]bool, len(transactions))
+	for _, key := range models.RowIDs(transactions) {
+		seen[key] = true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, idx := range s.sortedCurrent(run.Address) {
+		row := s.data.Rows[idx]
+		if row.Deleted || seen[row.Key] {
+			continue
+		}
+		if row.Transaction.BlockNumber < startBlock || row.Transaction.BlockNumber > endBlock {
+			continue
+		}
+		if _, err := s.put(run, row.Key, row.Transaction, true); err != nil {
+			return stats, err
+		}
+		stats.Deleted++
+	}
+	return stats, nil
+}
+
+// Delete soft-deletes the current version of the row identified by key
+func (s *Store) Delete(run Run, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	idx, ok := s.current[indexKey(run.Address, key)]
+	if !ok || s.data.Rows[idx].Deleted {
+		return fmt.Errorf("row %s not found", key)
+	}
+	_, err := s.put(run, key, s.data.Rows[idx].Transaction, true)
+	return err
+}
+
+// Latest returns the current, non-deleted transactions for address in
+// chronological order
+func (s *Store) Latest(address string) []models.Transaction {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result []models.Transaction
+	for _, idx := range s.sortedCurrent(normalizeAddress(address)) {
+		if !s.data.Rows[idx].Deleted {
+			result = append(result, s.data.Rows[idx].Transaction)
+		}
+	}
+	return result
+}
+
+// AsOfRun returns the transactions for address exactly as they were after
+// the given run completed
+func (s *Store) AsOfRun(address string, runID int64) []models.Transaction {
+	return s.asOf(normalizeAddress(address), func(row *Row) bool {
+		return row.RunID <= runID
+	})
+}
+
+// AsOfTime returns the transactions for address as they were known at t,
+// ignoring any version recorded after it
+func (s *Store) AsOfTime(address string, t time.Time) []models.Transaction {
+	return s.asOf(normalizeAddress(address), func(row *Row) bool {
+		return !row.RecordedAt.After(t)
+	})
+}
+
+// History returns every stored version of a row, oldest first
+func (s *Store) History(address, key string) []Row {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	address = normalizeAddress(address)
+	var rows []Row
+	for _, row := range s.data.Rows {
+		if row.Address == address && row.Key == key {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// asOf selects, per key, the newest version accepted by visible
+func (s *Store) asOf(address string, visible func(row *Row) bool) []models.Transaction {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	newest := make(map[string]int)
+	for i := range s.data.Rows {
+		row := &s.data.Rows[i]
+		if row.Address != address || !visible(row) {
+			continue
+		}
+		if prev, ok := newest[row.Key]; !ok || s.data.Rows[prev].ID < row.ID {
+			newest[row.Key] = i
+		}
+	}
+
+	var result []models.Transaction
+	for _, idx := range newest {
+		if !s.data.Rows[idx].Deleted {
+			result = append(result, s.data.Rows[idx].Transaction)
+		}
+	}
+	sortTransactions(result)
+	return result
+}
+
+type putResult int
+
+const (
+	putUnchanged putResult = iota
+	putInserted
+	putSuperseded
+)
+
+// put stores a new version of tx, the row identified by key, if it differs
+// from the current one. The caller must hold s.mu.
+func (s *Store) put(run Run, key string, tx models.Transaction, deleted bool) (putResult, error) {
+	ik := indexKey(run.Address, key)
+
+	prevIdx, exists := s.current[ik]
+	if exists {
+		prev := &s.data.Rows[prevIdx]
+		same, err := sameTransaction(prev.Transaction, tx)
+		if err != nil {
+			return putUnchanged, err
+		}
+		if same && prev.Deleted == deleted {
+			return putUnchanged, nil
+		}
+	}
+
+	row := Row{
+		ID:          s.data.NextRowID,
+		Key:         key,
+		Address:     run.Address,
+		RunID:       run.ID,
+		RecordedAt:  s.now().UTC(),
+		Deleted:     deleted,
+		Transaction: tx,
+	}
+	s.data.NextRowID++
+	s.data.Rows = append(s.data.Rows, row)
+	s.current[ik] = len(s.data.Rows) - 1
+
+	if !exists {
+		return putInserted, nil
+	}
+	s.data.Rows[prevIdx].SupersededBy = row.ID
+	return putSuperseded, nil
+}
+
+// sortedCurrent returns the indexes of the current rows for address in
+// chronological order. The caller must hold s.mu.
+func (s *Store) sortedCurrent(address string) []int {
+	var idxs []int
+	for i := range s.data.Rows {
+		if s.data.Rows[i].Address == address && s.data.Rows[i].Current() {
+			idxs = append(idxs, i)
+		}
+	}
+	sort.SliceStable(idxs, func(a, b int) bool {
+		return lessTransaction(&s.data.Rows[idxs[a]].Transaction, &s.data.Rows[idxs[b]].Transaction)
+	})
+	return idxs
+}
+
+func (s *Store) reindex() {
+	s.current = make(map[string]int)
+	for i := range s.data.Rows {
+		if s.data.Rows[i].Current() {
+			s.current[indexKey(s.data.Rows[i].Address, s.data.Rows[i].Key)] = i
+		}
+	}
+}
+
+func sameTransaction(a, b models.Transaction) (bool, error) {
+	ab, err := json.Marshal(a)
+	if err != nil {
+		return false, err
+	}
+	bb, err := json.Marshal(b)
+	if err != nil {
+		return false, err
+	}
+	return string(ab) == string(bb), nil
+}
+
+func sortTransactions(transactions []models.Transaction) {
+	sort.SliceStable(transactions, func(i, j int) bool {
+		return lessTransaction(&transactions[i], &transactions[j])
+	})
+}
+
+func lessTransaction(a, b *models.Transaction) bool {
+	if a.BlockNumber != b.BlockNumber {
+		return a.BlockNumber < b.BlockNumber
+	}
+	return a.Hash < b.Hash
+}
+
+func indexKey(address, key string) string {
+	return address + "|" + key
+}
+
+func normalizeAddress(address string) string {
+	return strings.ToLower(address)
+}
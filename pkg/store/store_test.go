@@ -0,0 +1,159 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+const testAddress = "0xWallet"
+
+func testTx(hash string, block int64, value string) models.Transaction {
+	return models.Transaction{
+		Hash:        hash,
+		BlockNumber: block,
+		Timestamp:   time.Unix(1630000000+block, 0).UTC(),
+		From:        "0xsender",
+		To:          "0xwallet",
+		Type:        models.TypeEthTransfer,
+		Value:       value,
+		GasFee:      "0.000420000000000000",
+	}
+}
+
+func values(transactions []models.Transaction) []string {
+	var result []string
+	for _, tx := range transactions {
+		result = append(result, tx.Hash+"="+tx.Value)
+	}
+	return result
+}
+
+func TestStore_UpsertSupersedes(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "store.json"))
+	assert.NoError(t, err)
+
+	run1 := s.BeginRun(testAddress)
+	stats, err := s.Upsert(run1, []models.Transaction{testTx("0x1", 10, "1.0"), testTx("0x2", 20, "2.0")})
+	assert.NoError(t, err)
+	assert.Equal(t, Stats{Inserted: 2}, stats)
+
+	run2 := s.BeginRun(testAddress)
+	stats, err = s.Upsert(run2, []models.Transaction{testTx("0x1", 10, "1.0"), testTx("0x2", 20, "2.5")})
+	assert.NoError(t, err)
+	assert.Equal(t, Stats{Superseded: 1, Unchanged: 1}, stats)
+
+	assert.Equal(t, []string{"0x1=1.0", "0x2=2.5"}, values(s.Latest(testAddress)))
+	assert.Equal(t, []string{"0x1=1.0", "0x2=2.0"}, values(s.AsOfRun(testAddress, run1.ID)))
+
+	tx := testTx("0x2", 20, "")
+	history := s.History(testAddress, tx.RowID())
+	assert.Len(t, history, 2)
+	assert.Equal(t, history[1].ID, history[0].SupersededBy)
+	assert.True(t, history[1].Current())
+}
+
+func TestStore_SamePartyTransfers(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "store.json"))
+	assert.NoError(t, err)
+
+	// Two USDC transfers between the same parties in one transaction
+	first := testTx("0x1", 10, "10")
+	first.Type = models.TypeERC20Transfer
+	first.AssetContractAddr = "0xusdc"
+	second := first
+	second.Value = "25"
+
+	run1 := s.BeginRun(testAddress)
+	stats, err := s.Sync(run1, 0, 100, []models.Transaction{first, second})
+	assert.NoError(t, err)
+	assert.Equal(t, Stats{Inserted: 2}, stats)
+	assert.ElementsMatch(t, []string{"0x1=10", "0x1=25"}, values(s.Latest(testAddress)))
+
+	run2 := s.BeginRun(testAddress)
+	stats, err = s.Sync(run2, 0, 100, []models.Transaction{first, second})
+	assert.NoError(t, err)
+	assert.Equal(t, Stats{Unchanged: 2}, stats)
+	assert.ElementsMatch(t, []string{"0x1=10", "0x1=25"}, values(s.Latest(testAddress)))
+}
+
+func TestStore_SyncSoftDeletes(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "store.json"))
+	assert.NoError(t, err)
+
+	run1 := s.BeginRun(testAddress)
+	_, err = s.Sync(run1, 0, 100, []models.Transaction{testTx("0x1", 10, "1.0"), testTx("0x2", 20, "2.0"), testTx("0x3", 200, "3.0")})
+	assert.NoError(t, err)
+
+	// 0x2 was reorged out; 0x3 is outside the synced range and must survive
+	run2 := s.BeginRun(testAddress)
+	stats, err := s.Sync(run2, 0, 100, []models.Transaction{testTx("0x1", 10, "1.0")})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, stats.Deleted)
+
+	assert.Equal(t, []string{"0x1=1.0", "0x3=3.0"}, values(s.Latest(testAddress)))
+	assert.Equal(t, []string{"0x1=1.0", "0x2=2.0", "0x3=3.0"}, values(s.AsOfRun(testAddress, run1.ID)))
+}
+
+func TestStore_Delete(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "store.json"))
+	assert.NoError(t, err)
+
+	run := s.BeginRun(testAddress)
+	tx := testTx("0x1", 10, "1.0")
+	_, err = s.Upsert(run, []models.Transaction{tx})
+	assert.NoError(t, err)
+
+	assert.NoError(t, s.Delete(run, tx.RowID()))
+	assert.Empty(t, s.Latest(testAddress))
+	assert.Error(t, s.Delete(run, tx.RowID()))
+}
+
+func TestStore_SaveAndReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "store.json")
+	s, err := Open(path)
+	assert.NoError(t, err)
+
+	run := s.BeginRun(testAddress)
+	_, err = s.Upsert(run, []models.Transaction{testTx("0x1", 10, "1.0")})
+	assert.NoError(t, err)
+	assert.NoError(t, s.Save())
+
+	reopened, err := Open(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0x1=1.0"}, values(reopened.Latest(testAddress)))
+	assert.Len(t, reopened.Runs(testAddress), 1)
+
+	// Identity survives the round trip, so re-syncing the same data is a no-op
+	run2 := reopened.BeginRun(testAddress)
+	stats, err := reopened.Upsert(run2, []models.Transaction{testTx("0x1", 10, "1.0")})
+	assert.NoError(t, err)
+	assert.Equal(t, Stats{Unchanged: 1}, stats)
+	assert.Equal(t, int64(2), run2.ID)
+}
+
+func TestStore_AsOfTime(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "store.json"))
+	assert.NoError(t, err)
+
+	now := time.Date(2024, 12, 30, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	run1 := s.BeginRun(testAddress)
+	_, err = s.Upsert(run1, []models.Transaction{testTx("0x1", 10, "1.0")})
+	assert.NoError(t, err)
+
+	// A correction made in January must not leak into the year-end view
+	now = time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC)
+	run2 := s.BeginRun(testAddress)
+	_, err = s.Upsert(run2, []models.Transaction{testTx("0x1", 10, "1.5"), testTx("0x2", 20, "2.0")})
+	assert.NoError(t, err)
+
+	yearEnd := time.Date(2024, 12, 31, 23, 59, 59, 0, time.UTC)
+	assert.Equal(t, []string{"0x1=1.0"}, values(s.AsOfTime(testAddress, yearEnd)))
+	assert.Equal(t, []string{"0x1=1.5", "0x2=2.0"}, values(s.AsOfTime(testAddress, now)))
+	assert.Empty(t, s.AsOfTime(testAddress, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)))
+}
@@ -0,0 +1,196 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/alert"
+	"github.com/haridev22/ct-assignement/pkg/chains"
+	"github.com/haridev22/ct-assignement/pkg/wallets"
+)
+
+// Errors of watchlist changes
+var (
+	ErrWatchlistNotFound = errors.New("watchlist not found")
+	ErrWatchlistExists   = errors.New("watchlist already exists")
+	ErrInvalidWatchlist  = errors.New("invalid watchlist")
+)
+
+// Watchlist is a named group of addresses that a tenant monitors together,
+// with the alert rules that apply to them
+type Watchlist struct {
+	ID int64 `json:"id"`
+	// Tenant owns the watchlist. Tenants only see their own watchlists.
+	Tenant    string           `json:"tenant"`
+	Name      string           `json:"name"`
+	Addresses []WatchedAddress `json:"addresses"`
+	Rules     alert.Rules      `json:"rules,omitempty"`
+	CreatedAt time.Time        `json:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+// WatchedAddress is an address of a watchlist
+type WatchedAddress struct {
+	Address string `json:"address"`
+	Label   string `json:"label,omitempty"`
+	// Chains are the networks to watch the address on, all when empty
+	Chains []string `json:"chains,omitempty"`
+}
+
+// OnChain reports whether the address is watched on the named chain
+func (a WatchedAddress) OnChain(chain string) bool {
+	if len(a.Chains) == 0 {
+		return true
+	}
+	for _, name := range a.Chains {
+		if strings.EqualFold(name, chain) {
+			return true
+		}
+	}
+	return false
+}
+
+// Watchlists returns the watchlists of tenant, by name
+func (s *Store) Watchlists(tenant string) []Watchlist {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := []Watchlist{}
+	for _, w := range s.data.Watchlists {
+		if w.Tenant == tenant {
+			result = append(result, w)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// Watchlist returns the watchlist of tenant with the given ID
+func (s *Store) Watchlist(tenant string, id int64) (Watchlist, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	idx := s.watchlistIndex(tenant, id)
+	if idx < 0 {
+		return Watchlist{}, ErrWatchlistNotFound
+	}
+	return s.data.Watchlists[idx], nil
+}
+
+// WatchlistByName returns the watchlist of tenant with the given name
+func (s *Store) WatchlistByName(tenant, name string) (Watchlist, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, w := range s.data.Watchlists {
+		if w.Tenant == tenant && w.Name == name {
+			return w, nil
+		}
+	}
+	return Watchlist{}, ErrWatchlistNotFound
+}
+
+// CreateWatchlist validates w and adds it with a new ID. The ID and times
+// of w are ignored.
+func (s *Store) CreateWatchlist(w Watchlist) (Watchlist, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkWatchlist(&w, 0); err != nil {
+		return Watchlist{}, err
+	}
+	if s.data.NextWatchlistID == 0 {
+		s.data.NextWatchlistID = 1
+	}
+	w.ID = s.data.NextWatchlistID
+	s.data.NextWatchlistID++
+	w.CreatedAt = s.now().UTC()
+	w.UpdatedAt = w.CreatedAt
+	s.data.Watchlists = append(s.data.Watchlists, w)
+	return w, nil
+}
+
+// UpdateWatchlist replaces the watchlist with the ID and tenant of w
+func (s *Store) UpdateWatchlist(w Watchlist) (Watchlist, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	idx := s.watchlistIndex(w.Tenant, w.ID)
+	if idx < 0 {
+		return Watchlist{}, ErrWatchlistNotFound
+	}
+	if err := s.checkWatchlist(&w, w.ID); err != nil {
+		return Watchlist{}, err
+	}
+	w.CreatedAt = s.data.Watchlists[idx].CreatedAt
+	w.UpdatedAt = s.now().UTC()
+	s.data.Watchlists[idx] = w
+	return w, nil
+}
+
+// DeleteWatchlist removes the watchlist of tenant with the given ID. Rows
+// recorded for its addresses are kept.
+func (s *Store) DeleteWatchlist(tenant string, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	idx := s.watchlistIndex(tenant, id)
+	if idx < 0 {
+		return ErrWatchlistNotFound
+	}
+	s.data.Watchlists = append(s.data.Watchlists[:idx], s.data.Watchlists[idx+1:]...)
+	return nil
+}
+
+func (s *Store) watchlistIndex(tenant string, id int64) int {
+	for i, w := range s.data.Watchlists {
+		if w.Tenant == tenant && w.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// checkWatchlist validates w and normalizes its addresses and chains. The
+// name must be unique within the tenant, except for the watchlist id.
+func (s *Store) checkWatchlist(w *Watchlist, id int64) error {
+	w.Name = strings.TrimSpace(w.Name)
+	if w.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidWatchlist)
+	}
+	for _, other := range s.data.Watchlists {
+		if other.Tenant == w.Tenant && other.Name == w.Name && other.ID != id {
+			return fmt.Errorf("%w: %q", ErrWatchlistExists, w.Name)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for i := range w.Addresses {
+		a := &w.Addresses[i]
+		a.Address = strings.TrimSpace(a.Address)
+		if !wallets.IsAddress(a.Address) {
+			return fmt.Errorf("%w: invalid address %q", ErrInvalidWatchlist, a.Address)
+		}
+		if seen[normalizeAddress(a.Address)] {
+			return fmt.Errorf("%w: address %s is listed twice", ErrInvalidWatchlist, a.Address)
+		}
+		seen[normalizeAddress(a.Address)] = true
+		for j, name := range a.Chains {
+			chain, err := chains.Lookup(name)
+			if err != nil {
+				return fmt.Errorf("%w: %v", ErrInvalidWatchlist, err)
+			}
+			a.Chains[j] = chain.Name
+		}
+	}
+	if err := w.Rules.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidWatchlist, err)
+	}
+	if w.Addresses == nil {
+		w.Addresses = []WatchedAddress{}
+	}
+	return nil
+}
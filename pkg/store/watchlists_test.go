@@ -0,0 +1,75 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/haridev22/ct-assignement/pkg/alert"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	treasury = "0x1111111111111111111111111111111111111111"
+	payroll  = "0x2222222222222222222222222222222222222222"
+)
+
+func TestStore_Watchlists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+	s, err := Open(path)
+	assert.NoError(t, err)
+
+	created, err := s.CreateWatchlist(Watchlist{
+		Tenant:    "acme",
+		Name:      " ops ",
+		Addresses: []WatchedAddress{{Address: treasury, Label: "treasury", Chains: []string{"Polygon"}}},
+		Rules:     alert.Rules{{Name: "large", ValueAbove: "100", Direction: "OUT"}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), created.ID)
+	assert.Equal(t, "ops", created.Name)
+	assert.Equal(t, []string{"polygon"}, created.Addresses[0].Chains)
+	assert.Equal(t, "out", created.Rules[0].Direction)
+	_, err = s.CreateWatchlist(Watchlist{Tenant: "other", Name: "ops"})
+	assert.NoError(t, err, "names are unique per tenant")
+	assert.NoError(t, s.Save())
+
+	reopened, err := Open(path)
+	assert.NoError(t, err)
+	assert.Len(t, reopened.Watchlists("acme"), 1)
+	assert.Empty(t, reopened.Watchlists("nobody"))
+	_, err = reopened.Watchlist("other", created.ID)
+	assert.ErrorIs(t, err, ErrWatchlistNotFound, "tenants only see their own watchlists")
+
+	created.Addresses = append(created.Addresses, WatchedAddress{Address: payroll})
+	updated, err := reopened.UpdateWatchlist(created)
+	assert.NoError(t, err)
+	assert.Len(t, updated.Addresses, 2)
+	assert.Equal(t, created.CreatedAt, updated.CreatedAt)
+	byName, err := reopened.WatchlistByName("acme", "ops")
+	assert.NoError(t, err)
+	assert.Equal(t, updated, byName)
+
+	assert.NoError(t, reopened.DeleteWatchlist("acme", created.ID))
+	assert.ErrorIs(t, reopened.DeleteWatchlist("acme", created.ID), ErrWatchlistNotFound)
+	assert.Empty(t, reopened.Watchlists("acme"))
+}
+
+func TestStore_CreateWatchlistInvalid(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "store.json"))
+	assert.NoError(t, err)
+	_, err = s.CreateWatchlist(Watchlist{Tenant: "acme", Name: "ops"})
+	assert.NoError(t, err)
+
+	tests := map[string]Watchlist{
+		"name is required":  {Tenant: "acme"},
+		"already exists":    {Tenant: "acme", Name: "ops"},
+		"invalid address":   {Tenant: "acme", Name: "a", Addresses: []WatchedAddress{{Address: "0x123"}}},
+		"listed twice":      {Tenant: "acme", Name: "a", Addresses: []WatchedAddress{{Address: treasury}, {Address: "0x1111111111111111111111111111111111111111"}}},
+		"unknown chain":     {Tenant: "acme", Name: "a", Addresses: []WatchedAddress{{Address: treasury, Chains: []string{"solana"}}}},
+		"invalid direction": {Tenant: "acme", Name: "a", Rules: alert.Rules{{Name: "r", Direction: "up"}}},
+	}
+	for want, w := range tests {
+		_, err := s.CreateWatchlist(w)
+		assert.ErrorContains(t, err, want)
+	}
+}
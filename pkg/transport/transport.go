@@ -0,0 +1,137 @@
+// Package transport builds the HTTP transport of provider clients from
+// proxy, header and TLS settings, for environments that can only reach the
+// internet through a proxy or a TLS-intercepting gateway.
+package transport
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Config describes how provider requests reach the network. The zero
+// Config leaves Go's default transport in place.
+type Config struct {
+	// Proxy is an http, https or socks5 proxy URL, credentials included.
+	// When empty the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment
+	// variables apply.
+	Proxy string
+	// Headers are set on every request
+	Headers http.Header
+	// CAFile is a PEM bundle of CA certificates to trust in addition to
+	// the system roots
+	CAFile string
+	// CertFile and KeyFile are a PEM client certificate and key for mutual TLS
+	CertFile string
+	KeyFile  string
+	// MinTLSVersion is 1.2 or 1.3. Empty keeps Go's default.
+	MinTLSVersion string
+	// InsecureSkipVerify disables server certificate verification
+	InsecureSkipVerify bool
+}
+
+// IsZero reports whether c changes nothing about the default transport
+func (c Config) IsZero() bool {
+	return c.Proxy == "" && len(c.Headers) == 0 && c.CAFile == "" && c.CertFile == "" &&
+		c.KeyFile == "" && c.MinTLSVersion == "" && !c.InsecureSkipVerify
+}
+
+// RoundTripper builds a transport from c
+func (c Config) RoundTripper() (http.RoundTripper, error) {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+
+	if c.Proxy != "" {
+		proxy, err := url.Parse(c.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		switch proxy.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return nil, fmt.Errorf("unsupported proxy scheme %q (use http, https or socks5)", proxy.Scheme)
+		}
+		t.Proxy = http.ProxyURL(proxy)
+	}
+
+	tlsConfig, err := c.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	t.TLSClientConfig = tlsConfig
+
+	if len(c.Headers) == 0 {
+		return t, nil
+	}
+	return &headerTransport{base: t, headers: c.Headers}, nil
+}
+
+// tlsConfig builds the TLS settings of c
+func (c Config) tlsConfig() (*tls.Config, error) {
+	config := &tls.Config{InsecureSkipVerify: c.InsecureSkipVerify}
+
+	switch c.MinTLSVersion {
+	case "":
+	case "1.2":
+		config.MinVersion = tls.VersionTLS12
+	case "1.3":
+		config.MinVersion = tls.VersionTLS13
+	default:
+		return nil, fmt.Errorf("unsupported minimum TLS version %q (use 1.2 or 1.3)", c.MinTLSVersion)
+	}
+
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", c.CAFile)
+		}
+		config.RootCAs = pool
+	}
+
+	if c.CertFile != "" || c.KeyFile != "" {
+		if c.CertFile == "" || c.KeyFile == "" {
+			return nil, fmt.Errorf("a client certificate needs both a certificate and a key file")
+		}
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
+
+// ParseHeader splits a "Name: value" header
+func ParseHeader(s string) (string, string, error) {
+	name, value, ok := strings.Cut(s, ":")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" {
+		return "", "", fmt.Errorf("invalid header %q (use \"Name: value\")", s)
+	}
+	return name, strings.TrimSpace(value), nil
+}
+
+// headerTransport sets fixed headers on every request
+type headerTransport struct {
+	base    http.RoundTripper
+	headers http.Header
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// A RoundTripper must not modify the caller's request
+	req = req.Clone(req.Context())
+	for name, values := range t.headers {
+		req.Header[name] = values
+	}
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,88 @@
+package transport
+
+import (
+	"crypto/tls"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.Header.Get("X-Gateway-Token"))
+	}))
+	defer server.Close()
+
+	rt, err := Config{Headers: http.Header{"X-Gateway-Token": {"secret"}}}.RoundTripper()
+	assert.NoError(t, err)
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	resp, err := (&http.Client{Transport: rt}).Do(req)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Empty(t, req.Header.Get("X-Gateway-Token"), "the caller's request is left unchanged")
+}
+
+func TestProxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+	}))
+	defer proxy.Close()
+
+	rt, err := Config{Proxy: proxy.URL}.RoundTripper()
+	assert.NoError(t, err)
+	resp, err := (&http.Client{Transport: rt}).Get("http://api.example.invalid/api?module=account")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "http://api.example.invalid/api?module=account", proxied)
+
+	_, err = Config{Proxy: "ftp://proxy:21"}.RoundTripper()
+	assert.Error(t, err)
+}
+
+func TestCAFile(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	assert.NoError(t, os.WriteFile(caFile, cert, 0o600))
+
+	// The test server's certificate is only trusted through the bundle
+	rt, err := Config{}.RoundTripper()
+	assert.NoError(t, err)
+	_, err = (&http.Client{Transport: rt}).Get(server.URL)
+	assert.Error(t, err)
+
+	rt, err = Config{CAFile: caFile, MinTLSVersion: "1.2"}.RoundTripper()
+	assert.NoError(t, err)
+	resp, err := (&http.Client{Transport: rt}).Get(server.URL)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, uint16(tls.VersionTLS12), rt.(*http.Transport).TLSClientConfig.MinVersion)
+}
+
+func TestInvalidTLSConfig(t *testing.T) {
+	_, err := Config{MinTLSVersion: "1.0"}.RoundTripper()
+	assert.Error(t, err)
+	_, err = Config{CertFile: "client.pem"}.RoundTripper()
+	assert.Error(t, err)
+	_, err = Config{CAFile: filepath.Join(t.TempDir(), "missing.pem")}.RoundTripper()
+	assert.Error(t, err)
+}
+
+func TestParseHeader(t *testing.T) {
+	name, value, err := ParseHeader("X-Api-Gateway:  abc:def ")
+	assert.NoError(t, err)
+	assert.Equal(t, "X-Api-Gateway", name)
+	assert.Equal(t, "abc:def", value)
+
+	_, _, err = ParseHeader("no colon")
+	assert.Error(t, err)
+	assert.True(t, Config{}.IsZero())
+}
@@ -0,0 +1,399 @@
+// Package tui is an interactive terminal browser for the rows of an
+// address. It filters the rows by type, token, counterparty or text, shows
+// every field of a row and exports the filtered rows.
+package tui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/haridev22/ct-assignement/pkg/filter"
+	"github.com/haridev22/ct-assignement/pkg/models"
+)
+
+// Filters narrow the rows a Browser lists. Empty fields keep every row.
+type Filters struct {
+	// Type is a transaction type, such as ERC20_TRANSFER
+	Type string
+	// Token is the symbol or contract address of an asset
+	Token        string
+	Counterparty string
+	// Text keeps rows whose hash, addresses or asset contain it
+	Text string
+}
+
+// funcs returns the filters as filter functions
+func (f Filters) funcs() []filter.Func {
+	var funcs []filter.Func
+	if f.Type != "" {
+		funcs = append(funcs, filter.Types(models.TransactionType(strings.ToUpper(f.Type))))
+	}
+	if f.Token != "" {
+		funcs = append(funcs, filter.Token(f.Token))
+	}
+	if f.Counterparty != "" {
+		funcs = append(funcs, filter.Counterparty(f.Counterparty))
+	}
+	if f.Text != "" {
+		needle := strings.ToLower(f.Text)
+		funcs = append(funcs, func(tx *models.Transaction) bool {
+			for _, s := range []string{tx.Hash, tx.From, tx.To, tx.AssetSymbol, tx.AssetContractAddr} {
+				if strings.Contains(strings.ToLower(s), needle) {
+					return true
+				}
+			}
+			return false
+		})
+	}
+	return funcs
+}
+
+// String describes the active filters, or returns none
+func (f Filters) String() string {
+	var parts []string
+	for _, p := range [][2]string{{"type", f.Type}, {"token", f.Token}, {"counterparty", f.Counterparty}, {"text", f.Text}} {
+		if p[1] != "" {
+			parts = append(parts, p[0]+"="+p[1])
+		}
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, " ")
+}
+
+// Browser is the state of the browser: the filtered rows, the selected
+// row and whether its details are open. It draws itself with Render and
+// changes with Handle, so it can be driven without a terminal.
+type Browser struct {
+	// ExportPath is the file the export prompt offers
+	ExportPath string
+	// Export writes rows to path and returns the file it wrote, which may
+	// differ from path. Without it rows cannot be exported.
+	Export func(path string, rows []models.Transaction) (string, error)
+
+	address       string
+	rows          []models.Transaction
+	view          []models.Transaction
+	filters       Filters
+	cursor, top   int
+	detail        bool
+	prompt        *prompt
+	message       string
+	width, height int
+}
+
+// prompt is a line of input the browser is reading
+type prompt struct {
+	label string
+	value []rune
+	apply func(b *Browser, value string)
+}
+
+// NewBrowser creates a browser of the rows of address, listing them all
+func NewBrowser(address string, rows []models.Transaction) *Browser {
+	return &Browser{address: address, rows: rows, view: rows, width: 80, height: 24}
+}
+
+// SetFilters lists the rows matching f, selecting the first one
+func (b *Browser) SetFilters(f Filters) {
+	b.filters = f
+	b.view = filter.Apply(b.rows, f.funcs()...)
+	b.cursor, b.top = 0, 0
+	b.detail = false
+}
+
+// Filters returns the active filters
+func (b *Browser) Filters() Filters {
+	return b.filters
+}
+
+// View returns the rows matching the filters
+func (b *Browser) View() []models.Transaction {
+	return b.view
+}
+
+// Selected returns the index of the selected row in View
+func (b *Browser) Selected() int {
+	return b.cursor
+}
+
+// Resize sets the size of the screen in characters
+func (b *Browser) Resize(width, height int) {
+	b.width, b.height = max(20, width), max(5, height)
+}
+
+// Handle applies a key press and reports whether to keep browsing
+func (b *Browser) Handle(k Key) bool {
+	if b.prompt != nil {
+		b.handlePrompt(k)
+		return true
+	}
+	b.message = ""
+	switch k.Code {
+	case KeyInterrupt:
+		return false
+	case KeyUp:
+		b.move(-1)
+	case KeyDown:
+		b.move(1)
+	case KeyPageUp:
+		b.move(-b.pageSize())
+	case KeyPageDown:
+		b.move(b.pageSize())
+	case KeyHome:
+		b.move(-len(b.view))
+	case KeyEnd:
+		b.move(len(b.view))
+	case KeyEnter:
+		b.detail = !b.detail && len(b.view) > 0
+	case KeyEscape, KeyBackspace:
+		b.detail = false
+	case KeyRune:
+		return b.handleRune(k.Rune)
+	}
+	return true
+}
+
+// handleRune applies the command of a character key
+func (b *Browser) handleRune(r rune) bool {
+	switch r {
+	case 'q':
+		return false
+	case 'k':
+		b.move(-1)
+	case 'j':
+		b.move(1)
+	case 'g':
+		b.move(-len(b.view))
+	case 'G':
+		b.move(len(b.view))
+	case 't':
+		b.ask("type", b.filters.Type, func(b *Browser, v string) {
+			f := b.filters
+			f.Type = v
+			b.SetFilters(f)
+		})
+	case 'a':
+		b.ask("token", b.filters.Token, func(b *Browser, v string) {
+			f := b.filters
+			f.Token = v
+			b.SetFilters(f)
+		})
+	case 'c':
+		b.ask("counterparty", b.filters.Counterparty, func(b *Browser, v string) {
+			f := b.filters
+			f.Counterparty = v
+			b.SetFilters(f)
+		})
+	case '/':
+		b.ask("search", b.filters.Text, func(b *Browser, v string) {
+			f := b.filters
+			f.Text = v
+			b.SetFilters(f)
+		})
+	case 'x':
+		b.SetFilters(Filters{})
+		b.message = "Filters cleared"
+	case 'e':
+		if b.Export == nil {
+			b.message = "Exporting is not available"
+			break
+		}
+		b.ask("export to", b.ExportPath, (*Browser).export)
+	}
+	return true
+}
+
+// ask starts reading a line of input, starting from value
+func (b *Browser) ask(label, value string, apply func(b *Browser, value string)) {
+	b.prompt = &prompt{label: label, value: []rune(value), apply: apply}
+}
+
+// handlePrompt edits the input line, applying it on enter
+func (b *Browser) handlePrompt(k Key) {
+	p := b.prompt
+	switch k.Code {
+	case KeyEnter:
+		b.prompt = nil
+		p.apply(b, strings.TrimSpace(string(p.value)))
+	case KeyEscape, KeyInterrupt:
+		b.prompt = nil
+	case KeyBackspace:
+		if len(p.value) > 0 {
+			p.value = p.value[:len(p.value)-1]
+		}
+	case KeyRune:
+		p.value = append(p.value, k.Rune)
+	}
+}
+
+// export writes the listed rows to path
+func (b *Browser) export(path string) {
+	if path == "" {
+		return
+	}
+	written, err := b.Export(path, b.view)
+	if err != nil {
+		b.message = "Export failed: " + err.Error()
+		return
+	}
+	b.ExportPath = path
+	b.message = fmt.Sprintf("Exported %d rows to %s", len(b.view), written)
+}
+
+// move moves the selection by delta rows, within the listed rows
+func (b *Browser) move(delta int) {
+	b.cursor = max(0, min(len(b.view)-1, b.cursor+delta))
+}
+
+// pageSize is the number of rows the list shows at once
+func (b *Browser) pageSize() int {
+	return max(1, b.height-3)
+}
+
+// ANSI escapes used to draw the screen
+const (
+	clearScreen = "\033[H\033[2J"
+	styleReset  = "\033[0m"
+	styleBold   = "\033[1m"
+	styleDim    = "\033[2m"
+	styleInvert = "\033[7m"
+)
+
+// Render draws the screen
+func (b *Browser) Render(w io.Writer) error {
+	_, err := io.WriteString(w, clearScreen+strings.Join(b.Lines(), "\r\n"))
+	return err
+}
+
+// Lines returns the lines of the screen: a title, the list or the details
+// of the selected row, and a line of help, input or messages
+func (b *Browser) Lines() []string {
+	title := fmt.Sprintf("%s  %d of %d rows  filters: %s", b.address, len(b.view), len(b.rows), b.filters)
+	lines := []string{styled(b.fit(title), styleBold)}
+	if b.detail {
+		lines = append(lines, b.detailLines()...)
+	} else {
+		lines = append(lines, b.listLines()...)
+	}
+	for len(lines) < b.height-1 {
+		lines = append(lines, "")
+	}
+	return append(lines[:b.height-1], b.footer())
+}
+
+// rowLayout aligns the columns of the list
+const rowLayout = "%-19s  %-16s  %-4s  %-13s  %22s  %s"
+
+// listLines returns the column header and the page of rows around the
+// selection
+func (b *Browser) listLines() []string {
+	lines := []string{styled(b.fit(fmt.Sprintf(rowLayout, "TIME", "TYPE", "DIR", "COUNTERPARTY", "VALUE", "ASSET")), styleBold)}
+	if len(b.view) == 0 {
+		return append(lines, "No rows match the filters (x clears them)")
+	}
+	visible := b.pageSize()
+	if b.cursor < b.top {
+		b.top = b.cursor
+	} else if b.cursor >= b.top+visible {
+		b.top = b.cursor - visible + 1
+	}
+	for i := b.top; i < len(b.view) && i < b.top+visible; i++ {
+		tx := &b.view[i]
+		direction, counterparty := b.direction(tx)
+		asset := tx.AssetSymbol
+		if asset == "" {
+			asset = "ETH"
+		}
+		if tx.Failed() {
+			asset += " (failed)"
+		}
+		value := tx.Value
+		if len(value) > 22 {
+			value = value[:21] + "…"
+		}
+		line := b.fit(fmt.Sprintf(rowLayout, tx.Timestamp.UTC().Format(time.DateTime), tx.Type, direction, shorten(counterparty), value, asset))
+		var style string
+		if tx.Failed() {
+			style += styleDim
+		}
+		if i == b.cursor {
+			style += styleInvert
+		}
+		lines = append(lines, styled(line, style))
+	}
+	return lines
+}
+
+// direction returns whether the address received (IN) or sent (OUT) tx,
+// with the other party
+func (b *Browser) direction(tx *models.Transaction) (string, string) {
+	switch {
+	case strings.EqualFold(tx.From, b.address) && strings.EqualFold(tx.To, b.address):
+		return "SELF", tx.To
+	case strings.EqualFold(tx.From, b.address):
+		return "OUT", tx.To
+	case strings.EqualFold(tx.To, b.address):
+		return "IN", tx.From
+	}
+	return "", tx.From
+}
+
+// detailLines returns every field of the selected row that has a value
+func (b *Browser) detailLines() []string {
+	tx := &b.view[b.cursor]
+	lines := []string{styled(b.fit(fmt.Sprintf("Row %d of %d", b.cursor+1, len(b.view))), styleBold)}
+	var columns []models.Column
+	width := 0
+	for _, col := range append(append([]models.Column{}, models.DefaultColumns...), models.OptionalColumns...) {
+		if col.Value != nil && col.Value(tx) != "" {
+			columns = append(columns, col)
+			width = max(width, len(col.Header))
+		}
+	}
+	for _, col := range columns {
+		lines = append(lines, b.fit(fmt.Sprintf("%-*s  %s", width, col.Header, col.Value(tx))))
+	}
+	return lines
+}
+
+// footer returns the input line, the last message or the keys to press
+func (b *Browser) footer() string {
+	switch {
+	case b.prompt != nil:
+		return b.fit(b.prompt.label + ": " + string(b.prompt.value) + "_")
+	case b.message != "":
+		return b.fit(b.message)
+	case b.detail:
+		return styled(b.fit("↑↓ previous/next  esc back  q quit"), styleDim)
+	}
+	return styled(b.fit("↑↓ move  enter details  t type  a token  c counterparty  / search  x clear  e export  q quit"), styleDim)
+}
+
+// fit cuts s to the width of the screen
+func (b *Browser) fit(s string) string {
+	if utf8.RuneCountInString(s) <= b.width {
+		return s
+	}
+	return string([]rune(s)[:b.width])
+}
+
+// styled wraps s in an ANSI style
+func styled(s, style string) string {
+	if style == "" {
+		return s
+	}
+	return style + s + styleReset
+}
+
+// shorten abbreviates a hex address to its first and last characters
+func shorten(s string) string {
+	if len(s) <= 14 {
+		return s
+	}
+	return s[:8] + "…" + s[len(s)-4:]
+}
@@ -0,0 +1,178 @@
+package tui
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	wallet = "0x1111111111111111111111111111111111111111"
+	other  = "0x2222222222222222222222222222222222222222"
+	usdc   = "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+)
+
+func browserRows() []models.Transaction {
+	at := time.Unix(1700000000, 0)
+	return []models.Transaction{
+		{Hash: "0xaa01", Timestamp: at, Type: models.TypeEthTransfer, From: wallet, To: other, Value: "1.5"},
+		{Hash: "0xaa02", Timestamp: at.Add(time.Hour), Type: models.TypeERC20Transfer, From: other, To: wallet, Value: "250", AssetSymbol: "USDC", AssetContractAddr: usdc},
+		{Hash: "0xaa03", Timestamp: at.Add(2 * time.Hour), Type: models.TypeERC20Transfer, From: wallet, To: "0x3333333333333333333333333333333333333333", Value: "10", AssetSymbol: "DAI", Status: models.StatusFailed},
+		{Hash: "0xaa04", Timestamp: at.Add(3 * time.Hour), Type: models.TypeEthTransfer, From: other, To: wallet, Value: "0.25"},
+	}
+}
+
+// typeKeys sends s as character keys
+func typeKeys(b *Browser, s string) {
+	for _, r := range s {
+		b.Handle(Key{Code: KeyRune, Rune: r})
+	}
+}
+
+func TestBrowser_List(t *testing.T) {
+	b := NewBrowser(wallet, browserRows())
+	b.Resize(100, 10)
+	lines := b.Lines()
+	if assert.Len(t, lines, 10) {
+		assert.Contains(t, lines[0], "4 of 4 rows  filters: none")
+		assert.Contains(t, lines[1], "TIME")
+		assert.Equal(t, styleInvert+"2023-11-14 22:13:20  ETH_TRANSFER      OUT   0x222222…2222                     1.5  ETH"+styleReset, lines[2])
+		assert.Contains(t, lines[3], "IN    0x222222…2222")
+		assert.True(t, strings.HasPrefix(lines[4], styleDim), "failed rows are dimmed")
+		assert.Contains(t, lines[4], "DAI (failed)")
+		assert.Contains(t, lines[9], "q quit")
+	}
+
+	b.Handle(Key{Code: KeyDown})
+	b.Handle(Key{Code: KeyRune, Rune: 'j'})
+	assert.Equal(t, 2, b.Selected())
+	b.Handle(Key{Code: KeyEnd})
+	assert.Equal(t, 3, b.Selected())
+	b.Handle(Key{Code: KeyDown})
+	assert.Equal(t, 3, b.Selected(), "the selection stops at the last row")
+	b.Handle(Key{Code: KeyRune, Rune: 'g'})
+	assert.Equal(t, 0, b.Selected())
+
+	assert.True(t, b.Handle(Key{Code: KeyEscape}))
+	assert.False(t, b.Handle(Key{Code: KeyRune, Rune: 'q'}))
+	assert.False(t, b.Handle(Key{Code: KeyInterrupt}))
+}
+
+func TestBrowser_Scroll(t *testing.T) {
+	b := NewBrowser(wallet, browserRows())
+	b.Resize(100, 5)
+	b.Handle(Key{Code: KeyEnd})
+	lines := b.Lines()
+	if assert.Len(t, lines, 5) {
+		assert.Contains(t, lines[2], "(failed)", "two rows fit, so the last two are shown")
+		assert.Contains(t, lines[3], "0.25")
+	}
+	b.Handle(Key{Code: KeyPageUp})
+	assert.Equal(t, 1, b.Selected())
+}
+
+func TestBrowser_Filters(t *testing.T) {
+	b := NewBrowser(wallet, browserRows())
+	b.Resize(200, 24)
+
+	typeKeys(b, "t")
+	typeKeys(b, "erc20_transfer")
+	assert.Contains(t, b.Lines()[b.height-1], "type: erc20_transfer_")
+	b.Handle(Key{Code: KeyEnter})
+	assert.Len(t, b.View(), 2)
+
+	typeKeys(b, "a")
+	typeKeys(b, usdc)
+	b.Handle(Key{Code: KeyEnter})
+	if assert.Len(t, b.View(), 1) {
+		assert.Equal(t, "0xaa02", b.View()[0].Hash)
+	}
+	assert.Contains(t, b.Lines()[0], "1 of 4 rows  filters: type=erc20_transfer token="+usdc)
+
+	typeKeys(b, "x")
+	assert.Len(t, b.View(), 4)
+	assert.Equal(t, "Filters cleared", b.Lines()[b.height-1])
+
+	typeKeys(b, "c")
+	typeKeys(b, "0x3333333333333333333333333333333333333333")
+	b.Handle(Key{Code: KeyEnter})
+	assert.Len(t, b.View(), 1)
+
+	// Editing starts from the current value, and escape keeps it
+	typeKeys(b, "c")
+	b.Handle(Key{Code: KeyBackspace})
+	b.Handle(Key{Code: KeyEscape})
+	assert.Equal(t, "0x3333333333333333333333333333333333333333", b.Filters().Counterparty)
+
+	b.SetFilters(Filters{})
+	typeKeys(b, "/")
+	typeKeys(b, "AA04")
+	b.Handle(Key{Code: KeyEnter})
+	assert.Len(t, b.View(), 1)
+
+	b.SetFilters(Filters{Type: "BLOCK_REWARD"})
+	assert.Empty(t, b.View())
+	assert.Contains(t, b.Lines()[2], "No rows match")
+	b.Handle(Key{Code: KeyEnter})
+	assert.Contains(t, b.Lines()[2], "No rows match", "there is no row to open")
+}
+
+func TestBrowser_Detail(t *testing.T) {
+	b := NewBrowser(wallet, browserRows())
+	b.Resize(120, 30)
+	b.Handle(Key{Code: KeyDown})
+	b.Handle(Key{Code: KeyEnter})
+	screen := strings.Join(b.Lines(), "\n")
+	assert.Contains(t, screen, "Row 2 of 4")
+	assert.Contains(t, screen, "0xaa02")
+	assert.Contains(t, screen, usdc)
+	assert.Contains(t, screen, "esc back")
+
+	b.Handle(Key{Code: KeyDown})
+	assert.Contains(t, strings.Join(b.Lines(), "\n"), "Row 3 of 4", "the arrows step through rows")
+	b.Handle(Key{Code: KeyEscape})
+	assert.Contains(t, b.Lines()[1], "TIME")
+}
+
+func TestBrowser_Export(t *testing.T) {
+	b := NewBrowser(wallet, browserRows())
+	typeKeys(b, "e")
+	assert.Equal(t, "Exporting is not available", b.Lines()[b.height-1])
+
+	var exported []models.Transaction
+	var exportedTo string
+	b.ExportPath = "out.csv"
+	b.Export = func(path string, rows []models.Transaction) (string, error) {
+		exportedTo, exported = path, rows
+		return "out_1.csv", nil
+	}
+	b.SetFilters(Filters{Type: "ETH_TRANSFER"})
+	typeKeys(b, "e")
+	assert.Contains(t, b.Lines()[b.height-1], "export to: out.csv_")
+	b.Handle(Key{Code: KeyEnter})
+	assert.Equal(t, "out.csv", exportedTo)
+	assert.Len(t, exported, 2, "the filtered rows are exported")
+	assert.Equal(t, "Exported 2 rows to out_1.csv", b.Lines()[b.height-1])
+
+	b.Export = func(string, []models.Transaction) (string, error) { return "", errors.New("disk full") }
+	typeKeys(b, "e")
+	b.Handle(Key{Code: KeyEnter})
+	assert.Equal(t, "Export failed: disk full", b.Lines()[b.height-1])
+}
+
+func TestBrowser_Render(t *testing.T) {
+	b := NewBrowser(wallet, browserRows())
+	b.Resize(30, 6)
+	var out bytes.Buffer
+	assert.NoError(t, b.Render(&out))
+	assert.True(t, strings.HasPrefix(out.String(), clearScreen))
+	lines := strings.Split(strings.TrimPrefix(out.String(), clearScreen), "\r\n")
+	if assert.Len(t, lines, 6) {
+		assert.Equal(t, styleBold+wallet[:30]+styleReset, lines[0], "lines are cut to the width")
+	}
+}
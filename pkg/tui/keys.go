@@ -0,0 +1,104 @@
+package tui
+
+import (
+	"bufio"
+)
+
+// KeyCode identifies a key that is not a printable character
+type KeyCode int
+
+const (
+	// KeyRune is a printable character, in Key.Rune
+	KeyRune KeyCode = iota
+	KeyUp
+	KeyDown
+	KeyPageUp
+	KeyPageDown
+	KeyHome
+	KeyEnd
+	KeyEnter
+	KeyEscape
+	KeyBackspace
+	// KeyInterrupt is Ctrl-C, which raw mode delivers as a key
+	KeyInterrupt
+	// KeyUnknown is an escape sequence the browser does not use
+	KeyUnknown
+)
+
+// Key is a key press
+type Key struct {
+	Code KeyCode
+	Rune rune
+}
+
+// ReadKey reads one key press from a terminal in raw mode. Escape
+// sequences arrive in one read, so an escape with nothing buffered after it
+// is the escape key itself.
+func ReadKey(r *bufio.Reader) (Key, error) {
+	c, _, err := r.ReadRune()
+	if err != nil {
+		return Key{}, err
+	}
+	switch c {
+	case 3:
+		return Key{Code: KeyInterrupt}, nil
+	case '\r', '\n':
+		return Key{Code: KeyEnter}, nil
+	case 127, 8:
+		return Key{Code: KeyBackspace}, nil
+	case 27:
+		if r.Buffered() == 0 {
+			return Key{Code: KeyEscape}, nil
+		}
+		return readEscape(r)
+	}
+	if c < ' ' {
+		return Key{Code: KeyUnknown}, nil
+	}
+	return Key{Code: KeyRune, Rune: c}, nil
+}
+
+// readEscape reads the rest of a CSI or SS3 escape sequence
+func readEscape(r *bufio.Reader) (Key, error) {
+	intro, err := r.ReadByte()
+	if err != nil {
+		return Key{}, err
+	}
+	if intro != '[' && intro != 'O' {
+		return Key{Code: KeyUnknown}, nil
+	}
+	var params []byte
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return Key{}, err
+		}
+		// Parameters and intermediates run up to a final byte in @ to ~
+		if b < '@' || b > '~' {
+			params = append(params, b)
+			continue
+		}
+		switch b {
+		case 'A':
+			return Key{Code: KeyUp}, nil
+		case 'B':
+			return Key{Code: KeyDown}, nil
+		case 'H':
+			return Key{Code: KeyHome}, nil
+		case 'F':
+			return Key{Code: KeyEnd}, nil
+		case '~':
+			switch string(params) {
+			case "1", "7":
+				return Key{Code: KeyHome}, nil
+			case "4", "8":
+				return Key{Code: KeyEnd}, nil
+			case "5":
+				return Key{Code: KeyPageUp}, nil
+			case "6":
+				return Key{Code: KeyPageDown}, nil
+			}
+		}
+		return Key{Code: KeyUnknown}, nil
+	}
+}
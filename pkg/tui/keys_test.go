@@ -0,0 +1,42 @@
+package tui
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadKey(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader("q\r\x7f\x03\x1b[A\x1b[B\x1b[5~\x1b[6~\x1bOH\x1b[4~\x1b[1;5C€"))
+	want := []Key{
+		{Code: KeyRune, Rune: 'q'},
+		{Code: KeyEnter},
+		{Code: KeyBackspace},
+		{Code: KeyInterrupt},
+		{Code: KeyUp},
+		{Code: KeyDown},
+		{Code: KeyPageUp},
+		{Code: KeyPageDown},
+		{Code: KeyHome},
+		{Code: KeyEnd},
+		{Code: KeyUnknown},
+		{Code: KeyRune, Rune: '€'},
+	}
+	for _, w := range want {
+		key, err := ReadKey(r)
+		assert.NoError(t, err)
+		assert.Equal(t, w, key)
+	}
+	_, err := ReadKey(r)
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestReadKey_Escape(t *testing.T) {
+	// A lone escape has nothing buffered after it
+	key, err := ReadKey(bufio.NewReader(strings.NewReader("\x1b")))
+	assert.NoError(t, err)
+	assert.Equal(t, Key{Code: KeyEscape}, key)
+}
@@ -0,0 +1,52 @@
+package tui
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Escapes switching to the alternate screen with a hidden cursor, so the
+// browser leaves the scrollback as it found it
+const (
+	enterScreen = "\033[?1049h\033[?25l"
+	leaveScreen = "\033[?25h\033[?1049l"
+)
+
+// Run browses b on the terminal of in and out until the user quits
+func Run(in, out *os.File, b *Browser) error {
+	restore, err := makeRaw(int(in.Fd()))
+	if err != nil {
+		return fmt.Errorf("cannot read keys from the terminal: %w", err)
+	}
+	defer restore()
+	fmt.Fprint(out, enterScreen)
+	defer fmt.Fprint(out, leaveScreen)
+
+	keys := bufio.NewReader(in)
+	var screen bytes.Buffer
+	for {
+		// Reading the size on every key follows resizes without SIGWINCH
+		if width, height, err := size(int(out.Fd())); err == nil {
+			b.Resize(width, height)
+		}
+		screen.Reset()
+		b.Render(&screen)
+		if _, err := out.Write(screen.Bytes()); err != nil {
+			return err
+		}
+		key, err := ReadKey(keys)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !b.Handle(key) {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd)
+
+package tui
+
+import "errors"
+
+var errUnsupported = errors.New("interactive terminals are not supported on this platform")
+
+func makeRaw(fd int) (func() error, error) {
+	return nil, errUnsupported
+}
+
+func size(fd int) (int, int, error) {
+	return 0, 0, errUnsupported
+}
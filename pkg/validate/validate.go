@@ -0,0 +1,313 @@
+// Package validate checks that an export is sound before it is loaded
+// downstream: every header is a registered column, every row parses, rows
+// are in chronological order, no row appears twice and no value is
+// impossible, such as a negative amount or a zero hash.
+package validate
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/export"
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/haridev22/ct-assignement/pkg/wallets"
+)
+
+// Issue is a problem found in a file
+type Issue struct {
+	// Line is the line of the file, 1 being the header
+	Line int `json:"line"`
+	// Column is the key of the column at fault, if a single one is
+	Column  string `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+// String describes the issue on one line
+func (i Issue) String() string {
+	if i.Column == "" {
+		return fmt.Sprintf("line %d: %s", i.Line, i.Message)
+	}
+	return fmt.Sprintf("line %d, %s: %s", i.Line, i.Column, i.Message)
+}
+
+// Result is the outcome of checking a file
+type Result struct {
+	Rows int
+	// Columns are the registered columns the header names
+	Columns []models.Column
+	Issues  []Issue
+}
+
+// Valid reports whether no issue was found
+func (r Result) Valid() bool {
+	return len(r.Issues) == 0
+}
+
+// Write prints the counts, then each issue
+func (r Result) Write(w io.Writer) error {
+	if _, err := fmt.Fprintf(w, "Rows:    %d\nColumns: %d (schema version %d)\nIssues:  %d\n",
+		r.Rows, len(r.Columns), models.DetectSchemaVersion(r.Columns), len(r.Issues)); err != nil {
+		return err
+	}
+	if !r.Valid() {
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+	}
+	for _, issue := range r.Issues {
+		if _, err := fmt.Fprintln(w, issue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MarshalJSON encodes the result with the column keys and schema version
+func (r Result) MarshalJSON() ([]byte, error) {
+	keys := make([]string, len(r.Columns))
+	for i, col := range r.Columns {
+		keys[i] = col.Key
+	}
+	issues := r.Issues
+	if issues == nil {
+		issues = []Issue{}
+	}
+	return json.Marshal(struct {
+		Valid         bool     `json:"valid"`
+		Rows          int      `json:"rows"`
+		Columns       []string `json:"columns"`
+		SchemaVersion int      `json:"schema_version"`
+		Issues        []Issue  `json:"issues"`
+	}{r.Valid(), r.Rows, keys, models.DetectSchemaVersion(r.Columns), issues})
+}
+
+// genesis is the time of the first Ethereum block; no chain has rows
+// older than it
+var genesis = time.Date(2015, 7, 30, 15, 26, 13, 0, time.UTC)
+
+// decimalPattern matches the plain decimals exports write amounts as
+var decimalPattern = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+
+// utf8BOM marks a file as UTF-8 for Excel
+const utf8BOM = "\xef\xbb\xbf"
+
+// Check reads an export written with opts and reports its issues. Columns
+// of opts replace the registered columns of the same key, as they do for
+// export.ReadCSVWithOptions. It returns an error only when r cannot be read
+// as CSV at all.
+func Check(r io.Reader, opts export.CSVOptions, now time.Time) (Result, error) {
+	reader := csv.NewReader(r)
+	if opts.Delimiter != 0 {
+		reader.Comma = opts.Delimiter
+	}
+	// Rows with the wrong number of fields are reported, not fatal
+	reader.FieldsPerRecord = -1
+
+	headers, err := reader.Read()
+	if err == io.EOF {
+		return Result{}, errors.New("the file is empty")
+	}
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	var result Result
+	// columns holds the column of each field, nil for unknown headers
+	columns := make([]*models.Column, len(headers))
+	seen := make(map[string]bool)
+	for i, header := range headers {
+		if i == 0 {
+			header = strings.TrimPrefix(header, utf8BOM)
+		}
+		col, ok := models.LookupHeader(header)
+		switch {
+		case !ok:
+			result.Issues = append(result.Issues, Issue{Line: 1, Message: fmt.Sprintf("unknown column header %q", header)})
+			continue
+		case seen[col.Key]:
+			result.Issues = append(result.Issues, Issue{Line: 1, Column: col.Key, Message: fmt.Sprintf("column header %q appears twice", header)})
+			continue
+		}
+		seen[col.Key] = true
+		for _, override := range opts.Columns {
+			if override.Key == col.Key {
+				col = override
+			}
+		}
+		columns[i] = &col
+		result.Columns = append(result.Columns, col)
+	}
+
+	c := checker{result: &result, now: now, hasHash: seen["hash"], hasTimestamp: seen["timestamp"], lines: make(map[string]int)}
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return result, fmt.Errorf("line %d: failed to read CSV record: %w", line, err)
+		}
+		result.Rows++
+		if len(record) != len(headers) {
+			c.add(line, "", "has %d fields, expected %d", len(record), len(headers))
+			continue
+		}
+		c.row(line, columns, record)
+	}
+	return result, nil
+}
+
+// checker holds the state of the checks that compare rows
+type checker struct {
+	result       *Result
+	now          time.Time
+	hasHash      bool
+	hasTimestamp bool
+	// lines maps the row IDs seen to their lines
+	lines map[string]int
+	// previous is the last row that parsed, to check the order against
+	previous *models.Transaction
+}
+
+func (c *checker) add(line int, column, format string, args ...any) {
+	c.result.Issues = append(c.result.Issues, Issue{Line: line, Column: column, Message: fmt.Sprintf(format, args...)})
+}
+
+// row checks the fields of a row, then the row against the rows before it
+func (c *checker) row(line int, columns []*models.Column, record []string) {
+	var tx models.Transaction
+	parsed := true
+	for i, col := range columns {
+		if col == nil {
+			continue
+		}
+		// Undo the text formulas of -excel-compat
+		value := record[i]
+		if len(value) >= 3 && strings.HasPrefix(value, `="`) && strings.HasSuffix(value, `"`) {
+			value = value[2 : len(value)-1]
+		}
+		if err := col.Set(&tx, value); err != nil {
+			c.add(line, col.Key, "invalid value %q: %v", value, err)
+			parsed = false
+			continue
+		}
+		if message := c.value(col, &tx, value); message != "" {
+			c.add(line, col.Key, "%s", message)
+		}
+	}
+	if c.hasHash && tx.Hash == "" && tx.Type != models.TypeBlockReward {
+		c.add(line, "hash", "missing transaction hash")
+	}
+	if !parsed {
+		return
+	}
+
+	if c.hasHash {
+		id := tx.RowID()
+		if first, ok := c.lines[id]; ok {
+			c.add(line, "", "duplicate of the row on line %d", first)
+		} else {
+			c.lines[id] = line
+		}
+	}
+	if c.previous != nil {
+		c.order(line, c.previous, &tx)
+	}
+	c.previous = &tx
+}
+
+// order checks that tx does not come before prev. Rows are ordered by
+// time, or by block when the file has no timestamps; rows of a chain at the
+// same time are ordered by block.
+func (c *checker) order(line int, prev, tx *models.Transaction) {
+	switch {
+	case c.hasTimestamp && tx.Timestamp.Before(prev.Timestamp):
+		c.add(line, "timestamp", "out of order: %s is before %s on the previous row",
+			tx.Timestamp.UTC().Format(time.RFC3339), prev.Timestamp.UTC().Format(time.RFC3339))
+	case (!c.hasTimestamp || tx.Timestamp.Equal(prev.Timestamp)) && tx.Chain == prev.Chain && tx.BlockNumber < prev.BlockNumber:
+		c.add(line, "block", "out of order: block %d is before block %d on the previous row", tx.BlockNumber, prev.BlockNumber)
+	}
+}
+
+// value checks a value that parsed against the type of its column and
+// returns what is wrong with it, or an empty string
+func (c *checker) value(col *models.Column, tx *models.Transaction, value string) string {
+	if value == "" {
+		return ""
+	}
+	switch col.Type {
+	case models.ColumnInteger:
+		n, ok := new(big.Int).SetString(value, 10)
+		if !ok {
+			return fmt.Sprintf("not an integer: %q", value)
+		}
+		if n.Sign() < 0 {
+			return fmt.Sprintf("negative value %q", value)
+		}
+	case models.ColumnDecimal:
+		if !decimalPattern.MatchString(value) {
+			return fmt.Sprintf("not a decimal number: %q", value)
+		}
+		// Running balances go below zero when rows are missing, which
+		// the export warns about
+		if strings.HasPrefix(value, "-") && strings.Trim(value, "-0.") != "" && col.Key != "balance" {
+			return fmt.Sprintf("negative amount %q", value)
+		}
+	case models.ColumnAddress:
+		if !wallets.IsAddress(value) {
+			return fmt.Sprintf("not an address: %q", value)
+		}
+	case models.ColumnHash:
+		if len(value) != 66 || !strings.HasPrefix(value, "0x") || !isHex(value[2:]) {
+			return fmt.Sprintf("not a transaction hash: %q", value)
+		}
+		if strings.Trim(value[2:], "0") == "" {
+			return "zero hash"
+		}
+	case models.ColumnTimestamp:
+		if tx.Timestamp.Before(genesis) {
+			return fmt.Sprintf("%s is before the first Ethereum block", value)
+		}
+		if tx.Timestamp.After(c.now) {
+			return fmt.Sprintf("%s is in the future", value)
+		}
+	case models.ColumnEnum:
+		return enumValue(col.Key, value)
+	}
+	return ""
+}
+
+// enumValue checks the value of the type and status columns
+func enumValue(key, value string) string {
+	switch key {
+	case "type":
+		for _, t := range models.TransactionTypes {
+			if string(t) == value {
+				return ""
+			}
+		}
+		return fmt.Sprintf("unknown transaction type %q", value)
+	case "status":
+		if value != string(models.StatusSuccess) && value != string(models.StatusFailed) {
+			return fmt.Sprintf("unknown status %q", value)
+		}
+	}
+	return ""
+}
+
+// isHex reports whether s holds only hexadecimal digits
+func isHex(s string) bool {
+	for _, r := range s {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,132 @@
+package validate
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/export"
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var now = time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+const (
+	hashA  = "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	hashB  = "0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+	wallet = "0x1111111111111111111111111111111111111111"
+	other  = "0x2222222222222222222222222222222222222222"
+)
+
+func validRows() []models.Transaction {
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	return []models.Transaction{
+		{Hash: hashA, BlockNumber: 100, Timestamp: at, From: wallet, To: other, Type: models.TypeEthTransfer, Value: "1.5", GasFee: "0.001", Status: models.StatusSuccess},
+		{Hash: hashB, BlockNumber: 101, Timestamp: at.Add(time.Minute), From: other, To: wallet, Type: models.TypeERC20Transfer, AssetContractAddr: other, AssetSymbol: "USDC", Value: "250", GasFee: "0", Status: models.StatusFailed},
+		{BlockNumber: 102, Timestamp: at.Add(2 * time.Minute), To: wallet, Type: models.TypeBlockReward, Value: "2", GasFee: "0", Status: models.StatusSuccess},
+	}
+}
+
+// check validates rows written with the default columns and extra
+func check(t *testing.T, rows []models.Transaction, extra ...models.Column) Result {
+	var buf bytes.Buffer
+	columns := append(append([]models.Column{}, models.DefaultColumns...), extra...)
+	assert.NoError(t, export.WriteCSVTo(&buf, rows, export.CSVOptions{Columns: columns}))
+	result, err := Check(&buf, export.CSVOptions{}, now)
+	assert.NoError(t, err)
+	return result
+}
+
+func messages(r Result) []string {
+	var out []string
+	for _, issue := range r.Issues {
+		out = append(out, issue.String())
+	}
+	return out
+}
+
+func TestCheck_Valid(t *testing.T) {
+	result := check(t, validRows())
+	assert.True(t, result.Valid(), messages(result))
+	assert.Equal(t, 3, result.Rows)
+	assert.Len(t, result.Columns, len(models.DefaultColumns))
+}
+
+func TestCheck_Values(t *testing.T) {
+	rows := validRows()
+	rows[0].Value = "-1.5"
+	rows[0].To = "0x1234"
+	rows[1].Hash = "0x" + strings.Repeat("0", 64)
+	rows[1].Type = "SWAP"
+	rows[2].GasFee = "-0"
+	rows = append(rows, models.Transaction{BlockNumber: 103, Timestamp: now.Add(time.Hour), Type: models.TypeEthTransfer, Value: "1e3"})
+
+	assert.Equal(t, []string{
+		`line 2, to: not an address: "0x1234"`,
+		`line 2, value: negative amount "-1.5"`,
+		`line 3, hash: zero hash`,
+		`line 3, type: unknown transaction type "SWAP"`,
+		`line 5, timestamp: 2024-06-01T01:00:00Z is in the future`,
+		`line 5, value: not a decimal number: "1e3"`,
+		`line 5, hash: missing transaction hash`,
+	}, messages(check(t, rows)))
+}
+
+func TestCheck_OrderAndDuplicates(t *testing.T) {
+	rows := validRows()
+	rows = append(rows, rows[0])
+	assert.Equal(t, []string{
+		"line 5: duplicate of the row on line 2",
+		"line 5, timestamp: out of order: 2024-01-02T03:04:05Z is before 2024-01-02T03:06:05Z on the previous row",
+	}, messages(check(t, rows)))
+
+	rows = validRows()
+	rows[1].Timestamp = rows[0].Timestamp
+	rows[1].BlockNumber = 99
+	assert.Equal(t, []string{"line 3, block: out of order: block 99 is before block 100 on the previous row"}, messages(check(t, rows)))
+
+	chain, _ := models.LookupColumn("chain")
+	rows[1].Chain = "base"
+	assert.Empty(t, messages(check(t, rows, chain)), "blocks of different chains are not compared")
+}
+
+func TestCheck_Layout(t *testing.T) {
+	input := "Transaction Hash,Block Number,Colour,Block Number\n" +
+		hashA + ",12,red,12\n" +
+		hashB + ",twelve,blue,13\n" +
+		hashB + ",14\n"
+	result, err := Check(strings.NewReader(input), export.CSVOptions{}, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result.Rows)
+	assert.Equal(t, []string{
+		`line 1: unknown column header "Colour"`,
+		`line 1, block: column header "Block Number" appears twice`,
+		`line 3, block: invalid value "twelve": strconv.ParseInt: parsing "twelve": invalid syntax`,
+		"line 4: has 2 fields, expected 4",
+	}, messages(result))
+
+	_, err = Check(strings.NewReader(""), export.CSVOptions{}, now)
+	assert.Error(t, err)
+
+	opts := export.CSVOptions{Delimiter: '\t', Columns: []models.Column{models.TimestampColumn(time.UTC, time.DateTime)}}
+	result, err = Check(strings.NewReader("Transaction Hash\tDate & Time\n"+hashA+"\t2024-01-02 03:04:05\n"), opts, now)
+	assert.NoError(t, err)
+	assert.True(t, result.Valid(), messages(result))
+}
+
+func TestResult_Write(t *testing.T) {
+	rows := validRows()
+	rows[0].Value = "-1"
+	var out bytes.Buffer
+	assert.NoError(t, check(t, rows).Write(&out))
+	assert.Equal(t, "Rows:    3\nColumns: 12 (schema version 3)\nIssues:  1\n\nline 2, value: negative amount \"-1\"\n", out.String())
+
+	encoded, err := json.Marshal(check(t, validRows()))
+	assert.NoError(t, err)
+	assert.Contains(t, string(encoded), `"valid":true`)
+	assert.Contains(t, string(encoded), `"issues":[]`)
+	assert.Contains(t, string(encoded), `"schema_version":3`)
+}
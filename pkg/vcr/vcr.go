@@ -0,0 +1,187 @@
+// Package vcr records provider responses to fixture files and replays them
+// without network access, so exports are reproducible offline and tests
+// can run the full pipeline against captured data.
+package vcr
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// ErrNotRecorded is returned when replaying a request that has no fixture
+var ErrNotRecorded = errors.New("no recorded response")
+
+// secretParams are query parameters left out of fixtures and their keys,
+// so fixtures hold no credentials and replay with any key
+var secretParams = []string{"apikey"}
+
+// Fixture is a recorded response
+type Fixture struct {
+	Method string `json:"method"`
+	// Request is the query and body of the request, without secrets
+	Request string      `json:"request"`
+	Status  int         `json:"status"`
+	Header  http.Header `json:"header,omitempty"`
+	Body    string      `json:"body"`
+}
+
+// Recorder is an http.RoundTripper that performs requests with Base and
+// saves every response to a fixture in Dir
+type Recorder struct {
+	Dir string
+	// Base performs the requests. Defaults to http.DefaultTransport.
+	Base http.RoundTripper
+}
+
+// RoundTrip performs req and records its response
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	key, request, err := requestKey(req)
+	if err != nil {
+		return nil, err
+	}
+	base := r.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	header := resp.Header.Clone()
+	header.Del("Set-Cookie")
+	header.Del("Date")
+	fixture := Fixture{Method: req.Method, Request: request, Status: resp.StatusCode, Header: header, Body: string(body)}
+	if err := save(filepath.Join(r.Dir, key+".json"), fixture); err != nil {
+		return nil, fmt.Errorf("cannot record response: %w", err)
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+// Replayer is an http.RoundTripper that answers requests from the
+// fixtures in Dir without network access
+type Replayer struct {
+	Dir string
+}
+
+// RoundTrip returns the recorded response to req
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	key, request, err := requestKey(req)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(r.Dir, key+".json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w for %s %s", ErrNotRecorded, req.Method, request)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var fixture Fixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("invalid fixture %s.json: %w", key, err)
+	}
+
+	header := fixture.Header
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", fixture.Status, http.StatusText(fixture.Status)),
+		StatusCode:    fixture.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader([]byte(fixture.Body))),
+		ContentLength: int64(len(fixture.Body)),
+		Request:       req,
+	}, nil
+}
+
+// requestKey identifies req by its method, endpoint, query and body with
+// secrets removed. It returns the fixture name and a readable form of the
+// request. The body of req is restored for sending.
+func requestKey(req *http.Request) (string, string, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return "", "", err
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	query := req.URL.Query()
+	for _, name := range secretParams {
+		query.Del(name)
+	}
+	request := query.Encode()
+	if len(body) > 0 {
+		request += " " + string(normalizeBody(body))
+	}
+
+	// The path is hashed but not stored, since RPC providers embed API keys in it
+	sum := sha256.Sum256([]byte(req.Method + " " + req.URL.Host + req.URL.Path + "?" + request))
+	return hex.EncodeToString(sum[:16]), request, nil
+}
+
+// normalizeBody drops the id of a JSON-RPC request, which differs between
+// runs, so the same call matches its fixture
+func normalizeBody(body []byte) []byte {
+	var call map[string]json.RawMessage
+	if json.Unmarshal(body, &call) != nil {
+		return body
+	}
+	if _, ok := call["id"]; !ok {
+		return body
+	}
+	delete(call, "id")
+	normalized, err := json.Marshal(call)
+	if err != nil {
+		return body
+	}
+	return normalized
+}
+
+// save writes fixture to path atomically, since concurrent fetchers can
+// record the same request
+func save(path string, fixture Fixture) error {
+	data, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".fixture-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
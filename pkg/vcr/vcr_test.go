@@ -0,0 +1,78 @@
+package vcr
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/haridev22/ct-assignement/pkg/api"
+	"github.com/haridev22/ct-assignement/pkg/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(api.APIResponse{
+			Status:  "1",
+			Message: "OK",
+			Result:  json.RawMessage(`[{"blockNumber":"5","hash":"0xabc","value":"1"}]`),
+		})
+	}))
+	dir := t.TempDir()
+
+	recording := api.NewEtherscanClient("secret-key")
+	recording.BaseURL = server.URL
+	recording.HTTPClient.Transport = &Recorder{Dir: dir}
+	recorded, err := recording.GetNormalTransactions("0xwallet", 0, 100)
+	assert.NoError(t, err)
+	server.Close()
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if assert.Len(t, files, 1) {
+		data, _ := os.ReadFile(files[0])
+		assert.NotContains(t, string(data), "secret-key", "fixtures hold no API keys")
+	}
+
+	// The server is gone, and replay works with any key
+	replaying := api.NewEtherscanClient("other-key")
+	replaying.BaseURL = server.URL
+	replaying.HTTPClient.Transport = &Replayer{Dir: dir}
+	replayed, err := replaying.GetNormalTransactions("0xwallet", 0, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, recorded, replayed)
+
+	replaying.MaxRetries = 0
+	_, err = replaying.GetNormalTransactions("0xother", 0, 100)
+	assert.ErrorIs(t, err, ErrNotRecorded)
+}
+
+func TestReplayIgnoresRPCRequestIDs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x10"}`))
+	}))
+	dir := t.TempDir()
+
+	node := rpc.NewClient(server.URL + "/v3/project-key")
+	node.HTTPClient.Transport = &Recorder{Dir: dir}
+	_, err := node.BlockNumber()
+	assert.NoError(t, err)
+	server.Close()
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if assert.Len(t, files, 1) {
+		data, _ := os.ReadFile(files[0])
+		assert.False(t, strings.Contains(string(data), "project-key"), "the path is not stored")
+	}
+
+	// A new client numbers its requests from the start again
+	node = rpc.NewClient(server.URL + "/v3/project-key")
+	node.HTTPClient.Transport = &Replayer{Dir: dir}
+	node.BlockNumber()
+	number, err := node.BlockNumber()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(16), number)
+}
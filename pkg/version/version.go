@@ -0,0 +1,11 @@
+// Package version exposes the semantic version of this module.
+//
+// The packages under pkg/ follow semantic versioning: within a major
+// version, exported identifiers are only ever added or deprecated, never
+// removed or changed incompatibly. Deprecated identifiers are kept for at
+// least one minor release and marked with a "Deprecated:" doc comment.
+package version
+
+// Version is the semantic version of the module. It is kept in sync with
+// the git tag of each release.
+const Version = "v1.0.0"
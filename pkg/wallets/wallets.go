@@ -0,0 +1,79 @@
+// Package wallets reads lists of addresses for bulk exports.
+package wallets
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Wallet is an address with an optional human-readable label
+type Wallet struct {
+	Address string
+	Label   string
+}
+
+// String returns the address followed by its label, if any
+func (w Wallet) String() string {
+	if w.Label == "" {
+		return w.Address
+	}
+	return fmt.Sprintf("%s (%s)", w.Address, w.Label)
+}
+
+// Parse reads one address or "address,label" per line. Blank lines and
+// lines starting with # are skipped. Invalid and repeated addresses are
+// errors, reported with their line number.
+func Parse(r io.Reader) ([]Wallet, error) {
+	var result []Wallet
+	seen := make(map[string]int)
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		address, label, _ := strings.Cut(text, ",")
+		wallet := Wallet{Address: strings.TrimSpace(address), Label: strings.TrimSpace(label)}
+		if !IsAddress(wallet.Address) {
+			return nil, fmt.Errorf("line %d: invalid address %q", line, wallet.Address)
+		}
+		key := strings.ToLower(wallet.Address)
+		if first, ok := seen[key]; ok {
+			return nil, fmt.Errorf("line %d: address %s already listed on line %d", line, wallet.Address, first)
+		}
+		seen[key] = line
+		result = append(result, wallet)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ParseFile reads a wallet list from path
+func ParseFile(path string) ([]Wallet, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return Parse(file)
+}
+
+// IsAddress reports whether s is a 0x-prefixed 20-byte hex address
+func IsAddress(s string) bool {
+	if len(s) != 42 || (s[:2] != "0x" && s[:2] != "0X") {
+		return false
+	}
+	for _, c := range s[2:] {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return false
+		}
+	}
+	return true
+}
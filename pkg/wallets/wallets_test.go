@@ -0,0 +1,38 @@
+package wallets
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	input := `# treasury wallets
+0x742d35Cc6634C0532925a3b844Bc454e4438f44e,Treasury, main
+
+0xde0b295669a9fd93d5f28d9ec85e40f4cb697bae
+`
+	wallets, err := Parse(strings.NewReader(input))
+	assert.NoError(t, err)
+	assert.Equal(t, []Wallet{
+		{Address: "0x742d35Cc6634C0532925a3b844Bc454e4438f44e", Label: "Treasury, main"},
+		{Address: "0xde0b295669a9fd93d5f28d9ec85e40f4cb697bae"},
+	}, wallets)
+	assert.Equal(t, "0x742d35Cc6634C0532925a3b844Bc454e4438f44e (Treasury, main)", wallets[0].String())
+	assert.Equal(t, "0xde0b295669a9fd93d5f28d9ec85e40f4cb697bae", wallets[1].String())
+}
+
+func TestParse_Errors(t *testing.T) {
+	_, err := Parse(strings.NewReader("0x123,short\n"))
+	assert.EqualError(t, err, `line 1: invalid address "0x123"`)
+
+	_, err = Parse(strings.NewReader("0xde0b295669a9fd93d5f28d9ec85e40f4cb697bae\n\n0xDE0B295669A9FD93D5F28D9EC85E40F4CB697BAE,dup\n"))
+	assert.EqualError(t, err, "line 3: address 0xDE0B295669A9FD93D5F28D9EC85E40F4CB697BAE already listed on line 1")
+}
+
+func TestIsAddress(t *testing.T) {
+	assert.True(t, IsAddress("0xde0b295669a9fd93d5f28d9ec85e40f4cb697bae"))
+	assert.False(t, IsAddress("de0b295669a9fd93d5f28d9ec85e40f4cb697bae"))
+	assert.False(t, IsAddress("0xzz0b295669a9fd93d5f28d9ec85e40f4cb697bae"))
+}
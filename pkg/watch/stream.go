@@ -0,0 +1,159 @@
+package watch
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+)
+
+const (
+	// historySize is the number of recent events kept for clients that
+	// reconnect with Last-Event-ID
+	historySize = 256
+	// subscriberBuffer is the number of events a subscriber can fall behind
+	// before it is disconnected
+	subscriberBuffer = 256
+	// keepAliveInterval is the time between comments that keep idle
+	// connections open through proxies
+	keepAliveInterval = 15 * time.Second
+)
+
+// Event is a detected transaction with its stream position
+type Event struct {
+	ID          int64
+	Transaction models.Transaction
+}
+
+// Hub fans detected transactions out to subscribers. It serves them as a
+// Server-Sent Events stream, so monitoring UIs can follow an address live.
+// The zero value is ready to use and safe for concurrent use.
+type Hub struct {
+	mu          sync.Mutex
+	nextID      int64
+	history     []Event
+	subscribers map[chan Event]bool
+}
+
+// Publish sends txs to every subscriber. Subscribers that have fallen too
+// far behind are disconnected rather than blocking the watcher.
+func (h *Hub) Publish(txs []models.Transaction) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, tx := range txs {
+		h.nextID++
+		event := Event{ID: h.nextID, Transaction: tx}
+		h.history = append(h.history, event)
+		if len(h.history) > historySize {
+			h.history = h.history[len(h.history)-historySize:]
+		}
+		for ch := range h.subscribers {
+			select {
+			case ch <- event:
+			default:
+				delete(h.subscribers, ch)
+				close(ch)
+			}
+		}
+	}
+	return nil
+}
+
+// Subscribe returns a channel of the events published after the event with
+// ID after, which is 0 for only new events, and a function that ends the
+// subscription. The channel is closed if the subscriber falls behind.
+func (h *Hub) Subscribe(after int64) (<-chan Event, func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.subscribers == nil {
+		h.subscribers = make(map[chan Event]bool)
+	}
+
+	ch := make(chan Event, subscriberBuffer+historySize)
+	if after > 0 {
+		for _, event := range h.history {
+			if event.ID > after {
+				ch <- event
+			}
+		}
+	}
+	h.subscribers[ch] = true
+	return ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if h.subscribers[ch] {
+			delete(h.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// Subscribers returns the number of connected subscribers
+func (h *Hub) Subscribers() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subscribers)
+}
+
+// ServeHTTP streams events as Server-Sent Events. Each event is a
+// "transaction" event whose data is the JSON of the row. An address query
+// parameter keeps the rows sent from or to that address, and clients that
+// reconnect with Last-Event-ID receive the recent events they missed.
+func (h *Hub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	var after int64
+	if lastID := r.Header.Get("Last-Event-ID"); lastID != "" {
+		after, _ = strconv.ParseInt(lastID, 10, 64)
+	}
+	address := r.URL.Query().Get("address")
+
+	events, cancel := h.Subscribe(after)
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case event, ok := <-events:
+			if !ok {
+				// Fell behind; the client reconnects with Last-Event-ID
+				return
+			}
+			tx := &event.Transaction
+			if address != "" && !strings.EqualFold(tx.From, address) && !strings.EqualFold(tx.To, address) {
+				continue
+			}
+			data, err := json.Marshal(tx)
+			if err != nil {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "id: %d\nevent: transaction\ndata: %s\n\n", event.ID, data); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package watch
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHub_Subscribe(t *testing.T) {
+	var hub Hub
+	hub.Publish([]models.Transaction{{Hash: "0x1"}, {Hash: "0x2"}})
+
+	events, cancel := hub.Subscribe(1)
+	assert.Equal(t, 1, hub.Subscribers())
+	hub.Publish([]models.Transaction{{Hash: "0x3"}})
+
+	// Events after the given ID are replayed before new ones
+	assert.Equal(t, Event{ID: 2, Transaction: models.Transaction{Hash: "0x2"}}, <-events)
+	assert.Equal(t, int64(3), (<-events).ID)
+
+	cancel()
+	cancel()
+	assert.Equal(t, 0, hub.Subscribers())
+	_, open := <-events
+	assert.False(t, open)
+}
+
+func TestHub_DropsSlowSubscribers(t *testing.T) {
+	var hub Hub
+	events, cancel := hub.Subscribe(0)
+	defer cancel()
+
+	txs := make([]models.Transaction, subscriberBuffer+historySize+1)
+	hub.Publish(txs)
+	assert.Equal(t, 0, hub.Subscribers())
+
+	received := 0
+	for range events {
+		received++
+	}
+	assert.Equal(t, subscriberBuffer+historySize, received)
+}
+
+func TestHub_ServeHTTP(t *testing.T) {
+	var hub Hub
+	srv := httptest.NewServer(&hub)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "?address=0xWallet")
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	reader := bufio.NewReader(resp.Body)
+	line, _ := reader.ReadString('\n')
+	assert.Equal(t, ": connected\n", line)
+
+	for hub.Subscribers() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	hub.Publish([]models.Transaction{
+		{Hash: "0xother", From: "0xa", To: "0xb"},
+		{Hash: "0xmine", From: "0xa", To: "0xwallet", Value: "1.5"},
+	})
+
+	var lines []string
+	for len(lines) < 3 {
+		line, err := reader.ReadString('\n')
+		assert.NoError(t, err)
+		if line = strings.TrimSuffix(line, "\n"); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	assert.Equal(t, "id: 2", lines[0])
+	assert.Equal(t, "event: transaction", lines[1])
+	assert.Contains(t, lines[2], `"hash":"0xmine"`)
+}
@@ -0,0 +1,120 @@
+// Package watch polls an address for transactions in newly mined blocks, so
+// callers can react to activity without re-exporting the whole history.
+package watch
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+)
+
+// DefaultInterval is the polling interval used when Watcher.Interval is zero
+const DefaultInterval = 15 * time.Second
+
+// FetchFunc returns the rows of address in the inclusive block range
+type FetchFunc func(address string, startBlock, endBlock int64) ([]models.Transaction, error)
+
+// HeadFunc returns the number of the latest block
+type HeadFunc func() (int64, error)
+
+// Watcher polls a chain for new rows of an address
+type Watcher struct {
+	Fetch FetchFunc
+	Head  HeadFunc
+	// Interval is the time between polls
+	Interval time.Duration
+	// Confirmations holds back blocks this close to the head, giving the
+	// provider's index time to catch up and shallow reorgs time to settle
+	Confirmations int64
+	// OnError is called for failed polls; the range is retried on the next poll
+	OnError func(error)
+}
+
+// Run polls from fromBlock until ctx is cancelled, passing the rows of each
+// newly covered range to emit in block order. A negative fromBlock starts
+// after the current head, so only new activity is reported. Run returns
+// ctx.Err() on cancellation, or the first error returned by emit.
+func (w *Watcher) Run(ctx context.Context, address string, fromBlock int64, emit func([]models.Transaction) error) error {
+	return w.RunTargets(ctx, []Target{{Address: address, FromBlock: fromBlock}}, func(_ string, txs []models.Transaction) error {
+		return emit(txs)
+	})
+}
+
+// Target is an address to watch from a block. A negative FromBlock starts
+// after the current head.
+type Target struct {
+	Address   string
+	FromBlock int64
+}
+
+// RunTargets polls several addresses like Run, with one head request per
+// poll for all of them. emit receives the rows of one address at a time.
+func (w *Watcher) RunTargets(ctx context.Context, targets []Target, emit func(address string, txs []models.Transaction) error) error {
+	interval := w.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+
+	next := make([]int64, len(targets))
+	for i, target := range targets {
+		next[i] = target.FromBlock
+	}
+	for {
+		if err := w.poll(targets, next, emit); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// poll fetches the blocks from next up to the confirmed head for every
+// target and advances next past them. A target whose fetch fails is retried
+// from the same block on the next poll. Only errors from emit are returned.
+func (w *Watcher) poll(targets []Target, next []int64, emit func(string, []models.Transaction) error) error {
+	head, err := w.Head()
+	if err != nil {
+		w.report(err)
+		return nil
+	}
+	safe := head - w.Confirmations
+	for i, target := range targets {
+		if next[i] < 0 {
+			next[i] = safe + 1
+			continue
+		}
+		if safe < next[i] {
+			continue
+		}
+
+		txs, err := w.Fetch(target.Address, next[i], safe)
+		if err != nil {
+			w.report(err)
+			continue
+		}
+		next[i] = safe + 1
+		if len(txs) == 0 {
+			continue
+		}
+
+		sort.SliceStable(txs, func(i, j int) bool {
+			return txs[i].BlockNumber < txs[j].BlockNumber
+		})
+		if err := emit(target.Address, txs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *Watcher) report(err error) {
+	if w.OnError != nil {
+		w.OnError(err)
+	}
+}
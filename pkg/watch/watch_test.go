@@ -0,0 +1,110 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatcher_Run(t *testing.T) {
+	heads := []int64{100, 100, 103, 103, 105}
+	polls := 0
+	var ranges [][2]int64
+	var errs []error
+
+	w := &Watcher{
+		Head: func() (int64, error) {
+			head := heads[polls]
+			polls++
+			if polls == 4 {
+				return 0, errors.New("rate limited")
+			}
+			return head, nil
+		},
+		Fetch: func(address string, start, end int64) ([]models.Transaction, error) {
+			ranges = append(ranges, [2]int64{start, end})
+			return []models.Transaction{{Hash: "0xb", BlockNumber: end}, {Hash: "0xa", BlockNumber: start}}, nil
+		},
+		Interval:      time.Millisecond,
+		Confirmations: 1,
+		OnError:       func(err error) { errs = append(errs, err) },
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var emitted [][]models.Transaction
+	err := w.Run(ctx, "0xwallet", -1, func(txs []models.Transaction) error {
+		emitted = append(emitted, txs)
+		if polls == len(heads) {
+			cancel()
+		}
+		return nil
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	// The first poll only finds the head; later polls cover confirmed blocks once
+	assert.Equal(t, [][2]int64{{100, 102}, {103, 104}}, ranges)
+	assert.Len(t, errs, 1)
+	if assert.Len(t, emitted, 2) {
+		assert.Equal(t, "0xa", emitted[0][0].Hash, "rows are emitted in block order")
+	}
+}
+
+func TestWatcher_FetchErrorRetries(t *testing.T) {
+	attempts := 0
+	w := &Watcher{
+		Head: func() (int64, error) { return 50, nil },
+		Fetch: func(address string, start, end int64) ([]models.Transaction, error) {
+			attempts++
+			assert.Equal(t, int64(10), start, "a failed range is retried from the same block")
+			if attempts == 1 {
+				return nil, errors.New("timeout")
+			}
+			return []models.Transaction{{Hash: "0x1", BlockNumber: 20}}, nil
+		},
+		Interval: time.Millisecond,
+	}
+
+	stop := errors.New("stop")
+	err := w.Run(context.Background(), "0xwallet", 10, func(txs []models.Transaction) error {
+		return stop
+	})
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, 2, attempts)
+}
+
+func TestWatcher_RunTargets(t *testing.T) {
+	heads := 0
+	fetched := map[string][][2]int64{}
+	w := &Watcher{
+		Head: func() (int64, error) {
+			heads++
+			return 100, nil
+		},
+		Fetch: func(address string, start, end int64) ([]models.Transaction, error) {
+			fetched[address] = append(fetched[address], [2]int64{start, end})
+			if address == "0xfailing" {
+				return nil, errors.New("timeout")
+			}
+			return []models.Transaction{{Hash: address, BlockNumber: end}}, nil
+		},
+		Interval: time.Millisecond,
+	}
+
+	targets := []Target{{Address: "0xnew", FromBlock: -1}, {Address: "0xfailing", FromBlock: 90}, {Address: "0xold", FromBlock: 80}}
+	var emitted []string
+	err := w.RunTargets(context.Background(), targets, func(address string, txs []models.Transaction) error {
+		emitted = append(emitted, address)
+		return errors.New("stop")
+	})
+
+	assert.EqualError(t, err, "stop")
+	assert.Equal(t, 1, heads, "one head request covers every target")
+	assert.Empty(t, fetched["0xnew"], "new-only targets wait for the next poll")
+	assert.Equal(t, [][2]int64{{90, 100}}, fetched["0xfailing"])
+	assert.Equal(t, [][2]int64{{80, 100}}, fetched["0xold"], "a failed target does not hold back the others")
+	assert.Equal(t, []string{"0xold"}, emitted)
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/haridev22/ct-assignement/pkg/export"
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/haridev22/ct-assignement/pkg/progress"
+)
+
+// definePreview defines the preview subcommand, which prints the first and
+// last rows of an export or a store as a table
+func definePreview(fs *flag.FlagSet) func() {
+	rows := addReportInputFlags(fs)
+	n := fs.Int("n", 10, "Number of rows to print from each end")
+	noColor := fs.Bool("no-color", false, "Print without colors (the default when output is not a terminal or NO_COLOR is set)")
+	return func() {
+		if *n <= 0 {
+			log.Fatal("Error: -n must be positive.")
+		}
+		address, txs := rows.load("preview")
+		printPreview(address, txs, *n, !*noColor)
+	}
+}
+
+// printPreview prints the first and last n rows of address to stdout,
+// colorized when color is allowed and stdout is a terminal
+func printPreview(address string, txs []models.Transaction, n int, color bool) {
+	opts := export.PreviewOptions{
+		Rows:    n,
+		Address: address,
+		Color:   color && os.Getenv("NO_COLOR") == "" && progress.IsTerminal(os.Stdout),
+	}
+	if err := export.WritePreview(os.Stdout, txs, opts); err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+}
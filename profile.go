@@ -0,0 +1,99 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"runtime"
+	runtimepprof "runtime/pprof"
+	"time"
+)
+
+// profileFlags holds the flags that profile a run, for diagnosing slow or
+// memory-hungry exports of very active addresses
+type profileFlags struct {
+	cpuProfile *string
+	memProfile *string
+	pprof      *string
+}
+
+func addProfileFlags(fs *flag.FlagSet) *profileFlags {
+	return &profileFlags{
+		cpuProfile: fs.String("cpuprofile", "", "Write a CPU profile of the run to this file, for go tool pprof"),
+		memProfile: fs.String("memprofile", "", "Write a heap profile to this file when the run finishes, for go tool pprof"),
+		pprof:      fs.String("pprof", "", "Serve live runtime profiles at http://[address]/debug/pprof/ during the run, e.g. 127.0.0.1:6060"),
+	}
+}
+
+// start begins the requested profiling, exiting if it cannot. The returned
+// function writes the profiles and must run before the command returns;
+// runs that exit with an error write no profiles.
+func (f *profileFlags) start() (stop func()) {
+	if *f.pprof != "" {
+		listener, err := net.Listen("tcp", *f.pprof)
+		if err != nil {
+			log.Fatalf("Error: invalid -pprof: %v", err)
+		}
+		mux := http.NewServeMux()
+		registerPprof(mux)
+		go func() {
+			httpServer := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+			if err := httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Error("profile server stopped", "error", err)
+			}
+		}()
+		logger.Info("serving runtime profiles", "url", fmt.Sprintf("http://%s/debug/pprof/", listener.Addr()))
+	}
+
+	var cpuFile *os.File
+	if *f.cpuProfile != "" {
+		var err error
+		if cpuFile, err = os.Create(*f.cpuProfile); err != nil {
+			log.Fatalf("Error: invalid -cpuprofile: %v", err)
+		}
+		if err := runtimepprof.StartCPUProfile(cpuFile); err != nil {
+			log.Fatalf("Error: could not start CPU profile: %v", err)
+		}
+	}
+
+	return func() {
+		if cpuFile != nil {
+			runtimepprof.StopCPUProfile()
+			if err := cpuFile.Close(); err != nil {
+				logger.Error("failed to write CPU profile", "error", err)
+			}
+		}
+		if *f.memProfile != "" {
+			writeHeapProfile(*f.memProfile)
+		}
+	}
+}
+
+// writeHeapProfile writes a heap profile to path
+func writeHeapProfile(path string) {
+	file, err := os.Create(path)
+	if err != nil {
+		logger.Error("failed to write heap profile", "error", err)
+		return
+	}
+	defer file.Close()
+	// Collect garbage first so the profile shows live memory
+	runtime.GC()
+	if err := runtimepprof.WriteHeapProfile(file); err != nil {
+		logger.Error("failed to write heap profile", "error", err)
+	}
+}
+
+// registerPprof mounts the runtime profile handlers at /debug/pprof/
+func registerPprof(mux *http.ServeMux) {
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+}
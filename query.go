@@ -0,0 +1,61 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/haridev22/ct-assignement/pkg/export"
+	"github.com/haridev22/ct-assignement/pkg/filter"
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/haridev22/ct-assignement/pkg/store"
+)
+
+// defineQuery defines the query subcommand, which prints the latest rows of
+// an address in a store that match the given filters
+func defineQuery(fs *flag.FlagSet) func() {
+	address := fs.String("address", "", "Ethereum wallet address to query (required)")
+	storePath := fs.String("store", "", "Versioned store file to read from (required)")
+	types := fs.String("type", "", "Comma-separated transaction types to keep, e.g. ERC20_TRANSFER,INTERNAL_TRANSFER")
+	hash := fs.String("hash", "", "Only rows of this transaction hash, including its internal transfers")
+	counterparty := fs.String("counterparty", "", "Only rows sent from or to this address")
+	limit := fs.Int("limit", 0, "Print at most this many rows (0 for all)")
+	format := fs.String("format", string(export.StreamTable), "Output format: table or ndjson")
+	return func() {
+		if *address == "" || *storePath == "" {
+			log.Fatal("Error: query requires -address and -store.")
+		}
+		streamFormat, err := export.ParseStreamFormat(*format)
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+
+		var filters []filter.Func
+		if *types != "" {
+			var selected []models.TransactionType
+			for _, t := range strings.Split(*types, ",") {
+				selected = append(selected, models.TransactionType(strings.ToUpper(strings.TrimSpace(t))))
+			}
+			filters = append(filters, filter.Types(selected...))
+		}
+		if *hash != "" {
+			filters = append(filters, filter.Hash(*hash))
+		}
+		if *counterparty != "" {
+			filters = append(filters, filter.Counterparty(*counterparty))
+		}
+
+		s, err := store.Open(*storePath)
+		if err != nil {
+			log.Fatalf("Error opening store: %v", err)
+		}
+		txs := filter.Apply(s.Latest(*address), filters...)
+		if *limit > 0 && len(txs) > *limit {
+			txs = txs[:*limit]
+		}
+		if err := export.NewStreamWriter(os.Stdout, streamFormat).Write(txs); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/haridev22/ct-assignement/pkg/api"
+	"github.com/haridev22/ct-assignement/pkg/report"
+)
+
+// reconcileBalance compares the running balance of address after endBlock
+// with the balance onChain reads from the chain, when -running-balance
+// replayed the rows of the export
+func reconcileBalance(address string, endBlock int64, opts runOptions, onChain func() (*big.Int, error)) {
+	if opts.balances == nil || opts.storeOnly {
+		return
+	}
+	wei, err := onChain()
+	if err != nil {
+		fmt.Printf("Warning: skipping balance reconciliation: %v\n", err)
+		return
+	}
+	if err := report.Reconcile(address, endBlock, opts.balances.Balance(address), wei).Write(os.Stdout); err != nil {
+		logger.Error("failed to print balance reconciliation", "error", err)
+	}
+}
+
+// etherscanBalance reads the balance of address after block. Etherscan only
+// serves past balances to API Pro keys, so with other keys the balance is
+// read at the latest block, which only works when block is the chain head.
+func etherscanBalance(client *api.EtherscanClient, address string, block int64) func() (*big.Int, error) {
+	return func() (*big.Int, error) {
+		balance, err := client.GetBalanceAt(address, block)
+		if err == nil {
+			return balance, nil
+		}
+		head, headErr := client.GetBlockNumber()
+		if headErr != nil || head != block {
+			return nil, err
+		}
+		return client.GetBalance(address)
+	}
+}
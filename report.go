@@ -0,0 +1,531 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/api"
+	"github.com/haridev22/ct-assignement/pkg/cache"
+	"github.com/haridev22/ct-assignement/pkg/chains"
+	"github.com/haridev22/ct-assignement/pkg/chart"
+	"github.com/haridev22/ct-assignement/pkg/costbasis"
+	"github.com/haridev22/ct-assignement/pkg/ens"
+	"github.com/haridev22/ct-assignement/pkg/export"
+	"github.com/haridev22/ct-assignement/pkg/htmlreport"
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/haridev22/ct-assignement/pkg/report"
+	"github.com/haridev22/ct-assignement/pkg/rpc"
+	"github.com/haridev22/ct-assignement/pkg/store"
+	"github.com/haridev22/ct-assignement/pkg/wallets"
+)
+
+// reportCommands are the subcommands of report, in the order they are
+// shown in its help
+var reportCommands = []command{
+	{"summary", "Print the totals of an export or a -store", defineReportSummary},
+	{"holdings", "Print the assets an address held after a block", defineReportHoldings},
+	{"counterparties", "Print the addresses an address transacted with most", defineReportCounterparties},
+	{"gains", "Print the realized gains of disposals with FIFO, LIFO or HIFO lots", defineReportGains},
+	{"statement", "Print or save the movements of every asset over a period", defineReportStatement},
+	{"html", "Save a self-contained HTML page with cards, charts and a table", defineReportHTML},
+	{"charts", "Save monthly chart images as SVG or PNG", defineReportCharts},
+}
+
+// defineReport defines the report subcommand, which regenerates an export
+// and summary from the store as the data stood at a given date. report
+// summary prints the totals of an export or store instead, report holdings
+// the assets held after a block, report counterparties the addresses funds
+// came from and went to, report gains the realized gains of disposals,
+// report statement the movements of every asset over a period, report html
+// a page to share, and report charts chart images.
+func defineReport(fs *flag.FlagSet) func() {
+	address := fs.String("address", "", "Ethereum wallet address to report on (required)")
+	storePath := fs.String("store", "", "Versioned store file to read from (required)")
+	asOf := fs.String("as-of", "", "Only use data and classifications recorded up to the end of this date (YYYY-MM-DD)")
+	outputDir := fs.String("output", defaultOutputDir, "Directory to save the report")
+	return func() {
+		if *address == "" || *storePath == "" {
+			log.Fatal("Error: report requires -address and -store.")
+		}
+
+		s, err := store.Open(*storePath)
+		if err != nil {
+			log.Fatalf("Error opening store: %v", err)
+		}
+
+		txs := s.Latest(*address)
+		suffix := "latest"
+		runs := s.Runs(*address)
+		if *asOf != "" {
+			day, err := time.Parse("2006-01-02", *asOf)
+			if err != nil {
+				log.Fatalf("Error: invalid -as-of date %q, expected YYYY-MM-DD", *asOf)
+			}
+			// Include everything recorded during the as-of day itself
+			cutoff := day.Add(24*time.Hour - time.Nanosecond)
+			txs = s.AsOfTime(*address, cutoff)
+			suffix = "as_of_" + *asOf
+			for len(runs) > 0 && runs[len(runs)-1].StartedAt.After(cutoff) {
+				runs = runs[:len(runs)-1]
+			}
+		}
+
+		filePath := filepath.Join(*outputDir, fmt.Sprintf("%s_tx_history_%s.csv", *address, suffix))
+		if err := export.WriteCSV(txs, filePath); err != nil {
+			log.Fatalf("Error exporting to CSV: %v", err)
+		}
+
+		summary := report.Summarize(txs)
+		summaryPath := filepath.Join(*outputDir, fmt.Sprintf("%s_summary_%s.txt", *address, suffix))
+		file, err := os.Create(summaryPath)
+		if err != nil {
+			log.Fatalf("Error creating summary file: %v", err)
+		}
+		defer file.Close()
+		if err := summary.Write(file); err != nil {
+			log.Fatalf("Error writing summary: %v", err)
+		}
+
+		// Compare against the run before the last one the report covers
+		var delta *report.Delta
+		if len(runs) > 1 {
+			previous := runs[len(runs)-2]
+			d := report.Compare(*address, s.AsOfRun(*address, previous.ID), txs)
+			d.PreviousRun, d.PreviousTime = previous.ID, previous.StartedAt
+			delta = &d
+			if err := delta.Write(file); err != nil {
+				log.Fatalf("Error writing summary: %v", err)
+			}
+		}
+
+		summary.Write(os.Stdout)
+		if delta != nil {
+			delta.Write(os.Stdout)
+		}
+		fmt.Printf("Exported %d transactions to %s\n", len(txs), filePath)
+		fmt.Printf("Saved summary to %s\n", summaryPath)
+	}
+}
+
+// defineReportSummary defines report summary, which prints the totals of
+// the rows of an export or a store
+func defineReportSummary(fs *flag.FlagSet) func() {
+	rows := addReportInputFlags(fs)
+	format := fs.String("format", "text", "Output format: text or json")
+	return func() {
+		if *format != "text" && *format != "json" {
+			log.Fatalf("Error: unknown -format %q (use text or json).", *format)
+		}
+		address, txs := rows.load("report summary")
+		printReport(report.Describe(address, txs), *format)
+	}
+}
+
+// defineReportHoldings defines report holdings, which prints the assets an
+// address held after a block, derived from the rows of an export or a store
+func defineReportHoldings(fs *flag.FlagSet) func() {
+	rows := addReportInputFlags(fs)
+	block := fs.Int64("block", 0, "Block after which to report the holdings (default: the last block of the rows)")
+	format := fs.String("format", "text", "Output format: text or json")
+	return func() {
+		if *format != "text" && *format != "json" {
+			log.Fatalf("Error: unknown -format %q (use text or json).", *format)
+		}
+		if *block < 0 {
+			log.Fatal("Error: -block cannot be negative.")
+		}
+		address, txs := rows.load("report holdings")
+		if *block == 0 {
+			for _, tx := range txs {
+				*block = max(*block, tx.BlockNumber)
+			}
+		}
+		printReport(report.HoldingsAt(address, *block, txs), *format)
+	}
+}
+
+// defineReportCounterparties defines report counterparties, which prints
+// the addresses an address transacted with most, with their labels and,
+// with -ens, their ENS names
+func defineReportCounterparties(fs *flag.FlagSet) func() {
+	rows := addReportInputFlags(fs)
+	top := fs.Int("top", 20, "Number of counterparties to print")
+	sortBy := fs.String("sort", "count", "Rank by transaction count (count), ETH received (in) or ETH sent (out)")
+	labelsFile := fs.String("labels", "", "File of address,label lines naming known counterparties")
+	resolveENS := fs.Bool("ens", false, "Look up the ENS names of the printed counterparties on Ethereum mainnet (needs -apikey or -rpc-url)")
+	apiKey := fs.String("apikey", "", "Etherscan API key used for -ens")
+	rpcURL := fs.String("rpc-url", "", "Ethereum mainnet JSON-RPC node used for -ens, instead of Etherscan")
+	format := fs.String("format", "text", "Output format: text or json")
+	logOpts := addLogFlags(fs)
+	transportOpts := addTransportFlags(fs)
+	return func() {
+		logOpts.apply()
+
+		if *format != "text" && *format != "json" {
+			log.Fatalf("Error: unknown -format %q (use text or json).", *format)
+		}
+		order, err := report.ParseCounterpartyOrder(*sortBy)
+		if err != nil {
+			log.Fatalf("Error: invalid -sort: %v", err)
+		}
+		if *top <= 0 {
+			log.Fatal("Error: -top must be positive.")
+		}
+		labels := make(map[string]string)
+		if *labelsFile != "" {
+			list, err := wallets.ParseFile(*labelsFile)
+			if err != nil {
+				log.Fatalf("Error reading -labels: %v", err)
+			}
+			for _, w := range list {
+				labels[strings.ToLower(w.Address)] = w.Label
+			}
+		}
+
+		var resolver *ens.Resolver
+		if *resolveENS {
+			resolver = &ens.Resolver{Cache: cache.New()}
+			if *rpcURL = resolveSecret("-rpc-url", *rpcURL); *rpcURL != "" {
+				node := rpc.NewClient(*rpcURL)
+				node.Logger = logger
+				node.HTTPClient.Transport = transportOpts.roundTripper()
+				resolver.Node = node
+			} else {
+				*apiKey = resolveAPIKey(*apiKey)
+				if *apiKey == "" {
+					log.Fatal("Error: -ens requires an Etherscan API key (-apikey or ETHERSCAN_API_KEY) or -rpc-url.")
+				}
+				client := newClient(*apiKey, api.DefaultCallsPerSecond, chains.Ethereum)
+				client.HTTPClient.Transport = transportOpts.roundTripper()
+				resolver.Node = client
+			}
+		}
+
+		address, txs := rows.load("report counterparties")
+		all := report.Counterparties(address, txs, order)
+		r := report.CounterpartyReport{Address: address, Order: order, Total: len(all), Top: all[:min(*top, len(all))]}
+		for i := range r.Top {
+			c := &r.Top[i]
+			c.Label = labels[c.Address]
+			if resolver == nil || !wallets.IsAddress(c.Address) {
+				continue
+			}
+			name, err := resolver.Name(c.Address)
+			if err != nil {
+				logger.Warn("ENS lookup failed", "address", c.Address, "error", err)
+				continue
+			}
+			c.Name = name
+		}
+		printReport(r, *format)
+	}
+}
+
+// defineReportGains defines report gains, which matches the disposals of
+// an address against its acquisitions and prints their realized gains
+func defineReportGains(fs *flag.FlagSet) func() {
+	rows := addReportInputFlags(fs)
+	pricesFile := fs.String("prices", "", "CSV file of date,asset,price lines valuing each asset in a fiat currency (required)")
+	methodName := fs.String("method", string(costbasis.FIFO), "Lot matching method: fifo, lifo or hifo")
+	format := fs.String("format", "text", "Output format: text or json")
+	return func() {
+		if *format != "text" && *format != "json" {
+			log.Fatalf("Error: unknown -format %q (use text or json).", *format)
+		}
+		method, err := costbasis.ParseMethod(*methodName)
+		if err != nil {
+			log.Fatalf("Error: invalid -method: %v", err)
+		}
+		if *pricesFile == "" {
+			log.Fatal("Error: report gains requires -prices.")
+		}
+		prices, err := costbasis.ReadPriceFile(*pricesFile)
+		if err != nil {
+			log.Fatalf("Error reading -prices: %v", err)
+		}
+		address, txs := rows.load("report gains")
+		result, err := costbasis.Compute(address, txs, method, prices)
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		printReport(result, *format)
+	}
+}
+
+// defineReportStatement defines report statement, which prints the
+// opening and closing balances, flows, fees and, with -prices, realized
+// gains of an address over a period, as text, CSV or PDF
+func defineReportStatement(fs *flag.FlagSet) func() {
+	rows := addReportInputFlags(fs)
+	periodName := fs.String("period", "", "Period to report: a year (2024), quarter (2024-Q1) or month (2024-03) (required)")
+	by := fs.String("by", "", "Split -period into one statement per month or quarter")
+	openingBalance := fs.String("opening-balance", "0", "ETH balance of the address before its first row")
+	pricesFile := fs.String("prices", "", "CSV file of date,asset,price lines, to include realized gains (see report gains)")
+	methodName := fs.String("method", string(costbasis.FIFO), "Lot matching method for realized gains: fifo, lifo or hifo")
+	format := fs.String("format", "text", "Output format: text, csv or pdf")
+	outputDir := fs.String("output", defaultOutputDir, "Directory to save csv and pdf statements")
+	return func() {
+		if *format != "text" && *format != "csv" && *format != "pdf" {
+			log.Fatalf("Error: unknown -format %q (use text, csv or pdf).", *format)
+		}
+		if *periodName == "" {
+			log.Fatal("Error: report statement requires -period.")
+		}
+		period, err := report.ParsePeriod(*periodName)
+		if err != nil {
+			log.Fatalf("Error: invalid -period: %v", err)
+		}
+		periods := []report.Period{period}
+		if *by != "" {
+			if periods, err = period.Split(*by); err != nil {
+				log.Fatalf("Error: invalid -by: %v", err)
+			}
+		}
+		opening, ok := new(big.Rat).SetString(*openingBalance)
+		if !ok {
+			log.Fatalf("Error: invalid -opening-balance %q.", *openingBalance)
+		}
+		method, err := costbasis.ParseMethod(*methodName)
+		if err != nil {
+			log.Fatalf("Error: invalid -method: %v", err)
+		}
+		address, txs := rows.load("report statement")
+
+		var gains *costbasis.Result
+		if *pricesFile != "" {
+			prices, err := costbasis.ReadPriceFile(*pricesFile)
+			if err != nil {
+				log.Fatalf("Error reading -prices: %v", err)
+			}
+			if gains, err = costbasis.Compute(address, txs, method, prices); err != nil {
+				log.Fatalf("Error: %v", err)
+			}
+		}
+		statements := make([]report.Statement, len(periods))
+		for i, p := range periods {
+			statements[i] = report.StatementOf(address, p, opening, txs)
+			if gains == nil {
+				continue
+			}
+			realized := gains.Realized(p.Start, p.End)
+			for j := range statements[i].Lines {
+				l := &statements[i].Lines[j]
+				if l.TokenID != "" {
+					continue
+				}
+				l.Gain = new(big.Rat)
+				if gain := realized[costbasis.Asset{Symbol: l.Symbol, Contract: l.Contract}]; gain != nil {
+					l.Gain = gain
+				}
+			}
+		}
+
+		if *format == "text" {
+			for i, statement := range statements {
+				if i > 0 {
+					fmt.Fprintln(os.Stdout)
+				}
+				if err := statement.Write(os.Stdout); err != nil {
+					log.Fatalf("Error: %v", err)
+				}
+			}
+			return
+		}
+		filePath := filepath.Join(*outputDir, fmt.Sprintf("%s_statement_%s.%s", address, period.Name, *format))
+		if err := os.MkdirAll(*outputDir, 0755); err != nil {
+			log.Fatalf("Error creating output directory: %v", err)
+		}
+		file, err := os.Create(filePath)
+		if err != nil {
+			log.Fatalf("Error creating statement file: %v", err)
+		}
+		defer file.Close()
+		if *format == "csv" {
+			err = report.WriteStatementsCSV(file, statements)
+		} else {
+			sections := make([][]string, len(statements))
+			for i, statement := range statements {
+				sections[i] = statement.TextLines()
+			}
+			err = export.WriteTextPDF(file, sections)
+		}
+		if err != nil {
+			log.Fatalf("Error writing statement: %v", err)
+		}
+		fmt.Printf("Saved statement to %s\n", filePath)
+	}
+}
+
+// defineReportHTML defines report html, which renders the rows of an
+// export or a store as a self-contained HTML page
+func defineReportHTML(fs *flag.FlagSet) func() {
+	rows := addReportInputFlags(fs)
+	title := fs.String("title", "", "Title of the page (default: the address)")
+	chainName := addChainFlag(fs)
+	outputDir := fs.String("output", defaultOutputDir, "Directory to save the report")
+	return func() {
+		chain := lookupChain(*chainName)
+		address, txs := rows.load("report html")
+		if err := os.MkdirAll(*outputDir, 0755); err != nil {
+			log.Fatalf("Error creating output directory: %v", err)
+		}
+		filePath := filepath.Join(*outputDir, fmt.Sprintf("%s_report.html", address))
+		file, err := os.Create(filePath)
+		if err != nil {
+			log.Fatalf("Error creating report file: %v", err)
+		}
+		defer file.Close()
+		if err := htmlreport.Write(file, address, txs, htmlreport.Options{Title: *title, Chain: chain}); err != nil {
+			log.Fatalf("Error writing report: %v", err)
+		}
+		fmt.Printf("Saved report of %d transactions to %s\n", len(txs), filePath)
+	}
+}
+
+// defineReportCharts defines report charts, which renders the transactions,
+// gas and flows per month of an address as SVG or PNG images
+func defineReportCharts(fs *flag.FlagSet) func() {
+	rows := addReportInputFlags(fs)
+	formatName := fs.String("format", string(chart.FormatSVG), "Image format: svg or png")
+	tokens := fs.Int("tokens", 3, "Number of tokens to chart the flows of, by number of transfers")
+	outputDir := fs.String("output", defaultOutputDir, "Directory to save the charts")
+	return func() {
+		format, err := chart.ParseFormat(*formatName)
+		if err != nil {
+			log.Fatalf("Error: invalid -format: %v", err)
+		}
+		if *tokens < 0 {
+			log.Fatal("Error: -tokens cannot be negative.")
+		}
+		address, txs := rows.load("report charts")
+		if len(txs) == 0 {
+			log.Fatal("Error: no rows to chart.")
+		}
+		months := report.Monthly(address, txs)
+		charts := map[string]chart.Chart{
+			"transactions": chart.Transactions(months),
+			"gas":          chart.Gas(months),
+			"flows_" + strings.ToLower(report.NativeAsset): chart.Flows(months, report.NativeAsset, ""),
+		}
+		var assets []report.AssetTotal
+		for _, asset := range report.Totals(address, txs) {
+			if asset.Contract != "" {
+				assets = append(assets, asset)
+			}
+		}
+		sort.SliceStable(assets, func(i, j int) bool { return assets[i].Count > assets[j].Count })
+		for _, asset := range assets[:min(*tokens, len(assets))] {
+			charts["flows_"+asset.Contract] = chart.Flows(months, asset.Symbol, asset.Contract)
+		}
+
+		if err := os.MkdirAll(*outputDir, 0755); err != nil {
+			log.Fatalf("Error creating output directory: %v", err)
+		}
+		names := make([]string, 0, len(charts))
+		for name := range charts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			filePath := filepath.Join(*outputDir, fmt.Sprintf("%s_chart_%s.%s", address, name, format))
+			file, err := os.Create(filePath)
+			if err != nil {
+				log.Fatalf("Error creating chart file: %v", err)
+			}
+			err = charts[name].Write(file, format)
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				log.Fatalf("Error writing chart: %v", err)
+			}
+			fmt.Printf("Saved chart to %s\n", filePath)
+		}
+	}
+}
+
+// reportInputFlags select the rows a report reads: an export, or the latest
+// rows of an address in a store
+type reportInputFlags struct {
+	input     *string
+	storePath *string
+	address   *string
+}
+
+func addReportInputFlags(fs *flag.FlagSet) *reportInputFlags {
+	return &reportInputFlags{
+		input:     fs.String("input", "", "Export CSV to read"),
+		storePath: fs.String("store", "", "Versioned store file to read the latest rows of, instead of -input"),
+		address:   fs.String("address", "", "Address the rows belong to (default: taken from the name of -input; required with -store)"),
+	}
+}
+
+// load reads the selected rows and returns them with their address,
+// exiting on invalid flags
+func (f *reportInputFlags) load(command string) (string, []models.Transaction) {
+	if (*f.input == "") == (*f.storePath == "") {
+		log.Fatalf("Error: %s requires exactly one of -input and -store.", command)
+	}
+
+	address := *f.address
+	if *f.input != "" {
+		txs, _, err := export.ReadCSVFile(*f.input)
+		if err != nil {
+			log.Fatalf("Error reading -input: %v", err)
+		}
+		if address == "" {
+			var ok bool
+			if address, ok = exportAddress(*f.input); !ok {
+				log.Fatal("Error: -address is required when the -input file name does not start with the address.")
+			}
+		}
+		return address, txs
+	}
+
+	if address == "" {
+		log.Fatalf("Error: %s requires -address with -store.", command)
+	}
+	s, err := store.Open(*f.storePath)
+	if err != nil {
+		log.Fatalf("Error opening store: %v", err)
+	}
+	return address, s.Latest(address)
+}
+
+// exportAddress returns the address an export file name starts with, as
+// exports are named <address>_tx_history...
+func exportAddress(path string) (string, bool) {
+	prefix, _, _ := strings.Cut(filepath.Base(path), "_")
+	return prefix, wallets.IsAddress(prefix)
+}
+
+// textReport is a report that prints itself in a plain-text layout and
+// marshals to JSON
+type textReport interface {
+	Write(w io.Writer) error
+}
+
+// printReport prints a report to stdout as text or, with format json, as
+// indented JSON
+func printReport(r textReport, format string) {
+	if format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(r); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		return
+	}
+	if err := r.Write(os.Stdout); err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+}
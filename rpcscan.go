@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"math/big"
+	"strconv"
+
+	"github.com/haridev22/ct-assignement/pkg/audit"
+	"github.com/haridev22/ct-assignement/pkg/chains"
+	"github.com/haridev22/ct-assignement/pkg/rpc"
+	"github.com/haridev22/ct-assignement/pkg/scan"
+)
+
+// runRPCScan produces the standard export by scanning blocks through a
+// JSON-RPC node. It is much slower than the explorer API and does not
+// detect internal transfers.
+func runRPCScan(rpcURL, address string, startBlock, endBlock int64, outputDir string, opts runOptions) {
+	node := rpc.NewClient(rpcURL)
+	node.Logger = logger
+	node.Audit = opts.audit
+	node.Context = opts.ctx
+	node.HTTPClient.Transport = opts.transport
+	if opts.httpTimeout > 0 {
+		node.HTTPClient.Timeout = opts.httpTimeout
+	}
+
+	head, err := node.BlockNumber()
+	if err != nil {
+		log.Fatalf("Error querying RPC node: %v", err)
+	}
+	if endBlock > head {
+		endBlock = head
+	}
+	if opts.minConfirmations > 0 {
+		endBlock = confirmedEndBlock(startBlock, endBlock, head, opts.minConfirmations)
+	}
+	if opts.finalized {
+		finalized, err := node.FinalizedBlockNumber()
+		if err != nil {
+			log.Fatalf("Error querying the latest finalized block: %v", err)
+		}
+		endBlock = finalizedEndBlock(startBlock, endBlock, finalized)
+	}
+
+	describeManifestRun(opts, "json-rpc", startBlock, endBlock, address)
+	fmt.Printf("Scanning blocks %d to %d via %s for address: %s\n", startBlock, endBlock, audit.RedactURL(rpcURL), address)
+	if opts.feeBreakdown {
+		logger.Warn("-fee-breakdown is not supported in RPC scanning mode")
+	}
+
+	scanner := scan.NewScanner(node)
+	totalBlocks := endBlock - startBlock + 1
+	scanner.Progress = func(block int64) {
+		done := block - startBlock + 1
+		if done%1000 == 0 || block == endBlock {
+			fmt.Printf("Scanned %d/%d blocks (%d%%)\n", done, totalBlocks, int(float64(done)/float64(totalBlocks)*100))
+		}
+	}
+
+	allTxs, err := scanner.Scan(address, startBlock, endBlock)
+	if err != nil {
+		log.Fatalf("Error scanning blocks: %v", err)
+	}
+	fmt.Printf("Total transactions processed: %d\n", len(allTxs))
+	// Chains missing from the registry are recorded by their chain ID
+	if id, err := node.ChainID(); err != nil {
+		logger.Warn("could not determine the chain of the RPC node, leaving the chain column empty", "error", err)
+	} else {
+		name := strconv.FormatInt(id, 10)
+		if chain, ok := chains.LookupID(id); ok {
+			name = chain.Name
+		}
+		for i := range allTxs {
+			allTxs[i].Chain = name
+		}
+	}
+
+	if opts.storePath != "" {
+		recordRun(opts.storePath, address, startBlock, endBlock, allTxs, true)
+	}
+	if opts.storeOnly {
+		return
+	}
+
+	allTxs = prepareExport(address, allTxs, opts)
+
+	written, err := writeExport(allTxs, outputDir, address+"_tx_history", opts)
+	if err != nil {
+		log.Fatalf("Error exporting to CSV: %v", err)
+	}
+
+	fmt.Printf("Exported transaction history to %s\n", written)
+	reconcileBalance(address, endBlock, opts, func() (*big.Int, error) { return node.Balance(address, endBlock) })
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"math"
+
+	"github.com/haridev22/ct-assignement/pkg/api"
+	"github.com/haridev22/ct-assignement/pkg/sample"
+)
+
+// runSample fetches a deterministic sample of block windows and prints
+// extrapolated row counts per transaction type with 95% confidence bounds
+func runSample(client *api.EtherscanClient, address string, startBlock, endBlock int64, fraction float64) {
+	windows := sample.Plan(address, startBlock, endBlock, fraction, sample.DefaultStrata)
+	if len(windows) == 0 {
+		log.Fatal("Error: block range is empty, nothing to sample.")
+	}
+	strata := sample.DefaultStrata
+	if span := endBlock - startBlock + 1; span < int64(strata) {
+		strata = int(span)
+	}
+
+	fmt.Printf("Sampling %d of %d block windows (%.0f%%) between blocks %d and %d\n",
+		len(windows), strata, fraction*100, startBlock, endBlock)
+
+	types := []string{"normal", "internal", "erc20", "erc721", "erc1155"}
+	counts := make(map[string][]int)
+	for _, w := range windows {
+		fmt.Printf("Sampling blocks %d to %d...\n", w.StartBlock, w.EndBlock)
+
+		normal, err := client.GetAllNormalTransactions(address, w.StartBlock, w.EndBlock)
+		if err != nil {
+			log.Fatalf("Error sampling normal transactions: %v", err)
+		}
+		internal, err := client.GetAllInternalTransactions(address, w.StartBlock, w.EndBlock)
+		if err != nil {
+			log.Fatalf("Error sampling internal transactions: %v", err)
+		}
+		erc20, err := client.GetAllERC20Transfers(address, w.StartBlock, w.EndBlock)
+		if err != nil {
+			log.Fatalf("Error sampling ERC20 transfers: %v", err)
+		}
+		erc721, err := client.GetAllERC721Transfers(address, w.StartBlock, w.EndBlock)
+		if err != nil {
+			log.Fatalf("Error sampling ERC721 transfers: %v", err)
+		}
+		erc1155, err := client.GetAllERC1155Transfers(address, w.StartBlock, w.EndBlock)
+		if err != nil {
+			log.Fatalf("Error sampling ERC1155 transfers: %v", err)
+		}
+
+		counts["normal"] = append(counts["normal"], len(normal))
+		counts["internal"] = append(counts["internal"], len(internal))
+		counts["erc20"] = append(counts["erc20"], len(erc20))
+		counts["erc721"] = append(counts["erc721"], len(erc721))
+		counts["erc1155"] = append(counts["erc1155"], len(erc1155))
+	}
+
+	fmt.Printf("\n%-10s %10s %12s %25s\n", "Type", "Sampled", "Estimate", "95% interval")
+	var total, low, high float64
+	pages := 0
+	for _, t := range types {
+		est := sample.Extrapolate(counts[t], strata)
+		fmt.Printf("%-10s %10d %12.0f %12.0f - %-12.0f\n", t, est.Sampled, est.Total, est.Low, est.High)
+		total += est.Total
+		low += est.Low
+		high += est.High
+		pages += int(math.Max(1, math.Ceil(est.High/float64(api.DefaultOffset))))
+	}
+	fmt.Printf("%-10s %10s %12.0f %12.0f - %-12.0f\n", "total", "", total, low, high)
+	fmt.Printf("\nEstimated API calls for a full export: up to %d\n", pages)
+}
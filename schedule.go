@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/schedule"
+)
+
+// schedulable are the commands a schedule can run. serve, tail and watch
+// never finish on their own.
+var schedulable = map[string]bool{"fetch": true, "sync": true, "export": true, "report": true, "query": true, "convert": true}
+
+// startSchedule runs the jobs of the schedule file at path in the
+// background until ctx is cancelled, exiting if the file is invalid. Each
+// job runs this binary with its args, so a failing job cannot take the
+// daemon down. The returned function waits for running jobs to stop.
+func startSchedule(ctx context.Context, path string) (wait func()) {
+	file, err := schedule.LoadFile(path)
+	if err != nil {
+		log.Fatalf("Error reading -schedule: %v", err)
+	}
+	self, err := os.Executable()
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	s := &schedule.Scheduler{Location: file.Location(), Logger: logger}
+	for _, entry := range file.Jobs {
+		name := entry.Args[0]
+		if !schedulable[name] {
+			log.Fatalf("Error: job %q runs %q, which is not a command a schedule can run.", entry.Name, name)
+		}
+		args := entry.Args
+		s.Jobs = append(s.Jobs, schedule.Job{
+			Name: entry.Name,
+			Cron: entry.Cron,
+			Lock: entry.Lock,
+			Run: func(ctx context.Context) error {
+				cmd := exec.CommandContext(ctx, self, args...)
+				cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+				// Interrupt rather than kill, so an export saves its checkpoint
+				cmd.Cancel = func() error { return cmd.Process.Signal(os.Interrupt) }
+				cmd.WaitDelay = 30 * time.Second
+				return cmd.Run()
+			},
+		})
+		fmt.Printf("Scheduled %s (%s): next run at %s\n", entry.Name, entry.Schedule,
+			entry.Cron.Next(time.Now().In(s.Location)).Format(time.RFC3339))
+	}
+
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+	return func() { <-done }
+}
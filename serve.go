@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/api"
+	"github.com/haridev22/ct-assignement/pkg/auth"
+	"github.com/haridev22/ct-assignement/pkg/health"
+	"github.com/haridev22/ct-assignement/pkg/server"
+	"github.com/haridev22/ct-assignement/pkg/store"
+)
+
+// defineServe defines the serve subcommand, which answers HTTP queries
+// about the addresses synced into a store until interrupted
+func defineServe(fs *flag.FlagSet) func() {
+	storePath := fs.String("store", "", "Versioned store file to serve (required)")
+	listen := fs.String("listen", "127.0.0.1:8080", "Address to listen on")
+	schedulePath := fs.String("schedule", "", "JSON file of jobs to run on cron schedules while serving, such as recurring syncs")
+	apiKey := fs.String("apikey", "", "Etherscan API key whose validity /readyz checks, or a comma-separated list of keys (default: ETHERSCAN_API_KEY)")
+	chainName := addChainFlag(fs)
+	debug := fs.Bool("debug", false, "Also serve runtime profiles at /debug/pprof/ (do not expose publicly)")
+	logOpts := addLogFlags(fs)
+	authOpts := addAuthFlags(fs)
+	profileOpts := addProfileFlags(fs)
+	return func() {
+		logOpts.apply()
+
+		if *storePath == "" {
+			log.Fatal("Error: serve requires -store.")
+		}
+		open := server.StoreFile(*storePath)
+		if _, err := open(); err != nil {
+			log.Fatalf("Error opening store: %v", err)
+		}
+
+		srv := server.New(open)
+		srv.Logger = logger
+		srv.Update = server.UpdateFile(*storePath)
+		authn := authOpts.authenticator()
+		if authn != nil {
+			srv.Tenant = identityTenant
+		}
+		checker := &health.Checker{Checks: []health.Check{storeCheck(open)}}
+		// Without a key there is no provider to check; scheduled jobs read
+		// ETHERSCAN_API_KEY themselves
+		if key := resolveAPIKey(*apiKey); key != "" {
+			client := newClient(key, api.DefaultCallsPerSecond, lookupChain(*chainName))
+			checker.Checks = append(checker.Checks, providerCheck(client))
+		}
+		mux := http.NewServeMux()
+		checker.Register(mux)
+		if *debug {
+			debugMux := http.NewServeMux()
+			registerPprof(debugMux)
+			mux.Handle("/debug/pprof/", requireScope(authn, scope(auth.ScopeAdmin), debugMux))
+		}
+		// Health probes stay open for orchestrators
+		mux.Handle("/", requireScope(authn, serveScope, srv))
+		httpServer := &http.Server{
+			Addr:              *listen,
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		go func() {
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			httpServer.Shutdown(shutdownCtx)
+		}()
+
+		defer profileOpts.start()()
+
+		waitJobs := func() {}
+		if *schedulePath != "" {
+			waitJobs = startSchedule(ctx, *schedulePath)
+		}
+
+		fmt.Printf("Serving %s on http://%s\n", *storePath, *listen)
+		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Error: %v", err)
+		}
+		waitJobs()
+	}
+}
+
+// providerCheck verifies that the explorer answers and accepts the API keys
+// of client
+func providerCheck(client *api.EtherscanClient) health.Check {
+	return health.Check{Name: "provider", Run: func(context.Context) error {
+		_, err := client.Preflight()
+		return err
+	}}
+}
+
+// storeCheck verifies that the store can be read
+func storeCheck(open func() (*store.Store, error)) health.Check {
+	return health.Check{Name: "store", Run: func(context.Context) error {
+		_, err := open()
+		return err
+	}}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"os"
+
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/haridev22/ct-assignement/pkg/report"
+	"github.com/haridev22/ct-assignement/pkg/store"
+)
+
+// recordRun stores fetched rows as a new run. When complete is true, rows
+// in the block range that the provider no longer returns are soft-deleted.
+func recordRun(storePath, address string, startBlock, endBlock int64, txs []models.Transaction, complete bool) {
+	var (
+		previousRuns     []store.Run
+		previous, latest []models.Transaction
+		run              store.Run
+		stats            store.Stats
+	)
+	err := store.Update(storePath, func(s *store.Store) error {
+		previousRuns = s.Runs(address)
+		previous = s.Latest(address)
+
+		run = s.BeginRun(address)
+		var err error
+		if complete {
+			stats, err = s.Sync(run, startBlock, endBlock, txs)
+		} else {
+			stats, err = s.Upsert(run, txs)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to record run: %w", err)
+		}
+		latest = s.Latest(address)
+		return nil
+	})
+	if err != nil {
+		log.Fatalf("Error updating store: %v", err)
+	}
+
+	fmt.Printf("Recorded run %d in %s: %d new, %d superseded, %d deleted, %d unchanged\n",
+		run.ID, storePath, stats.Inserted, stats.Superseded, stats.Deleted, stats.Unchanged)
+
+	if len(previousRuns) > 0 {
+		last := previousRuns[len(previousRuns)-1]
+		delta := report.Compare(address, previous, latest)
+		delta.PreviousRun, delta.PreviousTime = last.ID, last.StartedAt
+		delta.Write(os.Stdout)
+	}
+}
+
+// defineExport defines the export subcommand, which writes an export from
+// the rows recorded in a store without calling the provider
+func defineExport(fs *flag.FlagSet) func() {
+	address := fs.String("address", "", "Ethereum wallet address to export (required)")
+	storePath := fs.String("store", "", "Versioned store file to read from (required)")
+	asOfRun := fs.Int64("as-of-run", 0, "Export rows as they were after this run instead of the latest rows")
+	outputDir := fs.String("output", defaultOutputDir, "Directory to save CSV output, or - to write the export to stdout")
+	exportOpts := addExportFlags(fs)
+	bigqueryOpts := addBigQueryFlags(fs)
+	profileOpts := addProfileFlags(fs)
+	return func() {
+		if *address == "" || *storePath == "" {
+			log.Fatal("Error: export requires -address and -store.")
+		}
+		opts := exportOpts.options()
+		opts.bigquery = bigqueryOpts.sink()
+		exportToStdout(outputDir, &opts)
+		defer profileOpts.start()()
+		exportFromStore(*storePath, *address, *asOfRun, *outputDir, opts)
+		writeManifest(*outputDir, opts)
+	}
+}
+
+// exportFromStore exports the rows of address exactly as they were after
+// runID, or the latest rows when runID is 0
+func exportFromStore(storePath, address string, runID int64, outputDir string, opts runOptions) {
+	s, err := store.Open(storePath)
+	if err != nil {
+		log.Fatalf("Error opening store: %v", err)
+	}
+
+	rows, suffix, label := s.Latest(address), "latest", "latest rows"
+	if runID > 0 {
+		rows = s.AsOfRun(address, runID)
+		suffix, label = fmt.Sprintf("run_%d", runID), fmt.Sprintf("rows as of run %d", runID)
+	}
+	txs := prepareExport(address, rows, opts)
+	first, last := blockRange(txs)
+	describeManifestRun(opts, "store", first, last, address)
+
+	written, err := writeExport(txs, outputDir, fmt.Sprintf("%s_tx_history_%s", address, suffix), opts)
+	if err != nil {
+		log.Fatalf("Error exporting to CSV: %v", err)
+	}
+
+	fmt.Printf("Exported %d transactions (%s) to %s\n", len(txs), label, written)
+	// The rows are the whole history, so they replace every block
+	if err := loadBigQuery(opts, address, 0, math.MaxInt64, txs); err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+}
+
+// blockRange returns the first and last block of txs, in any order
+func blockRange(txs []models.Transaction) (first, last int64) {
+	for i, tx := range txs {
+		if i == 0 || tx.BlockNumber < first {
+			first = tx.BlockNumber
+		}
+		last = max(last, tx.BlockNumber)
+	}
+	return first, last
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/api"
+	"github.com/haridev22/ct-assignement/pkg/auth"
+	"github.com/haridev22/ct-assignement/pkg/cache"
+	"github.com/haridev22/ct-assignement/pkg/export"
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/haridev22/ct-assignement/pkg/watch"
+)
+
+// defineTail defines the tail subcommand, which prints the transactions of
+// an address to stdout as new blocks include them, like tail -f for a wallet.
+// Status messages go to stderr so NDJSON output can be piped.
+func defineTail(fs *flag.FlagSet) func() {
+	address := fs.String("address", "", "Ethereum wallet address to watch (required)")
+	apiKey := fs.String("apikey", "", "Etherscan API key, or a comma-separated list of keys to rotate between (required)")
+	chainName := addChainFlag(fs)
+	rateLimit := fs.Float64("rate-limit", api.DefaultCallsPerSecond, "Calls per second allowed for each key when several API keys are given")
+	format := fs.String("format", string(export.StreamTable), "Output format: table or ndjson")
+	interval := fs.Duration("interval", watch.DefaultInterval, "Time between polls")
+	confirmations := fs.Int64("confirmations", 1, "Only report blocks at least this many blocks below the latest block")
+	startBlock := fs.Int64("start", -1, "Report transactions from this block instead of only new ones")
+	listen := fs.String("listen", "", "Also stream new transactions as Server-Sent Events at http://[address]/events, e.g. 127.0.0.1:8081")
+	logOpts := addLogFlags(fs)
+	authOpts := addAuthFlags(fs)
+	transportOpts := addTransportFlags(fs)
+	profileOpts := addProfileFlags(fs)
+	return func() {
+		logOpts.apply()
+		authn := authOpts.authenticator()
+
+		if *address == "" {
+			log.Fatal("Error: tail requires -address.")
+		}
+		*apiKey = resolveAPIKey(*apiKey)
+		if *apiKey == "" {
+			log.Fatal("Error: Etherscan API key is required. Use -apikey flag or set ETHERSCAN_API_KEY environment variable.")
+		}
+		streamFormat, err := export.ParseStreamFormat(*format)
+		if err != nil {
+			log.Fatalf("Error: invalid -format: %v", err)
+		}
+		if *confirmations < 0 {
+			log.Fatal("Error: -confirmations cannot be negative.")
+		}
+
+		chain := lookupChain(*chainName)
+		client := newClient(*apiKey, *rateLimit, chain)
+		client.HTTPClient.Transport = transportOpts.roundTripper()
+		// Page progress lines would mix with the rows on stdout
+		client.Progress = func(api.PageEvent) {}
+		opts := runOptions{metadata: cache.New(), converter: converterFor(chain)}
+		if _, err := client.Preflight(); err != nil {
+			log.Fatalf("Error: preflight check failed: %v%s", err, errorHint(err))
+		}
+		out := export.NewStreamWriter(os.Stdout, streamFormat)
+
+		watcher := &watch.Watcher{
+			Head: client.GetBlockNumber,
+			Fetch: func(address string, startBlock, endBlock int64) ([]models.Transaction, error) {
+				txs, errs := fetchRange(client, opts, address, startBlock, endBlock)
+				return txs, errors.Join(errs...)
+			},
+			Interval:      *interval,
+			Confirmations: *confirmations,
+			OnError: func(err error) {
+				logger.Warn("poll failed, retrying", "error", err)
+			},
+		}
+
+		defer profileOpts.start()()
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		emit := out.Write
+		if *listen != "" {
+			hub := &watch.Hub{}
+			mux := http.NewServeMux()
+			mux.Handle("GET /events", requireScope(authn, scope(auth.ScopeRead), hub))
+			serveBackground(ctx, *listen, mux)
+			emit = func(txs []models.Transaction) error {
+				hub.Publish(txs)
+				return out.Write(txs)
+			}
+			if !*logOpts.quiet {
+				fmt.Fprintf(os.Stderr, "Streaming events at http://%s/events\n", *listen)
+			}
+		}
+
+		if !*logOpts.quiet {
+			fmt.Fprintf(os.Stderr, "Watching %s every %s (Ctrl-C to stop)\n", *address, *interval)
+		}
+		err = watcher.Run(ctx, *address, *startBlock, emit)
+		if err != nil && !errors.Is(err, context.Canceled) {
+			log.Fatalf("Error: %v", err)
+		}
+	}
+}
+
+// serveBackground serves handler on listen until ctx is cancelled, exiting
+// if the address cannot be used
+func serveBackground(ctx context.Context, listen string, handler http.Handler) {
+	listener, err := net.Listen("tcp", listen)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	httpServer := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("HTTP server stopped", "error", err)
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		// Streams never finish on their own, so close them instead of waiting
+		httpServer.Close()
+	}()
+}
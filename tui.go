@@ -0,0 +1,41 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/export"
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/haridev22/ct-assignement/pkg/progress"
+	"github.com/haridev22/ct-assignement/pkg/tui"
+)
+
+// defineTUI defines the tui subcommand, which browses the rows of an
+// address interactively and exports the filtered rows
+func defineTUI(fs *flag.FlagSet) func() {
+	rows := addReportInputFlags(fs)
+	outputDir := fs.String("output", defaultOutputDir, "Directory the export key offers to save the filtered rows in")
+	return func() {
+		if !progress.IsTerminal(os.Stdin) || !progress.IsTerminal(os.Stdout) {
+			log.Fatal("Error: tui needs an interactive terminal; use query or preview to print rows.")
+		}
+		address, txs := rows.load("tui")
+
+		browser := tui.NewBrowser(address, txs)
+		browser.ExportPath = filepath.Join(*outputDir, address+"_tui_export.csv")
+		browser.Export = func(path string, rows []models.Transaction) (string, error) {
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return "", err
+			}
+			// Like other exports, an existing file is kept
+			path = export.AvailablePath(path, "", time.Now())
+			return path, export.WriteCSV(rows, path)
+		}
+		if err := tui.Run(os.Stdin, os.Stdout, browser); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+	}
+}
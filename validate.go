@@ -0,0 +1,73 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/haridev22/ct-assignement/pkg/export"
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/haridev22/ct-assignement/pkg/validate"
+)
+
+// defineValidate defines the validate subcommand, which checks an export
+// before it is loaded downstream and exits with status 1 when it finds
+// issues
+func defineValidate(fs *flag.FlagSet) func() {
+	format := fs.String("format", "text", "Output format: text or json")
+	delimiter := fs.String("delimiter", "", "Field delimiter the file was written with (default: tab for .tsv files, a comma otherwise)")
+	timezone := fs.String("timezone", "", "Timezone the timestamps were written in, for formats without an offset (default: the local timezone)")
+	timeFormat := fs.String("time-format", "rfc3339", "Format the timestamps were written in: rfc3339, datetime, date or a Go layout")
+	return func() {
+		if *format != "text" && *format != "json" {
+			log.Fatalf("Error: unknown -format %q (use text or json).", *format)
+		}
+		if fs.NArg() != 1 {
+			log.Fatal("Error: validate requires one export: validate [flags] file.csv")
+		}
+		path := fs.Arg(0)
+
+		var opts export.CSVOptions
+		var err error
+		switch {
+		case *delimiter != "":
+			if opts.Delimiter, err = export.ParseDelimiter(*delimiter); err != nil {
+				log.Fatalf("Error: invalid -delimiter: %v", err)
+			}
+		case strings.EqualFold(filepath.Ext(path), ".tsv"):
+			opts.Delimiter = '\t'
+		}
+		loc := time.Local
+		if *timezone != "" {
+			if loc, err = time.LoadLocation(*timezone); err != nil {
+				log.Fatalf("Error: invalid -timezone: %v", err)
+			}
+		}
+		layout, err := models.ParseTimeFormat(*timeFormat)
+		if err != nil {
+			log.Fatalf("Error: invalid -time-format: %v", err)
+		}
+		opts.Columns = []models.Column{models.TimestampColumn(loc, layout)}
+
+		file, err := os.Open(path)
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		result, err := validate.Check(file, opts, time.Now())
+		file.Close()
+		if err != nil {
+			log.Fatalf("Error reading %s: %v", path, err)
+		}
+		if *format == "text" {
+			fmt.Printf("Validating %s\n\n", path)
+		}
+		printReport(result, *format)
+		if !result.Valid() {
+			os.Exit(1)
+		}
+	}
+}
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/haridev22/ct-assignement/pkg/alert"
+	"github.com/haridev22/ct-assignement/pkg/api"
+	"github.com/haridev22/ct-assignement/pkg/auth"
+	"github.com/haridev22/ct-assignement/pkg/cache"
+	"github.com/haridev22/ct-assignement/pkg/health"
+	"github.com/haridev22/ct-assignement/pkg/models"
+	"github.com/haridev22/ct-assignement/pkg/notify"
+	"github.com/haridev22/ct-assignement/pkg/rpc"
+	"github.com/haridev22/ct-assignement/pkg/server"
+	"github.com/haridev22/ct-assignement/pkg/store"
+	"github.com/haridev22/ct-assignement/pkg/wallets"
+	"github.com/haridev22/ct-assignement/pkg/watch"
+)
+
+// defineWatch defines the watch subcommand, which monitors addresses until
+// interrupted: rows in newly mined blocks are recorded in a store and sent
+// to the configured notifiers.
+func defineWatch(fs *flag.FlagSet) func() {
+	address := fs.String("address", "", "Ethereum wallet address to watch")
+	addressesFile := fs.String("addresses-file", "", "Watch every address in this file (one address or address,label per line) instead of -address")
+	watchlistName := fs.String("watchlist", "", "Watch the addresses of this watchlist of the store, as managed through serve, instead of -address")
+	tenant := fs.String("tenant", server.DefaultTenant, "Tenant owning -watchlist")
+	apiKey := fs.String("apikey", "", "Etherscan API key, or a comma-separated list of keys to rotate between (required)")
+	chainName := addChainFlag(fs)
+	rateLimit := fs.Float64("rate-limit", api.DefaultCallsPerSecond, "Calls per second allowed for each key when several API keys are given")
+	storePath := fs.String("store", "", "Versioned store file to record new transactions in (required)")
+	interval := fs.Duration("interval", watch.DefaultInterval, "Time between polls")
+	confirmations := fs.Int64("confirmations", 1, "Only report blocks at least this many blocks below the latest block")
+	startBlock := fs.Int64("start", -1, "Record transactions from this block instead of only new ones")
+	rpcURL := fs.String("rpc-url", "", "Read the latest block from this JSON-RPC node instead of Etherscan, saving explorer calls")
+	webhook := fs.String("webhook", "", "POST each new transaction as JSON to this URL")
+	slackWebhook := fs.String("slack-webhook", "", "Send an alert for each new transaction to this Slack incoming webhook URL")
+	discordWebhook := fs.String("discord-webhook", "", "Send an alert for each new transaction to this Discord webhook URL")
+	telegramToken := fs.String("telegram-token", "", "Telegram bot token to send alerts with (requires -telegram-chat)")
+	telegramChat := fs.String("telegram-chat", "", "Telegram chat ID or @channel to send alerts to")
+	natsURL := fs.String("nats-url", "", "Publish each new transaction as JSON to this NATS server, e.g. nats://token@127.0.0.1:4222")
+	natsSubject := fs.String("nats-subject", "eth.transactions.{address}", "NATS subject to publish to; {address} and {type} are replaced per transaction")
+	alertRules := fs.String("alert-rules", "", "JSON file of alert rules; only transactions matching a rule are sent to notifiers")
+	schedulePath := fs.String("schedule", "", "JSON file of jobs to run on cron schedules while watching, such as a nightly sync")
+	listen := fs.String("listen", "", "Serve Server-Sent Events of new transactions at http://[address]/events and health probes at /healthz and /readyz, e.g. 127.0.0.1:8081")
+	logOpts := addLogFlags(fs)
+	authOpts := addAuthFlags(fs)
+	transportOpts := addTransportFlags(fs)
+	profileOpts := addProfileFlags(fs)
+	return func() {
+		logOpts.apply()
+		authn := authOpts.authenticator()
+
+		given := 0
+		for _, value := range []string{*address, *addressesFile, *watchlistName} {
+			if value != "" {
+				given++
+			}
+		}
+		if given != 1 {
+			log.Fatal("Error: watch requires exactly one of -address, -addresses-file and -watchlist.")
+		}
+		if *storePath == "" {
+			log.Fatal("Error: watch requires -store.")
+		}
+		*apiKey = resolveAPIKey(*apiKey)
+		if *apiKey == "" {
+			log.Fatal("Error: Etherscan API key is required. Use -apikey flag or set ETHERSCAN_API_KEY environment variable.")
+		}
+		if *confirmations < 0 {
+			log.Fatal("Error: -confirmations cannot be negative.")
+		}
+		if (*telegramToken == "") != (*telegramChat == "") {
+			log.Fatal("Error: -telegram-token and -telegram-chat must be given together.")
+		}
+
+		walletList := []wallets.Wallet{{Address: *address}}
+		if *addressesFile != "" {
+			var err error
+			if walletList, err = wallets.ParseFile(*addressesFile); err != nil {
+				log.Fatalf("Error reading -addresses-file: %v", err)
+			}
+			if len(walletList) == 0 {
+				log.Fatalf("Error: %s lists no addresses.", *addressesFile)
+			}
+		}
+		// Fail before watching rather than on the first new transaction
+		st, err := store.Open(*storePath)
+		if err != nil {
+			log.Fatalf("Error opening store: %v", err)
+		}
+
+		chain := lookupChain(*chainName)
+		var watchlistRules alert.Rules
+		if *watchlistName != "" {
+			watchlist, err := st.WatchlistByName(*tenant, *watchlistName)
+			if err != nil {
+				log.Fatalf("Error: %v: %q of tenant %q", err, *watchlistName, *tenant)
+			}
+			walletList = walletList[:0]
+			for _, watched := range watchlist.Addresses {
+				if watched.OnChain(chain.Name) {
+					walletList = append(walletList, wallets.Wallet{Address: watched.Address, Label: watched.Label})
+				}
+			}
+			if len(walletList) == 0 {
+				log.Fatalf("Error: watchlist %q has no addresses on %s.", *watchlistName, chain.Name)
+			}
+			watchlistRules = watchlist.Rules
+		}
+		roundTripper := transportOpts.roundTripper()
+		client := newClient(*apiKey, *rateLimit, chain)
+		client.HTTPClient.Transport = roundTripper
+		client.Progress = func(api.PageEvent) {}
+		opts := runOptions{metadata: cache.New(), converter: converterFor(chain)}
+		if _, err := client.Preflight(); err != nil {
+			log.Fatalf("Error: preflight check failed: %v%s", err, errorHint(err))
+		}
+
+		head := client.GetBlockNumber
+		if *rpcURL != "" {
+			node := rpc.NewClient(resolveSecret("-rpc-url", *rpcURL))
+			node.Logger = logger
+			node.HTTPClient.Transport = roundTripper
+			chainID, err := node.ChainID()
+			if err != nil {
+				log.Fatalf("Error: could not reach -rpc-url: %v", err)
+			}
+			if chainID != chain.ID {
+				log.Fatalf("Error: -rpc-url serves chain %d, but -chain %s is chain %d.", chainID, chain.Name, chain.ID)
+			}
+			head = node.BlockNumber
+		}
+
+		// -alert-rules overrides the rules of the watchlist
+		rules := watchlistRules
+		if *alertRules != "" {
+			if rules, err = alert.Load(*alertRules); err != nil {
+				log.Fatalf("Error: invalid -alert-rules: %v", err)
+			}
+		}
+		var notifiers []notify.Notifier
+		if *webhook != "" {
+			notifiers = append(notifiers, &notify.Webhook{URL: resolveSecret("-webhook", *webhook)})
+		}
+		if *slackWebhook != "" {
+			notifiers = append(notifiers, &notify.Slack{WebhookURL: resolveSecret("-slack-webhook", *slackWebhook)})
+		}
+		if *discordWebhook != "" {
+			notifiers = append(notifiers, &notify.Discord{WebhookURL: resolveSecret("-discord-webhook", *discordWebhook)})
+		}
+		if *telegramToken != "" {
+			notifiers = append(notifiers, &notify.Telegram{Token: resolveSecret("-telegram-token", *telegramToken), ChatID: *telegramChat})
+		}
+		if *natsURL != "" {
+			if *natsSubject == "" || strings.ContainsAny(*natsSubject, " \t") {
+				log.Fatalf("Error: invalid -nats-subject %q.", *natsSubject)
+			}
+			publisher := &notify.NATS{URL: resolveSecret("-nats-url", *natsURL), Subject: *natsSubject, Name: "eth-tx-exporter watch"}
+			defer publisher.Close()
+			notifiers = append(notifiers, publisher)
+		}
+
+		watcher := &watch.Watcher{
+			Head: head,
+			Fetch: func(address string, startBlock, endBlock int64) ([]models.Transaction, error) {
+				txs, errs := fetchRange(client, opts, address, startBlock, endBlock)
+				return txs, errors.Join(errs...)
+			},
+			Interval:      *interval,
+			Confirmations: *confirmations,
+			OnError: func(err error) {
+				logger.Warn("poll failed, retrying", "error", err)
+			},
+		}
+
+		defer profileOpts.start()()
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		waitJobs := func() {}
+		if *schedulePath != "" {
+			waitJobs = startSchedule(ctx, *schedulePath)
+		}
+		defer waitJobs()
+
+		hub := &watch.Hub{}
+		if *listen != "" {
+			mux := http.NewServeMux()
+			mux.Handle("GET /events", requireScope(authn, scope(auth.ScopeRead), hub))
+			checker := &health.Checker{Checks: []health.Check{providerCheck(client), storeCheck(server.StoreFile(*storePath))}}
+			checker.Register(mux)
+			serveBackground(ctx, *listen, mux)
+			fmt.Printf("Streaming events at http://%s/events\n", *listen)
+		}
+
+		labels := make(map[string]string, len(walletList))
+		targets := make([]watch.Target, len(walletList))
+		for i, wallet := range walletList {
+			labels[wallet.Address] = wallet.Label
+			targets[i] = watch.Target{Address: wallet.Address, FromBlock: *startBlock}
+		}
+
+		fmt.Printf("Watching %d address(es) every %s into %s (Ctrl-C to stop)\n", len(targets), *interval, *storePath)
+		err = watcher.RunTargets(ctx, targets, func(address string, txs []models.Transaction) error {
+			if err := recordNew(*storePath, address, txs); err != nil {
+				return err
+			}
+			hub.Publish(txs)
+			for _, tx := range txs {
+				n := notify.Notification{Address: address, Label: labels[address], Transaction: tx, NativeSymbol: chain.NativeSymbol}
+				// Without rules every transaction is notified
+				if rules != nil {
+					if n.Alerts = rules.Match(address, &tx); len(n.Alerts) == 0 {
+						continue
+					}
+				}
+				if chain.Explorer != "" {
+					n.URL = chain.TxURL(tx.Hash)
+				}
+				for _, notifier := range notifiers {
+					if err := notifier.Notify(ctx, n); err != nil {
+						logger.Warn("notification failed", "hash", tx.Hash, "error", err)
+					}
+				}
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, context.Canceled) {
+			log.Fatalf("Error: %v", err)
+		}
+	}
+}
+
+// recordNew appends rows found by the watcher to the store as a new run.
+// The store is updated for each batch so runs recorded by sync in between
+// are kept.
+func recordNew(storePath, address string, txs []models.Transaction) error {
+	var (
+		run   store.Run
+		stats store.Stats
+	)
+	err := store.Update(storePath, func(s *store.Store) error {
+		run = s.BeginRun(address)
+		var err error
+		if stats, err = s.Upsert(run, txs); err != nil {
+			return fmt.Errorf("failed to record run: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s: %d new transaction(s) up to block %d, recorded as run %d (%d new, %d superseded)\n",
+		address, len(txs), txs[len(txs)-1].BlockNumber, run.ID, stats.Inserted, stats.Superseded)
+	return nil
+}